@@ -0,0 +1,105 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+const queryTimeout = 10 * time.Second
+
+// ClientInterface for mocks (only mocked function are necessary here)
+type ClientInterface interface {
+	GetFiringAlerts() ([]Alert, error)
+}
+
+// Alert is an alert of the v2 API of Alertmanager
+type Alert struct {
+	Annotations  map[string]string `json:"annotations"`
+	EndsAt       time.Time         `json:"endsAt"`
+	Fingerprint  string            `json:"fingerprint"`
+	GeneratorURL string            `json:"generatorURL"`
+	Labels       map[string]string `json:"labels"`
+	StartsAt     time.Time         `json:"startsAt"`
+	Status       struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// Client for the Alertmanager API.
+type Client struct {
+	ClientInterface
+	client  http.Client
+	baseURL *url.URL
+}
+
+// NewClient creates a client for the configured Alertmanager
+func NewClient(token string) (ClientInterface, error) {
+	cfg := config.Get()
+	cfgAlertmanager := cfg.ExternalServices.Alertmanager
+
+	if !cfgAlertmanager.Enabled {
+		return nil, errors.New("alertmanager is not available")
+	}
+	auth := cfgAlertmanager.Auth
+	if auth.UseKialiToken {
+		auth.Token = token
+	}
+	u, err := url.Parse(cfgAlertmanager.InClusterURL)
+	if !cfg.InCluster {
+		u, err = url.Parse(cfgAlertmanager.URL)
+	}
+	if err != nil {
+		log.Errorf("Error parse Alertmanager URL: %s", err)
+		return nil, err
+	}
+	transport, err := httputil.AuthTransport(&auth, &http.Transport{})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: http.Client{Transport: transport, Timeout: queryTimeout}, baseURL: u}, nil
+}
+
+// GetFiringAlerts returns the alerts firing, neither silenced nor inhibited
+func (in *Client) GetFiringAlerts() ([]Alert, error) {
+	u := *in.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/alerts"
+	q := url.Values{}
+	q.Set("active", "true")
+	q.Set("silenced", "false")
+	q.Set("inhibited", "false")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+	resp, err := in.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Alertmanager query failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	alerts := []Alert{}
+	if err := json.Unmarshal(body, &alerts); err != nil {
+		return nil, fmt.Errorf("Unknown response from Alertmanager: %v", err)
+	}
+	return alerts, nil
+}
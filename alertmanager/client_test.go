@@ -0,0 +1,53 @@
+package alertmanager
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+const firingAlerts = `[
+  {"annotations":{"summary":"High error rate of reviews"},"endsAt":"2021-03-01T10:05:00Z","fingerprint":"0a1b2c3d",
+   "generatorURL":"http://prometheus:9090/graph?g0.expr=errors","labels":{"alertname":"HighErrorRate","namespace":"bookinfo","service":"reviews","severity":"critical"},
+   "startsAt":"2021-03-01T10:00:00Z","status":{"state":"active"}}
+]`
+
+func TestGetFiringAlerts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received = r.URL.Query()
+		_, _ = w.Write([]byte(firingAlerts))
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	client := Client{client: http.Client{}, baseURL: baseURL}
+
+	alerts, err := client.GetFiringAlerts()
+	assert.NoError(err)
+	assert.Equal("true", received.Get("active"))
+	assert.Equal("false", received.Get("silenced"))
+	assert.Equal("false", received.Get("inhibited"))
+	assert.Len(alerts, 1)
+	assert.Equal("HighErrorRate", alerts[0].Labels["alertname"])
+	assert.Equal("High error rate of reviews", alerts[0].Annotations["summary"])
+	assert.Equal("active", alerts[0].Status.State)
+	assert.Equal(10, alerts[0].StartsAt.Hour())
+
+	baseURL, _ = url.Parse(server.URL + "/unknown")
+	client = Client{client: http.Client{}, baseURL: baseURL}
+	_, err = client.GetFiringAlerts()
+	assert.Error(err)
+}
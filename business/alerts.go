@@ -0,0 +1,117 @@
+package business
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kiali/kiali/alertmanager"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+type AlertmanagerLoader = func() (alertmanager.ClientInterface, error)
+
+// AlertService deals with the alerts firing in Alertmanager, mapped by their labels to the namespaces, services,
+// workloads and apps of Kiali
+type AlertService struct {
+	alertmanager  AlertmanagerLoader
+	businessLayer *Layer
+}
+
+// GetFiringAlerts returns the alerts firing in the namespaces accessible to the user, and the alerts about the cluster,
+// which match the criteria. The alerts are sorted by namespace and name.
+func (in *AlertService) GetFiringAlerts(criteria models.AlertCriteria) ([]models.Alert, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "AlertService", "GetFiringAlerts")
+	defer promtimer.ObserveNow(&err)
+
+	cfg := config.Get().ExternalServices.Alertmanager
+	if !cfg.Enabled {
+		err = kubernetes.NewNotFound("alertmanager", "kiali.io", "alerts")
+		return nil, err
+	}
+
+	// Alertmanager is queried with the credentials of Kiali, the alerts are filtered by the namespaces of the user
+	accessible := map[string]bool{"": true}
+	if criteria.Namespace != "" {
+		if _, err = in.businessLayer.Namespace.GetNamespace(criteria.Namespace); err != nil {
+			return nil, err
+		}
+		accessible[criteria.Namespace] = true
+	} else {
+		var namespaces []models.Namespace
+		if namespaces, err = in.businessLayer.Namespace.GetNamespaces(); err != nil {
+			return nil, err
+		}
+		for _, namespace := range namespaces {
+			accessible[namespace.Name] = true
+		}
+	}
+
+	if in.alertmanager == nil {
+		err = errors.New("alertmanager is not available")
+		return nil, err
+	}
+	var client alertmanager.ClientInterface
+	if client, err = in.alertmanager(); err != nil {
+		return nil, err
+	}
+	var firing []alertmanager.Alert
+	if firing, err = client.GetFiringAlerts(); err != nil {
+		return nil, err
+	}
+
+	alerts := []models.Alert{}
+	for _, a := range firing {
+		alert := toAlert(a, cfg)
+		if accessible[alert.Namespace] && criteria.Matches(alert) {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if alerts[i].Namespace != alerts[j].Namespace {
+			return alerts[i].Namespace < alerts[j].Namespace
+		}
+		if alerts[i].Name != alerts[j].Name {
+			return alerts[i].Name < alerts[j].Name
+		}
+		return alerts[i].StartsAt.Before(alerts[j].StartsAt)
+	})
+	return alerts, nil
+}
+
+// toAlert maps an alert of Alertmanager to the objects of Kiali, with the configured labels
+func toAlert(a alertmanager.Alert, cfg config.AlertmanagerConfig) models.Alert {
+	alert := models.Alert{
+		Name:         a.Labels["alertname"],
+		Severity:     a.Labels["severity"],
+		Summary:      a.Annotations["summary"],
+		Description:  a.Annotations["description"],
+		StartsAt:     a.StartsAt,
+		Namespace:    a.Labels[cfg.Labels.Namespace],
+		Service:      a.Labels[cfg.Labels.Service],
+		Workload:     a.Labels[cfg.Labels.Workload],
+		App:          a.Labels[cfg.Labels.App],
+		Labels:       a.Labels,
+		GeneratorURL: a.GeneratorURL,
+	}
+	if alert.Labels == nil {
+		alert.Labels = map[string]string{}
+	}
+	if cfg.URL != "" {
+		// The UI of Alertmanager filters the alerts with matchers, the ones of the labels of Kiali select this alert
+		matchers := []string{"alertname=" + strconv.Quote(alert.Name)}
+		for _, label := range []string{cfg.Labels.Namespace, cfg.Labels.Service, cfg.Labels.Workload, cfg.Labels.App} {
+			if value, ok := a.Labels[label]; ok && label != "" {
+				matchers = append(matchers, label+"="+strconv.Quote(value))
+			}
+		}
+		alert.URL = strings.TrimSuffix(cfg.URL, "/") + "/#/alerts?filter=" + url.QueryEscape("{"+strings.Join(matchers, ",")+"}")
+	}
+	return alert
+}
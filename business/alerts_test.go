@@ -0,0 +1,92 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/alertmanager"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+type fakeAlertmanager struct {
+	alerts []alertmanager.Alert
+}
+
+func (f fakeAlertmanager) GetFiringAlerts() ([]alertmanager.Alert, error) {
+	return f.alerts, nil
+}
+
+func fakeAlert(name string, labels map[string]string) alertmanager.Alert {
+	labels["alertname"] = name
+	return alertmanager.Alert{
+		Labels:      labels,
+		Annotations: map[string]string{"summary": "summary of " + name},
+		StartsAt:    time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func setupAlertService(enabled bool) AlertService {
+	conf := config.NewConfig()
+	conf.ExternalServices.Alertmanager.Enabled = enabled
+	conf.ExternalServices.Alertmanager.URL = "http://alertmanager.example.com"
+	config.Set(conf)
+	kialiCache = nil
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+	}, nil)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Alert.alertmanager = func() (alertmanager.ClientInterface, error) {
+		return fakeAlertmanager{alerts: []alertmanager.Alert{
+			fakeAlert("HighErrorRate", map[string]string{"namespace": "bookinfo", "service": "reviews", "severity": "critical"}),
+			fakeAlert("PodCrashLooping", map[string]string{"namespace": "bookinfo", "workload": "ratings-v1"}),
+			fakeAlert("HighLatency", map[string]string{"namespace": "secret", "service": "vault"}),
+			fakeAlert("NodeDown", map[string]string{"node": "worker-1"}),
+		}}, nil
+	}
+	return layer.Alert
+}
+
+func TestGetFiringAlerts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	service := setupAlertService(true)
+
+	// The alerts of the namespaces not accessible to the user are filtered out
+	alerts, err := service.GetFiringAlerts(models.AlertCriteria{})
+	require.NoError(err)
+	require.Len(alerts, 3)
+	assert.Equal("NodeDown", alerts[0].Name)
+	assert.Equal("", alerts[0].Namespace)
+	assert.Equal("HighErrorRate", alerts[1].Name)
+	assert.Equal("reviews", alerts[1].Service)
+	assert.Equal("critical", alerts[1].Severity)
+	assert.Equal("summary of HighErrorRate", alerts[1].Summary)
+	assert.Equal(`http://alertmanager.example.com/#/alerts?filter=%7Balertname%3D%22HighErrorRate%22%2Cnamespace%3D%22bookinfo%22%2Cservice%3D%22reviews%22%7D`, alerts[1].URL)
+	assert.Equal("ratings-v1", alerts[2].Workload)
+
+	alerts, err = service.GetFiringAlerts(models.AlertCriteria{Namespace: "bookinfo", Service: "reviews"})
+	require.NoError(err)
+	require.Len(alerts, 1)
+	assert.Equal("HighErrorRate", alerts[0].Name)
+}
+
+func TestGetFiringAlertsDisabled(t *testing.T) {
+	service := setupAlertService(false)
+
+	_, err := service.GetFiringAlerts(models.AlertCriteria{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,238 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// Points removed from the readiness score by each finding, according to its severity
+var ambientFindingPenalty = map[string]int{
+	models.AmbientFindingBlocker:  25,
+	models.AmbientFindingWaypoint: 10,
+	models.AmbientFindingChange:   5,
+}
+
+// Pod annotations tuning the sidecar, or its traffic interception, ignored by ambient
+var ambientIgnoredAnnotationPrefixes = []string{"sidecar.istio.io/", "traffic.sidecar.istio.io/", "proxy.istio.io/"}
+
+// GetAmbientReadiness reports what blocks or changes when migrating the workloads of a namespace from sidecars to
+// ambient: the EnvoyFilters, the settings of the sidecars and the L7 policies which need a waypoint proxy
+func (in *MeshService) GetAmbientReadiness(namespace string) (*models.AmbientReadiness, error) {
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	istioConfig, err := in.businessLayer.IstioConfig.GetIstioConfigList(IstioConfigCriteria{
+		Namespace:                     namespace,
+		IncludeVirtualServices:        true,
+		IncludeDestinationRules:       true,
+		IncludeSidecars:               true,
+		IncludeAuthorizationPolicies:  true,
+		IncludePeerAuthentications:    true,
+		IncludeWorkloadEntries:        true,
+		IncludeRequestAuthentications: true,
+		IncludeEnvoyFilters:           true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods([]models.Namespace{*ns})
+	if err != nil {
+		return nil, err
+	}
+	return ambientReadiness(namespace, istioConfig, pods[namespace]), nil
+}
+
+func ambientReadiness(namespace string, istioConfig models.IstioConfigList, pods []core_v1.Pod) *models.AmbientReadiness {
+	findings := append(ambientConfigFindings(istioConfig), ambientPodFindings(pods)...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return ambientFindingPenalty[findings[i].Severity] > ambientFindingPenalty[findings[j].Severity]
+	})
+
+	readiness := &models.AmbientReadiness{Namespace: namespace, Score: 100, Ready: true, Findings: findings}
+	for _, f := range findings {
+		readiness.Score -= ambientFindingPenalty[f.Severity]
+		switch f.Severity {
+		case models.AmbientFindingBlocker:
+			readiness.Ready = false
+		case models.AmbientFindingWaypoint:
+			readiness.WaypointRequired = true
+		}
+	}
+	if readiness.Score < 0 {
+		readiness.Score = 0
+	}
+	return readiness
+}
+
+func ambientConfigFindings(istioConfig models.IstioConfigList) []models.AmbientFinding {
+	findings := []models.AmbientFinding{}
+	add := func(severity, kind, name, message string) {
+		findings = append(findings, models.AmbientFinding{Severity: severity, Kind: kind, Name: name, Message: message})
+	}
+
+	for _, ef := range istioConfig.EnvoyFilters {
+		add(models.AmbientFindingBlocker, "EnvoyFilter", ef.Metadata.Name, "EnvoyFilters are not supported by ambient: their patches have to be replaced, or dropped, before migrating")
+	}
+	for _, s := range istioConfig.Sidecars {
+		add(models.AmbientFindingChange, "Sidecar", s.Metadata.Name, "Sidecar resources are ignored by ambient: the scoping of the configuration and the outbound traffic policy no longer apply")
+	}
+	for _, ap := range istioConfig.AuthorizationPolicies {
+		if authorizationPolicyIsL7(ap) {
+			add(models.AmbientFindingWaypoint, "AuthorizationPolicy", ap.Metadata.Name, "The policy has L7 rules (methods, paths, hosts, request principals or request attributes): they are enforced only by a waypoint, ztunnel enforces the L4 rules only")
+		}
+	}
+	for _, ra := range istioConfig.RequestAuthentications {
+		add(models.AmbientFindingWaypoint, "RequestAuthentication", ra.Metadata.Name, "JWT validation is performed only by a waypoint")
+	}
+	for _, pa := range istioConfig.PeerAuthentications {
+		if mtlsMode(pa.Spec.Mtls) == "DISABLE" {
+			add(models.AmbientFindingChange, "PeerAuthentication", pa.Metadata.Name, "mTLS cannot be disabled in ambient: the traffic between the workloads of the mesh is always encrypted by ztunnel")
+		}
+		if ports, ok := pa.Spec.PortLevelMtls.(map[string]interface{}); ok && len(ports) > 0 {
+			add(models.AmbientFindingChange, "PeerAuthentication", pa.Metadata.Name, "Port level mTLS settings are not supported by ambient")
+		}
+	}
+	for _, vs := range istioConfig.VirtualServices.Items {
+		if virtualServiceIsMeshL7(vs) {
+			add(models.AmbientFindingWaypoint, "VirtualService", vs.Metadata.Name, "HTTP routing inside the mesh (matches, retries, faults, mirroring, weights) is applied only by a waypoint")
+		}
+	}
+	for _, dr := range istioConfig.DestinationRules.Items {
+		if destinationRuleIsL7(dr) {
+			add(models.AmbientFindingWaypoint, "DestinationRule", dr.Metadata.Name, "Load balancing, HTTP connection pools, outlier detection and subsets are applied only by a waypoint")
+		}
+	}
+	for _, we := range istioConfig.WorkloadEntries {
+		add(models.AmbientFindingChange, "WorkloadEntry", we.Metadata.Name, "Virtual machines are not captured by ztunnel: the workload keeps its sidecar, check its traffic towards the migrated workloads")
+	}
+	return findings
+}
+
+func ambientPodFindings(pods []core_v1.Pod) []models.AmbientFinding {
+	findings := []models.AmbientFinding{}
+	seen := map[models.AmbientFinding]bool{}
+	add := func(f models.AmbientFinding) {
+		if !seen[f] {
+			seen[f] = true
+			findings = append(findings, f)
+		}
+	}
+	statusAnnotation := config.Get().ExternalServices.Istio.IstioSidecarAnnotation
+
+	for _, pod := range pods {
+		// Replicas are reported once, by their controller
+		kind, name := "Pod", pod.Name
+		if len(pod.OwnerReferences) > 0 {
+			kind, name = pod.OwnerReferences[0].Kind, pod.OwnerReferences[0].Name
+		}
+		if pod.Spec.HostNetwork {
+			add(models.AmbientFinding{Severity: models.AmbientFindingBlocker, Kind: kind, Name: name, Message: "Pods in the host network are not captured by ztunnel"})
+		}
+
+		annotations := []string{}
+		for key := range pod.Annotations {
+			if key == statusAnnotation || key == "sidecar.istio.io/inject" {
+				continue
+			}
+			for _, prefix := range ambientIgnoredAnnotationPrefixes {
+				if strings.HasPrefix(key, prefix) {
+					annotations = append(annotations, key)
+					break
+				}
+			}
+		}
+		sort.Strings(annotations)
+		for _, key := range annotations {
+			add(models.AmbientFinding{Severity: models.AmbientFindingChange, Kind: kind, Name: name, Message: fmt.Sprintf("The annotation %s configures the sidecar, it is ignored by ambient", key)})
+		}
+
+		for _, c := range pod.Spec.InitContainers {
+			if c.Name == istioProxyContainer {
+				add(models.AmbientFinding{Severity: models.AmbientFindingChange, Kind: kind, Name: name, Message: "The sidecar runs as a native sidecar container: the ordering of the startup of the containers changes once removed"})
+			}
+		}
+	}
+	return findings
+}
+
+func authorizationPolicyIsL7(ap models.AuthorizationPolicy) bool {
+	rules, _ := ap.Spec.Rules.([]interface{})
+	for _, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		for _, from := range asList(rule["from"]) {
+			source := asMap(asMap(from)["source"])
+			if hasAnyKey(source, "requestPrincipals", "notRequestPrincipals") {
+				return true
+			}
+		}
+		for _, to := range asList(rule["to"]) {
+			operation := asMap(asMap(to)["operation"])
+			if hasAnyKey(operation, "methods", "notMethods", "paths", "notPaths", "hosts", "notHosts") {
+				return true
+			}
+		}
+		for _, when := range asList(rule["when"]) {
+			if key, _ := asMap(when)["key"].(string); strings.HasPrefix(key, "request.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// virtualServiceIsMeshL7 tells whether a VirtualService has HTTP routes applied to the traffic inside the mesh, the
+// VirtualServices bound to gateways only being unaffected by the migration
+func virtualServiceIsMeshL7(vs models.VirtualService) bool {
+	if len(asList(vs.Spec.Http)) == 0 {
+		return false
+	}
+	gateways := asList(vs.Spec.Gateways)
+	if len(gateways) == 0 {
+		return true
+	}
+	for _, gw := range gateways {
+		if gw == "mesh" {
+			return true
+		}
+	}
+	return false
+}
+
+func destinationRuleIsL7(dr models.DestinationRule) bool {
+	if len(asList(dr.Spec.Subsets)) > 0 {
+		return true
+	}
+	policy := asMap(dr.Spec.TrafficPolicy)
+	return hasAnyKey(policy, "loadBalancer", "outlierDetection") || hasAnyKey(asMap(policy["connectionPool"]), "http")
+}
+
+func mtlsMode(mtls interface{}) string {
+	mode, _ := asMap(mtls)["mode"].(string)
+	return mode
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+func asList(value interface{}) []interface{} {
+	l, _ := value.([]interface{})
+	return l
+}
+
+func hasAnyKey(m map[string]interface{}, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := m[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestAmbientReadiness(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	istioConfig := models.IstioConfigList{}
+	l4, l7 := models.AuthorizationPolicy{}, models.AuthorizationPolicy{}
+	l4.Metadata.Name = "allow-namespace"
+	l4.Spec.Rules = []interface{}{map[string]interface{}{
+		"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"bookinfo"}}}},
+		"to":   []interface{}{map[string]interface{}{"operation": map[string]interface{}{"ports": []interface{}{"9080"}}}},
+	}}
+	l7.Metadata.Name = "allow-get"
+	l7.Spec.Rules = []interface{}{map[string]interface{}{
+		"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"GET"}}}},
+	}}
+	istioConfig.AuthorizationPolicies = models.AuthorizationPolicies{l4, l7}
+
+	meshVS, gatewayVS := models.VirtualService{}, models.VirtualService{}
+	meshVS.Metadata.Name = "reviews"
+	meshVS.Spec.Http = []interface{}{map[string]interface{}{}}
+	gatewayVS.Metadata.Name = "bookinfo"
+	gatewayVS.Spec.Http = []interface{}{map[string]interface{}{}}
+	gatewayVS.Spec.Gateways = []interface{}{"bookinfo-gateway"}
+	istioConfig.VirtualServices.Items = []models.VirtualService{meshVS, gatewayVS}
+
+	tcpDR := models.DestinationRule{}
+	tcpDR.Metadata.Name = "ratings"
+	tcpDR.Spec.TrafficPolicy = map[string]interface{}{"connectionPool": map[string]interface{}{"tcp": map[string]interface{}{}}}
+	istioConfig.DestinationRules.Items = []models.DestinationRule{tcpDR}
+
+	ef := models.EnvoyFilter{}
+	ef.Metadata.Name = "lua"
+	istioConfig.EnvoyFilters = models.EnvoyFilters{ef}
+
+	owner := []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: "reviews-v1-545db77b95"}}
+	pods := []core_v1.Pod{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-545db77b95-a", OwnerReferences: owner, Annotations: map[string]string{
+			"sidecar.istio.io/status": "{}", "sidecar.istio.io/inject": "true", "proxy.istio.io/config": "{}"}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-545db77b95-b", OwnerReferences: owner, Annotations: map[string]string{
+			"sidecar.istio.io/status": "{}", "proxy.istio.io/config": "{}"}}},
+	}
+
+	readiness := ambientReadiness("bookinfo", istioConfig, pods)
+	assert.Equal([]models.AmbientFinding{
+		{Severity: models.AmbientFindingBlocker, Kind: "EnvoyFilter", Name: "lua", Message: readiness.Findings[0].Message},
+		{Severity: models.AmbientFindingWaypoint, Kind: "AuthorizationPolicy", Name: "allow-get", Message: readiness.Findings[1].Message},
+		{Severity: models.AmbientFindingWaypoint, Kind: "VirtualService", Name: "reviews", Message: readiness.Findings[2].Message},
+		{Severity: models.AmbientFindingChange, Kind: "ReplicaSet", Name: "reviews-v1-545db77b95", Message: "The annotation proxy.istio.io/config configures the sidecar, it is ignored by ambient"},
+	}, readiness.Findings)
+	assert.Equal(100-25-10-10-5, readiness.Score)
+	assert.False(readiness.Ready)
+	assert.True(readiness.WaypointRequired)
+
+	readiness = ambientReadiness("bookinfo", models.IstioConfigList{}, nil)
+	assert.Equal(100, readiness.Score)
+	assert.True(readiness.Ready)
+	assert.False(readiness.WaypointRequired)
+	assert.Empty(readiness.Findings)
+}
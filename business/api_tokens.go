@@ -0,0 +1,409 @@
+package business
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// ApiTokenPrefix identifies the bearer tokens that are Kiali API tokens
+const ApiTokenPrefix = "kiali_"
+
+const (
+	apiTokenSecretKey     = "tokens"
+	apiTokenCacheDuration = 10 * time.Second
+)
+
+// ApiTokenService deals with the API tokens that automation uses to call the Kiali API
+type ApiTokenService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// storedApiToken is how an API token is persisted: the token itself is never stored, only its hash
+type storedApiToken struct {
+	models.ApiToken
+	Hash string `json:"hash"`
+}
+
+// The API tokens are read on every request authenticated with an API token, so they are kept in memory
+// for a short time to not hit the cluster API each time.
+var apiTokensCache struct {
+	sync.RWMutex
+	tokens  []storedApiToken
+	created time.Time
+	// Incremented on each change of the API tokens: a read started before a change must not overwrite it
+	generation uint64
+}
+
+var errApiTokenInvalid = errors.New("API token is invalid or is expired")
+
+// The permissions of the Kiali ServiceAccount required by the API tokens: it reviews the tokens of the users to know
+// who owns the API tokens, and the requests made with the API tokens impersonate their owners.
+var apiTokenPermissions = []struct {
+	group    string
+	resource string
+	verb     string
+}{
+	{group: "authentication.k8s.io", resource: "tokenreviews", verb: "create"},
+	{group: "", resource: "users", verb: "impersonate"},
+	{group: "", resource: "groups", verb: "impersonate"},
+}
+
+// apiTokensUnavailable is why the API tokens can't be used, when the Kiali ServiceAccount lacks their permissions.
+// It is set at startup, by CheckApiTokenPermissions.
+var apiTokensUnavailable error
+
+// CheckApiTokenPermissions checks that the Kiali ServiceAccount has the permissions required by the API tokens, when
+// they are enabled. Without them, the API tokens can't be created nor used, and the requests fail with the returned
+// error.
+func CheckApiTokenPermissions() error {
+	apiTokensUnavailable = nil
+	auth := config.Get().Auth
+	if !auth.ApiTokens.Enabled || !auth.UsesUserCredentials() {
+		return nil
+	}
+
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return err
+	}
+	for _, permission := range apiTokenPermissions {
+		ssars, err := k8s.GetSelfSubjectAccessReview("", permission.group, permission.resource, []string{permission.verb})
+		if err != nil {
+			return fmt.Errorf("cannot check the permissions of the Kiali ServiceAccount required by the API tokens: %v", err)
+		}
+		if len(ssars) == 0 || !ssars[0].Status.Allowed {
+			apiTokensUnavailable = fmt.Errorf("the Kiali ServiceAccount is not allowed to %s %s, which the API tokens require: add the permission to the ClusterRole of Kiali", permission.verb, permission.resource)
+			return apiTokensUnavailable
+		}
+	}
+	return nil
+}
+
+// GetApiTokenOwner returns the user owning the API tokens managed with this layer: the user of the token of the layer,
+// as reviewed by the cluster. The name doesn't depend on how the user logged in, and the requests made with the API
+// tokens impersonate it. A forbidden error is returned when the requests of the users don't reach the cluster with
+// their own credentials, since every user would then be the Kiali ServiceAccount.
+func (in *ApiTokenService) GetApiTokenOwner() (*models.UserIdentity, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ApiTokenService", "GetApiTokenOwner")
+	defer promtimer.ObserveNow(&err)
+
+	auth := config.Get().Auth
+	if !auth.UsesUserCredentials() {
+		err = newApiTokenForbidden(fmt.Errorf("API tokens are not available with the %s strategy, which doesn't use the credentials of the user", auth.Strategy))
+		return nil, err
+	}
+	if apiTokensUnavailable != nil {
+		err = newApiTokenForbidden(apiTokensUnavailable)
+		return nil, err
+	}
+
+	// The Kiali ServiceAccount can review tokens, the user may not
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	review, err := k8s.GetTokenReview(in.k8s.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated || review.Status.User.Username == "" {
+		err = newApiTokenForbidden(errors.New("the cluster cannot identify the user"))
+		return nil, err
+	}
+	groups := review.Status.User.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	return &models.UserIdentity{Username: review.Status.User.Username, Groups: groups}, nil
+}
+
+func newApiTokenForbidden(err error) error {
+	return k8s_errors.NewForbidden(schema.GroupResource{Group: "kiali.io", Resource: "apitokens"}, "", err)
+}
+
+// CreateApiToken creates an API token owned by the given user, as returned by GetApiTokenOwner. The user must have
+// access to every namespace requested in the scope and, if the token is not view only, must be able to change Istio
+// config in them. The cluster keeps enforcing the permissions of the user afterwards, as the requests made with the
+// token impersonate the user.
+func (in *ApiTokenService) CreateApiToken(owner models.UserIdentity, request models.ApiTokenRequest) (*models.ApiTokenCreated, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ApiTokenService", "CreateApiToken")
+	defer promtimer.ObserveNow(&err)
+
+	conf := config.Get().Auth.ApiTokens
+	if owner.Username == "" {
+		err = newApiTokenForbidden(errors.New("API tokens require an identified user"))
+		return nil, err
+	}
+	if request.Name == "" {
+		err = errors.New("API token name is required")
+		return nil, err
+	}
+	if len(request.Scope.Namespaces) == 0 {
+		err = errors.New("API token scope must include at least one namespace")
+		return nil, err
+	}
+	if request.ExpirationSeconds <= 0 || request.ExpirationSeconds > conf.MaxExpirationSeconds {
+		request.ExpirationSeconds = conf.MaxExpirationSeconds
+	}
+
+	// A token can't grant more than what its owner is allowed to do
+	for _, ns := range request.Scope.Namespaces {
+		if _, err = in.businessLayer.Namespace.GetNamespace(ns); err != nil {
+			return nil, err
+		}
+		if !request.Scope.ViewOnly {
+			if canCreate, canUpdate, canDelete := getPermissions(in.k8s, ns, kubernetes.VirtualServices); !canCreate || !canUpdate || !canDelete {
+				err = &AccessibleNamespaceError{msg: "User cannot change Istio config in namespace [" + ns + "]; only view only API tokens are allowed"}
+				return nil, err
+			}
+		}
+	}
+
+	var idBytes, secretBytes []byte
+	if idBytes, err = util.CryptoRandomBytes(8); err != nil {
+		return nil, err
+	}
+	if secretBytes, err = util.CryptoRandomBytes(32); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(idBytes)
+	token := ApiTokenPrefix + id + "_" + hex.EncodeToString(secretBytes)
+
+	now := util.Clock.Now()
+	created := storedApiToken{
+		ApiToken: models.ApiToken{
+			ID:          id,
+			Name:        request.Name,
+			Owner:       owner.Username,
+			OwnerGroups: owner.Groups,
+			Scope:       request.Scope,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(time.Duration(request.ExpirationSeconds) * time.Second),
+		},
+		Hash: hashApiToken(token),
+	}
+
+	err = updateApiTokens(func(tokens []storedApiToken) ([]storedApiToken, error) {
+		return append(tokens, created), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ApiTokenCreated{ApiToken: created.ApiToken, Token: token}, nil
+}
+
+// ListApiTokens returns the API tokens owned by the given user
+func (in *ApiTokenService) ListApiTokens(owner string) ([]models.ApiToken, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ApiTokenService", "ListApiTokens")
+	defer promtimer.ObserveNow(&err)
+
+	var tokens []storedApiToken
+	if tokens, err = getApiTokens(); err != nil {
+		return nil, err
+	}
+
+	result := []models.ApiToken{}
+	for _, token := range tokens {
+		if token.Owner == owner {
+			result = append(result, token.ApiToken)
+		}
+	}
+	return result, nil
+}
+
+// RevokeApiToken deletes the API token with the given id, if it is owned by the given user
+func (in *ApiTokenService) RevokeApiToken(owner string, id string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ApiTokenService", "RevokeApiToken")
+	defer promtimer.ObserveNow(&err)
+
+	err = updateApiTokens(func(tokens []storedApiToken) ([]storedApiToken, error) {
+		for i, token := range tokens {
+			if token.ID == id && token.Owner == owner {
+				return append(tokens[:i], tokens[i+1:]...), nil
+			}
+		}
+		return nil, kubernetes.NewNotFound(id, "kiali.io", "apitokens")
+	})
+	return err
+}
+
+// ValidateApiToken checks the given bearer token is a valid, non expired, API token and returns its definition
+func ValidateApiToken(token string) (*models.ApiToken, error) {
+	if apiTokensUnavailable != nil {
+		return nil, apiTokensUnavailable
+	}
+	parts := strings.Split(strings.TrimPrefix(token, ApiTokenPrefix), "_")
+	if !strings.HasPrefix(token, ApiTokenPrefix) || len(parts) != 2 {
+		return nil, errApiTokenInvalid
+	}
+
+	tokens, err := getApiTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashApiToken(token)
+	for _, stored := range tokens {
+		if stored.ID != parts[0] {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(hash)) != 1 || !util.Clock.Now().Before(stored.ExpiresAt) {
+			return nil, errApiTokenInvalid
+		}
+		apiToken := stored.ApiToken
+		return &apiToken, nil
+	}
+	return nil, errApiTokenInvalid
+}
+
+func hashApiToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// getApiTokens returns the stored API tokens, using the in-memory copy when it is recent enough
+func getApiTokens() ([]storedApiToken, error) {
+	apiTokensCache.RLock()
+	if apiTokensCache.tokens != nil && time.Since(apiTokensCache.created) < apiTokenCacheDuration {
+		defer apiTokensCache.RUnlock()
+		return apiTokensCache.tokens, nil
+	}
+	generation := apiTokensCache.generation
+	apiTokensCache.RUnlock()
+
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	_, tokens, err := readApiTokens(k8s)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTokensCache.Lock()
+	defer apiTokensCache.Unlock()
+	// The tokens changed while the Secret was read, i.e. a token was revoked: the read tokens are outdated and must
+	// not be cached, or the revoked token would be accepted again until the cache expires
+	if apiTokensCache.generation != generation {
+		return apiTokensCache.tokens, nil
+	}
+	apiTokensCache.tokens = tokens
+	apiTokensCache.created = time.Now()
+	return tokens, nil
+}
+
+// updateApiTokens applies the given change to the stored API tokens and persists the result
+func updateApiTokens(change func([]storedApiToken) ([]storedApiToken, error)) error {
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return err
+	}
+
+	apiTokensCache.Lock()
+	defer apiTokensCache.Unlock()
+
+	secret, tokens, err := readApiTokens(k8s)
+	if err != nil {
+		return err
+	}
+	if tokens, err = change(tokens); err != nil {
+		return err
+	}
+
+	rawTokens, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	conf := config.Get()
+	if secret == nil {
+		secret = &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      conf.Auth.ApiTokens.SecretName,
+				Namespace: conf.Deployment.Namespace,
+				Labels:    map[string]string{"app": "kiali"},
+			},
+			Data: map[string][]byte{apiTokenSecretKey: rawTokens},
+		}
+		_, err = k8s.CreateSecret(conf.Deployment.Namespace, secret)
+	} else {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[apiTokenSecretKey] = rawTokens
+		_, err = k8s.UpdateSecret(conf.Deployment.Namespace, secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	apiTokensCache.tokens = tokens
+	apiTokensCache.created = time.Now()
+	apiTokensCache.generation++
+	return nil
+}
+
+// readApiTokens reads the API tokens Secret. A nil Secret is returned if it doesn't exist yet.
+func readApiTokens(k8s kubernetes.ClientInterface) (*core_v1.Secret, []storedApiToken, error) {
+	conf := config.Get()
+	secret, err := k8s.GetSecret(conf.Deployment.Namespace, conf.Auth.ApiTokens.SecretName)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil, []storedApiToken{}, nil
+		}
+		return nil, nil, err
+	}
+
+	tokens := []storedApiToken{}
+	if rawTokens, ok := secret.Data[apiTokenSecretKey]; ok {
+		if err := json.Unmarshal(rawTokens, &tokens); err != nil {
+			log.Errorf("API tokens in Secret [%s] are corrupted: %v", conf.Auth.ApiTokens.SecretName, err)
+			return nil, nil, fmt.Errorf("cannot parse the stored API tokens: %v", err)
+		}
+	}
+	return secret, tokens, nil
+}
+
+// getKialiSAClient returns a client using the Kiali ServiceAccount token.
+// API tokens are stored in the Kiali namespace, where users are not expected to have privileges.
+func getKialiSAClient() (kubernetes.ClientInterface, error) {
+	if clientFactory == nil {
+		userClient, err := kubernetes.GetClientFactory()
+		if err != nil {
+			return nil, err
+		}
+		clientFactory = userClient
+	}
+
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientFactory.GetClient(kialiToken)
+}
@@ -0,0 +1,199 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	authn_v1 "k8s.io/api/authentication/v1"
+	auth_v1 "k8s.io/api/authorization/v1"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func setupApiTokensMock() (*kubetest.K8SClientMock, *core_v1.Secret) {
+	conf := config.NewConfig()
+	conf.Auth.ApiTokens.Enabled = true
+	config.Set(conf)
+
+	kubernetes.KialiToken = "kiali-sa-token"
+	util.Clock = util.ClockMock{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+	apiTokensCache.tokens = nil
+	apiTokensUnavailable = nil
+
+	stored := &core_v1.Secret{}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("alice-token")
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetSecret", conf.Deployment.Namespace, conf.Auth.ApiTokens.SecretName).Return((*core_v1.Secret)(nil), kubernetes.NewNotFound("kiali-api-tokens", "core", "secrets")).Once()
+	k8s.On("GetSecret", conf.Deployment.Namespace, conf.Auth.ApiTokens.SecretName).Return(stored, nil)
+	k8s.On("CreateSecret", conf.Deployment.Namespace, mock.AnythingOfType("*v1.Secret")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.Secret)
+	}).Return(stored, nil)
+	k8s.On("UpdateSecret", conf.Deployment.Namespace, mock.AnythingOfType("*v1.Secret")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.Secret)
+	}).Return(stored, nil)
+
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+	return k8s, stored
+}
+
+func TestApiTokenLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	k8s, stored := setupApiTokensMock()
+	layer := NewWithBackends(k8s, nil, nil)
+
+	created, err := layer.ApiToken.CreateApiToken(models.UserIdentity{Username: "alice", Groups: []string{"developers"}}, models.ApiTokenRequest{
+		Name:  "ci",
+		Scope: models.ApiTokenScope{Namespaces: []string{"bookinfo"}, ViewOnly: true},
+	})
+	assert.NoError(err)
+	assert.Contains(created.Token, ApiTokenPrefix+created.ID+"_")
+	assert.Equal(util.Clock.Now().Add(90*24*time.Hour), created.ExpiresAt)
+	assert.NotContains(string(stored.Data[apiTokenSecretKey]), created.Token)
+
+	apiToken, err := ValidateApiToken(created.Token)
+	assert.NoError(err)
+	assert.Equal("alice", apiToken.Owner)
+	assert.Equal([]string{"developers"}, apiToken.OwnerGroups)
+	assert.True(apiToken.Scope.AllowsNamespace("bookinfo"))
+	assert.False(apiToken.Scope.AllowsNamespace("istio-system"))
+
+	_, err = ValidateApiToken(created.Token[:len(created.Token)-1] + "x")
+	assert.Equal(errApiTokenInvalid, err)
+
+	tokens, err := layer.ApiToken.ListApiTokens("bob")
+	assert.NoError(err)
+	assert.Empty(tokens)
+	assert.Error(layer.ApiToken.RevokeApiToken("bob", created.ID))
+
+	assert.NoError(layer.ApiToken.RevokeApiToken("alice", created.ID))
+	_, err = ValidateApiToken(created.Token)
+	assert.Equal(errApiTokenInvalid, err)
+}
+
+func TestApiTokenExpiration(t *testing.T) {
+	assert := assert.New(t)
+	k8s, _ := setupApiTokensMock()
+	layer := NewWithBackends(k8s, nil, nil)
+
+	created, err := layer.ApiToken.CreateApiToken(models.UserIdentity{Username: "alice"}, models.ApiTokenRequest{
+		Name:              "short",
+		ExpirationSeconds: 60,
+		Scope:             models.ApiTokenScope{Namespaces: []string{"bookinfo"}, ViewOnly: true},
+	})
+	assert.NoError(err)
+
+	util.Clock = util.ClockMock{Time: util.Clock.Now().Add(2 * time.Minute)}
+	_, err = ValidateApiToken(created.Token)
+	assert.Equal(errApiTokenInvalid, err)
+}
+
+func TestApiTokenCannotExceedOwnerPermissions(t *testing.T) {
+	assert := assert.New(t)
+	k8s, _ := setupApiTokensMock()
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).Return([]*auth_v1.SelfSubjectAccessReview{}, nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	_, err := layer.ApiToken.CreateApiToken(models.UserIdentity{Username: "alice"}, models.ApiTokenRequest{
+		Name:  "writer",
+		Scope: models.ApiTokenScope{Namespaces: []string{"bookinfo"}},
+	})
+	assert.Error(err)
+	k8s.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything)
+}
+
+func TestApiTokenOwnerIsReviewedByTheCluster(t *testing.T) {
+	assert := assert.New(t)
+	k8s, _ := setupApiTokensMock()
+	k8s.On("GetTokenReview", "alice-token").Return(&authn_v1.TokenReview{
+		Status: authn_v1.TokenReviewStatus{
+			Authenticated: true,
+			User:          authn_v1.UserInfo{Username: "alice@example.com", Groups: []string{"developers"}},
+		},
+	}, nil).Once()
+	k8s.On("GetTokenReview", "alice-token").Return(&authn_v1.TokenReview{}, nil).Once()
+	layer := NewWithBackends(k8s, nil, nil)
+
+	owner, err := layer.ApiToken.GetApiTokenOwner()
+	assert.NoError(err)
+	assert.Equal(models.UserIdentity{Username: "alice@example.com", Groups: []string{"developers"}}, *owner)
+
+	_, err = layer.ApiToken.GetApiTokenOwner()
+	assert.True(k8s_errors.IsForbidden(err))
+
+	// The Kiali ServiceAccount is the client of every user
+	conf := config.Get()
+	conf.Auth.Strategy = config.AuthStrategyHeader
+	config.Set(conf)
+	_, err = layer.ApiToken.GetApiTokenOwner()
+	assert.True(k8s_errors.IsForbidden(err))
+
+	_, err = layer.ApiToken.CreateApiToken(models.UserIdentity{}, models.ApiTokenRequest{
+		Name:  "anonymous",
+		Scope: models.ApiTokenScope{Namespaces: []string{"bookinfo"}, ViewOnly: true},
+	})
+	assert.True(k8s_errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything)
+}
+
+func TestApiTokenPermissionsCheckedAtStartup(t *testing.T) {
+	assert := assert.New(t)
+	k8s, _ := setupApiTokensMock()
+	defer func() { apiTokensUnavailable = nil }()
+	k8s.On("GetSelfSubjectAccessReview", "", "authentication.k8s.io", "tokenreviews", []string{"create"}).Return(fakeAccessReview(true), nil)
+	k8s.On("GetSelfSubjectAccessReview", "", "", "users", []string{"impersonate"}).Return(fakeAccessReview(true), nil)
+	k8s.On("GetSelfSubjectAccessReview", "", "", "groups", []string{"impersonate"}).Return(fakeAccessReview(false), nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	err := CheckApiTokenPermissions()
+	assert.Error(err)
+	assert.Contains(err.Error(), "impersonate groups")
+
+	_, err = layer.ApiToken.GetApiTokenOwner()
+	assert.True(k8s_errors.IsForbidden(err))
+	assert.Contains(err.Error(), "ClusterRole of Kiali")
+	_, err = ValidateApiToken(ApiTokenPrefix + "id_secret")
+	assert.Equal(apiTokensUnavailable, err)
+	k8s.AssertNotCalled(t, "GetTokenReview", mock.Anything)
+}
+
+func TestApiTokenRevokedWhileRead(t *testing.T) {
+	assert := assert.New(t)
+	k8s, stored := setupApiTokensMock()
+	layer := NewWithBackends(k8s, nil, nil)
+	created, err := layer.ApiToken.CreateApiToken(models.UserIdentity{Username: "alice"}, models.ApiTokenRequest{
+		Name:  "ci",
+		Scope: models.ApiTokenScope{Namespaces: []string{"bookinfo"}, ViewOnly: true},
+	})
+	assert.NoError(err)
+
+	// The token is revoked while a request reads the tokens: the request reads the Secret before the revocation
+	conf := config.Get()
+	outdated := stored.DeepCopy()
+	reading := new(kubetest.K8SClientMock)
+	reading.On("GetSecret", conf.Deployment.Namespace, conf.Auth.ApiTokens.SecretName).Run(func(mock.Arguments) {
+		assert.NoError(layer.ApiToken.RevokeApiToken("alice", created.ID))
+	}).Return(outdated, nil).Once()
+	reading.On("GetSecret", conf.Deployment.Namespace, conf.Auth.ApiTokens.SecretName).Return(stored, nil)
+	reading.On("UpdateSecret", conf.Deployment.Namespace, mock.AnythingOfType("*v1.Secret")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.Secret)
+	}).Return(stored, nil)
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(reading), nil)
+	apiTokensCache.tokens = nil
+
+	tokens, err := getApiTokens()
+	assert.NoError(err)
+	assert.Empty(tokens)
+
+	_, err = ValidateApiToken(created.Token)
+	assert.Equal(errApiTokenInvalid, err)
+}
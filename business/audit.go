@@ -0,0 +1,67 @@
+package business
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/util"
+)
+
+// Number of writes made through Kiali kept in memory, the oldest are dropped first
+const maxAuditEntries = 1000
+
+var (
+	auditNamespaceRegexp = regexp.MustCompile(`on Namespace: (\S+)`)
+	auditServiceRegexp   = regexp.MustCompile(`Service name: (\S+)`)
+)
+
+// auditEntry is a write made through Kiali, as written in the audit log
+type auditEntry struct {
+	time      time.Time
+	user      string
+	message   string
+	namespace string
+	service   string
+}
+
+// auditEntries is a ring of the latest writes made through Kiali. It is lost when Kiali restarts, and each replica
+// of Kiali only knows the writes it served.
+var auditEntries = struct {
+	sync.RWMutex
+	entries []auditEntry
+	next    int
+}{}
+
+// RecordAudit keeps a write made through Kiali, logged in the audit log, for the timelines of changes
+func RecordAudit(user, message string) {
+	entry := auditEntry{time: util.Clock.Now(), user: user, message: message}
+	if match := auditNamespaceRegexp.FindStringSubmatch(message); match != nil {
+		entry.namespace = match[1]
+	}
+	if match := auditServiceRegexp.FindStringSubmatch(message); match != nil {
+		entry.service = match[1]
+	}
+
+	auditEntries.Lock()
+	defer auditEntries.Unlock()
+	if len(auditEntries.entries) < maxAuditEntries {
+		auditEntries.entries = append(auditEntries.entries, entry)
+		return
+	}
+	auditEntries.entries[auditEntries.next] = entry
+	auditEntries.next = (auditEntries.next + 1) % maxAuditEntries
+}
+
+// getAuditEntries returns the writes made through Kiali on a namespace between two points of time
+func getAuditEntries(namespace string, start, end time.Time) []auditEntry {
+	auditEntries.RLock()
+	defer auditEntries.RUnlock()
+	entries := []auditEntry{}
+	for _, entry := range auditEntries.entries {
+		if entry.namespace == namespace && !entry.time.Before(start) && !entry.time.After(end) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
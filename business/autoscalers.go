@@ -0,0 +1,74 @@
+package business
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// getAutoscalers returns the HorizontalPodAutoscalers, VerticalPodAutoscalers and KEDA ScaledObjects targeting a
+// workload. They only complete its details: the errors, i.e. when the user is not allowed to list them, are logged and
+// skipped.
+func (in *WorkloadService) getAutoscalers(namespace string, workload *models.Workload) ([]models.HorizontalAutoscaler, []models.VerticalAutoscaler, []models.ScaledObject) {
+	horizontal := []models.HorizontalAutoscaler{}
+	if hpas, err := in.k8s.GetHorizontalPodAutoscalers(namespace); err == nil {
+		for i := range hpas {
+			target := hpas[i].Spec.ScaleTargetRef
+			if target.Kind == workload.Type && target.Name == workload.Name {
+				autoscaler := models.HorizontalAutoscaler{}
+				autoscaler.Parse(&hpas[i])
+				horizontal = append(horizontal, autoscaler)
+			}
+		}
+	} else {
+		log.Debugf("Error fetching the HorizontalPodAutoscalers of namespace %s: %v", namespace, err)
+	}
+
+	vertical := []models.VerticalAutoscaler{}
+	if vpas, err := in.k8s.GetVerticalPodAutoscalers(namespace); err == nil {
+		for i := range vpas {
+			target := vpas[i].Spec.TargetRef
+			if target != nil && target.Kind == workload.Type && target.Name == workload.Name {
+				autoscaler := models.VerticalAutoscaler{}
+				autoscaler.Parse(&vpas[i])
+				vertical = append(vertical, autoscaler)
+			}
+		}
+	} else {
+		log.Debugf("Error fetching the VerticalPodAutoscalers of namespace %s: %v", namespace, err)
+	}
+
+	scaled := []models.ScaledObject{}
+	if sos, err := in.k8s.GetScaledObjects(namespace); err == nil {
+		for i := range sos {
+			target := sos[i].Spec.ScaleTargetRef
+			kind := target.Kind
+			if kind == "" {
+				kind = kubernetes.DeploymentType
+			}
+			if kind == workload.Type && target.Name == workload.Name {
+				scaledObject := models.ScaledObject{}
+				scaledObject.Parse(&sos[i], findKedaAutoscaler(&sos[i], horizontal))
+				scaled = append(scaled, scaledObject)
+			}
+		}
+	} else {
+		log.Debugf("Error fetching the ScaledObjects of namespace %s: %v", namespace, err)
+	}
+	return horizontal, vertical, scaled
+}
+
+// findKedaAutoscaler returns the HorizontalPodAutoscaler created by KEDA for a ScaledObject, named keda-hpa-<name>
+// unless its status says otherwise
+func findKedaAutoscaler(so *kubernetes.ScaledObject, horizontal []models.HorizontalAutoscaler) *models.HorizontalAutoscaler {
+	hpaName := so.Status.HpaName
+	if hpaName == "" {
+		hpaName = "keda-hpa-" + so.Name
+	}
+	for i := range horizontal {
+		if horizontal[i].Name == hpaName {
+			return &horizontal[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package business
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscaling_v1 "k8s.io/api/autoscaling/v1"
+	autoscaling_v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeHorizontalPodAutoscaler(name, kind, target string) autoscaling_v2beta2.HorizontalPodAutoscaler {
+	minReplicas := int32(2)
+	utilization := int32(60)
+	currentUtilization := int32(85)
+	requests := resource.MustParse("100")
+	lastScale := meta_v1.NewTime(time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC))
+	return autoscaling_v2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"},
+		Spec: autoscaling_v2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscaling_v2beta2.CrossVersionObjectReference{Kind: kind, Name: target, APIVersion: "apps/v1"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    10,
+			Metrics: []autoscaling_v2beta2.MetricSpec{
+				{Type: autoscaling_v2beta2.ResourceMetricSourceType, Resource: &autoscaling_v2beta2.ResourceMetricSource{
+					Name:   core_v1.ResourceCPU,
+					Target: autoscaling_v2beta2.MetricTarget{Type: autoscaling_v2beta2.UtilizationMetricType, AverageUtilization: &utilization},
+				}},
+				{Type: autoscaling_v2beta2.PodsMetricSourceType, Pods: &autoscaling_v2beta2.PodsMetricSource{
+					Metric: autoscaling_v2beta2.MetricIdentifier{Name: "istio_requests_per_second"},
+					Target: autoscaling_v2beta2.MetricTarget{Type: autoscaling_v2beta2.AverageValueMetricType, AverageValue: &requests},
+				}},
+			},
+		},
+		Status: autoscaling_v2beta2.HorizontalPodAutoscalerStatus{
+			LastScaleTime:   &lastScale,
+			CurrentReplicas: 2,
+			DesiredReplicas: 3,
+			CurrentMetrics: []autoscaling_v2beta2.MetricStatus{
+				{Type: autoscaling_v2beta2.ResourceMetricSourceType, Resource: &autoscaling_v2beta2.ResourceMetricStatus{
+					Name:    core_v1.ResourceCPU,
+					Current: autoscaling_v2beta2.MetricValueStatus{AverageUtilization: &currentUtilization},
+				}},
+			},
+			Conditions: []autoscaling_v2beta2.HorizontalPodAutoscalerCondition{{
+				Type:               autoscaling_v2beta2.ScalingActive,
+				Status:             core_v1.ConditionTrue,
+				Reason:             "ValidMetricFound",
+				LastTransitionTime: lastScale,
+			}},
+		},
+	}
+}
+
+func TestGetAutoscalers(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vpa := kubernetes.VerticalPodAutoscaler{ObjectMeta: meta_v1.ObjectMeta{Name: "details-vpa"}}
+	vpa.Spec.TargetRef = &autoscaling_v1.CrossVersionObjectReference{Kind: "Deployment", Name: "details-v1"}
+	vpa.Status.Conditions = []kubernetes.VerticalPodAutoscalerCondition{{Type: "RecommendationProvided", Status: "True"}}
+	vpa.Status.Recommendation = &kubernetes.RecommendedPodResources{ContainerRecommendations: []kubernetes.VerticalPodAutoscalerRecommendation{{
+		ContainerName: "details",
+		Target:        core_v1.ResourceList{core_v1.ResourceCPU: resource.MustParse("25m"), core_v1.ResourceMemory: resource.MustParse("256Mi")},
+	}}}
+	otherVpa := kubernetes.VerticalPodAutoscaler{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-vpa"}}
+	otherVpa.Spec.TargetRef = &autoscaling_v1.CrossVersionObjectReference{Kind: "Deployment", Name: "reviews-v1"}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetHorizontalPodAutoscalers", "bookinfo").Return([]autoscaling_v2beta2.HorizontalPodAutoscaler{
+		fakeHorizontalPodAutoscaler("details-hpa", "Deployment", "details-v1"),
+		fakeHorizontalPodAutoscaler("details-statefulset-hpa", "StatefulSet", "details-v1"),
+		fakeHorizontalPodAutoscaler("reviews-hpa", "Deployment", "reviews-v1"),
+	}, nil)
+	k8s.On("GetVerticalPodAutoscalers", "bookinfo").Return([]kubernetes.VerticalPodAutoscaler{vpa, otherVpa}, nil)
+	k8s.On("GetScaledObjects", "bookinfo").Return([]kubernetes.ScaledObject{}, nil)
+	k8s.On("GetHorizontalPodAutoscalers", "restricted").Return([]autoscaling_v2beta2.HorizontalPodAutoscaler{}, errors.New("forbidden"))
+	k8s.On("GetVerticalPodAutoscalers", "restricted").Return([]kubernetes.VerticalPodAutoscaler{}, nil)
+	k8s.On("GetScaledObjects", "restricted").Return([]kubernetes.ScaledObject{}, errors.New("forbidden"))
+	svc := setupWorkloadService(k8s)
+
+	workload := &models.Workload{}
+	workload.Name = "details-v1"
+	workload.Type = "Deployment"
+	horizontal, vertical, scaled := svc.getAutoscalers("bookinfo", workload)
+
+	assert.Len(horizontal, 1)
+	hpa := horizontal[0]
+	assert.Equal("details-hpa", hpa.Name)
+	assert.Equal(int32(2), hpa.MinReplicas)
+	assert.Equal(int32(10), hpa.MaxReplicas)
+	assert.Equal(int32(3), hpa.DesiredReplicas)
+	assert.Equal("2021-03-01T10:00:00Z", hpa.LastScaleTime)
+	assert.Equal([]models.AutoscalerMetric{
+		{Name: "cpu", Type: "Resource", Current: "85%", Target: "60%"},
+		{Name: "istio_requests_per_second", Type: "Pods", Target: "100"},
+	}, hpa.Metrics)
+	assert.Equal("ScalingActive", hpa.Conditions[0].Type)
+
+	assert.Len(vertical, 1)
+	assert.Equal("details-vpa", vertical[0].Name)
+	assert.Equal("Auto", vertical[0].UpdateMode)
+	assert.Equal([]models.ContainerRecommendation{{Container: "details", Target: map[string]string{"cpu": "25m", "memory": "256Mi"}}}, vertical[0].Recommendations)
+
+	horizontal, vertical, scaled = svc.getAutoscalers("restricted", workload)
+	assert.Empty(horizontal)
+	assert.Empty(vertical)
+	assert.Empty(scaled)
+}
+
+func TestGetScaledObjects(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// KEDA scales the workload through its own HorizontalPodAutoscaler, with a metric per trigger
+	kedaHpa := fakeHorizontalPodAutoscaler("keda-hpa-details", "Deployment", "details-v1")
+	threshold := resource.MustParse("50")
+	current := resource.MustParse("25")
+	kedaHpa.Spec.Metrics = []autoscaling_v2beta2.MetricSpec{{Type: autoscaling_v2beta2.ExternalMetricSourceType, External: &autoscaling_v2beta2.ExternalMetricSource{
+		Metric: autoscaling_v2beta2.MetricIdentifier{Name: "s0-prometheus-istio_requests"},
+		Target: autoscaling_v2beta2.MetricTarget{Type: autoscaling_v2beta2.AverageValueMetricType, AverageValue: &threshold},
+	}}}
+	kedaHpa.Status.CurrentMetrics = []autoscaling_v2beta2.MetricStatus{{Type: autoscaling_v2beta2.ExternalMetricSourceType, External: &autoscaling_v2beta2.ExternalMetricStatus{
+		Current: autoscaling_v2beta2.MetricValueStatus{AverageValue: &current},
+	}}}
+
+	maxReplicas := int32(5)
+	so := kubernetes.ScaledObject{ObjectMeta: meta_v1.ObjectMeta{
+		Name:        "details",
+		Annotations: map[string]string{kubernetes.ScaledObjectPausedReplicasAnnotation: "1"},
+	}}
+	// The kind of the target defaults to Deployment
+	so.Spec.ScaleTargetRef.Name = "details-v1"
+	so.Spec.MaxReplicaCount = &maxReplicas
+	so.Spec.Triggers = []kubernetes.ScaledObjectTrigger{{Type: "prometheus"}, {Type: "cron"}}
+	so.Status.Conditions = []kubernetes.ScaledObjectCondition{{Type: "Ready", Status: "True"}, {Type: "Active", Status: "False"}}
+	otherSo := kubernetes.ScaledObject{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews"}}
+	otherSo.Spec.ScaleTargetRef.Name = "reviews-v1"
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetHorizontalPodAutoscalers", "bookinfo").Return([]autoscaling_v2beta2.HorizontalPodAutoscaler{kedaHpa}, nil)
+	k8s.On("GetVerticalPodAutoscalers", "bookinfo").Return([]kubernetes.VerticalPodAutoscaler{}, nil)
+	k8s.On("GetScaledObjects", "bookinfo").Return([]kubernetes.ScaledObject{so, otherSo}, nil)
+	svc := setupWorkloadService(k8s)
+
+	workload := &models.Workload{}
+	workload.Name = "details-v1"
+	workload.Type = "Deployment"
+	_, _, scaled := svc.getAutoscalers("bookinfo", workload)
+
+	assert.Len(scaled, 1)
+	assert.Equal("details", scaled[0].Name)
+	assert.Equal(int32(0), scaled[0].MinReplicas)
+	assert.Equal(int32(5), scaled[0].MaxReplicas)
+	assert.True(scaled[0].Paused)
+	assert.Equal(int32(1), *scaled[0].PausedReplicas)
+	assert.False(scaled[0].Active)
+	assert.Equal([]models.ScaledObjectTrigger{
+		{Type: "prometheus", Current: "25", Target: "50"},
+		{Type: "cron"},
+	}, scaled[0].Triggers)
+	assert.Len(scaled[0].Conditions, 2)
+}
@@ -0,0 +1,58 @@
+package business
+
+import (
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/models"
+)
+
+// GetCacheStatus returns the status of the caches of Kiali. Informers are only reported for the given namespaces,
+// which should be the namespaces accessible by the user.
+func GetCacheStatus(namespaces []models.Namespace) models.CacheStatus {
+	conf := config.Get()
+	status := models.CacheStatus{
+		Enabled:  kialiCache != nil,
+		Clusters: []models.ClusterCacheStatus{},
+		Counters: cache.GetCacheCounters(),
+	}
+
+	homeCluster := models.ClusterCacheStatus{
+		Cluster:   conf.KubernetesConfig.ClusterName,
+		Informers: []models.InformerStatus{},
+		Warming:   []string{},
+	}
+	if kialiCache != nil {
+		accessible := make(map[string]bool, len(namespaces))
+		for _, ns := range namespaces {
+			accessible[ns.Name] = true
+		}
+		cached := kialiCache.GetStatus()
+		homeCluster.Cached = cached.Cached
+		for _, informer := range cached.Informers {
+			if accessible[informer.Namespace] {
+				homeCluster.Informers = append(homeCluster.Informers, informer)
+			}
+		}
+		for _, namespace := range cached.Warming {
+			if accessible[namespace] {
+				homeCluster.Warming = append(homeCluster.Warming, namespace)
+			}
+		}
+	}
+	status.Clusters = append(status.Clusters, homeCluster)
+
+	// The Kiali cache only holds objects of the home cluster: remote clusters are always fetched from their API
+	for _, cluster := range conf.Clustering.Clusters {
+		status.Clusters = append(status.Clusters, models.ClusterCacheStatus{
+			Cluster:   cluster.Name,
+			Informers: []models.InformerStatus{},
+			Warming:   []string{},
+		})
+	}
+	return status
+}
+
+// IsCacheWarming returns true while the Kiali cache is warming up namespaces in the background
+func IsCacheWarming() bool {
+	return kialiCache != nil && len(kialiCache.GetWarmingNamespaces()) > 0
+}
@@ -0,0 +1,231 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Defaults of the success criteria of a canary
+const (
+	defaultPromotionStep          = 10
+	defaultPromotionWindow        = "5m"
+	defaultPromotionMaxErrorRate  = 1.0
+	defaultPromotionMaxP99Latency = 500.0
+)
+
+// PromoteCanary evaluates the error rate and p99 latency of the canary subset of a service over a window. When both
+// are under their thresholds, the weight of the canary in the VirtualService of the service is raised by a step,
+// taken from the other destinations of its route. Without traffic, the criteria fail.
+func (in *WizardService) PromoteCanary(namespace, service string, request models.CanaryPromotionRequest) (*models.CanaryPromotion, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "PromoteCanary")
+	defer promtimer.ObserveNow(&err)
+
+	if request.Subset == "" {
+		err = errors.NewBadRequest("the subset of the canary is required")
+		return nil, err
+	}
+	if request.Step == 0 {
+		request.Step = defaultPromotionStep
+	}
+	if request.Step < 0 || request.Step > 100 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid step %d, expected a weight between 1 and 100", request.Step))
+		return nil, err
+	}
+	if request.Window == "" {
+		request.Window = defaultPromotionWindow
+	}
+	if _, err = pmod.ParseDuration(request.Window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 5m", request.Window))
+		return nil, err
+	}
+	if request.MaxErrorRate <= 0 {
+		request.MaxErrorRate = defaultPromotionMaxErrorRate
+	}
+	if request.MaxP99LatencyMillis <= 0 {
+		request.MaxP99LatencyMillis = defaultPromotionMaxP99Latency
+	}
+	ttl, err := parseWizardTTL(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, service)
+	if err != nil {
+		return nil, err
+	}
+	// Copied, so that the spec of the cached object is left as is
+	spec := map[string]interface{}{}
+	raw, _ := json.Marshal(vs.GetSpec())
+	if err = json.Unmarshal(raw, &spec); err != nil {
+		return nil, err
+	}
+	route := canaryRoute(spec, request.Subset)
+	if route == nil {
+		err = errors.NewBadRequest(fmt.Sprintf("no http route of VirtualService %s routes to subset %s", service, request.Subset))
+		return nil, err
+	}
+
+	version := request.Subset
+	if dr, drErr := in.k8s.GetIstioObject(namespace, kubernetes.DestinationRules, service); drErr == nil {
+		version = subsetVersion(dr.GetSpec(), request.Subset)
+	}
+
+	promotion := &models.CanaryPromotion{Namespace: namespace, Service: service, Subset: request.Subset, Window: request.Window}
+	if promotion.Criteria, err = in.evaluateCanary(namespace, service, version, request); err != nil {
+		return nil, err
+	}
+	promotion.Passed = true
+	for _, c := range promotion.Criteria {
+		promotion.Passed = promotion.Passed && c.Passed
+	}
+
+	promotion.PreviousWeight = shiftCanaryWeight(route, request.Subset, 0)
+	promotion.Weight = promotion.PreviousWeight
+	if !promotion.Passed || promotion.PreviousWeight == 100 {
+		return promotion, nil
+	}
+	promotion.Weight = shiftCanaryWeight(route, request.Subset, request.Step)
+	promotion.Change, err = in.applyWizardObjects(namespace, service, models.WizardCanaryPromotion, ttl, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: service, spec: spec},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// evaluateCanary returns the error rate and p99 latency criteria of the inbound requests of a version of a service
+func (in *WizardService) evaluateCanary(namespace, service, version string, request models.CanaryPromotionRequest) ([]models.PromotionCriterion, error) {
+	queryTime := util.Clock.Now()
+	lb := NewMetricsLabelsBuilder("inbound").SelfReporter().Service(service, namespace).Add("destination_version", version)
+	labels := lb.Build()
+
+	errorRate := models.PromotionCriterion{Name: "errorRate", Threshold: request.MaxErrorRate}
+	ratio, hasData, err := in.prom.FetchRateRatio(metricSelectors("istio_requests_total", lb.BuildForServerErrors()), "istio_requests_total"+labels, request.Window, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	if hasData {
+		value := ratio * 100
+		errorRate.Value = &value
+		errorRate.Passed = value <= request.MaxErrorRate
+	}
+
+	latency := models.PromotionCriterion{Name: "p99Latency", Threshold: request.MaxP99LatencyMillis}
+	histogram, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", request.Window, false, []string{"0.99"}, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	if vector := histogram["0.99"]; len(vector) > 0 && !math.IsNaN(float64(vector[0].Value)) {
+		value := float64(vector[0].Value)
+		latency.Value = &value
+		latency.Passed = value <= request.MaxP99LatencyMillis
+	}
+	return []models.PromotionCriterion{errorRate, latency}, nil
+}
+
+// canaryRoute returns the destinations of the first http route of a VirtualService spec routing to a subset
+func canaryRoute(spec map[string]interface{}, subset string) []interface{} {
+	httpRoutes, _ := spec["http"].([]interface{})
+	for _, h := range httpRoutes {
+		httpRoute, _ := h.(map[string]interface{})
+		destinations, _ := httpRoute["route"].([]interface{})
+		for _, d := range destinations {
+			destination, _ := d.(map[string]interface{})
+			target, _ := destination["destination"].(map[string]interface{})
+			if target["subset"] == subset {
+				return destinations
+			}
+		}
+	}
+	return nil
+}
+
+// shiftCanaryWeight adds step to the weight of the subset in the destinations of a route, taking it from the other
+// destinations in order. It returns the new weight of the subset.
+func shiftCanaryWeight(destinations []interface{}, subset string, step int) int {
+	weight := func(d map[string]interface{}) int {
+		if len(destinations) == 1 {
+			// A single destination gets all the traffic, whatever its weight
+			return 100
+		}
+		switch w := d["weight"].(type) {
+		case float64:
+			return int(w)
+		case int:
+			return w
+		case int64:
+			return int(w)
+		}
+		return 0
+	}
+
+	var canary map[string]interface{}
+	for _, d := range destinations {
+		destination, _ := d.(map[string]interface{})
+		target, _ := destination["destination"].(map[string]interface{})
+		if target["subset"] == subset {
+			canary = destination
+		}
+	}
+	current := weight(canary)
+	if step <= 0 {
+		return current
+	}
+	if current+step > 100 {
+		step = 100 - current
+	}
+
+	remaining := step
+	for _, d := range destinations {
+		destination, _ := d.(map[string]interface{})
+		if remaining == 0 {
+			break
+		}
+		if destination == nil || isSameDestination(destination, canary) {
+			continue
+		}
+		taken := weight(destination)
+		if taken > remaining {
+			taken = remaining
+		}
+		destination["weight"] = weight(destination) - taken
+		remaining -= taken
+	}
+	canary["weight"] = current + step - remaining
+	return current + step - remaining
+}
+
+func isSameDestination(a, b map[string]interface{}) bool {
+	targetA, _ := a["destination"].(map[string]interface{})
+	targetB, _ := b["destination"].(map[string]interface{})
+	return targetA["host"] == targetB["host"] && targetA["subset"] == targetB["subset"]
+}
+
+// subsetVersion returns the version label of a subset of a DestinationRule spec, or the name of the subset
+func subsetVersion(spec map[string]interface{}, subset string) string {
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	subsets, _ := spec["subsets"].([]interface{})
+	for _, s := range subsets {
+		definition, _ := s.(map[string]interface{})
+		if definition["name"] != subset {
+			continue
+		}
+		labels, _ := definition["labels"].(map[string]interface{})
+		if version, ok := labels[versionLabel].(string); ok {
+			return version
+		}
+	}
+	return subset
+}
@@ -0,0 +1,109 @@
+package business
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeCanaryVirtualService() *kubernetes.GenericIstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+			"http": []interface{}{map[string]interface{}{"route": []interface{}{
+				map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "stable"}, "weight": float64(95)},
+				map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "canary"}, "weight": float64(5)},
+			}}},
+		},
+	}
+}
+
+func setupCanaryPromotion(errorRatio float64, p99 float64) (*kubetest.K8SClientMock, *WizardService) {
+	setupWizardChangesMock(new(kubetest.K8SClientMock))
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(fakeCanaryVirtualService(), nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(&kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{"subsets": []interface{}{map[string]interface{}{"name": "canary", "labels": map[string]interface{}{"version": "v2"}}}},
+	}, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+
+	labels := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo",destination_version="v2"}`
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateRatio", []string{
+		"istio_requests_total" + strings.TrimSuffix(labels, "}") + `,response_code=~"^0$|^5\\d\\d$"}`,
+		"istio_requests_total" + strings.TrimSuffix(labels, "}") + `,grpc_response_status=~"^2$|^4$|^1[2-5]$",response_code!~"^0$|^5\\d\\d$"}`,
+	}, "istio_requests_total"+labels, "5m", mock.Anything).Return(errorRatio, true, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "", "5m", false, []string{"0.99"}, mock.Anything).
+		Return(map[string]pmod.Vector{"0.99": {&pmod.Sample{Value: pmod.SampleValue(p99)}}}, nil)
+	return k8s, &WizardService{k8s: k8s, prom: prom}
+}
+
+func TestPromoteCanary(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s, svc := setupCanaryPromotion(0.002, 120)
+	promotion, err := svc.PromoteCanary("bookinfo", "reviews", models.CanaryPromotionRequest{Subset: "canary"})
+	assert.NoError(err)
+	assert.True(promotion.Passed)
+	assert.Equal(5, promotion.PreviousWeight)
+	assert.Equal(15, promotion.Weight)
+	assert.Equal(models.WizardCanaryPromotion, promotion.Change.Wizard)
+	assert.Len(promotion.Criteria, 2)
+	assert.InDelta(0.2, *promotion.Criteria[0].Value, 0.0001)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	patch := k8s.Calls[len(k8s.Calls)-1].Arguments.String(4)
+	assert.Contains(patch, `{"destination":{"host":"reviews","subset":"stable"},"weight":85}`)
+	assert.Contains(patch, `{"destination":{"host":"reviews","subset":"canary"},"weight":15}`)
+	// The cached object is left as is
+	assert.Equal(float64(5), fakeCanaryVirtualService().Spec["http"].([]interface{})[0].(map[string]interface{})["route"].([]interface{})[1].(map[string]interface{})["weight"])
+}
+
+func TestPromoteCanaryFailingCriteria(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s, svc := setupCanaryPromotion(0.002, 800)
+	promotion, err := svc.PromoteCanary("bookinfo", "reviews", models.CanaryPromotionRequest{Subset: "canary"})
+	assert.NoError(err)
+	assert.False(promotion.Passed)
+	assert.True(promotion.Criteria[0].Passed)
+	assert.False(promotion.Criteria[1].Passed)
+	assert.Equal(5, promotion.Weight)
+	assert.Nil(promotion.Change)
+	k8s.AssertNotCalled(t, "UpdateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = svc.PromoteCanary("bookinfo", "reviews", models.CanaryPromotionRequest{Subset: "preview"})
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.PromoteCanary("bookinfo", "reviews", models.CanaryPromotionRequest{Subset: "canary", Window: "soon"})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestShiftCanaryWeight(t *testing.T) {
+	assert := assert.New(t)
+
+	destinations := []interface{}{
+		map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}, "weight": 4},
+		map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v2"}, "weight": 6},
+		map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v3"}, "weight": 90},
+	}
+	assert.Equal(100, shiftCanaryWeight(destinations, "v3", 25))
+	assert.Equal(0, destinations[0].(map[string]interface{})["weight"])
+	assert.Equal(0, destinations[1].(map[string]interface{})["weight"])
+}
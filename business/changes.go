@@ -0,0 +1,275 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Annotation of the ReplicaSets with the revision of their Deployment
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Istio types whose changes are in the timelines
+var changeIstioTypes = []string{
+	kubernetes.AuthorizationPolicies,
+	kubernetes.DestinationRules,
+	kubernetes.EnvoyFilters,
+	kubernetes.Gateways,
+	kubernetes.PeerAuthentications,
+	kubernetes.RequestAuthentications,
+	kubernetes.ServiceEntries,
+	kubernetes.Sidecars,
+	kubernetes.VirtualServices,
+	kubernetes.WorkloadEntries,
+}
+
+// ChangeService builds the timelines of the changes of the namespaces, to correlate them with the anomalies of the
+// traffic. The changes are read with the credentials of the user, from what Kubernetes keeps: the creation and the
+// last modification of each field manager of the Istio objects, the ReplicaSets of the Deployments, and the scaling
+// events, which expire after one hour by default. The writes made through Kiali are added when the audit log is
+// enabled.
+type ChangeService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// GetChangeTimeline returns the changes of a namespace between two points of time, the oldest first. When a service
+// is given, only the changes of its Istio objects, of the Istio objects applied to its pods and of its workloads are
+// returned. The sources of changes which cannot be read are reported in the errors of the timeline.
+func (in *ChangeService) GetChangeTimeline(namespace, service string, start, end time.Time) (*models.ChangeTimeline, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ChangeService", "GetChangeTimeline")
+	defer promtimer.ObserveNow(&err)
+
+	if !start.Before(end) {
+		err = k8s_errors.NewBadRequest("the start of the time window must be before its end")
+		return nil, err
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var svc *core_v1.Service
+	if service != "" {
+		if svc, err = in.k8s.GetService(namespace, service); err != nil {
+			return nil, err
+		}
+	}
+
+	timeline := &models.ChangeTimeline{
+		Namespace: namespace,
+		Service:   service,
+		StartTime: start,
+		EndTime:   end,
+		Changes:   []models.Change{},
+		Errors:    map[string]string{},
+	}
+	inWindow := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(start) && !t.After(end)
+	}
+
+	for _, istioType := range changeIstioTypes {
+		objects, err := in.k8s.GetIstioObjects(namespace, istioType, "")
+		if err != nil {
+			timeline.Errors[istioType] = err.Error()
+			continue
+		}
+		for _, object := range filterChangeIstioObjects(istioType, objects, namespace, svc) {
+			timeline.Changes = append(timeline.Changes, istioObjectChanges(kubernetes.PluralType[istioType], object, inWindow)...)
+		}
+	}
+
+	// The deployments are filtered by the labels of the template of their ReplicaSets
+	deployments := map[string]bool{}
+	if replicaSets, err := in.k8s.GetReplicaSets(namespace); err != nil {
+		timeline.Errors["replicasets"] = err.Error()
+	} else {
+		for _, rs := range replicaSets {
+			deployment := replicaSetDeployment(rs)
+			if deployment == "" || (svc != nil && !selectsTemplate(svc, rs)) {
+				continue
+			}
+			deployments[deployment] = true
+			if inWindow(rs.CreationTimestamp.Time) {
+				timeline.Changes = append(timeline.Changes, rolloutChange(deployment, rs))
+			}
+		}
+	}
+
+	if events, err := in.k8s.GetEvents(namespace); err != nil {
+		timeline.Errors["events"] = err.Error()
+	} else {
+		for _, event := range events {
+			if change, ok := scalingChange(event, svc, deployments); ok && inWindow(change.Time) {
+				timeline.Changes = append(timeline.Changes, change)
+			}
+		}
+	}
+
+	for _, entry := range getAuditEntries(namespace, start, end) {
+		if service != "" && entry.service != "" && entry.service != service {
+			continue
+		}
+		timeline.Changes = append(timeline.Changes, models.Change{
+			Time:      entry.time,
+			Kind:      models.ChangeKindAudit,
+			Operation: models.ChangeOpKialiWrite,
+			Message:   entry.message,
+			Author:    entry.user,
+		})
+	}
+
+	sort.SliceStable(timeline.Changes, func(i, j int) bool {
+		return timeline.Changes[i].Time.Before(timeline.Changes[j].Time)
+	})
+	return timeline, nil
+}
+
+// filterChangeIstioObjects keeps the Istio objects of a service: the routes and the destination rules of its host,
+// and the objects applied to its pods, selected by their labels or applied to the whole namespace
+func filterChangeIstioObjects(istioType string, objects []kubernetes.IstioObject, namespace string, svc *core_v1.Service) []kubernetes.IstioObject {
+	if svc == nil {
+		return objects
+	}
+	switch istioType {
+	case kubernetes.VirtualServices:
+		return kubernetes.FilterVirtualServices(objects, namespace, svc.Name)
+	case kubernetes.DestinationRules:
+		return kubernetes.FilterDestinationRules(objects, namespace, svc.Name)
+	case kubernetes.Gateways, kubernetes.ServiceEntries, kubernetes.WorkloadEntries:
+		return nil
+	}
+	filtered := []kubernetes.IstioObject{}
+	for _, object := range objects {
+		selector, ok := istioObjectSelector(object)
+		if !ok || (len(svc.Spec.Selector) > 0 && selector.Matches(labels.Set(svc.Spec.Selector))) {
+			filtered = append(filtered, object)
+		}
+	}
+	return filtered
+}
+
+// istioObjectSelector returns the selector of the workloads of an Istio object, false when it has none and is
+// applied to the whole namespace
+func istioObjectSelector(object kubernetes.IstioObject) (labels.Selector, bool) {
+	var selectorLabels interface{}
+	if ws, ok := object.GetSpec()["workloadSelector"].(map[string]interface{}); ok {
+		selectorLabels = ws["labels"]
+	} else if s, ok := object.GetSpec()["selector"].(map[string]interface{}); ok {
+		selectorLabels = s["matchLabels"]
+	}
+	rawLabels, ok := selectorLabels.(map[string]interface{})
+	if !ok || len(rawLabels) == 0 {
+		return nil, false
+	}
+	set := labels.Set{}
+	for key, value := range rawLabels {
+		set[key] = fmt.Sprintf("%v", value)
+	}
+	return labels.SelectorFromSet(set), true
+}
+
+// istioObjectChanges returns the creation of an Istio object, and the last modification of each of its field
+// managers. Kubernetes doesn't keep the previous modifications.
+func istioObjectChanges(kind string, object kubernetes.IstioObject, inWindow func(time.Time) bool) []models.Change {
+	meta := object.GetObjectMeta()
+	changes := []models.Change{}
+	created := meta.CreationTimestamp.Time
+	if inWindow(created) {
+		changes = append(changes, models.Change{
+			Time:       created,
+			Kind:       models.ChangeKindConfig,
+			Operation:  models.ChangeOpCreated,
+			ObjectKind: kind,
+			ObjectName: meta.Name,
+		})
+	}
+	for _, field := range meta.ManagedFields {
+		// The fields written at the creation are not a modification
+		if field.Time == nil || field.Time.Time.Equal(created) || !inWindow(field.Time.Time) {
+			continue
+		}
+		changes = append(changes, models.Change{
+			Time:       field.Time.Time,
+			Kind:       models.ChangeKindConfig,
+			Operation:  models.ChangeOpModified,
+			ObjectKind: kind,
+			ObjectName: meta.Name,
+			Message:    fmt.Sprintf("%s of the fields of %s", strings.ToLower(string(field.Operation)), field.Manager),
+			Author:     field.Manager,
+		})
+	}
+	return changes
+}
+
+// replicaSetDeployment returns the name of the Deployment of a ReplicaSet, empty when it has none
+func replicaSetDeployment(rs apps_v1.ReplicaSet) string {
+	for _, ref := range rs.OwnerReferences {
+		if ref.Kind == "Deployment" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+func selectsTemplate(svc *core_v1.Service, rs apps_v1.ReplicaSet) bool {
+	return len(svc.Spec.Selector) > 0 && labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(rs.Spec.Template.Labels))
+}
+
+// rolloutChange returns the rollout of a revision of a Deployment, with the images of the revision. The ReplicaSet
+// of a revision is created when its template changes, i.e. a new image.
+func rolloutChange(deployment string, rs apps_v1.ReplicaSet) models.Change {
+	images := make([]string, 0, len(rs.Spec.Template.Spec.Containers))
+	for _, container := range rs.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	message := fmt.Sprintf("images [%s]", strings.Join(images, ", "))
+	if revision, ok := rs.Annotations[deploymentRevisionAnnotation]; ok {
+		message = fmt.Sprintf("revision %s with %s", revision, message)
+	}
+	return models.Change{
+		Time:       rs.CreationTimestamp.Time,
+		Kind:       models.ChangeKindRollout,
+		Operation:  models.ChangeOpRolledOut,
+		ObjectKind: "Deployment",
+		ObjectName: deployment,
+		Message:    message,
+	}
+}
+
+// scalingChange returns the change of the replicas of an event of a Deployment or of an autoscaler. The autoscaler
+// events don't tell their target, they are omitted from the timelines of the services.
+func scalingChange(event core_v1.Event, svc *core_v1.Service, deployments map[string]bool) (models.Change, bool) {
+	switch {
+	case event.Reason == "ScalingReplicaSet" && event.InvolvedObject.Kind == "Deployment":
+		if svc != nil && !deployments[event.InvolvedObject.Name] {
+			return models.Change{}, false
+		}
+	case event.Reason == "SuccessfulRescale" && event.InvolvedObject.Kind == "HorizontalPodAutoscaler":
+		if svc != nil {
+			return models.Change{}, false
+		}
+	default:
+		return models.Change{}, false
+	}
+	var e models.KubernetesEvent
+	e.Parse(event)
+	return models.Change{
+		Time:       e.LastTimestamp,
+		Kind:       models.ChangeKindScaling,
+		Operation:  models.ChangeOpScaled,
+		ObjectKind: event.InvolvedObject.Kind,
+		ObjectName: event.InvolvedObject.Name,
+		Message:    event.Message,
+		Author:     event.Source.Component,
+	}, true
+}
@@ -0,0 +1,143 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGetChangeTimeline(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+	kialiCache = nil
+
+	at := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	minutes := func(m int) meta_v1.Time {
+		return meta_v1.NewTime(at.Add(time.Duration(m) * time.Minute))
+	}
+	modified := minutes(20)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.DestinationRules, "").Return([]kubernetes.IstioObject{
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:              "reviews",
+				CreationTimestamp: minutes(-120),
+				ManagedFields: []meta_v1.ManagedFieldsEntry{
+					{Manager: "kubectl-client-side-apply", Operation: meta_v1.ManagedFieldsOperationUpdate, Time: &modified},
+				},
+			},
+			Spec: map[string]interface{}{"host": "reviews"},
+		},
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", CreationTimestamp: minutes(10)},
+			Spec:       map[string]interface{}{"host": "ratings"},
+		},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.Sidecars, "").Return([]kubernetes.IstioObject{
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "default", CreationTimestamp: minutes(5)},
+			Spec:       map[string]interface{}{},
+		},
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", CreationTimestamp: minutes(6)},
+			Spec:       map[string]interface{}{"workloadSelector": map[string]interface{}{"labels": map[string]interface{}{"app": "ratings"}}},
+		},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", mock.AnythingOfType("string"), "").Return([]kubernetes.IstioObject{}, nil)
+	replicaSet := func(name, deployment, app, revision, image string, created meta_v1.Time) apps_v1.ReplicaSet {
+		rs := apps_v1.ReplicaSet{ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: created,
+			Annotations:       map[string]string{deploymentRevisionAnnotation: revision},
+			OwnerReferences:   []meta_v1.OwnerReference{{Kind: "Deployment", Name: deployment}},
+		}}
+		rs.Spec.Template.Labels = map[string]string{"app": app}
+		rs.Spec.Template.Spec.Containers = []core_v1.Container{{Name: app, Image: image}}
+		return rs
+	}
+	k8s.On("GetReplicaSets", "bookinfo").Return([]apps_v1.ReplicaSet{
+		replicaSet("reviews-v1-545db77b95", "reviews-v1", "reviews", "1", "reviews:v1", minutes(-600)),
+		replicaSet("reviews-v1-7bd7d7d7d7", "reviews-v1", "reviews", "2", "reviews:v2", minutes(30)),
+		replicaSet("ratings-v1-6f855c5fff", "ratings-v1", "ratings", "2", "ratings:v2", minutes(31)),
+	}, nil)
+	k8s.On("GetEvents", "bookinfo").Return([]core_v1.Event{
+		{
+			Reason:         "ScalingReplicaSet",
+			Message:        "Scaled up replica set reviews-v1-7bd7d7d7d7 to 1",
+			InvolvedObject: core_v1.ObjectReference{Kind: "Deployment", Name: "reviews-v1"},
+			Source:         core_v1.EventSource{Component: "deployment-controller"},
+			LastTimestamp:  minutes(30),
+		},
+		{
+			Reason:         "ScalingReplicaSet",
+			Message:        "Scaled up replica set ratings-v1-6f855c5fff to 1",
+			InvolvedObject: core_v1.ObjectReference{Kind: "Deployment", Name: "ratings-v1"},
+			LastTimestamp:  minutes(31),
+		},
+		{
+			Reason:         "Unhealthy",
+			InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v1-7bd7d7d7d7-wnvc9"},
+			LastTimestamp:  minutes(32),
+		},
+	}, nil)
+
+	util.Clock = util.ClockMock{Time: at.Add(40 * time.Minute)}
+	auditEntries.entries = nil
+	auditEntries.next = 0
+	RecordAudit("alice", "WIZARD traffic_shifting on Namespace: bookinfo Service name: reviews TTL: ")
+	RecordAudit("alice", "WIZARD traffic_shifting on Namespace: bookinfo Service name: ratings TTL: ")
+	RecordAudit("bob", "UPDATE on Namespace: travels Patch: {}")
+	defer func() { util.Clock = util.RealClock{} }()
+
+	service := NewWithBackends(k8s, nil, nil).Change
+
+	timeline, err := service.GetChangeTimeline("bookinfo", "reviews", at, at.Add(time.Hour))
+	require.NoError(err)
+	require.Len(timeline.Changes, 5)
+	assert.Empty(timeline.Errors)
+
+	assert.Equal(models.ChangeOpCreated, timeline.Changes[0].Operation)
+	assert.Equal("Sidecar", timeline.Changes[0].ObjectKind)
+	assert.Equal("default", timeline.Changes[0].ObjectName)
+
+	assert.Equal(models.ChangeOpModified, timeline.Changes[1].Operation)
+	assert.Equal("DestinationRule", timeline.Changes[1].ObjectKind)
+	assert.Equal("kubectl-client-side-apply", timeline.Changes[1].Author)
+
+	assert.Equal(models.ChangeKindRollout, timeline.Changes[2].Kind)
+	assert.Equal("reviews-v1", timeline.Changes[2].ObjectName)
+	assert.Equal("revision 2 with images [reviews:v2]", timeline.Changes[2].Message)
+
+	assert.Equal(models.ChangeKindScaling, timeline.Changes[3].Kind)
+	assert.Equal("deployment-controller", timeline.Changes[3].Author)
+
+	assert.Equal(models.ChangeKindAudit, timeline.Changes[4].Kind)
+	assert.Equal("alice", timeline.Changes[4].Author)
+
+	timeline, err = service.GetChangeTimeline("bookinfo", "", at, at.Add(time.Hour))
+	require.NoError(err)
+	assert.Len(timeline.Changes, 10)
+
+	_, err = service.GetChangeTimeline("bookinfo", "", at, at)
+	assert.Error(err)
+}
@@ -0,0 +1,137 @@
+package business
+
+import (
+	"fmt"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Versions of the Gateway API, from the most recent
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+const (
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+	// Only the experimental channel of the Gateway API serves these kinds
+	experimentalGatewayAPIVersion = gatewayAPIGroup + "/v1alpha2"
+)
+
+// Versions of the Gateway API Inference Extension, from the most recent
+var inferenceAPIVersions = []string{"inference.networking.k8s.io/v1", "inference.networking.x-k8s.io/v1alpha2"}
+
+// GetClustersCapabilities discovers the optional APIs supported by the home cluster and by every remote cluster,
+// with the credentials of the user for each remote cluster. The clusters are probed concurrently: the clusters not
+// answering in time are reported with the error only.
+func (in *ClusterService) GetClustersCapabilities(clusterTokens map[string]string, selector labels.Selector) []models.ClusterCapabilities {
+	home := config.Get().KubernetesConfig.ClusterName
+	clusters := selectClusters(selector)
+
+	values, errs := queryClusters(clusters, func(cluster string) (interface{}, error) {
+		if cluster == home {
+			return discoverCapabilities(cluster, in.k8s), nil
+		}
+		client, err := GetClusterClient(cluster, clusterTokens[cluster])
+		if err != nil {
+			return nil, err
+		}
+		return discoverCapabilities(cluster, client), nil
+	})
+	capabilities := make([]models.ClusterCapabilities, len(clusters))
+	for i, cluster := range clusters {
+		if errs[i] != nil {
+			capabilities[i] = models.ClusterCapabilities{
+				Cluster:       cluster,
+				IstioVersions: []string{},
+				Errors:        []string{errs[i].Error()},
+			}
+			continue
+		}
+		capabilities[i] = values[i].(models.ClusterCapabilities)
+	}
+	return capabilities
+}
+
+// discoverCapabilities probes the optional APIs of a cluster
+func discoverCapabilities(cluster string, client kubernetes.ClientInterface) models.ClusterCapabilities {
+	istioNamespace := config.Get().IstioNamespace
+	capabilities := models.ClusterCapabilities{
+		Cluster:       cluster,
+		IstioVersions: []string{},
+		Errors:        []string{},
+	}
+	fail := func(probe string, err error) {
+		capabilities.Errors = append(capabilities.Errors, fmt.Sprintf("%s: %v", probe, err))
+	}
+
+	for _, version := range gatewayAPIVersions {
+		served, err := servesKind(client, gatewayAPIGroup+"/"+version, "Gateway")
+		if err != nil {
+			fail(gatewayAPIGroup+"/"+version, err)
+		} else if served {
+			capabilities.GatewayAPI = true
+			capabilities.GatewayAPIVersion = version
+			break
+		}
+	}
+	for _, kind := range []string{"TCPRoute", "TLSRoute"} {
+		served, err := servesKind(client, experimentalGatewayAPIVersion, kind)
+		if err != nil {
+			fail(experimentalGatewayAPIVersion, err)
+			break
+		}
+		if served {
+			capabilities.ExperimentalGatewayAPI = true
+			break
+		}
+	}
+	for _, version := range inferenceAPIVersions {
+		served, err := servesKind(client, version, "InferencePool")
+		if err != nil {
+			fail(version, err)
+		} else if served {
+			capabilities.InferenceAPI = true
+			break
+		}
+	}
+
+	if ztunnels, err := client.GetPods(istioNamespace, ztunnelLabelSelector); err != nil {
+		fail("ztunnel", err)
+	} else {
+		capabilities.Ambient = len(ztunnels) > 0
+	}
+
+	if deployments, err := client.GetDeployments(istioNamespace); err != nil {
+		fail("istiod", err)
+	} else {
+		versions := map[string]bool{}
+		for _, revision := range controlPlaneRevisions(deployments) {
+			if revision.Version != "" && !versions[revision.Version] {
+				versions[revision.Version] = true
+				capabilities.IstioVersions = append(capabilities.IstioVersions, revision.Version)
+			}
+		}
+	}
+	return capabilities
+}
+
+// servesKind returns true if the cluster serves a kind in an API group version. A group version not served is not an
+// error.
+func servesKind(client kubernetes.ClientInterface, groupVersion, kind string) (bool, error) {
+	resources, err := client.GetServerResources(groupVersion)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
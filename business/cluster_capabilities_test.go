@@ -0,0 +1,53 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func fakeAPIResources(kinds ...string) *meta_v1.APIResourceList {
+	resources := &meta_v1.APIResourceList{}
+	for _, kind := range kinds {
+		resources.APIResources = append(resources.APIResources, meta_v1.APIResource{Kind: kind})
+	}
+	return resources
+}
+
+func TestDiscoverCapabilities(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	istiod := apps_v1.Deployment{ObjectMeta: meta_v1.ObjectMeta{Name: "istiod"}}
+	istiod.Spec.Template.Labels = map[string]string{"app": "istiod"}
+	istiod.Spec.Template.Spec.Containers = []core_v1.Container{{Name: "discovery", Image: "docker.io/istio/pilot:1.8.1"}}
+
+	notFound := kubernetes.NewNotFound("v1alpha2", "gateway.networking.k8s.io", "")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetServerResources", "gateway.networking.k8s.io/v1").Return((*meta_v1.APIResourceList)(nil), notFound)
+	k8s.On("GetServerResources", "gateway.networking.k8s.io/v1beta1").Return(fakeAPIResources("Gateway", "HTTPRoute"), nil)
+	k8s.On("GetServerResources", "gateway.networking.k8s.io/v1alpha2").Return(fakeAPIResources("ReferenceGrant"), nil)
+	k8s.On("GetServerResources", "inference.networking.k8s.io/v1").Return((*meta_v1.APIResourceList)(nil), notFound)
+	k8s.On("GetServerResources", "inference.networking.x-k8s.io/v1alpha2").Return((*meta_v1.APIResourceList)(nil), errors.New("connection refused"))
+	k8s.On("GetPods", "istio-system", "app=ztunnel").Return([]core_v1.Pod{{}}, nil)
+	k8s.On("GetDeployments", "istio-system").Return([]apps_v1.Deployment{istiod}, nil)
+
+	capabilities := discoverCapabilities("east", k8s)
+	assert.Equal("east", capabilities.Cluster)
+	assert.True(capabilities.GatewayAPI)
+	assert.Equal("v1beta1", capabilities.GatewayAPIVersion)
+	assert.False(capabilities.ExperimentalGatewayAPI)
+	assert.False(capabilities.InferenceAPI)
+	assert.True(capabilities.Ambient)
+	assert.Equal([]string{"1.8.1"}, capabilities.IstioVersions)
+	assert.Len(capabilities.Errors, 1)
+	assert.Contains(capabilities.Errors[0], "inference.networking.x-k8s.io/v1alpha2")
+}
@@ -0,0 +1,328 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const clusterRegistrySecretKey = "clusters"
+
+// ClusterService deals with the remote clusters Kiali connects to
+type ClusterService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// Serializes the updates of the registered clusters by this replica
+var clusterRegistryLock sync.Mutex
+
+// Stops the sync of the registered clusters
+var stopClusterRegistrySync context.CancelFunc
+
+// GetClusters returns the remote clusters, configured or registered through the API, whose labels match the selector
+func (in *ClusterService) GetClusters(selector labels.Selector) []models.RemoteCluster {
+	clusters := []models.RemoteCluster{}
+	for _, cluster := range config.Get().Clustering.Clusters {
+		if !selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		remote := models.RemoteCluster{
+			Name:         cluster.Name,
+			AuthStrategy: cluster.Auth.Strategy,
+			Labels:       cluster.Labels,
+			Registered:   cluster.Registered,
+		}
+		if ref := cluster.SecretRef; ref != nil {
+			remote.SecretRef = &models.ClusterSecretRef{Namespace: ref.Namespace, Name: ref.Name, Key: ref.Key}
+		}
+		clusters = append(clusters, remote)
+	}
+	return clusters
+}
+
+// RegisterCluster registers a remote cluster reached with the remote secret (kubeconfig) of a Secret. The cluster is
+// used by all the replicas of Kiali, without a restart. The user must be an administrator of Kiali. The Secret must be
+// in the namespace of Kiali: it is read with the Kiali service account, which must not disclose the Secrets of the
+// other namespaces.
+func (in *ClusterService) RegisterCluster(request models.ClusterRegistration) (*models.RemoteCluster, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ClusterService", "RegisterCluster")
+	defer promtimer.ObserveNow(&err)
+
+	if err = checkKialiAdmin(in.k8s, "registering a cluster"); err != nil {
+		return nil, err
+	}
+
+	conf := config.Get()
+	switch {
+	case request.Name == "":
+		err = k8s_errors.NewBadRequest("the name of the cluster is required")
+	case request.Name == conf.KubernetesConfig.ClusterName:
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("the cluster [%s] is the home cluster", request.Name))
+	case conf.Clustering.GetCluster(request.Name) != nil:
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("the cluster [%s] is already configured", request.Name))
+	case request.SecretRef.Namespace == "" || request.SecretRef.Name == "":
+		err = k8s_errors.NewBadRequest("the namespace and the name of the Secret holding the remote secret are required")
+	case request.SecretRef.Namespace != conf.Deployment.Namespace:
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("the Secret holding the remote secret must be in the namespace of Kiali [%s]", conf.Deployment.Namespace))
+	}
+	for key, value := range request.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			err = k8s_errors.NewBadRequest(fmt.Sprintf("invalid label [%s]: %s", key, strings.Join(errs, ", ")))
+		} else if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			err = k8s_errors.NewBadRequest(fmt.Sprintf("invalid value of label [%s]: %s", key, strings.Join(errs, ", ")))
+		}
+	}
+	switch request.AuthStrategy {
+	case config.ClusterAuthStrategyToken, config.ClusterAuthStrategyServiceAccount:
+	case config.ClusterAuthStrategyOpenIdExchange:
+		if conf.Auth.Strategy != config.AuthStrategyOpenId {
+			err = k8s_errors.NewBadRequest(fmt.Sprintf("the %s strategy requires the %s authentication strategy", request.AuthStrategy, config.AuthStrategyOpenId))
+		}
+	default:
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("unknown strategy [%s], expected %s, %s or %s", request.AuthStrategy, config.ClusterAuthStrategyToken, config.ClusterAuthStrategyOpenIdExchange, config.ClusterAuthStrategyServiceAccount))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The remote secret is read by Kiali, with its service account, each time it connects to the cluster
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := k8s.GetSecret(request.SecretRef.Namespace, request.SecretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	remoteSecret, err := kubernetes.GetRemoteSecretFromSecret(secret, request.SecretRef.Key)
+	if err != nil {
+		err = k8s_errors.NewBadRequest(err.Error())
+		return nil, err
+	}
+	if request.AuthStrategy == config.ClusterAuthStrategyServiceAccount && (len(remoteSecret.Users) == 0 || remoteSecret.Users[0].User.Token == "") {
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("the %s strategy requires the token of a service account in the remote secret", request.AuthStrategy))
+		return nil, err
+	}
+
+	cluster := config.RemoteCluster{
+		Name:   request.Name,
+		Auth:   config.RemoteClusterAuth{Strategy: request.AuthStrategy},
+		Labels: request.Labels,
+		SecretRef: &config.RemoteClusterSecretRef{
+			Namespace: request.SecretRef.Namespace,
+			Name:      request.SecretRef.Name,
+			Key:       request.SecretRef.Key,
+		},
+	}
+	err = updateRegisteredClusters(k8s, func(clusters []config.RemoteCluster) ([]config.RemoteCluster, error) {
+		for _, registered := range clusters {
+			if registered.Name == cluster.Name {
+				return nil, k8s_errors.NewBadRequest(fmt.Sprintf("the cluster [%s] is already registered", cluster.Name))
+			}
+		}
+		return append(clusters, cluster), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.RemoteCluster{
+		Name:         cluster.Name,
+		AuthStrategy: cluster.Auth.Strategy,
+		Labels:       cluster.Labels,
+		Registered:   true,
+		SecretRef:    &request.SecretRef,
+	}, nil
+}
+
+// DeregisterCluster deregisters a remote cluster registered through the API. The user must be an administrator of
+// Kiali.
+func (in *ClusterService) DeregisterCluster(name string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ClusterService", "DeregisterCluster")
+	defer promtimer.ObserveNow(&err)
+
+	if err = checkKialiAdmin(in.k8s, "deregistering a cluster"); err != nil {
+		return err
+	}
+	if cluster := config.Get().Clustering.GetCluster(name); cluster != nil && !cluster.Registered {
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("the cluster [%s] is in the configuration of Kiali, it is not registered", name))
+		return err
+	}
+
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return err
+	}
+	err = updateRegisteredClusters(k8s, func(clusters []config.RemoteCluster) ([]config.RemoteCluster, error) {
+		for i, registered := range clusters {
+			if registered.Name == name {
+				return append(clusters[:i], clusters[i+1:]...), nil
+			}
+		}
+		return nil, kubernetes.NewNotFound(name, "kiali.io", "clusters")
+	})
+	return err
+}
+
+// SyncRegisteredClusters reads the registered clusters, to use the ones registered by the other replicas of Kiali
+func SyncRegisteredClusters() error {
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return err
+	}
+	clusterRegistryLock.Lock()
+	defer clusterRegistryLock.Unlock()
+	_, clusters, err := readRegisteredClusters(k8s)
+	if err != nil {
+		return err
+	}
+	applyRegisteredClusters(clusters)
+	return nil
+}
+
+// StartClusterRegistrySync syncs the registered clusters now and then periodically, until StopClusterRegistrySync
+func StartClusterRegistrySync() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopClusterRegistrySync = cancel
+	period := time.Duration(config.Get().Clustering.RegistrySyncPeriod) * time.Second
+	go func() {
+		for {
+			if err := SyncRegisteredClusters(); err != nil {
+				log.Warningf("Cannot read the registered clusters: %v", err)
+			}
+			if period <= 0 {
+				return
+			}
+			select {
+			case <-time.After(period):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// StopClusterRegistrySync stops the periodic sync of the registered clusters
+func StopClusterRegistrySync() {
+	if stopClusterRegistrySync != nil {
+		stopClusterRegistrySync()
+		stopClusterRegistrySync = nil
+	}
+}
+
+// updateRegisteredClusters applies the given change to the registered clusters, persists and uses the result
+func updateRegisteredClusters(k8s kubernetes.ClientInterface, change func([]config.RemoteCluster) ([]config.RemoteCluster, error)) error {
+	clusterRegistryLock.Lock()
+	defer clusterRegistryLock.Unlock()
+
+	secret, clusters, err := readRegisteredClusters(k8s)
+	if err != nil {
+		return err
+	}
+	if clusters, err = change(clusters); err != nil {
+		return err
+	}
+	rawClusters, err := yaml.Marshal(clusters)
+	if err != nil {
+		return err
+	}
+
+	conf := config.Get()
+	if secret == nil {
+		secret = &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      conf.Clustering.RegistrySecretName,
+				Namespace: conf.Deployment.Namespace,
+				Labels:    map[string]string{"app": "kiali"},
+			},
+			Data: map[string][]byte{clusterRegistrySecretKey: rawClusters},
+		}
+		_, err = k8s.CreateSecret(conf.Deployment.Namespace, secret)
+	} else {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[clusterRegistrySecretKey] = rawClusters
+		_, err = k8s.UpdateSecret(conf.Deployment.Namespace, secret)
+	}
+	if err != nil {
+		return err
+	}
+	applyRegisteredClusters(clusters)
+	return nil
+}
+
+// readRegisteredClusters reads the Secret of the registered clusters. A nil Secret is returned if it doesn't exist yet.
+func readRegisteredClusters(k8s kubernetes.ClientInterface) (*core_v1.Secret, []config.RemoteCluster, error) {
+	conf := config.Get()
+	secret, err := k8s.GetSecret(conf.Deployment.Namespace, conf.Clustering.RegistrySecretName)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil, []config.RemoteCluster{}, nil
+		}
+		return nil, nil, err
+	}
+
+	clusters := []config.RemoteCluster{}
+	if rawClusters, ok := secret.Data[clusterRegistrySecretKey]; ok {
+		if err := yaml.Unmarshal(rawClusters, &clusters); err != nil {
+			return nil, nil, fmt.Errorf("cannot parse the registered clusters in Secret [%s]: %v", conf.Clustering.RegistrySecretName, err)
+		}
+	}
+	return secret, clusters, nil
+}
+
+// applyRegisteredClusters replaces the registered clusters in the configuration, keeping the configured ones, and
+// purges the clients of the clusters which are deregistered or changed
+func applyRegisteredClusters(registered []config.RemoteCluster) {
+	conf := config.Get()
+	previous := map[string]config.RemoteCluster{}
+	clusters := []config.RemoteCluster{}
+	for _, cluster := range conf.Clustering.Clusters {
+		if cluster.Registered {
+			previous[cluster.Name] = cluster
+		} else {
+			clusters = append(clusters, cluster)
+		}
+	}
+	current := map[string]bool{}
+	for _, cluster := range registered {
+		if conf.Clustering.GetCluster(cluster.Name) != nil && previous[cluster.Name].Name == "" {
+			log.Warningf("Registered cluster [%s] is ignored, it is in the configuration of Kiali", cluster.Name)
+			continue
+		}
+		cluster.Registered = true
+		clusters = append(clusters, cluster)
+		current[cluster.Name] = true
+		if old, ok := previous[cluster.Name]; ok && !reflect.DeepEqual(old, cluster) {
+			kubernetes.PurgeClusterClients(cluster.Name)
+		}
+	}
+	for name := range previous {
+		if !current[name] {
+			kubernetes.PurgeClusterClients(name)
+		}
+	}
+	conf.Clustering.Clusters = clusters
+	config.Set(conf)
+}
@@ -0,0 +1,156 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func setupClusterRegistryMock(admin bool) (*kubetest.K8SClientMock, *core_v1.Secret) {
+	conf := config.NewConfig()
+	conf.Deployment.Namespace = "kiali"
+	conf.Clustering.Clusters = []config.RemoteCluster{
+		{Name: "west", SecretFile: "/kiali-remote-secrets/west", Auth: config.RemoteClusterAuth{Strategy: config.ClusterAuthStrategyToken}},
+	}
+	config.Set(conf)
+	kubernetes.KialiToken = "kiali-sa-token"
+
+	stored := &core_v1.Secret{}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetSelfSubjectAccessReview", "kiali", "apps", "deployments", []string{"update"}).Return(fakeAccessReview(admin), nil)
+	k8s.On("GetSecret", "kiali", "istio-remote-secret-east").Return(&core_v1.Secret{
+		Data: map[string][]byte{"east": []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://east.example.com:6443
+  name: east
+users:
+- name: kiali
+  user:
+    token: sa-token
+`)},
+	}, nil)
+	k8s.On("GetSecret", "kiali", "kiali-remote-clusters").Return((*core_v1.Secret)(nil), kubernetes.NewNotFound("kiali-remote-clusters", "core", "secrets")).Once()
+	k8s.On("GetSecret", "kiali", "kiali-remote-clusters").Return(stored, nil)
+	k8s.On("CreateSecret", "kiali", mock.AnythingOfType("*v1.Secret")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.Secret)
+	}).Return(stored, nil)
+	k8s.On("UpdateSecret", "kiali", mock.AnythingOfType("*v1.Secret")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.Secret)
+	}).Return(stored, nil)
+
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+	return k8s, stored
+}
+
+func TestClusterRegistration(t *testing.T) {
+	assert := assert.New(t)
+	k8s, stored := setupClusterRegistryMock(true)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	request := models.ClusterRegistration{
+		Name:         "east",
+		AuthStrategy: config.ClusterAuthStrategyServiceAccount,
+		Labels:       map[string]string{"region": "eu-west"},
+		SecretRef:    models.ClusterSecretRef{Namespace: "kiali", Name: "istio-remote-secret-east"},
+	}
+	cluster, err := layer.Cluster.RegisterCluster(request)
+	assert.NoError(err)
+	assert.True(cluster.Registered)
+	assert.Contains(string(stored.Data[clusterRegistrySecretKey]), "istio-remote-secret-east")
+
+	clusters := layer.Cluster.GetClusters(labels.Everything())
+	assert.Len(clusters, 2)
+	assert.Equal("west", clusters[0].Name)
+	assert.False(clusters[0].Registered)
+	assert.Equal("east", clusters[1].Name)
+	assert.True(clusters[1].Registered)
+	assert.Equal("eu-west", clusters[1].Labels["region"])
+
+	clusters = layer.Cluster.GetClusters(labels.SelectorFromSet(labels.Set{"region": "eu-west"}))
+	assert.Len(clusters, 1)
+	assert.Equal("east", clusters[0].Name)
+	assert.Equal("istio-remote-secret-east", config.Get().Clustering.GetCluster("east").SecretRef.Name)
+
+	// A cluster is registered once
+	_, err = layer.Cluster.RegisterCluster(request)
+	assert.True(errors.IsBadRequest(err))
+
+	// The configured clusters cannot be deregistered
+	assert.True(errors.IsBadRequest(layer.Cluster.DeregisterCluster("west")))
+
+	assert.NoError(layer.Cluster.DeregisterCluster("east"))
+	assert.Nil(config.Get().Clustering.GetCluster("east"))
+	assert.NotNil(config.Get().Clustering.GetCluster("west"))
+	assert.True(errors.IsNotFound(layer.Cluster.DeregisterCluster("east")))
+}
+
+func TestClusterRegistrationRejected(t *testing.T) {
+	assert := assert.New(t)
+	k8s, _ := setupClusterRegistryMock(true)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	secretRef := models.ClusterSecretRef{Namespace: "kiali", Name: "istio-remote-secret-east"}
+	for _, request := range []models.ClusterRegistration{
+		{Name: "", AuthStrategy: config.ClusterAuthStrategyToken, SecretRef: secretRef},
+		{Name: "west", AuthStrategy: config.ClusterAuthStrategyToken, SecretRef: secretRef},
+		{Name: "east", AuthStrategy: "password", SecretRef: secretRef},
+		{Name: "east", AuthStrategy: config.ClusterAuthStrategyOpenIdExchange, SecretRef: secretRef},
+		{Name: "east", AuthStrategy: config.ClusterAuthStrategyToken},
+		// The Secrets of the other namespaces are not disclosed
+		{Name: "east", AuthStrategy: config.ClusterAuthStrategyToken, SecretRef: models.ClusterSecretRef{Namespace: "istio-system", Name: "istio-remote-secret-east"}},
+		{Name: "east", AuthStrategy: config.ClusterAuthStrategyToken, SecretRef: models.ClusterSecretRef{Namespace: "kiali", Name: "istio-remote-secret-east", Key: "west"}},
+	} {
+		_, err := layer.Cluster.RegisterCluster(request)
+		assert.True(errors.IsBadRequest(err), "registration of %v", request)
+	}
+	k8s.AssertNotCalled(t, "CreateSecret", "kiali", mock.Anything)
+
+	// Only the administrators of Kiali register clusters
+	k8s, _ = setupClusterRegistryMock(false)
+	layer = NewWithBackends(k8s, nil, nil)
+	_, err := layer.Cluster.RegisterCluster(models.ClusterRegistration{Name: "east", AuthStrategy: config.ClusterAuthStrategyToken, SecretRef: secretRef})
+	assert.True(errors.IsForbidden(err))
+}
+
+func TestSyncRegisteredClusters(t *testing.T) {
+	assert := assert.New(t)
+	_, stored := setupClusterRegistryMock(true)
+	assert.NoError(SyncRegisteredClusters())
+	assert.Len(config.Get().Clustering.Clusters, 1)
+
+	// A cluster registered by another replica
+	stored.Data = map[string][]byte{clusterRegistrySecretKey: []byte(`
+- name: east
+  auth:
+    strategy: token
+  secret_ref:
+    namespace: kiali
+    name: istio-remote-secret-east
+- name: west
+  auth:
+    strategy: token
+  secret_ref:
+    namespace: kiali
+    name: istio-remote-secret-west
+`)}
+	assert.NoError(SyncRegisteredClusters())
+	east := config.Get().Clustering.GetCluster("east")
+	assert.NotNil(east)
+	assert.True(east.Registered)
+	// The configured clusters take precedence
+	assert.Equal("/kiali-remote-secrets/west", config.Get().Clustering.GetCluster("west").SecretFile)
+	assert.Len(config.Get().Clustering.Clusters, 2)
+}
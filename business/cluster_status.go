@@ -0,0 +1,213 @@
+package business
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// Time given to a telemetry backend to answer a check of the clusters scoreboard
+const clusterCheckTimeout = 5 * time.Second
+
+// Last time each check of each cluster was ok, by cluster and check name
+var (
+	clusterChecksLastSuccess     = map[string]time.Time{}
+	clusterChecksLastSuccessLock sync.Mutex
+)
+
+// GetClustersStatus checks the connections of Kiali to the home cluster and to every remote cluster, with the
+// credentials of the user for each remote cluster, and to the telemetry backends of the clusters. The clusters are
+// checked concurrently: all the checks of a cluster not answering in time fail.
+func (in *ClusterService) GetClustersStatus(clusterTokens map[string]string, selector labels.Selector) []models.ClusterStatus {
+	home := config.Get().KubernetesConfig.ClusterName
+	clusters := selectClusters(selector)
+
+	values, errs := queryClusters(clusters, func(cluster string) (interface{}, error) {
+		if cluster == home {
+			return in.getClusterStatus(cluster, in.k8s, nil), nil
+		}
+		client, err := GetClusterClient(cluster, clusterTokens[cluster])
+		return in.getClusterStatus(cluster, client, err), nil
+	})
+	statuses := make([]models.ClusterStatus, len(clusters))
+	for i, cluster := range clusters {
+		if errs[i] == nil {
+			statuses[i] = values[i].(models.ClusterStatus)
+			continue
+		}
+		failed := func() error { return errs[i] }
+		statuses[i] = models.ClusterStatus{
+			Cluster:    cluster,
+			Home:       cluster == home,
+			API:        runClusterCheck(cluster, "api", failed),
+			IstioAPI:   runClusterCheck(cluster, "istioApi", failed),
+			Informers:  models.ClusterInformersStatus{Warming: []string{}},
+			Prometheus: runClusterCheck(cluster, "prometheus", failed),
+			Tracing:    runClusterCheck(cluster, "tracing", failed),
+		}
+	}
+	return statuses
+}
+
+// getClusterStatus runs the checks of a cluster. The checks of the APIs fail with the error of the client, if any.
+func (in *ClusterService) getClusterStatus(cluster string, client kubernetes.ClientInterface, clientErr error) models.ClusterStatus {
+	conf := config.Get()
+	status := models.ClusterStatus{
+		Cluster:   cluster,
+		Home:      cluster == conf.KubernetesConfig.ClusterName,
+		Informers: models.ClusterInformersStatus{Warming: []string{}},
+	}
+
+	status.API = runClusterCheck(cluster, "api", func() error {
+		if clientErr != nil {
+			return clientErr
+		}
+		_, err := client.GetServerVersion()
+		return err
+	})
+	status.IstioAPI = runClusterCheck(cluster, "istioApi", func() error {
+		if clientErr != nil {
+			return clientErr
+		}
+		_, err := client.GetIstioObjects(conf.IstioNamespace, kubernetes.VirtualServices, "")
+		return err
+	})
+
+	if status.Home && kialiCache != nil {
+		status.Informers = summarizeInformers(kialiCache.GetStatus())
+	}
+
+	if url, auth := prometheusOfCluster(conf, cluster); url != "" {
+		status.Prometheus = runClusterCheck(cluster, "prometheus", func() error {
+			return checkTelemetryBackend(url, auth)
+		})
+	} else {
+		status.Prometheus = models.ClusterCheck{Status: models.ClusterCheckDisabled}
+	}
+	if url, auth := tracingOfCluster(conf, cluster); url != "" {
+		status.Tracing = runClusterCheck(cluster, "tracing", func() error {
+			return checkTelemetryBackend(url, auth)
+		})
+	} else {
+		status.Tracing = models.ClusterCheck{Status: models.ClusterCheckDisabled}
+	}
+
+	status.Healthy = status.Informers.Synced == status.Informers.Total
+	for _, check := range []models.ClusterCheck{status.API, status.IstioAPI, status.Prometheus, status.Tracing} {
+		if check.Status == models.ClusterCheckFailing {
+			status.Healthy = false
+		}
+	}
+	return status
+}
+
+// runClusterCheck runs a check of a cluster, and remembers when it is ok
+func runClusterCheck(cluster, name string, check func() error) models.ClusterCheck {
+	start := time.Now()
+	err := check()
+	result := models.ClusterCheck{
+		Status:    models.ClusterCheckOk,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	key := cluster + "/" + name
+	clusterChecksLastSuccessLock.Lock()
+	defer clusterChecksLastSuccessLock.Unlock()
+	if err != nil {
+		result.Status = models.ClusterCheckFailing
+		result.Message = err.Error()
+	} else {
+		clusterChecksLastSuccess[key] = util.Clock.Now()
+	}
+	if lastSuccess, ok := clusterChecksLastSuccess[key]; ok {
+		result.LastSuccess = &lastSuccess
+	}
+	return result
+}
+
+// summarizeInformers counts the synced informers of the Kiali cache, and how long the oldest one is syncing
+func summarizeInformers(cached models.ClusterCacheStatus) models.ClusterInformersStatus {
+	summary := models.ClusterInformersStatus{
+		Cached:  cached.Cached,
+		Total:   len(cached.Informers),
+		Warming: cached.Warming,
+	}
+	now := util.Clock.Now()
+	for _, informer := range cached.Informers {
+		if informer.Synced {
+			summary.Synced++
+		} else if lag := int64(now.Sub(informer.Since).Seconds()); !informer.Since.IsZero() && lag > summary.SyncLagSeconds {
+			summary.SyncLagSeconds = lag
+		}
+	}
+	if summary.Warming == nil {
+		summary.Warming = []string{}
+	}
+	return summary
+}
+
+// prometheusOfCluster returns the endpoint and credentials of the Prometheus holding the metrics of a cluster
+func prometheusOfCluster(conf *config.Config, cluster string) (string, config.Auth) {
+	prom := conf.ExternalServices.Prometheus
+	if clusterConfig, ok := prom.Clusters[cluster]; ok && clusterConfig.URL != "" {
+		return clusterConfig.URL, clusterConfig.Auth
+	}
+	return prom.URL, prom.Auth
+}
+
+// tracingOfCluster returns the endpoint and credentials of the tracing backend holding the traces of a cluster, or
+// an empty endpoint when tracing is disabled
+func tracingOfCluster(conf *config.Config, cluster string) (string, config.Auth) {
+	tracing := conf.ExternalServices.Tracing
+	if !tracing.Enabled {
+		return "", tracing.Auth
+	}
+	if clusterConfig, ok := tracing.Clusters[cluster]; ok && cluster != conf.KubernetesConfig.ClusterName {
+		// Backends of remote clusters are usually reached through their external URL
+		url := clusterConfig.InClusterURL
+		if url == "" {
+			url = clusterConfig.URL
+		}
+		if url != "" {
+			auth := clusterConfig.Auth
+			if auth.Type == "" {
+				auth = tracing.Auth
+			}
+			return url, auth
+		}
+	}
+	if conf.InCluster {
+		return tracing.InClusterURL, tracing.Auth
+	}
+	return tracing.URL, tracing.Auth
+}
+
+// checkTelemetryBackend fails when a telemetry backend cannot be reached, rejects the credentials or fails to answer
+func checkTelemetryBackend(url string, auth config.Auth) error {
+	if auth.UseKialiToken {
+		token, err := kubernetes.GetKialiToken()
+		if err != nil {
+			return fmt.Errorf("the token of Kiali cannot be read: %v", err)
+		}
+		auth.Token = token
+	}
+	_, code, err := httputil.HttpGet(url, &auth, clusterCheckTimeout)
+	switch {
+	case err != nil:
+		return err
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return fmt.Errorf("the credentials are rejected with status %d", code)
+	case code >= http.StatusInternalServerError:
+		return fmt.Errorf("answered with status %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package business
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGetClustersStatus(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer prometheus.Close()
+	eastPrometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer eastPrometheus.Close()
+
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.URL = prometheus.URL
+	conf.ExternalServices.Prometheus.Clusters = map[string]config.PrometheusClusterConfig{"east": {URL: eastPrometheus.URL}}
+	conf.ExternalServices.Tracing.Enabled = false
+	conf.Clustering.Clusters = []config.RemoteCluster{
+		{Name: "east", SecretFile: "/kiali-remote-secrets/east", Auth: config.RemoteClusterAuth{Strategy: config.ClusterAuthStrategyToken}},
+	}
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetServerVersion").Return(&version.Info{}, nil)
+	k8s.On("GetIstioObjects", "istio-system", kubernetes.VirtualServices, "").Return([]kubernetes.IstioObject{}, nil)
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	statuses := layer.Cluster.GetClustersStatus(map[string]string{"east": "east-token"}, labels.Everything())
+	assert.Len(statuses, 2)
+
+	home := statuses[0]
+	assert.Equal(conf.KubernetesConfig.ClusterName, home.Cluster)
+	assert.True(home.Home)
+	assert.True(home.Healthy)
+	assert.Equal(models.ClusterCheckOk, home.API.Status)
+	assert.Equal(util.Clock.Now(), *home.API.LastSuccess)
+	assert.Equal(models.ClusterCheckOk, home.IstioAPI.Status)
+	assert.Equal(models.ClusterCheckOk, home.Prometheus.Status)
+	assert.Equal(models.ClusterCheckDisabled, home.Tracing.Status)
+
+	east := statuses[1]
+	assert.Equal("east", east.Cluster)
+	assert.False(east.Home)
+	assert.False(east.Healthy)
+	assert.Equal(models.ClusterCheckOk, east.API.Status)
+	assert.Equal(models.ClusterCheckFailing, east.Prometheus.Status)
+	assert.Contains(east.Prometheus.Message, "401")
+	assert.Nil(east.Prometheus.LastSuccess)
+}
+
+func TestSummarizeInformers(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+
+	summary := summarizeInformers(models.ClusterCacheStatus{
+		Cached: true,
+		Informers: []models.InformerStatus{
+			{Namespace: "bookinfo", Kind: "Pod", Synced: true, Since: now.Add(-time.Hour)},
+			{Namespace: "travels", Kind: "Pod", Synced: false, Since: now.Add(-30 * time.Second)},
+			{Namespace: "travels", Kind: "Service", Synced: false, Since: now.Add(-10 * time.Second)},
+		},
+	})
+	assert.True(summary.Cached)
+	assert.Equal(3, summary.Total)
+	assert.Equal(1, summary.Synced)
+	assert.Equal(int64(30), summary.SyncLagSeconds)
+	assert.Empty(summary.Warming)
+	assert.NotNil(summary.Warming)
+}
+
+func TestRunClusterCheckRemembersLastSuccess(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	check := runClusterCheck("west", "api", func() error { return nil })
+	assert.Equal(models.ClusterCheckOk, check.Status)
+
+	util.Clock = util.ClockMock{Time: time.Date(2020, 3, 1, 0, 5, 0, 0, time.UTC)}
+	check = runClusterCheck("west", "api", func() error { return errors.New("connection refused") })
+	assert.Equal(models.ClusterCheckFailing, check.Status)
+	assert.Equal("connection refused", check.Message)
+	assert.Equal(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), *check.LastSuccess)
+}
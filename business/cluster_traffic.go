@@ -0,0 +1,112 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultClusterTrafficWindow = "1h"
+	// Cluster of the traffic whose telemetry has no cluster label
+	unknownCluster = "unknown"
+)
+
+// GetClusterTrafficMatrix returns the rates and error rates of the requests between the clusters, sent by the
+// workloads of the namespaces accessible to the user, over the given window (1h by default). Unless the selector is
+// empty, the matrix is restricted to the clusters whose labels match it.
+func (in *ClusterService) GetClusterTrafficMatrix(window string, selector labels.Selector) (*models.ClusterTrafficMatrix, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ClusterService", "GetClusterTrafficMatrix")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultClusterTrafficWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 1h", window))
+		return nil, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+
+	requests, failures, err := in.prom.GetClusterTraffic(window, util.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	var selected map[string]bool
+	if !selector.Empty() {
+		selected = map[string]bool{}
+		for _, cluster := range selectClusters(selector) {
+			selected[cluster] = true
+		}
+	}
+	return clusterTrafficMatrix(window, requests, failures, accessible, selected), nil
+}
+
+// clusterTrafficMatrix sums the rates of the requests, and of the failed requests, sent from the accessible namespaces
+// by source and destination cluster. When the selected clusters are given, the traffic of the other clusters is ignored.
+func clusterTrafficMatrix(window string, requests, failures pmod.Vector, accessible, selected map[string]bool) *models.ClusterTrafficMatrix {
+	type route struct{ source, destination string }
+	sum := func(vector pmod.Vector) map[route]float64 {
+		rates := map[route]float64{}
+		for _, sample := range vector {
+			if !accessible[string(sample.Metric["source_workload_namespace"])] {
+				continue
+			}
+			r := route{clusterLabel(sample.Metric["source_cluster"]), clusterLabel(sample.Metric["destination_cluster"])}
+			if selected != nil && (!selected[r.source] || !selected[r.destination]) {
+				continue
+			}
+			rates[r] += float64(sample.Value)
+		}
+		return rates
+	}
+	rates, failed := sum(requests), sum(failures)
+
+	clusterSet := map[string]bool{}
+	for r := range rates {
+		clusterSet[r.source] = true
+		clusterSet[r.destination] = true
+	}
+	matrix := &models.ClusterTrafficMatrix{Window: window, Clusters: []string{}, Rates: [][]float64{}, ErrorRates: [][]float64{}}
+	for cluster := range clusterSet {
+		matrix.Clusters = append(matrix.Clusters, cluster)
+	}
+	sort.Strings(matrix.Clusters)
+
+	for _, source := range matrix.Clusters {
+		rateRow := make([]float64, len(matrix.Clusters))
+		errorRow := make([]float64, len(matrix.Clusters))
+		for j, destination := range matrix.Clusters {
+			r := route{source, destination}
+			rateRow[j] = rates[r]
+			if rates[r] > 0 {
+				errorRow[j] = failed[r] / rates[r]
+			}
+		}
+		matrix.Rates = append(matrix.Rates, rateRow)
+		matrix.ErrorRates = append(matrix.ErrorRates, errorRow)
+	}
+	return matrix
+}
+
+func clusterLabel(value pmod.LabelValue) string {
+	if value == "" {
+		return unknownCluster
+	}
+	return string(value)
+}
@@ -0,0 +1,81 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeClusterTrafficSample(source, destination, namespace string, value float64) *pmod.Sample {
+	return &pmod.Sample{
+		Metric: pmod.Metric{
+			"source_cluster":            pmod.LabelValue(source),
+			"destination_cluster":       pmod.LabelValue(destination),
+			"source_workload_namespace": pmod.LabelValue(namespace),
+		},
+		Value: pmod.SampleValue(value),
+	}
+}
+
+func TestGetClusterTrafficMatrix(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.API.Namespaces.Exclude = []string{}
+	conf.KubernetesConfig.ClusterName = "east"
+	conf.Clustering.HomeClusterLabels = map[string]string{"tier": "prod"}
+	conf.Clustering.Clusters = []config.RemoteCluster{{Name: "west", Labels: map[string]string{"tier": "dev"}}}
+	config.Set(conf)
+	kialiCache = nil
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+	}, nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetClusterTraffic", "1h", mock.AnythingOfType("time.Time")).Return(
+		pmod.Vector{
+			fakeClusterTrafficSample("east", "east", "bookinfo", 10),
+			fakeClusterTrafficSample("east", "west", "bookinfo", 4),
+			fakeClusterTrafficSample("west", "east", "bookinfo", 1),
+			fakeClusterTrafficSample("", "west", "bookinfo", 2),
+			// Not accessible
+			fakeClusterTrafficSample("east", "north", "secret", 3),
+		},
+		pmod.Vector{
+			fakeClusterTrafficSample("east", "west", "bookinfo", 1),
+			fakeClusterTrafficSample("east", "north", "secret", 3),
+		},
+		nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	matrix, err := layer.Cluster.GetClusterTrafficMatrix("", labels.Everything())
+	assert.NoError(err)
+	assert.Equal("1h", matrix.Window)
+	assert.Equal([]string{"east", "unknown", "west"}, matrix.Clusters)
+	assert.Equal([][]float64{{10, 0, 4}, {0, 0, 2}, {1, 0, 0}}, matrix.Rates)
+	assert.Equal([][]float64{{0, 0, 0.25}, {0, 0, 0}, {0, 0, 0}}, matrix.ErrorRates)
+
+	matrix, err = layer.Cluster.GetClusterTrafficMatrix("", labels.SelectorFromSet(labels.Set{"tier": "prod"}))
+	assert.NoError(err)
+	assert.Equal([]string{"east"}, matrix.Clusters)
+	assert.Equal([][]float64{{10}}, matrix.Rates)
+
+	_, err = layer.Cluster.GetClusterTrafficMatrix("an hour", labels.Everything())
+	assert.True(errors.IsBadRequest(err))
+}
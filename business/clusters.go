@@ -0,0 +1,133 @@
+package business
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+)
+
+// ClusterTokenParamPrefix is the prefix of the login form parameters holding the token of the user for
+// a remote cluster configured with the token strategy, i.e. "cluster_token_<cluster name>"
+const ClusterTokenParamPrefix = "cluster_token_"
+
+// GetClusterCredentials builds the credentials of the user for each configured remote cluster, to be stored
+// in the user session. Clusters using the token strategy take the token from the login form; clusters using
+// the openid_exchange strategy get a token by exchanging the given id_token. Clusters using the service_account
+// strategy don't need any credential from the user.
+// Remote clusters for which no credential can be obtained are skipped: the user can still log in, but won't be
+// able to see data of those clusters.
+func GetClusterCredentials(form url.Values, idToken string) map[string]string {
+	clusterTokens := make(map[string]string)
+	for _, cluster := range config.Get().Clustering.Clusters {
+		switch cluster.Auth.Strategy {
+		case config.ClusterAuthStrategyToken:
+			if token := form.Get(ClusterTokenParamPrefix + cluster.Name); token != "" {
+				clusterTokens[cluster.Name] = token
+			}
+		case config.ClusterAuthStrategyOpenIdExchange:
+			if idToken == "" {
+				log.Warningf("Cluster [%s] requires an OpenId token exchange, but the user has no OpenId token", cluster.Name)
+				continue
+			}
+			token, err := ExchangeOpenIdTokenForCluster(idToken, cluster)
+			if err != nil {
+				log.Warningf("Cannot get credentials for cluster [%s]: %v", cluster.Name, err)
+				continue
+			}
+			clusterTokens[cluster.Name] = token
+		}
+	}
+
+	if len(clusterTokens) == 0 {
+		return nil
+	}
+	return clusterTokens
+}
+
+// GetClusterClient returns a client for the given remote cluster, using the given credential of the user
+// for that cluster.
+// The business.Layer is not used for remote clusters because the Kiali Cache only holds data of the home cluster.
+func GetClusterClient(cluster string, token string) (kubernetes.ClientInterface, error) {
+	// Use an existing client factory if it exists, otherwise create and use in the future
+	if clientFactory == nil {
+		userClient, err := kubernetes.GetClientFactory()
+		if err != nil {
+			return nil, err
+		}
+		clientFactory = userClient
+	}
+
+	return clientFactory.GetClusterClient(cluster, token)
+}
+
+// selectClusters returns the home cluster, first, and the remote clusters whose labels match the selector
+func selectClusters(selector labels.Selector) []string {
+	conf := config.Get()
+	clusters := []string{}
+	if selector.Matches(labels.Set(conf.Clustering.HomeClusterLabels)) {
+		clusters = append(clusters, conf.KubernetesConfig.ClusterName)
+	}
+	for _, cluster := range conf.Clustering.Clusters {
+		if selector.Matches(labels.Set(cluster.Labels)) {
+			clusters = append(clusters, cluster.Name)
+		}
+	}
+	return clusters
+}
+
+// clusterResult is the result of a query on a cluster
+type clusterResult struct {
+	value interface{}
+	err   error
+}
+
+// queryClusters runs a query on each cluster concurrently, and returns the results and the errors of the clusters in
+// the same order. A cluster which does not answer within the request timeout of the clustering configuration fails
+// with a timeout: its query keeps running in the background but its result is dropped, so one unreachable cluster
+// doesn't fail nor block the whole request.
+func queryClusters(clusters []string, query func(cluster string) (interface{}, error)) ([]interface{}, []error) {
+	timeout := time.Duration(config.Get().Clustering.RequestTimeout) * time.Second
+	channels := make([]chan clusterResult, len(clusters))
+	for i, cluster := range clusters {
+		channels[i] = make(chan clusterResult, 1)
+		go func(ch chan<- clusterResult, cluster string) {
+			value, err := query(cluster)
+			ch <- clusterResult{value: value, err: err}
+		}(channels[i], cluster)
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	values := make([]interface{}, len(clusters))
+	errs := make([]error, len(clusters))
+	for i, cluster := range clusters {
+		// Once the deadline is passed, the results already available are still used
+		select {
+		case result := <-channels[i]:
+			values[i], errs[i] = result.value, result.err
+			continue
+		default:
+		}
+		select {
+		case result := <-channels[i]:
+			values[i], errs[i] = result.value, result.err
+		case <-deadline:
+			expired := make(chan time.Time)
+			close(expired)
+			deadline = expired
+			errs[i] = fmt.Errorf("cluster [%s] did not answer within %v", cluster, timeout)
+			log.Warningf("Skipping the data of cluster [%s]: %v", cluster, errs[i])
+		}
+	}
+	return values, errs
+}
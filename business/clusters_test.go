@@ -0,0 +1,53 @@
+package business
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestQueryClustersReturnsPartialResults(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Clustering.RequestTimeout = 1
+	config.Set(conf)
+
+	unreachable := make(chan struct{})
+	defer close(unreachable)
+	values, errs := queryClusters([]string{"home", "east", "west", "north"}, func(cluster string) (interface{}, error) {
+		switch cluster {
+		case "east":
+			<-unreachable
+		case "west":
+			return nil, errors.New("connection refused")
+		}
+		return cluster + " data", nil
+	})
+
+	assert.Equal("home data", values[0])
+	assert.NoError(errs[0])
+	assert.Nil(values[1])
+	assert.EqualError(errs[1], "cluster [east] did not answer within 1s")
+	assert.EqualError(errs[2], "connection refused")
+	// Answered before the deadline, checked after it
+	assert.Equal("north data", values[3])
+	assert.NoError(errs[3])
+}
+
+func TestQueryClustersWithoutTimeout(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Clustering.RequestTimeout = 0
+	config.Set(conf)
+
+	values, errs := queryClusters([]string{"home"}, func(cluster string) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return cluster, nil
+	})
+	assert.Equal([]interface{}{"home"}, values)
+	assert.Equal([]error{nil}, errs)
+}
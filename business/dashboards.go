@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/monitoringdashboards"
@@ -67,6 +68,15 @@ func (in *DashboardsService) k8s() (monitoringdashboards.ClientInterface, error)
 			return nil, fmt.Errorf("cannot initialize Kubernetes Client: %v", err)
 		}
 		in.k8sClient = client
+		cfg := config.Get()
+		if selector := cfg.ExternalServices.CustomDashboards.ConfigMapSelector; selector != "" {
+			resync := time.Duration(cfg.KubernetesConfig.CacheDuration) * time.Second
+			if dashboards, err := monitoringdashboards.WatchConfigMaps(selector, cfg.Deployment.AccessibleNamespaces, resync); err == nil {
+				in.k8sClient = monitoringdashboards.WithConfigMaps(client, dashboards)
+			} else {
+				log.Errorf("Cannot watch the dashboards of the ConfigMaps: %v", err)
+			}
+		}
 	}
 	return in.k8sClient, nil
 }
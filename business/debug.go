@@ -0,0 +1,51 @@
+package business
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// The administrators of Kiali are the users allowed to update its deployment
+var kialiDeploymentResource = schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+// DebugService deals with the runtime debugging of Kiali itself
+type DebugService struct {
+	k8s kubernetes.ClientInterface
+}
+
+// CheckProfilerAccess returns a NotFound error when the profiler is disabled in the configuration, and a Forbidden
+// error when the user is not an administrator of Kiali
+func (in *DebugService) CheckProfilerAccess() error {
+	conf := config.Get()
+	if !conf.Server.Profiler.Enabled {
+		return errors.NewNotFound(schema.GroupResource{Resource: "profiler"}, "server.profiler")
+	}
+
+	return checkKialiAdmin(in.k8s, "the profiler")
+}
+
+// checkKialiAdmin returns a Forbidden error when the user is not an administrator of Kiali, required for the action.
+// When the authentication strategy doesn't carry the credentials of the user, the client is the Kiali ServiceAccount
+// and nobody is known to be an administrator.
+func checkKialiAdmin(k8s kubernetes.ClientInterface, action string) error {
+	conf := config.Get()
+	namespace := conf.Deployment.Namespace
+	if !conf.Auth.UsesUserCredentials() {
+		return errors.NewForbidden(kialiDeploymentResource, "kiali", fmt.Errorf("%s is not available with the %s strategy, which doesn't use the credentials of the user", action, conf.Auth.Strategy))
+	}
+	ssars, err := k8s.GetSelfSubjectAccessReview(namespace, kialiDeploymentResource.Group, kialiDeploymentResource.Resource, []string{"update"})
+	if err != nil {
+		return err
+	}
+	for _, ssar := range ssars {
+		if ssar.Status.Allowed {
+			return nil
+		}
+	}
+	return errors.NewForbidden(kialiDeploymentResource, "kiali", fmt.Errorf("%s requires to be allowed to update the Kiali deployment in namespace %s", action, namespace))
+}
@@ -0,0 +1,39 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestCheckProfilerAccess(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Deployment.Namespace = "kiali"
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	debug := DebugService{k8s: k8s}
+	err := debug.CheckProfilerAccess()
+	assert.True(errors.IsNotFound(err))
+	k8s.AssertNotCalled(t, "GetSelfSubjectAccessReview")
+
+	conf.Server.Profiler.Enabled = true
+	config.Set(conf)
+	k8s.On("GetSelfSubjectAccessReview", "kiali", "apps", "deployments", []string{"update"}).Return(fakeAccessReview(false), nil).Once()
+	err = debug.CheckProfilerAccess()
+	assert.True(errors.IsForbidden(err))
+
+	k8s.On("GetSelfSubjectAccessReview", "kiali", "apps", "deployments", []string{"update"}).Return(fakeAccessReview(true), nil).Once()
+	assert.NoError(debug.CheckProfilerAccess())
+
+	// The client is the Kiali ServiceAccount, whatever the user
+	conf.Auth.Strategy = config.AuthStrategyHeader
+	config.Set(conf)
+	err = debug.CheckProfilerAccess()
+	assert.True(errors.IsForbidden(err))
+}
@@ -0,0 +1,184 @@
+package business
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	// Label of the east-west gateways deployed with the samples of the multi-network installations of Istio
+	eastWestGatewayLabel = "eastwestgateway"
+	// Port of the east-west gateways forwarding the mTLS traffic between the networks, by SNI
+	eastWestGatewayPort = 15443
+	// Label of the network of a cluster, on the Istio namespace
+	istioNetworkLabel = "topology.istio.io/network"
+)
+
+// Time given to an east-west gateway to complete the handshake of a probe
+var eastWestProbeTimeout = 5 * time.Second
+
+// clusterGateways are the east-west gateways of a cluster and its network
+type clusterGateways struct {
+	network  string
+	gateways []models.EastWestGateway
+}
+
+// ProbeEastWestGateways checks the connectivity through the east-west gateways of the clusters whose labels match the
+// selector. Kiali resolves the address of each gateway and opens a TLS connection to it, with the SNI routing it to
+// istiod of the cluster of the gateway, as the proxies of another network do. A pair of clusters is reachable when
+// they share a network, or when the gateway of the destination completes the handshake. The handshakes are made from
+// Kiali: a firewall between two clusters, but not between Kiali and the clusters, is not detected.
+func (in *ClusterService) ProbeEastWestGateways(clusterTokens map[string]string, selector labels.Selector) *models.EastWestProbe {
+	home := config.Get().KubernetesConfig.ClusterName
+	clusters := selectClusters(selector)
+
+	values, errs := queryClusters(clusters, func(cluster string) (interface{}, error) {
+		client := in.k8s
+		if cluster != home {
+			var err error
+			if client, err = GetClusterClient(cluster, clusterTokens[cluster]); err != nil {
+				return nil, err
+			}
+		}
+		return probeClusterGateways(cluster, client)
+	})
+
+	probe := &models.EastWestProbe{Gateways: []models.EastWestGateway{}, Pairs: []models.EastWestPair{}, Errors: map[string]string{}}
+	byCluster := map[string]clusterGateways{}
+	for i, cluster := range clusters {
+		if errs[i] != nil {
+			probe.Errors[cluster] = errs[i].Error()
+			continue
+		}
+		gateways := values[i].(clusterGateways)
+		byCluster[cluster] = gateways
+		probe.Gateways = append(probe.Gateways, gateways.gateways...)
+	}
+
+	for _, source := range clusters {
+		for _, destination := range clusters {
+			if source == destination {
+				continue
+			}
+			pair := models.EastWestPair{Source: source, Destination: destination}
+			src, srcOk := byCluster[source]
+			dst, dstOk := byCluster[destination]
+			switch {
+			case !srcOk || !dstOk:
+				pair.Message = "the cluster cannot be read"
+			case src.network == dst.network:
+				pair.Reachable = true
+				pair.Message = "same network, the proxies connect directly"
+			case len(dst.gateways) == 0:
+				pair.Message = fmt.Sprintf("no east-west gateway in cluster [%s]", destination)
+			default:
+				pair.Message = fmt.Sprintf("no east-west gateway of cluster [%s] completes the handshake", destination)
+				for _, gateway := range dst.gateways {
+					if gateway.Reachable {
+						pair.Reachable = true
+						pair.Message = ""
+						break
+					}
+				}
+			}
+			probe.Pairs = append(probe.Pairs, pair)
+		}
+	}
+	return probe
+}
+
+// probeClusterGateways finds the east-west gateways of a cluster, in the Istio namespace, and probes them
+func probeClusterGateways(cluster string, client kubernetes.ClientInterface) (clusterGateways, error) {
+	istioNamespace := config.Get().IstioNamespace
+	result := clusterGateways{gateways: []models.EastWestGateway{}}
+
+	namespace, err := client.GetNamespace(istioNamespace)
+	if err != nil {
+		return result, err
+	}
+	result.network = namespace.Labels[istioNetworkLabel]
+
+	services, err := client.GetServices(istioNamespace, map[string]string{"istio": eastWestGatewayLabel})
+	if err != nil {
+		return result, err
+	}
+	for _, service := range services {
+		gateway := models.EastWestGateway{
+			Cluster:   cluster,
+			Namespace: service.Namespace,
+			Name:      service.Name,
+			Network:   result.network,
+			Address:   gatewayAddress(service),
+			Port:      gatewayPort(service),
+		}
+		switch {
+		case gateway.Address == "":
+			gateway.Message = "the gateway has no external address"
+		case gateway.Port == 0:
+			gateway.Message = fmt.Sprintf("the gateway does not expose the port %d", eastWestGatewayPort)
+		default:
+			start := time.Now()
+			err := handshakeEastWestGateway(net.JoinHostPort(gateway.Address, strconv.Itoa(int(gateway.Port))), istioNamespace)
+			gateway.LatencyMs = time.Since(start).Milliseconds()
+			if err != nil {
+				gateway.Message = err.Error()
+			} else {
+				gateway.Reachable = true
+			}
+		}
+		result.gateways = append(result.gateways, gateway)
+	}
+	return result, nil
+}
+
+// gatewayAddress returns the address of a gateway reachable from the other networks: the address of its load
+// balancer, or an external IP
+func gatewayAddress(service core_v1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+	}
+	if len(service.Spec.ExternalIPs) > 0 {
+		return service.Spec.ExternalIPs[0]
+	}
+	return ""
+}
+
+// gatewayPort returns the port of a gateway forwarding the traffic of the other networks, or 0 if it is not exposed
+func gatewayPort(service core_v1.Service) int32 {
+	for _, port := range service.Spec.Ports {
+		if port.Port == eastWestGatewayPort || port.Name == "tls" {
+			return port.Port
+		}
+	}
+	return 0
+}
+
+// handshakeEastWestGateway opens a TLS connection to an east-west gateway with the SNI of istiod, which the gateway
+// forwards, untouched, to istiod. The handshake is completed by istiod: the gateway, its routing and istiod are up.
+// The certificate of istiod is not verified, it is signed by the CA of the mesh.
+func handshakeEastWestGateway(address, istioNamespace string) error {
+	dialer := &net.Dialer{Timeout: eastWestProbeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+		ServerName:         fmt.Sprintf("outbound_.15012_._.istiod.%s.svc.cluster.local", istioNamespace),
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return fmt.Errorf("handshake with %s failed: %v", address, err)
+	}
+	return conn.Close()
+}
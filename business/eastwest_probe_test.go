@@ -0,0 +1,68 @@
+package business
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func fakeIstioNamespace(network string) *core_v1.Namespace {
+	return &core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system", Labels: map[string]string{istioNetworkLabel: network}}}
+}
+
+func TestProbeEastWestGateways(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gateway := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer gateway.Close()
+	host, port, _ := net.SplitHostPort(gateway.Listener.Addr().String())
+	gatewayPort, _ := strconv.Atoi(port)
+
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ClusterName = "east"
+	conf.Clustering.Clusters = []config.RemoteCluster{{Name: "west"}}
+	config.Set(conf)
+
+	reachable := core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-eastwestgateway", Namespace: "istio-system"}}
+	reachable.Spec.ExternalIPs = []string{host}
+	reachable.Spec.Ports = []core_v1.ServicePort{{Name: "tls", Port: int32(gatewayPort)}}
+	pending := core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-eastwestgateway-canary", Namespace: "istio-system"}}
+	pending.Spec.Ports = []core_v1.ServicePort{{Name: "tls", Port: eastWestGatewayPort}}
+
+	east := new(kubetest.K8SClientMock)
+	east.On("IsOpenShift").Return(false)
+	east.On("GetNamespace", "istio-system").Return(fakeIstioNamespace("network1"), nil)
+	east.On("GetServices", "istio-system", map[string]string{"istio": "eastwestgateway"}).Return([]core_v1.Service{reachable, pending}, nil)
+	west := new(kubetest.K8SClientMock)
+	west.On("GetNamespace", "istio-system").Return(fakeIstioNamespace("network2"), nil)
+	west.On("GetServices", "istio-system", map[string]string{"istio": "eastwestgateway"}).Return([]core_v1.Service{}, nil)
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(west), nil)
+	layer := NewWithBackends(east, nil, nil)
+
+	probe := layer.Cluster.ProbeEastWestGateways(map[string]string{}, labels.Everything())
+	assert.Empty(probe.Errors)
+	require.Len(probe.Gateways, 2)
+	assert.True(probe.Gateways[0].Reachable)
+	assert.Equal("network1", probe.Gateways[0].Network)
+	assert.False(probe.Gateways[1].Reachable)
+	assert.Equal("the gateway has no external address", probe.Gateways[1].Message)
+
+	require.Len(probe.Pairs, 2)
+	assert.Equal("east", probe.Pairs[0].Source)
+	assert.False(probe.Pairs[0].Reachable)
+	assert.Equal("no east-west gateway in cluster [west]", probe.Pairs[0].Message)
+	assert.Equal("west", probe.Pairs[1].Source)
+	assert.True(probe.Pairs[1].Reachable)
+}
@@ -0,0 +1,217 @@
+package business
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// AccessLog holds the fields of an Envoy access log line, in the default text format of Istio or in JSON
+type AccessLog struct {
+	StartTime               string  `json:"startTime,omitempty"`
+	Method                  string  `json:"method,omitempty"`
+	Path                    string  `json:"path,omitempty"`
+	Protocol                string  `json:"protocol,omitempty"`
+	ResponseCode            int     `json:"responseCode"`
+	ResponseFlags           string  `json:"responseFlags,omitempty"`
+	ResponseCodeDetails     string  `json:"responseCodeDetails,omitempty"`
+	UpstreamFailureReason   string  `json:"upstreamFailureReason,omitempty"`
+	BytesReceived           int64   `json:"bytesReceived"`
+	BytesSent               int64   `json:"bytesSent"`
+	Duration                float64 `json:"duration"`
+	UpstreamServiceTime     string  `json:"upstreamServiceTime,omitempty"`
+	ForwardedFor            string  `json:"forwardedFor,omitempty"`
+	UserAgent               string  `json:"userAgent,omitempty"`
+	RequestID               string  `json:"requestId,omitempty"`
+	Authority               string  `json:"authority,omitempty"`
+	UpstreamHost            string  `json:"upstreamHost,omitempty"`
+	UpstreamCluster         string  `json:"upstreamCluster,omitempty"`
+	UpstreamLocalAddress    string  `json:"upstreamLocalAddress,omitempty"`
+	DownstreamLocalAddress  string  `json:"downstreamLocalAddress,omitempty"`
+	DownstreamRemoteAddress string  `json:"downstreamRemoteAddress,omitempty"`
+	RequestedServerName     string  `json:"requestedServerName,omitempty"`
+	RouteName               string  `json:"routeName,omitempty"`
+}
+
+// Fields closing the default text format of Istio, whatever the version
+const accessLogTrailingFields = 15
+
+// parseAccessLog parses a log line of an Envoy, returning nil when it is not an access log
+func parseAccessLog(message string) *AccessLog {
+	switch {
+	case strings.HasPrefix(message, "{"):
+		return parseJSONAccessLog(message)
+	case strings.HasPrefix(message, "["):
+		return parseTextAccessLog(message)
+	}
+	return nil
+}
+
+// parseTextAccessLog parses the default text format of Istio:
+//   [%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" %RESPONSE_CODE% %RESPONSE_FLAGS%
+//   ... %BYTES_RECEIVED% %BYTES_SENT% %DURATION% %RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% "%REQ(X-FORWARDED-FOR)%"
+//   "%REQ(USER-AGENT)%" "%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%" %UPSTREAM_CLUSTER%
+//   %UPSTREAM_LOCAL_ADDRESS% %DOWNSTREAM_LOCAL_ADDRESS% %DOWNSTREAM_REMOTE_ADDRESS% %REQUESTED_SERVER_NAME% %ROUTE_NAME%
+// The fields in between changed with the versions of Istio, the response code details and the upstream failure
+// reason are read when known.
+func parseTextAccessLog(message string) *AccessLog {
+	fields := splitAccessLogFields(message)
+	if len(fields) < 4+accessLogTrailingFields || !strings.HasPrefix(fields[0], "[") {
+		return nil
+	}
+	code, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil
+	}
+
+	accessLog := &AccessLog{
+		StartTime:     strings.Trim(fields[0], "[]"),
+		ResponseCode:  code,
+		ResponseFlags: accessLogValue(fields[3]),
+	}
+	request := strings.Fields(strings.Trim(fields[1], `"`))
+	// The method, path and protocol are "- - -" for TCP connections
+	if len(request) == 3 {
+		accessLog.Method, accessLog.Path, accessLog.Protocol = accessLogValue(request[0]), accessLogValue(request[1]), accessLogValue(request[2])
+	}
+
+	middle := fields[4 : len(fields)-accessLogTrailingFields]
+	switch len(middle) {
+	case 3:
+		// Istio 1.9+: %RESPONSE_CODE_DETAILS% %CONNECTION_TERMINATION_DETAILS% "%UPSTREAM_TRANSPORT_FAILURE_REASON%"
+		accessLog.ResponseCodeDetails = accessLogValue(middle[0])
+		accessLog.UpstreamFailureReason = accessLogValue(middle[2])
+	case 2:
+		// Istio 1.5 to 1.8: "%DYNAMIC_METADATA(istio.mixer:status)%" "%UPSTREAM_TRANSPORT_FAILURE_REASON%"
+		accessLog.UpstreamFailureReason = accessLogValue(middle[1])
+	}
+
+	trailing := fields[len(fields)-accessLogTrailingFields:]
+	accessLog.BytesReceived, _ = strconv.ParseInt(trailing[0], 10, 64)
+	accessLog.BytesSent, _ = strconv.ParseInt(trailing[1], 10, 64)
+	accessLog.Duration, _ = strconv.ParseFloat(trailing[2], 64)
+	accessLog.UpstreamServiceTime = accessLogValue(trailing[3])
+	accessLog.ForwardedFor = accessLogValue(trailing[4])
+	accessLog.UserAgent = accessLogValue(trailing[5])
+	accessLog.RequestID = accessLogValue(trailing[6])
+	accessLog.Authority = accessLogValue(trailing[7])
+	accessLog.UpstreamHost = accessLogValue(trailing[8])
+	accessLog.UpstreamCluster = accessLogValue(trailing[9])
+	accessLog.UpstreamLocalAddress = accessLogValue(trailing[10])
+	accessLog.DownstreamLocalAddress = accessLogValue(trailing[11])
+	accessLog.DownstreamRemoteAddress = accessLogValue(trailing[12])
+	accessLog.RequestedServerName = accessLogValue(trailing[13])
+	accessLog.RouteName = accessLogValue(trailing[14])
+	return accessLog
+}
+
+// splitAccessLogFields splits a text access log on the spaces, except in the quoted and bracketed fields
+func splitAccessLogFields(message string) []string {
+	fields := []string{}
+	var closing byte
+	start := -1
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		switch {
+		case closing != 0:
+			if c == closing {
+				closing = 0
+			}
+		case c == ' ':
+			if start >= 0 {
+				fields = append(fields, message[start:i])
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+			if c == '"' {
+				closing = '"'
+			} else if c == '[' {
+				closing = ']'
+			}
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, message[start:])
+	}
+	return fields
+}
+
+// accessLogValue unquotes a field, Envoy writing "-" for the values not set
+func accessLogValue(field string) string {
+	value := strings.Trim(field, `"`)
+	if value == "-" {
+		return ""
+	}
+	return value
+}
+
+// parseJSONAccessLog parses the JSON format of Istio, which uses the snake case names of the fields
+func parseJSONAccessLog(message string) *AccessLog {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return nil
+	}
+	if _, ok := fields["response_code"]; !ok {
+		return nil
+	}
+
+	text := func(key string) string {
+		if value, ok := fields[key].(string); ok && value != "-" {
+			return value
+		}
+		return ""
+	}
+	number := func(key string) float64 {
+		switch value := fields[key].(type) {
+		case float64:
+			return value
+		case string:
+			n, _ := strconv.ParseFloat(value, 64)
+			return n
+		}
+		return 0
+	}
+	accessLog := &AccessLog{
+		StartTime:               text("start_time"),
+		Method:                  text("method"),
+		Path:                    text("path"),
+		Protocol:                text("protocol"),
+		ResponseCode:            int(number("response_code")),
+		ResponseFlags:           text("response_flags"),
+		ResponseCodeDetails:     text("response_code_details"),
+		UpstreamFailureReason:   text("upstream_transport_failure_reason"),
+		BytesReceived:           int64(number("bytes_received")),
+		BytesSent:               int64(number("bytes_sent")),
+		Duration:                number("duration"),
+		ForwardedFor:            text("x_forwarded_for"),
+		UserAgent:               text("user_agent"),
+		RequestID:               text("request_id"),
+		Authority:               text("authority"),
+		UpstreamHost:            text("upstream_host"),
+		UpstreamCluster:         text("upstream_cluster"),
+		UpstreamLocalAddress:    text("upstream_local_address"),
+		DownstreamLocalAddress:  text("downstream_local_address"),
+		DownstreamRemoteAddress: text("downstream_remote_address"),
+		RequestedServerName:     text("requested_server_name"),
+		RouteName:               text("route_name"),
+	}
+	if ust, ok := fields["upstream_service_time"]; ok && ust != nil {
+		accessLog.UpstreamServiceTime = strings.TrimSuffix(strconv.FormatFloat(number("upstream_service_time"), 'f', -1, 64), ".0")
+	}
+	return accessLog
+}
+
+// hasResponseFlag tells whether the access log has one of the response flags, such as UF or UO
+func (a *AccessLog) hasResponseFlag(flags []string) bool {
+	for _, flag := range strings.Split(a.ResponseFlags, ",") {
+		for _, f := range flags {
+			if flag == f {
+				return true
+			}
+		}
+	}
+	return false
+}
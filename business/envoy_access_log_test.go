@@ -0,0 +1,85 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestParseTextAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLog := parseAccessLog(`[2021-02-22T21:22:35.237Z] "GET /reviews/0 HTTP/1.1" 503 UF,URX upstream_reset_before_response_started{connection_failure} - "-" 0 91 2 - "-" "Go-http-client/1.1" "7b6b4a6d-2c2f-9a7b-9a4e-6c4f0d5a8e2b" "reviews:9080" "10.244.0.12:9080" outbound|9080||reviews.bookinfo.svc.cluster.local - 10.96.27.89:9080 10.244.0.10:41278 - default`)
+	assert.NotNil(accessLog)
+	assert.Equal("2021-02-22T21:22:35.237Z", accessLog.StartTime)
+	assert.Equal("GET", accessLog.Method)
+	assert.Equal("/reviews/0", accessLog.Path)
+	assert.Equal("HTTP/1.1", accessLog.Protocol)
+	assert.Equal(503, accessLog.ResponseCode)
+	assert.Equal("UF,URX", accessLog.ResponseFlags)
+	assert.Equal("upstream_reset_before_response_started{connection_failure}", accessLog.ResponseCodeDetails)
+	assert.Equal(int64(91), accessLog.BytesSent)
+	assert.Equal(float64(2), accessLog.Duration)
+	assert.Equal("Go-http-client/1.1", accessLog.UserAgent)
+	assert.Equal("reviews:9080", accessLog.Authority)
+	assert.Equal("10.244.0.12:9080", accessLog.UpstreamHost)
+	assert.Equal("outbound|9080||reviews.bookinfo.svc.cluster.local", accessLog.UpstreamCluster)
+	assert.Empty(accessLog.UpstreamLocalAddress)
+	assert.Equal("default", accessLog.RouteName)
+	assert.True(accessLog.hasResponseFlag([]string{"UO", "UF"}))
+	assert.False(accessLog.hasResponseFlag([]string{"UO"}))
+
+	// Istio 1.8 and older, on a TCP connection
+	accessLog = parseAccessLog(`[2021-02-22T21:22:35.237Z] "- - -" 0 UO "-" "-" 125 0 1001 - "-" "-" "-" "-" "10.244.0.15:3306" outbound|3306||mysqldb.bookinfo.svc.cluster.local 10.244.0.10:53622 10.96.33.1:3306 10.244.0.10:53620 - -`)
+	assert.NotNil(accessLog)
+	assert.Equal(0, accessLog.ResponseCode)
+	assert.Equal("UO", accessLog.ResponseFlags)
+	assert.Empty(accessLog.Method)
+	assert.Equal(int64(125), accessLog.BytesReceived)
+	assert.Equal("10.244.0.15:3306", accessLog.UpstreamHost)
+	assert.Equal("10.244.0.10:53620", accessLog.DownstreamRemoteAddress)
+
+	assert.Nil(parseAccessLog(`[Envoy (Epoch 0)] [2021-02-22 21:22:35.237][19][warning][config] StreamAggregatedResources gRPC config stream closed`))
+	assert.Nil(parseAccessLog(`2021-02-22T21:22:35.237Z info sds resource:default pushed key/cert pair to proxy`))
+}
+
+func TestParseJSONAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLog := parseAccessLog(`{"authority":"reviews:9080","bytes_received":0,"bytes_sent":358,"downstream_remote_address":"10.244.0.10:41278","duration":5,"method":"GET","path":"/reviews/0","protocol":"HTTP/1.1","response_code":200,"response_flags":"-","start_time":"2021-02-22T21:22:35.237Z","upstream_cluster":"outbound|9080||reviews.bookinfo.svc.cluster.local","upstream_host":"10.244.0.12:9080","upstream_service_time":"4","x_forwarded_for":null}`)
+	assert.NotNil(accessLog)
+	assert.Equal(200, accessLog.ResponseCode)
+	assert.Empty(accessLog.ResponseFlags)
+	assert.Equal("GET", accessLog.Method)
+	assert.Equal(int64(358), accessLog.BytesSent)
+	assert.Equal(float64(5), accessLog.Duration)
+	assert.Equal("4", accessLog.UpstreamServiceTime)
+	assert.Equal("10.244.0.12:9080", accessLog.UpstreamHost)
+	assert.Empty(accessLog.ForwardedFor)
+	assert.False(accessLog.hasResponseFlag([]string{"UF"}))
+
+	assert.Nil(parseAccessLog(`{"level":"info","msg":"not an access log"}`))
+	assert.Nil(parseAccessLog(`{not json`))
+}
+
+func TestResponseFlagsFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	correlation := newLogCorrelation(config.LogCorrelationConfig{})
+	failed, _, ok := parseLogLine(`2021-02-22T21:22:35.237512Z [2021-02-22T21:22:35.237Z] "GET /reviews/0 HTTP/1.1" 503 UF upstream_reset_before_response_started{connection_failure} - "-" 0 91 2 - "-" "curl/7.64.0" "-" "reviews:9080" "10.244.0.12:9080" outbound|9080||reviews.bookinfo.svc.cluster.local - 10.96.27.89:9080 10.244.0.10:41278 - default`, correlation)
+	assert.True(ok)
+	assert.NotNil(failed.AccessLog)
+	succeeded, _, ok := parseLogLine(`2021-02-22T21:22:36.237512Z [2021-02-22T21:22:36.237Z] "GET /reviews/0 HTTP/1.1" 200 - via_upstream - "-" 0 358 5 4 "-" "curl/7.64.0" "-" "reviews:9080" "10.244.0.12:9080" outbound|9080||reviews.bookinfo.svc.cluster.local 10.244.0.10:50112 10.96.27.89:9080 10.244.0.10:41278 - default`, correlation)
+	assert.True(ok)
+	other, _, ok := parseLogLine(`2021-02-22T21:22:37.237512Z info sds resource:default pushed key/cert pair to proxy`, correlation)
+	assert.True(ok)
+	assert.Nil(other.AccessLog)
+
+	opts := &LogOptions{ResponseFlags: []string{"UF", "UO"}}
+	assert.True(opts.matches(failed))
+	assert.False(opts.matches(succeeded))
+	assert.False(opts.matches(other))
+	assert.True((&LogOptions{}).matches(other))
+}
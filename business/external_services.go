@@ -0,0 +1,242 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultExternalServicesWindow = "1d"
+	passthroughCluster            = "PassthroughCluster"
+)
+
+// GetExternalServices returns the inventory of the hosts outside of the mesh: the hosts registered by the
+// ServiceEntries of the accessible namespaces, and the hosts reached through the PassthroughCluster over a window,
+// with the workloads calling them and the TLS posture of their ports. The TLS posture is derived from the protocols
+// of the ports and from the DestinationRules originating TLS.
+func (in *MeshService) GetExternalServices(window string) (*models.ExternalServiceInventory, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "GetExternalServices")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultExternalServicesWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 1d", window))
+		return nil, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	services := map[string]*models.ExternalService{}
+	destinationRules := []kubernetes.IstioObject{}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+		var serviceEntries, drs []kubernetes.IstioObject
+		if serviceEntries, err = in.getIstioObjects(ns.Name, kubernetes.ServiceEntries); err != nil {
+			return nil, err
+		}
+		if drs, err = in.getIstioObjects(ns.Name, kubernetes.DestinationRules); err != nil {
+			return nil, err
+		}
+		destinationRules = append(destinationRules, drs...)
+		for _, se := range serviceEntries {
+			addServiceEntry(services, se)
+		}
+	}
+
+	requests, connections, err := in.prom.GetExternalTraffic(window, util.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	inventory := &models.ExternalServiceInventory{Window: window, ExternalServices: []models.ExternalService{}}
+	blockedRates := map[string]float64{}
+	for _, traffic := range []struct {
+		vector   pmod.Vector
+		protocol string
+	}{{requests, "HTTP"}, {connections, "TCP"}} {
+		for _, sample := range traffic.vector {
+			namespace := string(sample.Metric["source_workload_namespace"])
+			if !accessible[namespace] {
+				continue
+			}
+			rate := float64(sample.Value)
+			destination := string(sample.Metric["destination_service"])
+			var service *models.ExternalService
+			switch cluster := string(sample.Metric["destination_service_name"]); cluster {
+			case passthroughCluster, blackHoleCluster:
+				host, port := externalHostPort(destination, traffic.protocol)
+				if host == "" {
+					inventory.UnidentifiedRate += rate
+					continue
+				}
+				if service = services[host]; service == nil {
+					service = &models.ExternalService{Host: host, ServiceEntries: []models.IstioConfigReference{}, Ports: []models.ExternalServicePort{}, Sources: []models.ExternalHostSource{}}
+					services[host] = service
+				}
+				if cluster == blackHoleCluster {
+					blockedRates[host] += rate
+				}
+				addExternalServicePort(service, models.ExternalServicePort{Number: port.Number, Protocol: port.Protocol})
+			default:
+				// The other destinations without workload are the registered hosts, or services of the cluster
+				// without proxy, which are not external
+				if service = findRegisteredHost(services, strings.ToLower(destination)); service == nil {
+					continue
+				}
+			}
+			service.Rate += rate
+			addExternalServiceSource(service, models.ExternalHostSource{Namespace: namespace, Workload: string(sample.Metric["source_workload"]), Rate: rate})
+		}
+	}
+
+	for _, service := range services {
+		service.Blocked = !service.Registered && blockedRates[service.Host] > 0 && blockedRates[service.Host] >= service.Rate
+		for i := range service.Ports {
+			port := &service.Ports[i]
+			port.TLS = externalPortTLS(port.Protocol, tlsOriginated(destinationRules, service.Host, port.Number))
+			if port.TLS == models.ExternalTLSPlaintext {
+				service.Plaintext = true
+			}
+		}
+		sort.Slice(service.Ports, func(i, j int) bool { return service.Ports[i].Number < service.Ports[j].Number })
+		sort.Slice(service.Sources, func(i, j int) bool {
+			a, b := service.Sources[i], service.Sources[j]
+			return a.Namespace < b.Namespace || (a.Namespace == b.Namespace && a.Workload < b.Workload)
+		})
+		inventory.ExternalServices = append(inventory.ExternalServices, *service)
+	}
+	sort.Slice(inventory.ExternalServices, func(i, j int) bool {
+		return inventory.ExternalServices[i].Host < inventory.ExternalServices[j].Host
+	})
+	return inventory, nil
+}
+
+func (in *MeshService) getIstioObjects(namespace, resourceType string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(namespace, resourceType) {
+		return kialiCache.GetIstioObjects(namespace, resourceType, "")
+	}
+	return in.k8s.GetIstioObjects(namespace, resourceType, "")
+}
+
+// addServiceEntry adds the hosts of a ServiceEntry outside of the mesh, with its ports
+func addServiceEntry(services map[string]*models.ExternalService, se kubernetes.IstioObject) {
+	spec := se.GetSpec()
+	// The location of a ServiceEntry is MESH_EXTERNAL by default
+	if location, _ := spec["location"].(string); location == "MESH_INTERNAL" {
+		return
+	}
+	meta := se.GetObjectMeta()
+	reference := models.IstioConfigReference{ObjectType: kubernetes.ServiceEntries, Namespace: meta.Namespace, Name: meta.Name}
+	hosts, _ := spec["hosts"].([]interface{})
+	ports, _ := spec["ports"].([]interface{})
+	for _, h := range hosts {
+		host, ok := h.(string)
+		if !ok || host == "" {
+			continue
+		}
+		host = strings.ToLower(host)
+		service, ok := services[host]
+		if !ok {
+			service = &models.ExternalService{Host: host, ServiceEntries: []models.IstioConfigReference{}, Ports: []models.ExternalServicePort{}, Sources: []models.ExternalHostSource{}}
+			services[host] = service
+		}
+		service.Registered = true
+		service.ServiceEntries = append(service.ServiceEntries, reference)
+		for _, p := range ports {
+			if port, ok := p.(map[string]interface{}); ok {
+				protocol, _ := port["protocol"].(string)
+				addExternalServicePort(service, models.ExternalServicePort{Number: int(toFloat(port["number"])), Protocol: strings.ToUpper(protocol)})
+			}
+		}
+	}
+}
+
+// findRegisteredHost returns the registered service of a host, registered as such or by a wildcard
+func findRegisteredHost(services map[string]*models.ExternalService, host string) *models.ExternalService {
+	if service, ok := services[host]; ok && service.Registered {
+		return service
+	}
+	for _, service := range services {
+		if service.Registered && kubernetes.HostWithinWildcardHost(host, service.Host) {
+			return service
+		}
+	}
+	return nil
+}
+
+func addExternalServicePort(service *models.ExternalService, port models.ExternalServicePort) {
+	for _, p := range service.Ports {
+		if p.Number == port.Number && p.Protocol == port.Protocol {
+			return
+		}
+	}
+	service.Ports = append(service.Ports, port)
+}
+
+func addExternalServiceSource(service *models.ExternalService, source models.ExternalHostSource) {
+	for i, s := range service.Sources {
+		if s.Namespace == source.Namespace && s.Workload == source.Workload {
+			service.Sources[i].Rate += source.Rate
+			return
+		}
+	}
+	service.Sources = append(service.Sources, source)
+}
+
+// externalPortTLS returns the TLS posture of a port of an external service from its protocol
+func externalPortTLS(protocol string, originated bool) string {
+	switch protocol {
+	case "HTTPS", "TLS":
+		return models.ExternalTLSApplication
+	case "HTTP", "HTTP2", "GRPC", "GRPC-WEB":
+		if originated {
+			return models.ExternalTLSOrigination
+		}
+		return models.ExternalTLSPlaintext
+	}
+	return models.ExternalTLSUnknown
+}
+
+// tlsOriginated returns true when a DestinationRule of a host originates TLS on a port, in its port level settings
+// or in its traffic policy
+func tlsOriginated(destinationRules []kubernetes.IstioObject, host string, port int) bool {
+	for _, dr := range destinationRules {
+		drHost, _ := dr.GetSpec()["host"].(string)
+		drHost = strings.ToLower(drHost)
+		if drHost != host && !kubernetes.HostWithinWildcardHost(host, drHost) {
+			continue
+		}
+		trafficPolicy, _ := dr.GetSpec()["trafficPolicy"].(map[string]interface{})
+		settings, _ := trafficPolicy["portLevelSettings"].([]interface{})
+		tls := trafficPolicy["tls"]
+		for _, s := range settings {
+			setting, _ := s.(map[string]interface{})
+			if p, ok := setting["port"].(map[string]interface{}); ok && int(toFloat(p["number"])) == port {
+				if portTLS, ok := setting["tls"]; ok {
+					tls = portTLS
+				}
+				break
+			}
+		}
+		if tlsSettings, ok := tls.(map[string]interface{}); ok {
+			if mode, _ := tlsSettings["mode"].(string); mode == "SIMPLE" || mode == "MUTUAL" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGetExternalServices(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	conf := config.NewConfig()
+	conf.API.Namespaces.Exclude = []string{}
+	config.Set(conf)
+	kialiCache = nil
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "travels"}},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.ServiceEntries, "").Return([]kubernetes.IstioObject{
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "github", Namespace: "bookinfo"},
+			Spec: map[string]interface{}{
+				"hosts": []interface{}{"api.github.com"},
+				"ports": []interface{}{
+					map[string]interface{}{"number": float64(443), "protocol": "HTTPS", "name": "https"},
+				},
+			},
+		},
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "wikipedia", Namespace: "bookinfo"},
+			Spec: map[string]interface{}{
+				"hosts":    []interface{}{"*.wikipedia.org"},
+				"location": "MESH_EXTERNAL",
+				"ports": []interface{}{
+					map[string]interface{}{"number": float64(80), "protocol": "HTTP", "name": "http"},
+				},
+			},
+		},
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "vm", Namespace: "bookinfo"},
+			Spec: map[string]interface{}{
+				"hosts":    []interface{}{"vm.bookinfo.svc.cluster.local"},
+				"location": "MESH_INTERNAL",
+			},
+		},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.DestinationRules, "").Return([]kubernetes.IstioObject{
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "wikipedia", Namespace: "bookinfo"},
+			Spec: map[string]interface{}{
+				"host": "*.wikipedia.org",
+				"trafficPolicy": map[string]interface{}{
+					"portLevelSettings": []interface{}{
+						map[string]interface{}{
+							"port": map[string]interface{}{"number": float64(80)},
+							"tls":  map[string]interface{}{"mode": "SIMPLE"},
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+	k8s.On("GetIstioObjects", "travels", mock.AnythingOfType("string"), "").Return([]kubernetes.IstioObject{}, nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetExternalTraffic", "1d", mock.AnythingOfType("time.Time")).Return(
+		pmod.Vector{
+			fakeEgressSample("bookinfo", "reviews-v1", "api.github.com", "api.github.com", 2),
+			fakeEgressSample("travels", "cars-v1", "en.wikipedia.org", "en.wikipedia.org", 1),
+			fakeEgressSample("travels", "cars-v1", "httpbin.org", "PassthroughCluster", 0.5),
+			// Service of the cluster without proxy
+			fakeEgressSample("bookinfo", "reviews-v1", "ratings.bookinfo.svc.cluster.local", "ratings", 3),
+			// Not accessible
+			fakeEgressSample("secret", "app-v1", "api.github.com", "api.github.com", 1),
+		},
+		pmod.Vector{
+			fakeEgressSample("bookinfo", "ratings-v1", "10.0.0.12:5432", "PassthroughCluster", 0.1),
+		},
+		nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	inventory, err := layer.Mesh.GetExternalServices("")
+	require.NoError(err)
+	assert.Equal("1d", inventory.Window)
+	assert.InDelta(0.1, inventory.UnidentifiedRate, 0.0001)
+	require.Len(inventory.ExternalServices, 3)
+
+	wikipedia := inventory.ExternalServices[0]
+	assert.Equal("*.wikipedia.org", wikipedia.Host)
+	assert.True(wikipedia.Registered)
+	assert.False(wikipedia.Plaintext)
+	assert.Equal([]models.ExternalServicePort{{Number: 80, Protocol: "HTTP", TLS: models.ExternalTLSOrigination}}, wikipedia.Ports)
+	assert.Equal([]models.ExternalHostSource{{Namespace: "travels", Workload: "cars-v1", Rate: 1}}, wikipedia.Sources)
+
+	github := inventory.ExternalServices[1]
+	assert.Equal("api.github.com", github.Host)
+	assert.Equal([]models.IstioConfigReference{{ObjectType: kubernetes.ServiceEntries, Namespace: "bookinfo", Name: "github"}}, github.ServiceEntries)
+	assert.Equal(models.ExternalTLSApplication, github.Ports[0].TLS)
+	assert.Equal(2.0, github.Rate)
+
+	httpbin := inventory.ExternalServices[2]
+	assert.Equal("httpbin.org", httpbin.Host)
+	assert.False(httpbin.Registered)
+	assert.True(httpbin.Plaintext)
+	assert.Empty(httpbin.ServiceEntries)
+	assert.Equal([]models.ExternalServicePort{{Number: 80, Protocol: "HTTP", TLS: models.ExternalTLSPlaintext}}, httpbin.Ports)
+
+	_, err = layer.Mesh.GetExternalServices("forever")
+	assert.Error(err)
+}
@@ -0,0 +1,260 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Annotation set on the VirtualServices with faults injected by the wizard, with the time the faults are removed at.
+// It shows the faults which outlived their expiration, as when Kiali is restarted before it.
+const faultInjectionExpiryAnnotation = "wizard.kiali.io/fault-injection-expires-at"
+
+// ApplyFaultInjection injects delays and aborts in the http routes of the VirtualService of a service, generating a
+// VirtualService routing to the service when it has none. The faults are removed at their expiration: unlike the
+// other wizards, the change cannot be confirmed.
+func (in *WizardService) ApplyFaultInjection(namespace, service string, request models.FaultInjectionRequest) (*models.WizardChange, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "ApplyFaultInjection")
+	defer promtimer.ObserveNow(&err)
+
+	if request.TTL == "" {
+		err = errors.NewBadRequest("the ttl of the faults is required")
+		return nil, err
+	}
+	ttl, err := parseWizardTTL(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Faults) == 0 {
+		err = errors.NewBadRequest("no fault to inject")
+		return nil, err
+	}
+	for _, f := range request.Faults {
+		if err = validateRouteFault(f); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = in.k8s.GetService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{
+		"hosts": []interface{}{service},
+		"http":  []interface{}{map[string]interface{}{"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": service}}}}},
+	}
+	vs, err := in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, service)
+	if err == nil {
+		// Copied, so that the spec of the cached object is left as is
+		raw, _ := json.Marshal(vs.GetSpec())
+		spec = map[string]interface{}{}
+		if err = json.Unmarshal(raw, &spec); err != nil {
+			return nil, err
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	httpRoutes, _ := spec["http"].([]interface{})
+	for _, f := range request.Faults {
+		injected := false
+		for _, h := range httpRoutes {
+			httpRoute, _ := h.(map[string]interface{})
+			if httpRoute == nil || (f.Route != "" && httpRoute["name"] != f.Route) {
+				continue
+			}
+			httpRoute["fault"] = istioFault(f)
+			injected = true
+		}
+		if !injected {
+			err = errors.NewBadRequest(fmt.Sprintf("no http route of VirtualService %s is named %s", service, f.Route))
+			return nil, err
+		}
+	}
+
+	expiresAt := util.Clock.Now().Add(ttl)
+	var change *models.WizardChange
+	change, err = in.applyWizardObjects(namespace, service, models.WizardFaultInjection, ttl, []wizardObject{{
+		resourceType: kubernetes.VirtualServices,
+		name:         service,
+		spec:         spec,
+		annotations:  map[string]string{faultInjectionExpiryAnnotation: expiresAt.Format(time.RFC3339)},
+	}})
+	return change, err
+}
+
+// ListFaultInjections returns the faults injected in the VirtualServices of the accessible namespaces, by the wizard
+// or not, so that the forgotten ones can be found
+func (in *WizardService) ListFaultInjections() ([]models.FaultInjection, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "ListFaultInjections")
+	defer promtimer.ObserveNow(&err)
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	now := util.Clock.Now()
+	faults := []models.FaultInjection{}
+	for _, ns := range namespaces {
+		var vss []kubernetes.IstioObject
+		if IsResourceCached(ns.Name, kubernetes.VirtualServices) {
+			vss, err = kialiCache.GetIstioObjects(ns.Name, kubernetes.VirtualServices, "")
+		} else {
+			vss, err = in.k8s.GetIstioObjects(ns.Name, kubernetes.VirtualServices, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, vs := range vss {
+			faults = append(faults, virtualServiceFaults(ns.Name, vs, now)...)
+		}
+	}
+	return faults, nil
+}
+
+// RemoveFaultInjections removes the faults injected in the http routes of a VirtualService, cancelling the pending
+// fault injection of the wizard on it
+func (in *WizardService) RemoveFaultInjections(namespace, name string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "RemoveFaultInjections")
+	defer promtimer.ObserveNow(&err)
+
+	vs, err := in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, name)
+	if err != nil {
+		return err
+	}
+	raw, _ := json.Marshal(vs.GetSpec()["http"])
+	var httpRoutes []interface{}
+	if err = json.Unmarshal(raw, &httpRoutes); err != nil {
+		return err
+	}
+	removed := false
+	for _, h := range httpRoutes {
+		if httpRoute, ok := h.(map[string]interface{}); ok {
+			if _, ok := httpRoute["fault"]; ok {
+				delete(httpRoute, "fault")
+				removed = true
+			}
+		}
+	}
+	if !removed {
+		err = kubernetes.NewNotFound(name, "kiali.io", "fault injections")
+		return err
+	}
+
+	if pending, pendingErr := getPendingWizardChange(namespace, name); pendingErr == nil && pending.Change.Wizard == models.WizardFaultInjection {
+		// The faults are removed here, the rest of the change is kept
+		_, _ = takePendingWizardChange(namespace, name)
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{faultInjectionExpiryAnnotation: nil}},
+		"spec":     map[string]interface{}{"http": httpRoutes},
+	})
+	_, err = in.k8s.UpdateIstioObject(kubernetes.ResourceTypesToAPI[kubernetes.VirtualServices], namespace, kubernetes.VirtualServices, name, string(patch))
+	return err
+}
+
+func validateRouteFault(f models.RouteFault) error {
+	if f.Delay == nil && f.Abort == nil {
+		return errors.NewBadRequest("a fault needs a delay or an abort")
+	}
+	if f.Delay != nil {
+		if f.Delay.Percentage <= 0 || f.Delay.Percentage > 100 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid delay percentage %v, expected a value between 0 and 100", f.Delay.Percentage))
+		}
+		if d, err := time.ParseDuration(f.Delay.FixedDelay); err != nil || d <= 0 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid fixed delay [%s], expected a positive duration such as 5s", f.Delay.FixedDelay))
+		}
+	}
+	if f.Abort != nil {
+		if f.Abort.Percentage <= 0 || f.Abort.Percentage > 100 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid abort percentage %v, expected a value between 0 and 100", f.Abort.Percentage))
+		}
+		if f.Abort.HTTPStatus < 200 || f.Abort.HTTPStatus > 599 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid abort http status %d", f.Abort.HTTPStatus))
+		}
+	}
+	return nil
+}
+
+// istioFault returns the fault of an http route of a VirtualService spec
+func istioFault(f models.RouteFault) map[string]interface{} {
+	fault := map[string]interface{}{}
+	if f.Delay != nil {
+		fault["delay"] = map[string]interface{}{"fixedDelay": f.Delay.FixedDelay, "percentage": map[string]interface{}{"value": f.Delay.Percentage}}
+	}
+	if f.Abort != nil {
+		fault["abort"] = map[string]interface{}{"httpStatus": f.Abort.HTTPStatus, "percentage": map[string]interface{}{"value": f.Abort.Percentage}}
+	}
+	return fault
+}
+
+// virtualServiceFaults returns the faults of the http routes of a VirtualService
+func virtualServiceFaults(namespace string, vs kubernetes.IstioObject, now time.Time) []models.FaultInjection {
+	faults := []models.FaultInjection{}
+	meta := vs.GetObjectMeta()
+	var expiresAt *time.Time
+	if raw, ok := meta.Annotations[faultInjectionExpiryAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			expiresAt = &t
+		}
+	}
+	httpRoutes, _ := vs.GetSpec()["http"].([]interface{})
+	for i, h := range httpRoutes {
+		httpRoute, _ := h.(map[string]interface{})
+		fault, ok := httpRoute["fault"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		injection := models.FaultInjection{
+			Namespace:      namespace,
+			VirtualService: meta.Name,
+			RouteIndex:     i,
+			Wizard:         meta.Labels[wizardLabel] == models.WizardFaultInjection,
+			ExpiresAt:      expiresAt,
+			Expired:        expiresAt != nil && now.After(*expiresAt),
+		}
+		injection.Route, _ = httpRoute["name"].(string)
+		if delay, ok := fault["delay"].(map[string]interface{}); ok {
+			injection.Delay = &models.FaultDelay{Percentage: faultPercentage(delay)}
+			injection.Delay.FixedDelay, _ = delay["fixedDelay"].(string)
+		}
+		if abort, ok := fault["abort"].(map[string]interface{}); ok {
+			injection.Abort = &models.FaultAbort{Percentage: faultPercentage(abort), HTTPStatus: int(toFloat(abort["httpStatus"]))}
+		}
+		faults = append(faults, injection)
+	}
+	return faults
+}
+
+// faultPercentage returns the percentage of a delay or an abort, set by the percentage field or the deprecated
+// percent field. The fault applies to all the requests without any.
+func faultPercentage(fault map[string]interface{}) float64 {
+	if percentage, ok := fault["percentage"].(map[string]interface{}); ok {
+		return toFloat(percentage["value"])
+	}
+	if percent, ok := fault["percent"]; ok {
+		return toFloat(percent)
+	}
+	return 100
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
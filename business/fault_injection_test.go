@@ -0,0 +1,113 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestApplyFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+	setupWizardChangesMock(new(kubetest.K8SClientMock))
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetService", "bookinfo", "ratings").Return(&core_v1.Service{}, nil)
+	notFound := kubernetes.NewNotFound("ratings", "networking.istio.io", "virtualservices")
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "ratings").Return(&kubernetes.GenericIstioObject{}, notFound)
+	var created string
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { created = args.String(3) }).Return(&kubernetes.GenericIstioObject{}, nil)
+	svc := newWizardService(k8s)
+
+	change, err := svc.ApplyFaultInjection("bookinfo", "ratings", models.FaultInjectionRequest{
+		Faults: []models.RouteFault{{
+			Delay: &models.FaultDelay{Percentage: 10, FixedDelay: "5s"},
+			Abort: &models.FaultAbort{Percentage: 5, HTTPStatus: 503},
+		}},
+		TTL: "15m",
+	})
+	assert.NoError(err)
+	assert.Equal(time.Date(2021, 3, 1, 10, 15, 0, 0, time.UTC), *change.ExpiresAt)
+	assert.JSONEq(`{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind": "VirtualService",
+		"metadata": {"name": "ratings", "namespace": "bookinfo", "labels": {"kiali_wizard": "fault_injection"}, "annotations": {"wizard.kiali.io/fault-injection-expires-at": "2021-03-01T10:15:00Z"}},
+		"spec": {"hosts": ["ratings"], "http": [{
+			"route": [{"destination": {"host": "ratings"}}],
+			"fault": {"delay": {"fixedDelay": "5s", "percentage": {"value": 10}}, "abort": {"httpStatus": 503, "percentage": {"value": 5}}}
+		}]}
+	}`, created)
+
+	_, err = svc.ConfirmWizardChange("bookinfo", "ratings")
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.GetWizardChange("bookinfo", "ratings")
+	assert.NoError(err)
+	_, _ = takePendingWizardChange("bookinfo", "ratings")
+
+	_, err = svc.ApplyFaultInjection("bookinfo", "ratings", models.FaultInjectionRequest{Faults: []models.RouteFault{{Delay: &models.FaultDelay{Percentage: 10, FixedDelay: "5s"}}}})
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.ApplyFaultInjection("bookinfo", "ratings", models.FaultInjectionRequest{Faults: []models.RouteFault{{Abort: &models.FaultAbort{Percentage: 150, HTTPStatus: 503}}}, TTL: "5m"})
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.ApplyFaultInjection("bookinfo", "ratings", models.FaultInjectionRequest{Faults: []models.RouteFault{{Route: "v2", Abort: &models.FaultAbort{Percentage: 50, HTTPStatus: 503}}}, TTL: "5m"})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestVirtualServiceFaults(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "reviews",
+			Labels:      map[string]string{wizardLabel: models.WizardFaultInjection},
+			Annotations: map[string]string{faultInjectionExpiryAnnotation: "2021-03-01T10:15:00Z"},
+		},
+		Spec: map[string]interface{}{"http": []interface{}{
+			map[string]interface{}{"name": "v1", "route": []interface{}{}},
+			map[string]interface{}{"name": "v2", "fault": map[string]interface{}{"abort": map[string]interface{}{"httpStatus": float64(500), "percentage": map[string]interface{}{"value": 2.5}}}},
+			map[string]interface{}{"fault": map[string]interface{}{"delay": map[string]interface{}{"fixedDelay": "1s", "percent": float64(20)}}},
+		}},
+	}
+	faults := virtualServiceFaults("bookinfo", vs, time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC))
+	assert.Len(faults, 2)
+	assert.Equal("v2", faults[0].Route)
+	assert.Equal(1, faults[0].RouteIndex)
+	assert.Equal(&models.FaultAbort{Percentage: 2.5, HTTPStatus: 500}, faults[0].Abort)
+	assert.True(faults[0].Wizard)
+	assert.True(faults[0].Expired)
+	assert.Equal(&models.FaultDelay{Percentage: 20, FixedDelay: "1s"}, faults[1].Delay)
+	assert.Empty(faults[1].Route)
+}
+
+func TestRemoveFaultInjections(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(&kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{"http": []interface{}{
+			map[string]interface{}{"name": "v1", "fault": map[string]interface{}{"abort": map[string]interface{}{"httpStatus": float64(500)}}},
+		}},
+	}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "ratings").Return(fakeWizardVirtualService(), nil)
+	var patch string
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { patch = args.String(4) }).Return(&kubernetes.GenericIstioObject{}, nil)
+	svc := WizardService{k8s: k8s}
+
+	assert.NoError(svc.RemoveFaultInjections("bookinfo", "reviews"))
+	assert.JSONEq(`{"metadata":{"annotations":{"wizard.kiali.io/fault-injection-expires-at":null}},"spec":{"http":[{"name":"v1"}]}}`, patch)
+	assert.True(errors.IsNotFound(svc.RemoveFaultInjections("bookinfo", "ratings")))
+}
@@ -0,0 +1,173 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// FederationService aggregates the data of remote Kiali instances through their API. A central Kiali needs neither
+// informers nor permissions in the clusters of the remote instances: each one serves the data of its cluster.
+// The remote instances are read with the credentials of the central Kiali, not the ones of the user, so the
+// federation is reserved to the administrators of Kiali.
+type FederationService struct {
+	k8s kubernetes.ClientInterface
+}
+
+// Paths of the API which are not forwarded to the remote instances: the federation itself and the authentication
+var federationForbiddenPaths = []string{"api/federation", "api/authenticate", "api/logout", "api/auth"}
+
+// GetInstances returns the reachability of each remote Kiali instance
+func (in *FederationService) GetInstances() ([]models.FederatedInstanceStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "FederationService", "GetInstances")
+	defer promtimer.ObserveNow(&err)
+
+	federation := config.Get().Federation
+	if !federation.Enabled {
+		err = kubernetes.NewNotFound("federation", "kiali.io", "federation")
+		return nil, err
+	}
+	if err = checkKialiAdmin(in.k8s, "the federation"); err != nil {
+		return nil, err
+	}
+
+	values, errs := queryClusters(federatedClusters(federation), func(cluster string) (interface{}, error) {
+		start := time.Now()
+		_, err := getFederated(federation.GetInstance(cluster), "api/namespaces", "")
+		return time.Since(start), err
+	})
+	statuses := make([]models.FederatedInstanceStatus, len(federation.Instances))
+	for i, instance := range federation.Instances {
+		statuses[i] = models.FederatedInstanceStatus{Cluster: instance.Cluster, URL: instance.URL, Reachable: errs[i] == nil}
+		if errs[i] != nil {
+			statuses[i].Message = errs[i].Error()
+		} else {
+			statuses[i].LatencyMs = values[i].(time.Duration).Milliseconds()
+		}
+	}
+	return statuses, nil
+}
+
+// GetNamespaces returns the namespaces of all the remote Kiali instances. The instances which cannot be read are
+// reported in the errors, with the namespaces of the others.
+func (in *FederationService) GetNamespaces() (*models.FederatedNamespaces, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "FederationService", "GetNamespaces")
+	defer promtimer.ObserveNow(&err)
+
+	federation := config.Get().Federation
+	if !federation.Enabled {
+		err = kubernetes.NewNotFound("federation", "kiali.io", "federation")
+		return nil, err
+	}
+	if err = checkKialiAdmin(in.k8s, "the federation"); err != nil {
+		return nil, err
+	}
+
+	values, errs := queryClusters(federatedClusters(federation), func(cluster string) (interface{}, error) {
+		body, err := getFederated(federation.GetInstance(cluster), "api/namespaces", "")
+		if err != nil {
+			return nil, err
+		}
+		namespaces := []models.Namespace{}
+		if err := json.Unmarshal(body, &namespaces); err != nil {
+			return nil, fmt.Errorf("cannot parse the namespaces: %v", err)
+		}
+		return namespaces, nil
+	})
+	result := &models.FederatedNamespaces{Namespaces: []models.FederatedNamespace{}, Errors: map[string]string{}}
+	for i, instance := range federation.Instances {
+		if errs[i] != nil {
+			result.Errors[instance.Cluster] = errs[i].Error()
+			continue
+		}
+		for _, namespace := range values[i].([]models.Namespace) {
+			result.Namespaces = append(result.Namespaces, models.FederatedNamespace{Cluster: instance.Cluster, Name: namespace.Name})
+		}
+	}
+	sort.Slice(result.Namespaces, func(i, j int) bool {
+		if result.Namespaces[i].Cluster != result.Namespaces[j].Cluster {
+			return result.Namespaces[i].Cluster < result.Namespaces[j].Cluster
+		}
+		return result.Namespaces[i].Name < result.Namespaces[j].Name
+	})
+	return result, nil
+}
+
+// Proxy reads a path of the API of the remote Kiali instance of a cluster, i.e. "api/namespaces/bookinfo/health".
+// Only reads are forwarded, and neither the federation nor the authentication of the remote instance.
+func (in *FederationService) Proxy(cluster, path, rawQuery string) ([]byte, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "FederationService", "Proxy")
+	defer promtimer.ObserveNow(&err)
+
+	federation := config.Get().Federation
+	instance := federation.GetInstance(cluster)
+	if !federation.Enabled || instance == nil {
+		err = kubernetes.NewNotFound(cluster, "kiali.io", "federation")
+		return nil, err
+	}
+	if err = checkKialiAdmin(in.k8s, "the federation"); err != nil {
+		return nil, err
+	}
+	path = strings.TrimPrefix(path, "/")
+	if !strings.HasPrefix(path, "api/") || strings.Contains(path, "..") {
+		err = k8s_errors.NewBadRequest(fmt.Sprintf("path [%s] is not in the API of Kiali", path))
+		return nil, err
+	}
+	for _, forbidden := range federationForbiddenPaths {
+		if path == forbidden || strings.HasPrefix(path, forbidden+"/") {
+			err = k8s_errors.NewBadRequest(fmt.Sprintf("path [%s] is not forwarded to the remote Kiali", path))
+			return nil, err
+		}
+	}
+
+	var body []byte
+	body, err = getFederated(instance, path, rawQuery)
+	return body, err
+}
+
+func federatedClusters(federation config.FederationConfig) []string {
+	clusters := make([]string, len(federation.Instances))
+	for i, instance := range federation.Instances {
+		clusters[i] = instance.Cluster
+	}
+	return clusters
+}
+
+// getFederated reads a path of the API of a remote Kiali instance, with the credentials of the central Kiali. The
+// token of the ServiceAccount of the central Kiali is never sent: it would give its permissions in the home cluster to
+// the remote instance, which doesn't accept it anyway.
+func getFederated(instance *config.FederatedInstance, path, rawQuery string) ([]byte, error) {
+	auth := instance.Auth
+	auth.UseKialiToken = false
+	url := strings.TrimSuffix(instance.URL, "/") + "/" + path
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	timeout := time.Duration(config.Get().Clustering.RequestTimeout) * time.Second
+	body, code, err := httputil.HttpGet(url, &auth, timeout)
+	switch {
+	case err != nil:
+		return nil, err
+	case code == http.StatusNotFound:
+		return nil, kubernetes.NewNotFound(path, "kiali.io", instance.Cluster)
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return nil, fmt.Errorf("the credentials are rejected by the Kiali of cluster [%s] with status %d", instance.Cluster, code)
+	case code != http.StatusOK:
+		return nil, fmt.Errorf("the Kiali of cluster [%s] answered with status %d", instance.Cluster, code)
+	}
+	return body, nil
+}
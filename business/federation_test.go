@@ -0,0 +1,125 @@
+package business
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func setupFederation(admin bool, instances ...config.FederatedInstance) FederationService {
+	conf := config.NewConfig()
+	conf.Deployment.Namespace = "kiali"
+	conf.Federation.Enabled = true
+	conf.Federation.Instances = instances
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSelfSubjectAccessReview", "kiali", "apps", "deployments", []string{"update"}).Return(fakeAccessReview(admin), nil)
+	return FederationService{k8s: k8s}
+}
+
+func TestFederationNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	east := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/kiali/api/namespaces", r.URL.Path)
+		assert.Equal("Bearer east-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"name":"travels"},{"name":"bookinfo"}]`))
+	}))
+	defer east.Close()
+	west := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer west.Close()
+	federation := setupFederation(true,
+		config.FederatedInstance{Cluster: "east", URL: east.URL + "/kiali/", Auth: config.Auth{Type: config.AuthTypeBearer, Token: "east-token"}},
+		config.FederatedInstance{Cluster: "west", URL: west.URL},
+	)
+
+	namespaces, err := federation.GetNamespaces()
+	require.NoError(err)
+	require.Len(namespaces.Namespaces, 2)
+	assert.Equal("east", namespaces.Namespaces[0].Cluster)
+	assert.Equal("bookinfo", namespaces.Namespaces[0].Name)
+	assert.Equal("travels", namespaces.Namespaces[1].Name)
+	assert.Contains(namespaces.Errors["west"], "403")
+
+	instances, err := federation.GetInstances()
+	require.NoError(err)
+	require.Len(instances, 2)
+	assert.True(instances[0].Reachable)
+	assert.False(instances[1].Reachable)
+	assert.Contains(instances[1].Message, "rejected")
+}
+
+func TestFederationProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	east := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/api/namespaces/bookinfo/health", r.URL.Path)
+		assert.Equal("rateInterval=10m", r.URL.RawQuery)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer east.Close()
+	federation := setupFederation(true, config.FederatedInstance{Cluster: "east", URL: east.URL})
+
+	body, err := federation.Proxy("east", "api/namespaces/bookinfo/health", "rateInterval=10m")
+	assert.NoError(err)
+	assert.Equal("{}", string(body))
+
+	_, err = federation.Proxy("west", "api/namespaces", "")
+	assert.True(errors.IsNotFound(err))
+	_, err = federation.Proxy("east", "api/federation/east/api/namespaces", "")
+	assert.True(errors.IsBadRequest(err))
+	_, err = federation.Proxy("east", "console", "")
+	assert.True(errors.IsBadRequest(err))
+	_, err = federation.Proxy("east", "api/../metrics", "")
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestFederationRequiresAdmin(t *testing.T) {
+	assert := assert.New(t)
+
+	east := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the remote Kiali must not be read")
+	}))
+	defer east.Close()
+	federation := setupFederation(false, config.FederatedInstance{Cluster: "east", URL: east.URL})
+
+	_, err := federation.GetInstances()
+	assert.True(errors.IsForbidden(err))
+	_, err = federation.GetNamespaces()
+	assert.True(errors.IsForbidden(err))
+	_, err = federation.Proxy("east", "api/namespaces", "")
+	assert.True(errors.IsForbidden(err))
+}
+
+func TestFederationDoesNotSendKialiToken(t *testing.T) {
+	east := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotContains(t, r.Header.Get("Authorization"), "kiali-sa-token")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer east.Close()
+	kubernetes.KialiToken = "kiali-sa-token"
+	federation := setupFederation(true, config.FederatedInstance{Cluster: "east", URL: east.URL, Auth: config.Auth{Type: config.AuthTypeBearer, UseKialiToken: true}})
+
+	_, err := federation.Proxy("east", "api/namespaces", "")
+	assert.NoError(t, err)
+}
+
+func TestFederationDisabled(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	federation := FederationService{}
+	_, err := federation.GetNamespaces()
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -29,7 +29,17 @@ func (in *HealthService) GetServiceHealth(namespace, service, rateInterval strin
 	defer promtimer.ObserveNow(&err)
 
 	rqHealth, err := in.getServiceRequestsHealth(namespace, service, rateInterval, queryTime)
-	return models.ServiceHealth{Requests: rqHealth}, err
+	annotations := in.getNamespaceHealthAnnotations(namespace)
+	var svc *core_v1.Service
+	if IsNamespaceCached(namespace) {
+		svc, _ = kialiCache.GetService(namespace, service)
+	} else {
+		svc, _ = in.k8s.GetService(namespace, service)
+	}
+	if svc != nil {
+		annotations = models.MergeHealthAnnotations(annotations, models.GetHealthAnnotations(svc.Annotations))
+	}
+	return models.ServiceHealth{Requests: rqHealth, HealthAnnotations: annotations}, err
 }
 
 // GetAppHealth returns an app health from just Namespace and app name (thus, it fetches data from K8S and Prometheus)
@@ -75,6 +85,7 @@ func (in *HealthService) getAppHealth(namespace, app, rateInterval string, query
 
 	// Deployment status
 	health.WorkloadStatuses = ws.CastWorkloadStatuses()
+	health.HealthAnnotations = in.getNamespaceHealthAnnotations(namespace)
 
 	return health, errRate
 }
@@ -91,12 +102,16 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 	}
 
 	status := w.CastWorkloadStatus()
+	events := in.businessLayer.Event.getHealthEvents(namespace, models.Workloads{w})[w.Name]
+	annotations := models.MergeHealthAnnotations(in.getNamespaceHealthAnnotations(namespace), w.HealthAnnotations)
 
 	// Perf: do not bother fetching request rate if workload has no sidecar
 	if !w.IstioSidecar {
 		return models.WorkloadHealth{
-			WorkloadStatus: status,
-			Requests:       models.NewEmptyRequestHealth(),
+			WorkloadStatus:    status,
+			Requests:          models.NewEmptyRequestHealth(),
+			Events:            events,
+			HealthAnnotations: annotations,
 		}, nil
 	}
 
@@ -114,8 +129,10 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 	// Add Telemetry info
 	rate, err := in.getWorkloadRequestsHealth(namespace, workload, rateInterval, queryTime)
 	return models.WorkloadHealth{
-		WorkloadStatus: status,
-		Requests:       rate,
+		WorkloadStatus:    status,
+		Requests:          rate,
+		Events:            events,
+		HealthAnnotations: annotations,
 	}, err
 }
 
@@ -130,11 +147,31 @@ func (in *HealthService) GetNamespaceAppHealth(namespace, rateInterval string, q
 		return nil, err
 	}
 
-	return in.getNamespaceAppHealth(namespace, appEntities, rateInterval, queryTime)
+	key := resultsKey{}
+	for app, entities := range appEntities {
+		key.add("App/" + app)
+		if entities != nil {
+			for _, w := range entities.Workloads {
+				key.addWorkload(namespace, w.WorkloadListItem)
+			}
+		}
+	}
+	cacheKey := healthResultsKey("app", namespace, rateInterval, queryTime, key)
+	var health models.NamespaceAppHealth
+	if resultsCache != nil && resultsCache.Get(cacheKey, &health) {
+		return health, nil
+	}
+
+	health, err = in.getNamespaceAppHealth(namespace, appEntities, rateInterval, queryTime)
+	if err == nil && resultsCache != nil {
+		resultsCache.Set(cacheKey, health, healthResultsTTL())
+	}
+	return health, err
 }
 
 func (in *HealthService) getNamespaceAppHealth(namespace string, appEntities namespaceApps, rateInterval string, queryTime time.Time) (models.NamespaceAppHealth, error) {
 	allHealth := make(models.NamespaceAppHealth)
+	annotations := in.getNamespaceHealthAnnotations(namespace)
 
 	// Perf: do not bother fetching request rate if no workloads or no workload has sidecar
 	sidecarPresent := false
@@ -143,6 +180,7 @@ func (in *HealthService) getNamespaceAppHealth(namespace string, appEntities nam
 	for app, entities := range appEntities {
 		if app != "" {
 			h := models.EmptyAppHealth()
+			h.HealthAnnotations = annotations
 			allHealth[app] = &h
 			if entities != nil {
 				h.WorkloadStatuses = entities.Workloads.CastWorkloadStatuses()
@@ -190,15 +228,32 @@ func (in *HealthService) GetNamespaceServiceHealth(namespace, rateInterval strin
 	if err != nil {
 		return nil, err
 	}
-	return in.getNamespaceServiceHealth(namespace, services, rateInterval, queryTime), nil
+
+	key := resultsKey{}
+	for _, s := range services {
+		key.addObjectMeta("Service", s.ObjectMeta)
+	}
+	cacheKey := healthResultsKey("service", namespace, rateInterval, queryTime, key)
+	var health models.NamespaceServiceHealth
+	if resultsCache != nil && resultsCache.Get(cacheKey, &health) {
+		return health, nil
+	}
+
+	health = in.getNamespaceServiceHealth(namespace, services, rateInterval, queryTime)
+	if resultsCache != nil {
+		resultsCache.Set(cacheKey, health, healthResultsTTL())
+	}
+	return health, nil
 }
 
 func (in *HealthService) getNamespaceServiceHealth(namespace string, services []core_v1.Service, rateInterval string, queryTime time.Time) models.NamespaceServiceHealth {
 	allHealth := make(models.NamespaceServiceHealth)
+	annotations := in.getNamespaceHealthAnnotations(namespace)
 
 	// Prepare all data (note that it's important to provide data for all services, even those which may not have any health, for overview cards)
 	for _, service := range services {
 		h := models.EmptyServiceHealth()
+		h.HealthAnnotations = models.MergeHealthAnnotations(annotations, models.GetHealthAnnotations(service.Annotations))
 		allHealth[service.Name] = &h
 	}
 
@@ -227,7 +282,21 @@ func (in *HealthService) GetNamespaceWorkloadHealth(namespace, rateInterval stri
 		return nil, err
 	}
 
-	return in.getNamespaceWorkloadHealth(namespace, wl, rateInterval, queryTime)
+	key := resultsKey{}
+	for _, w := range wl {
+		key.addWorkload(namespace, w.WorkloadListItem)
+	}
+	cacheKey := healthResultsKey("workload", namespace, rateInterval, queryTime, key)
+	var health models.NamespaceWorkloadHealth
+	if resultsCache != nil && resultsCache.Get(cacheKey, &health) {
+		return health, nil
+	}
+
+	health, err = in.getNamespaceWorkloadHealth(namespace, wl, rateInterval, queryTime)
+	if err == nil && resultsCache != nil {
+		resultsCache.Set(cacheKey, health, healthResultsTTL())
+	}
+	return health, err
 }
 
 func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.Workloads, rateInterval string, queryTime time.Time) (models.NamespaceWorkloadHealth, error) {
@@ -235,9 +304,13 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 	hasSidecar := false
 
 	allHealth := make(models.NamespaceWorkloadHealth)
+	events := in.businessLayer.Event.getHealthEvents(namespace, ws)
+	annotations := in.getNamespaceHealthAnnotations(namespace)
 	for _, w := range ws {
 		allHealth[w.Name] = models.EmptyWorkloadHealth()
 		allHealth[w.Name].WorkloadStatus = w.CastWorkloadStatus()
+		allHealth[w.Name].Events = events[w.Name]
+		allHealth[w.Name].HealthAnnotations = models.MergeHealthAnnotations(annotations, w.HealthAnnotations)
 		if w.IstioSidecar {
 			hasSidecar = true
 		}
@@ -255,6 +328,17 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 	return allHealth, err
 }
 
+// getNamespaceHealthAnnotations returns the annotations of a namespace overriding the health configuration. They are
+// optional in the health: they are omitted when the namespace cannot be read.
+func (in *HealthService) getNamespaceHealthAnnotations(namespace string) map[string]string {
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
+		log.Debugf("The health annotations of namespace [%s] are not read: %v", namespace, err)
+		return nil
+	}
+	return ns.HealthAnnotations
+}
+
 // fillAppRequestRates aggregates requests rates from metrics fetched from Prometheus, and stores the result in the health map.
 func fillAppRequestRates(allHealth models.NamespaceAppHealth, rates model.Vector) {
 	lblDest := model.LabelName("destination_canonical_service")
@@ -15,6 +15,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
@@ -32,7 +33,15 @@ func TestGetServiceHealth(t *testing.T) {
 	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
 	prom.MockServiceRequestRates("ns", "httpbin", serviceRates)
 	k8s.On("IsOpenShift").Return(true)
-	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{
+		ObjectMeta: meta_v1.ObjectMeta{Annotations: map[string]string{
+			models.RateHealthAnnotation:         `^5\d\d$,20,40,http,inbound`,
+			models.RateIntervalHealthAnnotation: "30m",
+		}},
+	}, nil)
+	k8s.On("GetService", "ns", "httpbin").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Annotations: map[string]string{models.RateHealthAnnotation: `^5\d\d$,1,5,http,inbound`}},
+	}, nil)
 
 	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
 
@@ -51,6 +60,12 @@ func TestGetServiceHealth(t *testing.T) {
 	}
 	assert.Equal(result, health.Requests.Inbound)
 	assert.Equal(emptyResult, health.Requests.Outbound)
+
+	// The annotations of the service override the ones of its namespace
+	assert.Equal(map[string]string{
+		models.RateHealthAnnotation:         `^5\d\d$,1,5,http,inbound`,
+		models.RateIntervalHealthAnnotation: "30m",
+	}, health.HealthAnnotations)
 }
 
 func TestGetAppHealth(t *testing.T) {
@@ -111,6 +126,7 @@ func TestGetWorkloadHealth(t *testing.T) {
 	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
 	k8s.On("GetPods", "ns", "").Return(fakePodsHealthReview(), nil)
 	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+	k8s.On("GetEvents", "ns").Return(fakeEventsHealthReview(), nil)
 
 	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
 	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
@@ -138,6 +154,12 @@ func TestGetWorkloadHealth(t *testing.T) {
 		},
 	}
 	assert.Equal(result, health.Requests.Outbound)
+
+	// The warnings of the workload and of its pods explain its health
+	assert.Len(health.Events, 2)
+	assert.Equal("Unhealthy", health.Events[0].Reason)
+	assert.Equal(models.EventCategoryProbe, health.Events[0].Category)
+	assert.Equal(models.EventCategoryCrashLoop, health.Events[1].Category)
 }
 
 func TestGetAppHealthWithoutIstio(t *testing.T) {
@@ -181,6 +203,7 @@ func TestGetWorkloadHealthWithoutIstio(t *testing.T) {
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
 	k8s.On("GetPods", "ns", "").Return(fakePodsHealthReviewWithoutIstio(), nil)
+	k8s.On("GetEvents", "ns").Return([]core_v1.Event{}, nil)
 
 	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
 	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
@@ -412,6 +435,24 @@ func fakePodsHealthReview() []core_v1.Pod {
 	}
 }
 
+func fakeEventsHealthReview() []core_v1.Event {
+	at := time.Date(2017, 01, 14, 23, 0, 0, 0, time.UTC)
+	event := func(eventType, reason, kind, name string, minutes int) core_v1.Event {
+		return core_v1.Event{
+			Type:           eventType,
+			Reason:         reason,
+			InvolvedObject: core_v1.ObjectReference{Kind: kind, Name: name},
+			LastTimestamp:  meta_v1.NewTime(at.Add(time.Duration(minutes) * time.Minute)),
+		}
+	}
+	return []core_v1.Event{
+		event(core_v1.EventTypeWarning, "BackOff", "Pod", "reviews-v1", 10),
+		event(core_v1.EventTypeNormal, "Pulled", "Pod", "reviews-v1", 15),
+		event(core_v1.EventTypeWarning, "Unhealthy", "Pod", "reviews-v1", 20),
+		event(core_v1.EventTypeWarning, "Unhealthy", "Pod", "ratings-v1", 30),
+	}
+}
+
 func fakePodsHealthReviewWithoutIstio() []core_v1.Pod {
 	return []core_v1.Pod{
 		{
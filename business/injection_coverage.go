@@ -0,0 +1,136 @@
+package business
+
+import (
+	"sort"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Name of the webhooks of the sidecar injector, i.e. "rev.namespace.sidecar-injector.istio.io"
+const sidecarInjectorWebhook = "sidecar-injector.istio.io"
+
+// GetInjectionCoverage returns, for each accessible namespace, the pods in the data plane, with a sidecar or captured
+// by ztunnel, the revisions of their proxies and the reason of the pods outside of it. The rejections of pods by the
+// sidecar injector are read from the events, when the user can list them.
+func (in *MeshService) GetInjectionCoverage() (*models.InjectionCoverage, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "GetInjectionCoverage")
+	defer promtimer.ObserveNow(&err)
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	coverage := &models.InjectionCoverage{Namespaces: []models.NamespaceInjectionCoverage{}}
+	for _, ns := range namespaces {
+		events, eventsErr := in.k8s.GetEvents(ns.Name)
+		if eventsErr != nil {
+			log.Debugf("The injection failures of namespace [%s] are not reported: %v", ns.Name, eventsErr)
+		}
+		coverage.Namespaces = append(coverage.Namespaces, namespaceInjectionCoverage(ns, pods[ns.Name], events))
+	}
+	sort.Slice(coverage.Namespaces, func(i, j int) bool { return coverage.Namespaces[i].Namespace < coverage.Namespaces[j].Namespace })
+	return coverage, nil
+}
+
+func namespaceInjectionCoverage(ns models.Namespace, pods []core_v1.Pod, events []core_v1.Event) models.NamespaceInjectionCoverage {
+	coverage := models.NamespaceInjectionCoverage{
+		Namespace:         ns.Name,
+		Proxies:           []models.ProxyVersionCount{},
+		PodsOutOfMesh:     []models.PodInjection{},
+		InjectionFailures: models.KubernetesEvents{},
+	}
+	if ns.Labels[ambientDataplaneModeLabel] == models.EnrollmentModeAmbient {
+		coverage.Mode = models.EnrollmentModeAmbient
+	} else if revision := namespaceRevision(ns); revision != "" {
+		coverage.Mode = models.EnrollmentModeSidecar
+		coverage.Revision = revision
+	}
+
+	// The controllers whose pods were rejected by the sidecar injector
+	rejected := map[string]bool{}
+	for _, event := range events {
+		if event.Reason == "FailedCreate" && strings.Contains(event.Message, sidecarInjectorWebhook) {
+			rejected[eventObjectKey(event.InvolvedObject.Kind, event.InvolvedObject.Name)] = true
+			var e models.KubernetesEvent
+			e.Parse(event)
+			coverage.InjectionFailures = append(coverage.InjectionFailures, e)
+		}
+	}
+	sort.SliceStable(coverage.InjectionFailures, func(i, j int) bool {
+		return coverage.InjectionFailures[i].LastTimestamp.After(coverage.InjectionFailures[j].LastTimestamp)
+	})
+
+	counts := map[models.ProxyVersionCount]int{}
+	for _, pod := range pods {
+		// The completed pods, i.e. of the jobs, are not running in any data plane
+		if pod.Status.Phase == core_v1.PodSucceeded || pod.Status.Phase == core_v1.PodFailed {
+			continue
+		}
+		coverage.Pods++
+		if revision, version, ok := podProxy(pod); ok {
+			coverage.SidecarPods++
+			counts[models.ProxyVersionCount{Revision: revision, Version: version}]++
+			if coverage.Revision != "" && revision != coverage.Revision {
+				coverage.RevisionDrift = true
+			}
+			continue
+		}
+		if pod.Annotations[ambientRedirectionAnnotation] == "enabled" {
+			coverage.AmbientPods++
+			continue
+		}
+		coverage.PodsOutOfMesh = append(coverage.PodsOutOfMesh, models.PodInjection{Name: pod.Name, Reason: podInjectionReason(coverage.Mode, pod, rejected)})
+	}
+
+	for proxy, count := range counts {
+		proxy.Count = count
+		coverage.Proxies = append(coverage.Proxies, proxy)
+	}
+	sort.Slice(coverage.Proxies, func(i, j int) bool {
+		if coverage.Proxies[i].Revision != coverage.Proxies[j].Revision {
+			return coverage.Proxies[i].Revision < coverage.Proxies[j].Revision
+		}
+		return coverage.Proxies[i].Version < coverage.Proxies[j].Version
+	})
+	sort.Slice(coverage.PodsOutOfMesh, func(i, j int) bool { return coverage.PodsOutOfMesh[i].Name < coverage.PodsOutOfMesh[j].Name })
+	return coverage
+}
+
+// podInjectionReason explains why a pod is outside of the data plane of its namespace
+func podInjectionReason(mode string, pod core_v1.Pod, rejected map[string]bool) string {
+	injection := config.Get().ExternalServices.Istio.IstioInjectionAnnotation
+	// The label of the pod takes precedence over its annotation
+	inject, ok := pod.Labels[injection]
+	if !ok {
+		inject = pod.Annotations[injection]
+	}
+	_, podRevision := pod.Labels[istioRevisionLabel]
+
+	switch {
+	case pod.Spec.HostNetwork:
+		return models.InjectionReasonHostNetwork
+	case mode == models.EnrollmentModeAmbient && pod.Labels[ambientDataplaneModeLabel] == "none":
+		return models.InjectionReasonOptOut
+	case mode != models.EnrollmentModeAmbient && inject == "false":
+		return models.InjectionReasonOptOut
+	case mode == "" && inject != "true" && !podRevision:
+		return models.InjectionReasonNotEnrolled
+	}
+	for _, ref := range pod.OwnerReferences {
+		if rejected[eventObjectKey(ref.Kind, ref.Name)] {
+			return models.InjectionReasonInjectionFailed
+		}
+	}
+	return models.InjectionReasonRestartRequired
+}
@@ -0,0 +1,77 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestNamespaceInjectionCoverage(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ownedPod := func(name, owner string) core_v1.Pod {
+		return core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: name, OwnerReferences: []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: owner}}}}
+	}
+	optOut := ownedPod("ratings-v1-6f855c5fff-2bfd7", "ratings-v1-6f855c5fff")
+	optOut.Labels = map[string]string{"sidecar.istio.io/inject": "false"}
+	hostNetwork := ownedPod("node-exporter-x7k2p", "node-exporter")
+	hostNetwork.Spec.HostNetwork = true
+	completed := ownedPod("migration-4kq2z", "migration")
+	completed.Status.Phase = core_v1.PodSucceeded
+
+	ns := models.Namespace{Name: "travels", Labels: map[string]string{istioRevisionLabel: "1-8-1"}}
+	pods := []core_v1.Pod{
+		fakeProxyPod("cars-v1-7bd7d7d7d7-wnvc9", "1-8-1", "docker.io/istio/proxyv2:1.8.1"),
+		fakeProxyPod("hotels-v1-545db77b95-xq5tc", "default", "docker.io/istio/proxyv2:1.7.4"),
+		ownedPod("flights-v1-5d8f7b6c9d-k2j4h", "flights-v1-5d8f7b6c9d"),
+		ownedPod("insurances-v1-6c9f8d7b5-p9z8x", "insurances-v1-6c9f8d7b5"),
+		optOut,
+		hostNetwork,
+		completed,
+	}
+	events := []core_v1.Event{
+		{
+			Reason:         "FailedCreate",
+			Message:        `Error creating: Internal error occurred: failed calling webhook "rev.namespace.sidecar-injector.istio.io": context deadline exceeded`,
+			InvolvedObject: core_v1.ObjectReference{Kind: "ReplicaSet", Name: "flights-v1-5d8f7b6c9d"},
+		},
+		{
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "insurances-v1-6c9f8d7b5-" is forbidden: exceeded quota`,
+			InvolvedObject: core_v1.ObjectReference{Kind: "ReplicaSet", Name: "insurances-v1-6c9f8d7b5"},
+		},
+	}
+
+	coverage := namespaceInjectionCoverage(ns, pods, events)
+	assert.Equal(models.EnrollmentModeSidecar, coverage.Mode)
+	assert.Equal("1-8-1", coverage.Revision)
+	assert.Equal(6, coverage.Pods)
+	assert.Equal(2, coverage.SidecarPods)
+	assert.Equal(0, coverage.AmbientPods)
+	assert.True(coverage.RevisionDrift)
+	assert.Equal([]models.ProxyVersionCount{{Revision: "1-8-1", Version: "1.8.1", Count: 1}, {Revision: "default", Version: "1.7.4", Count: 1}}, coverage.Proxies)
+	assert.Equal([]models.PodInjection{
+		{Name: "flights-v1-5d8f7b6c9d-k2j4h", Reason: models.InjectionReasonInjectionFailed},
+		{Name: "insurances-v1-6c9f8d7b5-p9z8x", Reason: models.InjectionReasonRestartRequired},
+		{Name: "node-exporter-x7k2p", Reason: models.InjectionReasonHostNetwork},
+		{Name: "ratings-v1-6f855c5fff-2bfd7", Reason: models.InjectionReasonOptOut},
+	}, coverage.PodsOutOfMesh)
+	assert.Len(coverage.InjectionFailures, 1)
+
+	ambient := ownedPod("cars-v1-7bd7d7d7d7-wnvc9", "cars-v1-7bd7d7d7d7")
+	ambient.Annotations = map[string]string{ambientRedirectionAnnotation: "enabled"}
+	coverage = namespaceInjectionCoverage(models.Namespace{Name: "travels", Labels: map[string]string{ambientDataplaneModeLabel: "ambient"}}, []core_v1.Pod{ambient, ownedPod("hotels-v1-545db77b95-xq5tc", "hotels-v1-545db77b95")}, nil)
+	assert.Equal(models.EnrollmentModeAmbient, coverage.Mode)
+	assert.Equal(1, coverage.AmbientPods)
+	assert.Equal([]models.PodInjection{{Name: "hotels-v1-545db77b95-xq5tc", Reason: models.InjectionReasonRestartRequired}}, coverage.PodsOutOfMesh)
+
+	coverage = namespaceInjectionCoverage(models.Namespace{Name: "legacy"}, []core_v1.Pod{ownedPod("app-v1-5d8f7b6c9d-k2j4h", "app-v1-5d8f7b6c9d")}, nil)
+	assert.Equal("", coverage.Mode)
+	assert.Equal([]models.PodInjection{{Name: "app-v1-5d8f7b6c9d-k2j4h", Reason: models.InjectionReasonNotEnrolled}}, coverage.PodsOutOfMesh)
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/sync/errgroup"
 	errors2 "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
@@ -40,6 +42,10 @@ type IstioConfigCriteria struct {
 func (icc IstioConfigCriteria) Include(resource string) bool {
 	// Flag used to skip object that are not used in a query when a WorkloadSelector is present
 	isWorkloadSelector := icc.WorkloadSelector != ""
+	// Excluded types are handled as if they were not installed
+	if kubernetes.IsIstioResourceExcluded(resource) {
+		return false
+	}
 	switch resource {
 	case kubernetes.Gateways:
 		return icc.IncludeGateways
@@ -65,6 +71,9 @@ func (icc IstioConfigCriteria) Include(resource string) bool {
 	return false
 }
 
+// istioConfigListParallelism is the maximum number of kinds fetched at the same time by GetIstioConfigList
+const istioConfigListParallelism = 5
+
 // IstioConfig types used in the IstioConfig New Page Form
 var newIstioConfigTypes = []string{
 	kubernetes.AuthorizationPolicies,
@@ -99,7 +108,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 	}
 
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
-	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces and Auth.NamespaceAccess)
 	if _, err := in.businessLayer.Namespace.GetNamespace(criteria.Namespace); err != nil {
 		return models.IstioConfigList{}, err
 	}
@@ -110,207 +119,57 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		workloadSelector = criteria.WorkloadSelector
 	}
 
-	errChan := make(chan error, 10)
-
-	var wg sync.WaitGroup
-	wg.Add(10)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.Gateways) {
-			var gg []kubernetes.IstioObject
-			var ggErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.Gateways) {
-				gg, ggErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.Gateways, criteria.LabelSelector)
-			} else {
-				gg, ggErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.Gateways, criteria.LabelSelector)
-			}
-			if ggErr == nil {
-				if isWorkloadSelector {
-					gg = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, gg)
-				}
-				(&istioConfigList.Gateways).Parse(gg)
-			} else {
-				errChan <- ggErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.VirtualServices) {
-			var vs []kubernetes.IstioObject
-			var vsErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.VirtualServices) {
-				vs, vsErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.VirtualServices, criteria.LabelSelector)
-			} else {
-				vs, vsErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.VirtualServices, criteria.LabelSelector)
-			}
-			if vsErr == nil {
-				(&istioConfigList.VirtualServices).Parse(vs)
-			} else {
-				errChan <- vsErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.DestinationRules) {
-			var dr []kubernetes.IstioObject
-			var drErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.DestinationRules) {
-				dr, drErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.DestinationRules, criteria.LabelSelector)
-			} else {
-				dr, drErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.DestinationRules, criteria.LabelSelector)
-			}
-			if drErr == nil {
-				(&istioConfigList.DestinationRules).Parse(dr)
-			} else {
-				errChan <- drErr
-			}
+	// Objects of each kind, and whether they are read from the Kiali cache when the namespace is cached
+	kinds := []struct {
+		resource string
+		cached   bool
+		parse    func([]kubernetes.IstioObject)
+	}{
+		{kubernetes.Gateways, true, (&istioConfigList.Gateways).Parse},
+		{kubernetes.VirtualServices, true, (&istioConfigList.VirtualServices).Parse},
+		{kubernetes.DestinationRules, true, (&istioConfigList.DestinationRules).Parse},
+		{kubernetes.ServiceEntries, true, (&istioConfigList.ServiceEntries).Parse},
+		{kubernetes.AuthorizationPolicies, true, (&istioConfigList.AuthorizationPolicies).Parse},
+		{kubernetes.PeerAuthentications, true, (&istioConfigList.PeerAuthentications).Parse},
+		{kubernetes.Sidecars, true, (&istioConfigList.Sidecars).Parse},
+		{kubernetes.WorkloadEntries, false, (&istioConfigList.WorkloadEntries).Parse},
+		{kubernetes.RequestAuthentications, true, (&istioConfigList.RequestAuthentications).Parse},
+		{kubernetes.EnvoyFilters, false, (&istioConfigList.EnvoyFilters).Parse},
+	}
+
+	// Each kind is parsed into its own field of the list, so kinds can be fetched concurrently
+	var g errgroup.Group
+	semaphore := make(chan struct{}, istioConfigListParallelism)
+	for _, kind := range kinds {
+		if !criteria.Include(kind.resource) {
+			continue
 		}
-	}(errChan)
+		kind := kind
+		g.Go(func() error {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
 
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.ServiceEntries) {
-			var se []kubernetes.IstioObject
-			var seErr error
+			var objects []kubernetes.IstioObject
+			var kindErr error
 			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.ServiceEntries) {
-				se, seErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.ServiceEntries, criteria.LabelSelector)
+			if kind.cached && IsResourceCached(criteria.Namespace, kind.resource) {
+				objects, kindErr = kialiCache.GetIstioObjects(criteria.Namespace, kind.resource, criteria.LabelSelector)
 			} else {
-				se, seErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.ServiceEntries, criteria.LabelSelector)
+				objects, kindErr = in.k8s.GetIstioObjects(criteria.Namespace, kind.resource, criteria.LabelSelector)
 			}
-			if seErr == nil {
-				(&istioConfigList.ServiceEntries).Parse(se)
-			} else {
-				errChan <- seErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.AuthorizationPolicies) {
-			var ap []kubernetes.IstioObject
-			var apErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.AuthorizationPolicies) {
-				ap, apErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.AuthorizationPolicies, criteria.LabelSelector)
-			} else {
-				ap, apErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.AuthorizationPolicies, criteria.LabelSelector)
+			if kindErr != nil {
+				return kindErr
 			}
-			if apErr == nil {
-				if isWorkloadSelector {
-					ap = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ap)
-				}
-				(&istioConfigList.AuthorizationPolicies).Parse(ap)
-			} else {
-				errChan <- apErr
+			if isWorkloadSelector {
+				objects = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, objects)
 			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.PeerAuthentications) {
-			var pa []kubernetes.IstioObject
-			var paErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.PeerAuthentications) {
-				pa, paErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.PeerAuthentications, criteria.LabelSelector)
-			} else {
-				pa, paErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.PeerAuthentications, criteria.LabelSelector)
-			}
-			if paErr == nil {
-				if isWorkloadSelector {
-					pa = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, pa)
-				}
-				(&istioConfigList.PeerAuthentications).Parse(pa)
-			} else {
-				errChan <- paErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.Sidecars) {
-			var sc []kubernetes.IstioObject
-			var scErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.Sidecars) {
-				sc, scErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.Sidecars, criteria.LabelSelector)
-			} else {
-				sc, scErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.Sidecars, criteria.LabelSelector)
-			}
-			if scErr == nil {
-				if isWorkloadSelector {
-					sc = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, sc)
-				}
-				(&istioConfigList.Sidecars).Parse(sc)
-			} else {
-				errChan <- scErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.WorkloadEntries) {
-			if we, weErr := in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.WorkloadEntries, criteria.LabelSelector); weErr == nil {
-				(&istioConfigList.WorkloadEntries).Parse(we)
-			} else {
-				errChan <- weErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.RequestAuthentications) {
-			var ra []kubernetes.IstioObject
-			var raErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.RequestAuthentications) {
-				ra, raErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.RequestAuthentications, criteria.LabelSelector)
-			} else {
-				ra, raErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.RequestAuthentications, criteria.LabelSelector)
-			}
-			if raErr == nil {
-				if isWorkloadSelector {
-					ra = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ra)
-				}
-				(&istioConfigList.RequestAuthentications).Parse(ra)
-			} else {
-				errChan <- raErr
-			}
-		}
-	}(errChan)
-
-	go func(errChan chan error) {
-		defer wg.Done()
-		if criteria.Include(kubernetes.EnvoyFilters) {
-			if ef, efErr := in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.EnvoyFilters, criteria.LabelSelector); efErr == nil {
-				if isWorkloadSelector {
-					ef = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ef)
-				}
-				(&istioConfigList.EnvoyFilters).Parse(ef)
-			} else {
-				errChan <- efErr
-			}
-		}
-	}(errChan)
-
-	wg.Wait()
+			kind.parse(objects)
+			return nil
+		})
+	}
 
-	close(errChan)
-	for e := range errChan {
-		if e != nil { // Check that default value wasn't returned
-			err = e // To update the Kiali metric
-			return models.IstioConfigList{}, err
-		}
+	if err = g.Wait(); err != nil {
+		return models.IstioConfigList{}, err
 	}
 
 	return istioConfigList, nil
@@ -509,9 +368,9 @@ func (in *IstioConfigService) DeleteIstioConfigDetail(api, namespace, resourceTy
 
 	err = in.k8s.DeleteIstioObject(api, namespace, resourceType, name)
 
-	// Cache is stopped after a Create/Update/Delete operation to force a refresh
-	if kialiCache != nil && err == nil {
-		kialiCache.RefreshNamespace(namespace)
+	// Reads following the delete wait for the cache to remove the object
+	if err == nil {
+		waitForCache(namespace, resourceType, name, cache.ObjectDeleted())
 	}
 	return err
 }
@@ -578,9 +437,9 @@ func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceTy
 	default:
 		err = fmt.Errorf("object type not found: %v", resourceType)
 	}
-	// Cache is stopped after a Create/Update/Delete operation to force a refresh
-	if kialiCache != nil && err == nil {
-		kialiCache.RefreshNamespace(namespace)
+	// Reads following the write wait for the cache to receive the written version
+	if err == nil {
+		waitForCache(namespace, resourceType, result.GetObjectMeta().Name, cache.ObjectVersion(result.GetObjectMeta().UID, result.GetObjectMeta().ResourceVersion))
 	}
 	return istioConfigDetail, err
 }
@@ -149,6 +149,44 @@ func TestGetIstioConfigList(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestGetIstioConfigListExcludedTypes(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ExcludeIstioTypes = []string{"ServiceEntry"}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	criteria := ParseIstioConfigCriteria("test", "gateways,serviceentries", "", "")
+	assert.False(criteria.Include(kubernetes.ServiceEntries))
+	assert.True(criteria.Include(kubernetes.Gateways))
+
+	configService := mockGetIstioConfigList()
+	istioconfigList, err := configService.GetIstioConfigList(criteria)
+
+	assert.Nil(err)
+	assert.Equal(2, len(istioconfigList.Gateways))
+	assert.Equal(0, len(istioconfigList.ServiceEntries))
+}
+
+func TestGetIstioConfigListError(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "gateways", "").Return(fakeGetGateways(), nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return([]kubernetes.IstioObject{}, fmt.Errorf("forbidden"))
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return(fakeGetDestinationRules(), nil)
+	configService := IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	criteria := ParseIstioConfigCriteria("test", "gateways,virtualservices,destinationrules", "", "")
+	istioconfigList, err := configService.GetIstioConfigList(criteria)
+
+	assert.EqualError(err, "forbidden")
+	assert.Equal(0, len(istioconfigList.Gateways))
+}
+
 func TestGetIstioConfigDetails(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
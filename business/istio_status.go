@@ -1,10 +1,12 @@
 package business
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
 	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/kiali/kiali/config"
@@ -190,6 +192,12 @@ func (iss *IstioStatusService) getStatusOf(ds []apps_v1.Deployment) (IstioCompon
 	// Add missing deployments
 	for comp, isCore := range statusComponents {
 		if _, found := cf[comp]; !found {
+			if comp == "istiod" {
+				if address, external := iss.getExternalControlPlane(); external {
+					log.Debugf("istiod runs outside of the cluster, at [%s]", address)
+					continue
+				}
+			}
 			isc = append(isc, ComponentStatus{
 				Name:   comp,
 				Status: NotFound,
@@ -268,3 +276,88 @@ func getAddonStatus(name string, enabled bool, url string, auth *config.Auth, is
 		}
 	}
 }
+
+// Name of the ConfigMap holding the injection configuration, and its values
+const sidecarInjectorConfigMap = "istio-sidecar-injector"
+
+// getExternalControlPlane detects a control plane running outside of the cluster: in another cluster of the mesh
+// (primary-remote, external istiod) or provided by a managed service. The injection configuration of such a cluster
+// points at the remote istiod, or the istiod Service has no selector and forwards to endpoints managed by hand.
+// The address of the control plane is returned when known.
+func (iss *IstioStatusService) getExternalControlPlane() (string, bool) {
+	istioNamespace := config.Get().IstioNamespace
+
+	var cm *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(istioNamespace) {
+		cm, err = kialiCache.GetConfigMap(istioNamespace, sidecarInjectorConfigMap)
+	} else {
+		cm, err = iss.k8s.GetConfigMap(istioNamespace, sidecarInjectorConfigMap)
+	}
+	if err == nil {
+		if address, external := injectorRemoteAddress(cm.Data["values"]); external {
+			return address, true
+		}
+	} else {
+		log.Debugf("Cannot read the injection configuration: %v", err)
+	}
+
+	var svc *core_v1.Service
+	if IsNamespaceCached(istioNamespace) {
+		svc, err = kialiCache.GetService(istioNamespace, "istiod")
+	} else {
+		svc, err = iss.k8s.GetService(istioNamespace, "istiod")
+	}
+	if err != nil || len(svc.Spec.Selector) > 0 {
+		return "", false
+	}
+	if svc.Spec.Type == core_v1.ServiceTypeExternalName {
+		return svc.Spec.ExternalName, true
+	}
+	var endpoints *core_v1.Endpoints
+	if IsNamespaceCached(istioNamespace) {
+		endpoints, err = kialiCache.GetEndpoints(istioNamespace, "istiod")
+	} else {
+		endpoints, err = iss.k8s.GetEndpoints(istioNamespace, "istiod")
+	}
+	if err != nil {
+		return "", false
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.IP != "" {
+				return address.IP, true
+			}
+			return address.Hostname, true
+		}
+	}
+	return "", false
+}
+
+// injectorRemoteAddress reads the values of the injection configuration, telling whether the proxies are configured
+// with a remote istiod
+func injectorRemoteAddress(rawValues string) (string, bool) {
+	if rawValues == "" {
+		return "", false
+	}
+	values := struct {
+		Global struct {
+			RemotePilotAddress string `json:"remotePilotAddress"`
+		} `json:"global"`
+		IstiodRemote struct {
+			Enabled      bool   `json:"enabled"`
+			InjectionURL string `json:"injectionURL"`
+		} `json:"istiodRemote"`
+	}{}
+	if err := json.Unmarshal([]byte(rawValues), &values); err != nil {
+		log.Debugf("Cannot read the values of the injection configuration: %v", err)
+		return "", false
+	}
+	switch {
+	case values.Global.RemotePilotAddress != "":
+		return values.Global.RemotePilotAddress, true
+	case values.IstiodRemote.InjectionURL != "":
+		return values.IstiodRemote.InjectionURL, true
+	}
+	return "", values.IstiodRemote.Enabled
+}
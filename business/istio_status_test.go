@@ -1,6 +1,7 @@
 package business
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -320,6 +321,9 @@ func assertNotPresent(assert *assert.Assertions, icsl IstioComponentStatus, name
 func mockDeploymentCall(deployments []apps_v1.Deployment) *kubetest.K8SClientMock {
 	k8s := new(kubetest.K8SClientMock)
 	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(deployments, nil)
+	// No external control plane
+	k8s.On("GetConfigMap", "istio-system", "istio-sidecar-injector").Return((*v1.ConfigMap)(nil), errors.New("not found"))
+	k8s.On("GetService", "istio-system", "istiod").Return((*v1.Service)(nil), errors.New("not found"))
 
 	return k8s
 }
@@ -428,3 +432,49 @@ func addonAddMockUrls(baseUrl string, conf *config.Config) *config.Config {
 	conf.ExternalServices.CustomDashboards.Prometheus.URL = baseUrl + "/prometheus-dashboards/mock"
 	return conf
 }
+
+func TestExternalControlPlane(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ds := []apps_v1.Deployment{
+		fakeDeploymentWithStatus("istio-ingressgateway", map[string]string{"app": "istio-ingressgateway"}, healthyStatus),
+	}
+
+	// Injection configuration of a remote cluster
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(ds, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-sidecar-injector").Return(&v1.ConfigMap{Data: map[string]string{
+		"values": `{"global":{"remotePilotAddress":"10.0.0.12"},"istiodRemote":{"enabled":true}}`,
+	}}, nil)
+	iss := IstioStatusService{k8s: k8s}
+	icsl, err := iss.getIstioComponentStatus()
+	assert.NoError(err)
+	assertNotPresent(assert, icsl, "istiod")
+	address, external := iss.getExternalControlPlane()
+	assert.True(external)
+	assert.Equal("10.0.0.12", address)
+
+	// istiod Service forwarding to a managed control plane
+	k8s = new(kubetest.K8SClientMock)
+	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(ds, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-sidecar-injector").Return(&v1.ConfigMap{Data: map[string]string{"values": `{"global":{}}`}}, nil)
+	k8s.On("GetService", "istio-system", "istiod").Return(&v1.Service{}, nil)
+	k8s.On("GetEndpoints", "istio-system", "istiod").Return(&v1.Endpoints{Subsets: []v1.EndpointSubset{
+		{Addresses: []v1.EndpointAddress{{IP: "34.1.2.3"}}},
+	}}, nil)
+	iss = IstioStatusService{k8s: k8s}
+	icsl, err = iss.getIstioComponentStatus()
+	assert.NoError(err)
+	assertNotPresent(assert, icsl, "istiod")
+	address, external = iss.getExternalControlPlane()
+	assert.True(external)
+	assert.Equal("34.1.2.3", address)
+
+	// istiod missing
+	k8s = mockDeploymentCall(ds)
+	iss = IstioStatusService{k8s: k8s}
+	icsl, err = iss.getIstioComponentStatus()
+	assert.NoError(err)
+	assertComponent(assert, icsl, "istiod", NotFound, true)
+}
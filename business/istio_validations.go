@@ -3,6 +3,7 @@ package business
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
@@ -90,6 +91,12 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
+	conf := config.Get()
+	key := validationsResultsKey(namespace, service, istioDetails, services, namespaces, pods, workloadsPerNamespace, gatewaysPerNamespace, mtlsDetails, rbacDetails, deployments)
+	if validations, ok := getCachedValidations(key); ok {
+		return validations, nil
+	}
+
 	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces)
 
 	if service != "" {
@@ -101,10 +108,42 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	if service != "" {
 		validations = validations.FilterBySingleType("service", service)
 	}
+	setCachedValidations(key, validations, time.Duration(conf.KubernetesConfig.ResultsCache.ValidationsTTL)*time.Second)
 
 	return validations, nil
 }
 
+// validationsResultsKey returns the key of the validations in the results cache, which changes as soon as any of the
+// objects used to compute them changes
+func validationsResultsKey(namespace, service string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, namespaces models.Namespaces,
+	pods []core_v1.Pod, workloadsPerNamespace map[string]models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject,
+	mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, deployments []apps_v1.Deployment) string {
+	key := resultsKey{}
+	key.addIstioObjects(istioDetails.VirtualServices, istioDetails.DestinationRules, istioDetails.ServiceEntries, istioDetails.Gateways,
+		istioDetails.Sidecars, istioDetails.RequestAuthentications, mtlsDetails.DestinationRules, mtlsDetails.MeshPeerAuthentications,
+		mtlsDetails.PeerAuthentications, rbacDetails.AuthorizationPolicies)
+	key.addIstioObjects(gatewaysPerNamespace...)
+	key.add(fmt.Sprintf("autoMtls=%t", mtlsDetails.EnabledAutoMtls))
+	for _, ns := range namespaces {
+		key.add("Namespace/" + ns.Name)
+	}
+	for _, s := range services {
+		key.addObjectMeta("Service", s.ObjectMeta)
+	}
+	for _, p := range pods {
+		key.addObjectMeta("Pod", p.ObjectMeta)
+	}
+	for _, d := range deployments {
+		key.addObjectMeta("Deployment", d.ObjectMeta)
+	}
+	for ns, workloads := range workloadsPerNamespace {
+		for _, w := range workloads.Workloads {
+			key.addWorkload(ns, w)
+		}
+	}
+	return key.build("validations", namespace, service)
+}
+
 func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
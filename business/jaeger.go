@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
@@ -181,6 +183,79 @@ func (in *JaegerService) GetErrorTraces(ns, app string, duration time.Duration)
 	return client.GetErrorTraces(ns, app, duration)
 }
 
+// SearchAppTraceQL searches the traces of an app with a TraceQL query
+func (in *JaegerService) SearchAppTraceQL(ns, app string, query models.TraceQLQuery) (*jaeger.TraceQLResponse, error) {
+	client, err := in.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.SearchTraceQL(ns, app, query)
+}
+
+// GetNamespaceTraces fetches the traces of the apps of a namespace, i.e. of the services reporting traces named
+// after the namespace. Traces going through several apps are returned once.
+func (in *JaegerService) GetNamespaceTraces(ns string, query models.TracingQuery) ([]jaegerModels.Trace, error) {
+	client, err := in.client()
+	if err != nil {
+		return nil, err
+	}
+	services, err := client.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	apps := namespaceTracingApps(services, ns)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var fetchErr error
+	seen := make(map[jaegerModels.TraceID]bool)
+	traces := []jaegerModels.Trace{}
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app string) {
+			defer wg.Done()
+			r, err := client.GetAppTraces(ns, app, query)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				fetchErr = err
+				return
+			}
+			for _, trace := range r.Data {
+				if !seen[trace.TraceID] {
+					seen[trace.TraceID] = true
+					traces = append(traces, trace)
+				}
+			}
+		}(app)
+	}
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	return traces, nil
+}
+
+// namespaceTracingApps returns the apps of a namespace among the services reporting traces. Without namespace
+// selector the services are not named after their namespace, then all of them are returned.
+func namespaceTracingApps(services []string, ns string) []string {
+	conf := config.Get()
+	apps := []string{}
+	for _, service := range services {
+		switch {
+		case !conf.ExternalServices.Tracing.NamespaceSelector:
+			apps = append(apps, service)
+		case ns == conf.IstioNamespace:
+			if !strings.Contains(service, ".") {
+				apps = append(apps, service)
+			}
+		case strings.HasSuffix(service, "."+ns):
+			apps = append(apps, strings.TrimSuffix(service, "."+ns))
+		}
+	}
+	return apps
+}
+
 func matchesWorkload(trace *jaegerModels.Trace, namespace, workload string) bool {
 	for _, span := range trace.Spans {
 		if process, ok := trace.Processes[span.ProcessID]; ok {
@@ -0,0 +1,155 @@
+package business
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// Number of trace IDs returned per group of error traces
+const errorTraceGroupSamples = 5
+
+// Maximum length of the fingerprints of error messages
+const errorFingerprintLength = 200
+
+// Variable parts of the error messages, from the most specific to the least specific
+var errorFingerprintPatterns = []struct {
+	regexp      *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*[0-9][0-9a-fA-F]*\b`), "<hex>"},
+	{regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*[0-9][0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+// GetErrorTraceGroups fetches the traces in error of an app, over the given duration, and groups them by failure
+// signature: the service, operation, status code and message of the span at the root of the failure
+func (in *JaegerService) GetErrorTraceGroups(ns, app string, duration time.Duration, limit int) (*models.ErrorTraceGroups, error) {
+	now := time.Now()
+	query := models.TracingQuery{
+		StartMicros: strconv.FormatInt(now.Add(-duration).UnixNano()/1000, 10),
+		EndMicros:   strconv.FormatInt(now.UnixNano()/1000, 10),
+		Limit:       limit,
+	}
+	// Errors are flagged by the status of the spans in OTLP, the traces are filtered after their conversion
+	if config.Get().ExternalServices.Tracing.Provider != config.TracingProviderOTLP {
+		query.Tags = `{"error":"true"}`
+	}
+	r, err := in.GetAppTraces(ns, app, query)
+	if err != nil {
+		return nil, err
+	}
+	traces := []jaegerModels.Trace{}
+	if r != nil {
+		traces = r.Data
+	}
+	return groupErrorTraces(traces), nil
+}
+
+func groupErrorTraces(traces []jaegerModels.Trace) *models.ErrorTraceGroups {
+	result := &models.ErrorTraceGroups{Groups: []models.ErrorTraceGroup{}}
+	type signature struct{ service, operation, statusCode, fingerprint string }
+	groups := map[signature]*models.ErrorTraceGroup{}
+	starts := map[string]int64{}
+	for _, trace := range traces {
+		span := rootCauseSpan(trace)
+		if span == nil {
+			continue
+		}
+		result.Total++
+		starts[string(trace.TraceID)] = traceStartTime(trace)
+
+		service := ""
+		if process, ok := trace.Processes[span.ProcessID]; ok {
+			service = process.ServiceName
+		} else if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		statusCode := spanTag(span, "http.status_code")
+		if statusCode == "" {
+			statusCode = spanTag(span, "grpc.status_code")
+		}
+		message := spanErrorMessage(span)
+		key := signature{service: service, operation: spanOperation(span), statusCode: statusCode, fingerprint: errorFingerprint(message)}
+		group, ok := groups[key]
+		if !ok {
+			group = &models.ErrorTraceGroup{
+				Service:     key.service,
+				Operation:   key.operation,
+				StatusCode:  key.statusCode,
+				Fingerprint: key.fingerprint,
+				Message:     message,
+				TraceIDs:    []string{},
+			}
+			groups[key] = group
+		}
+		group.Count++
+		group.TraceIDs = append(group.TraceIDs, string(trace.TraceID))
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.TraceIDs, func(i, j int) bool {
+			return starts[group.TraceIDs[i]] > starts[group.TraceIDs[j]]
+		})
+		if len(group.TraceIDs) > errorTraceGroupSamples {
+			group.TraceIDs = group.TraceIDs[:errorTraceGroupSamples]
+		}
+		result.Groups = append(result.Groups, *group)
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		gi, gj := result.Groups[i], result.Groups[j]
+		if gi.Count != gj.Count {
+			return gi.Count > gj.Count
+		}
+		return gi.Service+gi.Operation+gi.StatusCode+gi.Fingerprint < gj.Service+gj.Operation+gj.StatusCode+gj.Fingerprint
+	})
+	return result
+}
+
+// rootCauseSpan returns the span at the root of the failure of a trace: a span in error none of the children of
+// which is in error, the earliest one when there are several. It returns nil when no span is in error.
+func rootCauseSpan(trace jaegerModels.Trace) *jaegerModels.Span {
+	parentsInError := map[jaegerModels.SpanID]bool{}
+	for i := range trace.Spans {
+		if !spanInError(&trace.Spans[i]) {
+			continue
+		}
+		for _, ref := range trace.Spans[i].References {
+			if ref.RefType == jaegerModels.ChildOf {
+				parentsInError[ref.SpanID] = true
+			}
+		}
+	}
+	var rootCause *jaegerModels.Span
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		if !spanInError(span) || parentsInError[span.SpanID] {
+			continue
+		}
+		if rootCause == nil || span.StartTime < rootCause.StartTime {
+			rootCause = span
+		}
+	}
+	return rootCause
+}
+
+// errorFingerprint replaces the variable parts of an error message, so that the same error gets the same fingerprint
+func errorFingerprint(message string) string {
+	fingerprint := strings.TrimSpace(message)
+	for _, p := range errorFingerprintPatterns {
+		fingerprint = p.regexp.ReplaceAllString(fingerprint, p.placeholder)
+	}
+	if len(fingerprint) > errorFingerprintLength {
+		fingerprint = fingerprint[:errorFingerprintLength]
+	}
+	return fingerprint
+}
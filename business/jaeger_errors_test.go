@@ -0,0 +1,73 @@
+package business
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func errorSpan(id, parent string, process jaegerModels.ProcessID, start uint64, tags ...jaegerModels.KeyValue) jaegerModels.Span {
+	span := jaegerModels.Span{SpanID: jaegerModels.SpanID(id), ProcessID: process, StartTime: start, OperationName: string(process) + "-op", Tags: tags}
+	if parent != "" {
+		span.References = []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, SpanID: jaegerModels.SpanID(parent)}}
+	}
+	return span
+}
+
+func errorTrace(id string, start uint64, message string) jaegerModels.Trace {
+	isError := jaegerModels.KeyValue{Key: "error", Value: true}
+	return jaegerModels.Trace{
+		TraceID: jaegerModels.TraceID(id),
+		Spans: []jaegerModels.Span{
+			errorSpan("1", "", "productpage", start, isError, jaegerModels.KeyValue{Key: "http.status_code", Value: float64(500)}),
+			errorSpan("2", "1", "reviews", start+10, isError, jaegerModels.KeyValue{Key: "http.status_code", Value: float64(503)}, jaegerModels.KeyValue{Key: "otel.status_description", Value: message}),
+			errorSpan("3", "1", "details", start+20),
+		},
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{
+			"productpage": {ServiceName: "productpage.bookinfo"},
+			"reviews":     {ServiceName: "reviews.bookinfo"},
+			"details":     {ServiceName: "details.bookinfo"},
+		},
+	}
+}
+
+func TestGroupErrorTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	timeout := errorTrace("t3", 3000, "")
+	timeout.Spans[1].Tags = []jaegerModels.KeyValue{{Key: "error", Value: "true"}, {Key: "grpc.status_code", Value: "4"}}
+	traces := []jaegerModels.Trace{
+		errorTrace("t1", 1000, "connection to 10.1.2.3:9080 refused after 3 attempts"),
+		errorTrace("t2", 2000, "connection to 10.1.2.4:9080 refused after 5 attempts"),
+		timeout,
+		// Not in error
+		{TraceID: "t4", Spans: []jaegerModels.Span{errorSpan("1", "", "details", 4000)}},
+	}
+
+	groups := groupErrorTraces(traces)
+	assert.Equal(3, groups.Total)
+	assert.Len(groups.Groups, 2)
+
+	refused := groups.Groups[0]
+	assert.Equal("reviews.bookinfo", refused.Service)
+	assert.Equal("reviews-op", refused.Operation)
+	assert.Equal("503", refused.StatusCode)
+	assert.Equal("connection to <ip> refused after <n> attempts", refused.Fingerprint)
+	assert.Equal("connection to 10.1.2.3:9080 refused after 3 attempts", refused.Message)
+	assert.Equal(2, refused.Count)
+	assert.Equal([]string{"t2", "t1"}, refused.TraceIDs)
+
+	grpc := groups.Groups[1]
+	assert.Equal("4", grpc.StatusCode)
+	assert.Equal("gRPC <n>", grpc.Fingerprint)
+	assert.Equal(1, grpc.Count)
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("order <uuid> not found", errorFingerprint("order 3f2a7c1e-9b4d-4e5f-8a6b-1c2d3e4f5a6b not found"))
+	assert.Equal("bad object <hex> at <hex>", errorFingerprint("bad object 5f3e2a1b at 0x1f"))
+	assert.Equal("deadline exceeded after <n>ms", errorFingerprint(" deadline exceeded after 250ms "))
+	assert.Equal("dial tcp <ip>: connect: connection refused", errorFingerprint("dial tcp 172.30.0.12:3306: connect: connection refused"))
+}
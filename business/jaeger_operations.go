@@ -0,0 +1,159 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/models"
+)
+
+// Number of error messages returned per operation
+const operationTopErrors = 5
+
+// GetServiceOperationStats aggregates the spans of a service by operation, returning the requests, errors and
+// duration percentiles of each operation, sorted by decreasing number of requests
+func (in *JaegerService) GetServiceOperationStats(ns, service string, query models.TracingQuery) ([]models.OperationStats, error) {
+	spans, err := in.GetServiceSpans(ns, service, query)
+	if err != nil {
+		return nil, err
+	}
+	jSpans := make([]jaegerModels.Span, 0, len(spans))
+	for _, span := range spans {
+		jSpans = append(jSpans, span.Span)
+	}
+	return operationStats(jSpans), nil
+}
+
+func operationStats(spans []jaegerModels.Span) []models.OperationStats {
+	durations := map[string][]uint64{}
+	errors := map[string]map[string]int{}
+	stats := map[string]*models.OperationStats{}
+	for _, span := range spans {
+		// Outbound requests are the operations of the called services
+		if kind := spanTag(&span, "span.kind"); kind == "client" || kind == "producer" {
+			continue
+		}
+		operation := spanOperation(&span)
+		stat, ok := stats[operation]
+		if !ok {
+			stat = &models.OperationStats{Operation: operation, TopErrors: []models.OperationError{}}
+			stats[operation] = stat
+			errors[operation] = map[string]int{}
+		}
+		stat.Requests++
+		durations[operation] = append(durations[operation], span.Duration)
+		if spanInError(&span) {
+			stat.Errors++
+			errors[operation][spanErrorMessage(&span)]++
+		}
+	}
+
+	result := make([]models.OperationStats, 0, len(stats))
+	for operation, stat := range stats {
+		d := durations[operation]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		stat.P50 = durationPercentile(d, 0.5)
+		stat.P95 = durationPercentile(d, 0.95)
+		stat.P99 = durationPercentile(d, 0.99)
+		stat.ErrorRatio = float64(stat.Errors) / float64(stat.Requests)
+		for message, count := range errors[operation] {
+			stat.TopErrors = append(stat.TopErrors, models.OperationError{Message: message, Count: count})
+		}
+		sort.Slice(stat.TopErrors, func(i, j int) bool {
+			if stat.TopErrors[i].Count != stat.TopErrors[j].Count {
+				return stat.TopErrors[i].Count > stat.TopErrors[j].Count
+			}
+			return stat.TopErrors[i].Message < stat.TopErrors[j].Message
+		})
+		if len(stat.TopErrors) > operationTopErrors {
+			stat.TopErrors = stat.TopErrors[:operationTopErrors]
+		}
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+// spanOperation returns the route of the span when the instrumentation reports it, otherwise its operation name.
+// For envoy spans, the operation name is like "reviews.bookinfo.svc.cluster.local:9080/*".
+func spanOperation(span *jaegerModels.Span) string {
+	if route := spanTag(span, "http.route"); route != "" {
+		if method := spanTag(span, "http.method"); method != "" {
+			return method + " " + route
+		}
+		return route
+	}
+	return span.OperationName
+}
+
+func spanInError(span *jaegerModels.Span) bool {
+	for _, tag := range span.Tags {
+		if tag.Key == "error" {
+			if v, ok := tag.Value.(bool); ok {
+				return v
+			}
+			return fmt.Sprintf("%v", tag.Value) == "true"
+		}
+	}
+	return false
+}
+
+// spanErrorMessage returns the description of the error of a span, from its status, its error logs or, at last, its
+// response code
+func spanErrorMessage(span *jaegerModels.Span) string {
+	for _, key := range []string{"otel.status_description", "error.message", "exception.message"} {
+		if message := spanTag(span, key); message != "" {
+			return message
+		}
+	}
+	for _, log := range span.Logs {
+		for _, field := range log.Fields {
+			if field.Key == "message" || field.Key == "exception.message" || field.Key == "error.object" {
+				if message := fmt.Sprintf("%v", field.Value); message != "" {
+					return message
+				}
+			}
+		}
+	}
+	if code := spanTag(span, "http.status_code"); code != "" {
+		message := "HTTP " + code
+		if flags := spanTag(span, "response_flags"); flags != "" && flags != "-" {
+			message += " " + flags
+		}
+		return message
+	}
+	if code := spanTag(span, "grpc.status_code"); code != "" {
+		return "gRPC " + code
+	}
+	return "error"
+}
+
+func spanTag(span *jaegerModels.Span, key string) string {
+	for _, tag := range span.Tags {
+		if tag.Key == key {
+			return fmt.Sprintf("%v", tag.Value)
+		}
+	}
+	return ""
+}
+
+// durationPercentile returns the nearest-rank percentile of sorted span durations, converted from microseconds to
+// milliseconds
+func durationPercentile(sorted []uint64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return float64(sorted[rank]) / 1000
+}
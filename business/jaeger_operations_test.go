@@ -0,0 +1,57 @@
+package business
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func operationSpan(operation string, duration uint64, tags ...jaegerModels.KeyValue) jaegerModels.Span {
+	return jaegerModels.Span{OperationName: operation, Duration: duration, Tags: tags}
+}
+
+func TestOperationStats(t *testing.T) {
+	assert := assert.New(t)
+
+	spans := []jaegerModels.Span{
+		operationSpan("GET /items", 3000, jaegerModels.KeyValue{Key: "http.route", Value: "/items/{id}"}, jaegerModels.KeyValue{Key: "http.method", Value: "GET"}),
+		// Outbound requests are skipped
+		operationSpan("ratings.bookinfo.svc.cluster.local:9080/*", 1000, jaegerModels.KeyValue{Key: "span.kind", Value: "client"}),
+	}
+	for i := 1; i <= 100; i++ {
+		tags := []jaegerModels.KeyValue{{Key: "span.kind", Value: "server"}}
+		switch {
+		case i%10 == 0:
+			tags = append(tags, jaegerModels.KeyValue{Key: "error", Value: true}, jaegerModels.KeyValue{Key: "http.status_code", Value: float64(503)}, jaegerModels.KeyValue{Key: "response_flags", Value: "UF"})
+		case i%25 == 1:
+			tags = append(tags, jaegerModels.KeyValue{Key: "error", Value: true}, jaegerModels.KeyValue{Key: "otel.status_description", Value: "timeout"})
+		}
+		spans = append(spans, operationSpan("reviews.bookinfo.svc.cluster.local:9080/*", uint64(i*1000), tags...))
+	}
+
+	stats := operationStats(spans)
+	assert.Len(stats, 2)
+
+	reviews := stats[0]
+	assert.Equal("reviews.bookinfo.svc.cluster.local:9080/*", reviews.Operation)
+	assert.Equal(100, reviews.Requests)
+	assert.Equal(14, reviews.Errors)
+	assert.Equal(0.14, reviews.ErrorRatio)
+	assert.Equal(50.0, reviews.P50)
+	assert.Equal(95.0, reviews.P95)
+	assert.Equal(99.0, reviews.P99)
+	assert.Len(reviews.TopErrors, 2)
+	assert.Equal("HTTP 503 UF", reviews.TopErrors[0].Message)
+	assert.Equal(10, reviews.TopErrors[0].Count)
+	assert.Equal("timeout", reviews.TopErrors[1].Message)
+	assert.Equal(4, reviews.TopErrors[1].Count)
+
+	items := stats[1]
+	assert.Equal("GET /items/{id}", items.Operation)
+	assert.Equal(1, items.Requests)
+	assert.Equal(0.0, items.ErrorRatio)
+	assert.Equal(3.0, items.P50)
+	assert.Equal(3.0, items.P99)
+	assert.Empty(items.TopErrors)
+}
@@ -0,0 +1,127 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Spans are ingested some time after they end: each poll looks back over this delay so that late traces are not
+// missed, the traces already sent being skipped.
+const traceTailLookback = 10 * time.Second
+
+// Maximum number of traces fetched by each poll
+const traceTailLimit = 100
+
+// TraceTailQuery selects the traces of a trace tail
+type TraceTailQuery struct {
+	Namespace string
+	// "app", "service" or "workload"
+	Kind string
+	Name string
+	// Traces starting at or before the cursor, in microseconds since epoch, are not sent
+	Since int64
+	// Interval between the polls of the tracing backend
+	Interval time.Duration
+}
+
+// TailTraces polls the tracing backend for the new traces of an app, service or workload, calling send for each of
+// them in the order of their start time, until the context is done or send fails. Each trace is sent along with
+// its cursor: resuming the tail from that cursor sends the traces after it.
+func (in *JaegerService) TailTraces(ctx context.Context, q TraceTailQuery, send func(trace jaegerModels.Trace, cursor int64) error) error {
+	if q.Kind != "app" && q.Kind != "service" && q.Kind != "workload" {
+		return fmt.Errorf("invalid kind [%s] of trace tail", q.Kind)
+	}
+
+	cursor := q.Since
+	// Traces sent, by start time, to skip them on the next polls
+	sent := map[jaegerModels.TraceID]int64{}
+	ticker := time.NewTicker(q.Interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		start := cursor - traceTailLookback.Microseconds()
+		query := models.TracingQuery{
+			StartMicros: strconv.FormatInt(start, 10),
+			EndMicros:   strconv.FormatInt(now.UnixNano()/1000, 10),
+			Limit:       traceTailLimit,
+		}
+		r, err := in.getTraces(q.Kind, q.Namespace, q.Name, query)
+		if err != nil {
+			return err
+		}
+		traces := []jaegerModels.Trace{}
+		if r != nil {
+			traces = r.Data
+		}
+
+		newTraces := []jaegerModels.Trace{}
+		starts := map[jaegerModels.TraceID]int64{}
+		for _, trace := range traces {
+			traceStart := traceStartTime(trace)
+			// Traces are matched by any of their spans, traces starting before the window may have been sent already
+			if _, isSent := sent[trace.TraceID]; isSent || traceStart <= q.Since || traceStart < start {
+				continue
+			}
+			starts[trace.TraceID] = traceStart
+			newTraces = append(newTraces, trace)
+		}
+		sort.SliceStable(newTraces, func(i, j int) bool {
+			return starts[newTraces[i].TraceID] < starts[newTraces[j].TraceID]
+		})
+		for _, trace := range newTraces {
+			if err := send(trace, starts[trace.TraceID]); err != nil {
+				return err
+			}
+			sent[trace.TraceID] = starts[trace.TraceID]
+			if starts[trace.TraceID] > cursor {
+				cursor = starts[trace.TraceID]
+			}
+		}
+		// Traces out of the lookback window won't be returned again
+		for id, traceStart := range sent {
+			if traceStart < cursor-traceTailLookback.Microseconds() {
+				delete(sent, id)
+			}
+		}
+		if len(traces) == traceTailLimit {
+			log.Debugf("Trace tail of %s [%s/%s] reached the limit of %d traces per poll", q.Kind, q.Namespace, q.Name, traceTailLimit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (in *JaegerService) getTraces(kind, ns, name string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
+	switch kind {
+	case "service":
+		return in.GetServiceTraces(ns, name, query)
+	case "workload":
+		return in.GetWorkloadTraces(ns, name, query)
+	default:
+		return in.GetAppTraces(ns, name, query)
+	}
+}
+
+// traceStartTime returns the start time of the earliest span of a trace, in microseconds since epoch
+func traceStartTime(trace jaegerModels.Trace) int64 {
+	var start uint64
+	for i, span := range trace.Spans {
+		if i == 0 || span.StartTime < start {
+			start = span.StartTime
+		}
+	}
+	return int64(start)
+}
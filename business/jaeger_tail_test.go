@@ -0,0 +1,64 @@
+package business
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/jaeger/jaegertest"
+	"github.com/kiali/kiali/models"
+)
+
+func tailTrace(id string, start uint64) jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: jaegerModels.TraceID(id),
+		Spans:   []jaegerModels.Span{{StartTime: start + 500}, {StartTime: start}},
+	}
+}
+
+func TestTailTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	since := time.Now().UnixNano() / 1000
+	old := tailTrace("old", uint64(since-1000))
+	first := tailTrace("first", uint64(since+2000))
+	second := tailTrace("second", uint64(since+1000))
+	late := tailTrace("late", uint64(since+1500))
+
+	client := new(jaegertest.JaegerClientMock)
+	client.On("GetAppTraces", "bookinfo", "reviews", mock.Anything).Return(&jaeger.JaegerResponse{Data: []jaegerModels.Trace{first, old, second}}, nil).Once()
+	client.On("GetAppTraces", "bookinfo", "reviews", mock.Anything).Return(&jaeger.JaegerResponse{Data: []jaegerModels.Trace{late, first, second}}, nil)
+	service := JaegerService{loader: func() (jaeger.ClientInterface, error) { return client, nil }}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sent := []jaegerModels.TraceID{}
+	cursors := []int64{}
+	err := service.TailTraces(ctx, TraceTailQuery{Namespace: "bookinfo", Kind: "app", Name: "reviews", Since: since, Interval: 10 * time.Millisecond},
+		func(trace jaegerModels.Trace, cursor int64) error {
+			sent = append(sent, trace.TraceID)
+			cursors = append(cursors, cursor)
+			if len(sent) == 3 {
+				cancel()
+			}
+			return nil
+		})
+	assert.NoError(err)
+
+	// Sorted by start time, sent once, late traces included
+	assert.Equal([]jaegerModels.TraceID{"second", "first", "late"}, sent)
+	assert.Equal([]int64{since + 1000, since + 2000, since + 1500}, cursors)
+
+	// The second poll looks back from the cursor
+	query := client.Calls[1].Arguments.Get(2).(models.TracingQuery)
+	assert.Equal(strconv.FormatInt(since+2000-traceTailLookback.Microseconds(), 10), query.StartMicros)
+
+	err = service.TailTraces(ctx, TraceTailQuery{Kind: "pod"}, nil)
+	assert.Error(err)
+}
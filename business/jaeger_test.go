@@ -6,6 +6,7 @@ import (
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/jaeger"
 )
 
@@ -159,3 +160,17 @@ func TestTracesToSpanWithWorkloadFilter(t *testing.T) {
 	assert.Equal("t2_process_2", string(spans[0].ProcessID))
 	assert.Equal("t2_process_3", string(spans[1].ProcessID))
 }
+
+func TestNamespaceTracingApps(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	services := []string{"productpage.bookinfo", "reviews.bookinfo", "ratings.other", "istio-ingressgateway", "jaeger-query"}
+	assert.Equal([]string{"productpage", "reviews"}, namespaceTracingApps(services, "bookinfo"))
+	assert.Equal([]string{"istio-ingressgateway", "jaeger-query"}, namespaceTracingApps(services, "istio-system"))
+
+	conf.ExternalServices.Tracing.NamespaceSelector = false
+	config.Set(conf)
+	assert.Equal(services, namespaceTracingApps(services, "bookinfo"))
+}
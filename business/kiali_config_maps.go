@@ -0,0 +1,68 @@
+package business
+
+import (
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// Serializes the updates of the ConfigMaps of Kiali by this replica
+var kialiConfigMapsLock sync.Mutex
+
+// readKialiConfigMap reads a key of a ConfigMap of the namespace of Kiali, with the service account of Kiali. A nil
+// ConfigMap is returned if it doesn't exist yet.
+func readKialiConfigMap(k8s kubernetes.ClientInterface, name, key string) (*core_v1.ConfigMap, string, error) {
+	configMap, err := k8s.GetConfigMap(config.Get().Deployment.Namespace, name)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	return configMap, configMap.Data[key], nil
+}
+
+// updateKialiConfigMap applies the given change to a key of a ConfigMap of the namespace of Kiali, and persists the
+// result. The ConfigMap is created when it doesn't exist yet.
+func updateKialiConfigMap(name, key string, change func(data string) (string, error)) error {
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return err
+	}
+
+	kialiConfigMapsLock.Lock()
+	defer kialiConfigMapsLock.Unlock()
+
+	configMap, data, err := readKialiConfigMap(k8s, name, key)
+	if err != nil {
+		return err
+	}
+	if data, err = change(data); err != nil {
+		return err
+	}
+
+	namespace := config.Get().Deployment.Namespace
+	if configMap == nil {
+		configMap = &core_v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "kiali"},
+			},
+			Data: map[string]string{key: data},
+		}
+		_, err = k8s.CreateConfigMap(namespace, configMap)
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = data
+	_, err = k8s.UpdateConfigMap(namespace, configMap)
+	return err
+}
@@ -0,0 +1,149 @@
+package business
+
+import (
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Number of warning events given with the health of a workload, the latest ones
+const maxHealthEvents = 5
+
+// EventService deals with the Kubernetes events of the workloads and services: scheduling failures, OOM kills, probe
+// failures, image pull errors... which explain most of the unhealthy workloads. The events are read with the
+// credentials of the user.
+type EventService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// GetNamespaceEvents returns the events of the objects of a namespace, the latest first
+func (in *EventService) GetNamespaceEvents(namespace string, warningsOnly bool) (models.KubernetesEvents, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "EventService", "GetNamespaceEvents")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var events []core_v1.Event
+	if events, err = in.k8s.GetEvents(namespace); err != nil {
+		return nil, err
+	}
+	return filterEvents(events, nil, warningsOnly), nil
+}
+
+// GetWorkloadEvents returns the events of a workload, of its pods and of the controllers of its pods (i.e. the
+// ReplicaSets of a Deployment), the latest first
+func (in *EventService) GetWorkloadEvents(namespace, workload string, warningsOnly bool) (models.KubernetesEvents, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "EventService", "GetWorkloadEvents")
+	defer promtimer.ObserveNow(&err)
+
+	var w *models.Workload
+	if w, err = fetchWorkload(in.businessLayer, namespace, workload, ""); err != nil {
+		return nil, err
+	}
+	var events []core_v1.Event
+	if events, err = in.k8s.GetEvents(namespace); err != nil {
+		return nil, err
+	}
+	return filterEvents(events, workloadObjects(w), warningsOnly), nil
+}
+
+// GetServiceEvents returns the events of a service, of its endpoints and of the pods it selects, the latest first
+func (in *EventService) GetServiceEvents(namespace, service string, warningsOnly bool) (models.KubernetesEvents, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "EventService", "GetServiceEvents")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var svc *core_v1.Service
+	if svc, err = in.k8s.GetService(namespace, service); err != nil {
+		return nil, err
+	}
+	objects := map[string]bool{
+		eventObjectKey("Service", svc.Name):   true,
+		eventObjectKey("Endpoints", svc.Name): true,
+	}
+	if len(svc.Spec.Selector) > 0 {
+		var pods []core_v1.Pod
+		if pods, err = in.k8s.GetPods(namespace, labels.Set(svc.Spec.Selector).String()); err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			objects[eventObjectKey("Pod", pod.Name)] = true
+		}
+	}
+
+	var events []core_v1.Event
+	if events, err = in.k8s.GetEvents(namespace); err != nil {
+		return nil, err
+	}
+	return filterEvents(events, objects, warningsOnly), nil
+}
+
+// getHealthEvents returns the latest warning events of each workload, to explain their health. The events are
+// optional in the health: they are omitted when the user cannot list them.
+func (in *EventService) getHealthEvents(namespace string, ws models.Workloads) map[string]models.KubernetesEvents {
+	events, err := in.k8s.GetEvents(namespace)
+	if err != nil {
+		log.Debugf("The events of namespace [%s] are not added to the health: %v", namespace, err)
+		return nil
+	}
+	healthEvents := map[string]models.KubernetesEvents{}
+	for _, w := range ws {
+		workloadEvents := filterEvents(events, workloadObjects(w), true)
+		if len(workloadEvents) > maxHealthEvents {
+			workloadEvents = workloadEvents[:maxHealthEvents]
+		}
+		if len(workloadEvents) > 0 {
+			healthEvents[w.Name] = workloadEvents
+		}
+	}
+	return healthEvents
+}
+
+func eventObjectKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// workloadObjects returns the keys of the objects of a workload: itself, its pods and their controllers
+func workloadObjects(w *models.Workload) map[string]bool {
+	objects := map[string]bool{eventObjectKey(w.Type, w.Name): true}
+	for _, pod := range w.Pods {
+		objects[eventObjectKey("Pod", pod.Name)] = true
+		for _, ref := range pod.CreatedBy {
+			objects[eventObjectKey(ref.Kind, ref.Name)] = true
+		}
+	}
+	return objects
+}
+
+// filterEvents keeps the events of the given objects, or all of them when no object is given, the latest first
+func filterEvents(events []core_v1.Event, objects map[string]bool, warningsOnly bool) models.KubernetesEvents {
+	filtered := models.KubernetesEvents{}
+	for _, event := range events {
+		if warningsOnly && event.Type != core_v1.EventTypeWarning {
+			continue
+		}
+		if objects != nil && !objects[eventObjectKey(event.InvolvedObject.Kind, event.InvolvedObject.Name)] {
+			continue
+		}
+		var e models.KubernetesEvent
+		e.Parse(event)
+		filtered = append(filtered, e)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].LastTimestamp.After(filtered[j].LastTimestamp)
+	})
+	return filtered
+}
@@ -0,0 +1,66 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetServiceEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+	kialiCache = nil
+
+	at := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := func(eventType, reason, message, kind, name string, minutes int) core_v1.Event {
+		return core_v1.Event{
+			Type:           eventType,
+			Reason:         reason,
+			Message:        message,
+			InvolvedObject: core_v1.ObjectReference{Kind: kind, Name: name},
+			LastTimestamp:  meta_v1.NewTime(at.Add(time.Duration(minutes) * time.Minute)),
+		}
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}, nil)
+	k8s.On("GetPods", "bookinfo", "app=reviews").Return([]core_v1.Pod{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-545db77b95-wnvc9"}},
+	}, nil)
+	k8s.On("GetEvents", "bookinfo").Return([]core_v1.Event{
+		event(core_v1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available: 3 Insufficient memory.", "Pod", "reviews-v1-545db77b95-wnvc9", 1),
+		event(core_v1.EventTypeWarning, "Failed", `Failed to pull image "reviews:v4"`, "Pod", "reviews-v1-545db77b95-wnvc9", 3),
+		event(core_v1.EventTypeNormal, "Scheduled", "Successfully assigned", "Pod", "reviews-v1-545db77b95-wnvc9", 2),
+		event(core_v1.EventTypeWarning, "FailedToUpdateEndpoint", "Failed to update endpoint", "Endpoints", "reviews", 5),
+		event(core_v1.EventTypeWarning, "Unhealthy", "Readiness probe failed", "Pod", "ratings-v1-6f855c5fff-2bfd7", 4),
+	}, nil)
+
+	service := NewWithBackends(k8s, nil, nil).Event
+
+	events, err := service.GetServiceEvents("bookinfo", "reviews", false)
+	require.NoError(err)
+	require.Len(events, 4)
+	assert.Equal("Endpoints", events[0].ObjectKind)
+	assert.Equal(models.EventCategoryImage, events[1].Category)
+	assert.Equal("Scheduled", events[2].Reason)
+	assert.Equal(models.EventCategoryScheduling, events[3].Category)
+	assert.Equal(int32(1), events[3].Count)
+
+	events, err = service.GetServiceEvents("bookinfo", "reviews", true)
+	require.NoError(err)
+	assert.Len(events, 3)
+}
@@ -2,17 +2,23 @@ package business
 
 import (
 	"sync"
+	"time"
 
+	"github.com/kiali/kiali/alertmanager"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/loki"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
 )
 
 // Layer is a container for fast access to inner services
 type Layer struct {
+	ApiToken       ApiTokenService
 	Svc            SvcService
 	Health         HealthService
 	Validations    IstioValidationsService
@@ -27,6 +33,17 @@ type Layer struct {
 	Iter8          Iter8Service
 	IstioStatus    IstioStatusService
 	ProxyStatus    ProxyStatus
+	SLO            SLOService
+	Mesh           MeshService
+	Wizard         WizardService
+	Debug          DebugService
+	Cluster        ClusterService
+	Federation     FederationService
+	SavedView      SavedViewService
+	Preferences    UserPreferencesService
+	Alert          AlertService
+	Event          EventService
+	Change         ChangeService
 }
 
 // Global clientfactory and prometheus clients.
@@ -43,6 +60,7 @@ func initKialiCache() {
 			kialiCache = cache
 		}
 	}
+	resultsCacheOnce.Do(initResultsCache)
 	if excludedWorkloads == nil {
 		excludedWorkloads = make(map[string]bool)
 		for _, w := range config.Get().KubernetesConfig.ExcludeWorkloads {
@@ -64,8 +82,37 @@ func IsResourceCached(namespace string, resource string) bool {
 	return ok
 }
 
+// waitForCache waits until the Kiali cache receives an object written by Kiali, so the reads following the write
+// are consistent with it. If the informer doesn't deliver the object in time, the cache of the namespace is
+// refreshed.
+func waitForCache(namespace, informerType, name string, condition cache.ObjectCondition) {
+	if kialiCache == nil {
+		return
+	}
+	timeout := time.Duration(config.Get().KubernetesConfig.CacheWriteTimeout) * time.Second
+	if !kialiCache.WaitForObject(namespace, informerType, name, condition, timeout) {
+		log.Warningf("Kiali cache didn't receive [%s: %s/%s] in %v, refreshing the cache of the namespace", informerType, namespace, name, timeout)
+		kialiCache.RefreshNamespace(namespace)
+	}
+}
+
 // Get the business.Layer
 func Get(token string) (*Layer, error) {
+	return newLayer(token, func(cf kubernetes.ClientFactory) (kubernetes.ClientInterface, error) {
+		// Creates a new k8s client based on the current users token
+		return cf.GetClient(token)
+	})
+}
+
+// GetImpersonating returns the business.Layer of a request authenticated with the given token on behalf of the given
+// user: the cluster applies the permissions of the user, not the ones of the token
+func GetImpersonating(token string, user models.UserIdentity) (*Layer, error) {
+	return newLayer(token, func(cf kubernetes.ClientFactory) (kubernetes.ClientInterface, error) {
+		return cf.GetImpersonatingClient(token, user.Username, user.Groups)
+	})
+}
+
+func newLayer(token string, getClient func(kubernetes.ClientFactory) (kubernetes.ClientInterface, error)) (*Layer, error) {
 	// Kiali Cache will be initialized once at first use of Business layer
 	once.Do(initKialiCache)
 
@@ -78,8 +125,7 @@ func Get(token string) (*Layer, error) {
 		clientFactory = userClient
 	}
 
-	// Creates a new k8s client based on the current users token
-	k8s, err := clientFactory.GetClient(token)
+	k8s, err := getClient(clientFactory)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +144,14 @@ func Get(token string) (*Layer, error) {
 		return jaeger.NewClient(token)
 	}
 
-	return NewWithBackends(k8s, prometheusClient, jaegerLoader), nil
+	layer := NewWithBackends(k8s, prometheusClient, jaegerLoader)
+	layer.Workload.loki = func() (loki.ClientInterface, error) {
+		return loki.NewClient(token)
+	}
+	layer.Alert.alertmanager = func() (alertmanager.ClientInterface, error) {
+		return alertmanager.NewClient(token)
+	}
+	return layer, nil
 }
 
 // SetWithBackends allows for specifying the ClientFactory and Prometheus clients to be used.
@@ -111,6 +164,7 @@ func SetWithBackends(cf kubernetes.ClientFactory, prom prometheus.ClientInterfac
 // NewWithBackends creates the business layer using the passed k8s and prom clients
 func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInterface, jaegerClient JaegerLoader) *Layer {
 	temporaryLayer := &Layer{}
+	temporaryLayer.ApiToken = ApiTokenService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
@@ -121,15 +175,29 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer.Jaeger = JaegerService{loader: jaegerClient, businessLayer: temporaryLayer}
 	temporaryLayer.k8s = k8s
 	temporaryLayer.OpenshiftOAuth = OpenshiftOAuthService{k8s: k8s}
-	temporaryLayer.TLS = TLSService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.TLS = TLSService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.Iter8 = Iter8Service{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s}
 	temporaryLayer.ProxyStatus = ProxyStatus{k8s: k8s}
+	temporaryLayer.SLO = SLOService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Mesh = MeshService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Wizard = WizardService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Debug = DebugService{k8s: k8s}
+	temporaryLayer.Cluster = ClusterService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Federation = FederationService{k8s: k8s}
+	temporaryLayer.SavedView = SavedViewService{}
+	temporaryLayer.Preferences = UserPreferencesService{}
+	temporaryLayer.Alert = AlertService{businessLayer: temporaryLayer}
+	temporaryLayer.Event = EventService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.Change = ChangeService{k8s: k8s, businessLayer: temporaryLayer}
 
 	return temporaryLayer
 }
 
 func Stop() {
+	StopLeaderJobs()
+	StopClusterRegistrySync()
+	monitoringdashboards.StopWatchingConfigMaps()
 	if kialiCache != nil {
 		kialiCache.Stop()
 	}
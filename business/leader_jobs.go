@@ -0,0 +1,142 @@
+package business
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/leaderelection"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+)
+
+// leaderJob is a background job run by one replica of Kiali only, the leader, until the context is done
+type leaderJob struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// The background jobs of the leader. The other replicas serve the requests only.
+// Kiali doesn't poll the control plane nor refresh an Istio registry in the background: the control plane and the
+// registry are read on request, through the cache of each replica. The periodic tasks that are not leader jobs fill
+// the memory of their replica, so each replica runs them: the informers of the namespaces of the Kubernetes cache
+// (kubernetes/cache syncNamespaces), the metrics of its status, and the sync of the registered clusters
+// (StartClusterRegistrySync), which applies the clusters registered by any replica to the configuration of this one.
+var leaderJobs = []leaderJob{
+	{name: "validations sweep", run: sweepValidations},
+	{name: "wizard changes expiration", run: expireWizardChanges},
+}
+
+// Stops the background jobs, and the leader election
+var stopLeaderJobs context.CancelFunc
+
+// StartLeaderJobs runs the background jobs once this replica of Kiali is elected leader, and stops them when it
+// loses the leadership. Without the leader election, the replica runs the jobs right away.
+func StartLeaderJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopLeaderJobs = cancel
+
+	if !config.Get().KubernetesConfig.LeaderElection.Enabled {
+		go runLeaderJobs(ctx)
+		return
+	}
+
+	// The name of the pod
+	identity, err := os.Hostname()
+	if err != nil {
+		log.Errorf("Kiali cannot take part in the leader election, the background jobs are not run: %v", err)
+		return
+	}
+	elector, err := kubernetes.NewLeaderElector(identity, leaderelection.LeaderCallbacks{
+		OnStartedLeading: runLeaderJobs,
+		OnStoppedLeading: func() {
+			log.Infof("Kiali replica [%s] stopped leading", identity)
+		},
+		OnNewLeader: func(leader string) {
+			log.Infof("Kiali replica [%s] is the leader", leader)
+		},
+	})
+	if err != nil {
+		log.Errorf("Kiali cannot take part in the leader election, the background jobs are not run: %v", err)
+		return
+	}
+	go func() {
+		// Run returns when the leadership is lost: the replica campaigns again
+		for ctx.Err() == nil {
+			elector.Run(ctx)
+		}
+	}()
+}
+
+// StopLeaderJobs stops the background jobs, and releases the leadership
+func StopLeaderJobs() {
+	if stopLeaderJobs != nil {
+		stopLeaderJobs()
+		stopLeaderJobs = nil
+	}
+}
+
+func runLeaderJobs(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range leaderJobs {
+		wg.Add(1)
+		go func(job leaderJob) {
+			defer wg.Done()
+			log.Debugf("Starting background job [%s]", job.name)
+			job.run(ctx)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// sweepValidations periodically computes the validations of all the namespaces accessible to Kiali, which stores
+// them in the results cache: shared by the replicas, the requests do not need to compute them.
+func sweepValidations(ctx context.Context) {
+	period := time.Duration(config.Get().KubernetesConfig.ResultsCache.ValidationsSweepPeriod) * time.Second
+	if period <= 0 {
+		return
+	}
+	once.Do(initKialiCache)
+	if resultsCache == nil {
+		return
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		sweepValidationsOnce(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sweepValidationsOnce(ctx context.Context) {
+	token, err := kubernetes.GetKialiToken()
+	if err != nil {
+		log.Errorf("Validations sweep cannot read the token of Kiali: %v", err)
+		return
+	}
+	layer, err := Get(token)
+	if err != nil {
+		log.Errorf("Validations sweep cannot initialize the services: %v", err)
+		return
+	}
+	namespaces, err := layer.Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Validations sweep cannot list the namespaces: %v", err)
+		return
+	}
+	for _, namespace := range namespaces {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := layer.Validations.GetValidations(namespace.Name, ""); err != nil {
+			log.Warningf("Validations sweep cannot validate namespace [%s]: %v", namespace.Name, err)
+		}
+	}
+}
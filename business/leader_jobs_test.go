@@ -0,0 +1,52 @@
+package business
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestLeaderJobsWithoutElection(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	originalJobs := leaderJobs
+	defer func() { leaderJobs = originalJobs }()
+	started := make(chan string, 2)
+	stopped := make(chan string, 2)
+	job := func(name string) leaderJob {
+		return leaderJob{name: name, run: func(ctx context.Context) {
+			started <- name
+			<-ctx.Done()
+			stopped <- name
+		}}
+	}
+	leaderJobs = []leaderJob{job("a"), job("b")}
+
+	StartLeaderJobs()
+	assert.ElementsMatch([]string{"a", "b"}, []string{receiveJob(t, started), receiveJob(t, started)})
+
+	StopLeaderJobs()
+	assert.ElementsMatch([]string{"a", "b"}, []string{receiveJob(t, stopped), receiveJob(t, stopped)})
+}
+
+func receiveJob(t *testing.T, c chan string) string {
+	select {
+	case s := <-c:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("the background job did not start or stop")
+		return ""
+	}
+}
+
+func TestSweepValidationsDisabled(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	// Returns right away
+	sweepValidations(context.Background())
+}
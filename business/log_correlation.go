@@ -0,0 +1,92 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+)
+
+// W3C trace context header, i.e. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+var traceparentRegexp = regexp.MustCompile(`\b[0-9a-f]{2}-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}\b`)
+
+// logCorrelation extracts the trace and span IDs of log lines
+type logCorrelation struct {
+	traceIDFields []string
+	spanIDFields  []string
+	traceIDRegexp *regexp.Regexp
+	spanIDRegexp  *regexp.Regexp
+}
+
+func newLogCorrelation(cfg config.LogCorrelationConfig) *logCorrelation {
+	return &logCorrelation{
+		traceIDFields: cfg.TraceIDFields,
+		spanIDFields:  cfg.SpanIDFields,
+		traceIDRegexp: logFieldRegexp(cfg.TraceIDFields),
+		spanIDRegexp:  logFieldRegexp(cfg.SpanIDFields),
+	}
+}
+
+// logFieldRegexp matches the hexadecimal value of any of the fields in key=value or "key": "value" log lines
+func logFieldRegexp(fields []string) *regexp.Regexp {
+	if len(fields) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+	return regexp.MustCompile(fmt.Sprintf(`(?:^|[\s,;{\[("'])["']?(?:%s)["']?\s*[=:]\s*["']?([0-9a-fA-F-]{16,36})\b`, strings.Join(quoted, "|")))
+}
+
+// parse returns the trace and span IDs of a log line, empty when not found. The fields of JSON log lines are looked
+// up first, then the fields of key=value log lines and at last the W3C traceparent values.
+func (lc *logCorrelation) parse(message string) (traceID, spanID string) {
+	if strings.HasPrefix(message, "{") {
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(message), &fields); err == nil {
+			traceID, spanID = jsonLogField(fields, lc.traceIDFields), jsonLogField(fields, lc.spanIDFields)
+		}
+	}
+	if traceID == "" && lc.traceIDRegexp != nil {
+		if match := lc.traceIDRegexp.FindStringSubmatch(message); match != nil {
+			traceID = match[1]
+		}
+	}
+	if spanID == "" && lc.spanIDRegexp != nil {
+		if match := lc.spanIDRegexp.FindStringSubmatch(message); match != nil {
+			spanID = match[1]
+		}
+	}
+	if traceID == "" {
+		if match := traceparentRegexp.FindStringSubmatch(message); match != nil {
+			traceID = match[1]
+			if spanID == "" {
+				spanID = match[2]
+			}
+		}
+	}
+	return normalizeTraceID(traceID), normalizeTraceID(spanID)
+}
+
+func jsonLogField(fields map[string]interface{}, names []string) string {
+	for _, name := range names {
+		if value, ok := fields[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeTraceID returns the lower case hexadecimal form of a trace or span ID, without dashes
+func normalizeTraceID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// sameTraceID compares trace IDs regardless of their leading zeros, as 64 bits trace IDs may be logged padded to
+// 128 bits
+func sameTraceID(id, other string) bool {
+	return strings.TrimLeft(normalizeTraceID(id), "0") == strings.TrimLeft(normalizeTraceID(other), "0")
+}
@@ -0,0 +1,91 @@
+package business
+
+import (
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/loki"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Sources of the logs of the pods
+const (
+	LogSourceKubernetes = "kubernetes"
+	LogSourceLoki       = "loki"
+)
+
+// Time range of the logs read from Loki when the options set no start time
+const defaultLokiLookback = time.Hour
+
+type LokiLoader = func() (loki.ClientInterface, error)
+
+// getLokiLogs reads the logs of a pod from Loki, which keeps the logs of the restarted and deleted pods. The regular
+// expression filters the lines in Loki, the other filters are applied to the lines returned.
+func (in *WorkloadService) getLokiLogs(namespace, name string, opts *LogOptions) (*PodLog, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "getLokiLogs")
+	defer promtimer.ObserveNow(&err)
+
+	// Loki is queried with the credentials of Kiali, the user must be allowed to read the pods
+	if err = in.checkCan("get", namespace, schema.GroupResource{Resource: "pods"}, name); err != nil {
+		return nil, err
+	}
+	if in.loki == nil {
+		err = errors.New("loki is not available")
+		return nil, err
+	}
+	client, err := in.loki()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config.Get().ExternalServices.Loki
+	end := util.Clock.Now()
+	start := end.Add(-defaultLokiLookback)
+	if opts.SinceTime != nil {
+		start = opts.SinceTime.Time
+		if opts.Duration != nil {
+			end = start.Add(*opts.Duration)
+		}
+	} else if opts.Duration != nil {
+		start = end.Add(-*opts.Duration)
+	}
+
+	// Loki returns the latest lines first, so the tail lines are its limit unless lines are filtered afterwards
+	limit := cfg.MaxEntries
+	filteredAfter := opts.TraceID != "" || len(opts.ResponseFlags) > 0
+	if opts.TailLines != nil && !filteredAfter && (limit <= 0 || int(*opts.TailLines) < limit) {
+		limit = int(*opts.TailLines)
+	}
+	filter := ""
+	if opts.Filter != nil {
+		filter = opts.Filter.String()
+	}
+
+	lines, err := client.QueryLogs(loki.PodLogsQuery(cfg.Labels, namespace, name, opts.Container, filter), start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	correlation := newLogCorrelation(config.Get().ExternalServices.Tracing.LogCorrelation)
+	entries := make([]LogEntry, 0, len(lines))
+	for _, line := range lines {
+		entry, at, ok := parseLogLine(line.Time.Format(time.RFC3339Nano)+" "+line.Line, correlation)
+		if !ok || !opts.matches(entry) {
+			continue
+		}
+		entry.at = at
+		if opts.Container == "" {
+			entry.Container = line.Labels[cfg.Labels.Container]
+		}
+		entries = append(entries, entry)
+	}
+	if opts.TailLines != nil && len(entries) > int(*opts.TailLines) {
+		entries = entries[len(entries)-int(*opts.TailLines):]
+	}
+	return &PodLog{Entries: entries}, nil
+}
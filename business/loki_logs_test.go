@@ -0,0 +1,85 @@
+package business
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/loki"
+	"github.com/kiali/kiali/util"
+)
+
+type lokiClientMock struct {
+	mock.Mock
+}
+
+func (l *lokiClientMock) QueryLogs(query string, start, end time.Time, limit int) ([]loki.Entry, error) {
+	args := l.Called(query, start, end, limit)
+	return args.Get(0).([]loki.Entry), args.Error(1)
+}
+
+func TestGetLokiLogs(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Loki.Enabled = true
+	config.Set(conf)
+	now := time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods", []string{"get"}).Return(fakeAccessReview(true), nil)
+	k8s.On("GetSelfSubjectAccessReview", "other", "", "pods", []string{"get"}).Return(fakeAccessReview(false), nil)
+	client := new(lokiClientMock)
+	line := func(at time.Time, container, message string) loki.Entry {
+		return loki.Entry{Time: at, Line: message, Labels: map[string]string{"namespace": "bookinfo", "pod": "details-v1", "container": container}}
+	}
+	client.On("QueryLogs", `{namespace="bookinfo",pod="details-v1"}`, now.Add(-time.Hour), now, 2).Return([]loki.Entry{
+		line(now.Add(-2*time.Second), "details", "GET /details/0"),
+		line(now.Add(-time.Second), "details", "ERROR details not found"),
+	}, nil)
+	since := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	client.On("QueryLogs", `{namespace="bookinfo",pod="details-v1",container="istio-proxy"} |~ "details"`, since, since.Add(10*time.Minute), 5000).Return([]loki.Entry{
+		line(since.Add(time.Second), "istio-proxy", `[2021-03-01T10:00:01.000Z] "GET /details/0 HTTP/1.1" 200 - via_upstream - "-" 0 178 2 1 "-" "curl/7.64.0" "-" "details:9080" "10.244.0.12:9080" inbound|9080|| 127.0.0.6:46143 10.244.0.12:9080 10.244.0.10:41278 outbound_.9080_._.details.bookinfo.svc.cluster.local default`),
+		line(since.Add(2*time.Second), "istio-proxy", `[2021-03-01T10:00:02.000Z] "GET /details/1 HTTP/1.1" 503 UF upstream_reset_before_response_started{connection_failure} - "-" 0 91 2 - "-" "curl/7.64.0" "-" "details:9080" "10.244.0.12:9080" inbound|9080|| - 10.244.0.12:9080 10.244.0.10:41278 outbound_.9080_._.details.bookinfo.svc.cluster.local default`),
+	}, nil)
+	svc := setupWorkloadService(k8s)
+	svc.loki = func() (loki.ClientInterface, error) { return client, nil }
+
+	tailLines := int64(2)
+	podLog, err := svc.GetPodLogs("bookinfo", "details-v1", &LogOptions{Source: LogSourceLoki, PodLogOptions: core_v1.PodLogOptions{TailLines: &tailLines}})
+	assert.NoError(err)
+	assert.Len(podLog.Entries, 2)
+	assert.Equal("GET /details/0", podLog.Entries[0].Message)
+	assert.Equal("details", podLog.Entries[0].Container)
+	assert.Equal("ERROR", podLog.Entries[1].Severity)
+	assert.Equal(now.Add(-time.Second).Unix(), podLog.Entries[1].TimestampUnix)
+
+	// The regular expression is sent to Loki, the response flags filter the lines returned
+	duration := 10 * time.Minute
+	opts := &LogOptions{
+		Source:        LogSourceLoki,
+		Duration:      &duration,
+		Filter:        regexp.MustCompile("details"),
+		ResponseFlags: []string{"UF"},
+		PodLogOptions: core_v1.PodLogOptions{TailLines: &tailLines, SinceTime: &meta_v1.Time{Time: since}},
+	}
+	opts.Container = "istio-proxy"
+	podLog, err = svc.GetPodLogs("bookinfo", "details-v1", opts)
+	assert.NoError(err)
+	assert.Len(podLog.Entries, 1)
+	assert.Equal(503, podLog.Entries[0].AccessLog.ResponseCode)
+	assert.Empty(podLog.Entries[0].Container)
+
+	_, err = svc.GetPodLogs("other", "details-v1", &LogOptions{Source: LogSourceLoki})
+	assert.True(k8s_errors.IsForbidden(err))
+	client.AssertNumberOfCalls(t, "QueryLogs", 2)
+}
@@ -0,0 +1,332 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// MeshService deals with the control planes of the mesh
+type MeshService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+const (
+	// Revision of istiod deployed without revision
+	defaultRevision = "default"
+	// Label of the revision of istiod, set on its deployment, on the namespaces and on the injected pods
+	istioRevisionLabel = "istio.io/rev"
+	// Name of the container of the proxies injected by Istio
+	istioProxyContainer = "istio-proxy"
+)
+
+// GetControlPlaneRevisions returns the revisions of istiod deployed in the Istio namespace
+func (in *MeshService) GetControlPlaneRevisions() ([]models.ControlPlaneRevision, error) {
+	istioNamespace := config.Get().IstioNamespace
+	var deployments []apps_v1.Deployment
+	var err error
+	if IsNamespaceCached(istioNamespace) {
+		deployments, err = kialiCache.GetDeployments(istioNamespace)
+	} else {
+		deployments, err = in.k8s.GetDeployments(istioNamespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return controlPlaneRevisions(deployments), nil
+}
+
+func controlPlaneRevisions(deployments []apps_v1.Deployment) []models.ControlPlaneRevision {
+	appLabel := config.Get().IstioLabels.AppLabelName
+	revisions := []models.ControlPlaneRevision{}
+	for _, d := range deployments {
+		if d.Spec.Template.Labels[appLabel] != "istiod" {
+			continue
+		}
+		revision := models.ControlPlaneRevision{
+			Revision:   d.Spec.Template.Labels[istioRevisionLabel],
+			Deployment: d.Name,
+			Status:     GetDeploymentStatus(d),
+		}
+		if revision.Revision == "" {
+			revision.Revision = defaultRevision
+		}
+		for _, c := range d.Spec.Template.Spec.Containers {
+			if c.Name == "discovery" || revision.Image == "" {
+				revision.Image = c.Image
+			}
+		}
+		revision.Version = imageTag(revision.Image)
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions
+}
+
+// imageTag returns the tag of an image, i.e. 1.8.1 for docker.io/istio/proxyv2:1.8.1
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// CompareRevisions compares two revisions of the control plane: their versions, their mesh configurations and the
+// namespaces, accessible by the user, pointing at them or running their proxies
+func (in *MeshService) CompareRevisions(from, to string) (*models.RevisionComparison, error) {
+	revisions, err := in.GetControlPlaneRevisions()
+	if err != nil {
+		return nil, err
+	}
+	comparison := &models.RevisionComparison{Revisions: revisions}
+	found := map[string]bool{}
+	for _, revision := range revisions {
+		if revision.Revision == from {
+			comparison.From = revision
+			found[from] = true
+		}
+		if revision.Revision == to {
+			comparison.To = revision
+			found[to] = true
+		}
+	}
+	for _, revision := range []string{from, to} {
+		if !found[revision] {
+			return nil, kubernetes.NewNotFound(revision, "", "revisions")
+		}
+	}
+
+	fromMesh, err := in.getRevisionMeshConfig(from)
+	if err != nil {
+		return nil, err
+	}
+	toMesh, err := in.getRevisionMeshConfig(to)
+	if err != nil {
+		return nil, err
+	}
+	comparison.MeshConfigDeltas = diffMeshConfig(fromMesh, toMesh)
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	comparison.Namespaces = namespaceRevisions(namespaces, pods, from, to)
+	return comparison, nil
+}
+
+// getRevisionMeshConfig returns the mesh configuration of a revision, read from the ConfigMap of the revision
+func (in *MeshService) getRevisionMeshConfig(revision string) (map[string]interface{}, error) {
+	cfg := config.Get()
+	name := cfg.ExternalServices.Istio.ConfigMapName
+	if revision != defaultRevision {
+		name = name + "-" + revision
+	}
+	var cm *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(cfg.IstioNamespace) {
+		cm, err = kialiCache.GetConfigMap(cfg.IstioNamespace, name)
+	} else {
+		cm, err = in.k8s.GetConfigMap(cfg.IstioNamespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mesh, err := parseMeshConfig(cm)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the mesh configuration of revision [%s]: %v", revision, err)
+	}
+	return mesh, nil
+}
+
+// parseMeshConfig returns the settings, by path, of the mesh configuration held by a ConfigMap of istiod
+func parseMeshConfig(cm *core_v1.ConfigMap) (map[string]interface{}, error) {
+	mesh := map[string]interface{}{}
+	if raw, ok := cm.Data["mesh"]; ok {
+		parsed := map[interface{}]interface{}{}
+		if err := yaml.Unmarshal([]byte(raw), &parsed); err != nil {
+			return nil, err
+		}
+		flattenMeshConfig("", parsed, mesh)
+	}
+	return mesh, nil
+}
+
+// flattenMeshConfig sets the settings of a mesh configuration by path, i.e. defaultConfig.tracing.sampling
+func flattenMeshConfig(prefix string, value map[interface{}]interface{}, settings map[string]interface{}) {
+	for key, v := range value {
+		path := fmt.Sprintf("%v", key)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if nested, ok := v.(map[interface{}]interface{}); ok && len(nested) > 0 {
+			flattenMeshConfig(path, nested, settings)
+			continue
+		}
+		settings[path] = jsonValue(v)
+	}
+}
+
+// jsonValue converts the maps of YAML values, keyed by interface{}, to maps keyed by strings
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprintf("%v", key)] = jsonValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = jsonValue(item)
+		}
+		return converted
+	}
+	return value
+}
+
+func diffMeshConfig(from, to map[string]interface{}) []models.MeshConfigDelta {
+	deltas := []models.MeshConfigDelta{}
+	for path, fromValue := range from {
+		if toValue, ok := to[path]; !ok || !reflect.DeepEqual(fromValue, toValue) {
+			deltas = append(deltas, models.MeshConfigDelta{Path: path, From: fromValue, To: toValue})
+		}
+	}
+	for path, toValue := range to {
+		if _, ok := from[path]; !ok {
+			deltas = append(deltas, models.MeshConfigDelta{Path: path, To: toValue})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Path < deltas[j].Path })
+	return deltas
+}
+
+func (in *MeshService) getNamespacesPods(namespaces []models.Namespace) (map[string][]core_v1.Pod, error) {
+	pods := make(map[string][]core_v1.Pod, len(namespaces))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(namespaces))
+	for _, ns := range namespaces {
+		wg.Add(1)
+		go func(namespace string) {
+			defer wg.Done()
+			var nsPods []core_v1.Pod
+			var err error
+			if IsNamespaceCached(namespace) {
+				nsPods, err = kialiCache.GetPods(namespace, "")
+			} else {
+				nsPods, err = in.k8s.GetPods(namespace, "")
+			}
+			if err != nil {
+				log.Errorf("Error fetching the pods of namespace %s: %s", namespace, err)
+				errChan <- err
+				return
+			}
+			mu.Lock()
+			pods[namespace] = nsPods
+			mu.Unlock()
+		}(ns.Name)
+	}
+	wg.Wait()
+	close(errChan)
+	for err := range errChan {
+		return nil, err
+	}
+	return pods, nil
+}
+
+// namespaceRevision returns the revision injecting the proxies of a namespace, from its labels
+func namespaceRevision(ns models.Namespace) string {
+	if revision, ok := ns.Labels[istioRevisionLabel]; ok {
+		return revision
+	}
+	if ns.Labels[config.Get().IstioLabels.InjectionLabelName] == "enabled" {
+		return defaultRevision
+	}
+	return ""
+}
+
+// podProxy returns the revision and version of the proxy injected in a pod, ok being false without proxy
+func podProxy(pod core_v1.Pod) (revision, version string, ok bool) {
+	status, injected := pod.Annotations[config.Get().ExternalServices.Istio.IstioSidecarAnnotation]
+	if !injected {
+		return "", "", false
+	}
+	sidecarStatus := struct {
+		Revision string `json:"revision"`
+	}{}
+	_ = json.Unmarshal([]byte(status), &sidecarStatus)
+	revision = sidecarStatus.Revision
+	if revision == "" {
+		revision = pod.Labels[istioRevisionLabel]
+	}
+	if revision == "" {
+		revision = defaultRevision
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == istioProxyContainer {
+			version = imageTag(c.Image)
+		}
+	}
+	return revision, version, true
+}
+
+func namespaceRevisions(namespaces []models.Namespace, pods map[string][]core_v1.Pod, from, to string) []models.NamespaceRevision {
+	result := []models.NamespaceRevision{}
+	for _, ns := range namespaces {
+		nsRevision := models.NamespaceRevision{Namespace: ns.Name, Revision: namespaceRevision(ns), Proxies: []models.ProxyVersionCount{}}
+		relevant := nsRevision.Revision == from || nsRevision.Revision == to
+		counts := map[models.ProxyVersionCount]int{}
+		for _, pod := range pods[ns.Name] {
+			revision, version, ok := podProxy(pod)
+			if !ok {
+				continue
+			}
+			counts[models.ProxyVersionCount{Revision: revision, Version: version}]++
+			if revision == from || revision == to {
+				relevant = true
+			}
+			if nsRevision.Revision != "" && revision != nsRevision.Revision {
+				nsRevision.RestartRequired = true
+			}
+		}
+		if !relevant {
+			continue
+		}
+		for proxy, count := range counts {
+			proxy.Count = count
+			nsRevision.Proxies = append(nsRevision.Proxies, proxy)
+		}
+		sort.Slice(nsRevision.Proxies, func(i, j int) bool {
+			if nsRevision.Proxies[i].Revision != nsRevision.Proxies[j].Revision {
+				return nsRevision.Proxies[i].Revision < nsRevision.Proxies[j].Revision
+			}
+			return nsRevision.Proxies[i].Version < nsRevision.Proxies[j].Version
+		})
+		result = append(result, nsRevision)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Namespace < result[j].Namespace })
+	return result
+}
@@ -0,0 +1,204 @@
+package business
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	// Certificates expiring within this delay are reported, unless they are valid for a shorter time than five times
+	// the delay, such as the workload certificates
+	certificateExpiringDelay = 30 * 24 * time.Hour
+	// Maximum number of config dumps fetched at the same time
+	certificateDumpParallelism = 10
+)
+
+// Sources of the certificates of the CA of istiod: the secret of a plugged-in CA, then the self-signed CA, and the
+// root certificate distributed to the namespaces
+var (
+	caSecretNames = []string{"cacerts", "istio-ca-secret"}
+	caSecretKeys  = []string{"root-cert.pem", "ca-cert.pem", "cert-chain.pem"}
+)
+
+const caRootConfigMap = "istio-ca-root-cert"
+
+// GetCertificates returns the certificates of the CA of istiod and, when a namespace is given, the workload
+// certificates loaded by the proxies of the namespace, with their expiration
+func (in *MeshService) GetCertificates(namespace string) (*models.MeshCertificates, error) {
+	certificates := &models.MeshCertificates{ControlPlane: []models.Certificate{}, Proxies: []models.ProxyCertificates{}}
+	istioNamespace := config.Get().IstioNamespace
+
+	for _, name := range caSecretNames {
+		secret, err := in.k8s.GetSecret(istioNamespace, name)
+		if err != nil {
+			if !k8s_errors.IsNotFound(err) {
+				certificates.Errors = append(certificates.Errors, fmt.Sprintf("cannot read secret %s/%s: %v", istioNamespace, name, err))
+			}
+			continue
+		}
+		for _, key := range caSecretKeys {
+			source := fmt.Sprintf("secret %s/%s, %s", istioNamespace, name, key)
+			certificates.ControlPlane = appendCertificates(certificates.ControlPlane, source, secret.Data[key])
+		}
+	}
+	var cm *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(istioNamespace) {
+		cm, err = kialiCache.GetConfigMap(istioNamespace, caRootConfigMap)
+	} else {
+		cm, err = in.k8s.GetConfigMap(istioNamespace, caRootConfigMap)
+	}
+	if err == nil {
+		source := fmt.Sprintf("configmap %s/%s, root-cert.pem", istioNamespace, caRootConfigMap)
+		certificates.ControlPlane = appendCertificates(certificates.ControlPlane, source, []byte(cm.Data["root-cert.pem"]))
+	} else if !k8s_errors.IsNotFound(err) {
+		certificates.Errors = append(certificates.Errors, fmt.Sprintf("cannot read configmap %s/%s: %v", istioNamespace, caRootConfigMap, err))
+	}
+
+	if namespace != "" {
+		ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := in.getNamespacesPods([]models.Namespace{*ns})
+		if err != nil {
+			return nil, err
+		}
+		certificates.Proxies = in.getProxiesCertificates(pods[namespace])
+	}
+
+	now := util.Clock.Now()
+	for i := range certificates.ControlPlane {
+		certificates.Warnings += setCertificateStatus(&certificates.ControlPlane[i], now)
+	}
+	for _, proxy := range certificates.Proxies {
+		for i := range proxy.Certificates {
+			certificates.Warnings += setCertificateStatus(&proxy.Certificates[i], now)
+		}
+	}
+	return certificates, nil
+}
+
+// getProxiesCertificates reads the certificates loaded by the proxies of the pods, from their config dumps
+func (in *MeshService) getProxiesCertificates(pods []core_v1.Pod) []models.ProxyCertificates {
+	proxies := []models.ProxyCertificates{}
+	for _, pod := range pods {
+		if _, _, ok := podProxy(pod); ok && pod.Status.Phase == core_v1.PodRunning {
+			proxies = append(proxies, models.ProxyCertificates{Namespace: pod.Namespace, Pod: pod.Name, Certificates: []models.Certificate{}})
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, certificateDumpParallelism)
+	for i := range proxies {
+		wg.Add(1)
+		go func(proxy *models.ProxyCertificates) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			proxy.Certificates, proxy.Error = in.getProxyCertificates(proxy.Namespace, proxy.Pod)
+		}(&proxies[i])
+	}
+	wg.Wait()
+
+	sort.Slice(proxies, func(i, j int) bool { return proxies[i].Pod < proxies[j].Pod })
+	return proxies
+}
+
+func (in *MeshService) getProxyCertificates(namespace, pod string) ([]models.Certificate, string) {
+	certificates := []models.Certificate{}
+	dump, err := in.k8s.GetConfigDump(namespace, pod)
+	if err != nil {
+		log.Debugf("Error fetching the config dump of pod %s/%s: %v", namespace, pod, err)
+		return certificates, err.Error()
+	}
+	secrets, err := dump.GetSecrets()
+	if err != nil {
+		return certificates, err.Error()
+	}
+	for _, s := range append(secrets.DynamicActiveSecrets, secrets.StaticSecrets...) {
+		var encoded string
+		switch {
+		case s.Secret.TlsCertificate != nil:
+			encoded = s.Secret.TlsCertificate.CertificateChain.InlineBytes
+		case s.Secret.ValidationContext != nil:
+			encoded = s.Secret.ValidationContext.TrustedCa.InlineBytes
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			log.Debugf("Invalid certificate %s in the config dump of pod %s/%s: %v", s.Name, namespace, pod, err)
+			continue
+		}
+		certificates = appendCertificates(certificates, s.Name, data)
+	}
+	return certificates, ""
+}
+
+// appendCertificates parses the PEM encoded certificates of a source, skipping the ones already known
+func appendCertificates(certificates []models.Certificate, source string, data []byte) []models.Certificate {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Debugf("Invalid certificate in %s: %v", source, err)
+			continue
+		}
+		certificate := models.Certificate{
+			Source:       source,
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: cert.SerialNumber.String(),
+			IsCA:         cert.IsCA,
+			NotBefore:    cert.NotBefore,
+			NotAfter:     cert.NotAfter,
+		}
+		certificate.SANs = append(certificate.SANs, cert.DNSNames...)
+		for _, uri := range cert.URIs {
+			certificate.SANs = append(certificate.SANs, uri.String())
+		}
+		known := false
+		for _, c := range certificates {
+			if c.SerialNumber == certificate.SerialNumber && c.Issuer == certificate.Issuer {
+				known = true
+				break
+			}
+		}
+		if !known {
+			certificates = append(certificates, certificate)
+		}
+	}
+	return certificates
+}
+
+// setCertificateStatus sets the status of a certificate, returning 1 when it is expiring or expired
+func setCertificateStatus(cert *models.Certificate, now time.Time) int {
+	delay := certificateExpiringDelay
+	if fifth := cert.NotAfter.Sub(cert.NotBefore) / 5; fifth < delay {
+		delay = fifth
+	}
+	switch {
+	case !now.Before(cert.NotAfter):
+		cert.Status = models.CertificateExpired
+	case cert.NotAfter.Sub(now) < delay:
+		cert.Status = models.CertificateExpiring
+	default:
+		cert.Status = models.CertificateValid
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,133 @@
+package business
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeCertificate(t *testing.T, serial int64, cn string, isCA bool, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if !isCA {
+		spiffe, _ := url.Parse("spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews")
+		template.URIs = []*url.URL{spiffe}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetCertificates(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	now := time.Date(2021, 1, 10, 12, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+
+	root := fakeCertificate(t, 1, "root", true, now.AddDate(-9, 0, 0), now.AddDate(0, 0, 20))
+	intermediate := fakeCertificate(t, 2, "intermediate", true, now.AddDate(-1, 0, 0), now.AddDate(1, 0, 0))
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSecret", "istio-system", "cacerts").Return(&core_v1.Secret{Data: map[string][]byte{
+		"root-cert.pem":  root,
+		"ca-cert.pem":    intermediate,
+		"cert-chain.pem": append(intermediate, root...),
+	}}, nil)
+	k8s.On("GetSecret", "istio-system", "istio-ca-secret").Return((*core_v1.Secret)(nil),
+		k8s_errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "istio-ca-secret"))
+	k8s.On("GetConfigMap", "istio-system", "istio-ca-root-cert").Return((*core_v1.ConfigMap)(nil), errors.New("forbidden"))
+	service := MeshService{k8s: k8s}
+
+	certificates, err := service.GetCertificates("")
+	assert.NoError(err)
+	assert.Len(certificates.ControlPlane, 2)
+	assert.Equal("CN=root", certificates.ControlPlane[0].Subject)
+	assert.Equal("secret istio-system/cacerts, root-cert.pem", certificates.ControlPlane[0].Source)
+	assert.Equal(models.CertificateExpiring, certificates.ControlPlane[0].Status)
+	assert.Equal("CN=intermediate", certificates.ControlPlane[1].Subject)
+	assert.Equal(models.CertificateValid, certificates.ControlPlane[1].Status)
+	assert.Equal(1, certificates.Warnings)
+	assert.Len(certificates.Errors, 1)
+	assert.Empty(certificates.Proxies)
+}
+
+func TestGetProxiesCertificates(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	now := time.Date(2021, 1, 10, 12, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+
+	root := fakeCertificate(t, 1, "root", true, now.AddDate(-1, 0, 0), now.AddDate(9, 0, 0))
+	workload := fakeCertificate(t, 3, "", false, now.Add(-23*time.Hour), now.Add(time.Hour))
+	dump := &kubernetes.ConfigDump{Configs: []interface{}{map[string]interface{}{
+		"@type": "type.googleapis.com/envoy.admin.v3.SecretsConfigDump",
+		"dynamic_active_secrets": []interface{}{
+			map[string]interface{}{"name": "default", "secret": map[string]interface{}{
+				"tls_certificate": map[string]interface{}{"certificate_chain": map[string]interface{}{"inline_bytes": base64.StdEncoding.EncodeToString(workload)}},
+			}},
+			map[string]interface{}{"name": "ROOTCA", "secret": map[string]interface{}{
+				"validation_context": map[string]interface{}{"trusted_ca": map[string]interface{}{"inline_bytes": base64.StdEncoding.EncodeToString(root)}},
+			}},
+		},
+	}}}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigDump", "bookinfo", "reviews").Return(dump, nil)
+	k8s.On("GetConfigDump", "bookinfo", "ratings").Return((*kubernetes.ConfigDump)(nil), errors.New("port-forward failed"))
+	service := MeshService{k8s: k8s}
+
+	reviews, ratings := fakeProxyPod("reviews", "", ""), fakeProxyPod("ratings", "", "")
+	reviews.Namespace, ratings.Namespace = "bookinfo", "bookinfo"
+	reviews.Status.Phase, ratings.Status.Phase = core_v1.PodRunning, core_v1.PodRunning
+	pending := fakeProxyPod("details", "", "")
+	pending.Namespace, pending.Status.Phase = "bookinfo", core_v1.PodPending
+	noProxy := core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "legacy", Namespace: "bookinfo"}, Status: core_v1.PodStatus{Phase: core_v1.PodRunning}}
+
+	proxies := service.getProxiesCertificates([]core_v1.Pod{reviews, ratings, pending, noProxy})
+	assert.Len(proxies, 2)
+	assert.Equal("ratings", proxies[0].Pod)
+	assert.Equal("port-forward failed", proxies[0].Error)
+	assert.Empty(proxies[0].Certificates)
+	assert.Equal("reviews", proxies[1].Pod)
+	assert.Empty(proxies[1].Error)
+	assert.Len(proxies[1].Certificates, 2)
+	assert.Equal("default", proxies[1].Certificates[0].Source)
+	assert.Equal([]string{"spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews"}, proxies[1].Certificates[0].SANs)
+	assert.Equal("ROOTCA", proxies[1].Certificates[1].Source)
+
+	// One hour left of a certificate valid for a day
+	assert.Equal(1, setCertificateStatus(&proxies[1].Certificates[0], now))
+	assert.Equal(models.CertificateExpiring, proxies[1].Certificates[0].Status)
+	assert.Equal(0, setCertificateStatus(&proxies[1].Certificates[1], now))
+	assert.Equal(1, setCertificateStatus(&proxies[1].Certificates[1], now.AddDate(10, 0, 0)))
+	assert.Equal(models.CertificateExpired, proxies[1].Certificates[1].Status)
+}
@@ -0,0 +1,174 @@
+package business
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"sort"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Istio objects of the root namespace applied to the whole mesh, compared between the clusters, by resource type
+var driftIstioResources = map[string]string{
+	kubernetes.PeerAuthentications:   "PeerAuthentication",
+	kubernetes.AuthorizationPolicies: "AuthorizationPolicy",
+	kubernetes.EnvoyFilters:          "EnvoyFilter",
+	kubernetes.Sidecars:              "Sidecar",
+}
+
+// Trust domain of the mesh when not set in its configuration
+const defaultTrustDomain = "cluster.local"
+
+// driftKey identifies a setting compared between the clusters
+type driftKey struct {
+	category string
+	key      string
+}
+
+// GetMeshDrift compares the mesh configuration, the root CA, the trust domain and the mesh-wide Istio objects of the
+// home cluster with the ones of the remote clusters, given with the clients of the user for them
+func (in *MeshService) GetMeshDrift(remotes map[string]kubernetes.ClientInterface) *models.MeshDrift {
+	clusters := []string{config.Get().KubernetesConfig.ClusterName}
+	remoteNames := make([]string, 0, len(remotes))
+	for cluster := range remotes {
+		remoteNames = append(remoteNames, cluster)
+	}
+	sort.Strings(remoteNames)
+	for _, cluster := range remoteNames {
+		clusters = append(clusters, cluster)
+	}
+
+	snapshots, errs := queryClusters(clusters, func(cluster string) (interface{}, error) {
+		if cluster == clusters[0] {
+			return meshSnapshot(in.k8s)
+		}
+		return meshSnapshot(remotes[cluster])
+	})
+
+	drift := &models.MeshDrift{Clusters: []string{}}
+	compared := map[string]map[driftKey]interface{}{}
+	for i, cluster := range clusters {
+		if errs[i] != nil {
+			if drift.Errors == nil {
+				drift.Errors = map[string]string{}
+			}
+			drift.Errors[cluster] = errs[i].Error()
+			continue
+		}
+		drift.Clusters = append(drift.Clusters, cluster)
+		compared[cluster] = snapshots[i].(map[driftKey]interface{})
+	}
+	drift.Drifts = diffSnapshots(drift.Clusters, compared)
+	return drift
+}
+
+// meshSnapshot reads the settings of the mesh compared between the clusters
+func meshSnapshot(k8s kubernetes.ClientInterface) (map[driftKey]interface{}, error) {
+	conf := config.Get()
+	snapshot := map[driftKey]interface{}{}
+
+	trustDomain := defaultTrustDomain
+	cm, err := k8s.GetConfigMap(conf.IstioNamespace, conf.ExternalServices.Istio.ConfigMapName)
+	if err == nil {
+		mesh, err := parseMeshConfig(cm)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the mesh configuration: %v", err)
+		}
+		for path, value := range mesh {
+			if path == "trustDomain" {
+				trustDomain = fmt.Sprintf("%v", value)
+				continue
+			}
+			snapshot[driftKey{models.DriftMeshConfig, path}] = value
+		}
+	} else if !k8s_errors.IsNotFound(err) {
+		return nil, err
+	}
+	snapshot[driftKey{models.DriftTrustDomain, "trustDomain"}] = trustDomain
+
+	cm, err = k8s.GetConfigMap(conf.IstioNamespace, caRootConfigMap)
+	if err == nil {
+		if fingerprints := certificateFingerprints([]byte(cm.Data["root-cert.pem"])); len(fingerprints) > 0 {
+			snapshot[driftKey{models.DriftRootCA, "root-cert.pem"}] = fingerprints
+		}
+	} else if !k8s_errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	for resource, kind := range driftIstioResources {
+		objects, err := k8s.GetIstioObjects(conf.IstioNamespace, resource, "")
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, o := range objects {
+			key := fmt.Sprintf("%s %s/%s", kind, conf.IstioNamespace, o.GetObjectMeta().Name)
+			snapshot[driftKey{models.DriftIstioConfig, key}] = o.GetSpec()
+		}
+	}
+	return snapshot, nil
+}
+
+// certificateFingerprints returns the SHA-256 fingerprints of the PEM encoded certificates
+func certificateFingerprints(data []byte) []string {
+	fingerprints := []string{}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			continue
+		}
+		sum := sha256.Sum256(block.Bytes)
+		fingerprints = append(fingerprints, hex.EncodeToString(sum[:]))
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+// diffSnapshots returns the settings whose values, or presence, differ between the clusters
+func diffSnapshots(clusters []string, snapshots map[string]map[driftKey]interface{}) []models.ConfigDrift {
+	keys := map[driftKey]bool{}
+	for _, snapshot := range snapshots {
+		for key := range snapshot {
+			keys[key] = true
+		}
+	}
+	drifts := []models.ConfigDrift{}
+	for key := range keys {
+		values := map[string]interface{}{}
+		drifting := false
+		for i, cluster := range clusters {
+			value, ok := snapshots[cluster][key]
+			if ok {
+				values[cluster] = value
+			}
+			if i > 0 {
+				firstValue, firstOk := snapshots[clusters[0]][key]
+				if ok != firstOk || !reflect.DeepEqual(value, firstValue) {
+					drifting = true
+				}
+			}
+		}
+		if drifting {
+			drifts = append(drifts, models.ConfigDrift{Category: key.category, Key: key.key, Values: values})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Category != drifts[j].Category {
+			return drifts[i].Category < drifts[j].Category
+		}
+		return drifts[i].Key < drifts[j].Key
+	})
+	return drifts
+}
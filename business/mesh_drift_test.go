@@ -0,0 +1,98 @@
+package business
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeDriftCluster(mesh string, rootCert []byte, peerAuthMode string, sidecarsErr error) *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(&core_v1.ConfigMap{Data: map[string]string{"mesh": mesh}}, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-ca-root-cert").Return(&core_v1.ConfigMap{Data: map[string]string{"root-cert.pem": string(rootCert)}}, nil)
+	if peerAuthMode != "" {
+		pa := &kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "default", Namespace: "istio-system"},
+			Spec:       map[string]interface{}{"mtls": map[string]interface{}{"mode": peerAuthMode}},
+		}
+		k8s.On("GetIstioObjects", "istio-system", kubernetes.PeerAuthentications, "").Return([]kubernetes.IstioObject{pa}, nil)
+	}
+	if sidecarsErr != nil {
+		k8s.On("GetIstioObjects", "istio-system", kubernetes.Sidecars, "").Return([]kubernetes.IstioObject{}, sidecarsErr)
+	}
+	k8s.On("GetIstioObjects", "istio-system", mock.Anything, "").Return([]kubernetes.IstioObject{}, nil)
+	return k8s
+}
+
+func TestGetMeshDrift(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ClusterName = "east"
+	config.Set(conf)
+
+	now := time.Now()
+	root := fakeCertificate(t, 1, "root", true, now, now.AddDate(10, 0, 0))
+	otherRoot := fakeCertificate(t, 2, "root", true, now, now.AddDate(10, 0, 0))
+
+	east := fakeDriftCluster(`
+outboundTrafficPolicy:
+  mode: REGISTRY_ONLY
+enableAutoMtls: true
+`, root, "STRICT", nil)
+	west := fakeDriftCluster(`
+outboundTrafficPolicy:
+  mode: ALLOW_ANY
+enableAutoMtls: true
+trustDomain: west.local
+`, otherRoot, "", nil)
+	central := fakeDriftCluster("", nil, "", nil)
+	central.ExpectedCalls = nil
+	central.On("GetConfigMap", "istio-system", mock.Anything).Return((*core_v1.ConfigMap)(nil), errors.New("forbidden"))
+	service := MeshService{k8s: east}
+
+	drift := service.GetMeshDrift(map[string]kubernetes.ClientInterface{"west": west, "central": central})
+	assert.Equal([]string{"east", "west"}, drift.Clusters)
+	assert.Equal(map[string]string{"central": "forbidden"}, drift.Errors)
+	assert.Len(drift.Drifts, 4)
+
+	assert.Equal(models.DriftIstioConfig, drift.Drifts[0].Category)
+	assert.Equal("PeerAuthentication istio-system/default", drift.Drifts[0].Key)
+	assert.Contains(drift.Drifts[0].Values, "east")
+	assert.NotContains(drift.Drifts[0].Values, "west")
+
+	assert.Equal(models.ConfigDrift{Category: models.DriftMeshConfig, Key: "outboundTrafficPolicy.mode",
+		Values: map[string]interface{}{"east": "REGISTRY_ONLY", "west": "ALLOW_ANY"}}, drift.Drifts[1])
+
+	assert.Equal(models.DriftRootCA, drift.Drifts[2].Category)
+	assert.NotEqual(drift.Drifts[2].Values["east"], drift.Drifts[2].Values["west"])
+
+	assert.Equal(models.ConfigDrift{Category: models.DriftTrustDomain, Key: "trustDomain",
+		Values: map[string]interface{}{"east": "cluster.local", "west": "west.local"}}, drift.Drifts[3])
+}
+
+func TestGetMeshDriftSameMesh(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	root := fakeCertificate(t, 1, "root", true, time.Now(), time.Now().AddDate(10, 0, 0))
+	home := fakeDriftCluster("enableAutoMtls: true", root, "STRICT", nil)
+	remote := fakeDriftCluster("enableAutoMtls: true", root, "STRICT", k8s_errors.NewNotFound(schema.GroupResource{Resource: "sidecars"}, ""))
+	service := MeshService{k8s: home}
+
+	drift := service.GetMeshDrift(map[string]kubernetes.ClientInterface{"remote": remote})
+	assert.Equal([]string{"Kubernetes", "remote"}, drift.Clusters)
+	assert.Empty(drift.Errors)
+	assert.Empty(drift.Drifts)
+}
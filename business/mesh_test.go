@@ -0,0 +1,128 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeIstiod(name, revision, image string) apps_v1.Deployment {
+	labels := map[string]string{"app": "istiod"}
+	if revision != "" {
+		labels[istioRevisionLabel] = revision
+	}
+	return apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "istio-system"},
+		Spec: apps_v1.DeploymentSpec{
+			Template: core_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{Labels: labels},
+				Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "discovery", Image: image}}},
+			},
+		},
+	}
+}
+
+func fakeProxyPod(name, revision, image string) core_v1.Pod {
+	status := `{"initContainers":["istio-init"],"containers":["istio-proxy"]}`
+	if revision != "" {
+		status = `{"initContainers":["istio-init"],"containers":["istio-proxy"],"revision":"` + revision + `"}`
+	}
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Annotations: map[string]string{"sidecar.istio.io/status": status}},
+		Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "app"}, {Name: "istio-proxy", Image: image}}},
+	}
+}
+
+func TestControlPlaneRevisions(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetDeployments", "istio-system").Return([]apps_v1.Deployment{
+		fakeIstiod("istiod-1-8-1", "1-8-1", "docker.io/istio/pilot:1.8.1"),
+		fakeIstiod("istiod", "", "docker.io/istio/pilot:1.7.4"),
+		fakeDeploymentWithStatus("prometheus", map[string]string{"app": "prometheus"}, apps_v1.DeploymentStatus{}),
+	}, nil)
+	service := MeshService{k8s: k8s}
+
+	revisions, err := service.GetControlPlaneRevisions()
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	assert.Equal("1-8-1", revisions[0].Revision)
+	assert.Equal("1.8.1", revisions[0].Version)
+	assert.Equal("istiod-1-8-1", revisions[0].Deployment)
+	assert.Equal("default", revisions[1].Revision)
+	assert.Equal("1.7.4", revisions[1].Version)
+
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(&core_v1.ConfigMap{Data: map[string]string{"mesh": `
+enableAutoMtls: true
+defaultConfig:
+  tracing:
+    sampling: 1
+  discoveryAddress: istiod.istio-system.svc:15012
+`}}, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-1-8-1").Return(&core_v1.ConfigMap{Data: map[string]string{"mesh": `
+enableAutoMtls: true
+defaultConfig:
+  tracing:
+    sampling: 10
+  discoveryAddress: istiod-1-8-1.istio-system.svc:15012
+trustDomainAliases: [cluster.local]
+`}}, nil)
+	from, err := service.getRevisionMeshConfig("default")
+	assert.NoError(err)
+	to, err := service.getRevisionMeshConfig("1-8-1")
+	assert.NoError(err)
+	deltas := diffMeshConfig(from, to)
+	assert.Equal([]models.MeshConfigDelta{
+		{Path: "defaultConfig.discoveryAddress", From: "istiod.istio-system.svc:15012", To: "istiod-1-8-1.istio-system.svc:15012"},
+		{Path: "defaultConfig.tracing.sampling", From: 1, To: 10},
+		{Path: "trustDomainAliases", To: []interface{}{"cluster.local"}},
+	}, deltas)
+
+	k8s.AssertCalled(t, "GetDeployments", mock.Anything)
+}
+
+func TestNamespaceRevisions(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespaces := []models.Namespace{
+		{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}},
+		{Name: "travels", Labels: map[string]string{istioRevisionLabel: "1-8-1"}},
+		{Name: "legacy"},
+		{Name: "other", Labels: map[string]string{istioRevisionLabel: "1-9-0"}},
+	}
+	pods := map[string][]core_v1.Pod{
+		"bookinfo": {fakeProxyPod("reviews", "", "docker.io/istio/proxyv2:1.7.4"), fakeProxyPod("ratings", "", "docker.io/istio/proxyv2:1.7.4"), {}},
+		"travels":  {fakeProxyPod("cars", "1-8-1", "docker.io/istio/proxyv2:1.8.1"), fakeProxyPod("hotels", "default", "docker.io/istio/proxyv2:1.7.4")},
+		"legacy":   {{}},
+		"other":    {fakeProxyPod("flights", "1-9-0", "docker.io/istio/proxyv2:1.9.0")},
+	}
+
+	revisions := namespaceRevisions(namespaces, pods, "default", "1-8-1")
+	assert.Len(revisions, 2)
+	assert.Equal("bookinfo", revisions[0].Namespace)
+	assert.Equal("default", revisions[0].Revision)
+	assert.Equal([]models.ProxyVersionCount{{Revision: "default", Version: "1.7.4", Count: 2}}, revisions[0].Proxies)
+	assert.False(revisions[0].RestartRequired)
+	assert.Equal("travels", revisions[1].Namespace)
+	assert.Equal("1-8-1", revisions[1].Revision)
+	assert.Equal([]models.ProxyVersionCount{{Revision: "1-8-1", Version: "1.8.1", Count: 1}, {Revision: "default", Version: "1.7.4", Count: 1}}, revisions[1].Proxies)
+	assert.True(revisions[1].RestartRequired)
+}
+
+func TestImageTag(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("1.8.1", imageTag("docker.io/istio/proxyv2:1.8.1"))
+	assert.Equal("1.8.1", imageTag("registry:5000/istio/proxyv2:1.8.1@sha256:abc"))
+	assert.Equal("", imageTag("registry:5000/istio/proxyv2"))
+}
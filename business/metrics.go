@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
 )
@@ -72,9 +73,21 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 		*histo = h
 	}
 
+	fetchExemplars := func(definition istioMetric, exemplars *[]prometheus.ExemplarSeries) {
+		defer wg.Done()
+		e, err := in.prom.FetchExemplars(definition.exemplarsName(), definition.labelsToUse(labels, labelsError), &q.RangeQuery)
+		if err != nil {
+			// Prometheus may not store exemplars, metrics are still returned
+			log.Debugf("Could not fetch exemplars of metric %s: %v", definition.kialiName, err)
+			return
+		}
+		*exemplars = e
+	}
+
 	type resultHolder struct {
 		metric     prometheus.Metric
 		histo      prometheus.Histogram
+		exemplars  []prometheus.ExemplarSeries
 		definition istioMetric
 	}
 	maxResults := len(istioMetrics)
@@ -104,6 +117,10 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 				labelsToUse := istioMetric.labelsToUse(labels, labelsError)
 				go fetchRate(istioMetric.istioName, &result.metric, labelsToUse)
 			}
+			if q.Exemplars && istioMetric.hasExemplars {
+				wg.Add(1)
+				go fetchExemplars(istioMetric, &result.exemplars)
+			}
 		}
 	}
 	wg.Wait()
@@ -132,6 +149,9 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 					return nil, err
 				}
 			}
+			if len(result.exemplars) > 0 {
+				models.AttachExemplars(converted, result.exemplars, conversionParams)
+			}
 			metrics[result.definition.kialiName] = append(metrics[result.definition.kialiName], converted...)
 		}
 	}
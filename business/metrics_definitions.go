@@ -5,24 +5,29 @@ type istioMetric struct {
 	istioName      string
 	isHisto        bool
 	useErrorLabels bool
+	// Exemplars are recorded by the proxies for the requests, linking the metric to the traces
+	hasExemplars bool
 }
 
 var istioMetrics = []istioMetric{
 	{
-		kialiName: "request_count",
-		istioName: "istio_requests_total",
-		isHisto:   false,
+		kialiName:    "request_count",
+		istioName:    "istio_requests_total",
+		isHisto:      false,
+		hasExemplars: true,
 	},
 	{
 		kialiName:      "request_error_count",
 		istioName:      "istio_requests_total",
 		isHisto:        false,
 		useErrorLabels: true,
+		hasExemplars:   true,
 	},
 	{
-		kialiName: "request_duration_millis",
-		istioName: "istio_request_duration_milliseconds",
-		isHisto:   true,
+		kialiName:    "request_duration_millis",
+		istioName:    "istio_request_duration_milliseconds",
+		isHisto:      true,
+		hasExemplars: true,
 	},
 	{
 		kialiName: "request_throughput",
@@ -62,3 +67,11 @@ func (in *istioMetric) labelsToUse(labels string, labelsError []string) []string
 	}
 	return []string{labels}
 }
+
+// exemplarsName returns the name of the series holding the exemplars of the metric
+func (in *istioMetric) exemplarsName() string {
+	if in.isHisto {
+		return in.istioName + "_bucket"
+	}
+	return in.istioName
+}
@@ -10,6 +10,9 @@ const (
 	source                     = "source"
 	regexGrpcResponseStatusErr = "^[1-9]$|^1[0-6]$"
 	regexResponseCodeErr       = "^0$|^[4-5]\\\\d\\\\d$"
+	// Server errors, as opposed to errors caused by the client
+	regexGrpcResponseStatusServerErr = "^2$|^4$|^1[2-5]$"
+	regexResponseCodeServerErr       = "^0$|^5\\\\d\\\\d$"
 )
 
 type MetricsLabelsBuilder struct {
@@ -37,6 +40,12 @@ func (lb *MetricsLabelsBuilder) Add(key, value string) *MetricsLabelsBuilder {
 	return lb
 }
 
+// Exclude matches the series whose label has another value
+func (lb *MetricsLabelsBuilder) Exclude(key, value string) *MetricsLabelsBuilder {
+	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s!="%s"`, key, value))
+	return lb
+}
+
 func (lb *MetricsLabelsBuilder) addSided(partialKey, value, side string) *MetricsLabelsBuilder {
 	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s_%s="%s"`, side, partialKey, value))
 	return lb
@@ -140,3 +149,16 @@ func (lb *MetricsLabelsBuilder) BuildForErrors() []string {
 	}
 	return errors
 }
+
+// BuildForServerErrors returns the labels of the requests failing because of the server, which consume the error
+// budget of the availability objectives
+func (lb *MetricsLabelsBuilder) BuildForServerErrors() []string {
+	errors := []string{}
+	httpLabels := append(lb.labelsKV[:len(lb.labelsKV):len(lb.labelsKV)], fmt.Sprintf(`response_code=~"%s"`, regexResponseCodeServerErr))
+	errors = append(errors, "{"+strings.Join(httpLabels, ",")+"}")
+	if lb.protocol != "http" {
+		grpcLabels := append(lb.labelsKV[:len(lb.labelsKV):len(lb.labelsKV)], fmt.Sprintf(`grpc_response_status=~"%s",response_code!~"%s"`, regexGrpcResponseStatusServerErr, regexResponseCodeServerErr))
+		errors = append(errors, "{"+strings.Join(grpcLabels, ",")+"}")
+	}
+	return errors
+}
@@ -438,3 +438,51 @@ func createSample(value float64) *model.Sample {
 		Metric:    model.Metric{},
 	}
 }
+
+func TestGetMetricsWithExemplars(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	prom := new(prometheustest.PromClientMock)
+	srv := NewMetricsService(prom)
+
+	q := models.IstioMetricsQuery{
+		Namespace: "bookinfo",
+		App:       "productpage",
+		Exemplars: true,
+	}
+	q.FillDefaults()
+	q.Filters = []string{"request_count"}
+	q.ByLabels = []string{"response_code"}
+
+	labels := `{reporter="source",source_workload_namespace="bookinfo",source_canonical_service="productpage"}`
+	prom.On("FetchRateRange", "istio_requests_total", []string{labels}, "response_code", &q.RangeQuery).Return(prometheus.Metric{
+		Matrix: model.Matrix{
+			&model.SampleStream{Metric: model.Metric{"response_code": "200"}, Values: []model.SamplePair{{Timestamp: 0, Value: 1}}},
+			&model.SampleStream{Metric: model.Metric{"response_code": "500"}, Values: []model.SamplePair{{Timestamp: 0, Value: 1}}},
+		},
+	})
+	prom.On("FetchExemplars", "istio_requests_total", []string{labels}, &q.RangeQuery).Return([]prometheus.ExemplarSeries{
+		{
+			SeriesLabels: model.LabelSet{"response_code": "200", "reporter": "source"},
+			Exemplars: []prometheus.Exemplar{
+				{Labels: model.LabelSet{"trace_id": "t2"}, Value: 1, Timestamp: 2000},
+				{Labels: model.LabelSet{"trace_id": "t1"}, Value: 1, Timestamp: 1000},
+				{Labels: model.LabelSet{"span_id": "no-trace"}, Value: 1, Timestamp: 3000},
+			},
+		},
+	}, nil)
+
+	metrics, err := srv.GetMetrics(q, nil)
+	assert.Nil(err)
+	rqCount := metrics["request_count"]
+	assert.Len(rqCount, 2)
+	for _, m := range rqCount {
+		if m.Labels["response_code"] == "200" {
+			assert.Len(m.Exemplars, 2)
+			assert.Equal("t1", m.Exemplars[0].TraceID)
+			assert.Equal("t2", m.Exemplars[1].TraceID)
+		} else {
+			assert.Empty(m.Exemplars)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Annotations of the namespace-wide PeerAuthentication keeping the state of its mTLS rollout
+const (
+	mtlsRolloutStageAnnotation        = "wizard.kiali.io/mtls-rollout-stage"
+	mtlsRolloutStartedAtAnnotation    = "wizard.kiali.io/mtls-rollout-started-at"
+	mtlsRolloutSoakAnnotation         = "wizard.kiali.io/mtls-rollout-soak"
+	mtlsRolloutPreviousModeAnnotation = "wizard.kiali.io/mtls-rollout-previous-mode"
+)
+
+const (
+	// Name of the PeerAuthentication applying to a whole namespace
+	namespaceWidePeerAuthentication = "default"
+	defaultMTLSRolloutSoak          = "24h"
+	// Previous mode of a PeerAuthentication without mTLS mode, inheriting it
+	mtlsModeUnset = "UNSET"
+)
+
+// StartMTLSRollout starts the staged STRICT mTLS enablement of a namespace: its namespace-wide PeerAuthentication is
+// set to PERMISSIVE, so that the plaintext traffic can be watched over the soak window. A started rollout is resumed.
+func (in *TLSService) StartMTLSRollout(namespace string, request models.MTLSRolloutRequest) (*models.MTLSRollout, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "TLSService", "StartMTLSRollout")
+	defer promtimer.ObserveNow(&err)
+
+	if request.Soak == "" {
+		request.Soak = defaultMTLSRolloutSoak
+	}
+	if soak, parseErr := pmod.ParseDuration(request.Soak); parseErr != nil || soak <= 0 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid soak [%s], expected a duration such as 24h", request.Soak))
+		return nil, err
+	}
+	if namespace == config.Get().IstioNamespace {
+		// Its namespace-wide PeerAuthentication applies to the whole mesh
+		err = errors.NewBadRequest("the mTLS of the namespace of the control plane is mesh-wide, it cannot be rolled out by namespace")
+		return nil, err
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	api := kubernetes.ResourceTypesToAPI[kubernetes.PeerAuthentications]
+	annotations := map[string]interface{}{
+		mtlsRolloutStageAnnotation:     models.MTLSRolloutPermissive,
+		mtlsRolloutStartedAtAnnotation: util.Clock.Now().UTC().Format(time.RFC3339),
+		mtlsRolloutSoakAnnotation:      request.Soak,
+	}
+	pa, err := in.k8s.GetIstioObject(namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication)
+	if errors.IsNotFound(err) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": kubernetes.ApiSecurityVersion,
+			"kind":       kubernetes.PluralType[kubernetes.PeerAuthentications],
+			"metadata":   map[string]interface{}{"name": namespaceWidePeerAuthentication, "namespace": namespace, "annotations": annotations},
+			"spec":       map[string]interface{}{"mtls": map[string]interface{}{"mode": "PERMISSIVE"}},
+		})
+		if _, err = in.k8s.CreateIstioObject(api, namespace, kubernetes.PeerAuthentications, string(body)); err != nil {
+			return nil, err
+		}
+		return in.GetMTLSRollout(namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pa.GetObjectMeta().Annotations[mtlsRolloutStageAnnotation]; ok {
+		return in.GetMTLSRollout(namespace)
+	}
+
+	previousMode := peerAuthenticationMode(pa)
+	if previousMode == "STRICT" {
+		err = errors.NewBadRequest(fmt.Sprintf("the mTLS of namespace %s is already STRICT", namespace))
+		return nil, err
+	}
+	annotations[mtlsRolloutPreviousModeAnnotation] = previousMode
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+		"spec":     map[string]interface{}{"mtls": map[string]interface{}{"mode": "PERMISSIVE"}},
+	})
+	if _, err = in.k8s.UpdateIstioObject(api, namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication, string(patch)); err != nil {
+		return nil, err
+	}
+	return in.GetMTLSRollout(namespace)
+}
+
+// GetMTLSRollout returns the state of the mTLS rollout of a namespace, with the clients which sent plaintext requests
+// to it since the start of the rollout
+func (in *TLSService) GetMTLSRollout(namespace string) (*models.MTLSRollout, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "TLSService", "GetMTLSRollout")
+	defer promtimer.ObserveNow(&err)
+
+	pa, err := in.k8s.GetIstioObject(namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	rollout, err := mtlsRolloutState(namespace, pa)
+	if err != nil {
+		return nil, err
+	}
+
+	now := util.Clock.Now()
+	rollout.SoakCompleted = !now.Before(rollout.SoakEndsAt)
+	window := now.Sub(rollout.StartedAt)
+	if soak := rollout.SoakEndsAt.Sub(rollout.StartedAt); window > soak {
+		window = soak
+	}
+	if window < time.Minute {
+		window = time.Minute
+	}
+	if rollout.PlaintextSources, err = in.getPlaintextSources(namespace, pmod.Duration(window.Truncate(time.Second)).String(), now); err != nil {
+		return nil, err
+	}
+	rollout.ReadyForStrict = rollout.Stage == models.MTLSRolloutPermissive && rollout.SoakCompleted && len(rollout.PlaintextSources) == 0
+	return rollout, nil
+}
+
+// StrictMTLSRollout flips the mTLS rollout of a namespace to STRICT, once its soak window completed without plaintext
+// traffic, or when forced
+func (in *TLSService) StrictMTLSRollout(namespace string, request models.MTLSRolloutStrictRequest) (*models.MTLSRollout, error) {
+	rollout, err := in.GetMTLSRollout(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Stage != models.MTLSRolloutPermissive {
+		return nil, errors.NewBadRequest(fmt.Sprintf("the mTLS rollout of namespace %s is already %s", namespace, rollout.Stage))
+	}
+	if !rollout.ReadyForStrict && !request.Force {
+		if !rollout.SoakCompleted {
+			return nil, errors.NewBadRequest(fmt.Sprintf("the soak window of the mTLS rollout of namespace %s ends at %s", namespace, rollout.SoakEndsAt.Format(time.RFC3339)))
+		}
+		return nil, errors.NewBadRequest(fmt.Sprintf("%d clients still send plaintext requests to namespace %s", len(rollout.PlaintextSources), namespace))
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{mtlsRolloutStageAnnotation: models.MTLSRolloutStrict}},
+		"spec":     map[string]interface{}{"mtls": map[string]interface{}{"mode": "STRICT"}},
+	})
+	api := kubernetes.ResourceTypesToAPI[kubernetes.PeerAuthentications]
+	if _, err = in.k8s.UpdateIstioObject(api, namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication, string(patch)); err != nil {
+		return nil, err
+	}
+	rollout.Stage = models.MTLSRolloutStrict
+	rollout.ReadyForStrict = false
+	return rollout, nil
+}
+
+// AbortMTLSRollout restores the mTLS of a namespace as it was before its rollout: the PeerAuthentication created by
+// the rollout is deleted, the mode of the others is restored
+func (in *TLSService) AbortMTLSRollout(namespace string) error {
+	pa, err := in.k8s.GetIstioObject(namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication)
+	if err != nil {
+		return err
+	}
+	rollout, err := mtlsRolloutState(namespace, pa)
+	if err != nil {
+		return err
+	}
+
+	api := kubernetes.ResourceTypesToAPI[kubernetes.PeerAuthentications]
+	if _, existed := pa.GetObjectMeta().Annotations[mtlsRolloutPreviousModeAnnotation]; !existed {
+		return in.k8s.DeleteIstioObject(api, namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication)
+	}
+	var mode interface{} = rollout.PreviousMode
+	if rollout.PreviousMode == mtlsModeUnset {
+		mode = nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+			mtlsRolloutStageAnnotation:        nil,
+			mtlsRolloutStartedAtAnnotation:    nil,
+			mtlsRolloutSoakAnnotation:         nil,
+			mtlsRolloutPreviousModeAnnotation: nil,
+		}},
+		"spec": map[string]interface{}{"mtls": map[string]interface{}{"mode": mode}},
+	})
+	_, err = in.k8s.UpdateIstioObject(api, namespace, kubernetes.PeerAuthentications, namespaceWidePeerAuthentication, string(patch))
+	return err
+}
+
+// getPlaintextSources returns the clients whose requests to the services of a namespace were not mutual TLS, as
+// reported by the destinations
+func (in *TLSService) getPlaintextSources(namespace, window string, queryTime time.Time) ([]models.PlaintextSource, error) {
+	rates, err := in.prom.GetNamespaceServicesRequestRates(namespace, window, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	bySource := map[models.PlaintextSource]float64{}
+	for _, sample := range rates {
+		m := sample.Metric
+		if m["reporter"] != "destination" || m["connection_security_policy"] == "mutual_tls" {
+			continue
+		}
+		source := models.PlaintextSource{
+			Namespace: string(m["source_workload_namespace"]),
+			Workload:  string(m["source_workload"]),
+			Service:   string(m["destination_service_name"]),
+		}
+		bySource[source] += float64(sample.Value)
+	}
+	sources := make([]models.PlaintextSource, 0, len(bySource))
+	for source, rate := range bySource {
+		source.RequestRate = rate
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].RequestRate > sources[j].RequestRate })
+	return sources, nil
+}
+
+// mtlsRolloutState returns the state of an mTLS rollout kept in the annotations of a PeerAuthentication
+func mtlsRolloutState(namespace string, pa kubernetes.IstioObject) (*models.MTLSRollout, error) {
+	annotations := pa.GetObjectMeta().Annotations
+	stage, ok := annotations[mtlsRolloutStageAnnotation]
+	if !ok {
+		return nil, kubernetes.NewNotFound(namespace, "kiali.io", "mtls rollouts")
+	}
+	startedAt, err := time.Parse(time.RFC3339, annotations[mtlsRolloutStartedAtAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of PeerAuthentication %s: %v", mtlsRolloutStartedAtAnnotation, namespaceWidePeerAuthentication, err)
+	}
+	soak, err := pmod.ParseDuration(annotations[mtlsRolloutSoakAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of PeerAuthentication %s: %v", mtlsRolloutSoakAnnotation, namespaceWidePeerAuthentication, err)
+	}
+	return &models.MTLSRollout{
+		Namespace:          namespace,
+		PeerAuthentication: namespaceWidePeerAuthentication,
+		Stage:              stage,
+		PreviousMode:       annotations[mtlsRolloutPreviousModeAnnotation],
+		StartedAt:          startedAt,
+		Soak:               annotations[mtlsRolloutSoakAnnotation],
+		SoakEndsAt:         startedAt.Add(time.Duration(soak)),
+		PlaintextSources:   []models.PlaintextSource{},
+	}, nil
+}
+
+func peerAuthenticationMode(pa kubernetes.IstioObject) string {
+	if mtls, ok := pa.GetSpec()["mtls"].(map[string]interface{}); ok {
+		if mode, ok := mtls["mode"].(string); ok && mode != "" {
+			return mode
+		}
+	}
+	return mtlsModeUnset
+}
@@ -0,0 +1,130 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeRolloutPeerAuthentication(annotations map[string]string, mode string) *kubernetes.GenericIstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "default", Namespace: "bookinfo", Annotations: annotations},
+		Spec:       map[string]interface{}{"mtls": map[string]interface{}{"mode": mode}},
+	}
+}
+
+func TestStartMTLSRollout(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.PeerAuthentications, "default").Return(fakeRolloutPeerAuthentication(nil, "DISABLE"), nil).Once()
+	var patch string
+	k8s.On("UpdateIstioObject", "security.istio.io", "bookinfo", kubernetes.PeerAuthentications, "default", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { patch = args.String(4) }).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.PeerAuthentications, "default").Return(fakeRolloutPeerAuthentication(map[string]string{
+		mtlsRolloutStageAnnotation:        "permissive",
+		mtlsRolloutStartedAtAnnotation:    "2021-03-01T10:00:00Z",
+		mtlsRolloutSoakAnnotation:         "1d",
+		mtlsRolloutPreviousModeAnnotation: "DISABLE",
+	}, "PERMISSIVE"), nil)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetNamespaceServicesRequestRates", "bookinfo", "1m", mock.Anything).Return(pmod.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	rollout, err := layer.TLS.StartMTLSRollout("bookinfo", models.MTLSRolloutRequest{Soak: "1d"})
+	assert.NoError(err)
+	assert.JSONEq(`{"metadata":{"annotations":{
+		"wizard.kiali.io/mtls-rollout-stage": "permissive",
+		"wizard.kiali.io/mtls-rollout-started-at": "2021-03-01T10:00:00Z",
+		"wizard.kiali.io/mtls-rollout-soak": "1d",
+		"wizard.kiali.io/mtls-rollout-previous-mode": "DISABLE"
+	}},"spec":{"mtls":{"mode":"PERMISSIVE"}}}`, patch)
+	assert.Equal(models.MTLSRolloutPermissive, rollout.Stage)
+	assert.Equal(time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC), rollout.SoakEndsAt)
+	assert.False(rollout.SoakCompleted)
+	assert.False(rollout.ReadyForStrict)
+
+	// Resumed
+	_, err = layer.TLS.StartMTLSRollout("bookinfo", models.MTLSRolloutRequest{})
+	assert.NoError(err)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+
+	_, err = layer.TLS.StartMTLSRollout("istio-system", models.MTLSRolloutRequest{})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestStrictMTLSRollout(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 3, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.PeerAuthentications, "default").Return(fakeRolloutPeerAuthentication(map[string]string{
+		mtlsRolloutStageAnnotation:     "permissive",
+		mtlsRolloutStartedAtAnnotation: "2021-03-01T10:00:00Z",
+		mtlsRolloutSoakAnnotation:      "1d",
+	}, "PERMISSIVE"), nil)
+	k8s.On("UpdateIstioObject", "security.istio.io", "bookinfo", kubernetes.PeerAuthentications, "default", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("DeleteIstioObject", "security.istio.io", "bookinfo", kubernetes.PeerAuthentications, "default").Return(nil)
+	prom := new(prometheustest.PromClientMock)
+	plaintext := pmod.Vector{
+		{Metric: pmod.Metric{"reporter": "destination", "connection_security_policy": "none", "source_workload_namespace": "unknown", "source_workload": "unknown", "destination_service_name": "reviews"}, Value: 2},
+		{Metric: pmod.Metric{"reporter": "source", "connection_security_policy": "unknown", "source_workload_namespace": "bookinfo", "source_workload": "productpage-v1", "destination_service_name": "reviews"}, Value: 5},
+		{Metric: pmod.Metric{"reporter": "destination", "connection_security_policy": "mutual_tls", "source_workload_namespace": "bookinfo", "source_workload": "productpage-v1", "destination_service_name": "reviews"}, Value: 5},
+	}
+	prom.On("GetNamespaceServicesRequestRates", "bookinfo", "1d", mock.Anything).Return(plaintext, nil).Once()
+	prom.On("GetNamespaceServicesRequestRates", "bookinfo", "1d", mock.Anything).Return(plaintext[1:], nil)
+	svc := TLSService{k8s: k8s, prom: prom}
+
+	_, err := svc.StrictMTLSRollout("bookinfo", models.MTLSRolloutStrictRequest{})
+	assert.True(errors.IsBadRequest(err))
+
+	rollout, err := svc.StrictMTLSRollout("bookinfo", models.MTLSRolloutStrictRequest{})
+	assert.NoError(err)
+	assert.Equal(models.MTLSRolloutStrict, rollout.Stage)
+	update := k8s.Calls[len(k8s.Calls)-1].Arguments.String(4)
+	var patch map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(update), &patch))
+	assert.Equal(map[string]interface{}{"mtls": map[string]interface{}{"mode": "STRICT"}}, patch["spec"])
+
+	// Created by the rollout
+	assert.NoError(svc.AbortMTLSRollout("bookinfo"))
+	k8s.AssertCalled(t, "DeleteIstioObject", "security.istio.io", "bookinfo", kubernetes.PeerAuthentications, "default")
+}
+
+func TestGetPlaintextSources(t *testing.T) {
+	assert := assert.New(t)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetNamespaceServicesRequestRates", "bookinfo", "1h", mock.Anything).Return(pmod.Vector{
+		{Metric: pmod.Metric{"reporter": "destination", "connection_security_policy": "none", "source_workload_namespace": "legacy", "source_workload": "cron", "destination_service_name": "reviews"}, Value: 1},
+		{Metric: pmod.Metric{"reporter": "destination", "connection_security_policy": "none", "source_workload_namespace": "legacy", "source_workload": "cron", "destination_service_name": "reviews", "response_code": "500"}, Value: 0.5},
+		{Metric: pmod.Metric{"reporter": "destination", "connection_security_policy": "none", "source_workload_namespace": "unknown", "source_workload": "unknown", "destination_service_name": "ratings"}, Value: 3},
+	}, nil)
+	svc := TLSService{prom: prom}
+
+	sources, err := svc.getPlaintextSources("bookinfo", "1h", time.Now())
+	assert.NoError(err)
+	assert.Equal([]models.PlaintextSource{
+		{Namespace: "unknown", Workload: "unknown", Service: "ratings", RequestRate: 3},
+		{Namespace: "legacy", Workload: "cron", Service: "reviews", RequestRate: 1.5},
+	}, sources)
+}
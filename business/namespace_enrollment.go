@@ -0,0 +1,225 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	// Label enrolling a namespace in ambient
+	ambientDataplaneModeLabel = "istio.io/dataplane-mode"
+	// Annotation set by the CNI on the pods whose traffic is redirected to ztunnel
+	ambientRedirectionAnnotation = "ambient.istio.io/redirection"
+)
+
+// GetNamespaceEnrollment returns the enrollment of a namespace in the mesh, with the revisions it can be enrolled in
+func (in *MeshService) GetNamespaceEnrollment(namespace string) (*models.NamespaceEnrollment, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "GetNamespaceEnrollment")
+	defer promtimer.ObserveNow(&err)
+
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods([]models.Namespace{*ns})
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := in.GetControlPlaneRevisions()
+	if err != nil {
+		return nil, err
+	}
+	return namespaceEnrollment(*ns, pods[namespace], revisions), nil
+}
+
+// PreflightNamespaceEnrollment runs the checks of the enrollment of a namespace, without enrolling it
+func (in *MeshService) PreflightNamespaceEnrollment(namespace string, request models.EnrollmentRequest) (*models.EnrollmentPreflight, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "PreflightNamespaceEnrollment")
+	defer promtimer.ObserveNow(&err)
+
+	if request.Mode != models.EnrollmentModeSidecar && request.Mode != models.EnrollmentModeAmbient {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid mode [%s], expected sidecar or ambient", request.Mode))
+		return nil, err
+	}
+	if request.Mode == models.EnrollmentModeAmbient && request.Revision != "" {
+		err = errors.NewBadRequest("a revision can only be picked for the sidecar mode")
+		return nil, err
+	}
+
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods([]models.Namespace{*ns})
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []models.ControlPlaneRevision
+	var ztunnels []core_v1.Pod
+	var readiness *models.AmbientReadiness
+	if request.Mode == models.EnrollmentModeSidecar {
+		if revisions, err = in.GetControlPlaneRevisions(); err != nil {
+			return nil, err
+		}
+	} else {
+		istioNamespace := config.Get().IstioNamespace
+		if IsNamespaceCached(istioNamespace) {
+			ztunnels, err = kialiCache.GetPods(istioNamespace, ztunnelLabelSelector)
+		} else {
+			ztunnels, err = in.k8s.GetPods(istioNamespace, ztunnelLabelSelector)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if readiness, err = in.GetAmbientReadiness(namespace); err != nil {
+			return nil, err
+		}
+	}
+	return enrollmentPreflight(namespace, request, pods[namespace], revisions, ztunnels, readiness), nil
+}
+
+// EnrollNamespace labels a namespace to enroll it in the sidecar or ambient data plane, once the preflight checks pass.
+// Its pods have to be restarted to join the data plane.
+func (in *MeshService) EnrollNamespace(namespace string, request models.EnrollmentRequest) (*models.NamespaceEnrollment, error) {
+	preflight, err := in.PreflightNamespaceEnrollment(namespace, request)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range preflight.Checks {
+		if check.Blocking && !check.Passed {
+			return nil, errors.NewBadRequest(fmt.Sprintf("namespace %s cannot be enrolled: %s", namespace, check.Message))
+		}
+	}
+	if _, err = in.businessLayer.Namespace.UpdateNamespace(namespace, enrollmentPatch(request)); err != nil {
+		return nil, err
+	}
+	return in.GetNamespaceEnrollment(namespace)
+}
+
+// UnenrollNamespace removes the labels enrolling a namespace in the mesh. Its pods keep their sidecars until restarted.
+func (in *MeshService) UnenrollNamespace(namespace string) (*models.NamespaceEnrollment, error) {
+	if _, err := in.businessLayer.Namespace.UpdateNamespace(namespace, enrollmentPatch(models.EnrollmentRequest{})); err != nil {
+		return nil, err
+	}
+	return in.GetNamespaceEnrollment(namespace)
+}
+
+func namespaceEnrollment(ns models.Namespace, pods []core_v1.Pod, revisions []models.ControlPlaneRevision) *models.NamespaceEnrollment {
+	enrollment := &models.NamespaceEnrollment{Namespace: ns.Name, Pods: len(pods), Revisions: revisions}
+	if ns.Labels[ambientDataplaneModeLabel] == models.EnrollmentModeAmbient {
+		enrollment.Mode = models.EnrollmentModeAmbient
+	} else if revision := namespaceRevision(ns); revision != "" {
+		enrollment.Mode = models.EnrollmentModeSidecar
+		enrollment.Revision = revision
+	}
+	for _, pod := range pods {
+		switch enrollment.Mode {
+		case models.EnrollmentModeAmbient:
+			if pod.Annotations[ambientRedirectionAnnotation] == "enabled" {
+				enrollment.EnrolledPods++
+			}
+		case models.EnrollmentModeSidecar:
+			if revision, _, ok := podProxy(pod); ok && revision == enrollment.Revision {
+				enrollment.EnrolledPods++
+			}
+		}
+	}
+	return enrollment
+}
+
+func enrollmentPreflight(namespace string, request models.EnrollmentRequest, pods []core_v1.Pod, revisions []models.ControlPlaneRevision, ztunnels []core_v1.Pod, readiness *models.AmbientReadiness) *models.EnrollmentPreflight {
+	preflight := &models.EnrollmentPreflight{Namespace: namespace, Mode: request.Mode, Revision: request.Revision, Ready: true, Checks: []models.EnrollmentCheck{}}
+	add := func(name string, passed, blocking bool, message string) {
+		preflight.Checks = append(preflight.Checks, models.EnrollmentCheck{Name: name, Passed: passed, Blocking: blocking, Message: message})
+		if blocking && !passed {
+			preflight.Ready = false
+		}
+	}
+
+	if namespace == config.Get().IstioNamespace {
+		add("namespace", false, true, "The namespace of the control plane cannot be enrolled")
+	} else {
+		add("namespace", true, true, "The namespace is not the namespace of the control plane")
+	}
+
+	if request.Mode == models.EnrollmentModeSidecar {
+		if preflight.Revision == "" {
+			preflight.Revision = defaultRevision
+		}
+		var revision *models.ControlPlaneRevision
+		for i := range revisions {
+			if revisions[i].Revision == preflight.Revision {
+				revision = &revisions[i]
+			}
+		}
+		switch {
+		case revision == nil:
+			add("revision", false, true, fmt.Sprintf("No istiod of revision %s runs in the mesh", preflight.Revision))
+		case revision.Status != Healthy:
+			add("revision", false, false, fmt.Sprintf("The istiod of revision %s is %s: it may not inject the sidecars", revision.Revision, revision.Status))
+		default:
+			add("revision", true, true, fmt.Sprintf("The istiod of revision %s %s is healthy", revision.Revision, revision.Version))
+		}
+
+		restart := 0
+		for _, pod := range pods {
+			if podRevision, _, ok := podProxy(pod); !ok || podRevision != preflight.Revision {
+				restart++
+			}
+		}
+		add("restart", restart == 0, false, fmt.Sprintf("%d of %d pods have to be restarted to get a sidecar of revision %s", restart, len(pods), preflight.Revision))
+	} else {
+		add("ztunnel", len(ztunnels) > 0, true, fmt.Sprintf("%d ztunnel pods run in the mesh", len(ztunnels)))
+		if readiness != nil {
+			blockers := 0
+			for _, f := range readiness.Findings {
+				if f.Severity == models.AmbientFindingBlocker {
+					blockers++
+				}
+			}
+			add("ambientReadiness", readiness.Ready, true, fmt.Sprintf("%d findings block the migration to ambient, readiness score %d", blockers, readiness.Score))
+		}
+
+		restart := 0
+		for _, pod := range pods {
+			if _, _, ok := podProxy(pod); ok {
+				restart++
+			}
+		}
+		add("restart", restart == 0, false, fmt.Sprintf("%d of %d pods have to be restarted to remove their sidecar", restart, len(pods)))
+	}
+	return preflight
+}
+
+// enrollmentPatch returns the JSON merge patch setting the labels of a mode, removing those of the other modes. It
+// removes all of them without mode.
+func enrollmentPatch(request models.EnrollmentRequest) string {
+	injectionLabel := config.Get().IstioLabels.InjectionLabelName
+	labels := map[string]interface{}{
+		injectionLabel:            nil,
+		istioRevisionLabel:        nil,
+		ambientDataplaneModeLabel: nil,
+	}
+	switch request.Mode {
+	case models.EnrollmentModeAmbient:
+		labels[ambientDataplaneModeLabel] = models.EnrollmentModeAmbient
+	case models.EnrollmentModeSidecar:
+		if request.Revision == "" || request.Revision == defaultRevision {
+			labels[injectionLabel] = "enabled"
+		} else {
+			labels[istioRevisionLabel] = request.Revision
+		}
+	}
+	patch, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+	return string(patch)
+}
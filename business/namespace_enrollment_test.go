@@ -0,0 +1,105 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestNamespaceEnrollment(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pods := []core_v1.Pod{
+		fakeProxyPod("reviews-v1", "1-8-1", "docker.io/istio/proxyv2:1.8.1"),
+		fakeProxyPod("reviews-v2", "", "docker.io/istio/proxyv2:1.7.4"),
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-v1", Annotations: map[string]string{ambientRedirectionAnnotation: "enabled"}}},
+	}
+
+	ns := models.Namespace{Name: "bookinfo", Labels: map[string]string{istioRevisionLabel: "1-8-1"}}
+	enrollment := namespaceEnrollment(ns, pods, []models.ControlPlaneRevision{})
+	assert.Equal(models.EnrollmentModeSidecar, enrollment.Mode)
+	assert.Equal("1-8-1", enrollment.Revision)
+	assert.Equal(3, enrollment.Pods)
+	assert.Equal(1, enrollment.EnrolledPods)
+
+	ns.Labels = map[string]string{ambientDataplaneModeLabel: "ambient"}
+	enrollment = namespaceEnrollment(ns, pods, []models.ControlPlaneRevision{})
+	assert.Equal(models.EnrollmentModeAmbient, enrollment.Mode)
+	assert.Empty(enrollment.Revision)
+	assert.Equal(1, enrollment.EnrolledPods)
+
+	ns.Labels = map[string]string{}
+	enrollment = namespaceEnrollment(ns, pods, []models.ControlPlaneRevision{})
+	assert.Empty(enrollment.Mode)
+	assert.Equal(0, enrollment.EnrolledPods)
+}
+
+func TestEnrollmentPreflight(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pods := []core_v1.Pod{
+		fakeProxyPod("reviews-v1", "1-8-1", "docker.io/istio/proxyv2:1.8.1"),
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-v1"}},
+	}
+	revisions := []models.ControlPlaneRevision{
+		{Revision: "1-8-1", Version: "1.8.1", Status: Healthy},
+		{Revision: "default", Version: "1.7.4", Status: Unhealthy},
+	}
+	checks := func(preflight *models.EnrollmentPreflight) map[string]bool {
+		passed := map[string]bool{}
+		for _, c := range preflight.Checks {
+			passed[c.Name] = c.Passed
+		}
+		return passed
+	}
+
+	preflight := enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "sidecar", Revision: "1-8-1"}, pods, revisions, nil, nil)
+	assert.True(preflight.Ready)
+	assert.Equal(map[string]bool{"namespace": true, "revision": true, "restart": false}, checks(preflight))
+
+	// An unhealthy revision is a warning only
+	preflight = enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "sidecar"}, pods, revisions, nil, nil)
+	assert.True(preflight.Ready)
+	assert.Equal("default", preflight.Revision)
+	assert.Equal(map[string]bool{"namespace": true, "revision": false, "restart": false}, checks(preflight))
+
+	preflight = enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "sidecar", Revision: "1-9-0"}, pods, revisions, nil, nil)
+	assert.False(preflight.Ready)
+
+	preflight = enrollmentPreflight("istio-system", models.EnrollmentRequest{Mode: "sidecar", Revision: "1-8-1"}, pods, revisions, nil, nil)
+	assert.False(preflight.Ready)
+
+	ztunnels := []core_v1.Pod{{ObjectMeta: meta_v1.ObjectMeta{Name: "ztunnel-a"}}}
+	readiness := &models.AmbientReadiness{Ready: true, Score: 95}
+	preflight = enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "ambient"}, pods, nil, ztunnels, readiness)
+	assert.True(preflight.Ready)
+	assert.Equal(map[string]bool{"namespace": true, "ztunnel": true, "ambientReadiness": true, "restart": false}, checks(preflight))
+
+	preflight = enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "ambient"}, pods, nil, []core_v1.Pod{}, readiness)
+	assert.False(preflight.Ready)
+
+	readiness.Ready = false
+	preflight = enrollmentPreflight("bookinfo", models.EnrollmentRequest{Mode: "ambient"}, pods, nil, ztunnels, readiness)
+	assert.False(preflight.Ready)
+}
+
+func TestEnrollmentPatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	assert.JSONEq(`{"metadata":{"labels":{"istio-injection":"enabled","istio.io/rev":null,"istio.io/dataplane-mode":null}}}`,
+		enrollmentPatch(models.EnrollmentRequest{Mode: "sidecar"}))
+	assert.JSONEq(`{"metadata":{"labels":{"istio-injection":null,"istio.io/rev":"1-8-1","istio.io/dataplane-mode":null}}}`,
+		enrollmentPatch(models.EnrollmentRequest{Mode: "sidecar", Revision: "1-8-1"}))
+	assert.JSONEq(`{"metadata":{"labels":{"istio-injection":null,"istio.io/rev":null,"istio.io/dataplane-mode":"ambient"}}}`,
+		enrollmentPatch(models.EnrollmentRequest{Mode: "ambient"}))
+	assert.JSONEq(`{"metadata":{"labels":{"istio-injection":null,"istio.io/rev":null,"istio.io/dataplane-mode":null}}}`,
+		enrollmentPatch(models.EnrollmentRequest{}))
+}
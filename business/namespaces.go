@@ -19,6 +19,8 @@ type NamespaceService struct {
 	k8s                    kubernetes.ClientInterface
 	hasProjects            bool
 	isAccessibleNamespaces map[string]bool
+	// Namespace access rules that apply to the user, see RestrictToUser
+	accessRules []config.NamespaceAccessRule
 }
 
 type AccessibleNamespaceError struct {
@@ -57,15 +59,27 @@ func NewNamespaceService(k8s kubernetes.ClientInterface) NamespaceService {
 	}
 }
 
+// RestrictToUser applies the namespace access rules configured for the given user.
+// Restricted namespaces are hidden and inaccessible, even if the user has permissions on them in the cluster.
+func (in *NamespaceService) RestrictToUser(user models.UserIdentity) {
+	in.accessRules = nil
+	for _, rule := range config.Get().Auth.NamespaceAccess {
+		if rule.AppliesTo(user.Username, user.Groups) {
+			in.accessRules = append(in.accessRules, rule)
+		}
+	}
+}
+
 // Returns a list of the given namespaces / projects
 func (in *NamespaceService) GetNamespaces() ([]models.Namespace, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetNamespaces")
 	defer promtimer.ObserveNow(&err)
 
+	// The cache is shared by the users of a token, so the access rules are applied after reading from it
 	if kialiCache != nil {
 		if ns := kialiCache.GetNamespaces(in.k8s.GetToken()); ns != nil {
-			return ns, nil
+			return in.filterRestrictedNamespaces(ns), nil
 		}
 	}
 
@@ -150,7 +164,47 @@ func (in *NamespaceService) GetNamespaces() ([]models.Namespace, error) {
 		kialiCache.SetNamespaces(in.k8s.GetToken(), result)
 	}
 
-	return result, nil
+	return in.filterRestrictedNamespaces(result), nil
+}
+
+func (in *NamespaceService) filterRestrictedNamespaces(namespaces []models.Namespace) []models.Namespace {
+	if len(in.accessRules) == 0 {
+		return namespaces
+	}
+	result := []models.Namespace{}
+	for _, namespace := range namespaces {
+		if !in.isRestrictedNamespace(namespace.Name) {
+			result = append(result, namespace)
+		}
+	}
+	return result
+}
+
+// isRestrictedNamespace checks the namespace against the access rules of the user: it is restricted if any rule
+// denies it or if it is not allowed by any of the rules that have an allow list.
+func (in *NamespaceService) isRestrictedNamespace(namespace string) bool {
+	allowed, hasAllowList := false, false
+	for _, rule := range in.accessRules {
+		if matchesNamespacePattern(rule.Deny, namespace) {
+			return true
+		}
+		if len(rule.Allow) > 0 {
+			hasAllowList = true
+			allowed = allowed || matchesNamespacePattern(rule.Allow, namespace)
+		}
+	}
+	return hasAllowList && !allowed
+}
+
+// matchesNamespacePattern checks the namespace against the patterns of an access rule. The invalid patterns, reported
+// when the configuration is loaded, match no namespace.
+func matchesNamespacePattern(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if re, err := config.CompileNamespacePattern(pattern); err == nil && re.MatchString(namespace) {
+			return true
+		}
+	}
+	return false
 }
 
 func (in *NamespaceService) isAccessibleNamespace(namespace string) bool {
@@ -181,6 +235,11 @@ func (in *NamespaceService) GetNamespace(namespace string) (*models.Namespace, e
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetNamespace")
 	defer promtimer.ObserveNow(&err)
 
+	if in.isRestrictedNamespace(namespace) {
+		err = &AccessibleNamespaceError{msg: "Namespace [" + namespace + "] is not accessible for the user"}
+		return nil, err
+	}
+
 	// Cache already has included/excluded namespaces applied
 	if kialiCache != nil {
 		if ns := kialiCache.GetNamespace(in.k8s.GetToken(), namespace); ns != nil {
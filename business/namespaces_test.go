@@ -0,0 +1,88 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func setupNamespaceAccessRules() NamespaceService {
+	conf := config.NewConfig()
+	conf.API.Namespaces.Exclude = []string{}
+	conf.Auth.NamespaceAccess = []config.NamespaceAccessRule{
+		{Users: []string{"*"}, Deny: []string{"kube-.*"}},
+		{Groups: []string{"team-a"}, Allow: []string{"team-a-.*", "shared"}},
+		{Users: []string{"bob"}, Allow: []string{"team-b-.*"}, Deny: []string{"team-b-secret"}},
+	}
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "kube-system"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "shared"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "team-a-dev"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "team-b-dev"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "team-b-secret"}},
+	}, nil)
+	k8s.On("GetNamespace", "team-a-dev").Return(kubetest.FakeNamespace("team-a-dev"), nil)
+
+	return NewNamespaceService(k8s)
+}
+
+func namespaceNames(namespaces []models.Namespace) []string {
+	names := []string{}
+	for _, ns := range namespaces {
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+func TestNamespaceAccessRules(t *testing.T) {
+	assert := assert.New(t)
+	kialiCache = nil
+
+	nsService := setupNamespaceAccessRules()
+	namespaces, err := nsService.GetNamespaces()
+	assert.NoError(err)
+	assert.Len(namespaces, 5)
+
+	nsService.RestrictToUser(models.UserIdentity{Username: "alice"})
+	namespaces, err = nsService.GetNamespaces()
+	assert.NoError(err)
+	assert.Equal([]string{"shared", "team-a-dev", "team-b-dev", "team-b-secret"}, namespaceNames(namespaces))
+
+	nsService.RestrictToUser(models.UserIdentity{Username: "alice", Groups: []string{"team-a"}})
+	namespaces, err = nsService.GetNamespaces()
+	assert.NoError(err)
+	assert.Equal([]string{"shared", "team-a-dev"}, namespaceNames(namespaces))
+
+	// Allow lists of several rules are combined
+	nsService.RestrictToUser(models.UserIdentity{Username: "bob", Groups: []string{"team-a"}})
+	namespaces, err = nsService.GetNamespaces()
+	assert.NoError(err)
+	assert.Equal([]string{"shared", "team-a-dev", "team-b-dev"}, namespaceNames(namespaces))
+}
+
+func TestNamespaceAccessRulesDenyNamespace(t *testing.T) {
+	assert := assert.New(t)
+	kialiCache = nil
+
+	nsService := setupNamespaceAccessRules()
+	nsService.RestrictToUser(models.UserIdentity{Username: "bob"})
+
+	_, err := nsService.GetNamespace("team-a-dev")
+	assert.True(IsAccessibleError(err))
+
+	nsService.RestrictToUser(models.UserIdentity{Username: "alice", Groups: []string{"team-a"}})
+	ns, err := nsService.GetNamespace("team-a-dev")
+	assert.NoError(err)
+	assert.Equal("team-a-dev", ns.Name)
+}
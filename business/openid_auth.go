@@ -22,6 +22,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
 )
 
 const (
@@ -49,8 +50,10 @@ type OpenIdCallbackParams struct {
 	Nonce         string
 	NonceHash     []byte
 	ParsedIdToken *jwt.Token
-	State         string
-	Subject       string
+	// Given by the OpenId provider in the "authorization code" flow, to renew the id_token of the session
+	RefreshToken string
+	State        string
+	Subject      string
 }
 
 var cachedOpenIdKeySet *jose.JSONWebKeySet
@@ -58,14 +61,8 @@ var cachedOpenIdMetadata *OpenIdMetadata
 var openIdFlightGroup singleflight.Group
 
 func BuildOpenIdJwtClaims(openIdParams *OpenIdCallbackParams) *config.IanaClaims {
-	return &config.IanaClaims{
-		SessionId: openIdParams.IdToken,
-		StandardClaims: jwt.StandardClaims{
-			Subject:   openIdParams.Subject,
-			ExpiresAt: openIdParams.ExpiresOn.Unix(),
-			Issuer:    config.AuthStrategyOpenIdIssuer,
-		},
-	}
+	claims := config.NewSessionClaims(openIdParams.IdToken, nil, openIdParams.Subject, openIdParams.ExpiresOn, config.AuthStrategyOpenIdIssuer)
+	return &claims
 }
 
 func CallbackCleanup(w http.ResponseWriter) {
@@ -222,7 +219,7 @@ func GetJwkFromKeySet(keyId string) (*jose.JSONWebKey, error) {
 }
 
 func GetOpenIdAesSession(r *http.Request) (*config.IanaClaims, error) {
-	authCookie, err := r.Cookie(config.TokenCookieName + "-aes")
+	authCookie, err := httputil.GetChunkedCookie(r, config.TokenCookieName+"-aes")
 	if err != nil {
 		if err == http.ErrNoCookie {
 			return nil, nil
@@ -230,7 +227,7 @@ func GetOpenIdAesSession(r *http.Request) (*config.IanaClaims, error) {
 		return nil, err
 	}
 
-	cipherSessionData, err := base64.StdEncoding.DecodeString(authCookie.Value)
+	cipherSessionData, err := base64.StdEncoding.DecodeString(authCookie)
 	if err != nil {
 		return nil, err
 	}
@@ -446,6 +443,37 @@ func IsOpenIdCodeFlowPossible() bool {
 }
 
 func RequestOpenIdToken(openIdParams *OpenIdCallbackParams, redirect_uri string) error {
+	// Exchange authorization code for a token
+	requestParams := url.Values{}
+	requestParams.Set("code", openIdParams.Code)
+	requestParams.Set("grant_type", "authorization_code")
+	requestParams.Set("redirect_uri", redirect_uri)
+	return requestOpenIdTokens(requestParams, openIdParams)
+}
+
+// RefreshOpenIdToken gets a new id_token of the user from the OpenId provider, with the refresh token of the session.
+// The returned parameters hold the refresh token to use next time, which the provider may have rotated.
+func RefreshOpenIdToken(refreshToken string) (*OpenIdCallbackParams, error) {
+	requestParams := url.Values{}
+	requestParams.Set("grant_type", "refresh_token")
+	requestParams.Set("refresh_token", refreshToken)
+
+	openIdParams := &OpenIdCallbackParams{}
+	if err := requestOpenIdTokens(requestParams, openIdParams); err != nil {
+		return nil, err
+	}
+	if openIdParams.RefreshToken == "" {
+		openIdParams.RefreshToken = refreshToken
+	}
+	if err := ParseOpenIdToken(openIdParams); err != nil {
+		return nil, err
+	}
+	return openIdParams, nil
+}
+
+// requestOpenIdTokens sends a request to the token endpoint of the OpenId provider, and stores the returned id_token
+// and refresh token in the given parameters
+func requestOpenIdTokens(requestParams url.Values, openIdParams *OpenIdCallbackParams) error {
 	openIdMetadata, err := GetOpenIdMetadata()
 	if err != nil {
 		return err
@@ -466,11 +494,6 @@ func RequestOpenIdToken(openIdParams *OpenIdCallbackParams, redirect_uri string)
 		Transport: httpTransport,
 	}
 
-	// Exchange authorization code for a token
-	requestParams := url.Values{}
-	requestParams.Set("code", openIdParams.Code)
-	requestParams.Set("grant_type", "authorization_code")
-	requestParams.Set("redirect_uri", redirect_uri)
 	if len(cfg.ClientSecret) == 0 {
 		requestParams.Set("client_id", cfg.ClientId)
 	}
@@ -503,7 +526,8 @@ func RequestOpenIdToken(openIdParams *OpenIdCallbackParams, redirect_uri string)
 
 	// Parse token response
 	var tokenResponse struct {
-		IdToken string `json:"id_token"`
+		IdToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token"`
 	}
 
 	err = json.Unmarshal(rawTokenResponse, &tokenResponse)
@@ -516,9 +540,91 @@ func RequestOpenIdToken(openIdParams *OpenIdCallbackParams, redirect_uri string)
 	}
 
 	openIdParams.IdToken = tokenResponse.IdToken
+	openIdParams.RefreshToken = tokenResponse.RefreshToken
 	return nil
 }
 
+// ExchangeOpenIdTokenForCluster exchanges the id_token of the user for a token accepted by the
+// given remote cluster, using the OAuth 2.0 Token Exchange grant (RFC 8693).
+func ExchangeOpenIdTokenForCluster(idToken string, cluster config.RemoteCluster) (string, error) {
+	cfg := config.Get().Auth.OpenId
+
+	tokenEndpoint := cluster.Auth.TokenExchangeEndpoint
+	if len(tokenEndpoint) == 0 {
+		openIdMetadata, err := GetOpenIdMetadata()
+		if err != nil {
+			return "", err
+		}
+		tokenEndpoint = openIdMetadata.TokenURL
+	}
+
+	// Create HTTP client
+	httpTransport := &http.Transport{}
+	if cfg.InsecureSkipVerifyTLS {
+		httpTransport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	httpClient := http.Client{
+		Timeout:   time.Second * 10,
+		Transport: httpTransport,
+	}
+
+	requestParams := url.Values{}
+	requestParams.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+	requestParams.Set("subject_token", idToken)
+	requestParams.Set("subject_token_type", "urn:ietf:params:oauth:token-type:id_token")
+	requestParams.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	if len(cluster.Auth.TokenExchangeAudience) != 0 {
+		requestParams.Set("audience", cluster.Auth.TokenExchangeAudience)
+	}
+	if len(cfg.ClientSecret) == 0 {
+		requestParams.Set("client_id", cfg.ClientId)
+	}
+
+	exchangeRequest, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(requestParams.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failure when creating the token exchange request: %w", err)
+	}
+
+	if len(cfg.ClientSecret) > 0 {
+		exchangeRequest.SetBasicAuth(url.QueryEscape(cfg.ClientId), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	exchangeRequest.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	response, err := httpClient.Do(exchangeRequest)
+	if err != nil {
+		return "", fmt.Errorf("failure when requesting token exchange from IdP: %w", err)
+	}
+
+	defer response.Body.Close()
+	rawExchangeResponse, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token exchange response from IdP: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		log.Debugf("OpenId token exchange for cluster [%s] failed with response: %s", cluster.Name, string(rawExchangeResponse))
+		return "", fmt.Errorf("token exchange failed (HTTP response status = %s)", response.Status)
+	}
+
+	var exchangeResponse struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.Unmarshal(rawExchangeResponse, &exchangeResponse)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse token exchange response: %w", err)
+	}
+
+	if len(exchangeResponse.AccessToken) == 0 {
+		return "", errors.New("the IdP did not provide an access_token")
+	}
+
+	return exchangeResponse.AccessToken, nil
+}
+
 func ValidateOpenIdNonceCode(openIdParams *OpenIdCallbackParams) (validationFailure string) {
 	// Parse the received id_token from the IdP and check nonce code
 	idTokenClaims := openIdParams.ParsedIdToken.Claims.(jwt.MapClaims)
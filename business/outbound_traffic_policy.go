@@ -0,0 +1,193 @@
+package business
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	pmod "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultOutboundTrafficWindow = "1d"
+	// Outbound traffic policy of the mesh when its configuration does not set it
+	defaultOutboundTrafficMode = "ALLOW_ANY"
+	blackHoleCluster           = "BlackHoleCluster"
+	// Value of the Istio labels of the telemetry when unknown
+	istioUnknownLabel = "unknown"
+)
+
+// AnalyzeOutboundTrafficPolicy lists the external hosts reached by the workloads of the accessible namespaces over a
+// window, through the PassthroughCluster or blocked by the BlackHoleCluster. Those hosts need a ServiceEntry to be
+// reachable with the REGISTRY_ONLY outbound traffic policy: the ServiceEntries are generated.
+func (in *MeshService) AnalyzeOutboundTrafficPolicy(window string) (*models.OutboundTrafficPolicyAnalysis, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "AnalyzeOutboundTrafficPolicy")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultOutboundTrafficWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 1d", window))
+		return nil, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+
+	analysis := &models.OutboundTrafficPolicyAnalysis{Window: window, Mode: defaultOutboundTrafficMode, ExternalHosts: []models.ExternalHost{}}
+	if mesh, meshErr := in.getRevisionMeshConfig(defaultRevision); meshErr != nil {
+		log.Warningf("Cannot read the outbound traffic policy of the mesh: %v", meshErr)
+	} else if mode, ok := mesh["outboundTrafficPolicy.mode"].(string); ok {
+		analysis.Mode = mode
+	}
+
+	requests, connections, err := in.prom.GetEgressClusterTraffic(window, util.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	hosts := map[string]*models.ExternalHost{}
+	blockedRates := map[string]float64{}
+	for _, traffic := range []struct {
+		vector   pmod.Vector
+		protocol string
+	}{{requests, "HTTP"}, {connections, "TCP"}} {
+		for _, sample := range traffic.vector {
+			namespace := string(sample.Metric["source_workload_namespace"])
+			if !accessible[namespace] {
+				continue
+			}
+			rate := float64(sample.Value)
+			host, port := externalHostPort(string(sample.Metric["destination_service"]), traffic.protocol)
+			if host == "" {
+				analysis.UnidentifiedRate += rate
+				continue
+			}
+			externalHost, ok := hosts[host]
+			if !ok {
+				externalHost = &models.ExternalHost{Host: host, Ports: []models.ExternalHostPort{}, Sources: []models.ExternalHostSource{}}
+				hosts[host] = externalHost
+			}
+			externalHost.Rate += rate
+			if sample.Metric["destination_service_name"] == blackHoleCluster {
+				blockedRates[host] += rate
+			}
+			addExternalHostPort(externalHost, port)
+			addExternalHostSource(externalHost, models.ExternalHostSource{Namespace: namespace, Workload: string(sample.Metric["source_workload"]), Rate: rate})
+		}
+	}
+
+	istioNamespace := config.Get().IstioNamespace
+	serviceEntries := []string{}
+	for _, externalHost := range hosts {
+		externalHost.Blocked = blockedRates[externalHost.Host] >= externalHost.Rate
+		sort.Slice(externalHost.Ports, func(i, j int) bool { return externalHost.Ports[i].Number < externalHost.Ports[j].Number })
+		sort.Slice(externalHost.Sources, func(i, j int) bool {
+			a, b := externalHost.Sources[i], externalHost.Sources[j]
+			return a.Namespace < b.Namespace || (a.Namespace == b.Namespace && a.Workload < b.Workload)
+		})
+		externalHost.ServiceEntryNamespace = externalHost.Sources[0].Namespace
+		for _, source := range externalHost.Sources {
+			if source.Namespace != externalHost.ServiceEntryNamespace {
+				externalHost.ServiceEntryNamespace = istioNamespace
+				break
+			}
+		}
+		analysis.ExternalHosts = append(analysis.ExternalHosts, *externalHost)
+	}
+	sort.Slice(analysis.ExternalHosts, func(i, j int) bool { return analysis.ExternalHosts[i].Host < analysis.ExternalHosts[j].Host })
+	for _, externalHost := range analysis.ExternalHosts {
+		serviceEntries = append(serviceEntries, externalServiceEntry(externalHost, istioNamespace))
+	}
+	analysis.ServiceEntries = strings.Join(serviceEntries, "---\n")
+	return analysis, nil
+}
+
+// externalHostPort returns the host and the port of the destination service of the traffic routed to an egress
+// cluster. The host is empty when the destination is unknown or an IP address. Without a port, the default one of the
+// protocol is assumed: the TCP connections to external hosts are mostly TLS.
+func externalHostPort(destination, protocol string) (string, models.ExternalHostPort) {
+	port := models.ExternalHostPort{Number: 80, Protocol: protocol}
+	if protocol == "TCP" {
+		port = models.ExternalHostPort{Number: 443, Protocol: "TLS"}
+	}
+	host := destination
+	if h, p, err := net.SplitHostPort(destination); err == nil {
+		host = h
+		if number, err := strconv.Atoi(p); err == nil {
+			port.Number = number
+			if protocol == "TCP" && number != 443 {
+				port.Protocol = protocol
+			}
+		}
+	}
+	if host == "" || host == istioUnknownLabel || net.ParseIP(host) != nil {
+		return "", port
+	}
+	return strings.ToLower(host), port
+}
+
+func addExternalHostPort(externalHost *models.ExternalHost, port models.ExternalHostPort) {
+	for _, p := range externalHost.Ports {
+		if p == port {
+			return
+		}
+	}
+	externalHost.Ports = append(externalHost.Ports, port)
+}
+
+func addExternalHostSource(externalHost *models.ExternalHost, source models.ExternalHostSource) {
+	for i, s := range externalHost.Sources {
+		if s.Namespace == source.Namespace && s.Workload == source.Workload {
+			externalHost.Sources[i].Rate += source.Rate
+			return
+		}
+	}
+	externalHost.Sources = append(externalHost.Sources, source)
+}
+
+// externalServiceEntry returns the YAML of the ServiceEntry registering an external host. It is exported to its own
+// namespace only, unless it is generated in the Istio namespace for the whole mesh.
+func externalServiceEntry(externalHost models.ExternalHost, istioNamespace string) string {
+	ports := make([]yaml.MapSlice, 0, len(externalHost.Ports))
+	for _, p := range externalHost.Ports {
+		ports = append(ports, yaml.MapSlice{
+			{Key: "number", Value: p.Number},
+			{Key: "name", Value: fmt.Sprintf("%s-%d", strings.ToLower(p.Protocol), p.Number)},
+			{Key: "protocol", Value: p.Protocol},
+		})
+	}
+	spec := yaml.MapSlice{
+		{Key: "hosts", Value: []string{externalHost.Host}},
+		{Key: "location", Value: "MESH_EXTERNAL"},
+		{Key: "resolution", Value: "DNS"},
+		{Key: "ports", Value: ports},
+	}
+	if externalHost.ServiceEntryNamespace != istioNamespace {
+		spec = append(spec, yaml.MapItem{Key: "exportTo", Value: []string{"."}})
+	}
+	serviceEntry, _ := yaml.Marshal(yaml.MapSlice{
+		{Key: "apiVersion", Value: kubernetes.ApiNetworkingVersion},
+		{Key: "kind", Value: kubernetes.ServiceEntryType},
+		{Key: "metadata", Value: yaml.MapSlice{{Key: "name", Value: externalHost.Host}, {Key: "namespace", Value: externalHost.ServiceEntryNamespace}}},
+		{Key: "spec", Value: spec},
+	})
+	return string(serviceEntry)
+}
@@ -0,0 +1,136 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeEgressSample(namespace, workload, destination, cluster string, value float64) *pmod.Sample {
+	return &pmod.Sample{
+		Metric: pmod.Metric{
+			"source_workload_namespace": pmod.LabelValue(namespace),
+			"source_workload":           pmod.LabelValue(workload),
+			"destination_service":       pmod.LabelValue(destination),
+			"destination_service_name":  pmod.LabelValue(cluster),
+		},
+		Value: pmod.SampleValue(value),
+	}
+}
+
+func TestAnalyzeOutboundTrafficPolicy(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.API.Namespaces.Exclude = []string{}
+	config.Set(conf)
+	kialiCache = nil
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "travels"}},
+	}, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(&core_v1.ConfigMap{Data: map[string]string{
+		"mesh": "outboundTrafficPolicy:\n  mode: REGISTRY_ONLY\n",
+	}}, nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetEgressClusterTraffic", "1d", mock.AnythingOfType("time.Time")).Return(
+		pmod.Vector{
+			fakeEgressSample("bookinfo", "reviews-v1", "httpbin.org", "PassthroughCluster", 2),
+			fakeEgressSample("bookinfo", "reviews-v2", "httpbin.org:8000", "PassthroughCluster", 1),
+			fakeEgressSample("travels", "cars-v1", "HTTPBIN.org", "BlackHoleCluster", 0.5),
+			// Not accessible
+			fakeEgressSample("secret", "app-v1", "internal.example.com", "PassthroughCluster", 1),
+		},
+		pmod.Vector{
+			fakeEgressSample("bookinfo", "ratings-v1", "api.github.com", "BlackHoleCluster", 0.25),
+			fakeEgressSample("bookinfo", "ratings-v1", "10.0.0.12:5432", "PassthroughCluster", 0.1),
+			fakeEgressSample("bookinfo", "ratings-v1", "unknown", "PassthroughCluster", 0.2),
+		},
+		nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	analysis, err := layer.Mesh.AnalyzeOutboundTrafficPolicy("")
+	assert.NoError(err)
+	assert.Equal("1d", analysis.Window)
+	assert.Equal("REGISTRY_ONLY", analysis.Mode)
+	assert.InDelta(0.3, analysis.UnidentifiedRate, 0.0001)
+
+	assert.Len(analysis.ExternalHosts, 2)
+	github := analysis.ExternalHosts[0]
+	assert.Equal("api.github.com", github.Host)
+	assert.True(github.Blocked)
+	assert.Equal([]models.ExternalHostPort{{Number: 443, Protocol: "TLS"}}, github.Ports)
+	assert.Equal("bookinfo", github.ServiceEntryNamespace)
+
+	httpbin := analysis.ExternalHosts[1]
+	assert.Equal("httpbin.org", httpbin.Host)
+	assert.False(httpbin.Blocked)
+	assert.InDelta(3.5, httpbin.Rate, 0.0001)
+	assert.Equal([]models.ExternalHostPort{{Number: 80, Protocol: "HTTP"}, {Number: 8000, Protocol: "HTTP"}}, httpbin.Ports)
+	assert.Len(httpbin.Sources, 3)
+	assert.Equal("travels", httpbin.Sources[2].Namespace)
+	// Reached from several namespaces: registered for the whole mesh
+	assert.Equal("istio-system", httpbin.ServiceEntryNamespace)
+
+	assert.Equal(`apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: api.github.com
+  namespace: bookinfo
+spec:
+  hosts:
+  - api.github.com
+  location: MESH_EXTERNAL
+  resolution: DNS
+  ports:
+  - number: 443
+    name: tls-443
+    protocol: TLS
+  exportTo:
+  - .
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: httpbin.org
+  namespace: istio-system
+spec:
+  hosts:
+  - httpbin.org
+  location: MESH_EXTERNAL
+  resolution: DNS
+  ports:
+  - number: 80
+    name: http-80
+    protocol: HTTP
+  - number: 8000
+    name: http-8000
+    protocol: HTTP
+`, analysis.ServiceEntries)
+}
+
+func TestAnalyzeOutboundTrafficPolicyBadWindow(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	mesh := MeshService{k8s: new(kubetest.K8SClientMock), prom: new(prometheustest.PromClientMock)}
+	_, err := mesh.AnalyzeOutboundTrafficPolicy("yesterday")
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid window [yesterday]")
+}
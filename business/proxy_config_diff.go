@@ -0,0 +1,113 @@
+package business
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Replaces the IP of a pod in the listeners of its Envoy, so that the replicas of a workload can be compared
+const podIPPlaceholder = "<pod ip>"
+
+// envoySummary holds the clusters, listeners and routes of an Envoy, by key
+type envoySummary struct {
+	clusters  map[string]interface{}
+	listeners map[string]interface{}
+	routes    map[string]interface{}
+}
+
+// DiffConfigDumps compares the clusters, listeners and routes of the Envoys of two pods, typically two replicas of a
+// workload or the old and new pods of a canary, ignoring the IPs of the pods
+func (in *ProxyStatus) DiffConfigDumps(fromNamespace, fromPod, toNamespace, toPod string) (*models.EnvoyConfigDiff, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyStatus", "DiffConfigDumps")
+	defer promtimer.ObserveNow(&err)
+
+	// The config dumps are fetched one after the other: the port-forwards to the Envoy admin listen on the same port
+	from, err := in.getEnvoySummary(fromNamespace, fromPod)
+	if err != nil {
+		return nil, err
+	}
+	to, err := in.getEnvoySummary(toNamespace, toPod)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.EnvoyConfigDiff{
+		From:      fromNamespace + "/" + fromPod,
+		To:        toNamespace + "/" + toPod,
+		Clusters:  diffEnvoyEntries(from.clusters, to.clusters),
+		Listeners: diffEnvoyEntries(from.listeners, to.listeners),
+		Routes:    diffEnvoyEntries(from.routes, to.routes),
+	}
+	diff.Identical = len(diff.Clusters) == 0 && len(diff.Listeners) == 0 && len(diff.Routes) == 0
+	return diff, nil
+}
+
+func (in *ProxyStatus) getEnvoySummary(namespace, pod string) (*envoySummary, error) {
+	p, err := in.k8s.GetPod(namespace, pod)
+	if err != nil {
+		return nil, err
+	}
+	dump, err := in.k8s.GetConfigDump(namespace, pod)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeConfigDump(dump, p.Status.PodIP)
+}
+
+func summarizeConfigDump(dump *kubernetes.ConfigDump, podIP string) (*envoySummary, error) {
+	summary := &envoySummary{clusters: map[string]interface{}{}, listeners: map[string]interface{}{}, routes: map[string]interface{}{}}
+
+	clusters := models.Clusters{}
+	if err := clusters.Parse(dump); err != nil {
+		return nil, err
+	}
+	for _, c := range clusters {
+		key := fmt.Sprintf("%s|%d|%s|%s", c.Direction, c.Port, c.Subset, c.ServiceFQDN)
+		summary.clusters[key] = *c
+	}
+
+	listeners := models.Listeners{}
+	if err := listeners.Parse(dump); err != nil {
+		return nil, err
+	}
+	for _, l := range listeners {
+		listener := *l
+		if podIP != "" && listener.Address == podIP {
+			listener.Address = podIPPlaceholder
+		}
+		key := fmt.Sprintf("%s:%v %s", listener.Address, listener.Port, listener.Match)
+		summary.listeners[key] = listener
+	}
+
+	routes := models.Routes{}
+	if err := routes.Parse(dump); err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		key := fmt.Sprintf("%s %s %s", r.Name, r.Domains, r.Match)
+		summary.routes[key] = *r
+	}
+	return summary, nil
+}
+
+func diffEnvoyEntries(from, to map[string]interface{}) []models.EnvoyConfigDelta {
+	deltas := []models.EnvoyConfigDelta{}
+	for key, fromEntry := range from {
+		if toEntry, ok := to[key]; !ok || !reflect.DeepEqual(fromEntry, toEntry) {
+			deltas = append(deltas, models.EnvoyConfigDelta{Key: key, From: fromEntry, To: toEntry})
+		}
+	}
+	for key, toEntry := range to {
+		if _, ok := from[key]; !ok {
+			deltas = append(deltas, models.EnvoyConfigDelta{Key: key, To: toEntry})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Key < deltas[j].Key })
+	return deltas
+}
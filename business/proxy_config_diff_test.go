@@ -0,0 +1,87 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeEnvoyDump(podIP string, clusters []string, reviewsCluster string) *kubernetes.ConfigDump {
+	dynamicClusters := []interface{}{}
+	for _, c := range clusters {
+		dynamicClusters = append(dynamicClusters, map[string]interface{}{"cluster": map[string]interface{}{"name": c, "type": "EDS"}})
+	}
+	return &kubernetes.ConfigDump{Configs: []interface{}{
+		map[string]interface{}{
+			"@type":                   "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+			"dynamic_active_clusters": dynamicClusters,
+		},
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+			"dynamic_listeners": []interface{}{map[string]interface{}{
+				"name": podIP + "_9080",
+				"active_state": map[string]interface{}{"listener": map[string]interface{}{
+					"address": map[string]interface{}{"socket_address": map[string]interface{}{"address": podIP, "port_value": 9080}},
+					"filter_chains": []interface{}{map[string]interface{}{"filters": []interface{}{map[string]interface{}{
+						"name":         "envoy.filters.network.tcp_proxy",
+						"typed_config": map[string]interface{}{"cluster": "inbound|9080||"},
+					}}}},
+				}},
+			}},
+		},
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+			"dynamic_route_configs": []interface{}{map[string]interface{}{"route_config": map[string]interface{}{
+				"name": "9080",
+				"virtual_hosts": []interface{}{map[string]interface{}{
+					"domains": []interface{}{"reviews.bookinfo.svc.cluster.local"},
+					"routes": []interface{}{map[string]interface{}{
+						"match": map[string]interface{}{"prefix": "/"},
+						"route": map[string]interface{}{"cluster": reviewsCluster},
+					}},
+				}},
+			}}},
+		},
+	}}
+}
+
+func TestDiffConfigDumps(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPod", "bookinfo", "productpage-a").Return(&core_v1.Pod{Status: core_v1.PodStatus{PodIP: "10.0.0.1"}}, nil)
+	k8s.On("GetPod", "bookinfo", "productpage-b").Return(&core_v1.Pod{Status: core_v1.PodStatus{PodIP: "10.0.0.2"}}, nil)
+	k8s.On("GetPod", "bookinfo", "productpage-c").Return(&core_v1.Pod{Status: core_v1.PodStatus{PodIP: "10.0.0.3"}}, nil)
+	clusters := []string{"outbound|9080||reviews.bookinfo.svc.cluster.local", "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"}
+	k8s.On("GetConfigDump", "bookinfo", "productpage-a").Return(fakeEnvoyDump("10.0.0.1", clusters, "outbound|9080||reviews.bookinfo.svc.cluster.local"), nil)
+	k8s.On("GetConfigDump", "bookinfo", "productpage-b").Return(fakeEnvoyDump("10.0.0.2", clusters, "outbound|9080||reviews.bookinfo.svc.cluster.local"), nil)
+	k8s.On("GetConfigDump", "bookinfo", "productpage-c").Return(fakeEnvoyDump("10.0.0.3", clusters[:1], "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"), nil)
+	service := ProxyStatus{k8s: k8s}
+
+	// Replicas differing by their IPs only
+	diff, err := service.DiffConfigDumps("bookinfo", "productpage-a", "bookinfo", "productpage-b")
+	assert.NoError(err)
+	assert.True(diff.Identical)
+	assert.Equal("bookinfo/productpage-a", diff.From)
+	assert.Empty(diff.Listeners)
+	summary, err := summarizeConfigDump(fakeEnvoyDump("10.0.0.1", clusters, ""), "10.0.0.1")
+	assert.NoError(err)
+	assert.Contains(summary.listeners, "<pod ip>:9080 ALL")
+
+	diff, err = service.DiffConfigDumps("bookinfo", "productpage-a", "bookinfo", "productpage-c")
+	assert.NoError(err)
+	assert.False(diff.Identical)
+	assert.Empty(diff.Listeners)
+	assert.Len(diff.Clusters, 1)
+	assert.Equal("outbound|9080|v1|reviews.bookinfo.svc.cluster.local", diff.Clusters[0].Key)
+	assert.Nil(diff.Clusters[0].To)
+	assert.Len(diff.Routes, 1)
+	assert.Equal("9080 reviews.bookinfo.svc.cluster.local /*", diff.Routes[0].Key)
+	assert.Equal("outbound|9080||reviews.bookinfo.svc.cluster.local", diff.Routes[0].From.(models.Route).Cluster)
+	assert.Equal("outbound|9080|v1|reviews.bookinfo.svc.cluster.local", diff.Routes[0].To.(models.Route).Cluster)
+}
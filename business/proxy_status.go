@@ -1,6 +1,15 @@
 package business
 
 import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
@@ -12,20 +21,8 @@ type ProxyStatus struct {
 
 func (in *ProxyStatus) GetPodProxyStatus(ns, pod string) (*kubernetes.ProxyStatus, error) {
 	if kialiCache != nil {
-		if !kialiCache.CheckProxyStatus() {
-			var proxyStatus []*kubernetes.ProxyStatus
-			var err error
-			proxyStatus, err = in.k8s.GetProxyStatus()
-			if err != nil {
-				if proxyStatus, err = in.getProxyStatusUsingKialiSA(); err != nil {
-					return &kubernetes.ProxyStatus{}, err
-				}
-			}
-			if err == nil {
-				kialiCache.SetProxyStatus(proxyStatus)
-			} else {
-				return &kubernetes.ProxyStatus{}, err
-			}
+		if err := in.loadProxyStatus(); err != nil {
+			return &kubernetes.ProxyStatus{}, err
 		}
 		return kialiCache.GetPodProxyStatus(ns, pod), nil
 	}
@@ -33,6 +30,27 @@ func (in *ProxyStatus) GetPodProxyStatus(ns, pod string) (*kubernetes.ProxyStatu
 	return &kubernetes.ProxyStatus{}, nil
 }
 
+// loadProxyStatus fills the cache with the status of the proxies, fetched from istiod, unless recently done
+func (in *ProxyStatus) loadProxyStatus() error {
+	if kialiCache.CheckProxyStatus() {
+		return nil
+	}
+	proxyStatus, err := in.getProxyStatus()
+	if err != nil {
+		return err
+	}
+	kialiCache.SetProxyStatus(proxyStatus)
+	return nil
+}
+
+func (in *ProxyStatus) getProxyStatus() ([]*kubernetes.ProxyStatus, error) {
+	proxyStatus, err := in.k8s.GetProxyStatus()
+	if err != nil {
+		return in.getProxyStatusUsingKialiSA()
+	}
+	return proxyStatus, nil
+}
+
 func (in *ProxyStatus) getProxyStatusUsingKialiSA() ([]*kubernetes.ProxyStatus, error) {
 	clientFactory, err := kubernetes.GetClientFactory()
 	if err != nil {
@@ -122,3 +140,134 @@ func buildDump(dump *kubernetes.ConfigDump, resource string) (*models.EnvoyProxy
 
 	return response, err
 }
+
+// GetNamespaceProxiesStatus returns the xDS distribution status of the proxies of the pods of a namespace, like
+// istioctl proxy-status. The proxies not connected to any istiod are reported too.
+func (in *ProxyStatus) GetNamespaceProxiesStatus(namespace string) ([]models.ProxySyncStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyStatus", "GetNamespaceProxiesStatus")
+	defer promtimer.ObserveNow(&err)
+
+	var pods []core_v1.Pod
+	if IsNamespaceCached(namespace) {
+		pods, err = kialiCache.GetPods(namespace, "")
+	} else {
+		pods, err = in.k8s.GetPods(namespace, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The cache indexes the status by pod, without cache the status is fetched from istiod for this request only
+	var podStatus func(pod string) *kubernetes.ProxyStatus
+	if kialiCache != nil {
+		if err = in.loadProxyStatus(); err != nil {
+			return nil, err
+		}
+		podStatus = func(pod string) *kubernetes.ProxyStatus { return kialiCache.GetPodProxyStatus(namespace, pod) }
+	} else {
+		var proxyStatus []*kubernetes.ProxyStatus
+		if proxyStatus, err = in.getProxyStatus(); err != nil {
+			return nil, err
+		}
+		byProxy := make(map[string]*kubernetes.ProxyStatus, len(proxyStatus))
+		for _, ps := range proxyStatus {
+			byProxy[ps.ProxyID] = ps
+		}
+		podStatus = func(pod string) *kubernetes.ProxyStatus { return byProxy[pod+"."+namespace] }
+	}
+
+	return proxiesSyncStatus(namespace, pods, podStatus), nil
+}
+
+func proxiesSyncStatus(namespace string, pods []core_v1.Pod, podStatus func(pod string) *kubernetes.ProxyStatus) []models.ProxySyncStatus {
+	statuses := []models.ProxySyncStatus{}
+	for _, pod := range pods {
+		if _, _, ok := podProxy(pod); !ok || pod.Status.Phase != core_v1.PodRunning {
+			continue
+		}
+		status := models.ProxySyncStatus{Namespace: namespace, Pod: pod.Name}
+		if ps := podStatus(pod.Name); ps != nil {
+			status.Istiod = ps.Pilot()
+			status.ProxyVersion = ps.ProxyVersion
+			status.IstioVersion = ps.IstioVersion
+			status.ProxyStatus = *castProxyStatus(*ps)
+			status.Connected = true
+			status.Synced = true
+			for _, xds := range []string{status.CDS, status.EDS, status.LDS, status.RDS} {
+				// NOT_SENT is expected, i.e. for a gateway without route
+				if strings.HasPrefix(xds, "Stale") {
+					status.Synced = false
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Pod < statuses[j].Pod })
+	return statuses
+}
+
+// GetEnvoyStats returns the counters and gauges of the Envoy of a pod allowed by the configuration and, when given,
+// matching the filter, a regular expression
+func (in *ProxyStatus) GetEnvoyStats(namespace, pod string, filter *regexp.Regexp) ([]models.EnvoyStat, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyStatus", "GetEnvoyStats")
+	defer promtimer.ObserveNow(&err)
+
+	allowlist := getEnvoyStatsAllowlist()
+	if len(allowlist) == 0 {
+		return []models.EnvoyStat{}, nil
+	}
+
+	// Envoy does not run the regular expressions of Go: it is only given the literal prefix of the matches of the
+	// filter, escaped, so that it returns fewer stats. The filter itself is applied to the stats returned.
+	envoyFilter := ""
+	if filter != nil {
+		prefix, _ := filter.LiteralPrefix()
+		envoyFilter = regexp.QuoteMeta(prefix)
+	}
+	stats, err := in.k8s.GetEnvoyStats(namespace, pod, envoyFilter)
+	if err != nil {
+		return nil, err
+	}
+	return allowedEnvoyStats(stats, allowlist, filter), nil
+}
+
+// The allowlist of the Envoy stats, compiled once for the patterns of the configuration
+var envoyStatsAllowlist struct {
+	sync.Mutex
+	patterns []string
+	regexps  []*regexp.Regexp
+}
+
+func getEnvoyStatsAllowlist() []*regexp.Regexp {
+	patterns := config.Get().ExternalServices.Istio.EnvoyStatsAllowlist
+	envoyStatsAllowlist.Lock()
+	defer envoyStatsAllowlist.Unlock()
+	if envoyStatsAllowlist.regexps == nil || !reflect.DeepEqual(patterns, envoyStatsAllowlist.patterns) {
+		regexps := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pattern := range patterns {
+			// The patterns are validated when Kiali starts
+			regexps = append(regexps, regexp.MustCompile(pattern))
+		}
+		envoyStatsAllowlist.patterns, envoyStatsAllowlist.regexps = patterns, regexps
+	}
+	return envoyStatsAllowlist.regexps
+}
+
+func allowedEnvoyStats(stats *kubernetes.EnvoyStats, allowlist []*regexp.Regexp, filter *regexp.Regexp) []models.EnvoyStat {
+	allowed := []models.EnvoyStat{}
+	for _, stat := range stats.Stats {
+		if stat.Name == "" || (filter != nil && !filter.MatchString(stat.Name)) {
+			continue
+		}
+		for _, re := range allowlist {
+			if re.MatchString(stat.Name) {
+				allowed = append(allowed, models.EnvoyStat{Name: stat.Name, Value: stat.Value})
+				break
+			}
+		}
+	}
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i].Name < allowed[j].Name })
+	return allowed
+}
@@ -0,0 +1,100 @@
+package business
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestProxiesSyncStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	running := func(pod core_v1.Pod) core_v1.Pod {
+		pod.Status.Phase = core_v1.PodRunning
+		return pod
+	}
+	pods := []core_v1.Pod{
+		running(fakeProxyPod("reviews", "", "")),
+		running(fakeProxyPod("ratings", "", "")),
+		running(fakeProxyPod("details", "", "")),
+		fakeProxyPod("pending", "", ""),
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "legacy"}, Status: core_v1.PodStatus{Phase: core_v1.PodRunning}},
+	}
+	byPod := map[string]*kubernetes.ProxyStatus{
+		"reviews": {SyncStatus: kubernetes.SyncStatus{ProxyID: "reviews.bookinfo", ProxyVersion: "1.8.1", IstioVersion: "1.8.1",
+			ClusterSent: "a", ClusterAcked: "a", ListenerSent: "b", ListenerAcked: "b", EndpointSent: "c", EndpointAcked: "c"}},
+		"ratings": {SyncStatus: kubernetes.SyncStatus{ProxyID: "ratings.bookinfo", ProxyVersion: "1.8.1", IstioVersion: "1.8.1",
+			ClusterSent: "a", ClusterAcked: "a", ListenerSent: "b", ListenerAcked: "b", RouteSent: "c", RouteAcked: "d", EndpointSent: "e"}},
+	}
+
+	statuses := proxiesSyncStatus("bookinfo", pods, func(pod string) *kubernetes.ProxyStatus { return byPod[pod] })
+	assert.Len(statuses, 3)
+	assert.Equal(models.ProxySyncStatus{Namespace: "bookinfo", Pod: "details"}, statuses[0])
+	assert.Equal("ratings", statuses[1].Pod)
+	assert.True(statuses[1].Connected)
+	assert.False(statuses[1].Synced)
+	assert.Equal(models.ProxyStatus{CDS: "Synced", EDS: "Stale (Never Acknowledged)", LDS: "Synced", RDS: "Stale"}, statuses[1].ProxyStatus)
+	assert.Equal("reviews", statuses[2].Pod)
+	assert.Equal("1.8.1", statuses[2].ProxyVersion)
+	assert.True(statuses[2].Connected)
+	assert.True(statuses[2].Synced)
+	assert.Equal("NOT_SENT", statuses[2].RDS)
+}
+
+func TestGetEnvoyStats(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	stats := &kubernetes.EnvoyStats{Stats: []kubernetes.EnvoyStat{
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_retry", Value: 3},
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_pending_overflow", Value: 1},
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.circuit_breakers.default.rq_open", Value: 0},
+		{Name: "cluster.outbound|9080||ratings.bookinfo.svc.cluster.local.upstream_rq_retry", Value: 2},
+		{Name: "server.memory_allocated", Value: 1024},
+		{},
+	}}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetEnvoyStats", "bookinfo", "productpage", "").Return(stats, nil)
+	k8s.On("GetEnvoyStats", "bookinfo", "productpage", "reviews").Return(stats, nil)
+	k8s.On("GetEnvoyStats", "bookinfo", "productpage", `outbound\|9080\|\|r`).Return(stats, nil)
+	service := ProxyStatus{k8s: k8s}
+
+	allowed, err := service.GetEnvoyStats("bookinfo", "productpage", nil)
+	assert.NoError(err)
+	assert.Equal([]models.EnvoyStat{
+		{Name: "cluster.outbound|9080||ratings.bookinfo.svc.cluster.local.upstream_rq_retry", Value: 2},
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.circuit_breakers.default.rq_open", Value: 0},
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_pending_overflow", Value: 1},
+		{Name: "cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_retry", Value: 3},
+	}, allowed)
+
+	// The filter is applied again, whatever Envoy supports
+	allowed, err = service.GetEnvoyStats("bookinfo", "productpage", regexp.MustCompile("reviews"))
+	assert.NoError(err)
+	assert.Len(allowed, 3)
+
+	// Envoy is only given the literal prefix of the matches of the filter
+	allowed, err = service.GetEnvoyStats("bookinfo", "productpage", regexp.MustCompile(`outbound\|9080\|\|(ratings|reviews)\.`))
+	assert.NoError(err)
+	assert.Len(allowed, 4)
+	allowed, err = service.GetEnvoyStats("bookinfo", "productpage", regexp.MustCompile(`(?i)RATINGS`))
+	assert.NoError(err)
+	assert.Len(allowed, 1)
+
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.EnvoyStatsAllowlist = nil
+	config.Set(conf)
+	allowed, err = service.GetEnvoyStats("bookinfo", "productpage", nil)
+	assert.NoError(err)
+	assert.Empty(allowed)
+	k8s.AssertNumberOfCalls(t, "GetEnvoyStats", 4)
+}
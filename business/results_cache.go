@@ -0,0 +1,112 @@
+package business
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// resultsCache stores computed validations and health, optionally shared with other replicas of Kiali.
+// It is nil when the results cache is disabled.
+var resultsCache cache.ResultsCache
+var resultsCacheOnce sync.Once
+
+// initResultsCache creates the results cache. It is initialized with the Kiali Cache, or before when the sessions
+// need it.
+func initResultsCache() {
+	if cache, err := cache.NewResultsCache(config.Get().KubernetesConfig.ResultsCache); err != nil {
+		log.Errorf("Error initializing Kiali results cache. Details: %s", err)
+	} else {
+		resultsCache = cache
+	}
+}
+
+// resultsKey builds the keys of the results cache from the resourceVersions of the objects used to compute a
+// result, so a cached result is not read anymore as soon as any of these objects changes.
+type resultsKey struct {
+	versions []string
+}
+
+func (k *resultsKey) addIstioObjects(lists ...[]kubernetes.IstioObject) {
+	for _, objects := range lists {
+		for _, o := range objects {
+			k.addObjectMeta(o.GetTypeMeta().Kind, o.GetObjectMeta())
+		}
+	}
+}
+
+func (k *resultsKey) addObjectMeta(kind string, meta meta_v1.ObjectMeta) {
+	k.versions = append(k.versions, kind+"/"+meta.Namespace+"/"+meta.Name+"@"+meta.ResourceVersion)
+}
+
+func (k *resultsKey) addWorkload(namespace string, workload models.WorkloadListItem) {
+	k.versions = append(k.versions, "Workload/"+namespace+"/"+workload.Name+"@"+workload.ResourceVersion)
+}
+
+func (k *resultsKey) add(values ...string) {
+	k.versions = append(k.versions, values...)
+}
+
+// build returns the key of the result: the versions are hashed, as there can be many of them
+func (k *resultsKey) build(parts ...string) string {
+	sort.Strings(k.versions)
+	hash := sha256.Sum256([]byte(strings.Join(k.versions, ",")))
+	return strings.Join(append(parts, hex.EncodeToString(hash[:])), ":")
+}
+
+// cachedValidation is an entry of IstioValidations, as its keys can't be serialized
+type cachedValidation struct {
+	Key        models.IstioValidationKey `json:"key"`
+	Validation *models.IstioValidation   `json:"validation"`
+}
+
+func getCachedValidations(key string) (models.IstioValidations, bool) {
+	if resultsCache == nil {
+		return nil, false
+	}
+	var cached []cachedValidation
+	if !resultsCache.Get(key, &cached) {
+		return nil, false
+	}
+	validations := make(models.IstioValidations, len(cached))
+	for _, v := range cached {
+		validations[v.Key] = v.Validation
+	}
+	return validations, true
+}
+
+func setCachedValidations(key string, validations models.IstioValidations, ttl time.Duration) {
+	if resultsCache == nil {
+		return
+	}
+	cached := make([]cachedValidation, 0, len(validations))
+	for k, v := range validations {
+		cached = append(cached, cachedValidation{Key: k, Validation: v})
+	}
+	resultsCache.Set(key, cached, ttl)
+}
+
+// healthResultsKey returns the key of the health of a namespace in the results cache. The health includes request
+// rates, so the query time is truncated to the TTL of the health entries to let close requests share the result.
+func healthResultsKey(kind, namespace, rateInterval string, queryTime time.Time, key resultsKey) string {
+	if ttl := healthResultsTTL(); ttl > 0 {
+		queryTime = queryTime.Truncate(ttl)
+	}
+	return key.build("health", kind, namespace, rateInterval, strconv.FormatInt(queryTime.Unix(), 10))
+}
+
+func healthResultsTTL() time.Duration {
+	return time.Duration(config.Get().KubernetesConfig.ResultsCache.HealthTTL) * time.Second
+}
@@ -0,0 +1,153 @@
+package business
+
+import (
+	"testing"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeRollout() kubernetes.Rollout {
+	replicas := int32(3)
+	step := int32(1)
+	r := kubernetes.Rollout{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+	r.Spec.Replicas = &replicas
+	r.Spec.Selector = &meta_v1.LabelSelector{MatchLabels: map[string]string{"app": "reviews"}}
+	r.Spec.Template.Labels = map[string]string{"app": "reviews"}
+	r.Spec.Strategy.Canary = &kubernetes.RolloutCanaryStrategy{Steps: []map[string]interface{}{
+		{"setWeight": 20}, {"pause": map[string]interface{}{}}, {"setWeight": 60},
+	}}
+	r.Status.Replicas = 3
+	r.Status.AvailableReplicas = 3
+	r.Status.StableRS = "7b8d9c"
+	r.Status.CurrentPodHash = "5f6a7e"
+	r.Status.CurrentStepIndex = &step
+	r.Status.Phase = "Paused"
+	r.Status.PauseConditions = []kubernetes.RolloutPauseCondition{{Reason: "CanaryPauseStep"}}
+	r.Status.Canary.Weights = &kubernetes.RolloutTrafficWeights{}
+	r.Status.Canary.Weights.Canary.Weight = 20
+	r.Status.Canary.Weights.Stable.Weight = 80
+	return r
+}
+
+func fakeRolloutReplicaSet(hash string) apps_v1.ReplicaSet {
+	t := true
+	return apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "reviews-" + hash,
+			Namespace:       "bookinfo",
+			OwnerReferences: []meta_v1.OwnerReference{{Kind: kubernetes.RolloutType, Name: "reviews", Controller: &t}},
+		},
+		Spec: apps_v1.ReplicaSetSpec{
+			Template: core_v1.PodTemplateSpec{ObjectMeta: meta_v1.ObjectMeta{
+				Labels: map[string]string{"app": "reviews", kubernetes.RolloutPodTemplateHashLabel: hash},
+			}},
+		},
+	}
+}
+
+func fakeRolloutPod(name, hash string) core_v1.Pod {
+	t := true
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            name,
+			Namespace:       "bookinfo",
+			Labels:          map[string]string{"app": "reviews", kubernetes.RolloutPodTemplateHashLabel: hash},
+			OwnerReferences: []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: "reviews-" + hash, Controller: &t}},
+		},
+	}
+}
+
+func setupRolloutMocks() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.Deployment{}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{
+		fakeRolloutReplicaSet("7b8d9c"), fakeRolloutReplicaSet("5f6a7e"),
+	}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetRollouts", mock.AnythingOfType("string")).Return([]kubernetes.Rollout{fakeRollout()}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{
+		fakeRolloutPod("reviews-7b8d9c-a1", "7b8d9c"),
+		fakeRolloutPod("reviews-7b8d9c-b2", "7b8d9c"),
+		fakeRolloutPod("reviews-5f6a7e-c3", "5f6a7e"),
+	}, nil)
+	return k8s
+}
+
+func TestGetWorkloadListFromRollouts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(setupRolloutMocks())
+	workloadList, err := svc.GetWorkloadList("bookinfo")
+	assert.NoError(err)
+
+	workloads := workloadList.Workloads
+	assert.Len(workloads, 1)
+	assert.Equal("reviews", workloads[0].Name)
+	assert.Equal(kubernetes.RolloutType, workloads[0].Type)
+	assert.True(workloads[0].AppLabel)
+}
+
+func TestGetWorkloadFromRollout(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(setupRolloutMocks())
+	workload, err := svc.GetWorkload("bookinfo", "reviews", kubernetes.RolloutType, false)
+	assert.NoError(err)
+
+	assert.Equal(kubernetes.RolloutType, workload.Type)
+	assert.Equal(int32(3), workload.DesiredReplicas)
+	assert.Len(workload.Pods, 3)
+	revisions := map[string]string{}
+	for _, pod := range workload.Pods {
+		revisions[pod.Name] = pod.RolloutRevision
+	}
+	assert.Equal(map[string]string{
+		"reviews-7b8d9c-a1": models.RolloutRevisionStable,
+		"reviews-7b8d9c-b2": models.RolloutRevisionStable,
+		"reviews-5f6a7e-c3": models.RolloutRevisionCanary,
+	}, revisions)
+
+	rollout := workload.Rollout
+	assert.NotNil(rollout)
+	assert.Equal("canary", rollout.Strategy)
+	assert.Equal("Paused", rollout.Phase)
+	assert.Equal("5f6a7e", rollout.CanaryRevision)
+	assert.Equal(3, rollout.Steps)
+	assert.Equal(int32(1), *rollout.CurrentStep)
+	assert.Equal(int32(20), *rollout.CanaryWeight)
+	assert.True(rollout.Paused)
+
+	// Without the type, the pods are resolved to the Rollout through their ReplicaSets
+	k8s := setupRolloutMocks()
+	k8s.On("GetDeployment", "bookinfo", "reviews").Return(&apps_v1.Deployment{}, errors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "reviews"))
+	k8s.On("GetDeploymentConfig", "bookinfo", "reviews").Return(&osapps_v1.DeploymentConfig{}, errors.NewNotFound(schema.GroupResource{Resource: "deploymentconfigs"}, "reviews"))
+	k8s.On("GetStatefulSet", "bookinfo", "reviews").Return(&apps_v1.StatefulSet{}, errors.NewNotFound(schema.GroupResource{Resource: "statefulsets"}, "reviews"))
+	svc = setupWorkloadService(k8s)
+	workload, err = svc.GetWorkload("bookinfo", "reviews", "", false)
+	assert.NoError(err)
+	assert.Equal(kubernetes.RolloutType, workload.Type)
+	assert.Len(workload.Pods, 3)
+}
@@ -0,0 +1,221 @@
+package business
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	savedViewsConfigMapName = "kiali-saved-views"
+	savedViewsConfigMapKey  = "views"
+	// The views are stored in a ConfigMap, limited to 1MiB
+	maxSavedViewsPerUser = 50
+)
+
+var savedViewsResource = schema.GroupResource{Group: "kiali.io", Resource: "savedviews"}
+
+// SavedViewService deals with the views of the console saved by the users. A view is visible to its owner, and to
+// all the users when it is shared, but only its owner can change it.
+type SavedViewService struct{}
+
+// ListSavedViews returns the views of the given user and the views shared by the other users, sorted by name
+func (in *SavedViewService) ListSavedViews(user string) ([]models.SavedView, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SavedViewService", "ListSavedViews")
+	defer promtimer.ObserveNow(&err)
+
+	var views []models.SavedView
+	if views, err = getSavedViews(); err != nil {
+		return nil, err
+	}
+	visible := []models.SavedView{}
+	for _, view := range views {
+		if view.Owner == user || view.Shared {
+			visible = append(visible, view)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].Name < visible[j].Name
+	})
+	return visible, nil
+}
+
+// GetSavedView returns a view of the given user, or a view shared by another user
+func (in *SavedViewService) GetSavedView(user, id string) (*models.SavedView, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SavedViewService", "GetSavedView")
+	defer promtimer.ObserveNow(&err)
+
+	var views []models.SavedView
+	if views, err = getSavedViews(); err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		if view.ID == id && (view.Owner == user || view.Shared) {
+			return &view, nil
+		}
+	}
+	err = kubernetes.NewNotFound(id, "kiali.io", "savedviews")
+	return nil, err
+}
+
+// CreateSavedView saves a view owned by the given user
+func (in *SavedViewService) CreateSavedView(user string, request models.SavedViewRequest) (*models.SavedView, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SavedViewService", "CreateSavedView")
+	defer promtimer.ObserveNow(&err)
+
+	if err = validateSavedView(request); err != nil {
+		return nil, err
+	}
+	var idBytes []byte
+	if idBytes, err = util.CryptoRandomBytes(8); err != nil {
+		return nil, err
+	}
+	now := util.Clock.Now()
+	created := models.SavedView{
+		ID:               hex.EncodeToString(idBytes),
+		Owner:            user,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		SavedViewRequest: request,
+	}
+
+	err = updateSavedViews(func(views []models.SavedView) ([]models.SavedView, error) {
+		owned := 0
+		for _, view := range views {
+			if view.Owner == user {
+				owned++
+			}
+		}
+		if owned >= maxSavedViewsPerUser {
+			return nil, k8s_errors.NewBadRequest(fmt.Sprintf("a user cannot save more than %d views", maxSavedViewsPerUser))
+		}
+		return append(views, created), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateSavedView replaces a view owned by the given user
+func (in *SavedViewService) UpdateSavedView(user, id string, request models.SavedViewRequest) (*models.SavedView, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SavedViewService", "UpdateSavedView")
+	defer promtimer.ObserveNow(&err)
+
+	if err = validateSavedView(request); err != nil {
+		return nil, err
+	}
+	var updated models.SavedView
+	err = updateSavedViews(func(views []models.SavedView) ([]models.SavedView, error) {
+		i, err := findOwnedSavedView(views, user, id)
+		if err != nil {
+			return nil, err
+		}
+		views[i].SavedViewRequest = request
+		views[i].UpdatedAt = util.Clock.Now()
+		updated = views[i]
+		return views, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteSavedView deletes a view owned by the given user
+func (in *SavedViewService) DeleteSavedView(user, id string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SavedViewService", "DeleteSavedView")
+	defer promtimer.ObserveNow(&err)
+
+	err = updateSavedViews(func(views []models.SavedView) ([]models.SavedView, error) {
+		i, err := findOwnedSavedView(views, user, id)
+		if err != nil {
+			return nil, err
+		}
+		return append(views[:i], views[i+1:]...), nil
+	})
+	return err
+}
+
+func validateSavedView(request models.SavedViewRequest) error {
+	switch {
+	case request.Name == "":
+		return k8s_errors.NewBadRequest("the name of the view is required")
+	case request.Page == "":
+		return k8s_errors.NewBadRequest("the page of the view is required")
+	case request.Duration < 0:
+		return k8s_errors.NewBadRequest("the duration of the view cannot be negative")
+	}
+	return nil
+}
+
+// findOwnedSavedView returns the index of a view of the given user. Changing a view shared by another user is
+// forbidden.
+func findOwnedSavedView(views []models.SavedView, user, id string) (int, error) {
+	for i, view := range views {
+		if view.ID != id {
+			continue
+		}
+		if view.Owner == user {
+			return i, nil
+		}
+		if view.Shared {
+			return -1, k8s_errors.NewForbidden(savedViewsResource, id, fmt.Errorf("the view is owned by another user"))
+		}
+		break
+	}
+	return -1, kubernetes.NewNotFound(id, "kiali.io", "savedviews")
+}
+
+// getSavedViews reads the saved views, with the service account of Kiali
+func getSavedViews() ([]models.SavedView, error) {
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := readKialiConfigMap(k8s, savedViewsConfigMapName, savedViewsConfigMapKey)
+	if err != nil {
+		return nil, err
+	}
+	return parseSavedViews(data)
+}
+
+// updateSavedViews applies the given change to the saved views and persists the result
+func updateSavedViews(change func([]models.SavedView) ([]models.SavedView, error)) error {
+	return updateKialiConfigMap(savedViewsConfigMapName, savedViewsConfigMapKey, func(data string) (string, error) {
+		views, err := parseSavedViews(data)
+		if err != nil {
+			return "", err
+		}
+		if views, err = change(views); err != nil {
+			return "", err
+		}
+		rawViews, err := json.Marshal(views)
+		return string(rawViews), err
+	})
+}
+
+func parseSavedViews(data string) ([]models.SavedView, error) {
+	views := []models.SavedView{}
+	if data == "" {
+		return views, nil
+	}
+	if err := json.Unmarshal([]byte(data), &views); err != nil {
+		return nil, fmt.Errorf("cannot parse the saved views in ConfigMap [%s]: %v", savedViewsConfigMapName, err)
+	}
+	return views, nil
+}
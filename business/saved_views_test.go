@@ -0,0 +1,97 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// setupKialiConfigMapMock mocks a ConfigMap of the namespace of Kiali, which doesn't exist when it is first read
+func setupKialiConfigMapMock(name string) *core_v1.ConfigMap {
+	conf := config.NewConfig()
+	config.Set(conf)
+	kubernetes.KialiToken = "kiali-sa-token"
+	util.Clock = util.ClockMock{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	stored := mockKialiConfigMap(k8s, name)
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+	return stored
+}
+
+// mockKialiConfigMap mocks a ConfigMap of the namespace of Kiali in the given client
+func mockKialiConfigMap(k8s *kubetest.K8SClientMock, name string) *core_v1.ConfigMap {
+	namespace := config.Get().Deployment.Namespace
+	stored := &core_v1.ConfigMap{}
+	k8s.On("GetConfigMap", namespace, name).Return((*core_v1.ConfigMap)(nil), kubernetes.NewNotFound(name, "core", "configmaps")).Once()
+	k8s.On("GetConfigMap", namespace, name).Return(stored, nil)
+	k8s.On("CreateConfigMap", namespace, mock.AnythingOfType("*v1.ConfigMap")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.ConfigMap)
+	}).Return(stored, nil)
+	k8s.On("UpdateConfigMap", namespace, mock.AnythingOfType("*v1.ConfigMap")).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*core_v1.ConfigMap)
+	}).Return(stored, nil)
+	return stored
+}
+
+func TestSavedViewLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	stored := setupKialiConfigMapMock(savedViewsConfigMapName)
+	service := SavedViewService{}
+
+	private, err := service.CreateSavedView("alice", models.SavedViewRequest{Name: "reviews", Page: "graph", Namespaces: []string{"bookinfo"}})
+	require.NoError(err)
+	assert.Equal("alice", private.Owner)
+	assert.Contains(stored.Data[savedViewsConfigMapKey], private.ID)
+	shared, err := service.CreateSavedView("alice", models.SavedViewRequest{Name: "errors", Page: "graph", Filters: map[string]string{"find": "%error>1"}, Shared: true})
+	require.NoError(err)
+
+	views, err := service.ListSavedViews("alice")
+	require.NoError(err)
+	require.Len(views, 2)
+	assert.Equal("errors", views[0].Name)
+	assert.Equal("%error>1", views[0].Filters["find"])
+
+	// The other users see the shared views only, and cannot change them
+	views, err = service.ListSavedViews("bob")
+	require.NoError(err)
+	require.Len(views, 1)
+	assert.Equal(shared.ID, views[0].ID)
+	_, err = service.GetSavedView("bob", private.ID)
+	assert.True(errors.IsNotFound(err))
+	_, err = service.UpdateSavedView("bob", shared.ID, models.SavedViewRequest{Name: "mine", Page: "graph"})
+	assert.True(errors.IsForbidden(err))
+	assert.True(errors.IsNotFound(service.DeleteSavedView("bob", private.ID)))
+
+	updated, err := service.UpdateSavedView("alice", private.ID, models.SavedViewRequest{Name: "reviews", Page: "graph", Duration: 600})
+	require.NoError(err)
+	assert.Equal(int64(600), updated.Duration)
+	assert.Equal(private.CreatedAt, updated.CreatedAt)
+
+	assert.NoError(service.DeleteSavedView("alice", private.ID))
+	views, err = service.ListSavedViews("alice")
+	require.NoError(err)
+	assert.Len(views, 1)
+}
+
+func TestSavedViewValidation(t *testing.T) {
+	setupKialiConfigMapMock(savedViewsConfigMapName)
+	service := SavedViewService{}
+
+	_, err := service.CreateSavedView("alice", models.SavedViewRequest{Page: "graph"})
+	assert.True(t, errors.IsBadRequest(err))
+	_, err = service.CreateSavedView("alice", models.SavedViewRequest{Name: "reviews"})
+	assert.True(t, errors.IsBadRequest(err))
+}
@@ -0,0 +1,83 @@
+package business
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/util"
+)
+
+// replacedSession tells when a session token stopped being valid, either because it was renewed or revoked
+type replacedSession struct {
+	ValidUntil time.Time `json:"validUntil"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// replacedSessions holds the ids of the session tokens that were renewed or revoked by this replica. A token is kept
+// until its own expiration, as after that it is rejected anyway.
+var replacedSessions = struct {
+	sync.Mutex
+	tokens map[string]replacedSession
+}{tokens: map[string]replacedSession{}}
+
+func replacedSessionKey(id string) string {
+	return "session:replaced:" + id
+}
+
+// IsReplacedSession returns true if the session token with the given id was renewed or revoked and can't be used
+// anymore. The tokens replaced by the other replicas of Kiali are known through the results cache, when it is shared.
+func IsReplacedSession(id string) bool {
+	replaced, ok := getReplacedSession(id)
+	return ok && !util.Clock.Now().Before(replaced.ValidUntil)
+}
+
+// IsRenewedSession returns true if the session token with the given id was already renewed or revoked, even if it is
+// still accepted for the grace period: the requests sent with it don't renew the session again.
+func IsRenewedSession(id string) bool {
+	_, ok := getReplacedSession(id)
+	return ok
+}
+
+func getReplacedSession(id string) (replacedSession, bool) {
+	if id == "" {
+		return replacedSession{}, false
+	}
+
+	replacedSessions.Lock()
+	replaced, ok := replacedSessions.tokens[id]
+	replacedSessions.Unlock()
+	if !ok {
+		resultsCacheOnce.Do(initResultsCache)
+		ok = resultsCache != nil && resultsCache.Get(replacedSessionKey(id), &replaced)
+	}
+	return replaced, ok
+}
+
+// ReplaceSession stops accepting the session token with the given id, which expires at the given time, after the given
+// grace period. The replaced token is stored in the results cache too, so that the other replicas of Kiali reject it
+// when the cache is shared (i.e. stored in Redis).
+func ReplaceSession(id string, expiresAt time.Time, gracePeriod time.Duration) {
+	if id == "" {
+		return
+	}
+
+	now := util.Clock.Now()
+	replaced := replacedSession{ValidUntil: now.Add(gracePeriod), ExpiresAt: expiresAt}
+	replacedSessions.Lock()
+	for replacedId, entry := range replacedSessions.tokens {
+		if now.After(entry.ExpiresAt) {
+			delete(replacedSessions.tokens, replacedId)
+		}
+	}
+	if previous, ok := replacedSessions.tokens[id]; ok {
+		replaced = previous
+	} else {
+		replacedSessions.tokens[id] = replaced
+	}
+	replacedSessions.Unlock()
+
+	resultsCacheOnce.Do(initResultsCache)
+	if ttl := expiresAt.Sub(now); resultsCache != nil && ttl > 0 {
+		resultsCache.Set(replacedSessionKey(id), replaced, ttl)
+	}
+}
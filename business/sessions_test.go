@@ -0,0 +1,38 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/util"
+)
+
+func TestReplacedSessionSharedThroughResultsCache(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	util.Clock = util.ClockMock{Time: now}
+	resultsCacheOnce.Do(func() {})
+	shared, err := cache.NewResultsCache(config.ResultsCacheConfig{Backend: config.ResultsCacheBackendMemory})
+	assert.NoError(err)
+	resultsCache = shared
+	defer func() { resultsCache = nil }()
+
+	ReplaceSession("renewed-id", now.Add(time.Hour), 30*time.Second)
+	ReplaceSession("revoked-id", now.Add(time.Hour), 0)
+	assert.False(IsReplacedSession("renewed-id"))
+	assert.True(IsReplacedSession("revoked-id"))
+
+	// Another replica only knows the sessions through the results cache
+	replacedSessions.Lock()
+	replacedSessions.tokens = map[string]replacedSession{}
+	replacedSessions.Unlock()
+	assert.True(IsReplacedSession("revoked-id"))
+	assert.False(IsReplacedSession("renewed-id"))
+	util.Clock = util.ClockMock{Time: now.Add(time.Minute)}
+	assert.True(IsReplacedSession("renewed-id"))
+	assert.False(IsReplacedSession("other-id"))
+}
@@ -0,0 +1,270 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	pmod "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultSidecarSizingWindow = "1d"
+	// Ratio of the recommended request to the usage of the 95th percentile of the proxies
+	sidecarRequestHeadroom = 1.2
+	// Ratio of the recommended limit to the highest usage of the proxies
+	sidecarLimitHeadroom = 2.0
+	// Ratio of the limit above which the usage of a proxy is considered close to it
+	sidecarLimitPressure = 0.9
+)
+
+// Annotations of the pod templates overriding the resources of the injected proxies
+const (
+	proxyCPUAnnotation         = "sidecar.istio.io/proxyCPU"
+	proxyCPULimitAnnotation    = "sidecar.istio.io/proxyCPULimit"
+	proxyMemoryAnnotation      = "sidecar.istio.io/proxyMemory"
+	proxyMemoryLimitAnnotation = "sidecar.istio.io/proxyMemoryLimit"
+)
+
+// sidecarResource describes how a resource of the proxies is sized
+type sidecarResource struct {
+	name string
+	// Quantities are rounded up to the step, and recommended at least at the minimum
+	step    float64
+	minimum float64
+	format  string
+	// Consequence of a usage close to the limit
+	limitRisk string
+}
+
+var (
+	sidecarCPU    = sidecarResource{name: "CPU", step: 10, minimum: 10, format: "%.0fm", limitRisk: "throttled"}
+	sidecarMemory = sidecarResource{name: "memory", step: 16, minimum: 32, format: "%.0fMi", limitRisk: "OOM killed"}
+)
+
+// proxyResource is the request, limit and usage of a resource by a proxy. Zero is unset, and the usage is nil when
+// it is not reported.
+type proxyResource struct {
+	request float64
+	limit   float64
+	usage   *float64
+}
+
+type proxySizing struct {
+	cpu    proxyResource
+	memory proxyResource
+}
+
+// GetSidecarSizing compares the peak CPU and memory usages of the proxies injected in the accessible namespaces over
+// a window to their requests and limits. It recommends the resources of the proxies of each namespace, as annotations
+// when they differ from the ones recommended for the whole mesh, set in the values of the Istio installation.
+func (in *MeshService) GetSidecarSizing(window string) (*models.SidecarSizingReport, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "GetSidecarSizing")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultSidecarSizingWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 1d", window))
+		return nil, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := in.getNamespacesPods(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SidecarSizingReport{Window: window, Namespaces: []models.NamespaceSidecarSizing{}}
+	all := []proxySizing{}
+	queryTime := util.Clock.Now()
+	for _, ns := range namespaces {
+		proxies := map[string]*proxySizing{}
+		for _, pod := range pods[ns.Name] {
+			if proxy, ok := podProxySizing(pod); ok {
+				proxies[pod.Name] = &proxy
+			}
+		}
+		if len(proxies) == 0 {
+			continue
+		}
+		cpu, memory, err := in.prom.GetContainerResourceUsage(ns.Name, istioProxyContainer, window, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range cpu {
+			if proxy, ok := proxies[string(sample.Metric["pod"])]; ok {
+				// Cores to millicores
+				usage := float64(sample.Value) * 1000
+				proxy.cpu.usage = &usage
+			}
+		}
+		for _, sample := range memory {
+			if proxy, ok := proxies[string(sample.Metric["pod"])]; ok {
+				// Bytes to MiB
+				usage := float64(sample.Value) / (1 << 20)
+				proxy.memory.usage = &usage
+			}
+		}
+
+		nsProxies := make([]proxySizing, 0, len(proxies))
+		for _, proxy := range proxies {
+			nsProxies = append(nsProxies, *proxy)
+		}
+		all = append(all, nsProxies...)
+		report.Namespaces = append(report.Namespaces, models.NamespaceSidecarSizing{Namespace: ns.Name, SidecarSizing: sizeSidecars(nsProxies)})
+	}
+
+	report.Global = sizeSidecars(all)
+	if report.Global.MeasuredProxies == 0 {
+		return report, nil
+	}
+	values, _ := yaml.Marshal(map[string]interface{}{
+		"values": map[string]interface{}{"global": map[string]interface{}{"proxy": map[string]interface{}{"resources": map[string]interface{}{
+			"requests": map[string]string{"cpu": report.Global.CPU.RecommendedRequest, "memory": report.Global.Memory.RecommendedRequest},
+			"limits":   map[string]string{"cpu": report.Global.CPU.RecommendedLimit, "memory": report.Global.Memory.RecommendedLimit},
+		}}}},
+	})
+	report.GlobalValues = string(values)
+	for i, nsSizing := range report.Namespaces {
+		if nsSizing.MeasuredProxies == 0 || (nsSizing.CPU.RecommendedRequest == report.Global.CPU.RecommendedRequest &&
+			nsSizing.CPU.RecommendedLimit == report.Global.CPU.RecommendedLimit &&
+			nsSizing.Memory.RecommendedRequest == report.Global.Memory.RecommendedRequest &&
+			nsSizing.Memory.RecommendedLimit == report.Global.Memory.RecommendedLimit) {
+			continue
+		}
+		report.Namespaces[i].Annotations = map[string]string{
+			proxyCPUAnnotation:         nsSizing.CPU.RecommendedRequest,
+			proxyCPULimitAnnotation:    nsSizing.CPU.RecommendedLimit,
+			proxyMemoryAnnotation:      nsSizing.Memory.RecommendedRequest,
+			proxyMemoryLimitAnnotation: nsSizing.Memory.RecommendedLimit,
+		}
+	}
+	return report, nil
+}
+
+// podProxySizing returns the requests and limits of the proxy injected in a pod
+func podProxySizing(pod core_v1.Pod) (proxySizing, bool) {
+	if _, injected := pod.Annotations[config.Get().ExternalServices.Istio.IstioSidecarAnnotation]; !injected {
+		return proxySizing{}, false
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name != istioProxyContainer {
+			continue
+		}
+		proxy := proxySizing{}
+		if q, ok := c.Resources.Requests[core_v1.ResourceCPU]; ok {
+			proxy.cpu.request = float64(q.MilliValue())
+		}
+		if q, ok := c.Resources.Limits[core_v1.ResourceCPU]; ok {
+			proxy.cpu.limit = float64(q.MilliValue())
+		}
+		if q, ok := c.Resources.Requests[core_v1.ResourceMemory]; ok {
+			proxy.memory.request = float64(q.Value()) / (1 << 20)
+		}
+		if q, ok := c.Resources.Limits[core_v1.ResourceMemory]; ok {
+			proxy.memory.limit = float64(q.Value()) / (1 << 20)
+		}
+		return proxy, true
+	}
+	return proxySizing{}, false
+}
+
+// sizeSidecars returns the sizing of a set of proxies
+func sizeSidecars(proxies []proxySizing) models.SidecarSizing {
+	sizing := models.SidecarSizing{Proxies: len(proxies), Findings: []string{}}
+	cpu := make([]proxyResource, len(proxies))
+	memory := make([]proxyResource, len(proxies))
+	for i, p := range proxies {
+		cpu[i] = p.cpu
+		memory[i] = p.memory
+		if p.cpu.usage != nil || p.memory.usage != nil {
+			sizing.MeasuredProxies++
+		}
+	}
+	var findings []string
+	sizing.CPU, findings = sizeResource(sidecarCPU, cpu)
+	sizing.Findings = append(sizing.Findings, findings...)
+	sizing.Memory, findings = sizeResource(sidecarMemory, memory)
+	sizing.Findings = append(sizing.Findings, findings...)
+	return sizing
+}
+
+// sizeResource compares the usages of a resource by proxies to their most common request and limit, and recommends
+// them, with the reasons of the recommendation
+func sizeResource(r sidecarResource, proxies []proxyResource) (models.ResourceSizing, []string) {
+	sizing := models.ResourceSizing{}
+	findings := []string{}
+	usages := []float64{}
+	requests := map[float64]int{}
+	limits := map[float64]int{}
+	for _, p := range proxies {
+		sizing.TotalRequested += p.request
+		requests[p.request]++
+		limits[p.limit]++
+		if p.usage != nil {
+			usages = append(usages, *p.usage)
+			sizing.TotalUsage += *p.usage
+		}
+	}
+	request, limit := mostCommon(requests), mostCommon(limits)
+	if request > 0 {
+		sizing.Request = fmt.Sprintf(r.format, request)
+	}
+	if limit > 0 {
+		sizing.Limit = fmt.Sprintf(r.format, limit)
+	}
+	if len(usages) == 0 {
+		return sizing, findings
+	}
+
+	sort.Float64s(usages)
+	p95 := usages[int(math.Ceil(0.95*float64(len(usages))))-1]
+	highest := usages[len(usages)-1]
+	sizing.P95Usage, sizing.MaxUsage = &p95, &highest
+
+	recommendedRequest := math.Max(roundUp(p95*sidecarRequestHeadroom, r.step), r.minimum)
+	recommendedLimit := math.Max(roundUp(highest*sidecarLimitHeadroom, r.step), recommendedRequest)
+	sizing.RecommendedRequest = fmt.Sprintf(r.format, recommendedRequest)
+	sizing.RecommendedLimit = fmt.Sprintf(r.format, recommendedLimit)
+
+	switch {
+	case request == 0:
+		findings = append(findings, fmt.Sprintf("No %s request is set on the proxies: they are scheduled as if they used none", r.name))
+	case p95 > request:
+		findings = append(findings, fmt.Sprintf("The %s request of %s is under the usage of 95%% of the proxies (%s): their nodes can be overcommitted", r.name, sizing.Request, fmt.Sprintf(r.format, p95)))
+	case p95*2 < request:
+		findings = append(findings, fmt.Sprintf("The %s request of %s is over twice the usage of 95%% of the proxies (%s): %s are reserved and unused", r.name, sizing.Request, fmt.Sprintf(r.format, p95), fmt.Sprintf(r.format, sizing.TotalRequested-sizing.TotalUsage)))
+	}
+	if limit > 0 && highest > limit*sidecarLimitPressure {
+		findings = append(findings, fmt.Sprintf("The highest %s usage of %s is close to the limit of %s: the proxies risk being %s", r.name, fmt.Sprintf(r.format, highest), sizing.Limit, r.limitRisk))
+	}
+	return sizing, findings
+}
+
+// mostCommon returns the most common value of counts, the lowest one on ties
+func mostCommon(counts map[float64]int) float64 {
+	value, count := 0.0, 0
+	for v, c := range counts {
+		if c > count || (c == count && v < value) {
+			value, count = v, c
+		}
+	}
+	return value
+}
+
+func roundUp(value, step float64) float64 {
+	return math.Ceil(value/step) * step
+}
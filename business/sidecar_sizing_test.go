@@ -0,0 +1,124 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeSizedProxyPod(name string, injected bool) core_v1.Pod {
+	pod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		Spec: core_v1.PodSpec{Containers: []core_v1.Container{
+			{Name: "details", Image: "docker.io/istio/examples-bookinfo-details-v1:1.16.2"},
+			{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.9.0", Resources: core_v1.ResourceRequirements{
+				Requests: core_v1.ResourceList{core_v1.ResourceCPU: resource.MustParse("100m"), core_v1.ResourceMemory: resource.MustParse("128Mi")},
+				Limits:   core_v1.ResourceList{core_v1.ResourceCPU: resource.MustParse("2"), core_v1.ResourceMemory: resource.MustParse("256Mi")},
+			}},
+		}},
+	}
+	if injected {
+		pod.Annotations["sidecar.istio.io/status"] = `{"containers":["istio-proxy"]}`
+	}
+	return pod
+}
+
+func fakePodSample(pod string, value float64) *pmod.Sample {
+	return &pmod.Sample{Metric: pmod.Metric{"pod": pmod.LabelValue(pod)}, Value: pmod.SampleValue(value)}
+}
+
+func TestGetSidecarSizing(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.API.Namespaces.Exclude = []string{}
+	config.Set(conf)
+	kialiCache = nil
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "legacy"}},
+	}, nil)
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeSizedProxyPod("details-v1-1", true),
+		fakeSizedProxyPod("details-v1-2", true),
+		fakeSizedProxyPod("reviews-v1-1", true),
+	}, nil)
+	k8s.On("GetPods", "legacy", "").Return([]core_v1.Pod{fakeSizedProxyPod("legacy-1", false)}, nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetContainerResourceUsage", "bookinfo", "istio-proxy", "1d", mock.AnythingOfType("time.Time")).Return(
+		pmod.Vector{fakePodSample("details-v1-1", 0.012), fakePodSample("details-v1-2", 0.015), fakePodSample("reviews-v1-1", 0.041)},
+		pmod.Vector{fakePodSample("details-v1-1", 40<<20), fakePodSample("details-v1-2", 42<<20), fakePodSample("reviews-v1-1", 240<<20)},
+		nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	report, err := layer.Mesh.GetSidecarSizing("")
+	assert.NoError(err)
+	assert.Equal("1d", report.Window)
+
+	// Pods without injected proxies are ignored
+	assert.Len(report.Namespaces, 1)
+	sizing := report.Namespaces[0]
+	assert.Equal("bookinfo", sizing.Namespace)
+	assert.Equal(3, sizing.Proxies)
+	assert.Equal(3, sizing.MeasuredProxies)
+
+	assert.Equal("100m", sizing.CPU.Request)
+	assert.Equal("2000m", sizing.CPU.Limit)
+	assert.InDelta(41, *sizing.CPU.P95Usage, 0.001)
+	assert.InDelta(300, sizing.CPU.TotalRequested, 0.001)
+	assert.InDelta(68, sizing.CPU.TotalUsage, 0.001)
+	assert.Equal("50m", sizing.CPU.RecommendedRequest)
+	assert.Equal("90m", sizing.CPU.RecommendedLimit)
+
+	assert.Equal("128Mi", sizing.Memory.Request)
+	assert.Equal("256Mi", sizing.Memory.Limit)
+	assert.InDelta(240, *sizing.Memory.MaxUsage, 0.001)
+	assert.Equal("288Mi", sizing.Memory.RecommendedRequest)
+	assert.Equal("480Mi", sizing.Memory.RecommendedLimit)
+
+	assert.Len(sizing.Findings, 3)
+	assert.Contains(sizing.Findings[0], "The CPU request of 100m is over twice the usage of 95% of the proxies (41m)")
+	assert.Contains(sizing.Findings[1], "The memory request of 128Mi is under the usage of 95% of the proxies (240Mi)")
+	assert.Contains(sizing.Findings[2], "close to the limit of 256Mi: the proxies risk being OOM killed")
+
+	// A single namespace is sized as the whole mesh: the global values apply to it
+	assert.Nil(sizing.Annotations)
+	assert.Equal(sizing.SidecarSizing, report.Global)
+	assert.Contains(report.GlobalValues, "cpu: 50m")
+	assert.Contains(report.GlobalValues, "memory: 480Mi")
+}
+
+func TestSizeSidecarsWithoutUsage(t *testing.T) {
+	assert := assert.New(t)
+
+	sizing := sizeSidecars([]proxySizing{{}, {}})
+	assert.Equal(2, sizing.Proxies)
+	assert.Equal(0, sizing.MeasuredProxies)
+	assert.Empty(sizing.CPU.Request)
+	assert.Empty(sizing.CPU.RecommendedRequest)
+	assert.Empty(sizing.Findings)
+
+	usage := 3.0
+	sizing = sizeSidecars([]proxySizing{{cpu: proxyResource{usage: &usage}}})
+	assert.Equal(1, sizing.MeasuredProxies)
+	// The minimums are recommended for the proxies using little
+	assert.Equal("10m", sizing.CPU.RecommendedRequest)
+	assert.Equal("10m", sizing.CPU.RecommendedLimit)
+	assert.Equal([]string{"No CPU request is set on the proxies: they are scheduled as if they used none"}, sizing.Findings)
+}
@@ -0,0 +1,239 @@
+package business
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Namespace annotations setting the objectives of the services and workloads of the namespace, overriding the
+// objectives of the configuration
+const (
+	SLOAvailabilityAnnotation     = "slo.kiali.io/availability"
+	SLOLatencyTargetAnnotation    = "slo.kiali.io/latency-target"
+	SLOLatencyThresholdAnnotation = "slo.kiali.io/latency-threshold"
+	SLOWindowAnnotation           = "slo.kiali.io/window"
+)
+
+const defaultSLOWindow = "30d"
+
+// SLOService computes the attainment of the service level objectives from the Istio request metrics
+type SLOService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// burnRateWindow is a multi-window burn rate alert, firing when the given ratio of the error budget of the window of
+// the objective is consumed within the long window. See the Site Reliability Workbook, "Alerting on SLOs": for a 30d
+// window, the thresholds are the burn rates of the workbook (14.4, 6, 3 and 1).
+type burnRateWindow struct {
+	severity    string
+	long, short time.Duration
+	budget      float64
+}
+
+var burnRateWindows = []burnRateWindow{
+	{severity: "page", long: time.Hour, short: 5 * time.Minute, budget: 0.02},
+	{severity: "page", long: 6 * time.Hour, short: 30 * time.Minute, budget: 0.05},
+	{severity: "ticket", long: 24 * time.Hour, short: 2 * time.Hour, budget: 0.1},
+	{severity: "ticket", long: 72 * time.Hour, short: 6 * time.Hour, budget: 0.1},
+}
+
+// sli is the ratio of the bad requests of an objective: the parts series over the total series, or the complement
+// of that ratio when the parts series count the good requests
+type sli struct {
+	parts []string
+	total string
+	good  bool
+}
+
+// GetServiceSLO returns the attainment of the objectives of a service, for its inbound requests
+func (in *SLOService) GetServiceSLO(namespace, service string, queryTime time.Time) (*models.SLOStatus, error) {
+	lb := NewMetricsLabelsBuilder("inbound").SelfReporter().Service(service, namespace)
+	return in.getSLO(namespace, "service", service, lb, queryTime)
+}
+
+// GetWorkloadSLO returns the attainment of the objectives of a workload, for its inbound requests
+func (in *SLOService) GetWorkloadSLO(namespace, workload string, queryTime time.Time) (*models.SLOStatus, error) {
+	lb := NewMetricsLabelsBuilder("inbound").SelfReporter().Workload(workload, namespace)
+	return in.getSLO(namespace, "workload", workload, lb, queryTime)
+}
+
+func (in *SLOService) getSLO(namespace, kind, name string, lb *MetricsLabelsBuilder, queryTime time.Time) (*models.SLOStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SLOService", "getSLO")
+	defer promtimer.ObserveNow(&err)
+
+	// Check the user has access to the namespace
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var annotations map[string]string
+	// Users of OpenShift projects may not be allowed to read the namespace, the objectives of the configuration are
+	// used then
+	if ns, nsErr := in.k8s.GetNamespace(namespace); nsErr == nil {
+		annotations = ns.Annotations
+	} else {
+		log.Debugf("Cannot read the SLO annotations of namespace [%s]: %v", namespace, nsErr)
+	}
+	objective, found := resolveSLOObjective(config.Get().SLO.Objectives, annotations, namespace, kind, name)
+	if !found {
+		err = kubernetes.NewNotFound(name, "Kiali", "SLO")
+		return nil, err
+	}
+	window, err := pmod.ParseDuration(objective.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window [%s] of SLO: %v", objective.Window, err)
+	}
+
+	labels := lb.Build()
+	status := &models.SLOStatus{Namespace: namespace, Kind: kind, Name: name, Window: objective.Window}
+	if objective.Availability > 0 {
+		availability := sli{parts: metricSelectors("istio_requests_total", lb.BuildForServerErrors()), total: "istio_requests_total" + labels}
+		if status.Availability, err = in.getSLIStatus(availability, objective.Availability, time.Duration(window), queryTime); err != nil {
+			return nil, err
+		}
+	}
+	if objective.LatencyTarget > 0 {
+		threshold := strconv.FormatFloat(objective.LatencyThreshold, 'f', -1, 64)
+		// Requests faster than the threshold are counted in the bucket of the threshold
+		fast := fmt.Sprintf(`istio_request_duration_milliseconds_bucket%s,le="%s"}`, strings.TrimSuffix(labels, "}"), threshold)
+		latency := sli{parts: []string{fast}, total: "istio_request_duration_milliseconds_count" + labels, good: true}
+		if status.Latency, err = in.getSLIStatus(latency, objective.LatencyTarget, time.Duration(window), queryTime); err != nil {
+			return nil, err
+		}
+		status.Latency.ThresholdMillis = objective.LatencyThreshold
+	}
+	return status, nil
+}
+
+// resolveSLOObjective returns the objective of a service or workload: the first objective of the configuration
+// matching it, with the values set by the annotations of its namespace
+func resolveSLOObjective(objectives []config.SLOObjective, annotations map[string]string, namespace, kind, name string) (config.SLOObjective, bool) {
+	objective := config.SLOObjective{}
+	for _, o := range objectives {
+		if o.Matches(namespace, kind, name) {
+			objective = o
+			break
+		}
+	}
+	for annotation, value := range map[string]*float64{
+		SLOAvailabilityAnnotation:     &objective.Availability,
+		SLOLatencyTargetAnnotation:    &objective.LatencyTarget,
+		SLOLatencyThresholdAnnotation: &objective.LatencyThreshold,
+	} {
+		raw, ok := annotations[annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(raw, 64)
+		// Objectives are percentages, the threshold is in milliseconds
+		if err != nil || parsed < 0 || (parsed >= 100 && annotation != SLOLatencyThresholdAnnotation) {
+			log.Warningf("Ignoring invalid annotation [%s: %s] of namespace [%s]", annotation, raw, namespace)
+			continue
+		}
+		*value = parsed
+	}
+	if window, ok := annotations[SLOWindowAnnotation]; ok {
+		if _, err := pmod.ParseDuration(window); err == nil {
+			objective.Window = window
+		} else {
+			log.Warningf("Ignoring invalid annotation [%s: %s] of namespace [%s]", SLOWindowAnnotation, window, namespace)
+		}
+	}
+	if objective.Window == "" {
+		objective.Window = defaultSLOWindow
+	}
+	if objective.LatencyThreshold <= 0 {
+		objective.LatencyTarget = 0
+	}
+	return objective, objective.Availability > 0 || objective.LatencyTarget > 0
+}
+
+// metricSelectors prefixes label selectors with the name of a metric, so that they match its series only
+func metricSelectors(metric string, selectors []string) []string {
+	prefixed := make([]string, len(selectors))
+	for i, s := range selectors {
+		prefixed[i] = metric + s
+	}
+	return prefixed
+}
+
+// getSLIStatus fetches the ratios of bad requests over the window of the objective and the windows of the burn
+// rate alerts shorter than the window of the objective
+func (in *SLOService) getSLIStatus(indicator sli, objective float64, window time.Duration, queryTime time.Time) (*models.SLIStatus, error) {
+	windows := map[time.Duration]bool{window: true}
+	alerts := []burnRateWindow{}
+	for _, alert := range burnRateWindows {
+		if alert.long <= window {
+			alerts = append(alerts, alert)
+			windows[alert.long] = true
+			windows[alert.short] = true
+		}
+	}
+
+	type ratio struct {
+		value   float64
+		hasData bool
+	}
+	ratios := make(map[time.Duration]ratio, len(windows))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var fetchErr error
+	for w := range windows {
+		wg.Add(1)
+		go func(w time.Duration) {
+			defer wg.Done()
+			value, hasData, err := in.prom.FetchRateRatio(indicator.parts, indicator.total, pmod.Duration(w).String(), queryTime)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				fetchErr = err
+				return
+			}
+			if indicator.good && hasData {
+				value = 1 - value
+			}
+			ratios[w] = ratio{value: value, hasData: hasData}
+		}(w)
+	}
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	budget := 1 - objective/100
+	status := &models.SLIStatus{
+		Objective:            objective,
+		HasData:              ratios[window].hasData,
+		Attainment:           100 * (1 - ratios[window].value),
+		ErrorBudgetRemaining: 1 - ratios[window].value/budget,
+		BurnRates:            []models.BurnRate{},
+	}
+	for _, alert := range alerts {
+		burnRate := models.BurnRate{
+			Severity:      alert.severity,
+			LongWindow:    pmod.Duration(alert.long).String(),
+			ShortWindow:   pmod.Duration(alert.short).String(),
+			LongBurnRate:  ratios[alert.long].value / budget,
+			ShortBurnRate: ratios[alert.short].value / budget,
+			Threshold:     alert.budget * float64(window) / float64(alert.long),
+		}
+		burnRate.Firing = burnRate.LongBurnRate > burnRate.Threshold && burnRate.ShortBurnRate > burnRate.Threshold
+		status.Alerting = status.Alerting || burnRate.Firing
+		status.BurnRates = append(status.BurnRates, burnRate)
+	}
+	return status, nil
+}
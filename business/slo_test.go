@@ -0,0 +1,124 @@
+package business
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestResolveSLOObjective(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	objectives := []config.SLOObjective{
+		{Namespace: "bookinfo", Kind: "service", Name: "reviews.*", Availability: 99.9, Window: "7d"},
+		{Namespace: ".*", Availability: 99},
+	}
+
+	objective, found := resolveSLOObjective(objectives, nil, "bookinfo", "service", "reviews")
+	assert.True(found)
+	assert.Equal(99.9, objective.Availability)
+	assert.Equal("7d", objective.Window)
+
+	objective, found = resolveSLOObjective(objectives, nil, "bookinfo", "workload", "reviews-v1")
+	assert.True(found)
+	assert.Equal(99.0, objective.Availability)
+	assert.Equal("30d", objective.Window)
+
+	// Annotations override the configuration, invalid ones are ignored
+	objective, found = resolveSLOObjective(objectives, map[string]string{
+		SLOAvailabilityAnnotation:     "100",
+		SLOLatencyTargetAnnotation:    "95",
+		SLOLatencyThresholdAnnotation: "250",
+		SLOWindowAnnotation:           "1d",
+	}, "bookinfo", "service", "reviews")
+	assert.True(found)
+	assert.Equal(99.9, objective.Availability)
+	assert.Equal(95.0, objective.LatencyTarget)
+	assert.Equal(250.0, objective.LatencyThreshold)
+	assert.Equal("1d", objective.Window)
+
+	// Latency objectives require a threshold
+	_, found = resolveSLOObjective(nil, map[string]string{SLOLatencyTargetAnnotation: "95"}, "bookinfo", "service", "reviews")
+	assert.False(found)
+}
+
+func TestGetServiceSLO(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.SLO.Objectives = []config.SLOObjective{{Availability: 99, LatencyTarget: 90, LatencyThreshold: 100}}
+	config.Set(conf)
+
+	ns := kubetest.FakeNamespace("bookinfo")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespace", "bookinfo").Return(ns, nil)
+
+	queryTime := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"}`
+	prom := new(prometheustest.PromClientMock)
+	serverErrors := mock.MatchedBy(func(parts []string) bool {
+		for _, p := range parts {
+			if !strings.HasPrefix(p, "istio_requests_total{") {
+				return false
+			}
+		}
+		return len(parts) == 2
+	})
+	// 5% of server errors in the last hour, 2% in the other windows
+	prom.On("FetchRateRatio", serverErrors, "istio_requests_total"+labels, mock.MatchedBy(func(w string) bool { return w == "1h" || w == "5m" }), queryTime).Return(0.05, true, nil)
+	prom.On("FetchRateRatio", serverErrors, "istio_requests_total"+labels, mock.Anything, queryTime).Return(0.02, true, nil)
+	// 80% of the requests faster than the threshold
+	prom.On("FetchRateRatio", []string{`istio_request_duration_milliseconds_bucket{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo",le="100"}`}, "istio_request_duration_milliseconds_count"+labels, mock.Anything, queryTime).Return(0.8, true, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	slo, err := layer.SLO.GetServiceSLO("bookinfo", "reviews", queryTime)
+	assert.NoError(err)
+	assert.Equal("30d", slo.Window)
+
+	assert.True(slo.Availability.HasData)
+	assert.InDelta(98.0, slo.Availability.Attainment, 0.0001)
+	assert.InDelta(-1.0, slo.Availability.ErrorBudgetRemaining, 0.0001)
+	assert.Len(slo.Availability.BurnRates, 4)
+	page := slo.Availability.BurnRates[0]
+	assert.Equal("1h", page.LongWindow)
+	assert.Equal("5m", page.ShortWindow)
+	assert.InDelta(5.0, page.LongBurnRate, 0.0001)
+	assert.InDelta(14.4, page.Threshold, 0.0001)
+	assert.False(page.Firing)
+	ticket := slo.Availability.BurnRates[3]
+	assert.Equal("3d", ticket.LongWindow)
+	assert.InDelta(2.0, ticket.LongBurnRate, 0.0001)
+	assert.InDelta(1.0, ticket.Threshold, 0.0001)
+	assert.True(ticket.Firing)
+	assert.True(slo.Availability.Alerting)
+
+	assert.Equal(100.0, slo.Latency.ThresholdMillis)
+	assert.InDelta(80.0, slo.Latency.Attainment, 0.0001)
+	assert.InDelta(2.0, slo.Latency.BurnRates[0].LongBurnRate, 0.0001)
+	assert.True(slo.Latency.Alerting)
+}
+
+func TestGetWorkloadSLONotFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+
+	layer := NewWithBackends(k8s, new(prometheustest.PromClientMock), nil)
+	_, err := layer.SLO.GetWorkloadSLO("bookinfo", "reviews-v1", time.Now())
+	assert.Error(err)
+	assert.True(errors.IsNotFound(err))
+}
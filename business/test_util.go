@@ -755,3 +755,12 @@ func FakeServices() []core_v1.Service {
 		},
 	}
 }
+
+func FakePodLogsWithTraces() *kubernetes.PodLogs {
+	return &kubernetes.PodLogs{
+		Logs: `2018-01-02T03:34:28+00:00 {"level":"info","msg":"GET /reviews/0","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7"}
+2018-01-02T03:34:29+00:00 level=info msg="rating fetched" traceId=0000000000000000a3ce929d0e0e4736 spanId=b7ad6b7169203331
+2018-01-02T03:34:30+00:00 [2018-01-02T03:34:30.000Z] "GET /reviews/0 HTTP/1.1" 200 - "traceparent: 00-4bf92f3577b34da6a3ce929d0e0e4736-a2fb4a1d1a96d312-01"
+2018-01-02T03:34:31+00:00 INFO no trace`,
+	}
+}
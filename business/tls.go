@@ -8,11 +8,13 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/util/mtls"
 )
 
 type TLSService struct {
 	k8s             kubernetes.ClientInterface
+	prom            prometheus.ClientInterface
 	businessLayer   *Layer
 	enabledAutoMtls *bool
 }
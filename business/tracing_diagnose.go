@@ -0,0 +1,363 @@
+package business
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+const tracingDiagnoseTimeout = 5 * time.Second
+
+// Traces are queried by time range: a larger skew between the clocks of Kiali and of the backend hides recent traces
+const maxTracingClockSkew = 30 * time.Second
+
+// Certificates of the backend expiring within this delay are reported
+const tracingCertExpiryWarning = 7 * 24 * time.Hour
+
+// Flavors of the tracing APIs detected by the diagnosis
+const (
+	tracingFlavorJaeger  = "jaeger"
+	tracingFlavorOTLP    = "otlp"
+	tracingFlavorTempo   = "tempo"
+	tracingFlavorGRPC    = "grpc"
+	tracingFlavorUnknown = "unknown"
+)
+
+type tracingDiagnosis struct {
+	models.TracingDiagnosis
+	auth config.Auth
+}
+
+func (d *tracingDiagnosis) add(name, status, message, hint string) {
+	d.Checks = append(d.Checks, models.TracingCheck{Name: name, Status: status, Message: message, Hint: hint})
+}
+
+// DiagnoseTracing probes the configured tracing backend, step by step: connection, TLS handshake, credentials, flavor
+// of the API and clock skew. The probes stop at the first step failing; each failure comes with a hint to fix it.
+func DiagnoseTracing(token string) models.TracingDiagnosis {
+	conf := config.Get()
+	cfg := conf.ExternalServices.Tracing
+	rawURL := cfg.InClusterURL
+	urlField := "external_services.tracing.in_cluster_url"
+	if !conf.InCluster {
+		rawURL = cfg.URL
+		urlField = "external_services.tracing.url"
+	}
+	d := &tracingDiagnosis{
+		TracingDiagnosis: models.TracingDiagnosis{
+			URL:            rawURL,
+			Provider:       cfg.Provider,
+			DetectedFlavor: tracingFlavorUnknown,
+			Checks:         []models.TracingCheck{},
+		},
+		auth: cfg.Auth,
+	}
+	if d.auth.UseKialiToken {
+		d.auth.Token = token
+	}
+
+	if !cfg.Enabled {
+		d.add("url", models.TracingCheckSkipped, "Tracing is disabled", "Set external_services.tracing.enabled to true to query the traces")
+		return d.TracingDiagnosis
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		d.add("url", models.TracingCheckError, fmt.Sprintf("Invalid URL [%s]", rawURL),
+			fmt.Sprintf("Set %s to the HTTP URL of the query API of the backend, i.e. http://tracing.istio-system:16686/jaeger", urlField))
+		return d.TracingDiagnosis
+	}
+	d.add("url", models.TracingCheckOK, fmt.Sprintf("Querying [%s]", rawURL), "")
+
+	if !d.checkConnection(u, conf.InCluster) || !d.checkTLS(u) {
+		return d.TracingDiagnosis
+	}
+	d.checkAPI(u, cfg.Provider)
+	if tempoURL := tempoURL(conf.InCluster, cfg.Tempo); tempoURL != "" {
+		d.checkTempo(tempoURL)
+	}
+	return d.TracingDiagnosis
+}
+
+func tempoURL(inCluster bool, cfg config.TempoConfig) string {
+	if inCluster {
+		return cfg.InClusterURL
+	}
+	return cfg.URL
+}
+
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+func (d *tracingDiagnosis) checkConnection(u *url.URL, inCluster bool) bool {
+	conn, err := net.DialTimeout("tcp", hostPort(u), tracingDiagnoseTimeout)
+	if err == nil {
+		conn.Close()
+		d.add("connection", models.TracingCheckOK, fmt.Sprintf("Connected to [%s]", hostPort(u)), "")
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		hint := "Check the host of the URL. In the cluster, the backend is reached by the name of its service, i.e. tracing.istio-system."
+		if !inCluster {
+			hint = "Check the host of the URL: Kiali runs out of the cluster, the URL must be reachable from where Kiali runs, i.e. through a route or an ingress."
+		}
+		d.add("connection", models.TracingCheckError, fmt.Sprintf("Cannot resolve host [%s]: %v", u.Hostname(), err), hint)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		d.add("connection", models.TracingCheckError, fmt.Sprintf("Connection to [%s] timed out", hostPort(u)),
+			"Check the network policies and firewalls between Kiali and the backend")
+	case strings.Contains(err.Error(), "refused"):
+		d.add("connection", models.TracingCheckError, fmt.Sprintf("Connection to [%s] refused", hostPort(u)),
+			"Check the port of the URL: the HTTP query API of Jaeger listens on port 16686 by default")
+	default:
+		d.add("connection", models.TracingCheckError, fmt.Sprintf("Cannot connect to [%s]: %v", hostPort(u), err), "")
+	}
+	return false
+}
+
+func (d *tracingDiagnosis) checkTLS(u *url.URL) bool {
+	if u.Scheme != "https" {
+		d.add("tls", models.TracingCheckSkipped, "Plain HTTP", "")
+		return true
+	}
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: d.auth.InsecureSkipVerify}
+	if d.auth.CAFile != "" {
+		ca, err := ioutil.ReadFile(d.auth.CAFile)
+		if err != nil {
+			d.add("tls", models.TracingCheckError, fmt.Sprintf("Cannot read the CA file [%s]: %v", d.auth.CAFile, err),
+				"Mount the CA certificate in the Kiali pod, at the path of external_services.tracing.auth.ca_file")
+			return false
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			d.add("tls", models.TracingCheckError, fmt.Sprintf("The CA file [%s] holds no PEM certificate", d.auth.CAFile),
+				"Set external_services.tracing.auth.ca_file to a PEM encoded certificate")
+			return false
+		}
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: tracingDiagnoseTimeout}, "tcp", hostPort(u), tlsConfig)
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		var hostname x509.HostnameError
+		var invalid x509.CertificateInvalidError
+		switch {
+		case errors.As(err, &unknownAuthority):
+			d.add("tls", models.TracingCheckError, "The certificate of the backend is signed by an unknown authority: "+err.Error(),
+				"Set external_services.tracing.auth.ca_file to the CA signing the certificate of the backend, i.e. the service CA on OpenShift: /var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt")
+		case errors.As(err, &hostname):
+			d.add("tls", models.TracingCheckError, "The certificate of the backend is not valid for its host: "+err.Error(),
+				fmt.Sprintf("Set the host of the URL to one of the names of the certificate: %s", strings.Join(hostname.Certificate.DNSNames, ", ")))
+		case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+			d.add("tls", models.TracingCheckError, "The certificate of the backend is expired: "+err.Error(),
+				"Renew the certificate of the backend")
+		default:
+			d.add("tls", models.TracingCheckError, "TLS handshake failed: "+err.Error(),
+				"Check that the port of the URL serves TLS, or use an http:// URL")
+		}
+		return false
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		d.add("tls", models.TracingCheckOK, "TLS handshake succeeded", "")
+		return true
+	}
+	cert := certs[0]
+	now := util.Clock.Now()
+	switch {
+	case now.After(cert.NotAfter):
+		d.add("tls", models.TracingCheckError, fmt.Sprintf("The certificate of the backend expired on %s", cert.NotAfter.Format(time.RFC3339)),
+			"Renew the certificate of the backend")
+	case d.auth.InsecureSkipVerify:
+		d.add("tls", models.TracingCheckWarning, "The certificate of the backend is not verified",
+			"Set external_services.tracing.auth.ca_file to the CA signing the certificate of the backend, and insecure_skip_verify to false")
+	case cert.NotAfter.Sub(now) < tracingCertExpiryWarning:
+		d.add("tls", models.TracingCheckWarning, fmt.Sprintf("The certificate of the backend expires on %s", cert.NotAfter.Format(time.RFC3339)),
+			"Renew the certificate of the backend")
+	default:
+		d.add("tls", models.TracingCheckOK, fmt.Sprintf("The certificate of the backend is valid until %s", cert.NotAfter.Format(time.RFC3339)), "")
+	}
+	return true
+}
+
+type tracingProbe struct {
+	code   int
+	body   []byte
+	header http.Header
+	err    error
+}
+
+func (d *tracingDiagnosis) probe(client *http.Client, base *url.URL, apiPath string) tracingProbe {
+	u := *base
+	u.Path = path.Join(u.Path, apiPath)
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return tracingProbe{err: err}
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return tracingProbe{code: resp.StatusCode, body: body, header: resp.Header, err: err}
+}
+
+// hasJSONField tells whether a response is a JSON object with the given field
+func (p tracingProbe) hasJSONField(field string) bool {
+	if p.err != nil || p.code != http.StatusOK {
+		return false
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(p.body, &fields); err != nil {
+		return false
+	}
+	_, ok := fields[field]
+	return ok
+}
+
+func (d *tracingDiagnosis) checkAPI(u *url.URL, provider string) {
+	transport, err := httputil.AuthTransport(&d.auth, &http.Transport{})
+	if err != nil {
+		d.add("auth", models.TracingCheckError, err.Error(), "Check external_services.tracing.auth")
+		return
+	}
+	client := &http.Client{Transport: transport, Timeout: tracingDiagnoseTimeout}
+
+	jaegerProbe := d.probe(client, u, "/api/services")
+	otlpProbe := d.probe(client, u, "/api/v3/services")
+	if jaegerProbe.err != nil && strings.Contains(jaegerProbe.err.Error(), "malformed HTTP response") {
+		d.DetectedFlavor = tracingFlavorGRPC
+		d.add("api", models.TracingCheckError, "The URL serves gRPC, not HTTP",
+			"Set the port of the URL to the HTTP query port of the backend, i.e. 16686 for Jaeger rather than its gRPC port 16685")
+		return
+	}
+	if jaegerProbe.err != nil {
+		d.add("api", models.TracingCheckError, "Query failed: "+jaegerProbe.err.Error(), "")
+		return
+	}
+	d.checkClock(jaegerProbe.header)
+
+	if !d.checkAuth(jaegerProbe.code) {
+		return
+	}
+
+	supportsJaeger, supportsOTLP := jaegerProbe.hasJSONField("data"), otlpProbe.hasJSONField("services")
+	switch {
+	case supportsOTLP && (provider == config.TracingProviderOTLP || !supportsJaeger):
+		d.DetectedFlavor = tracingFlavorOTLP
+	case supportsJaeger:
+		d.DetectedFlavor = tracingFlavorJaeger
+	default:
+		if echo := d.probe(client, u, "/api/echo"); echo.err == nil && echo.code == http.StatusOK && strings.TrimSpace(string(echo.body)) == "echo" {
+			d.DetectedFlavor = tracingFlavorTempo
+			d.add("api", models.TracingCheckError, "The URL serves the API of Tempo, not a Jaeger query API",
+				"Set the URL to the Jaeger query frontend of Tempo (tempo-query, port 16686), and external_services.tracing.tempo.url to this URL for the TraceQL searches")
+			return
+		}
+		d.add("api", models.TracingCheckError, fmt.Sprintf("No tracing API found, the query of the services returned HTTP %d", jaegerProbe.code),
+			"Check the path of the URL: Jaeger may be served under a prefix, i.e. /jaeger, set by its QUERY_BASE_PATH")
+		return
+	}
+
+	switch {
+	case provider == config.TracingProviderOTLP && !supportsOTLP:
+		d.add("api", models.TracingCheckError, "The backend serves the Jaeger API but not the query API v3 of Jaeger v2",
+			"Set external_services.tracing.provider to jaeger")
+	case provider != config.TracingProviderOTLP && !supportsJaeger:
+		d.add("api", models.TracingCheckError, "The backend serves the query API v3 of Jaeger v2 only",
+			"Set external_services.tracing.provider to otlp")
+	default:
+		d.add("api", models.TracingCheckOK, fmt.Sprintf("The backend serves the %s API", d.DetectedFlavor), "")
+	}
+}
+
+func (d *tracingDiagnosis) checkAuth(code int) bool {
+	switch code {
+	case http.StatusUnauthorized:
+		hint := "The backend requires credentials: set external_services.tracing.auth.type to bearer or basic"
+		switch d.auth.Type {
+		case config.AuthTypeBearer:
+			hint = "The token was rejected: check external_services.tracing.auth.token, or set use_kiali_token to true to send the token of the user"
+			if d.auth.UseKialiToken {
+				hint = "The token of the user was rejected: the backend may expect the token of another issuer, set external_services.tracing.auth.token instead"
+			}
+		case config.AuthTypeBasic:
+			hint = "The credentials were rejected: check external_services.tracing.auth.username and password"
+		}
+		d.add("auth", models.TracingCheckError, "Unauthorized", hint)
+		return false
+	case http.StatusForbidden:
+		d.add("auth", models.TracingCheckError, "Forbidden", "The credentials are valid but not allowed to query the traces: grant the read access to the traces to the user or the token")
+		return false
+	}
+	if d.auth.Type == "" || d.auth.Type == config.AuthTypeNone {
+		d.add("auth", models.TracingCheckSkipped, "No credentials", "")
+	} else {
+		d.add("auth", models.TracingCheckOK, "Credentials accepted", "")
+	}
+	return true
+}
+
+func (d *tracingDiagnosis) checkClock(header http.Header) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		d.add("clock", models.TracingCheckSkipped, "The backend sent no date", "")
+		return
+	}
+	// The date has a precision of a second
+	skew := date.Sub(util.Clock.Now().Truncate(time.Second))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxTracingClockSkew {
+		d.add("clock", models.TracingCheckWarning, fmt.Sprintf("The clocks of Kiali and of the backend differ by %v", skew),
+			"Synchronize the clocks of the nodes of Kiali and of the backend (NTP): the traces are queried by time range")
+		return
+	}
+	d.add("clock", models.TracingCheckOK, fmt.Sprintf("The clocks of Kiali and of the backend differ by %v", skew), "")
+}
+
+func (d *tracingDiagnosis) checkTempo(rawURL string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		d.add("tempo", models.TracingCheckError, fmt.Sprintf("Invalid Tempo URL [%s]", rawURL), "Set external_services.tracing.tempo.url to the HTTP URL of Tempo, i.e. http://tempo.tempo:3200")
+		return
+	}
+	transport, err := httputil.AuthTransport(&d.auth, &http.Transport{})
+	if err != nil {
+		d.add("tempo", models.TracingCheckError, err.Error(), "Check external_services.tracing.auth")
+		return
+	}
+	echo := d.probe(&http.Client{Transport: transport, Timeout: tracingDiagnoseTimeout}, u, "/api/echo")
+	switch {
+	case echo.err != nil:
+		d.add("tempo", models.TracingCheckError, "Query of Tempo failed: "+echo.err.Error(), "Check external_services.tracing.tempo.url, the HTTP port of Tempo is 3200 by default")
+	case echo.code != http.StatusOK || strings.TrimSpace(string(echo.body)) != "echo":
+		d.add("tempo", models.TracingCheckError, fmt.Sprintf("The Tempo URL doesn't serve the API of Tempo, the echo returned HTTP %d", echo.code),
+			"Set external_services.tracing.tempo.url to the HTTP URL of Tempo, not of its Jaeger query frontend")
+	default:
+		d.add("tempo", models.TracingCheckOK, "The Tempo URL serves the API of Tempo, TraceQL searches are available", "")
+	}
+}
@@ -0,0 +1,170 @@
+package business
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func setupTracingDiagnose(url string) *config.Config {
+	conf := config.NewConfig()
+	conf.InCluster = true
+	conf.ExternalServices.Tracing.InClusterURL = url
+	config.Set(conf)
+	util.Clock = util.RealClock{}
+	return conf
+}
+
+func tracingCheck(d models.TracingDiagnosis, name string) models.TracingCheck {
+	for _, check := range d.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	return models.TracingCheck{}
+}
+
+func jaegerAPIHandler(v3 bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/services":
+			_, _ = w.Write([]byte(`{"data":["productpage.bookinfo"]}`))
+		case r.URL.Path == "/api/v3/services" && v3:
+			_, _ = w.Write([]byte(`{"services":["productpage.bookinfo"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestDiagnoseTracingJaeger(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewServer(jaegerAPIHandler(false))
+	defer ts.Close()
+	setupTracingDiagnose(ts.URL)
+
+	d := DiagnoseTracing("")
+	assert.Equal("jaeger", d.DetectedFlavor)
+	for _, check := range d.Checks {
+		assert.NotEqual(models.TracingCheckError, check.Status, check.Name)
+	}
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "connection").Status)
+	assert.Equal(models.TracingCheckSkipped, tracingCheck(d, "tls").Status)
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "api").Status)
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "clock").Status)
+
+	// Clock skew
+	util.Clock = util.ClockMock{Time: time.Now().Add(-time.Hour)}
+	d = DiagnoseTracing("")
+	assert.Equal(models.TracingCheckWarning, tracingCheck(d, "clock").Status)
+	assert.NotEmpty(tracingCheck(d, "clock").Hint)
+}
+
+func TestDiagnoseTracingProvider(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewServer(jaegerAPIHandler(false))
+	defer ts.Close()
+	conf := setupTracingDiagnose(ts.URL)
+	conf.ExternalServices.Tracing.Provider = config.TracingProviderOTLP
+	config.Set(conf)
+
+	d := DiagnoseTracing("")
+	assert.Equal("jaeger", d.DetectedFlavor)
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "api").Status)
+	assert.Contains(tracingCheck(d, "api").Hint, "provider to jaeger")
+
+	ts2 := httptest.NewServer(jaegerAPIHandler(true))
+	defer ts2.Close()
+	conf.ExternalServices.Tracing.InClusterURL = ts2.URL
+	config.Set(conf)
+	d = DiagnoseTracing("")
+	assert.Equal("otlp", d.DetectedFlavor)
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "api").Status)
+}
+
+func TestDiagnoseTracingTempo(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/echo" {
+			_, _ = w.Write([]byte("echo"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	setupTracingDiagnose(ts.URL)
+
+	d := DiagnoseTracing("")
+	assert.Equal("tempo", d.DetectedFlavor)
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "api").Status)
+	assert.Contains(tracingCheck(d, "api").Hint, "tempo.url")
+}
+
+func TestDiagnoseTracingAuth(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		jaegerAPIHandler(false)(w, r)
+	}))
+	defer ts.Close()
+	conf := setupTracingDiagnose(ts.URL)
+
+	d := DiagnoseTracing("")
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "auth").Status)
+	assert.Contains(tracingCheck(d, "auth").Hint, "auth.type")
+
+	conf.ExternalServices.Tracing.Auth = config.Auth{Type: config.AuthTypeBearer, UseKialiToken: true}
+	config.Set(conf)
+	d = DiagnoseTracing("bad")
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "auth").Status)
+	assert.Contains(tracingCheck(d, "auth").Hint, "token of the user")
+
+	d = DiagnoseTracing("good")
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "auth").Status)
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "api").Status)
+}
+
+func TestDiagnoseTracingTLS(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewTLSServer(jaegerAPIHandler(false))
+	defer ts.Close()
+	conf := setupTracingDiagnose(ts.URL)
+
+	d := DiagnoseTracing("")
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "tls").Status)
+	assert.Contains(tracingCheck(d, "tls").Hint, "ca_file")
+	assert.Equal(models.TracingCheck{}, tracingCheck(d, "api"))
+
+	conf.ExternalServices.Tracing.Auth = config.Auth{Type: config.AuthTypeNone, InsecureSkipVerify: true}
+	config.Set(conf)
+	d = DiagnoseTracing("")
+	assert.Equal(models.TracingCheckWarning, tracingCheck(d, "tls").Status)
+	assert.Equal(models.TracingCheckOK, tracingCheck(d, "api").Status)
+}
+
+func TestDiagnoseTracingConnection(t *testing.T) {
+	assert := assert.New(t)
+	ts := httptest.NewServer(jaegerAPIHandler(false))
+	url := ts.URL
+	ts.Close()
+	setupTracingDiagnose(url)
+
+	d := DiagnoseTracing("")
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "connection").Status)
+	assert.Contains(tracingCheck(d, "connection").Hint, "port")
+
+	setupTracingDiagnose("tracing.istio-system/jaeger")
+	d = DiagnoseTracing("")
+	assert.Equal(models.TracingCheckError, tracingCheck(d, "url").Status)
+	assert.Len(d.Checks, 1)
+}
@@ -0,0 +1,236 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const defaultMirroringReportWindow = "10m"
+
+// Classes of the http statuses compared between the primary and the mirror destinations
+var mirroringResponseClasses = []string{"2xx", "3xx", "4xx", "5xx"}
+
+// ApplyTrafficMirroring mirrors a percentage of the requests of the http routes of a service to a shadow destination.
+// The responses of the shadow destination are discarded by the proxies: it can be tested with the live traffic.
+func (in *WizardService) ApplyTrafficMirroring(namespace, service string, request models.TrafficMirroringRequest) (*models.WizardChange, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "ApplyTrafficMirroring")
+	defer promtimer.ObserveNow(&err)
+
+	ttl, err := parseWizardTTL(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+	if request.Host == "" {
+		request.Host = service
+	}
+	if request.Host == service && request.Subset == "" {
+		err = errors.NewBadRequest("a subset is required to mirror the traffic of a service to itself")
+		return nil, err
+	}
+	if request.Percentage == 0 {
+		request.Percentage = 100
+	}
+	if request.Percentage < 0 || request.Percentage > 100 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid percentage %v, expected a value between 0 and 100", request.Percentage))
+		return nil, err
+	}
+	if _, err = in.k8s.GetService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{
+		"hosts": []interface{}{service},
+		"http":  []interface{}{map[string]interface{}{"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": service}}}}},
+	}
+	vs, err := in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, service)
+	if err == nil {
+		// Copied, so that the spec of the cached object is left as is
+		raw, _ := json.Marshal(vs.GetSpec())
+		spec = map[string]interface{}{}
+		if err = json.Unmarshal(raw, &spec); err != nil {
+			return nil, err
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	mirror := map[string]interface{}{"host": request.Host}
+	if request.Subset != "" {
+		mirror["subset"] = request.Subset
+	}
+	mirrored := false
+	httpRoutes, _ := spec["http"].([]interface{})
+	for _, h := range httpRoutes {
+		httpRoute, _ := h.(map[string]interface{})
+		if httpRoute == nil || (request.Route != "" && httpRoute["name"] != request.Route) {
+			continue
+		}
+		httpRoute["mirror"] = mirror
+		httpRoute["mirrorPercentage"] = map[string]interface{}{"value": request.Percentage}
+		mirrored = true
+	}
+	if !mirrored && request.Route == "" {
+		err = errors.NewBadRequest(fmt.Sprintf("VirtualService %s has no http routes", service))
+		return nil, err
+	}
+	if !mirrored {
+		err = errors.NewBadRequest(fmt.Sprintf("no http route of VirtualService %s is named %s", service, request.Route))
+		return nil, err
+	}
+
+	var change *models.WizardChange
+	change, err = in.applyWizardObjects(namespace, service, models.WizardTrafficMirroring, ttl, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: service, spec: spec},
+	})
+	return change, err
+}
+
+// GetTrafficMirroringReport compares the response codes and latencies of the primary destination of a service to
+// those of the shadow destination its traffic is mirrored to, as reported by their workloads over a window
+func (in *WizardService) GetTrafficMirroringReport(namespace, service, window string) (*models.TrafficMirroringReport, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "GetTrafficMirroringReport")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultMirroringReportWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 10m", window))
+		return nil, err
+	}
+	vs, err := in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, service)
+	if err != nil {
+		return nil, err
+	}
+	mirror, percentage, ok := virtualServiceMirror(vs.GetSpec())
+	if !ok {
+		err = kubernetes.NewNotFound(service, "kiali.io", "traffic mirrorings")
+		return nil, err
+	}
+
+	report := &models.TrafficMirroringReport{Namespace: namespace, Service: service, Window: window, Percentage: percentage}
+	report.Primary.Host = service
+	report.Mirror.Host, _ = mirror["host"].(string)
+	report.Mirror.Subset, _ = mirror["subset"].(string)
+
+	mirrorService, mirrorNamespace := mirrorServiceName(report.Mirror.Host, namespace)
+	mirrorVersion := ""
+	if report.Mirror.Subset != "" {
+		mirrorVersion = report.Mirror.Subset
+		if dr, drErr := in.k8s.GetIstioObject(mirrorNamespace, kubernetes.DestinationRules, mirrorService); drErr == nil {
+			mirrorVersion = subsetVersion(dr.GetSpec(), report.Mirror.Subset)
+		}
+	}
+
+	primaryLabels := NewMetricsLabelsBuilder("inbound").SelfReporter().Service(service, namespace)
+	mirrorLabels := NewMetricsLabelsBuilder("inbound").SelfReporter().Service(mirrorService, mirrorNamespace)
+	if mirrorVersion != "" {
+		mirrorLabels.Add("destination_version", mirrorVersion)
+		if mirrorService == service && mirrorNamespace == namespace {
+			// The requests of the subset mirrored to are the mirrored requests
+			primaryLabels.Exclude("destination_version", mirrorVersion)
+		}
+	}
+
+	queryTime := util.Clock.Now()
+	if err = in.fetchMirroringStats(&report.Primary, primaryLabels, window, queryTime); err != nil {
+		return nil, err
+	}
+	if err = in.fetchMirroringStats(&report.Mirror, mirrorLabels, window, queryTime); err != nil {
+		return nil, err
+	}
+	if report.Primary.ErrorRate != nil && report.Mirror.ErrorRate != nil {
+		delta := *report.Mirror.ErrorRate - *report.Primary.ErrorRate
+		report.ErrorRateDelta = &delta
+	}
+	if report.Primary.P99Latency != nil && report.Mirror.P99Latency != nil {
+		delta := *report.Mirror.P99Latency - *report.Primary.P99Latency
+		report.P99LatencyDelta = &delta
+	}
+	return report, nil
+}
+
+// fetchMirroringStats sets the percentages of the response classes and the latencies of a destination
+func (in *WizardService) fetchMirroringStats(stats *models.MirroringDestinationStats, lb *MetricsLabelsBuilder, window string, queryTime time.Time) error {
+	labels := lb.Build()
+	total := "istio_requests_total" + labels
+	responseCodes := map[string]float64{}
+	for _, class := range mirroringResponseClasses {
+		part := fmt.Sprintf(`istio_requests_total%s,response_code=~"%s.."}`, strings.TrimSuffix(labels, "}"), class[:1])
+		ratio, hasData, err := in.prom.FetchRateRatio([]string{part}, total, window, queryTime)
+		if err != nil {
+			return err
+		}
+		if !hasData {
+			return nil
+		}
+		responseCodes[class] = ratio * 100
+	}
+	stats.HasTraffic = true
+	stats.ResponseCodes = responseCodes
+
+	errorRate, _, err := in.prom.FetchRateRatio(metricSelectors("istio_requests_total", lb.BuildForServerErrors()), total, window, queryTime)
+	if err != nil {
+		return err
+	}
+	errorRate *= 100
+	stats.ErrorRate = &errorRate
+
+	histogram, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", window, false, []string{"0.5", "0.99"}, queryTime)
+	if err != nil {
+		return err
+	}
+	for quantile, latency := range map[string]**float64{"0.5": &stats.P50Latency, "0.99": &stats.P99Latency} {
+		if vector := histogram[quantile]; len(vector) > 0 && !math.IsNaN(float64(vector[0].Value)) {
+			value := float64(vector[0].Value)
+			*latency = &value
+		}
+	}
+	return nil
+}
+
+// virtualServiceMirror returns the mirror of the first http route of a VirtualService spec mirroring its traffic,
+// with the percentage of the requests mirrored
+func virtualServiceMirror(spec map[string]interface{}) (map[string]interface{}, float64, bool) {
+	httpRoutes, _ := spec["http"].([]interface{})
+	for _, h := range httpRoutes {
+		httpRoute, _ := h.(map[string]interface{})
+		mirror, ok := httpRoute["mirror"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		percentage := 100.0
+		if p, ok := httpRoute["mirrorPercentage"].(map[string]interface{}); ok {
+			percentage = toFloat(p["value"])
+		} else if p, ok := httpRoute["mirrorPercent"]; ok {
+			percentage = toFloat(p)
+		}
+		return mirror, percentage, true
+	}
+	return nil, 0, false
+}
+
+// mirrorServiceName returns the service and namespace of the host of a mirror
+func mirrorServiceName(host, namespace string) (string, string) {
+	parsed := kubernetes.ParseHost(host, namespace, "")
+	if parsed.CompleteInput {
+		return parsed.Service, parsed.Namespace
+	}
+	if parts := strings.Split(host, "."); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return host, namespace
+}
@@ -0,0 +1,108 @@
+package business
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func TestApplyTrafficMirroring(t *testing.T) {
+	assert := assert.New(t)
+	setupWizardChangesMock(new(kubetest.K8SClientMock))
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(fakeCanaryVirtualService(), nil)
+	var patch string
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { patch = args.String(4) }).Return(&kubernetes.GenericIstioObject{}, nil)
+	svc := WizardService{k8s: k8s}
+
+	change, err := svc.ApplyTrafficMirroring("bookinfo", "reviews", models.TrafficMirroringRequest{Subset: "v3", Percentage: 25})
+	assert.NoError(err)
+	assert.Nil(change.ExpiresAt)
+	assert.Contains(patch, `"mirror":{"host":"reviews","subset":"v3"},"mirrorPercentage":{"value":25}`)
+
+	_, err = svc.ApplyTrafficMirroring("bookinfo", "reviews", models.TrafficMirroringRequest{})
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.ApplyTrafficMirroring("bookinfo", "reviews", models.TrafficMirroringRequest{Host: "reviews-shadow", Percentage: 120})
+	assert.True(errors.IsBadRequest(err))
+
+	k8s = new(kubetest.K8SClientMock)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(&kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+			"tcp":   []interface{}{map[string]interface{}{"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "reviews"}}}}},
+		},
+	}, nil)
+	svc = WizardService{k8s: k8s}
+	_, err = svc.ApplyTrafficMirroring("bookinfo", "reviews", models.TrafficMirroringRequest{Host: "reviews-shadow"})
+	assert.True(errors.IsBadRequest(err))
+	assert.Contains(err.Error(), "VirtualService reviews has no http routes")
+}
+
+func TestGetTrafficMirroringReport(t *testing.T) {
+	assert := assert.New(t)
+	setupWizardChangesMock(new(kubetest.K8SClientMock))
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews"},
+		Spec: map[string]interface{}{"http": []interface{}{map[string]interface{}{
+			"route":            []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}}},
+			"mirror":           map[string]interface{}{"host": "reviews", "subset": "shadow"},
+			"mirrorPercentage": map[string]interface{}{"value": float64(50)},
+		}}},
+	}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(&kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{"subsets": []interface{}{map[string]interface{}{"name": "shadow", "labels": map[string]interface{}{"version": "v3"}}}},
+	}, nil)
+
+	primary := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo",destination_version!="v3"}`
+	mirror := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo",destination_version="v3"}`
+	class := func(labels, class string) []string {
+		return []string{"istio_requests_total" + strings.TrimSuffix(labels, "}") + `,response_code=~"` + class + `.."}`}
+	}
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateRatio", class(primary, "2"), "istio_requests_total"+primary, "10m", mock.Anything).Return(0.99, true, nil)
+	prom.On("FetchRateRatio", class(primary, "5"), "istio_requests_total"+primary, "10m", mock.Anything).Return(0.01, true, nil)
+	prom.On("FetchRateRatio", class(mirror, "2"), "istio_requests_total"+mirror, "10m", mock.Anything).Return(0.9, true, nil)
+	prom.On("FetchRateRatio", class(mirror, "5"), "istio_requests_total"+mirror, "10m", mock.Anything).Return(0.1, true, nil)
+	prom.On("FetchRateRatio", mock.Anything, "istio_requests_total"+primary, "10m", mock.Anything).Return(0.01, true, nil)
+	prom.On("FetchRateRatio", mock.Anything, "istio_requests_total"+mirror, "10m", mock.Anything).Return(0.0, true, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", primary, "", "10m", false, []string{"0.5", "0.99"}, mock.Anything).
+		Return(map[string]pmod.Vector{"0.5": {&pmod.Sample{Value: 20}}, "0.99": {&pmod.Sample{Value: 100}}}, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", mirror, "", "10m", false, []string{"0.5", "0.99"}, mock.Anything).
+		Return(map[string]pmod.Vector{"0.5": {&pmod.Sample{Value: 25}}, "0.99": {&pmod.Sample{Value: 160}}}, nil)
+	svc := WizardService{k8s: k8s, prom: prom}
+
+	report, err := svc.GetTrafficMirroringReport("bookinfo", "reviews", "")
+	assert.NoError(err)
+	assert.Equal(50.0, report.Percentage)
+	assert.Equal("shadow", report.Mirror.Subset)
+	assert.True(report.Mirror.HasTraffic)
+	assert.Equal(map[string]float64{"2xx": 99, "3xx": 1, "4xx": 1, "5xx": 1}, report.Primary.ResponseCodes)
+	assert.InDelta(10, report.Mirror.ResponseCodes["5xx"], 0.0001)
+	assert.InDelta(1, *report.Primary.ErrorRate, 0.0001)
+	assert.InDelta(-1, *report.ErrorRateDelta, 0.0001)
+	assert.Equal(60.0, *report.P99LatencyDelta)
+
+	_, err = svc.GetTrafficMirroringReport("bookinfo", "reviews", "recently")
+	assert.True(errors.IsBadRequest(err))
+}
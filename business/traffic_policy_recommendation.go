@@ -0,0 +1,231 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	pmod "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultTrafficPolicyWindow = "1h"
+	// Smallest connection pool recommended, so that a service with little traffic can take a burst
+	minConnectionPoolSize = 16
+	// Ratio of the recommended connection pool to the peak concurrency
+	connectionPoolHeadroom = 2
+	// Server error rate, in percent, above which the errors are considered widespread: ejecting hosts would then
+	// lower the capacity of the service without routing around the errors
+	widespreadErrorRate = 5.0
+)
+
+// GetTrafficPolicyRecommendation observes the concurrency, latency, errors, pool overflows and ejections of a
+// service over a window, and recommends the connection pool and outlier detection of the traffic policy of its
+// DestinationRule, with a patch applying them
+func (in *WizardService) GetTrafficPolicyRecommendation(namespace, service, window string) (*models.TrafficPolicyRecommendation, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "GetTrafficPolicyRecommendation")
+	defer promtimer.ObserveNow(&err)
+
+	if window == "" {
+		window = defaultTrafficPolicyWindow
+	}
+	if _, err = pmod.ParseDuration(window); err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid window [%s], expected a duration such as 1h", window))
+		return nil, err
+	}
+	if _, err = in.k8s.GetService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	recommendation := &models.TrafficPolicyRecommendation{Namespace: namespace, Service: service, Window: window, DestinationRule: service, Findings: []string{}}
+	current := map[string]interface{}{}
+	dr, err := in.k8s.GetIstioObject(namespace, kubernetes.DestinationRules, service)
+	if err == nil {
+		if policy, ok := dr.GetSpec()["trafficPolicy"].(map[string]interface{}); ok {
+			current = policy
+			recommendation.Current = policy
+		}
+	} else if errors.IsNotFound(err) {
+		recommendation.Create = true
+	} else {
+		return nil, err
+	}
+
+	if recommendation.Observed, recommendation.HasTraffic, err = in.observeTraffic(namespace, service, window); err != nil {
+		return nil, err
+	}
+	if !recommendation.HasTraffic {
+		recommendation.Findings = append(recommendation.Findings, fmt.Sprintf("The service received no request over the last %s", window))
+		return recommendation, nil
+	}
+
+	policy, findings := recommendTrafficPolicy(current, recommendation.Observed)
+	recommendation.Recommended = policy
+	recommendation.Findings = append(recommendation.Findings, findings...)
+
+	var patch []byte
+	if recommendation.Create {
+		patch, _ = json.Marshal(map[string]interface{}{
+			"apiVersion": kubernetes.ApiNetworkingVersion,
+			"kind":       kubernetes.PluralType[kubernetes.DestinationRules],
+			"metadata":   map[string]interface{}{"name": service, "namespace": namespace},
+			"spec":       map[string]interface{}{"host": service, "trafficPolicy": policy},
+		})
+	} else {
+		patch, _ = json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"trafficPolicy": policy}})
+	}
+	recommendation.Patch = string(patch)
+	return recommendation, nil
+}
+
+// observeTraffic fetches the traffic of a service over a window. The stats of the connection pools and outlier
+// detection are reported by the clients of the service, when their proxies expose them.
+func (in *WizardService) observeTraffic(namespace, service, window string) (models.ObservedTraffic, bool, error) {
+	observed := models.ObservedTraffic{}
+	queryTime := util.Clock.Now()
+	lb := NewMetricsLabelsBuilder("inbound").SelfReporter().Service(service, namespace)
+	labels := lb.Build()
+
+	rate, hasData, err := in.prom.FetchRate("istio_requests_total"+labels, window, queryTime)
+	if err != nil || !hasData || rate == 0 {
+		return observed, false, err
+	}
+	observed.RequestRate = rate
+
+	histogram, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", window, true, []string{"0.99"}, queryTime)
+	if err != nil {
+		return observed, false, err
+	}
+	for quantile, latency := range map[string]**float64{"avg": &observed.AvgLatency, "0.99": &observed.P99Latency} {
+		if vector := histogram[quantile]; len(vector) > 0 && !math.IsNaN(float64(vector[0].Value)) {
+			value := float64(vector[0].Value)
+			*latency = &value
+		}
+	}
+	if observed.AvgLatency != nil {
+		observed.Concurrency = rate * *observed.AvgLatency / 1000
+	}
+	if observed.P99Latency != nil {
+		observed.PeakConcurrency = rate * *observed.P99Latency / 1000
+	}
+
+	if ratio, hasData, err := in.prom.FetchRateRatio(metricSelectors("istio_requests_total", lb.BuildForServerErrors()), "istio_requests_total"+labels, window, queryTime); err != nil {
+		return observed, false, err
+	} else if hasData {
+		errorRate := ratio * 100
+		observed.ErrorRate = &errorRate
+	}
+
+	// Example: outbound|9080||reviews.bookinfo.svc.cluster.local
+	fqdn := fmt.Sprintf("%s.%s.%s", service, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain)
+	clusters := fmt.Sprintf(`{cluster_name=~"outbound\\|[0-9]+\\|[^|]*\\|%s"}`, strings.ReplaceAll(regexp.QuoteMeta(fqdn), `\`, `\\`))
+	for metric, stat := range map[string]**float64{
+		"envoy_cluster_upstream_rq_pending_overflow":               &observed.PendingOverflowRate,
+		"envoy_cluster_outlier_detection_ejections_enforced_total": &observed.EjectionRate,
+	} {
+		value, hasData, err := in.prom.FetchRate(metric+clusters, window, queryTime)
+		if err != nil {
+			return observed, false, err
+		}
+		if hasData {
+			*stat = &value
+		}
+	}
+	return observed, true, nil
+}
+
+// recommendTrafficPolicy returns the traffic policy with the recommended connection pool and outlier detection,
+// keeping the other fields of the current policy, with the reasons of the changes
+func recommendTrafficPolicy(current map[string]interface{}, observed models.ObservedTraffic) (map[string]interface{}, []string) {
+	findings := []string{}
+	policy := map[string]interface{}{}
+	for k, v := range current {
+		policy[k] = v
+	}
+	overflow := observed.PendingOverflowRate != nil && *observed.PendingOverflowRate > 0
+	if overflow {
+		findings = append(findings, fmt.Sprintf("%.2f requests per second overflowed the connection pool of the clients", *observed.PendingOverflowRate))
+	}
+
+	size := int(math.Ceil(observed.PeakConcurrency * connectionPoolHeadroom))
+	if size < minConnectionPoolSize {
+		size = minConnectionPoolSize
+	}
+	connectionPool := map[string]interface{}{}
+	currentPool, _ := current["connectionPool"].(map[string]interface{})
+	for _, limit := range []struct{ protocol, name string }{
+		{"tcp", "maxConnections"},
+		{"http", "http1MaxPendingRequests"},
+		{"http", "http2MaxRequests"},
+	} {
+		currentProtocol, _ := currentPool[limit.protocol].(map[string]interface{})
+		configured := int(toFloat(currentProtocol[limit.name]))
+		recommended := size
+		switch {
+		case configured == 0:
+		case overflow:
+			if configured*2 > recommended {
+				recommended = configured * 2
+			}
+		case configured >= recommended:
+			recommended = configured
+		default:
+			findings = append(findings, fmt.Sprintf("The %s of %d is under twice the peak concurrency of %.1f requests", limit.name, configured, observed.PeakConcurrency))
+		}
+		protocol, ok := connectionPool[limit.protocol].(map[string]interface{})
+		if !ok {
+			protocol = map[string]interface{}{}
+			for k, v := range currentProtocol {
+				protocol[k] = v
+			}
+			connectionPool[limit.protocol] = protocol
+		}
+		protocol[limit.name] = recommended
+	}
+	for k, v := range currentPool {
+		if _, ok := connectionPool[k]; !ok {
+			connectionPool[k] = v
+		}
+	}
+	policy["connectionPool"] = connectionPool
+
+	outlierDetection := map[string]interface{}{"consecutive5xxErrors": 5, "interval": "10s", "baseEjectionTime": "30s", "maxEjectionPercent": 50}
+	currentOutlier, ok := current["outlierDetection"].(map[string]interface{})
+	if !ok {
+		findings = append(findings, "No outlier detection: the unhealthy hosts keep receiving requests")
+	} else {
+		for k, v := range currentOutlier {
+			outlierDetection[k] = v
+		}
+		errorRate := 0.0
+		if observed.ErrorRate != nil {
+			errorRate = *observed.ErrorRate
+		}
+		if observed.EjectionRate != nil && *observed.EjectionRate > 0 && errorRate < 1 {
+			consecutive := int(toFloat(outlierDetection["consecutive5xxErrors"]))
+			if consecutive < 5 {
+				consecutive = 5
+			} else {
+				consecutive *= 2
+			}
+			outlierDetection["consecutive5xxErrors"] = consecutive
+			findings = append(findings, fmt.Sprintf("Hosts were ejected with a server error rate of %.2f%%: the ejections are too eager", errorRate))
+		}
+		if toFloat(outlierDetection["maxEjectionPercent"]) > 50 && errorRate >= widespreadErrorRate {
+			outlierDetection["maxEjectionPercent"] = 50
+			findings = append(findings, fmt.Sprintf("The server error rate of %.2f%% is widespread: ejecting most hosts would lower the capacity of the service", errorRate))
+		}
+	}
+	policy["outlierDetection"] = outlierDetection
+	return policy, findings
+}
@@ -0,0 +1,78 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGetTrafficPolicyRecommendation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(&kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{"host": "reviews", "trafficPolicy": map[string]interface{}{
+			"tls":            map[string]interface{}{"mode": "ISTIO_MUTUAL"},
+			"connectionPool": map[string]interface{}{"tcp": map[string]interface{}{"maxConnections": float64(10), "connectTimeout": "1s"}},
+		}},
+	}, nil)
+
+	labels := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"}`
+	clusters := `{cluster_name=~"outbound\\|[0-9]+\\|[^|]*\\|reviews\\.bookinfo\\.svc\\.cluster\\.local"}`
+	prom := new(prometheustest.PromClientMock)
+	// 100 requests per second of 200ms at p99: 20 requests in flight
+	prom.On("FetchRate", "istio_requests_total"+labels, "1h", mock.Anything).Return(100.0, true, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "", "1h", true, []string{"0.99"}, mock.Anything).
+		Return(map[string]pmod.Vector{"avg": {&pmod.Sample{Value: 50}}, "0.99": {&pmod.Sample{Value: 200}}}, nil)
+	prom.On("FetchRateRatio", mock.Anything, "istio_requests_total"+labels, "1h", mock.Anything).Return(0.001, true, nil)
+	prom.On("FetchRate", "envoy_cluster_upstream_rq_pending_overflow"+clusters, "1h", mock.Anything).Return(0.5, true, nil)
+	prom.On("FetchRate", "envoy_cluster_outlier_detection_ejections_enforced_total"+clusters, "1h", mock.Anything).Return(0.0, false, nil)
+	svc := WizardService{k8s: k8s, prom: prom}
+
+	recommendation, err := svc.GetTrafficPolicyRecommendation("bookinfo", "reviews", "")
+	assert.NoError(err)
+	assert.True(recommendation.HasTraffic)
+	assert.False(recommendation.Create)
+	assert.Equal(5.0, recommendation.Observed.Concurrency)
+	assert.Equal(20.0, recommendation.Observed.PeakConcurrency)
+	assert.Nil(recommendation.Observed.EjectionRate)
+	assert.Len(recommendation.Findings, 2)
+	assert.JSONEq(`{"spec":{"trafficPolicy":{
+		"tls": {"mode": "ISTIO_MUTUAL"},
+		"connectionPool": {"tcp": {"maxConnections": 40, "connectTimeout": "1s"}, "http": {"http1MaxPendingRequests": 40, "http2MaxRequests": 40}},
+		"outlierDetection": {"consecutive5xxErrors": 5, "interval": "10s", "baseEjectionTime": "30s", "maxEjectionPercent": 50}
+	}}}`, recommendation.Patch)
+}
+
+func TestRecommendOutlierDetection(t *testing.T) {
+	assert := assert.New(t)
+
+	ejections, errorRate := 0.2, 0.1
+	current := map[string]interface{}{"outlierDetection": map[string]interface{}{"consecutive5xxErrors": float64(5), "maxEjectionPercent": float64(100)}}
+	policy, findings := recommendTrafficPolicy(current, models.ObservedTraffic{PeakConcurrency: 1, EjectionRate: &ejections, ErrorRate: &errorRate})
+	outlier := policy["outlierDetection"].(map[string]interface{})
+	assert.Equal(10, outlier["consecutive5xxErrors"])
+	assert.Equal(float64(100), outlier["maxEjectionPercent"])
+	assert.Len(findings, 1)
+
+	errorRate = 20
+	policy, _ = recommendTrafficPolicy(current, models.ObservedTraffic{PeakConcurrency: 1, ErrorRate: &errorRate})
+	outlier = policy["outlierDetection"].(map[string]interface{})
+	assert.Equal(float64(5), outlier["consecutive5xxErrors"])
+	assert.Equal(50, outlier["maxEjectionPercent"])
+	assert.Equal(16, policy["connectionPool"].(map[string]interface{})["tcp"].(map[string]interface{})["maxConnections"])
+}
@@ -0,0 +1,92 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	userPreferencesConfigMapName = "kiali-user-preferences"
+	userPreferencesConfigMapKey  = "preferences"
+)
+
+// UserPreferencesService deals with the preferences of the users, stored by user name
+type UserPreferencesService struct{}
+
+// GetUserPreferences returns the preferences of the given user, empty when the user never saved them
+func (in *UserPreferencesService) GetUserPreferences(user string) (*models.UserPreferences, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "UserPreferencesService", "GetUserPreferences")
+	defer promtimer.ObserveNow(&err)
+
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := readKialiConfigMap(k8s, userPreferencesConfigMapName, userPreferencesConfigMapKey)
+	if err != nil {
+		return nil, err
+	}
+	var preferences map[string]models.UserPreferences
+	if preferences, err = parseUserPreferences(data); err != nil {
+		return nil, err
+	}
+	userPreferences, ok := preferences[user]
+	if !ok {
+		userPreferences = models.UserPreferences{DefaultNamespaces: []string{}, HiddenColumns: map[string][]string{}}
+	}
+	return &userPreferences, nil
+}
+
+// UpdateUserPreferences replaces the preferences of the given user
+func (in *UserPreferencesService) UpdateUserPreferences(user string, userPreferences models.UserPreferences) (*models.UserPreferences, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "UserPreferencesService", "UpdateUserPreferences")
+	defer promtimer.ObserveNow(&err)
+
+	switch {
+	case userPreferences.RefreshInterval < 0:
+		err = k8s_errors.NewBadRequest("the refresh interval cannot be negative")
+	case userPreferences.Duration < 0:
+		err = k8s_errors.NewBadRequest("the duration cannot be negative")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userPreferences.DefaultNamespaces == nil {
+		userPreferences.DefaultNamespaces = []string{}
+	}
+	if userPreferences.HiddenColumns == nil {
+		userPreferences.HiddenColumns = map[string][]string{}
+	}
+
+	err = updateKialiConfigMap(userPreferencesConfigMapName, userPreferencesConfigMapKey, func(data string) (string, error) {
+		preferences, err := parseUserPreferences(data)
+		if err != nil {
+			return "", err
+		}
+		preferences[user] = userPreferences
+		rawPreferences, err := json.Marshal(preferences)
+		return string(rawPreferences), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userPreferences, nil
+}
+
+func parseUserPreferences(data string) (map[string]models.UserPreferences, error) {
+	preferences := map[string]models.UserPreferences{}
+	if data == "" {
+		return preferences, nil
+	}
+	if err := json.Unmarshal([]byte(data), &preferences); err != nil {
+		return nil, fmt.Errorf("cannot parse the preferences of the users in ConfigMap [%s]: %v", userPreferencesConfigMapName, err)
+	}
+	return preferences, nil
+}
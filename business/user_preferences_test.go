@@ -0,0 +1,55 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestUserPreferencesByUser(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	stored := setupKialiConfigMapMock(userPreferencesConfigMapName)
+	service := UserPreferencesService{}
+
+	preferences, err := service.GetUserPreferences("alice")
+	require.NoError(err)
+	assert.Empty(preferences.DefaultNamespaces)
+	assert.NotNil(preferences.HiddenColumns)
+
+	_, err = service.UpdateUserPreferences("alice", models.UserPreferences{
+		DefaultNamespaces: []string{"bookinfo"},
+		RefreshInterval:   15000,
+		HiddenColumns:     map[string][]string{"workloads": {"labels"}},
+	})
+	require.NoError(err)
+	_, err = service.UpdateUserPreferences("bob", models.UserPreferences{Duration: 3600})
+	require.NoError(err)
+	assert.Contains(stored.Data[userPreferencesConfigMapKey], "alice")
+	assert.Contains(stored.Data[userPreferencesConfigMapKey], "bob")
+
+	preferences, err = service.GetUserPreferences("alice")
+	require.NoError(err)
+	assert.Equal([]string{"bookinfo"}, preferences.DefaultNamespaces)
+	assert.Equal(int64(15000), preferences.RefreshInterval)
+	assert.Equal([]string{"labels"}, preferences.HiddenColumns["workloads"])
+
+	preferences, err = service.GetUserPreferences("bob")
+	require.NoError(err)
+	assert.Equal(int64(3600), preferences.Duration)
+	assert.Empty(preferences.DefaultNamespaces)
+}
+
+func TestUserPreferencesValidation(t *testing.T) {
+	setupKialiConfigMapMock(userPreferencesConfigMapName)
+	service := UserPreferencesService{}
+
+	_, err := service.UpdateUserPreferences("alice", models.UserPreferences{RefreshInterval: -1})
+	assert.True(t, errors.IsBadRequest(err))
+	_, err = service.UpdateUserPreferences("alice", models.UserPreferences{Duration: -1})
+	assert.True(t, errors.IsBadRequest(err))
+}
@@ -0,0 +1,515 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Label set on the Istio objects generated by the wizards, with the name of the wizard
+const wizardLabel = "kiali_wizard"
+
+// WizardService generates and applies the Istio config of the wizards of a service. A change can expire: it is then
+// reverted unless confirmed before, which protects from a wrong traffic shift.
+type WizardService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// wizardObject is an Istio object generated by a wizard
+type wizardObject struct {
+	resourceType string
+	name         string
+	spec         map[string]interface{}
+	annotations  map[string]string
+}
+
+// wizardSnapshot is an Istio object before a wizard changed it
+type wizardSnapshot struct {
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+	// Whether the object existed, with its spec, labels and annotations, or was created by the wizard
+	Existed     bool                   `json:"existed"`
+	Spec        map[string]interface{} `json:"spec,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	// Version of the object once changed by the wizard: an object modified since is not reverted
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// pendingWizardChange is a change waiting for its confirmation, reverted at its expiration
+type pendingWizardChange struct {
+	Change    models.WizardChange `json:"change"`
+	Snapshots []wizardSnapshot    `json:"snapshots"`
+}
+
+// The changes waiting for a confirmation are stored in a ConfigMap of the namespace of Kiali, by namespace and
+// service, so that any replica confirms, rolls back or expires them, even after a restart
+const (
+	wizardChangesConfigMapName = "kiali-wizard-changes"
+	wizardChangesConfigMapKey  = "changes"
+)
+
+// Period of the checks of the expiration of the pending changes, which are reverted up to that late
+const wizardChangesExpirationPeriod = 10 * time.Second
+
+func wizardChangeKey(namespace, service string) string {
+	return namespace + "/" + service
+}
+
+// ApplyTrafficShifting routes the traffic of a service to its workloads by weight, with a VirtualService and a
+// DestinationRule defining a subset per workload, named after its version
+func (in *WizardService) ApplyTrafficShifting(namespace, service string, request models.TrafficShiftingRequest) (*models.WizardChange, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WizardService", "ApplyTrafficShifting")
+	defer promtimer.ObserveNow(&err)
+
+	ttl, err := parseWizardTTL(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+	total := 0
+	for _, w := range request.Workloads {
+		if w.Weight < 0 {
+			err = errors.NewBadRequest(fmt.Sprintf("invalid weight %d of workload %s", w.Weight, w.Name))
+			return nil, err
+		}
+		total += w.Weight
+	}
+	if len(request.Workloads) == 0 || total != 100 {
+		err = errors.NewBadRequest(fmt.Sprintf("the weights of the workloads add up to %d instead of 100", total))
+		return nil, err
+	}
+	if _, err = in.k8s.GetService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	subsets := make([]interface{}, 0, len(request.Workloads))
+	routes := make([]interface{}, 0, len(request.Workloads))
+	for _, w := range request.Workloads {
+		var workload *models.Workload
+		if workload, err = fetchWorkload(in.businessLayer, namespace, w.Name, ""); err != nil {
+			return nil, err
+		}
+		version, ok := workload.Labels[versionLabel]
+		if !ok {
+			err = errors.NewBadRequest(fmt.Sprintf("workload %s has no %s label to define its subset", w.Name, versionLabel))
+			return nil, err
+		}
+		subsets = append(subsets, map[string]interface{}{"name": version, "labels": map[string]interface{}{versionLabel: version}})
+		routes = append(routes, map[string]interface{}{
+			"destination": map[string]interface{}{"host": service, "subset": version},
+			"weight":      w.Weight,
+		})
+	}
+
+	var change *models.WizardChange
+	change, err = in.applyWizardObjects(namespace, service, models.WizardTrafficShifting, ttl, []wizardObject{
+		{resourceType: kubernetes.DestinationRules, name: service, spec: map[string]interface{}{"host": service, "subsets": subsets}},
+		{resourceType: kubernetes.VirtualServices, name: service, spec: map[string]interface{}{
+			"hosts": []interface{}{service},
+			"http":  []interface{}{map[string]interface{}{"route": routes}},
+		}},
+	})
+	return change, err
+}
+
+// GetWizardChange returns the change of a service waiting for its confirmation
+func (in *WizardService) GetWizardChange(namespace, service string) (*models.WizardChange, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	pending, err := getPendingWizardChange(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	return &pending.Change, nil
+}
+
+// ConfirmWizardChange keeps the change of a service, cancelling its rollback
+func (in *WizardService) ConfirmWizardChange(namespace, service string) (*models.WizardChange, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if pending, err := getPendingWizardChange(namespace, service); err == nil && pending.Change.Wizard == models.WizardFaultInjection {
+		return nil, errors.NewBadRequest("injected faults are removed at their expiration, they cannot be confirmed")
+	}
+	pending, err := takePendingWizardChange(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	pending.Change.Confirmed = true
+	pending.Change.ExpiresAt = nil
+	return &pending.Change, nil
+}
+
+// RollbackWizardChange reverts now the change of a service waiting for its confirmation. The change stays pending
+// until it is reverted: a failed rollback is retried at its expiration.
+func (in *WizardService) RollbackWizardChange(namespace, service string) (*models.WizardChange, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	pending, err := getPendingWizardChange(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if err = revertWizardSnapshots(in.k8s, namespace, pending.Snapshots); err != nil {
+		return nil, err
+	}
+	if _, err = takePendingWizardChange(namespace, service); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	pending.Change.RolledBack = true
+	pending.Change.ExpiresAt = nil
+	return &pending.Change, nil
+}
+
+// getPendingWizardChange returns the change of a service waiting for its confirmation, with its snapshots
+func getPendingWizardChange(namespace, service string) (*pendingWizardChange, error) {
+	changes, err := getPendingWizardChanges()
+	if err != nil {
+		return nil, err
+	}
+	pending, ok := changes[wizardChangeKey(namespace, service)]
+	if !ok {
+		return nil, kubernetes.NewNotFound(service, "kiali.io", "wizard changes")
+	}
+	return &pending, nil
+}
+
+// takePendingWizardChange removes the change of a service from the pending changes, so that it is not reverted at
+// its expiration
+func takePendingWizardChange(namespace, service string) (*pendingWizardChange, error) {
+	var taken *pendingWizardChange
+	err := updatePendingWizardChanges(func(changes map[string]pendingWizardChange) error {
+		key := wizardChangeKey(namespace, service)
+		pending, ok := changes[key]
+		if !ok {
+			return kubernetes.NewNotFound(service, "kiali.io", "wizard changes")
+		}
+		delete(changes, key)
+		taken = &pending
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+// expireWizardChanges periodically reverts the changes not confirmed before their expiration, with the service
+// account of Kiali
+func expireWizardChanges(ctx context.Context) {
+	ticker := time.NewTicker(wizardChangesExpirationPeriod)
+	defer ticker.Stop()
+	for {
+		expireWizardChangesOnce()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func expireWizardChangesOnce() {
+	// The expired changes are removed once reverted, so that a change whose revert fails is retried at the next check
+	changes, err := getPendingWizardChanges()
+	if err != nil {
+		log.Errorf("Error expiring the wizard changes: %v", err)
+		return
+	}
+	now := util.Clock.Now()
+	expired := []pendingWizardChange{}
+	for _, pending := range changes {
+		if pending.Change.ExpiresAt != nil && !now.Before(*pending.Change.ExpiresAt) {
+			expired = append(expired, pending)
+		}
+	}
+	if len(expired) == 0 {
+		return
+	}
+
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		log.Errorf("Error expiring the wizard changes: %v", err)
+		return
+	}
+	for _, pending := range expired {
+		change := pending.Change
+		log.Infof("Reverting the unconfirmed %s wizard on service %s.%s", change.Wizard, change.Service, change.Namespace)
+		if err := revertWizardSnapshots(k8s, change.Namespace, pending.Snapshots); err != nil {
+			if !errors.IsConflict(err) {
+				log.Errorf("Error reverting the %s wizard on service %s.%s: %v", change.Wizard, change.Service, change.Namespace, err)
+				continue
+			}
+			// Reverting again would overwrite the changes made since the wizard
+			log.Warningf("The %s wizard on service %s.%s is not reverted: %v", change.Wizard, change.Service, change.Namespace, err)
+		}
+		if _, err := takePendingWizardChange(change.Namespace, change.Service); err != nil && !errors.IsNotFound(err) {
+			log.Errorf("Error removing the reverted %s wizard on service %s.%s: %v", change.Wizard, change.Service, change.Namespace, err)
+		}
+	}
+}
+
+// getPendingWizardChanges reads the changes waiting for a confirmation, with the service account of Kiali
+func getPendingWizardChanges() (map[string]pendingWizardChange, error) {
+	k8s, err := getKialiSAClient()
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := readKialiConfigMap(k8s, wizardChangesConfigMapName, wizardChangesConfigMapKey)
+	if err != nil {
+		return nil, err
+	}
+	return parsePendingWizardChanges(data)
+}
+
+// updatePendingWizardChanges applies the given change to the changes waiting for a confirmation and persists the
+// result. The update fails with a conflict when another replica updated them meanwhile.
+func updatePendingWizardChanges(change func(map[string]pendingWizardChange) error) error {
+	return updateKialiConfigMap(wizardChangesConfigMapName, wizardChangesConfigMapKey, func(data string) (string, error) {
+		changes, err := parsePendingWizardChanges(data)
+		if err != nil {
+			return "", err
+		}
+		if err = change(changes); err != nil {
+			return "", err
+		}
+		rawChanges, err := json.Marshal(changes)
+		return string(rawChanges), err
+	})
+}
+
+func parsePendingWizardChanges(data string) (map[string]pendingWizardChange, error) {
+	changes := map[string]pendingWizardChange{}
+	if data == "" {
+		return changes, nil
+	}
+	if err := json.Unmarshal([]byte(data), &changes); err != nil {
+		return nil, fmt.Errorf("cannot parse the pending wizard changes: %v", err)
+	}
+	return changes, nil
+}
+
+func parseWizardTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration <= 0 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid ttl [%s], expected a positive duration such as 5m", ttl))
+	}
+	return duration, nil
+}
+
+// applyWizardObjects creates or updates the objects of a wizard, snapshotting them first. With a ttl, the change is
+// reverted at its expiration unless confirmed. A change applied with a ttl while another one is pending extends it:
+// the objects are reverted to their state before the first change. A change without ttl is rejected while another
+// one is pending, as it would leave the pending change without rollback.
+func (in *WizardService) applyWizardObjects(namespace, service, wizard string, ttl time.Duration, objects []wizardObject) (*models.WizardChange, error) {
+	if ttl == 0 {
+		if _, err := getPendingWizardChange(namespace, service); err == nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("a change of service %s is waiting for its confirmation, it must be confirmed or rolled back first", service))
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	change := models.WizardChange{Namespace: namespace, Service: service, Wizard: wizard, AppliedAt: util.Clock.Now(), Objects: []models.WizardObject{}}
+	snapshots := []wizardSnapshot{}
+	for _, o := range objects {
+		snapshot, err := in.applyWizardObject(namespace, wizard, o)
+		if err != nil {
+			// Leave the objects as they were
+			if rollbackErr := revertWizardSnapshots(in.k8s, namespace, snapshots); rollbackErr != nil {
+				log.Errorf("Error reverting the %s wizard on service %s.%s: %v", wizard, service, namespace, rollbackErr)
+			}
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+		change.Objects = append(change.Objects, models.WizardObject{ObjectType: o.resourceType, Name: o.name, Created: !snapshot.Existed})
+	}
+	if ttl == 0 {
+		return &change, nil
+	}
+
+	expiresAt := change.AppliedAt.Add(ttl)
+	change.ExpiresAt = &expiresAt
+	err := updatePendingWizardChanges(func(changes map[string]pendingWizardChange) error {
+		key := wizardChangeKey(namespace, service)
+		pending := pendingWizardChange{Change: change, Snapshots: snapshots}
+		if previous, ok := changes[key]; ok {
+			pending.Snapshots = mergeWizardSnapshots(previous.Snapshots, snapshots)
+		}
+		changes[key] = pending
+		return nil
+	})
+	if err != nil {
+		// A change that would not expire is not left behind
+		if rollbackErr := revertWizardSnapshots(in.k8s, namespace, snapshots); rollbackErr != nil {
+			log.Errorf("Error reverting the %s wizard on service %s.%s: %v", wizard, service, namespace, rollbackErr)
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (in *WizardService) applyWizardObject(namespace, wizard string, o wizardObject) (*wizardSnapshot, error) {
+	api := kubernetes.ResourceTypesToAPI[o.resourceType]
+	snapshot := &wizardSnapshot{ResourceType: o.resourceType, Name: o.name}
+	annotations := map[string]interface{}{}
+	for k, v := range o.annotations {
+		annotations[k] = v
+	}
+	current, err := in.k8s.GetIstioObject(namespace, o.resourceType, o.name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		metadata := map[string]interface{}{"name": o.name, "namespace": namespace, "labels": map[string]interface{}{wizardLabel: wizard}}
+		if len(annotations) > 0 {
+			metadata["annotations"] = annotations
+		}
+		object := map[string]interface{}{
+			"apiVersion": kubernetes.ApiNetworkingVersion,
+			"kind":       kubernetes.PluralType[o.resourceType],
+			"metadata":   metadata,
+			"spec":       o.spec,
+		}
+		body, _ := json.Marshal(object)
+		created, err := in.k8s.CreateIstioObject(api, namespace, o.resourceType, string(body))
+		if err != nil {
+			return nil, err
+		}
+		snapshot.ResourceVersion = created.GetObjectMeta().ResourceVersion
+		return snapshot, nil
+	}
+
+	snapshot.Existed = true
+	snapshot.Spec = current.GetSpec()
+	snapshot.Labels = current.GetObjectMeta().Labels
+	snapshot.Annotations = current.GetObjectMeta().Annotations
+	metadata := map[string]interface{}{"labels": map[string]interface{}{wizardLabel: wizard}}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": metadata,
+		"spec":     mergePatchFor(snapshot.Spec, o.spec),
+	})
+	updated, err := in.k8s.UpdateIstioObject(api, namespace, o.resourceType, o.name, string(patch))
+	if err != nil {
+		return nil, err
+	}
+	snapshot.ResourceVersion = updated.GetObjectMeta().ResourceVersion
+	return snapshot, nil
+}
+
+// revertWizardSnapshots deletes the objects created by a wizard and restores the spec and labels of the others. It
+// fails with a conflict, reverting nothing, when an object was modified since the wizard changed it.
+func revertWizardSnapshots(k8s kubernetes.ClientInterface, namespace string, snapshots []wizardSnapshot) error {
+	for _, s := range snapshots {
+		if s.ResourceVersion == "" {
+			continue
+		}
+		current, err := k8s.GetIstioObject(namespace, s.ResourceType, s.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if version := current.GetObjectMeta().ResourceVersion; version != s.ResourceVersion {
+			return errors.NewConflict(schema.GroupResource{Group: kubernetes.ResourceTypesToAPI[s.ResourceType], Resource: s.ResourceType}, s.Name,
+				fmt.Errorf("modified since the wizard changed it (resource version %s instead of %s)", version, s.ResourceVersion))
+		}
+	}
+	for _, s := range snapshots {
+		api := kubernetes.ResourceTypesToAPI[s.ResourceType]
+		if !s.Existed {
+			if err := k8s.DeleteIstioObject(api, namespace, s.ResourceType, s.Name); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		current, err := k8s.GetIstioObject(namespace, s.ResourceType, s.Name)
+		if err != nil {
+			return err
+		}
+		meta := current.GetObjectMeta()
+		patch, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      mergePatchFor(stringMap(meta.Labels), stringMap(s.Labels)),
+				"annotations": mergePatchFor(stringMap(meta.Annotations), stringMap(s.Annotations)),
+			},
+			"spec": mergePatchFor(current.GetSpec(), s.Spec),
+		})
+		if _, err = k8s.UpdateIstioObject(api, namespace, s.ResourceType, s.Name, string(patch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		converted[k] = v
+	}
+	return converted
+}
+
+// mergeWizardSnapshots keeps the oldest snapshot of each object, with the version of the object after the newest change
+func mergeWizardSnapshots(oldest, newest []wizardSnapshot) []wizardSnapshot {
+	merged := append([]wizardSnapshot{}, oldest...)
+	for _, n := range newest {
+		found := false
+		for i, o := range merged {
+			if o.ResourceType == n.ResourceType && o.Name == n.Name {
+				merged[i].ResourceVersion = n.ResourceVersion
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, n)
+		}
+	}
+	return merged
+}
+
+// mergePatchFor returns the JSON merge patch turning current into target: the fields missing from target are removed
+func mergePatchFor(current, target map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for k, v := range target {
+		currentMap, currentIsMap := current[k].(map[string]interface{})
+		targetMap, targetIsMap := v.(map[string]interface{})
+		if currentIsMap && targetIsMap {
+			patch[k] = mergePatchFor(currentMap, targetMap)
+		} else {
+			patch[k] = v
+		}
+	}
+	for k := range current {
+		if _, ok := target[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	return patch
+}
@@ -0,0 +1,257 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// setupWizardChangesMock stores the pending wizard changes in a ConfigMap of the given client, which is also the
+// client of the Kiali service account
+func setupWizardChangesMock(k8s *kubetest.K8SClientMock) *core_v1.ConfigMap {
+	config.Set(config.NewConfig())
+	kubernetes.KialiToken = "kiali-sa-token"
+	stored := mockKialiConfigMap(k8s, wizardChangesConfigMapName)
+	SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+	return stored
+}
+
+// newWizardService returns the wizard service of a layer whose client is the mock, giving access to bookinfo
+func newWizardService(k8s *kubetest.K8SClientMock) WizardService {
+	kialiCache = nil
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	return NewWithBackends(k8s, nil, nil).Wizard
+}
+
+func fakeWizardVirtualService() *kubernetes.GenericIstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo", Labels: map[string]string{"team": "bookinfo"}},
+		Spec: map[string]interface{}{
+			"hosts":    []interface{}{"reviews"},
+			"http":     []interface{}{map[string]interface{}{"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "reviews"}}}}},
+			"gateways": []interface{}{"bookinfo-gateway"},
+		},
+	}
+}
+
+func TestMergePatchFor(t *testing.T) {
+	assert := assert.New(t)
+
+	current := map[string]interface{}{
+		"host":          "reviews",
+		"subsets":       []interface{}{"v1", "v2"},
+		"trafficPolicy": map[string]interface{}{"tls": "ISTIO_MUTUAL", "loadBalancer": "ROUND_ROBIN"},
+	}
+	target := map[string]interface{}{
+		"host":          "reviews",
+		"trafficPolicy": map[string]interface{}{"tls": "DISABLE"},
+	}
+	assert.Equal(map[string]interface{}{
+		"host":          "reviews",
+		"subsets":       nil,
+		"trafficPolicy": map[string]interface{}{"tls": "DISABLE", "loadBalancer": nil},
+	}, mergePatchFor(current, target))
+}
+
+func TestApplyTrafficShiftingValidation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	svc := WizardService{k8s: new(kubetest.K8SClientMock)}
+	_, err := svc.ApplyTrafficShifting("bookinfo", "reviews", models.TrafficShiftingRequest{Workloads: []models.WorkloadWeight{{Name: "reviews-v1", Weight: 80}}})
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.ApplyTrafficShifting("bookinfo", "reviews", models.TrafficShiftingRequest{Workloads: []models.WorkloadWeight{{Name: "reviews-v1", Weight: 100}}, TTL: "soon"})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestWizardChangeRollback(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	stored := setupWizardChangesMock(k8s)
+	notFound := kubernetes.NewNotFound("reviews", "networking.istio.io", "destinationrules")
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(&kubernetes.GenericIstioObject{}, notFound).Once()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", kubernetes.DestinationRules, mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(fakeWizardVirtualService(), nil)
+	var patches []string
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { patches = append(patches, args.String(4)) }).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.DestinationRules, "reviews").Return(nil)
+	svc := newWizardService(k8s)
+
+	change, err := svc.applyWizardObjects("bookinfo", "reviews", models.WizardTrafficShifting, time.Hour, []wizardObject{
+		{resourceType: kubernetes.DestinationRules, name: "reviews", spec: map[string]interface{}{"host": "reviews"}},
+		{resourceType: kubernetes.VirtualServices, name: "reviews", spec: map[string]interface{}{"hosts": []interface{}{"reviews"}}},
+	})
+	assert.NoError(err)
+	assert.NotNil(change.ExpiresAt)
+	assert.Equal([]models.WizardObject{
+		{ObjectType: kubernetes.DestinationRules, Name: "reviews", Created: true},
+		{ObjectType: kubernetes.VirtualServices, Name: "reviews", Created: false},
+	}, change.Objects)
+	assert.Len(patches, 1)
+	assert.JSONEq(`{"metadata":{"labels":{"kiali_wizard":"traffic_shifting"}},"spec":{"hosts":["reviews"],"http":null,"gateways":null}}`, patches[0])
+
+	// The change is stored for the other replicas
+	assert.Contains(stored.Data[wizardChangesConfigMapKey], `"bookinfo/reviews"`)
+	pending, err := svc.GetWizardChange("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.Equal(models.WizardTrafficShifting, pending.Wizard)
+
+	// A change without expiration would drop the rollback of the pending change
+	_, err = svc.applyWizardObjects("bookinfo", "reviews", models.WizardTrafficShifting, 0, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: "reviews", spec: map[string]interface{}{"hosts": []interface{}{"reviews"}}},
+	})
+	assert.True(errors.IsBadRequest(err))
+	assert.Len(patches, 1)
+
+	change, err = svc.RollbackWizardChange("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.True(change.RolledBack)
+	k8s.AssertCalled(t, "DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.DestinationRules, "reviews")
+	// The mock returns the object before the change: the patch restores its spec as is
+	assert.Len(patches, 2)
+	var restore map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(patches[1]), &restore))
+	assert.Equal(fakeWizardVirtualService().Spec["gateways"], restore["spec"].(map[string]interface{})["gateways"])
+
+	_, err = svc.GetWizardChange("bookinfo", "reviews")
+	assert.True(errors.IsNotFound(err))
+}
+
+func TestWizardChangeExpiration(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	setupWizardChangesMock(k8s)
+	notFound := kubernetes.NewNotFound("ratings", "networking.istio.io", "virtualservices")
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "ratings").Return(&kubernetes.GenericIstioObject{}, notFound)
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "ratings").Return(nil)
+	svc := newWizardService(k8s)
+
+	_, err := svc.applyWizardObjects("bookinfo", "ratings", models.WizardTrafficShifting, time.Minute, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: "ratings", spec: map[string]interface{}{"hosts": []interface{}{"ratings"}}},
+	})
+	assert.NoError(err)
+
+	expireWizardChangesOnce()
+	k8s.AssertNotCalled(t, "DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "ratings")
+
+	util.Clock = util.ClockMock{Time: util.Clock.Now().Add(2 * time.Minute)}
+	expireWizardChangesOnce()
+	k8s.AssertNumberOfCalls(t, "DeleteIstioObject", 1)
+	_, err = svc.GetWizardChange("bookinfo", "ratings")
+	assert.True(errors.IsNotFound(err))
+
+	// A confirmed change is kept
+	_, err = svc.applyWizardObjects("bookinfo", "ratings", models.WizardTrafficShifting, time.Minute, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: "ratings", spec: map[string]interface{}{"hosts": []interface{}{"ratings"}}},
+	})
+	assert.NoError(err)
+	change, err := svc.ConfirmWizardChange("bookinfo", "ratings")
+	assert.NoError(err)
+	assert.True(change.Confirmed)
+	util.Clock = util.ClockMock{Time: util.Clock.Now().Add(2 * time.Minute)}
+	expireWizardChangesOnce()
+	k8s.AssertNumberOfCalls(t, "DeleteIstioObject", 1)
+}
+
+func TestWizardChangeNamespaceAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	setupWizardChangesMock(k8s)
+	svc := newWizardService(k8s)
+	forbidden := errors.NewForbidden(core_v1.Resource("namespaces"), "payments", nil)
+	k8s.On("GetNamespace", "payments").Return(&core_v1.Namespace{}, forbidden)
+
+	_, err := svc.GetWizardChange("payments", "ledger")
+	assert.True(errors.IsForbidden(err))
+	_, err = svc.ConfirmWizardChange("payments", "ledger")
+	assert.True(errors.IsForbidden(err))
+	_, err = svc.RollbackWizardChange("payments", "ledger")
+	assert.True(errors.IsForbidden(err))
+}
+
+func TestWizardChangeRevertedBeforeRemoval(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	setupWizardChangesMock(k8s)
+	notFound := kubernetes.NewNotFound("ratings", "networking.istio.io", "virtualservices")
+	created := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", ResourceVersion: "10"}}
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "ratings").Return(&kubernetes.GenericIstioObject{}, notFound).Once()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, mock.AnythingOfType("string")).Return(created, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "ratings").Return(created, nil)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "ratings").Return(errors.NewServiceUnavailable("unavailable")).Once()
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "ratings").Return(nil)
+	svc := newWizardService(k8s)
+
+	_, err := svc.applyWizardObjects("bookinfo", "ratings", models.WizardTrafficShifting, time.Minute, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: "ratings", spec: map[string]interface{}{"hosts": []interface{}{"ratings"}}},
+	})
+	assert.NoError(err)
+
+	// A failed revert is retried
+	util.Clock = util.ClockMock{Time: util.Clock.Now().Add(2 * time.Minute)}
+	expireWizardChangesOnce()
+	_, err = svc.GetWizardChange("bookinfo", "ratings")
+	assert.NoError(err)
+	expireWizardChangesOnce()
+	k8s.AssertNumberOfCalls(t, "DeleteIstioObject", 2)
+	_, err = svc.GetWizardChange("bookinfo", "ratings")
+	assert.True(errors.IsNotFound(err))
+}
+
+func TestWizardChangeRevertConflict(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	setupWizardChangesMock(k8s)
+	before := fakeWizardVirtualService()
+	before.ResourceVersion = "10"
+	modified := fakeWizardVirtualService()
+	modified.ResourceVersion = "12"
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(before, nil).Once()
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).
+		Return(&kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", ResourceVersion: "11"}}, nil)
+	// Someone else modified the VirtualService since the wizard
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(modified, nil)
+	svc := newWizardService(k8s)
+
+	_, err := svc.applyWizardObjects("bookinfo", "reviews", models.WizardTrafficShifting, time.Minute, []wizardObject{
+		{resourceType: kubernetes.VirtualServices, name: "reviews", spec: map[string]interface{}{"hosts": []interface{}{"reviews"}}},
+	})
+	assert.NoError(err)
+
+	_, err = svc.RollbackWizardChange("bookinfo", "reviews")
+	assert.True(errors.IsConflict(err))
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	_, err = svc.GetWizardChange("bookinfo", "reviews")
+	assert.NoError(err)
+
+	// The expiration gives up on the change instead of overwriting the other modifications
+	util.Clock = util.ClockMock{Time: util.Clock.Now().Add(2 * time.Minute)}
+	expireWizardChangesOnce()
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	_, err = svc.GetWizardChange("bookinfo", "reviews")
+	assert.True(errors.IsNotFound(err))
+}
@@ -0,0 +1,128 @@
+package business
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Annotation of the pod template set by kubectl rollout restart
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Resources of the workload types which can be scaled and restarted
+var workloadResources = map[string]schema.GroupResource{
+	kubernetes.DeploymentType:  {Group: "apps", Resource: "deployments"},
+	kubernetes.StatefulSetType: {Group: "apps", Resource: "statefulsets"},
+	kubernetes.RolloutType:     {Group: "argoproj.io", Resource: "rollouts"},
+}
+
+// ScaleWorkload sets the number of replicas of a Deployment, StatefulSet or Argo Rollout, when the user is allowed to
+// patch it. The type is looked up when not given, except for Rollouts which are not resolved as workloads.
+func (in *WorkloadService) ScaleWorkload(namespace, workloadName, workloadType string, replicas int32) (*models.WorkloadScale, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "ScaleWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	if replicas < 0 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid number of replicas %d", replicas))
+		return nil, err
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	if workloadType == "" {
+		var workload *models.Workload
+		if workload, err = in.GetWorkload(namespace, workloadName, "", false); err != nil {
+			return nil, err
+		}
+		workloadType = workload.Type
+	}
+	resource, ok := workloadResources[workloadType]
+	if !ok || !isWorkloadIncluded(workloadType) {
+		err = errors.NewBadRequest(fmt.Sprintf("workload %s of type %s cannot be scaled", workloadName, workloadType))
+		return nil, err
+	}
+
+	if err = in.checkCan("patch", namespace, resource, workloadName); err != nil {
+		return nil, err
+	}
+	if _, err = in.k8s.UpdateWorkload(namespace, workloadName, workloadType, fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)); err != nil {
+		return nil, err
+	}
+	return &models.WorkloadScale{Namespace: namespace, Name: workloadName, Type: workloadType, Replicas: replicas}, nil
+}
+
+// RestartWorkload replaces the pods of a Deployment, StatefulSet or Argo Rollout following its update strategy, like
+// kubectl rollout restart, when the user is allowed to patch it
+func (in *WorkloadService) RestartWorkload(namespace, workloadName, workloadType string) (*models.WorkloadRestart, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "RestartWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	if workloadType == "" {
+		var workload *models.Workload
+		if workload, err = in.GetWorkload(namespace, workloadName, "", false); err != nil {
+			return nil, err
+		}
+		workloadType = workload.Type
+	}
+	resource, ok := workloadResources[workloadType]
+	if !ok || !isWorkloadIncluded(workloadType) {
+		err = errors.NewBadRequest(fmt.Sprintf("workload %s of type %s cannot be restarted", workloadName, workloadType))
+		return nil, err
+	}
+
+	if err = in.checkCan("patch", namespace, resource, workloadName); err != nil {
+		return nil, err
+	}
+	restartedAt := util.Clock.Now().UTC().Format(time.RFC3339)
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"%s":"%s"}}}}}`, restartedAtAnnotation, restartedAt)
+	if workloadType == kubernetes.RolloutType {
+		// A Rollout restarts its pods itself, at the requested time
+		patch = fmt.Sprintf(`{"spec":{"restartAt":"%s"}}`, restartedAt)
+	}
+	if _, err = in.k8s.UpdateWorkload(namespace, workloadName, workloadType, patch); err != nil {
+		return nil, err
+	}
+	return &models.WorkloadRestart{Namespace: namespace, Name: workloadName, Type: workloadType, RestartedAt: restartedAt}, nil
+}
+
+// EvictPod evicts a pod, which its controller replaces, unless it would violate a PodDisruptionBudget. The eviction
+// is denied then with a TooManyRequests error.
+func (in *WorkloadService) EvictPod(namespace, pod string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "EvictPod")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return err
+	}
+	err = in.k8s.EvictPod(namespace, pod)
+	return err
+}
+
+// checkCan returns a Forbidden error when the RBAC of the user does not allow the verb on the resource
+func (in *WorkloadService) checkCan(verb, namespace string, resource schema.GroupResource, name string) error {
+	ssars, err := in.k8s.GetSelfSubjectAccessReview(namespace, resource.Group, resource.Resource, []string{verb})
+	if err != nil {
+		return err
+	}
+	for _, ssar := range ssars {
+		if ssar.Status.Allowed {
+			return nil
+		}
+	}
+	return errors.NewForbidden(resource, name, fmt.Errorf("the user cannot %s %s in namespace %s", verb, resource.Resource, namespace))
+}
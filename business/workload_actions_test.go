@@ -0,0 +1,93 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/util"
+)
+
+func fakeAccessReview(allowed bool) []*auth_v1.SelfSubjectAccessReview {
+	return []*auth_v1.SelfSubjectAccessReview{{Status: auth_v1.SubjectAccessReviewStatus{Allowed: allowed}}}
+}
+
+func TestScaleWorkload(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "apps", "statefulsets", []string{"patch"}).Return(fakeAccessReview(true), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "argoproj.io", "rollouts", []string{"patch"}).Return(fakeAccessReview(false), nil)
+	k8s.On("UpdateWorkload", "bookinfo", "ratings", kubernetes.StatefulSetType, `{"spec":{"replicas":3}}`).Return("", nil)
+	svc := setupWorkloadService(k8s)
+
+	scale, err := svc.ScaleWorkload("bookinfo", "ratings", kubernetes.StatefulSetType, 3)
+	assert.NoError(err)
+	assert.Equal("StatefulSet", scale.Type)
+	assert.Equal(int32(3), scale.Replicas)
+	k8s.AssertCalled(t, "UpdateWorkload", "bookinfo", "ratings", kubernetes.StatefulSetType, `{"spec":{"replicas":3}}`)
+
+	// Not allowed by the RBAC of the user
+	_, err = svc.ScaleWorkload("bookinfo", "reviews", kubernetes.RolloutType, 2)
+	assert.True(errors.IsForbidden(err))
+
+	_, err = svc.ScaleWorkload("bookinfo", "details", kubernetes.JobType, 2)
+	assert.True(errors.IsBadRequest(err))
+
+	_, err = svc.ScaleWorkload("bookinfo", "ratings", kubernetes.StatefulSetType, -1)
+	assert.True(errors.IsBadRequest(err))
+
+	k8s.AssertNumberOfCalls(t, "UpdateWorkload", 1)
+	k8s.AssertNotCalled(t, "UpdateWorkload", "bookinfo", "reviews", mock.Anything, mock.Anything)
+}
+
+func TestRestartWorkload(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", mock.Anything, mock.Anything, []string{"patch"}).Return(fakeAccessReview(true), nil)
+	k8s.On("UpdateWorkload", "bookinfo", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	svc := setupWorkloadService(k8s)
+
+	restart, err := svc.RestartWorkload("bookinfo", "ratings", kubernetes.StatefulSetType)
+	assert.NoError(err)
+	assert.Equal("2021-03-01T10:00:00Z", restart.RestartedAt)
+	k8s.AssertCalled(t, "UpdateWorkload", "bookinfo", "ratings", kubernetes.StatefulSetType,
+		`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2021-03-01T10:00:00Z"}}}}}`)
+
+	_, err = svc.RestartWorkload("bookinfo", "reviews", kubernetes.RolloutType)
+	assert.NoError(err)
+	k8s.AssertCalled(t, "UpdateWorkload", "bookinfo", "reviews", kubernetes.RolloutType, `{"spec":{"restartAt":"2021-03-01T10:00:00Z"}}`)
+
+	_, err = svc.RestartWorkload("bookinfo", "details", kubernetes.PodType)
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestEvictPod(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("EvictPod", "bookinfo", "ratings-v1-abc").Return(nil)
+	k8s.On("EvictPod", "bookinfo", "reviews-v1-def").Return(errors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0))
+	svc := setupWorkloadService(k8s)
+
+	assert.NoError(svc.EvictPod("bookinfo", "ratings-v1-abc"))
+	assert.True(errors.IsTooManyRequests(svc.EvictPod("bookinfo", "reviews-v1-def")))
+}
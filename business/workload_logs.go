@@ -0,0 +1,173 @@
+package business
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Logs of the containers of a workload fetched at the same time
+const maxConcurrentLogFetches = 10
+
+// Longest log line read from a stream, the longer lines ending the stream of their container
+const maxStreamedLogLine = 1024 * 1024
+
+// streamedLogLine is a raw log line read from the stream of a container
+type streamedLogLine struct {
+	container string
+	line      string
+}
+
+// StreamPodLogs follows the logs of the container of the options or else of all the containers of a pod, calling
+// send for each entry kept by the filters, with its exact time, as soon as it is written. It returns when stop is
+// closed, the streams end or send fails. The entries of several containers are interleaved in the order they are read.
+func (in *WorkloadService) StreamPodLogs(namespace, name string, opts *LogOptions, stop <-chan struct{}, send func(entry LogEntry, at time.Time) error) error {
+	containers := []string{opts.Container}
+	if opts.Container == "" {
+		pod, err := in.k8s.GetPod(namespace, name)
+		if err != nil {
+			return err
+		}
+		containers = make([]string, 0, len(pod.Spec.Containers))
+		for _, c := range pod.Spec.Containers {
+			containers = append(containers, c.Name)
+		}
+	}
+
+	streams := make([]io.ReadCloser, 0, len(containers))
+	// Closing the streams unblocks the readers
+	defer func() {
+		for _, stream := range streams {
+			stream.Close()
+		}
+	}()
+	for _, container := range containers {
+		k8sOpts := opts.PodLogOptions
+		k8sOpts.Container = container
+		k8sOpts.Follow = true
+		k8sOpts.Timestamps = true
+		stream, err := in.k8s.StreamPodLogs(namespace, name, &k8sOpts)
+		if err != nil {
+			return err
+		}
+		streams = append(streams, stream)
+	}
+
+	lines := make(chan streamedLogLine)
+	done := make(chan struct{})
+	defer close(done)
+	var wg sync.WaitGroup
+	for i := range streams {
+		wg.Add(1)
+		go func(container string, stream io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 0, 64*1024), maxStreamedLogLine)
+			for scanner.Scan() {
+				select {
+				case lines <- streamedLogLine{container: container, line: scanner.Text()}:
+				case <-done:
+					return
+				}
+			}
+		}(containers[i], streams[i])
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	correlation := newLogCorrelation(config.Get().ExternalServices.Tracing.LogCorrelation)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case l, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			entry, at, ok := parseLogLine(l.line, correlation)
+			if !ok || !opts.matches(entry) {
+				continue
+			}
+			if len(containers) > 1 {
+				entry.Container = l.container
+			}
+			if err := send(entry, at); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetWorkloadLogs merges the logs of the containers of all the pods of a workload, ordered by time, the entries being
+// attributed to their pod and container. The options apply to each container, the tail lines to the merged logs too.
+func (in *WorkloadService) GetWorkloadLogs(namespace, workloadName string, opts *LogOptions) (*PodLog, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadLogs")
+	defer promtimer.ObserveNow(&err)
+
+	workload, err := in.GetWorkload(namespace, workloadName, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	type podContainer struct {
+		pod       string
+		container string
+	}
+	sources := []podContainer{}
+	for _, pod := range workload.Pods {
+		for _, c := range append(append([]*models.ContainerInfo{}, pod.Containers...), pod.IstioContainers...) {
+			if opts.Container == "" || opts.Container == c.Name {
+				sources = append(sources, podContainer{pod: pod.Name, container: c.Name})
+			}
+		}
+	}
+
+	logs := make([][]LogEntry, len(sources))
+	errs := make([]error, len(sources))
+	slots := make(chan struct{}, maxConcurrentLogFetches)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source podContainer) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			sourceOpts := *opts
+			sourceOpts.Container = source.container
+			podLog, err := in.getParsedLogs(namespace, source.pod, &sourceOpts)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for j := range podLog.Entries {
+				podLog.Entries[j].Pod = source.pod
+				podLog.Entries[j].Container = source.container
+			}
+			logs[i] = podLog.Entries
+		}(i, source)
+	}
+	wg.Wait()
+
+	entries := []LogEntry{}
+	for i := range sources {
+		if errs[i] != nil {
+			err = errs[i]
+			return nil, err
+		}
+		entries = append(entries, logs[i]...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+	if opts.TailLines != nil && len(entries) > int(*opts.TailLines) {
+		entries = entries[len(entries)-int(*opts.TailLines):]
+	}
+	return &PodLog{Entries: entries}, nil
+}
@@ -0,0 +1,135 @@
+package business
+
+import (
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func containerLogOptions(container string) interface{} {
+	return mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == container && opts.Follow && opts.Timestamps
+	})
+}
+
+func setupLogsStreamingService() WorkloadService {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetPod", "bookinfo", "details-v1").Return(&core_v1.Pod{Spec: core_v1.PodSpec{Containers: []core_v1.Container{{Name: "details"}, {Name: "istio-proxy"}}}}, nil)
+	k8s.On("StreamPodLogs", "bookinfo", "details-v1", containerLogOptions("details")).Return(ioutil.NopCloser(strings.NewReader(
+		"2021-03-01T10:00:00.100Z GET /details/0\n2021-03-01T10:00:01.200Z ERROR details not found\n")), nil)
+	k8s.On("StreamPodLogs", "bookinfo", "details-v1", containerLogOptions("istio-proxy")).Return(ioutil.NopCloser(strings.NewReader(
+		"2021-03-01T10:00:00.150Z [2021-03-01T10:00:00.150Z] \"GET /details/0 HTTP/1.1\" 200\n\n")), nil)
+	return setupWorkloadService(k8s)
+}
+
+func TestStreamPodLogs(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	svc := setupLogsStreamingService()
+	entries := []LogEntry{}
+	times := map[string]time.Time{}
+	err := svc.StreamPodLogs("bookinfo", "details-v1", &LogOptions{}, make(chan struct{}), func(entry LogEntry, at time.Time) error {
+		entries = append(entries, entry)
+		times[entry.Message] = at
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(entries, 3)
+	containers := map[string]int{}
+	for _, entry := range entries {
+		containers[entry.Container]++
+	}
+	assert.Equal(map[string]int{"details": 2, "istio-proxy": 1}, containers)
+	assert.Equal(time.Date(2021, 3, 1, 10, 0, 1, 200000000, time.UTC), times["ERROR details not found"])
+
+	// Filtered, of a single container
+	svc = setupLogsStreamingService()
+	entries = []LogEntry{}
+	err = svc.StreamPodLogs("bookinfo", "details-v1", &LogOptions{Filter: regexp.MustCompile("not found"), PodLogOptions: core_v1.PodLogOptions{Container: "details"}}, make(chan struct{}), func(entry LogEntry, at time.Time) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(entries, 1)
+	assert.Equal("ERROR", entries[0].Severity)
+	assert.Empty(entries[0].Container)
+
+	// The stream ends when the entry cannot be sent
+	svc = setupLogsStreamingService()
+	err = svc.StreamPodLogs("bookinfo", "details-v1", &LogOptions{PodLogOptions: core_v1.PodLogOptions{Container: "istio-proxy"}}, make(chan struct{}), func(entry LogEntry, at time.Time) error {
+		return errors.New("connection closed")
+	})
+	assert.EqualError(err, "connection closed")
+}
+
+func podLogOptions(container string) interface{} {
+	return mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool { return opts.Container == container })
+}
+
+func TestGetWorkloadLogs(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	notfound := k8s_errors.NewNotFound(schema.GroupResource{}, "not found")
+	pods := FakePodsSyncedWithDeployments()
+	pods[0].Labels = map[string]string{"app": "details", "version": "v1"}
+	other := *pods[0].DeepCopy()
+	other.Name = "details-v1-3618568057-xyz"
+	pods = append(pods, other)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetRollouts", mock.AnythingOfType("string")).Return([]kubernetes.Rollout{}, nil)
+	k8s.On("GetPodLogs", "Namespace", "details-v1-3618568057-dnkjp", podLogOptions("details")).Return(&kubernetes.PodLogs{Logs: "2021-03-01T10:00:00.100Z first\n2021-03-01T10:00:00.900Z fourth\n"}, nil)
+	k8s.On("GetPodLogs", "Namespace", "details-v1-3618568057-dnkjp", podLogOptions("istio-proxy")).Return(&kubernetes.PodLogs{Logs: "2021-03-01T10:00:00.500Z third\n"}, nil)
+	k8s.On("GetPodLogs", "Namespace", "details-v1-3618568057-xyz", podLogOptions("details")).Return(&kubernetes.PodLogs{Logs: "2021-03-01T10:00:00.200Z second\n2021-03-01T10:00:01.000Z fifth\n"}, nil)
+	k8s.On("GetPodLogs", "Namespace", "details-v1-3618568057-xyz", podLogOptions("istio-proxy")).Return(&kubernetes.PodLogs{}, nil)
+	svc := setupWorkloadService(k8s)
+
+	logs, err := svc.GetWorkloadLogs("Namespace", "details-v1", &LogOptions{})
+	assert.NoError(err)
+	messages := []string{}
+	for _, entry := range logs.Entries {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal([]string{"first", "second", "third", "fourth", "fifth"}, messages)
+	assert.Equal("details-v1-3618568057-xyz", logs.Entries[1].Pod)
+	assert.Equal("details", logs.Entries[1].Container)
+	assert.Equal("istio-proxy", logs.Entries[2].Container)
+
+	tailLines := int64(2)
+	logs, err = svc.GetWorkloadLogs("Namespace", "details-v1", &LogOptions{PodLogOptions: core_v1.PodLogOptions{Container: "details", TailLines: &tailLines}})
+	assert.NoError(err)
+	assert.Len(logs.Entries, 2)
+	assert.Equal("fourth", logs.Entries[0].Message)
+	assert.Equal("fifth", logs.Entries[1].Message)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
@@ -30,6 +31,7 @@ import (
 type WorkloadService struct {
 	prom          prometheus.ClientInterface
 	k8s           kubernetes.ClientInterface
+	loki          LokiLoader
 	businessLayer *Layer
 }
 
@@ -44,14 +46,43 @@ type LogEntry struct {
 	Severity      string `json:"severity,omitempty"`
 	Timestamp     string `json:"timestamp,omitempty"`
 	TimestampUnix int64  `json:"timestampUnix,omitempty"`
+	// Trace and span IDs found in the log line, see config.LogCorrelationConfig
+	TraceID string `json:"traceId,omitempty"`
+	SpanID  string `json:"spanId,omitempty"`
+	// Pod and container of the log line, when the logs of several pods or containers are merged
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	// Fields of the line when it is an Envoy access log
+	AccessLog *AccessLog `json:"accessLog,omitempty"`
+	// Exact time of the log line, to merge the logs of several pods
+	at time.Time
 }
 
 // LogOptions holds query parameter values
 type LogOptions struct {
 	Duration *time.Duration
+	// Keep only the log lines of this trace
+	TraceID string
+	// Keep only the log lines matching this regular expression
+	Filter *regexp.Regexp
+	// Keep only the Envoy access logs having one of these response flags, such as UF or UO
+	ResponseFlags []string
+	// Source of the logs, LogSourceKubernetes when empty
+	Source string
 	core_v1.PodLogOptions
 }
 
+// matches tells whether a log entry is kept by the trace, response flags and regular expression filters
+func (opts *LogOptions) matches(entry LogEntry) bool {
+	if opts.TraceID != "" && !sameTraceID(entry.TraceID, opts.TraceID) {
+		return false
+	}
+	if len(opts.ResponseFlags) > 0 && (entry.AccessLog == nil || !entry.AccessLog.hasResponseFlag(opts.ResponseFlags)) {
+		return false
+	}
+	return opts.Filter == nil || opts.Filter.MatchString(entry.Message)
+}
+
 var (
 	excludedWorkloads map[string]bool
 
@@ -114,6 +145,12 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 	}()
 
 	if includeServices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workload.HorizontalAutoscalers, workload.VerticalAutoscalers, workload.ScaledObjects = in.getAutoscalers(namespace, workload)
+		}()
+
 		var services []core_v1.Service
 		var err error
 		// Check if namespace is cached
@@ -142,15 +179,33 @@ func (in *WorkloadService) UpdateWorkload(namespace string, workloadName string,
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "UpdateWorkload")
 	defer promtimer.ObserveNow(&err)
 
+	// Versions of the workload held by the cache before the patch, to wait for the patched versions
+	staleVersions := map[string]string{}
+	staleConditions := map[string]cache.ObjectCondition{}
+	if kialiCache != nil {
+		for _, cachedType := range []string{kubernetes.DeploymentType, kubernetes.ReplicaSetType, kubernetes.StatefulSetType, kubernetes.PodType} {
+			if workloadType != "" && workloadType != cachedType {
+				continue
+			}
+			if uid, version, ok := kialiCache.GetObjectVersion(namespace, cachedType, workloadName); ok {
+				staleVersions[cachedType] = version
+				staleConditions[cachedType] = cache.ObjectChanged(uid, version)
+			}
+		}
+	}
+
 	// Identify controller and apply patch to workload
-	err = updateWorkload(in.businessLayer, namespace, workloadName, workloadType, jsonPatch)
+	patchedVersions, err := updateWorkload(in.businessLayer, namespace, workloadName, workloadType, jsonPatch)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache is stopped after a Create/Update/Delete operation to force a refresh
-	if kialiCache != nil && err == nil {
-		kialiCache.RefreshNamespace(namespace)
+	for cachedType, condition := range staleConditions {
+		// A patch changing nothing leaves the resource version as is: the cache gets no event to wait for
+		if version, patched := patchedVersions[cachedType]; !patched || version == staleVersions[cachedType] {
+			continue
+		}
+		waitForCache(namespace, cachedType, workloadName, condition)
 	}
 
 	// After the update we fetch the whole workload
@@ -237,15 +292,21 @@ func (in *WorkloadService) BuildLogOptionsCriteria(container string, duration st
 }
 
 func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOptions) (*PodLog, error) {
+	if opts.Source == LogSourceLoki {
+		return in.getLokiLogs(namespace, name, opts)
+	}
 	k8sOpts := opts.PodLogOptions
 	// the k8s API does not support "endTime/beforeTime". So for bounded time ranges we need to
 	// 1) discard the logs after sinceTime+duration
 	// 2) manually apply tailLines to the remaining logs
+	// Logs filtered by trace or regular expression get tailLines applied after the filter too
 	isBounded := opts.Duration != nil
 	tailLines := k8sOpts.TailLines
-	if isBounded {
+	manualTail := isBounded || opts.TraceID != "" || opts.Filter != nil || len(opts.ResponseFlags) > 0
+	if manualTail {
 		k8sOpts.TailLines = nil
 	}
+	correlation := newLogCorrelation(config.Get().ExternalServices.Tracing.LogCorrelation)
 
 	podLog, err := in.k8s.GetPodLogs(namespace, name, &k8sOpts)
 
@@ -263,65 +324,36 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	}
 
 	for _, line := range lines {
-		entry := LogEntry{
-			Message:       "",
-			Timestamp:     "",
-			TimestampUnix: 0,
-			Severity:      "INFO",
-		}
-
-		splitted := strings.SplitN(line, " ", 2)
-		if len(splitted) != 2 {
-			log.Debugf("Skipping unexpected log line [%s]", line)
+		entry, at, ok := parseLogLine(line, correlation)
+		if !ok {
 			continue
 		}
+		entry.at = at
 
-		// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
-		splittedTimestamp := strings.Split(splitted[0], ".")
-		if len(splittedTimestamp) == 1 {
-			entry.Timestamp = splittedTimestamp[0]
-		} else {
-			entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
-		}
-
-		entry.Message = strings.TrimSpace(splitted[1])
-		if entry.Message == "" {
-			log.Debugf("Skipping empty log line [%s]", line)
-			continue
+		parsed := time.Unix(entry.TimestampUnix, 0).UTC()
+		if startTime == nil {
+			startTime = &parsed
 		}
 
-		parsed, err := time.Parse(time.RFC3339, entry.Timestamp)
-		if err == nil {
-			if startTime == nil {
-				startTime = &parsed
+		if isBounded {
+			if endTime == nil {
+				end := parsed.Add(*opts.Duration)
+				endTime = &end
 			}
 
-			if isBounded {
-				if endTime == nil {
-					end := parsed.Add(*opts.Duration)
-					endTime = &end
-				}
-
-				if parsed.After(*endTime) {
-					break
-				}
+			if parsed.After(*endTime) {
+				break
 			}
-
-			entry.TimestampUnix = parsed.Unix()
-		} else {
-			log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
-			continue
 		}
 
-		severity := severityRegexp.FindString(line)
-		if severity != "" {
-			entry.Severity = strings.ToUpper(severity)
+		if !opts.matches(entry) {
+			continue
 		}
 
 		entries = append(entries, entry)
 	}
 
-	if isBounded && tailLines != nil && len(entries) > int(*tailLines) {
+	if manualTail && tailLines != nil && len(entries) > int(*tailLines) {
 		entries = entries[len(entries)-int(*tailLines):]
 	}
 
@@ -332,6 +364,56 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	return &message, err
 }
 
+// parseLogLine parses a log line prefixed by its timestamp, ok being false when the line is empty or unexpected. The
+// exact time of the line is returned too, the timestamp of the entry being truncated to the second.
+func parseLogLine(line string, correlation *logCorrelation) (entry LogEntry, at time.Time, ok bool) {
+	entry = LogEntry{
+		Message:       "",
+		Timestamp:     "",
+		TimestampUnix: 0,
+		Severity:      "INFO",
+	}
+
+	splitted := strings.SplitN(line, " ", 2)
+	if len(splitted) != 2 {
+		log.Debugf("Skipping unexpected log line [%s]", line)
+		return entry, at, false
+	}
+
+	// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
+	splittedTimestamp := strings.Split(splitted[0], ".")
+	if len(splittedTimestamp) == 1 {
+		entry.Timestamp = splittedTimestamp[0]
+	} else {
+		entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
+	}
+
+	entry.Message = strings.TrimSpace(splitted[1])
+	if entry.Message == "" {
+		log.Debugf("Skipping empty log line [%s]", line)
+		return entry, at, false
+	}
+
+	parsed, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
+		return entry, at, false
+	}
+	entry.TimestampUnix = parsed.Unix()
+	if at, err = time.Parse(time.RFC3339Nano, splitted[0]); err != nil {
+		at = parsed
+	}
+
+	entry.TraceID, entry.SpanID = correlation.parse(entry.Message)
+	entry.AccessLog = parseAccessLog(entry.Message)
+
+	severity := severityRegexp.FindString(line)
+	if severity != "" {
+		entry.Severity = strings.ToUpper(severity)
+	}
+	return entry, at, true
+}
+
 // GetPodLogs returns pod logs given the provided options
 func (in *WorkloadService) GetPodLogs(namespace, name string, opts *LogOptions) (*PodLog, error) {
 	return in.getParsedLogs(namespace, name, opts)
@@ -346,6 +428,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	var fulset []apps_v1.StatefulSet
 	var jbs []batch_v1.Job
 	var conjbs []batch_v1beta1.CronJob
+	var rollouts []kubernetes.Rollout
 
 	ws := models.Workloads{}
 
@@ -356,8 +439,8 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(8)
-	errChan := make(chan error, 8)
+	wg.Add(9)
+	errChan := make(chan error, 9)
 
 	go func() {
 		defer wg.Done()
@@ -471,6 +554,18 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		var err error
+		if isWorkloadIncluded(kubernetes.RolloutType) {
+			rollouts, err = layer.k8s.GetRollouts(namespace)
+			if err != nil {
+				log.Errorf("Error fetching Rollouts per namespace %s: %s", namespace, err)
+				errChan <- err
+			}
+		}
+	}()
+
 	wg.Wait()
 	if len(errChan) != 0 {
 		err := <-errChan
@@ -651,6 +746,15 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 			controllers[fs.Name] = "StatefulSet"
 		}
 	}
+	for _, ro := range rollouts {
+		selectorCheck := true
+		if selector != nil {
+			selectorCheck = selector.Matches(labels.Set(ro.Spec.Template.Labels))
+		}
+		if _, exist := controllers[ro.Name]; !exist && selectorCheck {
+			controllers[ro.Name] = kubernetes.RolloutType
+		}
+	}
 
 	// Build workloads from controllers
 	var cnames []string
@@ -757,6 +861,23 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 				log.Errorf("Workload %s is not found as StatefulSet", cname)
 				cnFound = false
 			}
+		case kubernetes.RolloutType:
+			found := false
+			iFound := -1
+			for i, ro := range rollouts {
+				if ro.Name == cname {
+					found = true
+					iFound = i
+					break
+				}
+			}
+			if found {
+				w.SetPods(filterRolloutPods(&rollouts[iFound], pods))
+				w.ParseRollout(&rollouts[iFound])
+			} else {
+				log.Errorf("Workload %s is not found as Rollout", cname)
+				cnFound = false
+			}
 		case "Pod":
 			found := false
 			iFound := -1
@@ -844,6 +965,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	var fulset *apps_v1.StatefulSet
 	var jbs []batch_v1.Job
 	var conjbs []batch_v1beta1.CronJob
+	var rollouts []kubernetes.Rollout
 
 	wl := &models.Workload{
 		Pods:     models.Pods{},
@@ -857,8 +979,8 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(8)
-	errChan := make(chan error, 8)
+	wg.Add(9)
+	errChan := make(chan error, 9)
 
 	// Pods are always fetched for all workload types
 	go func() {
@@ -1003,6 +1125,22 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		// Check if workloadType is passed
+		if workloadType != "" && workloadType != kubernetes.RolloutType {
+			return
+		}
+		var err error
+		if isWorkloadIncluded(kubernetes.RolloutType) {
+			rollouts, err = layer.k8s.GetRollouts(namespace)
+			if err != nil {
+				log.Errorf("Error fetching Rollouts per namespace %s: %s", namespace, err)
+				errChan <- err
+			}
+		}
+	}()
+
 	wg.Wait()
 	if len(errChan) != 0 {
 		err := <-errChan
@@ -1155,6 +1293,11 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 			controllers[fulset.Name] = "StatefulSet"
 		}
 	}
+	for _, ro := range rollouts {
+		if _, exist := controllers[ro.Name]; !exist && ro.Name == workloadName {
+			controllers[ro.Name] = kubernetes.RolloutType
+		}
+	}
 
 	// Build workload from controllers
 
@@ -1230,6 +1373,23 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 				log.Errorf("Workload %s is not found as StatefulSet", workloadName)
 				cnFound = false
 			}
+		case kubernetes.RolloutType:
+			found := false
+			iFound := -1
+			for i, ro := range rollouts {
+				if ro.Name == workloadName {
+					found = true
+					iFound = i
+					break
+				}
+			}
+			if found {
+				w.SetPods(filterRolloutPods(&rollouts[iFound], pods))
+				w.ParseRollout(&rollouts[iFound])
+			} else {
+				log.Errorf("Workload %s is not found as Rollout", workloadName)
+				cnFound = false
+			}
 		case "Pod":
 			found := false
 			iFound := -1
@@ -1311,11 +1471,13 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	return wl, kubernetes.NewNotFound(workloadName, "Kiali", "Workload")
 }
 
-func updateWorkload(layer *Layer, namespace string, workloadName string, workloadType string, jsonPatch string) error {
+// updateWorkload patches the workloads of the name, of any type unless given, and returns their resource versions
+// after the patch, by type
+func updateWorkload(layer *Layer, namespace string, workloadName string, workloadType string, jsonPatch string) (map[string]string, error) {
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
 	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
 	if _, err := layer.Namespace.GetNamespace(namespace); err != nil {
-		return err
+		return nil, err
 	}
 
 	workloadTypes := []string{
@@ -1347,13 +1509,21 @@ func updateWorkload(layer *Layer, namespace string, workloadName string, workloa
 	wg := sync.WaitGroup{}
 	wg.Add(len(workloadTypes))
 	errChan := make(chan error, len(workloadTypes))
+	versions := map[string]string{}
+	var versionsLock sync.Mutex
 
 	for _, workloadType := range workloadTypes {
 		go func(wkType string) {
 			defer wg.Done()
 			var err error
+			var version string
 			if isWorkloadIncluded(wkType) {
-				err = layer.k8s.UpdateWorkload(namespace, workloadName, wkType, jsonPatch)
+				version, err = layer.k8s.UpdateWorkload(namespace, workloadName, wkType, jsonPatch)
+				if err == nil {
+					versionsLock.Lock()
+					versions[wkType] = version
+					versionsLock.Unlock()
+				}
 			}
 			if err != nil {
 				if !errors.IsNotFound(err) {
@@ -1367,10 +1537,10 @@ func updateWorkload(layer *Layer, namespace string, workloadName string, workloa
 	wg.Wait()
 	if len(errChan) != 0 {
 		err := <-errChan
-		return err
+		return nil, err
 	}
 
-	return nil
+	return versions, nil
 }
 
 // KIALI-1730
@@ -1380,6 +1550,7 @@ func updateWorkload(layer *Layer, namespace string, workloadName string, workloa
 // But Istio only identifies one controller as workload (it doesn't note which one).
 // Kiali can select one on the list of workloads and other in the details and this should be consistent.
 var controllerOrder = map[string]int{
+	"Rollout":               7,
 	"Deployment":            6,
 	"DeploymentConfig":      5,
 	"ReplicaSet":            4,
@@ -1417,3 +1588,14 @@ func (in *WorkloadService) GetWorkloadAppName(namespace, workload string) (strin
 	app := wkd.Labels[appLabelName]
 	return app, nil
 }
+
+// filterRolloutPods returns the pods of all the revisions of a Rollout, matched by its selector or else by its template labels
+func filterRolloutPods(rollout *kubernetes.Rollout, pods []core_v1.Pod) []core_v1.Pod {
+	if rollout.Spec.Selector != nil {
+		if selector, err := meta_v1.LabelSelectorAsSelector(rollout.Spec.Selector); err == nil {
+			return kubernetes.FilterPodsForSelector(selector, pods)
+		}
+	}
+	selector := labels.Set(rollout.Spec.Template.Labels).AsSelector()
+	return kubernetes.FilterPodsForSelector(selector, pods)
+}
@@ -0,0 +1,152 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Label of the pods of the ztunnel DaemonSet, in the Istio namespace
+const ztunnelLabelSelector = "app=ztunnel"
+
+// GetZtunnelConfig reads the config dumps of all the ztunnels of the cluster and aggregates the workloads, services
+// and policies of the namespaces, optionally of a workload only, reporting the entries the ztunnels disagree on
+func (in *ProxyStatus) GetZtunnelConfig(namespaces []string, workload string) (*models.ZtunnelConfig, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyStatus", "GetZtunnelConfig")
+	defer promtimer.ObserveNow(&err)
+
+	istioNamespace := config.Get().IstioNamespace
+	var pods []core_v1.Pod
+	if IsNamespaceCached(istioNamespace) {
+		pods, err = kialiCache.GetPods(istioNamespace, ztunnelLabelSelector)
+	} else {
+		pods, err = in.k8s.GetPods(istioNamespace, ztunnelLabelSelector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	filter := ztunnelFilter{namespaces: map[string]bool{}, workload: workload}
+	for _, ns := range namespaces {
+		filter.namespaces[ns] = true
+	}
+
+	ztunnels := []models.ZtunnelPod{}
+	dumps := map[string]*kubernetes.ZtunnelConfigDump{}
+	// The config dumps are fetched one after the other: the port-forwards to the admins all listen on the same port
+	for _, pod := range pods {
+		if pod.Status.Phase != core_v1.PodRunning {
+			continue
+		}
+		ztunnel := models.ZtunnelPod{Pod: pod.Name, Node: pod.Spec.NodeName}
+		dump, err := in.k8s.GetZtunnelConfigDump(istioNamespace, pod.Name)
+		if err != nil {
+			log.Debugf("Error fetching the config dump of ztunnel %s: %v", pod.Name, err)
+			ztunnel.Error = err.Error()
+		} else {
+			dumps[pod.Name] = dump
+		}
+		ztunnels = append(ztunnels, ztunnel)
+	}
+
+	return aggregateZtunnelDumps(ztunnels, dumps, filter), nil
+}
+
+// ztunnelFilter selects the entries of the config dumps of the ztunnels
+type ztunnelFilter struct {
+	namespaces map[string]bool
+	workload   string
+}
+
+func aggregateZtunnelDumps(ztunnels []models.ZtunnelPod, dumps map[string]*kubernetes.ZtunnelConfigDump, filter ztunnelFilter) *models.ZtunnelConfig {
+	aggregated := &models.ZtunnelConfig{
+		Ztunnels:  ztunnels,
+		Workloads: []models.ZtunnelWorkload{},
+		Services:  []models.ZtunnelService{},
+		Policies:  []models.ZtunnelPolicy{},
+	}
+	workloads := map[string]int{}
+	services := map[string]int{}
+	policies := map[string]int{}
+
+	compared := []string{}
+	snapshots := map[string]map[driftKey]interface{}{}
+	for _, ztunnel := range ztunnels {
+		dump, ok := dumps[ztunnel.Pod]
+		if !ok {
+			continue
+		}
+		compared = append(compared, ztunnel.Pod)
+		snapshot := map[driftKey]interface{}{}
+		snapshots[ztunnel.Pod] = snapshot
+
+		for _, w := range dump.Workloads {
+			if !filter.namespaces[w.Namespace] || (filter.workload != "" && w.WorkloadName != filter.workload) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", w.Namespace, w.Name)
+			snapshot[driftKey{models.ZtunnelDriftWorkload, key}] = w
+			if i, ok := workloads[w.UID]; ok {
+				aggregated.Workloads[i].Ztunnels = append(aggregated.Workloads[i].Ztunnels, ztunnel.Pod)
+				continue
+			}
+			workloads[w.UID] = len(aggregated.Workloads)
+			aggregated.Workloads = append(aggregated.Workloads, models.ZtunnelWorkload{ZtunnelWorkload: w, Ztunnels: []string{ztunnel.Pod}})
+		}
+		for _, s := range dump.Services {
+			if !filter.namespaces[s.Namespace] {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", s.Namespace, s.Hostname)
+			snapshot[driftKey{models.ZtunnelDriftService, key}] = s
+			if i, ok := services[key]; ok {
+				aggregated.Services[i].Ztunnels = append(aggregated.Services[i].Ztunnels, ztunnel.Pod)
+				continue
+			}
+			services[key] = len(aggregated.Services)
+			aggregated.Services = append(aggregated.Services, models.ZtunnelService{ZtunnelService: s, Ztunnels: []string{ztunnel.Pod}})
+		}
+		for _, p := range dump.Policies {
+			if !filter.namespaces[p.Namespace] {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+			snapshot[driftKey{models.ZtunnelDriftPolicy, key}] = p
+			if i, ok := policies[key]; ok {
+				aggregated.Policies[i].Ztunnels = append(aggregated.Policies[i].Ztunnels, ztunnel.Pod)
+				continue
+			}
+			policies[key] = len(aggregated.Policies)
+			aggregated.Policies = append(aggregated.Policies, models.ZtunnelPolicy{ZtunnelPolicy: p, Ztunnels: []string{ztunnel.Pod}})
+		}
+	}
+
+	sort.Slice(aggregated.Workloads, func(i, j int) bool {
+		wi, wj := aggregated.Workloads[i], aggregated.Workloads[j]
+		if wi.Namespace != wj.Namespace {
+			return wi.Namespace < wj.Namespace
+		}
+		return wi.Name < wj.Name
+	})
+	sort.Slice(aggregated.Services, func(i, j int) bool {
+		return aggregated.Services[i].Hostname < aggregated.Services[j].Hostname
+	})
+	sort.Slice(aggregated.Policies, func(i, j int) bool {
+		pi, pj := aggregated.Policies[i], aggregated.Policies[j]
+		if pi.Namespace != pj.Namespace {
+			return pi.Namespace < pj.Namespace
+		}
+		return pi.Name < pj.Name
+	})
+	aggregated.Drifts = diffSnapshots(compared, snapshots)
+	return aggregated
+}
@@ -0,0 +1,85 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeZtunnelPod(name, node string, phase core_v1.PodPhase) core_v1.Pod {
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "istio-system"},
+		Spec:       core_v1.PodSpec{NodeName: node},
+		Status:     core_v1.PodStatus{Phase: phase},
+	}
+}
+
+func fakeZtunnelDump(reviewsStatus string) *kubernetes.ZtunnelConfigDump {
+	return &kubernetes.ZtunnelConfigDump{
+		Workloads: []kubernetes.ZtunnelWorkload{
+			{UID: "uid-reviews", Name: "reviews-v1-abc", Namespace: "bookinfo", WorkloadName: "reviews-v1", Status: reviewsStatus},
+			{UID: "uid-ratings", Name: "ratings-v1-def", Namespace: "bookinfo", WorkloadName: "ratings-v1", Status: "Healthy"},
+			{UID: "uid-istiod", Name: "istiod-ghi", Namespace: "istio-system", WorkloadName: "istiod", Status: "Healthy"},
+		},
+		Services: []kubernetes.ZtunnelService{
+			{Name: "reviews", Namespace: "bookinfo", Hostname: "reviews.bookinfo.svc.cluster.local"},
+		},
+		Policies: []kubernetes.ZtunnelPolicy{
+			{Name: "deny-all", Namespace: "bookinfo", Scope: "Namespace", Action: "Deny"},
+		},
+	}
+}
+
+func TestGetZtunnelConfig(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPods", "istio-system", "app=ztunnel").Return([]core_v1.Pod{
+		fakeZtunnelPod("ztunnel-c", "node-c", core_v1.PodRunning),
+		fakeZtunnelPod("ztunnel-a", "node-a", core_v1.PodRunning),
+		fakeZtunnelPod("ztunnel-b", "node-b", core_v1.PodRunning),
+		fakeZtunnelPod("ztunnel-d", "node-d", core_v1.PodPending),
+	}, nil)
+	k8s.On("GetZtunnelConfigDump", "istio-system", "ztunnel-a").Return(fakeZtunnelDump("Healthy"), nil)
+	k8s.On("GetZtunnelConfigDump", "istio-system", "ztunnel-b").Return(fakeZtunnelDump("Unhealthy"), nil)
+	k8s.On("GetZtunnelConfigDump", "istio-system", "ztunnel-c").Return((*kubernetes.ZtunnelConfigDump)(nil), errors.New("connection refused"))
+	service := ProxyStatus{k8s: k8s}
+
+	dump, err := service.GetZtunnelConfig([]string{"bookinfo"}, "")
+	assert.NoError(err)
+	assert.Equal([]models.ZtunnelPod{
+		{Pod: "ztunnel-a", Node: "node-a"},
+		{Pod: "ztunnel-b", Node: "node-b"},
+		{Pod: "ztunnel-c", Node: "node-c", Error: "connection refused"},
+	}, dump.Ztunnels)
+
+	// The entries of the other namespaces are filtered out, the ones known by several ztunnels aggregated
+	assert.Len(dump.Workloads, 2)
+	assert.Equal("ratings-v1-def", dump.Workloads[0].Name)
+	assert.Equal([]string{"ztunnel-a", "ztunnel-b"}, dump.Workloads[0].Ztunnels)
+	assert.Len(dump.Services, 1)
+	assert.Equal([]string{"ztunnel-a", "ztunnel-b"}, dump.Services[0].Ztunnels)
+	assert.Len(dump.Policies, 1)
+	assert.Equal("Deny", dump.Policies[0].Action)
+
+	// The ztunnels disagree on the status of reviews
+	assert.Len(dump.Drifts, 1)
+	assert.Equal(models.ZtunnelDriftWorkload, dump.Drifts[0].Category)
+	assert.Equal("bookinfo/reviews-v1-abc", dump.Drifts[0].Key)
+	assert.Equal("Unhealthy", dump.Drifts[0].Values["ztunnel-b"].(kubernetes.ZtunnelWorkload).Status)
+
+	dump, err = service.GetZtunnelConfig([]string{"bookinfo"}, "ratings-v1")
+	assert.NoError(err)
+	assert.Len(dump.Workloads, 1)
+	assert.Equal("ratings-v1", dump.Workloads[0].WorkloadName)
+	assert.Empty(dump.Drifts)
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
+	"regexp"
 	"sync"
 
 	"gopkg.in/yaml.v2"
@@ -24,6 +27,9 @@ const (
 
 	// Login Token signing key used to prepare the token for user login
 	EnvLoginTokenSigningKey = "LOGIN_TOKEN_SIGNING_KEY"
+
+	// Password of the Redis server of the results cache
+	EnvResultsCacheRedisPassword = "RESULTS_CACHE_REDIS_PASSWORD"
 )
 
 // The versions that Kiali requires
@@ -40,6 +46,7 @@ const (
 	AuthStrategyAnonymous = "anonymous"
 	AuthStrategyToken     = "token"
 	AuthStrategyOpenId    = "openid"
+	AuthStrategyHeader    = "header"
 
 	TokenCookieName             = "kiali-token"
 	AuthStrategyOpenshiftIssuer = "kiali-openshift"
@@ -50,6 +57,30 @@ const (
 	AuthTypeBasic  = "basic"
 	AuthTypeBearer = "bearer"
 	AuthTypeNone   = "none"
+
+	// These constants are used for the per-cluster auth of remote clusters; not for the Kiali home cluster
+	ClusterAuthStrategyOpenIdExchange = "openid_exchange"
+	ClusterAuthStrategyServiceAccount = "service_account"
+	ClusterAuthStrategyToken          = "token"
+)
+
+// The providers of the tracing API
+const (
+	TracingProviderJaeger = "jaeger"
+	TracingProviderOTLP   = "otlp"
+)
+
+// The valid scopes of the Kiali cache
+const (
+	CacheScopeAccessibleNamespaces = "accessible_namespaces"
+	CacheScopeOnDemand             = "on_demand"
+)
+
+// Backends of the cache of computed results
+const (
+	ResultsCacheBackendMemory = "memory"
+	ResultsCacheBackendNone   = "none"
+	ResultsCacheBackendRedis  = "redis"
 )
 
 const (
@@ -63,19 +94,75 @@ var rwMutex sync.RWMutex
 
 // Server configuration
 type Server struct {
-	Address                    string `yaml:",omitempty"`
-	AuditLog                   bool   `yaml:"audit_log,omitempty"` // When true, allows additional audit logging on Write operations
-	CORSAllowAll               bool   `yaml:"cors_allow_all,omitempty"`
-	GzipEnabled                bool   `yaml:"gzip_enabled,omitempty"`
-	MetricsEnabled             bool   `yaml:"metrics_enabled,omitempty"`
-	MetricsPort                int    `yaml:"metrics_port,omitempty"`
-	Port                       int    `yaml:",omitempty"`
-	StaticContentRootDirectory string `yaml:"static_content_root_directory,omitempty"`
-	WebFQDN                    string `yaml:"web_fqdn,omitempty"`
-	WebPort                    string `yaml:"web_port,omitempty"`
-	WebRoot                    string `yaml:"web_root,omitempty"`
-	WebHistoryMode             string `yaml:"web_history_mode,omitempty"`
-	WebSchema                  string `yaml:"web_schema,omitempty"`
+	Address                    string         `yaml:",omitempty"`
+	AuditLog                   bool           `yaml:"audit_log,omitempty"` // When true, allows additional audit logging on Write operations
+	CORSAllowAll               bool           `yaml:"cors_allow_all,omitempty"`
+	GzipEnabled                bool           `yaml:"gzip_enabled,omitempty"`
+	H2CEnabled                 bool           `yaml:"h2c_enabled,omitempty"`   // When true, HTTP/2 is accepted in cleartext (h2c) when TLS is not configured
+	HTTP2Enabled               bool           `yaml:"http2_enabled,omitempty"` // When true, HTTP/2 is negotiated with the clients over TLS
+	IdleTimeout                int            `yaml:"idle_timeout,omitempty"`  // Seconds a keep-alive connection waits for the next request
+	MaxHeaderBytes             int            `yaml:"max_header_bytes,omitempty"`
+	MetricsEnabled             bool           `yaml:"metrics_enabled,omitempty"`
+	MetricsPort                int            `yaml:"metrics_port,omitempty"`
+	Port                       int            `yaml:",omitempty"`
+	Profiler                   ServerProfiler `yaml:"profiler,omitempty"`
+	ReadTimeout                int            `yaml:"read_timeout,omitempty"`         // Seconds to read a request, body included
+	ShutdownDrainDelay         int            `yaml:"shutdown_drain_delay,omitempty"` // Seconds the readiness fails, with the requests still served, before Kiali stops
+	ShutdownTimeout            int            `yaml:"shutdown_timeout,omitempty"`     // Seconds given to the requests in flight to complete when Kiali stops
+	StaticContentRootDirectory string         `yaml:"static_content_root_directory,omitempty"`
+	TLS                        ServerTLS      `yaml:"tls,omitempty"`
+	WebFQDN                    string         `yaml:"web_fqdn,omitempty"`
+	WebPort                    string         `yaml:"web_port,omitempty"`
+	WebRoot                    string         `yaml:"web_root,omitempty"`
+	WebHistoryMode             string         `yaml:"web_history_mode,omitempty"`
+	WebSchema                  string         `yaml:"web_schema,omitempty"`
+	WriteTimeout               int            `yaml:"write_timeout,omitempty"` // Seconds to write a response, from the end of the request
+}
+
+// ServerProfiler exposes the pprof profiles of Kiali, i.e. its goroutines and allocations, to its administrators:
+// the users allowed to update the Kiali deployment.
+type ServerProfiler struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// ServerTLS restricts the TLS connections accepted by the server, when TLS is configured with the identity of Kiali
+type ServerTLS struct {
+	// Minimum version of TLS accepted from the clients: 1.2 or 1.3
+	MinVersion string `yaml:"min_version,omitempty"`
+	// Names of the cipher suites accepted for TLS 1.2, i.e. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The defaults of Go
+	// are used when empty. The cipher suites of TLS 1.3 are not configurable.
+	CipherSuites []string `yaml:"cipher_suites,omitempty"`
+}
+
+// TLSVersion returns the minimum TLS version, TLS 1.2 when not set
+func (t ServerTLS) TLSVersion() (uint16, error) {
+	switch t.MinVersion {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version [%s], expected 1.2 or 1.3", t.MinVersion)
+}
+
+// CipherSuiteIDs returns the ids of the cipher suites, nil when not set. The insecure cipher suites are rejected.
+func (t ServerTLS) CipherSuiteIDs() ([]uint16, error) {
+	if len(t.CipherSuites) == 0 {
+		return nil, nil
+	}
+	secure := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(t.CipherSuites))
+	for _, name := range t.CipherSuites {
+		id, ok := secure[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite [%s]", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
 // Auth provides authentication data for external services
@@ -104,16 +191,85 @@ type PrometheusConfig struct {
 	// Enable cache for Prometheus queries
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
 	// Global cache expiration expressed in seconds
-	CacheExpiration int    `yaml:"cache_expiration:omitempty"`
-	URL             string `yaml:"url,omitempty"`
+	CacheExpiration int `yaml:"cache_expiration:omitempty"`
+	// Endpoint, credentials and tenant of the queries for the metrics of each cluster, by cluster name.
+	// Queries for clusters not listed use the global settings.
+	Clusters map[string]PrometheusClusterConfig `yaml:"clusters,omitempty"`
+	// Headers added to every query, i.e. to route the queries through a proxy
+	CustomHeaders map[string]string `yaml:"custom_headers,omitempty"`
+	// Maximum number of data points of each series returned by the range queries. Longer ranges are queried with
+	// a larger step. Zero disables the limit.
+	MaxDataPoints int `yaml:"max_data_points,omitempty"`
+	// Duration, in seconds, of the results of the queries in the query cache. The same query issued by several
+	// users within this duration is sent once to Prometheus.
+	QueryCacheDuration int `yaml:"query_cache_duration,omitempty"`
+	// Enable cache for the results of any query to Prometheus, keyed by query and time range. Disabled by default:
+	// it comes on top of the cache of the request rates (cache_enabled), for the large meshes watched by many users.
+	QueryCacheEnabled bool `yaml:"query_cache_enabled,omitempty"`
+	// Recording rules pre-aggregating the rates queried by Kiali. When the recorded metric exists, Kiali queries it
+	// instead of computing the rate of the raw metric.
+	RecordingRules []RecordingRule `yaml:"recording_rules,omitempty"`
+	// Tenant of the metrics in multi-tenant metric stores (Mimir, Cortex, Thanos), sent in the X-Scope-OrgID header
+	TenantID string `yaml:"tenant_id,omitempty"`
+	// Query the downsampled data of Thanos, with the max_source_resolution parameter, when the step of a range
+	// query is large enough
+	ThanosDownsampling bool   `yaml:"thanos_downsampling,omitempty"`
+	URL                string `yaml:"url,omitempty"`
+}
+
+// RecordingRule describes a recording rule recording the rate of a metric, i.e.
+// record: kiali:request_total:rate5m, expr: sum(rate(istio_requests_total[5m])) by (reporter, source_workload, ...)
+type RecordingRule struct {
+	// Rate interval of the rule, i.e. 5m. Queries with other rate intervals use the raw metric.
+	Interval string `yaml:"interval"`
+	// Labels kept by the rule. Queries filtering or grouping by other labels use the raw metric. When empty, the
+	// rule keeps all the labels of the metric.
+	Labels []string `yaml:"labels,omitempty"`
+	// Metric whose rate is recorded, i.e. istio_requests_total
+	Metric string `yaml:"metric"`
+	// Name of the recorded metric, i.e. kiali:request_total:rate5m
+	Record string `yaml:"record"`
+}
+
+// PrometheusClusterConfig overrides the endpoint, the credentials and the tenant of the Prometheus queries for a cluster
+type PrometheusClusterConfig struct {
+	Auth          Auth              `yaml:"auth,omitempty"`
+	CustomHeaders map[string]string `yaml:"custom_headers,omitempty"`
+	TenantID      string            `yaml:"tenant_id,omitempty"`
+	// Prometheus or Thanos storing the metrics of the cluster. When empty, the metrics of the cluster are queried
+	// from the global URL.
+	URL string `yaml:"url,omitempty"`
+}
+
+// AlertmanagerConfig describes the Alertmanager whose firing alerts are shown on the services, workloads and
+// namespaces they are about
+type AlertmanagerConfig struct {
+	Auth         Auth   `yaml:"auth,omitempty"`
+	Enabled      bool   `yaml:"enabled"`
+	InClusterURL string `yaml:"in_cluster_url"`
+	// Labels of the alerts holding the namespace, service, workload and app they are about
+	Labels AlertmanagerLabelsConfig `yaml:"labels,omitempty"`
+	// URL of the Alertmanager UI, to link the alerts
+	URL string `yaml:"url"`
+}
+
+// AlertmanagerLabelsConfig holds the names of the labels mapping an alert to the objects of Kiali
+type AlertmanagerLabelsConfig struct {
+	App       string `yaml:"app"`
+	Namespace string `yaml:"namespace"`
+	Service   string `yaml:"service"`
+	Workload  string `yaml:"workload"`
 }
 
 // CustomDashboardsConfig describes configuration specific to Custom Dashboards
 type CustomDashboardsConfig struct {
-	Enabled         bool             `yaml:"enabled,omitempty"`
-	IsCoreComponent bool             `yaml:"is_core_component,omitempty"`
-	NamespaceLabel  string           `yaml:"namespace_label,omitempty"`
-	Prometheus      PrometheusConfig `yaml:"prometheus,omitempty"`
+	// Label selector of the ConfigMaps defining dashboards, in any namespace. Empty disables the dashboards of the
+	// ConfigMaps.
+	ConfigMapSelector string           `yaml:"config_map_selector,omitempty"`
+	Enabled           bool             `yaml:"enabled,omitempty"`
+	IsCoreComponent   bool             `yaml:"is_core_component,omitempty"`
+	NamespaceLabel    string           `yaml:"namespace_label,omitempty"`
+	Prometheus        PrometheusConfig `yaml:"prometheus,omitempty"`
 }
 
 // GrafanaConfig describes configuration used for Grafana links
@@ -139,26 +295,84 @@ type GrafanaVariablesConfig struct {
 	Workload  string `yaml:"workload" json:"workload,omitempty"`
 }
 
+// LokiConfig describes the Loki storing the logs of the pods, queried instead of the Kubernetes API when requested so
+// that the logs of restarted pods remain available and long time ranges don't load the kubelets
+type LokiConfig struct {
+	Auth         Auth   `yaml:"auth,omitempty"`
+	Enabled      bool   `yaml:"enabled"`
+	InClusterURL string `yaml:"in_cluster_url"`
+	// Labels of the log streams holding the namespace, pod and container of the lines
+	Labels LokiLabelsConfig `yaml:"labels,omitempty"`
+	// Maximum number of log lines returned by a query
+	MaxEntries int `yaml:"max_entries,omitempty"`
+	// Tenant of the queries, sent in the X-Scope-OrgID header to multi-tenant Lokis
+	TenantID string `yaml:"tenant_id,omitempty"`
+	URL      string `yaml:"url"`
+}
+
+// LokiLabelsConfig holds the names of the labels identifying the logs of a container in Loki
+type LokiLabelsConfig struct {
+	Container string `yaml:"container,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+	Pod       string `yaml:"pod,omitempty"`
+}
+
 // TracingConfig describes configuration used for tracing links
 type TracingConfig struct {
-	Auth                 Auth     `yaml:"auth"`
-	Enabled              bool     `yaml:"enabled"` // Enable Jaeger in Kiali
-	InClusterURL         string   `yaml:"in_cluster_url"`
-	IsCoreComponent      bool     `yaml:"is_core_component"`
-	NamespaceSelector    bool     `yaml:"namespace_selector"`
-	URL                  string   `yaml:"url"`
-	WhiteListIstioSystem []string `yaml:"whitelist_istio_system"`
+	Auth Auth `yaml:"auth"`
+	// Tracing backends storing the traces of other clusters, by cluster name, i.e. Tempo for a production cluster
+	// and Jaeger for a staging cluster. The traces of clusters not listed are queried from the global backend.
+	Clusters        map[string]TracingClusterConfig `yaml:"clusters,omitempty"`
+	Enabled         bool                            `yaml:"enabled"` // Enable Jaeger in Kiali
+	InClusterURL    string                          `yaml:"in_cluster_url"`
+	IsCoreComponent bool                            `yaml:"is_core_component"`
+	// Fields holding the trace and span IDs in the logs of the pods
+	LogCorrelation    LogCorrelationConfig `yaml:"log_correlation,omitempty"`
+	NamespaceSelector bool                 `yaml:"namespace_selector"`
+	// Provider of the API queried for the traces: "jaeger", or "otlp" for the query API v3 of Jaeger v2, returning
+	// the traces in the OTLP format
+	Provider             string      `yaml:"provider"`
+	Tempo                TempoConfig `yaml:"tempo"`
+	URL                  string      `yaml:"url"`
+	WhiteListIstioSystem []string    `yaml:"whitelist_istio_system"`
+}
+
+// TracingClusterConfig describes the tracing backend storing the traces of a cluster. Backends without credentials
+// or provider use the global ones.
+type TracingClusterConfig struct {
+	Auth         Auth        `yaml:"auth,omitempty"`
+	InClusterURL string      `yaml:"in_cluster_url,omitempty"`
+	Provider     string      `yaml:"provider,omitempty"`
+	Tempo        TempoConfig `yaml:"tempo,omitempty"`
+	URL          string      `yaml:"url,omitempty"`
+}
+
+// LogCorrelationConfig describes the fields of the log lines holding the trace and span IDs, i.e. the keys of JSON
+// logs or of key=value logs. W3C traceparent values are recognized in any log line.
+type LogCorrelationConfig struct {
+	SpanIDFields  []string `yaml:"span_id_fields,omitempty"`
+	TraceIDFields []string `yaml:"trace_id_fields,omitempty"`
+}
+
+// TempoConfig describes the HTTP API of Tempo, queried for TraceQL searches. The Jaeger-compatible API of the tracing
+// configuration is still used for everything else. TraceQL searches are disabled when no URL is set.
+type TempoConfig struct {
+	InClusterURL string `yaml:"in_cluster_url"`
+	URL          string `yaml:"url"`
 }
 
 // IstioConfig describes configuration used for istio links
 type IstioConfig struct {
-	ComponentStatuses        ComponentStatuses `yaml:"component_status,omitempty"`
-	ConfigMapName            string            `yaml:"config_map_name,omitempty"`
-	EnvoyAdminLocalPort      int               `yaml:"envoy_admin_local_port,omitempty"`
-	IstioIdentityDomain      string            `yaml:"istio_identity_domain,omitempty"`
-	IstioInjectionAnnotation string            `yaml:"istio_injection_annotation,omitempty"`
-	IstioSidecarAnnotation   string            `yaml:"istio_sidecar_annotation,omitempty"`
-	UrlServiceVersion        string            `yaml:"url_service_version"`
+	ComponentStatuses ComponentStatuses `yaml:"component_status,omitempty"`
+	ConfigMapName     string            `yaml:"config_map_name,omitempty"`
+	// Deprecated: ignored, the port-forwards to the Envoy admin listen on a local port chosen by the system
+	EnvoyAdminLocalPort int `yaml:"envoy_admin_local_port,omitempty"`
+	// Regular expressions of the Envoy stats which can be read through the API, matched against the name of the stats
+	EnvoyStatsAllowlist      []string `yaml:"envoy_stats_allowlist,omitempty"`
+	IstioIdentityDomain      string   `yaml:"istio_identity_domain,omitempty"`
+	IstioInjectionAnnotation string   `yaml:"istio_injection_annotation,omitempty"`
+	IstioSidecarAnnotation   string   `yaml:"istio_sidecar_annotation,omitempty"`
+	UrlServiceVersion        string   `yaml:"url_service_version"`
 }
 
 type ComponentStatuses struct {
@@ -186,8 +400,10 @@ type Extensions struct {
 
 // ExternalServices holds configurations for other systems that Kiali depends on
 type ExternalServices struct {
+	Alertmanager     AlertmanagerConfig     `yaml:"alertmanager,omitempty"`
 	Grafana          GrafanaConfig          `yaml:"grafana,omitempty"`
 	Istio            IstioConfig            `yaml:"istio,omitempty"`
+	Loki             LokiConfig             `yaml:"loki,omitempty"`
 	Prometheus       PrometheusConfig       `yaml:"prometheus,omitempty"`
 	CustomDashboards CustomDashboardsConfig `yaml:"custom_dashboards,omitempty"`
 	Tracing          TracingConfig          `yaml:"tracing,omitempty"`
@@ -195,8 +411,12 @@ type ExternalServices struct {
 
 // LoginToken holds config used for generating the Kiali session tokens.
 type LoginToken struct {
-	ExpirationSeconds int64  `yaml:"expiration_seconds,omitempty"`
+	ExpirationSeconds int64 `yaml:"expiration_seconds,omitempty"`
+	// Maximum lifetime of a session renewed by the sliding expiration, regardless of the activity of the user
+	MaxSessionSeconds int64  `yaml:"max_session_seconds,omitempty"`
 	SigningKey        string `yaml:"signing_key,omitempty"`
+	// If true, the session is renewed while the user is active, so ExpirationSeconds becomes an idle timeout
+	SlidingExpiration bool `yaml:"sliding_expiration,omitempty"`
 }
 
 func (lt *LoginToken) Obfuscate() {
@@ -228,18 +448,71 @@ type KubernetesConfig struct {
 	// Kiali can cache VirtualService,DestinationRule,Gateway and ServiceEntry Istio resources if they are present
 	// on this list of Istio types. Other Istio types are not yet supported.
 	CacheIstioTypes []string `yaml:"cache_istio_types,omitempty"`
+	// Limits of the memory used by the cache
+	CacheLimits CacheLimitsConfig `yaml:"cache_limits,omitempty"`
 	// List of namespaces or regex defining namespaces to include in a cache
 	CacheNamespaces []string `yaml:"cache_namespaces,omitempty"`
+	// How often the cached namespaces are synced with the namespaces of the cluster, expressed in seconds.
+	// Only used by the "accessible_namespaces" cache scope.
+	CacheNamespaceSyncPeriod int `yaml:"cache_namespace_sync_period,omitempty"`
+	// How the caches of the namespaces are warmed up
+	CacheWarmup CacheWarmupConfig `yaml:"cache_warmup,omitempty"`
+	// When the informers of a namespace are created: "on_demand" creates them the first time the namespace is
+	// requested; "accessible_namespaces" creates them on start for all the accessible namespaces included in the
+	// cache, adding and removing informers as namespaces appear and disappear.
+	CacheScope string `yaml:"cache_scope,omitempty"`
 	// Cache duration expressed in seconds
 	// Kiali cache list of namespaces per user, this is typically short lived cache compared with the duration of the
 	// namespace cache defined by previous CacheDuration parameter
 	CacheTokenNamespaceDuration int `yaml:"cache_token_namespace_duration,omitempty"`
+	// Maximum time, in seconds, that a write done by Kiali waits for the cache to receive the written object.
+	// When the cache doesn't receive the object in time, the cache of the namespace is refreshed.
+	CacheWriteTimeout int `yaml:"cache_write_timeout,omitempty"`
+	// Name of the cluster where Kiali is deployed (the home cluster), as known by Istio
+	ClusterName string `yaml:"cluster_name,omitempty"`
 	// List of controllers that won't be used for Workload calculation
 	// Kiali queries Deployment,ReplicaSet,ReplicationController,DeploymentConfig,StatefulSet,Job and CronJob controllers
 	// Deployment and ReplicaSet will be always queried, but ReplicationController,DeploymentConfig,StatefulSet,Job and CronJobs
 	// can be skipped from Kiali workloads query if they are present in this list
 	ExcludeWorkloads []string `yaml:"excluded_workloads,omitempty"`
-	QPS              float32  `yaml:"qps,omitempty"`
+	// List of Istio types (i.e. EnvoyFilter, WorkloadEntry) that are never used in the mesh. Kiali doesn't watch nor
+	// query these types, and handles them as if they were not installed in the cluster.
+	ExcludeIstioTypes []string `yaml:"excluded_istio_types,omitempty"`
+	// Election of the replica of Kiali running the background jobs, like the validations sweeps
+	LeaderElection LeaderElectionConfig `yaml:"leader_election,omitempty"`
+	// Cache of the results computed by Kiali, like validations and health
+	ResultsCache ResultsCacheConfig `yaml:"results_cache,omitempty"`
+	QPS          float32            `yaml:"qps,omitempty"`
+}
+
+// IsIstioTypeExcluded returns true if the Istio type (i.e. EnvoyFilter) is excluded from Kiali
+func (kc *KubernetesConfig) IsIstioTypeExcluded(istioType string) bool {
+	for _, excluded := range kc.ExcludeIstioTypes {
+		if excluded == istioType {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheLimitsConfig bounds the memory used by the Kiali cache in huge meshes
+type CacheLimitsConfig struct {
+	// Maximum number of cached objects of a kind (i.e. Pod, ReplicaSet, VirtualService) across all the namespaces.
+	// When a limit is exceeded, the caches of the least recently used namespaces are dropped, and these namespaces
+	// are served directly from the API until the next resync period (cache_duration).
+	ObjectsPerKind map[string]int `yaml:"objects_per_kind,omitempty"`
+	// Remove the fields Kiali never reads (managedFields, last-applied-configuration annotation, status of Services)
+	// from the cached objects
+	StripUnusedFields bool `yaml:"strip_unused_fields,omitempty"`
+}
+
+// CacheWarmupConfig configures how the caches of the namespaces are warmed up.
+// By default, the first request to a namespace waits for its cache to sync. In background mode, requests never wait:
+// they are served directly from the API while the cache of the namespace warms up.
+type CacheWarmupConfig struct {
+	Background bool `yaml:"background,omitempty"`
+	// Namespaces warmed up in the background on start, in this order, before any other namespace
+	Namespaces []string `yaml:"namespaces,omitempty"`
 }
 
 // ApiConfig contains API specific configuration.
@@ -253,11 +526,132 @@ type ApiNamespacesConfig struct {
 	LabelSelector string `yaml:"label_selector,omitempty" json:"labelSelector"`
 }
 
+// ResultsCacheConfig configures where Kiali stores computed results, like validations and health, so that they are
+// not recomputed on each request. A shared backend allows several replicas of Kiali to reuse the results of each other.
+type ResultsCacheConfig struct {
+	// One of "none", "memory" or "redis"
+	Backend string `yaml:"backend,omitempty"`
+	// Time to live of the cached health, expressed in seconds
+	HealthTTL int         `yaml:"health_ttl,omitempty"`
+	Redis     RedisConfig `yaml:"redis,omitempty"`
+	// How often the validations of all the namespaces are computed in the background, expressed in seconds, so that
+	// the requests find them in the cache. Run by the leader replica only. 0 disables the sweeps.
+	ValidationsSweepPeriod int `yaml:"validations_sweep_period,omitempty"`
+	// Time to live of the cached validations, expressed in seconds.
+	// Validations are also recomputed as soon as any of the validated objects changes.
+	ValidationsTTL int `yaml:"validations_ttl,omitempty"`
+}
+
+// LeaderElectionConfig elects one of the replicas of Kiali to run the background jobs, while all of the replicas
+// serve the requests. Without the election, every replica runs the background jobs.
+type LeaderElectionConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Name of the Lease held by the leader, in the namespace of Kiali
+	LeaseName string `yaml:"lease_name,omitempty"`
+	// Time, in seconds, the other replicas wait before taking over the lease of a leader which does not renew it
+	LeaseDuration int `yaml:"lease_duration,omitempty"`
+	// Time, in seconds, the leader keeps retrying to renew its lease before giving up the leadership
+	RenewDeadline int `yaml:"renew_deadline,omitempty"`
+	// Time, in seconds, between the attempts to acquire or renew the lease
+	RetryPeriod int `yaml:"retry_period,omitempty"`
+}
+
+// RedisConfig describes how to connect to a Redis (or compatible, like Valkey) server
+type RedisConfig struct {
+	Address  string `yaml:"address,omitempty"`
+	Database int    `yaml:"database,omitempty"`
+	// Prefix of the keys, so several Kiali installations can share the same server
+	KeyPrefix          string `yaml:"key_prefix,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+	Password           string `yaml:"password,omitempty"`
+	UseTLS             bool   `yaml:"use_tls,omitempty"`
+}
+
 // AuthConfig provides details on how users are to authenticate
 type AuthConfig struct {
-	OpenId    OpenIdConfig    `yaml:"openid,omitempty"`
-	OpenShift OpenShiftConfig `yaml:"openshift,omitempty"`
-	Strategy  string          `yaml:"strategy,omitempty"`
+	ApiTokens ApiTokensConfig `yaml:"api_tokens,omitempty"`
+	Audit     AuthAuditConfig `yaml:"audit,omitempty"`
+	Header    HeaderConfig    `yaml:"header,omitempty"`
+	// Restrictions of the namespaces that users can access in Kiali, on top of their Kubernetes RBAC permissions
+	NamespaceAccess []NamespaceAccessRule `yaml:"namespace_access,omitempty"`
+	OpenId          OpenIdConfig          `yaml:"openid,omitempty"`
+	OpenShift       OpenShiftConfig       `yaml:"openshift,omitempty"`
+	Strategy        string                `yaml:"strategy,omitempty"`
+}
+
+// UsesUserCredentials returns true if the requests of the users reach the cluster API with their own credentials.
+// With the other strategies, the Kiali ServiceAccount is used for everybody, so its permissions say nothing of the user.
+func (ac *AuthConfig) UsesUserCredentials() bool {
+	switch ac.Strategy {
+	case AuthStrategyOpenshift, AuthStrategyToken:
+		return true
+	case AuthStrategyOpenId:
+		return !ac.OpenId.DisableRBAC
+	}
+	return false
+}
+
+// NamespaceAccessRule restricts the namespaces that the matching users or groups can see and act upon.
+// Namespaces are regular expressions matching the whole namespace name.
+type NamespaceAccessRule struct {
+	// Namespaces the matching users can access. An empty list allows any namespace that is not denied
+	Allow []string `yaml:"allow,omitempty"`
+	// Namespaces the matching users cannot access. Deny takes precedence over allow
+	Deny   []string `yaml:"deny,omitempty"`
+	Groups []string `yaml:"groups,omitempty"`
+	// Users the rule applies to. The "*" user applies the rule to everybody
+	Users []string `yaml:"users,omitempty"`
+}
+
+// AppliesTo returns true if the rule matches the given user or any of its groups
+func (rule *NamespaceAccessRule) AppliesTo(username string, groups []string) bool {
+	for _, user := range rule.Users {
+		if user == "*" || user == username {
+			return true
+		}
+	}
+	for _, ruleGroup := range rule.Groups {
+		for _, group := range groups {
+			if ruleGroup == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type namePattern struct {
+	regexp *regexp.Regexp
+	err    error
+}
+
+// The compiled patterns of the configuration matching whole names, by pattern
+var namePatterns sync.Map
+
+// compileNamePattern returns the regular expression matching the whole names of a pattern. Each pattern is compiled
+// once.
+func compileNamePattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := namePatterns.Load(pattern); ok {
+		return compiled.(namePattern).regexp, compiled.(namePattern).err
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	namePatterns.Store(pattern, namePattern{regexp: re, err: err})
+	return re, err
+}
+
+// CompileNamespacePattern returns the regular expression matching the whole namespace names of a pattern of the
+// namespace access rules
+func CompileNamespacePattern(pattern string) (*regexp.Regexp, error) {
+	return compileNamePattern(pattern)
+}
+
+// ApiTokensConfig contains the configuration of the API tokens that automation can use to call the Kiali API
+type ApiTokensConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Maximum lifetime of an API token expressed in seconds
+	MaxExpirationSeconds int64 `yaml:"max_expiration_seconds,omitempty"`
+	// Name of the Secret, in the Kiali deployment namespace, where the hashes of the API tokens are stored
+	SecretName string `yaml:"secret_name,omitempty"`
 }
 
 // OpenShiftConfig contains specific configuration for authentication when on OpenShift
@@ -265,6 +659,46 @@ type OpenShiftConfig struct {
 	ClientIdPrefix string `yaml:"client_id_prefix,omitempty"`
 }
 
+// AuthAuditConfig contains the configuration of the audit log of the authentication and authorization events
+type AuthAuditConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// If set, the audit events are also sent as JSON to this URL with a POST request
+	WebhookUrl string `yaml:"webhook_url,omitempty"`
+}
+
+// HeaderConfig contains specific configuration for authentication delegated to a proxy in front of Kiali
+// (e.g. oauth2-proxy or Istio external authorization) that sets the identity of the user in request headers
+type HeaderConfig struct {
+	GroupsHeader string `yaml:"groups_header,omitempty"`
+	// Separator of the groups listed in the groups header
+	GroupsSeparator string `yaml:"groups_separator,omitempty"`
+	// IPs or CIDRs of the proxies allowed to send the identity headers. Requests from any other source are rejected
+	TrustedProxies []string `yaml:"trusted_proxies,omitempty"`
+	UserHeader     string   `yaml:"user_header,omitempty"`
+}
+
+// IsTrustedProxy returns true if the given address (host or host:port) belongs to one of the trusted proxies
+func (hc *HeaderConfig) IsTrustedProxy(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, trusted := range hc.TrustedProxies {
+		if _, cidr, err := net.ParseCIDR(trusted); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if trustedIP := net.ParseIP(trusted); trustedIP != nil && trustedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // OpenIdConfig contains specific configuration for authentication using an OpenID provider
 type OpenIdConfig struct {
 	ApiProxy              string   `yaml:"api_proxy,omitempty"`
@@ -280,10 +714,107 @@ type OpenIdConfig struct {
 	UsernameClaim         string   `yaml:"username_claim,omitempty"`
 }
 
+// RemoteClusterAuth describes how Kiali authenticates users against a remote cluster
+type RemoteClusterAuth struct {
+	// Strategy is one of "token", "openid_exchange" or "service_account"
+	Strategy string `yaml:"strategy,omitempty"`
+	// Audience requested when exchanging the OpenId token of the user for a token of the remote cluster
+	TokenExchangeAudience string `yaml:"token_exchange_audience,omitempty"`
+	// Token endpoint used for the exchange. Defaults to the token endpoint of the configured OpenId provider
+	TokenExchangeEndpoint string `yaml:"token_exchange_endpoint,omitempty"`
+}
+
+// RemoteCluster describes a remote cluster and how to reach it
+type RemoteCluster struct {
+	Auth RemoteClusterAuth `yaml:"auth,omitempty"`
+	// Labels of the cluster, i.e. region, environment or tier, to select the clusters of the multi-cluster views
+	Labels map[string]string `yaml:"labels,omitempty"`
+	Name   string            `yaml:"name"`
+	// Path to a remote secret (kubeconfig) holding the API server of the cluster and, for the
+	// service_account strategy, the token to use
+	SecretFile string `yaml:"secret_file"`
+	// Secret holding the remote secret, instead of the file. Set for the clusters registered through the API.
+	SecretRef *RemoteClusterSecretRef `yaml:"secret_ref,omitempty"`
+	// True for the clusters registered through the API, which are not in the configuration file
+	Registered bool `yaml:"-"`
+}
+
+// RemoteClusterSecretRef references the key of a Secret holding the remote secret (kubeconfig) of a cluster
+type RemoteClusterSecretRef struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	// Key of the remote secret in the data of the Secret. Optional when the Secret holds a single key.
+	Key string `yaml:"key,omitempty"`
+}
+
+// ClusteringConfig holds the configuration of the remote clusters Kiali connects to
+type ClusteringConfig struct {
+	Clusters []RemoteCluster `yaml:"clusters,omitempty"`
+	// Labels of the home cluster, to select it with the remote clusters
+	HomeClusterLabels map[string]string `yaml:"home_cluster_labels,omitempty"`
+	// Name of the Secret, in the Kiali deployment namespace, where the clusters registered through the API are stored
+	RegistrySecretName string `yaml:"registry_secret_name,omitempty"`
+	// How often each replica of Kiali reads the registered clusters, expressed in seconds
+	RegistrySyncPeriod int `yaml:"registry_sync_period,omitempty"`
+	// Time given to each cluster to answer the requests aggregating the data of several clusters, expressed in
+	// seconds. The clusters answering later are reported as failing, with the data of the other clusters.
+	RequestTimeout int `yaml:"request_timeout,omitempty"`
+}
+
+// GetCluster returns the configuration of the remote cluster with the given name, or nil if it is not configured
+func (cc *ClusteringConfig) GetCluster(name string) *RemoteCluster {
+	for i := range cc.Clusters {
+		if cc.Clusters[i].Name == name {
+			return &cc.Clusters[i]
+		}
+	}
+	return nil
+}
+
+// GetClusterLabels returns the labels of the given cluster: the home cluster, named homeCluster, or a remote cluster
+func (cc *ClusteringConfig) GetClusterLabels(cluster, homeCluster string) map[string]string {
+	if cluster == homeCluster {
+		return cc.HomeClusterLabels
+	}
+	if remote := cc.GetCluster(cluster); remote != nil {
+		return remote.Labels
+	}
+	return nil
+}
+
+// FederationConfig holds the remote Kiali instances aggregated by a central Kiali. Each remote Kiali serves the data
+// of its cluster through its API, so the central Kiali needs neither informers nor permissions in the remote clusters.
+type FederationConfig struct {
+	Enabled   bool                `yaml:"enabled,omitempty"`
+	Instances []FederatedInstance `yaml:"instances,omitempty"`
+}
+
+// FederatedInstance describes a remote Kiali instance and how to reach its API
+type FederatedInstance struct {
+	// Credentials of the central Kiali, i.e. a bearer API token issued by the remote Kiali. The token of Kiali
+	// (use_kiali_token) is not supported.
+	Auth Auth `yaml:"auth,omitempty"`
+	// Name of the cluster of the remote Kiali
+	Cluster string `yaml:"cluster"`
+	// Base URL of the remote Kiali, i.e. https://kiali.east.example.com/kiali
+	URL string `yaml:"url"`
+}
+
+// GetInstance returns the remote Kiali instance of the given cluster, or nil if it is not federated
+func (fc *FederationConfig) GetInstance(cluster string) *FederatedInstance {
+	for i := range fc.Instances {
+		if fc.Instances[i].Cluster == cluster {
+			return &fc.Instances[i]
+		}
+	}
+	return nil
+}
+
 // DeploymentConfig provides details on how Kiali was deployed.
 type DeploymentConfig struct {
 	AccessibleNamespaces []string `yaml:"accessible_namespaces"`
 	Namespace            string   `yaml:"namespace,omitempty"` // Kiali deployment namespace
+	ViewOnlyMode         bool     `yaml:"view_only_mode,omitempty"`
 }
 
 // IstioComponentNamespaces holds the component-specific Istio namespaces. Any missing component
@@ -291,7 +822,9 @@ type DeploymentConfig struct {
 type IstioComponentNamespaces map[string]string
 
 type KialiFeatureFlags struct {
-	IstioInjectionAction bool `yaml:"istio_injection_action,omitempty" json:"istioInjectionAction"`
+	// Features turned on or off by name, overriding their defaults. See the Feature constants.
+	Features             map[string]bool `yaml:"features,omitempty" json:"features"`
+	IstioInjectionAction bool            `yaml:"istio_injection_action,omitempty" json:"istioInjectionAction"`
 }
 
 // ToleranceConfig
@@ -316,14 +849,63 @@ type HealthConfig struct {
 	Rate []Rate `yaml:"rate,omitempty" json:"rate"`
 }
 
+// SLOObjective sets the service level objectives of the services or workloads matching the regular expressions.
+// Objectives are percentages of the requests, measured over the window, i.e. 30d.
+type SLOObjective struct {
+	Namespace string `yaml:"namespace,omitempty" json:"namespace"`
+	Kind      string `yaml:"kind,omitempty" json:"kind"`
+	Name      string `yaml:"name,omitempty" json:"name"`
+	// Percentage of the requests not failing with a server error
+	Availability float64 `yaml:"availability,omitempty" json:"availability"`
+	// Percentage of the requests faster than the threshold, in milliseconds. The threshold must be a bucket
+	// boundary of the istio_request_duration_milliseconds histogram.
+	LatencyTarget    float64 `yaml:"latency_target,omitempty" json:"latencyTarget"`
+	LatencyThreshold float64 `yaml:"latency_threshold,omitempty" json:"latencyThreshold"`
+	Window           string  `yaml:"window,omitempty" json:"window"`
+}
+
+// CompilePatterns compiles the patterns of the objective, so that they are compiled once, when the configuration is
+// loaded
+func (o SLOObjective) CompilePatterns() error {
+	for _, pattern := range []string{o.Namespace, o.Kind, o.Name} {
+		if _, err := compileNamePattern(pattern); err != nil {
+			return fmt.Errorf("SLO objective has an invalid pattern [%v]: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the patterns of the objective match the whole namespace, kind and name of a service or
+// workload. An empty pattern matches any value.
+func (o SLOObjective) Matches(namespace, kind, name string) bool {
+	return matchesNamePattern(o.Namespace, namespace) && matchesNamePattern(o.Kind, kind) && matchesNamePattern(o.Name, name)
+}
+
+func matchesNamePattern(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	// The invalid patterns are rejected when the configuration is loaded
+	re, err := compileNamePattern(pattern)
+	return err == nil && re.MatchString(value)
+}
+
+// SLOConfig sets the service level objectives reported by the SLO API. The first objective matching a service or
+// workload applies to it.
+type SLOConfig struct {
+	Objectives []SLOObjective `yaml:"objectives,omitempty" json:"objectives"`
+}
+
 // Config defines full YAML configuration.
 type Config struct {
 	AdditionalDisplayDetails []AdditionalDisplayItem  `yaml:"additional_display_details,omitempty"`
 	API                      ApiConfig                `yaml:"api,omitempty"`
 	Auth                     AuthConfig               `yaml:"auth,omitempty"`
+	Clustering               ClusteringConfig         `yaml:"clustering,omitempty"`
 	Deployment               DeploymentConfig         `yaml:"deployment,omitempty"`
 	Extensions               Extensions               `yaml:"extensions,omitempty"`
 	ExternalServices         ExternalServices         `yaml:"external_services,omitempty"`
+	Federation               FederationConfig         `yaml:"federation,omitempty"`
 	HealthConfig             HealthConfig             `yaml:"health_config,omitempty" json:"healthConfig"`
 	Identity                 security.Identity        `yaml:",omitempty"`
 	InCluster                bool                     `yaml:"in_cluster,omitempty"`
@@ -334,6 +916,7 @@ type Config struct {
 	KialiFeatureFlags        KialiFeatureFlags        `yaml:"kiali_feature_flags,omitempty"`
 	KubernetesConfig         KubernetesConfig         `yaml:"kubernetes_config,omitempty"`
 	LoginToken               LoginToken               `yaml:"login_token,omitempty"`
+	SLO                      SLOConfig                `yaml:"slo,omitempty"`
 	Server                   Server                   `yaml:",omitempty"`
 }
 
@@ -354,7 +937,22 @@ func NewConfig() (c *Config) {
 			},
 		},
 		Auth: AuthConfig{
+			ApiTokens: ApiTokensConfig{
+				Enabled:              false,
+				MaxExpirationSeconds: 90 * 24 * 3600,
+				SecretName:           "kiali-api-tokens",
+			},
+			Audit: AuthAuditConfig{
+				Enabled:    true,
+				WebhookUrl: "",
+			},
 			Strategy: "token",
+			Header: HeaderConfig{
+				GroupsHeader:    "X-Forwarded-Groups",
+				GroupsSeparator: ",",
+				TrustedProxies:  []string{},
+				UserHeader:      "X-Forwarded-User",
+			},
 			OpenId: OpenIdConfig{
 				ApiProxy:              "",
 				ApiProxyCAData:        "",
@@ -372,6 +970,11 @@ func NewConfig() (c *Config) {
 				ClientIdPrefix: "kiali",
 			},
 		},
+		Clustering: ClusteringConfig{
+			RegistrySecretName: "kiali-remote-clusters",
+			RegistrySyncPeriod: 60,
+			RequestTimeout:     10,
+		},
 		Deployment: DeploymentConfig{
 			AccessibleNamespaces: []string{"**"},
 			Namespace:            "istio-system",
@@ -383,9 +986,23 @@ func NewConfig() (c *Config) {
 			},
 		},
 		ExternalServices: ExternalServices{
+			Alertmanager: AlertmanagerConfig{
+				Auth: Auth{
+					Type: AuthTypeNone,
+				},
+				Enabled:      false,
+				InClusterURL: "http://alertmanager.istio-system:9093",
+				Labels: AlertmanagerLabelsConfig{
+					App:       "app",
+					Namespace: "namespace",
+					Service:   "service",
+					Workload:  "workload",
+				},
+			},
 			CustomDashboards: CustomDashboardsConfig{
-				Enabled:        true,
-				NamespaceLabel: "kubernetes_namespace",
+				ConfigMapSelector: "kiali.io/dashboard=true",
+				Enabled:           true,
+				NamespaceLabel:    "kubernetes_namespace",
 			},
 			Grafana: GrafanaConfig{
 				Auth: Auth{
@@ -412,8 +1029,15 @@ func NewConfig() (c *Config) {
 						},
 					},
 				},
-				ConfigMapName:            "istio",
-				EnvoyAdminLocalPort:      15000,
+				ConfigMapName: "istio",
+				EnvoyStatsAllowlist: []string{
+					`^cluster\..+\.circuit_breakers\.`,
+					`^cluster\..+\.upstream_(cx|rq)_.+`,
+					`^cluster\..+\.outlier_detection\.`,
+					`^cluster\..+\.retry_or_shadow_abandoned$`,
+					`^listener\..+\.downstream_cx_.+`,
+					`^http\..+\.downstream_rq_.+`,
+				},
 				IstioIdentityDomain:      "svc.cluster.local",
 				IstioInjectionAnnotation: "sidecar.istio.io/inject",
 				IstioSidecarAnnotation:   "sidecar.istio.io/status",
@@ -428,16 +1052,39 @@ func NewConfig() (c *Config) {
 				CacheDuration: 7,
 				// Prom Cache expires and it forces to repopulate cache
 				CacheExpiration: 300,
-				URL:             "http://prometheus.istio-system:9090",
+				// Width of a chart, roughly
+				MaxDataPoints: 1000,
+				// Graph refresh interval
+				QueryCacheDuration: 15,
+				QueryCacheEnabled:  false,
+				URL:                "http://prometheus.istio-system:9090",
+			},
+			Loki: LokiConfig{
+				Auth: Auth{
+					Type: AuthTypeNone,
+				},
+				Enabled:      false,
+				InClusterURL: "http://loki.istio-system:3100",
+				Labels: LokiLabelsConfig{
+					Container: "container",
+					Namespace: "namespace",
+					Pod:       "pod",
+				},
+				MaxEntries: 5000,
 			},
 			Tracing: TracingConfig{
 				Auth: Auth{
 					Type: AuthTypeNone,
 				},
-				IsCoreComponent:      false,
-				Enabled:              true,
+				IsCoreComponent: false,
+				Enabled:         true,
+				LogCorrelation: LogCorrelationConfig{
+					SpanIDFields:  []string{"span_id", "spanId", "spanID", "x-b3-spanid"},
+					TraceIDFields: []string{"trace_id", "traceId", "traceID", "x-b3-traceid"},
+				},
 				NamespaceSelector:    true,
 				InClusterURL:         "http://tracing.istio-system/jaeger",
+				Provider:             TracingProviderJaeger,
 				URL:                  "",
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
@@ -456,25 +1103,67 @@ func NewConfig() (c *Config) {
 			CacheEnabled:                true,
 			CacheIstioTypes:             []string{"DestinationRule", "Gateway", "ServiceEntry", "VirtualService", "Sidecar", "PeerAuthentication", "RequestAuthentication", "AuthorizationPolicy"},
 			CacheNamespaces:             []string{".*"},
+			CacheNamespaceSyncPeriod:    60,
+			CacheScope:                  CacheScopeOnDemand,
 			CacheTokenNamespaceDuration: 10,
+			CacheWriteTimeout:           10,
+			ClusterName:                 "Kubernetes",
 			ExcludeWorkloads:            []string{"CronJob", "DeploymentConfig", "Job", "ReplicationController"},
 			QPS:                         175,
+			LeaderElection: LeaderElectionConfig{
+				Enabled:       false,
+				LeaseName:     "kiali-leader",
+				LeaseDuration: 15,
+				RenewDeadline: 10,
+				RetryPeriod:   2,
+			},
+			ResultsCache: ResultsCacheConfig{
+				Backend:   ResultsCacheBackendNone,
+				HealthTTL: 15,
+				Redis: RedisConfig{
+					Address:   "",
+					Database:  0,
+					KeyPrefix: "kiali:",
+				},
+				ValidationsSweepPeriod: 0,
+				ValidationsTTL:         5 * 60,
+			},
+			CacheLimits: CacheLimitsConfig{
+				ObjectsPerKind:    map[string]int{},
+				StripUnusedFields: true,
+			},
+			CacheWarmup: CacheWarmupConfig{
+				Background: false,
+				Namespaces: []string{},
+			},
 		},
 		LoginToken: LoginToken{
 			ExpirationSeconds: 24 * 3600,
+			MaxSessionSeconds: 7 * 24 * 3600,
 			SigningKey:        "kiali",
+			SlidingExpiration: false,
 		},
 		Server: Server{
 			AuditLog:                   true,
 			GzipEnabled:                true,
+			HTTP2Enabled:               true,
+			IdleTimeout:                120,
+			MaxHeaderBytes:             1 << 20,
 			MetricsEnabled:             true,
 			MetricsPort:                9090,
 			Port:                       20001,
+			ReadTimeout:                30,
+			ShutdownDrainDelay:         5,
+			ShutdownTimeout:            25,
 			StaticContentRootDirectory: "/opt/kiali/console",
-			WebFQDN:                    "",
-			WebRoot:                    "/",
-			WebHistoryMode:             "browser",
-			WebSchema:                  "",
+			TLS: ServerTLS{
+				MinVersion: "1.2",
+			},
+			WebFQDN:        "",
+			WebRoot:        "/",
+			WebHistoryMode: "browser",
+			WebSchema:      "",
+			WriteTimeout:   30,
 		},
 	}
 
@@ -545,12 +1234,30 @@ func Set(conf *Config) {
 // WARNING: do NOT use the result of this function to retrieve any configuration: some fields are obfuscated for security reasons.
 func (conf Config) String() (str string) {
 	obf := conf
+	obf.ExternalServices.Alertmanager.Auth.Obfuscate()
 	obf.ExternalServices.Grafana.Auth.Obfuscate()
+	obf.ExternalServices.Loki.Auth.Obfuscate()
 	obf.ExternalServices.Prometheus.Auth.Obfuscate()
+	obf.ExternalServices.Prometheus.Clusters = make(map[string]PrometheusClusterConfig, len(conf.ExternalServices.Prometheus.Clusters))
+	for cluster, clusterConfig := range conf.ExternalServices.Prometheus.Clusters {
+		clusterConfig.Auth.Obfuscate()
+		obf.ExternalServices.Prometheus.Clusters[cluster] = clusterConfig
+	}
 	obf.ExternalServices.Tracing.Auth.Obfuscate()
+	obf.ExternalServices.Tracing.Clusters = make(map[string]TracingClusterConfig, len(conf.ExternalServices.Tracing.Clusters))
+	for cluster, clusterConfig := range conf.ExternalServices.Tracing.Clusters {
+		clusterConfig.Auth.Obfuscate()
+		obf.ExternalServices.Tracing.Clusters[cluster] = clusterConfig
+	}
+	obf.Federation.Instances = make([]FederatedInstance, len(conf.Federation.Instances))
+	for i, instance := range conf.Federation.Instances {
+		instance.Auth.Obfuscate()
+		obf.Federation.Instances[i] = instance
+	}
 	obf.Identity.Obfuscate()
 	obf.LoginToken.Obfuscate()
 	obf.Auth.OpenId.ClientSecret = "xxx"
+	obf.KubernetesConfig.ResultsCache.Redis.Password = "xxx"
 	str, err := Marshal(&obf)
 	if err != nil {
 		str = fmt.Sprintf("Failed to marshal config to string. err=%v", err)
@@ -606,6 +1313,10 @@ func Unmarshal(yamlString string) (conf *Config, err error) {
 			configValue: &conf.LoginToken.SigningKey,
 			envVarName:  EnvLoginTokenSigningKey,
 		},
+		{
+			configValue: &conf.KubernetesConfig.ResultsCache.Redis.Password,
+			envVarName:  EnvResultsCacheRedisPassword,
+		},
 	}
 
 	for _, override := range overrides {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 	"sync"
@@ -236,3 +237,55 @@ func TestRaces(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestServerTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	version, err := ServerTLS{}.TLSVersion()
+	assert.NoError(err)
+	assert.Equal(uint16(tls.VersionTLS12), version)
+	version, err = ServerTLS{MinVersion: "1.3"}.TLSVersion()
+	assert.NoError(err)
+	assert.Equal(uint16(tls.VersionTLS13), version)
+	_, err = ServerTLS{MinVersion: "1.1"}.TLSVersion()
+	assert.Error(err)
+
+	suites, err := ServerTLS{}.CipherSuiteIDs()
+	assert.NoError(err)
+	assert.Nil(suites)
+	suites, err = ServerTLS{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}}.CipherSuiteIDs()
+	assert.NoError(err)
+	assert.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, suites)
+	_, err = ServerTLS{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}.CipherSuiteIDs()
+	assert.Error(err)
+}
+
+func TestCompileNamespacePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	re, err := CompileNamespacePattern("team-.*|bookinfo")
+	assert.NoError(err)
+	assert.True(re.MatchString("team-a"))
+	assert.True(re.MatchString("bookinfo"))
+	assert.False(re.MatchString("bookinfo-dev"))
+	cached, _ := CompileNamespacePattern("team-.*|bookinfo")
+	assert.Same(re, cached)
+
+	_, err = CompileNamespacePattern("team-(")
+	assert.Error(err)
+	_, err = CompileNamespacePattern("team-(")
+	assert.Error(err)
+}
+
+func TestSLOObjectiveMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	objective := SLOObjective{Namespace: "bookinfo|travels", Kind: "service"}
+	assert.NoError(objective.CompilePatterns())
+	assert.True(objective.Matches("bookinfo", "service", "reviews"))
+	assert.False(objective.Matches("bookinfo-dev", "service", "reviews"))
+	assert.False(objective.Matches("travels", "workload", "cars-v1"))
+
+	assert.Error(SLOObjective{Name: "reviews-("}.CompilePatterns())
+	assert.False(SLOObjective{Name: "reviews-("}.Matches("bookinfo", "service", "reviews-("))
+}
@@ -0,0 +1,50 @@
+package config
+
+import "sort"
+
+// Features of Kiali which can be turned on or off per installation, in kiali_feature_flags.features. The
+// experimental ones ship disabled.
+const (
+	// Ambient mesh readiness of the namespaces and configuration of the ztunnels
+	FeatureAmbient = "ambient"
+	// Saved views shared by the users, stored in a ConfigMap of the namespace of Kiali
+	FeatureSavedViews = "saved_views"
+	// Preferences of the users, stored in a ConfigMap of the namespace of Kiali
+	FeatureUserPreferences = "user_preferences"
+)
+
+// Whether the features are enabled when the configuration does not set them
+var defaultFeatures = map[string]bool{
+	FeatureAmbient:         false,
+	FeatureSavedViews:      false,
+	FeatureUserPreferences: false,
+}
+
+// IsEnabled returns true if a feature is turned on in the configuration, or is enabled by default
+func (kff KialiFeatureFlags) IsEnabled(feature string) bool {
+	if enabled, ok := kff.Features[feature]; ok {
+		return enabled
+	}
+	return defaultFeatures[feature]
+}
+
+// EnabledFeatures returns whether each of the features known to Kiali is enabled
+func (kff KialiFeatureFlags) EnabledFeatures() map[string]bool {
+	features := make(map[string]bool, len(defaultFeatures))
+	for feature := range defaultFeatures {
+		features[feature] = kff.IsEnabled(feature)
+	}
+	return features
+}
+
+// unknownFeatures returns the features set in the configuration which are not known to Kiali
+func (kff KialiFeatureFlags) unknownFeatures() []string {
+	unknown := []string{}
+	for feature := range kff.Features {
+		if _, ok := defaultFeatures[feature]; !ok {
+			unknown = append(unknown, feature)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	assert.False(conf.KialiFeatureFlags.IsEnabled(FeatureAmbient))
+	assert.Equal(map[string]bool{FeatureAmbient: false, FeatureSavedViews: false, FeatureUserPreferences: false}, conf.KialiFeatureFlags.EnabledFeatures())
+
+	conf.KialiFeatureFlags.Features = map[string]bool{FeatureAmbient: true, "graphql": true}
+	assert.True(conf.KialiFeatureFlags.IsEnabled(FeatureAmbient))
+	assert.False(conf.KialiFeatureFlags.IsEnabled("other"))
+	assert.Equal(map[string]bool{FeatureAmbient: true, FeatureSavedViews: false, FeatureUserPreferences: false}, conf.KialiFeatureFlags.EnabledFeatures())
+
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	findings := conf.Lint()
+	assert.Len(findings, 1)
+	assert.Equal("kiali_feature_flags.features.graphql", findings[0].Setting)
+	assert.Equal(LintWarning, findings[0].Severity)
+}
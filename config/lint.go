@@ -0,0 +1,296 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Severities of the findings of the configuration lint
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintFinding is a problem of the configuration, with the setting causing it
+type LintFinding struct {
+	// error or warning. Kiali does not work as expected with an error.
+	Severity string `json:"severity"`
+	// Path of the setting in the YAML configuration, i.e. auth.openid.issuer_uri
+	Setting string `json:"setting"`
+	Message string `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	if f.Setting == "" {
+		return fmt.Sprintf("[%s] %s", f.Severity, f.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Setting, f.Message)
+}
+
+// LintYAML reports the settings of a YAML configuration which are not settings of this version of Kiali: they were
+// removed, renamed or are misspelled, and are ignored.
+func LintYAML(yamlString string) []LintFinding {
+	findings := []LintFinding{}
+	err := yaml.UnmarshalStrict([]byte(yamlString), NewConfig())
+	if err == nil {
+		return findings
+	}
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return append(findings, LintFinding{Severity: LintError, Setting: "", Message: fmt.Sprintf("invalid YAML: %v", err)})
+	}
+	for _, message := range typeErr.Errors {
+		findings = append(findings, LintFinding{Severity: LintWarning, Setting: "", Message: message + ": the setting is ignored, it was removed, renamed or is misspelled"})
+	}
+	return findings
+}
+
+// Lint checks the configuration for contradictory or incomplete settings, and for the files it refers to which are
+// missing, as the secrets of the remote clusters. The external services are not contacted.
+func (conf *Config) Lint() []LintFinding {
+	findings := []LintFinding{}
+	add := func(severity, setting, format string, args ...interface{}) {
+		findings = append(findings, LintFinding{Severity: severity, Setting: setting, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Authentication
+	auth := conf.Auth
+	if auth.Strategy == AuthStrategyOpenId {
+		if auth.OpenId.IssuerUri == "" {
+			add(LintError, "auth.openid.issuer_uri", "the %s authentication strategy requires the URI of the issuer", auth.Strategy)
+		}
+		if auth.OpenId.ClientId == "" {
+			add(LintError, "auth.openid.client_id", "the %s authentication strategy requires the client id", auth.Strategy)
+		}
+		if auth.OpenId.InsecureSkipVerifyTLS {
+			add(LintWarning, "auth.openid.insecure_skip_verify_tls", "the certificate of the OpenId provider is not verified")
+		}
+	}
+	if err := ValidateSigningKey(conf.LoginToken.SigningKey, auth.Strategy); err != nil {
+		add(LintError, "login_token.signing_key", "%v: it must be set, and not to the well-known default", err)
+	}
+	if conf.LoginToken.MaxSessionSeconds > 0 && conf.LoginToken.ExpirationSeconds > conf.LoginToken.MaxSessionSeconds {
+		add(LintWarning, "login_token.expiration_seconds", "the expiration of %d seconds exceeds the maximum session of %d seconds", conf.LoginToken.ExpirationSeconds, conf.LoginToken.MaxSessionSeconds)
+	}
+
+	for _, feature := range conf.KialiFeatureFlags.unknownFeatures() {
+		add(LintWarning, "kiali_feature_flags.features."+feature, "unknown feature, it is ignored")
+	}
+
+	// Server
+	if conf.Server.MetricsEnabled && conf.Server.MetricsPort == conf.Server.Port {
+		add(LintError, "server.metrics_port", "the metrics port %d is the port of the server", conf.Server.MetricsPort)
+	}
+
+	if _, err := conf.Server.TLS.TLSVersion(); err != nil {
+		add(LintError, "server.tls.min_version", "%v", err)
+	}
+	if _, err := conf.Server.TLS.CipherSuiteIDs(); err != nil {
+		add(LintError, "server.tls.cipher_suites", "%v", err)
+	}
+	if conf.Server.TLS.MinVersion == "1.3" && len(conf.Server.TLS.CipherSuites) > 0 {
+		add(LintWarning, "server.tls.cipher_suites", "the cipher suites are ignored, they are not configurable with TLS 1.3")
+	}
+	if conf.Server.H2CEnabled && conf.Identity.CertFile != "" && conf.Identity.PrivateKeyFile != "" {
+		add(LintWarning, "server.h2c_enabled", "HTTP/2 in cleartext is ignored, the server serves TLS")
+	}
+	for _, timeout := range []struct {
+		setting string
+		seconds int
+	}{{"server.read_timeout", conf.Server.ReadTimeout}, {"server.write_timeout", conf.Server.WriteTimeout}} {
+		if timeout.seconds <= 0 {
+			add(LintWarning, timeout.setting, "the server has no timeout, slow clients can hold its connections")
+		}
+	}
+
+	// Cache
+	kc := conf.KubernetesConfig
+	if kc.CacheScope != "" && kc.CacheScope != CacheScopeAccessibleNamespaces && kc.CacheScope != CacheScopeOnDemand {
+		add(LintError, "kubernetes_config.cache_scope", "unknown cache scope [%s], expected %s or %s", kc.CacheScope, CacheScopeAccessibleNamespaces, CacheScopeOnDemand)
+	}
+	if !kc.CacheEnabled && len(kc.CacheWarmup.Namespaces) > 0 {
+		add(LintWarning, "kubernetes_config.cache_warmup.namespaces", "the namespaces are not warmed up, the cache is disabled")
+	}
+	switch kc.ResultsCache.Backend {
+	case "", ResultsCacheBackendNone, ResultsCacheBackendMemory:
+	case ResultsCacheBackendRedis:
+		if kc.ResultsCache.Redis.Address == "" {
+			add(LintError, "kubernetes_config.results_cache.redis.address", "the %s backend of the results cache requires the address of the server", kc.ResultsCache.Backend)
+		}
+	default:
+		add(LintError, "kubernetes_config.results_cache.backend", "unknown backend [%s], expected %s, %s or %s", kc.ResultsCache.Backend, ResultsCacheBackendNone, ResultsCacheBackendMemory, ResultsCacheBackendRedis)
+	}
+	if kc.ResultsCache.ValidationsSweepPeriod > 0 {
+		switch kc.ResultsCache.Backend {
+		case "", ResultsCacheBackendNone:
+			add(LintWarning, "kubernetes_config.results_cache.validations_sweep_period", "the validations are not swept, the results cache is disabled")
+		case ResultsCacheBackendMemory:
+			if kc.LeaderElection.Enabled {
+				add(LintWarning, "kubernetes_config.results_cache.backend", "the swept validations are not shared with the replicas which are not the leader, with the %s backend", kc.ResultsCache.Backend)
+			}
+		}
+	}
+	if le := kc.LeaderElection; le.Enabled {
+		if le.LeaseName == "" {
+			add(LintError, "kubernetes_config.leader_election.lease_name", "the leader election requires the name of the lease")
+		}
+		if conf.Deployment.Namespace == "" {
+			add(LintError, "deployment.namespace", "the leader election requires the namespace of Kiali, which holds the lease")
+		}
+		if le.RetryPeriod <= 0 || le.RenewDeadline <= le.RetryPeriod || le.LeaseDuration <= le.RenewDeadline {
+			add(LintError, "kubernetes_config.leader_election.lease_duration", "the lease duration (%ds) must exceed the renew deadline (%ds), which must exceed the retry period (%ds)", le.LeaseDuration, le.RenewDeadline, le.RetryPeriod)
+		}
+	}
+	for _, namespaces := range []struct {
+		setting  string
+		patterns []string
+	}{{"api.namespaces.exclude", conf.API.Namespaces.Exclude}, {"kubernetes_config.cache_namespaces", kc.CacheNamespaces}} {
+		for _, pattern := range namespaces.patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				add(LintError, namespaces.setting, "invalid namespace pattern [%s]: %v", pattern, err)
+			}
+		}
+	}
+
+	// External services
+	es := conf.ExternalServices
+	if es.Prometheus.URL == "" {
+		add(LintError, "external_services.prometheus.url", "the URL of Prometheus is required")
+	}
+	if es.Tracing.Provider != TracingProviderJaeger && es.Tracing.Provider != TracingProviderOTLP {
+		add(LintError, "external_services.tracing.provider", "unknown tracing provider [%s], expected %s or %s", es.Tracing.Provider, TracingProviderJaeger, TracingProviderOTLP)
+	}
+	if es.Tracing.Enabled && es.Tracing.InClusterURL == "" && es.Tracing.URL == "" {
+		add(LintWarning, "external_services.tracing.in_cluster_url", "the tracing is enabled without any URL")
+	}
+	if es.Loki.Enabled && es.Loki.InClusterURL == "" {
+		add(LintError, "external_services.loki.in_cluster_url", "Loki is enabled without its URL")
+	}
+	if es.Alertmanager.Enabled && es.Alertmanager.InClusterURL == "" {
+		add(LintError, "external_services.alertmanager.in_cluster_url", "Alertmanager is enabled without its URL")
+	}
+	auths := map[string]Auth{
+		"external_services.alertmanager.auth":                 es.Alertmanager.Auth,
+		"external_services.custom_dashboards.prometheus.auth": es.CustomDashboards.Prometheus.Auth,
+		"external_services.grafana.auth":                      es.Grafana.Auth,
+		"external_services.loki.auth":                         es.Loki.Auth,
+		"external_services.prometheus.auth":                   es.Prometheus.Auth,
+		"external_services.tracing.auth":                      es.Tracing.Auth,
+	}
+	for cluster, clusterConfig := range es.Prometheus.Clusters {
+		auths[fmt.Sprintf("external_services.prometheus.clusters.%s.auth", cluster)] = clusterConfig.Auth
+	}
+	for cluster, clusterConfig := range es.Tracing.Clusters {
+		auths[fmt.Sprintf("external_services.tracing.clusters.%s.auth", cluster)] = clusterConfig.Auth
+	}
+	for i, instance := range conf.Federation.Instances {
+		auths[fmt.Sprintf("federation.instances[%d].auth", i)] = instance.Auth
+	}
+	settings := make([]string, 0, len(auths))
+	for setting := range auths {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+	for _, setting := range settings {
+		a := auths[setting]
+		switch {
+		case a.Type == AuthTypeBasic && a.Username == "":
+			add(LintError, setting+".username", "the %s authentication requires a username", a.Type)
+		case a.Type == AuthTypeBearer && a.Token == "" && !a.UseKialiToken:
+			add(LintError, setting+".token", "the %s authentication requires a token, or the token of Kiali", a.Type)
+		}
+		if a.CAFile != "" {
+			if a.InsecureSkipVerify {
+				add(LintWarning, setting+".insecure_skip_verify", "the CA file is ignored, the certificates are not verified")
+			} else if _, err := os.Stat(a.CAFile); err != nil {
+				add(LintError, setting+".ca_file", "cannot read the CA file: %v", err)
+			}
+		}
+	}
+
+	// Remote clusters
+	lintLabels := func(setting string, clusterLabels map[string]string) {
+		keys := make([]string, 0, len(clusterLabels))
+		for key := range clusterLabels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				add(LintError, setting, "invalid label [%s]: %s", key, strings.Join(errs, ", "))
+			} else if errs := validation.IsValidLabelValue(clusterLabels[key]); len(errs) > 0 {
+				add(LintError, setting, "invalid value of label [%s]: %s", key, strings.Join(errs, ", "))
+			}
+		}
+	}
+	lintLabels("clustering.home_cluster_labels", conf.Clustering.HomeClusterLabels)
+	names := map[string]bool{}
+	for i, cluster := range conf.Clustering.Clusters {
+		setting := fmt.Sprintf("clustering.clusters[%d]", i)
+		switch {
+		case cluster.Name == "":
+			add(LintError, setting+".name", "the name of the cluster is required")
+		case cluster.Name == kc.ClusterName:
+			add(LintError, setting+".name", "the cluster [%s] is the home cluster", cluster.Name)
+		case names[cluster.Name]:
+			add(LintError, setting+".name", "the cluster [%s] is configured more than once", cluster.Name)
+		}
+		names[cluster.Name] = true
+		lintLabels(setting+".labels", cluster.Labels)
+		switch cluster.Auth.Strategy {
+		case ClusterAuthStrategyToken, ClusterAuthStrategyServiceAccount:
+		case ClusterAuthStrategyOpenIdExchange:
+			if auth.Strategy != AuthStrategyOpenId {
+				add(LintError, setting+".auth.strategy", "the %s strategy requires the %s authentication strategy", cluster.Auth.Strategy, AuthStrategyOpenId)
+			}
+		default:
+			add(LintError, setting+".auth.strategy", "unknown strategy [%s], expected %s, %s or %s", cluster.Auth.Strategy, ClusterAuthStrategyToken, ClusterAuthStrategyOpenIdExchange, ClusterAuthStrategyServiceAccount)
+		}
+		switch {
+		case cluster.SecretRef != nil:
+			if cluster.SecretRef.Namespace == "" || cluster.SecretRef.Name == "" {
+				add(LintError, setting+".secret_ref", "the namespace and the name of the Secret are required")
+			}
+		case cluster.SecretFile == "":
+			add(LintError, setting+".secret_file", "the secret of the cluster is required")
+		default:
+			if _, err := os.Stat(cluster.SecretFile); err != nil {
+				add(LintError, setting+".secret_file", "cannot read the secret of the cluster: %v", err)
+			}
+		}
+	}
+
+	// Federated Kiali instances
+	if conf.Federation.Enabled && len(conf.Federation.Instances) == 0 {
+		add(LintWarning, "federation.instances", "the federation is enabled without any instance")
+	}
+	federated := map[string]bool{}
+	for i, instance := range conf.Federation.Instances {
+		setting := fmt.Sprintf("federation.instances[%d]", i)
+		switch {
+		case instance.Cluster == "":
+			add(LintError, setting+".cluster", "the cluster of the instance is required")
+		case instance.Cluster == kc.ClusterName:
+			add(LintError, setting+".cluster", "the cluster [%s] is the home cluster", instance.Cluster)
+		case federated[instance.Cluster]:
+			add(LintError, setting+".cluster", "the cluster [%s] is federated more than once", instance.Cluster)
+		case conf.Clustering.GetCluster(instance.Cluster) != nil:
+			add(LintWarning, setting+".cluster", "the cluster [%s] is also a remote cluster, its data is read from the remote Kiali by the federation", instance.Cluster)
+		}
+		federated[instance.Cluster] = true
+		if instance.Auth.UseKialiToken {
+			add(LintError, setting+".auth.use_kiali_token", "the token of Kiali is not sent to the remote Kiali, which requires an API token it issued")
+		}
+		if u, err := url.Parse(instance.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			add(LintError, setting+".url", "invalid URL [%s] of the instance", instance.URL)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintedSettings(findings []LintFinding) []string {
+	settings := []string{}
+	for _, f := range findings {
+		settings = append(settings, f.Setting)
+	}
+	return settings
+}
+
+func TestLintDefaultConfig(t *testing.T) {
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+
+	assert.Empty(t, conf.Lint())
+}
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	conf.Auth.Strategy = AuthStrategyOpenId
+	conf.Auth.OpenId.IssuerUri = "https://accounts.example.com"
+	conf.LoginToken.ExpirationSeconds = 2 * conf.LoginToken.MaxSessionSeconds
+	conf.Server.MetricsPort = conf.Server.Port
+	conf.KubernetesConfig.CacheEnabled = false
+	conf.KubernetesConfig.CacheWarmup.Namespaces = []string{"bookinfo"}
+	conf.KubernetesConfig.ResultsCache.Backend = ResultsCacheBackendRedis
+	conf.API.Namespaces.Exclude = []string{"kube-(.*"}
+	conf.ExternalServices.Prometheus.Auth = Auth{Type: AuthTypeBearer, CAFile: "/missing/ca.crt"}
+	conf.ExternalServices.Grafana.Auth = Auth{Type: AuthTypeBasic, Username: "admin", CAFile: "/missing/ca.crt", InsecureSkipVerify: true}
+	conf.Clustering.Clusters = []RemoteCluster{
+		{Name: "east", SecretFile: "/missing/east", Auth: RemoteClusterAuth{Strategy: ClusterAuthStrategyOpenIdExchange}},
+		{Name: "east", SecretFile: "", Auth: RemoteClusterAuth{Strategy: "password"}},
+		{Name: "north", SecretRef: &RemoteClusterSecretRef{Name: "istio-remote-secret-north"}, Auth: RemoteClusterAuth{Strategy: ClusterAuthStrategyToken}},
+	}
+
+	findings := conf.Lint()
+	assert.Equal([]string{
+		"auth.openid.client_id",
+		"login_token.signing_key",
+		"login_token.expiration_seconds",
+		"server.metrics_port",
+		"kubernetes_config.cache_warmup.namespaces",
+		"kubernetes_config.results_cache.redis.address",
+		"api.namespaces.exclude",
+		"external_services.grafana.auth.insecure_skip_verify",
+		"external_services.prometheus.auth.token",
+		"external_services.prometheus.auth.ca_file",
+		"clustering.clusters[0].secret_file",
+		"clustering.clusters[1].name",
+		"clustering.clusters[1].auth.strategy",
+		"clustering.clusters[1].secret_file",
+		"clustering.clusters[2].secret_ref",
+	}, lintedSettings(findings))
+	assert.Equal(LintWarning, findings[2].Severity)
+	assert.Equal(LintError, findings[10].Severity)
+	assert.Contains(findings[10].Message, "cannot read the secret of the cluster")
+	assert.Equal("the cluster [east] is configured more than once", findings[11].Message)
+}
+
+func TestLintFederation(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	conf.Federation.Enabled = true
+	conf.Federation.Instances = []FederatedInstance{
+		{Cluster: "east", URL: "https://kiali.east.example.com"},
+		{Cluster: "east", URL: "kiali.west"},
+		{URL: "https://kiali.example.com", Auth: Auth{Type: AuthTypeBearer}},
+		{Cluster: "west", URL: "https://kiali.west.example.com", Auth: Auth{Type: AuthTypeBearer, UseKialiToken: true}},
+	}
+
+	assert.Equal([]string{
+		"federation.instances[2].auth.token",
+		"federation.instances[1].cluster",
+		"federation.instances[1].url",
+		"federation.instances[2].cluster",
+		"federation.instances[3].auth.use_kiali_token",
+	}, lintedSettings(conf.Lint()))
+}
+
+func TestLintClusterLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	conf.Clustering.HomeClusterLabels = map[string]string{"region": "eu-west", "-tier": "prod"}
+	conf.Clustering.Clusters = []RemoteCluster{
+		{Name: "east", SecretRef: &RemoteClusterSecretRef{Namespace: "istio-system", Name: "east"}, Auth: RemoteClusterAuth{Strategy: ClusterAuthStrategyToken}, Labels: map[string]string{"region": "eu west"}},
+	}
+
+	findings := conf.Lint()
+	assert.Equal([]string{"clustering.home_cluster_labels", "clustering.clusters[0].labels"}, lintedSettings(findings))
+	assert.Contains(findings[0].Message, "invalid label [-tier]")
+	assert.Contains(findings[1].Message, "invalid value of label [region]")
+}
+
+func TestLintYAML(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(LintYAML("server:\n  port: 20001\n"))
+
+	findings := LintYAML("server:\n  port: 20001\n  web_rot: /kiali\n")
+	assert.Len(findings, 1)
+	assert.Equal(LintWarning, findings[0].Severity)
+	assert.Contains(findings[0].Message, "field web_rot not found")
+
+	findings = LintYAML("server: [")
+	assert.Len(findings, 1)
+	assert.Equal(LintError, findings[0].Severity)
+}
+
+func TestLintLeaderElection(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	conf.Deployment.Namespace = ""
+	conf.KubernetesConfig.LeaderElection.Enabled = true
+	conf.KubernetesConfig.LeaderElection.RenewDeadline = conf.KubernetesConfig.LeaderElection.LeaseDuration
+	conf.KubernetesConfig.ResultsCache.Backend = ResultsCacheBackendMemory
+	conf.KubernetesConfig.ResultsCache.ValidationsSweepPeriod = 60
+
+	assert.Equal([]string{
+		"kubernetes_config.results_cache.backend",
+		"deployment.namespace",
+		"kubernetes_config.leader_election.lease_duration",
+	}, lintedSettings(conf.Lint()))
+
+	conf.KubernetesConfig.LeaderElection = NewConfig().KubernetesConfig.LeaderElection
+	conf.KubernetesConfig.ResultsCache.Backend = ResultsCacheBackendNone
+	findings := conf.Lint()
+	assert.Len(findings, 1)
+	assert.Equal("kubernetes_config.results_cache.validations_sweep_period", findings[0].Setting)
+}
+
+func TestLintServer(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	conf.Server.TLS.MinVersion = "1.0"
+	conf.Server.TLS.CipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA"}
+	conf.Server.WriteTimeout = 0
+
+	assert.Equal([]string{
+		"server.tls.min_version",
+		"server.tls.cipher_suites",
+		"server.write_timeout",
+	}, lintedSettings(conf.Lint()))
+}
@@ -15,9 +15,34 @@ import (
 // See examples for how to use this with your own claim types
 type IanaClaims struct {
 	SessionId string `json:"sid,omitempty"`
+	// Credentials of the user for the remote clusters, keyed by cluster name. They can make the session larger than a
+	// cookie: the session cookies are chunked (see httputil.SetChunkedCookie).
+	ClusterTokens map[string]string `json:"ctk,omitempty"`
+	// Time when the user logged in. Renewed tokens keep it to enforce the maximum duration of the session
+	AuthTime int64 `json:"auth_time,omitempty"`
+	// Refresh token of the OpenId provider, renewing the id_token of the session. Only held by the ciphered sessions of
+	// the "authorization code" flow.
+	RefreshToken string `json:"rtk,omitempty"`
 	jwt.StandardClaims
 }
 
+// NewSessionClaims returns the claims of a new session of the user, identified by a random token id
+func NewSessionClaims(sessionId string, clusterTokens map[string]string, subject string, expiresAt time.Time, issuer string) IanaClaims {
+	now := util.Clock.Now()
+	return IanaClaims{
+		SessionId:     sessionId,
+		ClusterTokens: clusterTokens,
+		AuthTime:      now.Unix(),
+		StandardClaims: jwt.StandardClaims{
+			Id:        util.RandomString(24),
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    issuer,
+		},
+	}
+}
+
 type TokenGenerated struct {
 	Username  string    `json:"username"`
 	Token     string    `json:"token"`
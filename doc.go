@@ -4,6 +4,7 @@ import (
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
 
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph/config/cytoscape"
 	"github.com/kiali/kiali/handlers"
 	"github.com/kiali/kiali/jaeger"
@@ -34,7 +35,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces appTraceQLSearch appTracesTail
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -52,7 +53,7 @@ type AppVersionParam struct {
 	Name string `json:"version"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadLogs
 type ContainerParam struct {
 	// The pod container name. Optional for single-container pod. Otherwise required.
 	//
@@ -61,7 +62,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard appTraceQLSearch appTracesTail serviceTracesTail workloadTracesTail serviceOperations istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource serviceSLO workloadSLO ambientReadiness namespaceProxiesStatus podProxyStats podProxyDumpDiff workloadScale workloadRestart podEvict workloadLogs namespaceEnrollment namespaceEnrollmentPreflight namespaceEnroll namespaceUnenroll wizardTrafficShifting wizardChange wizardConfirm wizardRollback wizardCanaryPromotion wizardFaultInjection faultInjectionsRemove wizardTrafficMirroring trafficMirroringReport trafficPolicyRecommendation namespaceMTLSRollout namespaceMTLSRolloutStart namespaceMTLSRolloutStrict namespaceMTLSRolloutAbort namespaceEvents serviceEvents workloadEvents namespaceChanges
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -79,7 +80,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype faultInjectionsRemove
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -98,7 +99,7 @@ type ObjectTypeParam struct {
 	Name string `json:"object_type"`
 }
 
-// swagger:parameters podDetails podLogs podProxyDump podProxyResource
+// swagger:parameters podDetails podLogs podProxyDump podProxyResource podProxyStats podProxyDumpDiff podEvict
 type PodParam struct {
 	// The pod name.
 	//
@@ -107,6 +108,42 @@ type PodParam struct {
 	Name string `json:"pod"`
 }
 
+// swagger:parameters podProxyStats
+type EnvoyStatsFilterParam struct {
+	// Regular expression the names of the stats have to match
+	//
+	// in: query
+	// required: false
+	Name string `json:"filter"`
+}
+
+// swagger:parameters podLogs workloadLogs
+type ResponseFlagsLogParam struct {
+	// Keep only the Envoy access logs having one of these comma separated response flags, such as UF,UO.
+	//
+	// in: query
+	// required: false
+	Name string `json:"responseFlags"`
+}
+
+// swagger:parameters podProxyDumpDiff
+type OtherPodParam struct {
+	// Name of the pod compared with
+	//
+	// in: query
+	// required: true
+	Name string `json:"otherPod"`
+}
+
+// swagger:parameters podProxyDumpDiff
+type OtherNamespaceParam struct {
+	// Namespace of the pod compared with, the namespace of the first pod by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"otherNamespace"`
+}
+
 // swagger:parameters podProxyResource
 type ResourceParam struct {
 	// The discovery service resource
@@ -116,7 +153,7 @@ type ResourceParam struct {
 	Name string `json:"resource"`
 }
 
-// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces serviceSLO serviceTracesTail serviceOperations wizardTrafficShifting wizardChange wizardConfirm wizardRollback wizardCanaryPromotion wizardFaultInjection wizardTrafficMirroring trafficMirroringReport trafficPolicyRecommendation serviceEvents
 type ServiceParam struct {
 	// The service name.
 	//
@@ -125,7 +162,7 @@ type ServiceParam struct {
 	Name string `json:"service"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadLogs
 type SinceTimeParam struct {
 	// The start time for fetching logs. UNIX time in seconds. Default is all logs.
 	//
@@ -134,7 +171,7 @@ type SinceTimeParam struct {
 	Name string `json:"sinceTime"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadLogs
 type DurationLogParam struct {
 	// Query time-range duration (Golang string duration). Duration starts on
 	// `sinceTime` if set, or the time for the first log message if not set.
@@ -144,6 +181,44 @@ type DurationLogParam struct {
 	Name string `json:"duration"`
 }
 
+// swagger:parameters podLogs workloadLogs
+type TraceIdLogParam struct {
+	// Keep only the log lines of this trace, from the trace IDs found in the log lines.
+	//
+	// in: query
+	// required: false
+	Name string `json:"traceId"`
+}
+
+// swagger:parameters podLogs workloadLogs
+type FilterLogParam struct {
+	// Keep only the log lines matching this regular expression.
+	//
+	// in: query
+	// required: false
+	Name string `json:"filter"`
+}
+
+// swagger:parameters podLogs
+type FollowLogParam struct {
+	// Stream the log lines as server-sent events while they are written, of all the containers when none is set. Each
+	// stream ends after 25 seconds, the client reconnecting with the Last-Event-ID header to get the next lines.
+	//
+	// in: query
+	// required: false
+	Name string `json:"follow"`
+}
+
+// swagger:parameters podLogs workloadLogs
+type LogSourceParam struct {
+	// Source of the logs: "kubernetes" (default) or "loki", which keeps the logs of the restarted pods. The logs read
+	// from Loki cannot be followed.
+	//
+	// in: query
+	// required: false
+	Name string `json:"source"`
+}
+
 // swagger:parameters traceDetails
 type TraceIDParam struct {
 	// The trace ID.
@@ -162,7 +237,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces workloadSLO workloadTracesTail workloadScale workloadRestart workloadLogs workloadEvents
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -178,7 +253,7 @@ type WorkloadParam struct {
 
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [deadNode, istio, aggregateNode, responseTime, securityPolicy, serviceEntry, sidecarsCheck, unusedNode].
+	// Comma-separated list of Appenders to run. Available appenders: [alerts, deadNode, istio, aggregateNode, responseTime, securityPolicy, serviceEntry, sidecarsCheck, unusedNode].
 	//
 	// in: query
 	// required: false
@@ -369,6 +444,64 @@ type ReporterParam struct {
 	Name string `json:"reporter"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
+type ExemplarsParam struct {
+	// Include the exemplars of the request metrics, linking data points to traces.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"exemplars"`
+}
+
+// swagger:parameters appTraces serviceTraces workloadTraces appSpans serviceSpans workloadSpans appTraceQLSearch
+type TracingClusterParam struct {
+	// Cluster whose tracing backend is queried, when the traces of several clusters are stored in different backends.
+	// Defaults to the backends of all the clusters.
+	//
+	// in: query
+	// required: false
+	Name string `json:"cluster"`
+}
+
+// swagger:parameters appTraceQLSearch
+type TraceQLParam struct {
+	// TraceQL spanset filters the traces must match, i.e. { span.http.status_code >= 500 } >> { name = "db" }.
+	//
+	// in: query
+	// required: false
+	Name string `json:"q"`
+}
+
+// swagger:parameters appTraceQLSearch
+type TraceQLAttributesParam struct {
+	// JSON map of the attributes the spans of the app must have, i.e. {"http.method":"POST"}.
+	//
+	// in: query
+	// required: false
+	Name string `json:"attributes"`
+}
+
+// swagger:parameters appTracesTail serviceTracesTail workloadTracesTail
+type TracesTailSinceParam struct {
+	// Cursor of the tail, in microseconds since epoch: the traces starting after it are sent. Default: now.
+	// The Last-Event-ID header takes precedence.
+	//
+	// in: query
+	// required: false
+	Name string `json:"since"`
+}
+
+// swagger:parameters appTracesTail serviceTracesTail workloadTracesTail
+type TracesTailIntervalParam struct {
+	// Interval between the polls of the tracing backend, at least 1s.
+	//
+	// in: query
+	// required: false
+	// default: 2s
+	Name string `json:"interval"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
 type StepParam struct {
 	// Step between [graph] datapoints, in seconds.
@@ -433,6 +566,35 @@ type NotFoundError struct {
 	} `json:"body"`
 }
 
+// A ForbiddenError is the error message that means the request is not allowed for the client
+//
+// swagger:response forbiddenError
+type ForbiddenError struct {
+	// in: body
+	Body struct {
+		// HTTP status code
+		// example: 403
+		// default: 403
+		Code    int32 `json:"code"`
+		Message error `json:"message"`
+	} `json:"body"`
+}
+
+// A TooManyRequestsError is the error message that means the request is denied for now, i.e. an eviction violating a
+// PodDisruptionBudget
+//
+// swagger:response tooManyRequestsError
+type TooManyRequestsError struct {
+	// in: body
+	Body struct {
+		// HTTP status code
+		// example: 429
+		// default: 429
+		Code    int32 `json:"code"`
+		Message error `json:"message"`
+	} `json:"body"`
+}
+
 // A NotAcceptable is the error message that means request can't be accepted
 //
 // swagger:response notAcceptableError
@@ -580,6 +742,23 @@ type ErrorTracesResponse struct {
 	Body int
 }
 
+// swagger:parameters errorTraces
+type ErrorTraceGroupsParam struct {
+	// Group the traces in error by failure signature instead of counting them. The response is then the groups of
+	// traces.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"groups"`
+}
+
+// Traces in error grouped by failure signature, returned by errorTraces with groups=true
+// swagger:response errorTraceGroupsResponse
+type ErrorTraceGroupsResponse struct {
+	// in:body
+	Body models.ErrorTraceGroups
+}
+
 // Listing all the information related to a Span
 // swagger:response spansResponse
 type SpansResponse struct {
@@ -685,6 +864,20 @@ type ConfigDumpResourceResponse struct {
 	Body map[string]interface{}
 }
 
+// Return the differences between the configurations of two envoy proxies
+// swagger:response configDumpDiff
+type ConfigDumpDiffResponse struct {
+	// in:body
+	Body models.EnvoyConfigDiff
+}
+
+// Return the stats of a given envoy proxy
+// swagger:response envoyStats
+type EnvoyStatsResponse struct {
+	// in:body
+	Body []models.EnvoyStat
+}
+
 //////////////////
 // SWAGGER MODELS
 //////////////////
@@ -745,3 +938,676 @@ type MetricsStatsResponse struct {
 	// in: body
 	Body models.MetricsStats
 }
+
+// swagger:parameters apiTokenRevoke
+type ApiTokenIdParam struct {
+	// The id of the API token.
+	//
+	// in: path
+	// required: true
+	Name string `json:"id"`
+}
+
+// Posted parameters to create an API token
+// swagger:parameters apiTokenCreate
+type ApiTokenRequestBody struct {
+	// in: body
+	Body models.ApiTokenRequest
+}
+
+// List of the API tokens of the user
+// swagger:response apiTokenListResponse
+type ApiTokenListResponse struct {
+	// in: body
+	Body []models.ApiToken
+}
+
+// The created API token, including the token itself that is only returned once
+// swagger:response apiTokenCreatedResponse
+type ApiTokenCreatedResponse struct {
+	// in: body
+	Body models.ApiTokenCreated
+}
+
+// Status of the caches of Kiali
+// swagger:response cacheStatusResponse
+type CacheStatusResponse struct {
+	// in: body
+	Body models.CacheStatus
+}
+
+// Attainment and burn rates of the service level objectives of a service or workload
+// swagger:response sloResponse
+type SLOResponse struct {
+	// in: body
+	Body models.SLOStatus
+}
+
+// Traces matching a TraceQL search, with their matching spans
+// swagger:response traceQLSearchResponse
+type TraceQLSearchResponse struct {
+	// in: body
+	Body jaeger.TraceQLResponse
+}
+
+// Server-sent events of the new traces: each "trace" event holds a trace, its ID being the cursor to resume from
+// swagger:response tracesTailResponse
+type TracesTailResponse struct {
+	// in: body
+	Body jaegerModels.Trace
+}
+
+// Requests, errors and latency percentiles of the operations of a service
+// swagger:response operationStatsResponse
+type OperationStatsResponse struct {
+	// in: body
+	Body []models.OperationStats
+}
+
+// Results of the probes of the tracing backend
+// swagger:response tracingDiagnosisResponse
+type TracingDiagnosisResponse struct {
+	// in: body
+	Body models.TracingDiagnosis
+}
+
+// swagger:parameters controlPlaneRevisionsCompare
+type RevisionFromParam struct {
+	// Revision of the control plane migrated from, "default" for istiod deployed without revision
+	//
+	// in: query
+	// required: true
+	Name string `json:"from"`
+}
+
+// swagger:parameters controlPlaneRevisionsCompare
+type RevisionToParam struct {
+	// Revision of the control plane migrated to
+	//
+	// in: query
+	// required: true
+	Name string `json:"to"`
+}
+
+// Revisions of the control plane running in the mesh
+// swagger:response controlPlaneRevisionsResponse
+type ControlPlaneRevisionsResponse struct {
+	// in: body
+	Body []models.ControlPlaneRevision
+}
+
+// Comparison of two revisions of the control plane
+// swagger:response revisionComparisonResponse
+type RevisionComparisonResponse struct {
+	// in: body
+	Body models.RevisionComparison
+}
+
+// What blocks or changes when migrating a namespace from sidecars to ambient
+// swagger:response ambientReadinessResponse
+type AmbientReadinessResponse struct {
+	// in: body
+	Body models.AmbientReadiness
+}
+
+// swagger:parameters meshCertificates
+type CertificatesNamespaceParam struct {
+	// Namespace of the proxies whose workload certificates are read from their config dumps
+	//
+	// in: query
+	// required: false
+	Name string `json:"namespace"`
+}
+
+// Certificates of the control plane and of the proxies, with their expiration
+// swagger:response meshCertificatesResponse
+type MeshCertificatesResponse struct {
+	// in: body
+	Body models.MeshCertificates
+}
+
+// xDS distribution status of the proxies
+// swagger:response proxiesSyncStatusResponse
+type ProxiesSyncStatusResponse struct {
+	// in: body
+	Body []models.ProxySyncStatus
+}
+
+// Settings of the mesh differing between its clusters
+// swagger:response meshDriftResponse
+type MeshDriftResponse struct {
+	// in: body
+	Body models.MeshDrift
+}
+
+// swagger:parameters ztunnelConfigDump
+type ZtunnelNamespaceParam struct {
+	// Namespace of the entries, every namespace accessible by the user when not set
+	//
+	// in: query
+	// required: false
+	Name string `json:"namespace"`
+}
+
+// swagger:parameters ztunnelConfigDump
+type ZtunnelWorkloadParam struct {
+	// Name of the workload whose instances are returned
+	//
+	// in: query
+	// required: false
+	Name string `json:"workload"`
+}
+
+// Workloads, services and policies known by the ztunnels
+// swagger:response ztunnelConfigResponse
+type ZtunnelConfigResponse struct {
+	// in: body
+	Body models.ZtunnelConfig
+}
+
+// swagger:parameters workloadScale workloadRestart
+type WorkloadTypeParam struct {
+	// Type of the workload, looked up when not set, required for a Rollout
+	//
+	// in: query
+	// required: false
+	// pattern: ^(Deployment|StatefulSet|Rollout)$
+	Name string `json:"type"`
+}
+
+// swagger:parameters workloadScale
+type WorkloadScaleBody struct {
+	// Number of replicas, as {"replicas": 3}
+	//
+	// in: body
+	// required: true
+	Body struct {
+		// required: true
+		Replicas int32 `json:"replicas"`
+	}
+}
+
+// Number of replicas a workload was scaled to
+// swagger:response workloadScaleResponse
+type WorkloadScaleResponse struct {
+	// in: body
+	Body models.WorkloadScale
+}
+
+// Time the pods of a workload were restarted at
+// swagger:response workloadRestartResponse
+type WorkloadRestartResponse struct {
+	// in: body
+	Body models.WorkloadRestart
+}
+
+// Confirmation of the eviction of a pod
+// swagger:response evictResponse
+type EvictResponse struct {
+	// in: body
+	Body string
+}
+
+// Log entries of the pods of a workload, merged by time
+// swagger:response workloadLogsResponse
+type WorkloadLogsResponse struct {
+	// in: body
+	Body business.PodLog
+}
+
+// Posted mode and revision of the enrollment of a namespace
+// swagger:parameters namespaceEnrollmentPreflight namespaceEnroll
+type EnrollmentRequestBody struct {
+	// in: body
+	Body models.EnrollmentRequest
+}
+
+// Enrollment of a namespace in the mesh
+// swagger:response namespaceEnrollmentResponse
+type NamespaceEnrollmentResponse struct {
+	// in: body
+	Body models.NamespaceEnrollment
+}
+
+// Checks of the enrollment of a namespace
+// swagger:response enrollmentPreflightResponse
+type EnrollmentPreflightResponse struct {
+	// in: body
+	Body models.EnrollmentPreflight
+}
+
+// Posted weights of the workloads of a service
+// swagger:parameters wizardTrafficShifting
+type TrafficShiftingRequestBody struct {
+	// in: body
+	Body models.TrafficShiftingRequest
+}
+
+// Change applied by a wizard on a service
+// swagger:response wizardChangeResponse
+type WizardChangeResponse struct {
+	// in: body
+	Body models.WizardChange
+}
+
+// Posted success criteria of a canary subset
+// swagger:parameters wizardCanaryPromotion
+type CanaryPromotionRequestBody struct {
+	// in: body
+	Body models.CanaryPromotionRequest
+}
+
+// Evaluation of the success criteria of a canary subset, with its promotion
+// swagger:response canaryPromotionResponse
+type CanaryPromotionResponse struct {
+	// in: body
+	Body models.CanaryPromotion
+}
+
+// Posted faults injected in the routes of a service
+// swagger:parameters wizardFaultInjection
+type FaultInjectionRequestBody struct {
+	// in: body
+	Body models.FaultInjectionRequest
+}
+
+// Faults injected in the VirtualServices of the mesh
+// swagger:response faultInjectionsResponse
+type FaultInjectionsResponse struct {
+	// in: body
+	Body []models.FaultInjection
+}
+
+// swagger:parameters trafficMirroringReport
+type MirroringWindowParam struct {
+	// Window of the metrics compared, 10m by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"window"`
+}
+
+// Posted shadow destination of the traffic of a service
+// swagger:parameters wizardTrafficMirroring
+type TrafficMirroringRequestBody struct {
+	// in: body
+	Body models.TrafficMirroringRequest
+}
+
+// Comparison of the responses of the primary and shadow destinations of a service
+// swagger:response trafficMirroringReportResponse
+type TrafficMirroringReportResponse struct {
+	// in: body
+	Body models.TrafficMirroringReport
+}
+
+// swagger:parameters trafficPolicyRecommendation
+type TrafficPolicyWindowParam struct {
+	// Window of the traffic observed, 1h by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"window"`
+}
+
+// Recommended traffic policy of a service
+// swagger:response trafficPolicyRecommendationResponse
+type TrafficPolicyRecommendationResponse struct {
+	// in: body
+	Body models.TrafficPolicyRecommendation
+}
+
+// Posted soak window of an mTLS rollout
+// swagger:parameters namespaceMTLSRolloutStart
+type MTLSRolloutRequestBody struct {
+	// in: body
+	Body models.MTLSRolloutRequest
+}
+
+// swagger:parameters namespaceMTLSRolloutStrict
+type MTLSRolloutStrictRequestBody struct {
+	// in: body
+	Body models.MTLSRolloutStrictRequest
+}
+
+// State of the mTLS rollout of a namespace
+// swagger:response mtlsRolloutResponse
+type MTLSRolloutResponse struct {
+	// in: body
+	Body models.MTLSRollout
+}
+
+// swagger:parameters meshSidecarSizing
+type SidecarSizingWindowParam struct {
+	// Window of the usage of the proxies, 1d by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"window"`
+}
+
+// Sizing of the injected proxies
+// swagger:response sidecarSizingResponse
+type SidecarSizingResponse struct {
+	// in: body
+	Body models.SidecarSizingReport
+}
+
+// swagger:parameters meshOutboundTrafficPolicy meshExternalServices
+type OutboundTrafficWindowParam struct {
+	// Window of the traffic to the external hosts, 1d by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"window"`
+}
+
+// External hosts needing a ServiceEntry
+// swagger:response outboundTrafficPolicyResponse
+type OutboundTrafficPolicyResponse struct {
+	// in: body
+	Body models.OutboundTrafficPolicyAnalysis
+}
+
+// Inventory of the external hosts of the mesh
+// swagger:response externalServicesResponse
+type ExternalServicesResponse struct {
+	// in: body
+	Body models.ExternalServiceInventory
+}
+
+// Coverage of the data plane of the namespaces
+// swagger:response injectionCoverageResponse
+type InjectionCoverageResponse struct {
+	// in: body
+	Body models.InjectionCoverage
+}
+
+// Problems of the configuration
+// swagger:response configValidationResponse
+type ConfigValidationResponse struct {
+	// in: body
+	Body []config.LintFinding
+}
+
+// swagger:parameters debugProfile
+type DebugProfileParam struct {
+	// The profile name, i.e. goroutine, heap or allocs. The CPU profile is profile, the execution trace is trace.
+	//
+	// in: path
+	// required: true
+	Name string `json:"profile"`
+}
+
+// swagger:parameters debugProfile
+type DebugProfileSecondsParam struct {
+	// Seconds of CPU profiling or tracing, 10 by default, between 1 and 60
+	//
+	// in: query
+	// required: false
+	Name string `json:"seconds"`
+}
+
+// swagger:parameters debugProfile
+type DebugProfileDebugParam struct {
+	// Text format instead of the pprof format when greater than 0. The goroutine dump lists the stacks with 2.
+	//
+	// in: query
+	// required: false
+	Name string `json:"debug"`
+}
+
+// Profiles of Kiali
+// swagger:response debugProfilesResponse
+type DebugProfilesResponse struct {
+	// in: body
+	Body []handlers.DebugProfile
+}
+
+// Profile of Kiali in the pprof format
+// swagger:response debugProfileResponse
+type DebugProfileResponse struct {
+	// in: body
+	Body []byte
+}
+
+// swagger:parameters clusterDeregister
+type ClusterNameParam struct {
+	// The name of the cluster.
+	//
+	// in: path
+	// required: true
+	Name string `json:"cluster"`
+}
+
+// Posted parameters to register a remote cluster
+// swagger:parameters clusterRegister
+type ClusterRegistrationBody struct {
+	// in: body
+	Body models.ClusterRegistration
+}
+
+// List of the remote clusters
+// swagger:response clustersResponse
+type ClustersResponse struct {
+	// in: body
+	Body []models.RemoteCluster
+}
+
+// The registered cluster
+// swagger:response clusterResponse
+type ClusterResponse struct {
+	// in: body
+	Body models.RemoteCluster
+}
+
+// Status of the connections to the clusters
+// swagger:response clustersStatusResponse
+type ClustersStatusResponse struct {
+	// in: body
+	Body []models.ClusterStatus
+}
+
+// Optional APIs supported by the clusters
+// swagger:response clustersCapabilitiesResponse
+type ClustersCapabilitiesResponse struct {
+	// in: body
+	Body []models.ClusterCapabilities
+}
+
+// swagger:parameters clusterTraffic
+type ClusterTrafficWindowParam struct {
+	// Window of the rates, 1h by default
+	//
+	// in: query
+	// required: false
+	Name string `json:"window"`
+}
+
+// Traffic between the clusters
+// swagger:response clusterTrafficResponse
+type ClusterTrafficResponse struct {
+	// in: body
+	Body models.ClusterTrafficMatrix
+}
+
+// swagger:parameters federationProxy
+type FederatedClusterParam struct {
+	// The cluster of the remote Kiali.
+	//
+	// in: path
+	// required: true
+	Name string `json:"cluster"`
+}
+
+// swagger:parameters federationProxy
+type FederationPathParam struct {
+	// The path in the API of the remote Kiali, i.e. api/namespaces/bookinfo/health.
+	//
+	// in: path
+	// required: true
+	Name string `json:"path"`
+}
+
+// Reachability of the remote Kiali instances
+// swagger:response federatedInstancesResponse
+type FederatedInstancesResponse struct {
+	// in: body
+	Body []models.FederatedInstanceStatus
+}
+
+// Namespaces of the remote Kiali instances
+// swagger:response federatedNamespacesResponse
+type FederatedNamespacesResponse struct {
+	// in: body
+	Body models.FederatedNamespaces
+}
+
+// Answer of the remote Kiali
+// swagger:response federationProxyResponse
+type FederationProxyResponse struct {
+	// in: body
+	Body interface{}
+}
+
+// swagger:parameters clusters clustersStatus clustersCapabilities clusterTraffic clustersEastWestProbe meshDrift
+type ClusterSelectorParam struct {
+	// Selector of the clusters on their labels, i.e. region=eu-west,environment!=dev. All the clusters by default.
+	//
+	// in: query
+	// required: false
+	Name string `json:"clusters"`
+}
+
+// Connectivity between the clusters through their east-west gateways
+// swagger:response eastWestProbeResponse
+type EastWestProbeResponse struct {
+	// in: body
+	Body models.EastWestProbe
+}
+
+// swagger:parameters istioConfigList serviceList workloadList appList namespaceValidations
+type ListFormatParam struct {
+	// Format of the list: csv to export it, flattened, as CSV. The validations are then the checks of the objects.
+	//
+	// in: query
+	// required: false
+	Name string `json:"format"`
+}
+
+// swagger:parameters savedViewDetails savedViewUpdate savedViewDelete
+type SavedViewIdParam struct {
+	// The id of the saved view.
+	//
+	// in: path
+	// required: true
+	Name string `json:"id"`
+}
+
+// Posted parameters to save a view
+// swagger:parameters savedViewCreate savedViewUpdate
+type SavedViewRequestBody struct {
+	// in: body
+	Body models.SavedViewRequest
+}
+
+// List of the saved views
+// swagger:response savedViewListResponse
+type SavedViewListResponse struct {
+	// in: body
+	Body []models.SavedView
+}
+
+// A saved view
+// swagger:response savedViewResponse
+type SavedViewResponse struct {
+	// in: body
+	Body models.SavedView
+}
+
+// swagger:parameters userPreferencesUpdate
+type UserPreferencesBody struct {
+	// in: body
+	Body models.UserPreferences
+}
+
+// The preferences of the user
+// swagger:response userPreferencesResponse
+type UserPreferencesResponse struct {
+	// in: body
+	Body models.UserPreferences
+}
+
+// swagger:parameters alerts
+type AlertsParams struct {
+	// Only the alerts about this namespace
+	//
+	// in: query
+	// required: false
+	Namespace string `json:"namespace"`
+	// Only the alerts about this service
+	//
+	// in: query
+	// required: false
+	Service string `json:"service"`
+	// Only the alerts about this workload
+	//
+	// in: query
+	// required: false
+	Workload string `json:"workload"`
+	// Only the alerts about this app
+	//
+	// in: query
+	// required: false
+	App string `json:"app"`
+}
+
+// The alerts firing in Alertmanager
+// swagger:response alertsResponse
+type AlertsResponse struct {
+	// in: body
+	Body []models.Alert
+}
+
+// swagger:parameters namespaceEvents serviceEvents workloadEvents
+type EventTypeParam struct {
+	// Only the events of this type, Warning
+	//
+	// in: query
+	// required: false
+	Type string `json:"type"`
+}
+
+// The recent Kubernetes events, the latest first
+// swagger:response kubernetesEventsResponse
+type KubernetesEventsResponse struct {
+	// in: body
+	Body models.KubernetesEvents
+}
+
+// swagger:parameters namespaceChanges
+type ChangeTimelineParams struct {
+	// Only the changes of this service
+	//
+	// in: query
+	// required: false
+	Service string `json:"service"`
+	// The time window of the changes, in seconds
+	//
+	// in: query
+	// required: false
+	// default: 3600
+	Duration int64 `json:"duration"`
+	// The end of the time window, in seconds since the epoch. Defaults to now.
+	//
+	// in: query
+	// required: false
+	QueryTime int64 `json:"queryTime"`
+}
+
+// The changes of a namespace, the oldest first
+// swagger:response changeTimelineResponse
+type ChangeTimelineResponse struct {
+	// in: body
+	Body models.ChangeTimeline
+}
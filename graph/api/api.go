@@ -5,9 +5,11 @@ import (
 	"net/http"
 
 	"github.com/kiali/kiali/business"
+	kialiConfig "github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/config/cytoscape"
 	"github.com/kiali/kiali/graph/telemetry/istio"
+	"github.com/kiali/kiali/graph/telemetry/tracing"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
@@ -21,9 +23,9 @@ func GraphNamespaces(business *business.Layer, o graph.Options) (code int, confi
 
 	switch o.TelemetryVendor {
 	case graph.VendorIstio:
-		prom, err := prometheus.NewClient()
-		graph.CheckError(err)
-		code, config = graphNamespacesIstio(business, prom, o)
+		code, config = graphNamespacesIstioOrTracing(business, o)
+	case graph.VendorTracing:
+		code, config = graphNamespacesTracing(business, o)
 	default:
 		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
 	}
@@ -34,6 +36,50 @@ func GraphNamespaces(business *business.Layer, o graph.Options) (code int, confi
 	return code, config
 }
 
+// graphNamespacesIstioOrTracing generates the graph from the Istio telemetry. When Prometheus fails and tracing is
+// enabled, the graph is generated from the traces instead, rather than failing.
+func graphNamespacesIstioOrTracing(business *business.Layer, o graph.Options) (code int, vendorConfig interface{}) {
+	if !kialiConfig.Get().ExternalServices.Tracing.Enabled {
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		return graphNamespacesIstio(business, prom, o)
+	}
+
+	promErr := func() (promErr interface{}) {
+		defer func() {
+			if r := recover(); r != nil {
+				// Bad requests and forbidden namespaces are not related to Prometheus
+				if _, isResponse := r.(graph.Response); isResponse {
+					panic(r)
+				}
+				promErr = r
+			}
+		}()
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, vendorConfig = graphNamespacesIstio(business, prom, o)
+		return nil
+	}()
+	if promErr == nil {
+		return code, vendorConfig
+	}
+	if errFunc, ok := promErr.(func() string); ok {
+		promErr = errFunc()
+	}
+	log.Warningf("Generating the graph from the traces, the Istio telemetry is not available: %v", promErr)
+	return graphNamespacesTracing(business, o)
+}
+
+// graphNamespacesTracing generates the graph from the traces
+func graphNamespacesTracing(business *business.Layer, o graph.Options) (code int, vendorConfig interface{}) {
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+
+	trafficMap := tracing.BuildNamespacesTrafficMap(o.TelemetryOptions, globalInfo)
+	o.ConfigOptions.TraceDerived = true
+	return generateGraph(trafficMap, o)
+}
+
 // graphNamespacesIstio provides a test hook that accepts mock clients
 func graphNamespacesIstio(business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, config interface{}) {
 
@@ -68,6 +68,7 @@ type NodeData struct {
 	Aggregate       string              `json:"aggregate,omitempty"`       // set like "<aggregate>=<aggregateVal>"
 	DestServices    []graph.ServiceName `json:"destServices,omitempty"`    // requested services for [dest] node
 	Traffic         []ProtocolTraffic   `json:"traffic,omitempty"`         // traffic rates for all detected protocols
+	FiringAlerts    int                 `json:"firingAlerts,omitempty"`    // number of alerts firing in Alertmanager about the node
 	HasCB           bool                `json:"hasCB,omitempty"`           // true (has circuit breaker) | false
 	HasMissingSC    bool                `json:"hasMissingSC,omitempty"`    // true (has missing sidecar) | false
 	HasVS           bool                `json:"hasVS,omitempty"`           // true (has route rule) | false
@@ -109,10 +110,11 @@ type Elements struct {
 }
 
 type Config struct {
-	Timestamp int64    `json:"timestamp"`
-	Duration  int64    `json:"duration"`
-	GraphType string   `json:"graphType"`
-	Elements  Elements `json:"elements"`
+	Timestamp    int64    `json:"timestamp"`
+	Duration     int64    `json:"duration"`
+	GraphType    string   `json:"graphType"`
+	TraceDerived bool     `json:"traceDerived,omitempty"` // the traffic is sampled from the traces
+	Elements     Elements `json:"elements"`
 }
 
 func nodeHash(id string) string {
@@ -175,10 +177,11 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 
 	elements := Elements{nodes, edges}
 	result = Config{
-		Duration:  int64(o.Duration.Seconds()),
-		Timestamp: o.QueryTime,
-		GraphType: o.GraphType,
-		Elements:  elements,
+		Duration:     int64(o.Duration.Seconds()),
+		Timestamp:    o.QueryTime,
+		GraphType:    o.GraphType,
+		TraceDerived: o.TraceDerived,
+		Elements:     elements,
 	}
 	return result
 }
@@ -234,6 +237,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.HasMissingSC = val.(bool)
 		}
 
+		// node may have firing alerts
+		if val, ok := n.Metadata[graph.FiringAlerts]; ok {
+			nd.FiringAlerts = val.(int)
+		}
+
 		// check if node is misconfigured
 		if val, ok := n.Metadata[graph.IsMisconfigured]; ok {
 			nd.IsMisconfigured = val.(string)
@@ -17,6 +17,7 @@ const (
 	AggregateValue  MetadataKey = "aggregateValue"
 	DestPrincipal   MetadataKey = "destPrincipal"
 	DestServices    MetadataKey = "destServices"
+	FiringAlerts    MetadataKey = "firingAlerts"
 	HasCB           MetadataKey = "hasCB"
 	HasMissingSC    MetadataKey = "hasMissingSC"
 	HasVS           MetadataKey = "hasVS"
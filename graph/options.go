@@ -16,12 +16,14 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 )
 
 // The supported vendors
 const (
 	VendorCytoscape        string = "cytoscape"
 	VendorIstio            string = "istio"
+	VendorTracing          string = "tracing"
 	defaultConfigVendor    string = VendorCytoscape
 	defaultTelemetryVendor string = VendorIstio
 )
@@ -63,7 +65,8 @@ type CommonOptions struct {
 
 // ConfigOptions are those supplied to Config Vendors
 type ConfigOptions struct {
-	GroupBy string
+	GroupBy      string
+	TraceDerived bool // the traffic is derived from the traces, rather than from the telemetry metrics
 	CommonOptions
 }
 
@@ -172,7 +175,7 @@ func NewOptions(r *net_http.Request) Options {
 	}
 	if telemetryVendor == "" {
 		telemetryVendor = defaultTelemetryVendor
-	} else if telemetryVendor != VendorIstio {
+	} else if telemetryVendor != VendorIstio && telemetryVendor != VendorTracing {
 		BadRequest(fmt.Sprintf("Invalid telemetryVendor [%s]", telemetryVendor))
 	}
 
@@ -191,7 +194,9 @@ func NewOptions(r *net_http.Request) Options {
 		Error("token missing in request context")
 	}
 
-	accessibleNamespaces := getAccessibleNamespaces(token)
+	identity, _ := r.Context().Value("userIdentity").(models.UserIdentity)
+	impersonated, _ := r.Context().Value("impersonatedUser").(models.UserIdentity)
+	accessibleNamespaces := getAccessibleNamespaces(token, identity, impersonated)
 
 	// If path variable is set then it is the only relevant namespace (it's a node graph)
 	// Else if namespaces query param is set it specifies the relevant namespaces
@@ -274,13 +279,20 @@ func (o *TelemetryOptions) GetGraphKind() string {
 // getAccessibleNamespaces returns a Set of all namespaces accessible to the user.
 // The Set is implemented using the map convention. Each map entry is set to the
 // creation timestamp of the namespace, to be used to ensure valid time ranges for
-// queries against the namespace.
-func getAccessibleNamespaces(token string) map[string]time.Time {
+// queries against the namespace. The token impersonates the given user, if any.
+func getAccessibleNamespaces(token string, identity, impersonated models.UserIdentity) map[string]time.Time {
 	// Get the namespaces
-	business, err := business.Get(token)
+	var layer *business.Layer
+	var err error
+	if impersonated.Username != "" {
+		layer, err = business.GetImpersonating(token, impersonated)
+	} else {
+		layer, err = business.Get(token)
+	}
 	CheckError(err)
+	layer.Namespace.RestrictToUser(identity)
 
-	namespaces, err := business.Namespace.GetNamespaces()
+	namespaces, err := layer.Namespace.GetNamespaces()
 	CheckError(err)
 
 	// Create a map to store the namespaces
@@ -0,0 +1,76 @@
+package appender
+
+import (
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	AlertsAppenderName = "alerts"
+	firingAlertsKey    = "firingAlerts" // global vendor info
+)
+
+// AlertsAppender counts the alerts firing in Alertmanager about the workload, app or service of each node. An
+// unavailable Alertmanager doesn't fail the graph, its nodes are not counted.
+// Name: alerts
+type AlertsAppender struct{}
+
+// Name implements Appender
+func (a AlertsAppender) Name() string {
+	return AlertsAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AlertsAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	alerts, ok := globalInfo.Vendor[firingAlertsKey].([]models.Alert)
+	if !ok {
+		var err error
+		if alerts, err = globalInfo.Business.Alert.GetFiringAlerts(models.AlertCriteria{}); err != nil {
+			log.Warningf("The firing alerts are not added to the graph: %v", err)
+			alerts = []models.Alert{}
+		}
+		globalInfo.Vendor[firingAlertsKey] = alerts
+	}
+
+	a.countAlerts(trafficMap, alerts, namespaceInfo.Namespace)
+}
+
+func (a AlertsAppender) countAlerts(trafficMap graph.TrafficMap, alerts []models.Alert, namespace string) {
+	for _, n := range trafficMap {
+		// Skip the nodes outside the requested namespace, they are counted with their own namespace
+		if n.Namespace != namespace {
+			continue
+		}
+
+		criteria := models.AlertCriteria{Namespace: n.Namespace}
+		var name string
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			criteria.Workload, name = n.Workload, n.Workload
+		case graph.NodeTypeApp:
+			criteria.App, name = n.App, n.App
+		case graph.NodeTypeService:
+			criteria.Service, name = n.Service, n.Service
+		default:
+			continue
+		}
+		if !graph.IsOK(name) {
+			continue
+		}
+
+		firing := 0
+		for _, alert := range alerts {
+			if criteria.Matches(alert) {
+				firing++
+			}
+		}
+		if firing > 0 {
+			n.Metadata[graph.FiringAlerts] = firing
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package appender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+)
+
+func TestAlertsCounted(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	workload := graph.NewNode("bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	trafficMap[workload.ID] = &workload
+	service := graph.NewNode("bookinfo", "reviews", "bookinfo", graph.Unknown, graph.Unknown, graph.Unknown, graph.GraphTypeWorkload)
+	trafficMap[service.ID] = &service
+	other := graph.NewNode("istio-system", "", "istio-system", "istio-ingressgateway", "istio-ingressgateway", "latest", graph.GraphTypeWorkload)
+	trafficMap[other.ID] = &other
+
+	alerts := []models.Alert{
+		{Name: "PodCrashLooping", Namespace: "bookinfo", Workload: "reviews-v1"},
+		{Name: "HighErrorRate", Namespace: "bookinfo", Service: "reviews"},
+		{Name: "HighLatency", Namespace: "bookinfo", Service: "reviews"},
+		{Name: "GatewayDown", Namespace: "istio-system", Workload: "istio-ingressgateway"},
+	}
+
+	a := AlertsAppender{}
+	a.countAlerts(trafficMap, alerts, "bookinfo")
+
+	assert.Equal(1, workload.Metadata[graph.FiringAlerts])
+	assert.Equal(2, service.Metadata[graph.FiringAlerts])
+	_, ok := other.Metadata[graph.FiringAlerts]
+	assert.False(ok)
+}
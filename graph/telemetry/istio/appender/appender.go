@@ -22,6 +22,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 	if !o.Appenders.All {
 		for _, appenderName := range o.Appenders.AppenderNames {
 			switch appenderName {
+			case AlertsAppenderName:
+				requestedAppenders[AlertsAppenderName] = true
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
 			case DeadNodeAppenderName:
@@ -128,6 +130,11 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		a := SidecarsCheckAppender{}
 		appenders = append(appenders, a)
 	}
+	// Alertmanager is optional, its alerts are added to all the graphs only when it is enabled
+	if _, ok := requestedAppenders[AlertsAppenderName]; ok || (o.Appenders.All && config.Get().ExternalServices.Alertmanager.Enabled) {
+		a := AlertsAppender{}
+		appenders = append(appenders, a)
+	}
 
 	return appenders
 }
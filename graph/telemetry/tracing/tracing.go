@@ -0,0 +1,297 @@
+// Package tracing builds TrafficMaps from the spans of the traces, when the Istio telemetry is not available.
+package tracing
+
+// Tracing.go is responsible for generating TrafficMaps from the traces reported by the proxies. The request
+// traffic is the traffic of the sampled requests, so the rates are underestimated by the sampling rate of the
+// traces: the graph is meant to show the dependencies of the services, not their traffic.
+//
+// A request is the server span of the destination proxy, child of the client span of the source proxy. The nodes
+// are identified by the tags set by the Istio proxies:
+//   istio.namespace, istio.canonical_service and istio.canonical_revision: namespace, app and version
+//   node_id: the pod, from which the workload is inferred
+//   upstream_cluster: the destination service, on the client span
+//
+// The appenders are not applied, most of them query Prometheus.
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Maximum number of traces fetched for each app
+const tracesLimit = 200
+
+// Pod names of Deployments (<replicaset>-<hash>) and of DaemonSets (<daemonset>-<hash>)
+var (
+	deploymentPod = regexp.MustCompile(`^(.+)-[a-z0-9]{5,10}-[a-z0-9]{5}$`)
+	daemonSetPod  = regexp.MustCompile(`^(.+)-[a-z0-9]{5}$`)
+)
+
+// endpoint is the source or destination of a request
+type endpoint struct {
+	namespace, workload, app, version, service string
+}
+
+// BuildNamespacesTrafficMap builds the traffic map of the requested namespaces from the traces of their apps
+func BuildNamespacesTrafficMap(o graph.TelemetryOptions, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	log.Tracef("Build trace-derived [%s] graph for [%d] namespaces [%v]", o.GraphType, len(o.Namespaces), o.Namespaces)
+
+	end := time.Unix(o.QueryTime, 0)
+	query := models.TracingQuery{
+		StartMicros: fmt.Sprintf("%d", end.Add(-o.Duration).UnixNano()/1000),
+		EndMicros:   fmt.Sprintf("%d", end.UnixNano()/1000),
+		Limit:       tracesLimit,
+	}
+
+	trafficMap := graph.NewTrafficMap()
+	for _, namespace := range o.Namespaces {
+		traces, err := globalInfo.Business.Jaeger.GetNamespaceTraces(namespace.Name, query)
+		graph.CheckError(err)
+		namespaceTrafficMap := graph.NewTrafficMap()
+		for _, trace := range traces {
+			populateTrafficMap(namespaceTrafficMap, namespace.Name, trace, o)
+		}
+		telemetry.MergeTrafficMaps(trafficMap, namespace.Name, namespaceTrafficMap)
+	}
+
+	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
+	telemetry.MarkTrafficGenerators(trafficMap)
+
+	if graph.GraphTypeService == o.GraphType {
+		trafficMap = telemetry.ReduceToServiceGraph(trafficMap)
+	}
+
+	return trafficMap
+}
+
+// populateTrafficMap adds the requests of a trace from or to the namespace
+func populateTrafficMap(trafficMap graph.TrafficMap, namespace string, trace jaegerModels.Trace, o graph.TelemetryOptions) {
+	// Each sampled request counts for one request over the duration
+	val := 1 / o.Duration.Seconds()
+
+	spans := make(map[jaegerModels.SpanID]*jaegerModels.Span, len(trace.Spans))
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		if process, ok := trace.Processes[span.ProcessID]; ok {
+			span.Process = &process
+		}
+		spans[span.SpanID] = span
+	}
+	// Client spans with a server span, the others are requests leaving the mesh
+	answered := make(map[jaegerModels.SpanID]bool)
+
+	for _, span := range spans {
+		if tagValue(span, "span.kind") != "server" {
+			continue
+		}
+		dest, ok := spanEndpoint(span)
+		if !ok {
+			continue
+		}
+		source := endpoint{namespace: graph.Unknown, workload: graph.Unknown, app: graph.Unknown, version: graph.Unknown}
+		var client *jaegerModels.Span
+		if parent, ok := spans[parentSpanID(span)]; ok && tagValue(parent, "span.kind") == "client" {
+			client = parent
+			answered[client.SpanID] = true
+			if source, ok = spanEndpoint(client); !ok {
+				continue
+			}
+			if ns, svc, ok := upstreamService(client); ok && ns == dest.namespace {
+				dest.service = svc
+			}
+		}
+		if source.namespace != namespace && dest.namespace != namespace {
+			continue
+		}
+		if dest.service == "" {
+			dest.service = dest.app
+		}
+		protocol, code, flags := requestOutcome(span, client)
+		addRequest(trafficMap, val, protocol, code, flags, source, dest, o)
+	}
+
+	for _, span := range spans {
+		if tagValue(span, "span.kind") != "client" || answered[span.SpanID] {
+			continue
+		}
+		source, ok := spanEndpoint(span)
+		if !ok {
+			continue
+		}
+		ns, svc, ok := upstreamService(span)
+		if !ok || (source.namespace != namespace && ns != namespace) {
+			continue
+		}
+		dest := endpoint{namespace: ns, service: svc}
+		protocol, code, flags := requestOutcome(span, nil)
+		addRequest(trafficMap, val, protocol, code, flags, source, dest, o)
+	}
+}
+
+func addRequest(trafficMap graph.TrafficMap, val float64, protocol, code, flags string, source, dest endpoint, o graph.TelemetryOptions) {
+	// don't inject a service node if the dest node is already a service node
+	if o.InjectServiceNodes && graph.IsOK(dest.service) && dest.workload != "" {
+		if _, destNodeType := graph.Id(dest.namespace, dest.service, dest.namespace, dest.workload, valueOrUnknown(dest.app), valueOrUnknown(dest.version), o.GraphType); destNodeType != graph.NodeTypeService {
+			addTraffic(trafficMap, val, protocol, code, flags, source, endpoint{namespace: dest.namespace, service: dest.service}, o)
+			addTraffic(trafficMap, val, protocol, code, flags, endpoint{namespace: dest.namespace, service: dest.service}, dest, o)
+			return
+		}
+	}
+	addTraffic(trafficMap, val, protocol, code, flags, source, dest, o)
+}
+
+func addTraffic(trafficMap graph.TrafficMap, val float64, protocol, code, flags string, source, dest endpoint, o graph.TelemetryOptions) {
+	sourceNode := addNode(trafficMap, source, o)
+	destNode := addNode(trafficMap, dest, o)
+
+	var edge *graph.Edge
+	for _, e := range sourceNode.Edges {
+		if destNode.ID == e.Dest.ID && e.Metadata[graph.ProtocolKey] == protocol {
+			edge = e
+			break
+		}
+	}
+	if nil == edge {
+		edge = sourceNode.AddEdge(destNode)
+		edge.Metadata[graph.ProtocolKey] = protocol
+	}
+	host := dest.service
+	if dest.service != "" {
+		host = dest.service + "." + dest.namespace + ".svc.cluster.local"
+	}
+	graph.AddToMetadata(protocol, val, code, flags, host, sourceNode.Metadata, destNode.Metadata, edge.Metadata)
+}
+
+func addNode(trafficMap graph.TrafficMap, e endpoint, o graph.TelemetryOptions) *graph.Node {
+	workload := valueOrUnknown(e.workload)
+	app := valueOrUnknown(e.app)
+	version := valueOrUnknown(e.version)
+	id, nodeType := graph.Id(e.namespace, e.service, e.namespace, workload, app, version, o.GraphType)
+	node, found := trafficMap[id]
+	if !found {
+		newNode := graph.NewNodeExplicit(id, e.namespace, workload, app, version, e.service, nodeType, o.GraphType)
+		node = &newNode
+		trafficMap[id] = node
+	}
+	return node
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return graph.Unknown
+	}
+	return value
+}
+
+// spanEndpoint returns the workload of the proxy reporting a span
+func spanEndpoint(span *jaegerModels.Span) (endpoint, bool) {
+	e := endpoint{
+		namespace: tagValue(span, "istio.namespace"),
+		app:       tagValue(span, "istio.canonical_service"),
+		version:   tagValue(span, "istio.canonical_revision"),
+	}
+	// node_id is "<proxy type>~<ip>~<pod>.<namespace>~<namespace>.svc.<domain>"
+	if parts := strings.Split(tagValue(span, "node_id"), "~"); len(parts) == 4 {
+		if i := strings.LastIndex(parts[2], "."); i > 0 {
+			if e.namespace == "" {
+				e.namespace = parts[2][i+1:]
+			}
+			e.workload = podWorkload(parts[2][:i])
+		}
+	}
+	// The service name of the process is "<app>.<namespace>" with the namespace selector
+	if (e.namespace == "" || e.app == "") && span.Process != nil {
+		name := span.Process.ServiceName
+		if i := strings.LastIndex(name, "."); i > 0 {
+			if e.namespace == "" {
+				e.namespace = name[i+1:]
+			}
+			name = name[:i]
+		}
+		if e.app == "" {
+			e.app = name
+		}
+	}
+	if e.version == "latest" {
+		e.version = ""
+	}
+	// Spans not reported by a proxy lack the pod, the app stands for the workload then
+	if e.workload == "" {
+		e.workload = e.app
+	}
+	return e, e.namespace != "" && (e.app != "" || e.workload != "")
+}
+
+// podWorkload infers the workload of a pod from its name
+func podWorkload(pod string) string {
+	if m := deploymentPod.FindStringSubmatch(pod); m != nil {
+		return m[1]
+	}
+	if m := daemonSetPod.FindStringSubmatch(pod); m != nil {
+		return m[1]
+	}
+	return pod
+}
+
+// upstreamService returns the destination service of a client span, from its cluster
+// "outbound|<port>|<subset>|<service>.<namespace>.svc.<domain>"
+func upstreamService(span *jaegerModels.Span) (namespace, service string, ok bool) {
+	parts := strings.Split(tagValue(span, "upstream_cluster"), "|")
+	if len(parts) != 4 || parts[0] != "outbound" {
+		return "", "", false
+	}
+	host := strings.Split(parts[3], ".")
+	if len(host) < 3 || host[2] != "svc" {
+		return "", "", false
+	}
+	return host[1], host[0], true
+}
+
+// requestOutcome returns the protocol, response code and response flags of a request, preferring the client span
+func requestOutcome(server, client *jaegerModels.Span) (protocol, code, flags string) {
+	spans := []*jaegerModels.Span{server}
+	if client != nil {
+		spans = []*jaegerModels.Span{client, server}
+	}
+	protocol, code, flags = "http", "-", "-"
+	for _, span := range spans {
+		if grpcCode := tagValue(span, "grpc.status_code"); grpcCode != "" {
+			protocol, code = "grpc", grpcCode
+		} else if httpCode := tagValue(span, "http.status_code"); httpCode != "" && httpCode != "0" {
+			code = httpCode
+		}
+		if f := tagValue(span, "response_flags"); f != "" {
+			flags = f
+		}
+		if code != "-" {
+			break
+		}
+	}
+	return protocol, code, flags
+}
+
+func parentSpanID(span *jaegerModels.Span) jaegerModels.SpanID {
+	for _, ref := range span.References {
+		if ref.RefType == jaegerModels.ChildOf {
+			return ref.SpanID
+		}
+	}
+	return span.ParentSpanID
+}
+
+func tagValue(span *jaegerModels.Span, key string) string {
+	for _, tag := range span.Tags {
+		if tag.Key == key {
+			return fmt.Sprintf("%v", tag.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func tag(key string, value interface{}) jaegerModels.KeyValue {
+	return jaegerModels.KeyValue{Key: key, Value: value}
+}
+
+func proxySpan(id, parent, kind, app, version, pod, namespace string, tags ...jaegerModels.KeyValue) jaegerModels.Span {
+	span := jaegerModels.Span{
+		SpanID: jaegerModels.SpanID(id),
+		Tags: append([]jaegerModels.KeyValue{
+			tag("span.kind", kind),
+			tag("istio.canonical_service", app),
+			tag("istio.canonical_revision", version),
+			tag("istio.namespace", namespace),
+			tag("node_id", "sidecar~10.1.1.1~"+pod+"."+namespace+"~"+namespace+".svc.cluster.local"),
+		}, tags...),
+	}
+	if parent != "" {
+		span.References = []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, SpanID: jaegerModels.SpanID(parent)}}
+	}
+	return span
+}
+
+// productpage -> reviews -> ratings (503), reviews -> details.other (no server span)
+var bookinfoTrace = jaegerModels.Trace{
+	Spans: []jaegerModels.Span{
+		proxySpan("1", "", "server", "productpage", "v1", "productpage-v1-5d9b4c9849-qbz2l", "bookinfo",
+			tag("http.status_code", int64(200)), tag("response_flags", "-")),
+		proxySpan("2", "1", "client", "productpage", "v1", "productpage-v1-5d9b4c9849-qbz2l", "bookinfo",
+			tag("upstream_cluster", "outbound|9080||reviews.bookinfo.svc.cluster.local"), tag("http.status_code", int64(200))),
+		proxySpan("3", "2", "server", "reviews", "v2", "reviews-v2-7bf8c9648f-2ngdv", "bookinfo",
+			tag("http.status_code", int64(200))),
+		proxySpan("4", "3", "client", "reviews", "v2", "reviews-v2-7bf8c9648f-2ngdv", "bookinfo",
+			tag("upstream_cluster", "outbound|9080||ratings.bookinfo.svc.cluster.local"), tag("http.status_code", int64(503)), tag("response_flags", "UF")),
+		proxySpan("5", "4", "server", "ratings", "latest", "ratings-v1-6c9dbf6b45-9l7kd", "bookinfo",
+			tag("http.status_code", int64(503))),
+		proxySpan("6", "3", "client", "reviews", "v2", "reviews-v2-7bf8c9648f-2ngdv", "bookinfo",
+			tag("upstream_cluster", "outbound|9080||details.other.svc.cluster.local"), tag("grpc.status_code", "14")),
+		// Not reported by a proxy
+		{SpanID: "7", Tags: []jaegerModels.KeyValue{tag("span.kind", "internal")}},
+	},
+}
+
+func TestPopulateTrafficMap(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{CommonOptions: graph.CommonOptions{Duration: 10 * time.Second, GraphType: graph.GraphTypeVersionedApp}}
+	trafficMap := graph.NewTrafficMap()
+	populateTrafficMap(trafficMap, "bookinfo", bookinfoTrace, o)
+
+	assert.Len(trafficMap, 5)
+	unknown, ok := trafficMap["unknown_source"]
+	assert.True(ok)
+	productpage, ok := trafficMap["vapp_bookinfo_productpage-v1"]
+	assert.True(ok)
+	reviews, ok := trafficMap["vapp_bookinfo_reviews-v2"]
+	assert.True(ok)
+	ratings, ok := trafficMap["vapp_bookinfo_ratings-v1"]
+	assert.True(ok)
+	assert.Equal(graph.Unknown, ratings.Version)
+	details, ok := trafficMap["svc_other_details"]
+	assert.True(ok)
+	assert.Equal(graph.NodeTypeService, details.NodeType)
+
+	assert.Len(unknown.Edges, 1)
+	assert.Equal(productpage.ID, unknown.Edges[0].Dest.ID)
+	assert.Len(productpage.Edges, 1)
+	assert.Equal(reviews.ID, productpage.Edges[0].Dest.ID)
+	assert.Equal(0.1, productpage.Edges[0].Metadata[graph.MetadataKey("http")])
+	assert.Len(reviews.Edges, 2)
+	for _, edge := range reviews.Edges {
+		switch edge.Dest.ID {
+		case ratings.ID:
+			assert.Equal("http", edge.Metadata[graph.ProtocolKey])
+			assert.Equal(0.1, edge.Metadata[graph.MetadataKey("http5xx")])
+		case details.ID:
+			assert.Equal("grpc", edge.Metadata[graph.ProtocolKey])
+			assert.Equal(0.1, edge.Metadata[graph.MetadataKey("grpcErr")])
+		default:
+			assert.Fail("unexpected edge", edge.Dest.ID)
+		}
+	}
+
+	// Requests of other namespaces are skipped
+	trafficMap = graph.NewTrafficMap()
+	populateTrafficMap(trafficMap, "other", bookinfoTrace, o)
+	assert.Len(trafficMap, 2)
+}
+
+func TestPopulateTrafficMapWorkloadGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{CommonOptions: graph.CommonOptions{Duration: 10 * time.Second, GraphType: graph.GraphTypeWorkload}, InjectServiceNodes: true}
+	trafficMap := graph.NewTrafficMap()
+	populateTrafficMap(trafficMap, "bookinfo", bookinfoTrace, o)
+
+	for _, id := range []string{"unknown_source", "wl_bookinfo_productpage-v1", "wl_bookinfo_reviews-v2", "wl_bookinfo_ratings-v1",
+		"svc_bookinfo_productpage", "svc_bookinfo_reviews", "svc_bookinfo_ratings", "svc_other_details"} {
+		_, ok := trafficMap[id]
+		assert.True(ok, id)
+	}
+	assert.Len(trafficMap, 8)
+}
+
+func TestPodWorkload(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("reviews-v2", podWorkload("reviews-v2-7bf8c9648f-2ngdv"))
+	assert.Equal("fluentd", podWorkload("fluentd-x8k2p"))
+	assert.Equal("mysql-0", podWorkload("mysql-0"))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/kiali/kiali/models"
+)
+
+// Alerts is the API handler to list the alerts firing in Alertmanager, optionally about a namespace, service,
+// workload or app
+func Alerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	criteria := models.AlertCriteria{
+		App:       query.Get("app"),
+		Namespace: query.Get("namespace"),
+		Service:   query.Get("service"),
+		Workload:  query.Get("workload"),
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	alerts, err := business.Alert.GetFiringAlerts(criteria)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, alerts)
+}
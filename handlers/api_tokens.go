@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// getApiTokenOwner returns the business layer of the request and the user that owns the API tokens managed in it,
+// as identified by the cluster. API tokens can only be managed from an interactive session, not with another API token.
+func getApiTokenOwner(w http.ResponseWriter, r *http.Request) (*business.Layer, *models.UserIdentity, bool) {
+	if !config.Get().Auth.ApiTokens.Enabled {
+		RespondWithError(w, http.StatusNotFound, "API tokens are not enabled")
+		return nil, nil, false
+	}
+	if _, isApiToken := getApiTokenScope(r); isApiToken {
+		RespondWithError(w, http.StatusForbidden, "API tokens cannot be managed using an API token")
+		return nil, nil, false
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return nil, nil, false
+	}
+	owner, err := layer.ApiToken.GetApiTokenOwner()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return nil, nil, false
+	}
+	return layer, owner, true
+}
+
+// ApiTokenList is the API handler to list the API tokens of the user
+func ApiTokenList(w http.ResponseWriter, r *http.Request) {
+	business, owner, ok := getApiTokenOwner(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := business.ApiToken.ListApiTokens(owner.Username)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, tokens)
+}
+
+// ApiTokenCreate is the API handler to create an API token for the user
+func ApiTokenCreate(w http.ResponseWriter, r *http.Request) {
+	business, owner, ok := getApiTokenOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.ApiTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Create request with bad json: "+err.Error())
+		return
+	}
+
+	token, err := business.ApiToken.CreateApiToken(*owner, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "CREATE on ApiToken: "+token.ID+" Name: "+token.Name)
+	RespondWithJSON(w, http.StatusOK, token)
+}
+
+// ApiTokenRevoke is the API handler to revoke an API token of the user
+func ApiTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	business, owner, ok := getApiTokenOwner(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+
+	if err := business.ApiToken.RevokeApiToken(owner.Username, id); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "DELETE on ApiToken: "+id)
+	RespondWithCode(w, http.StatusNoContent)
+}
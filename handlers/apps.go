@@ -25,6 +25,11 @@ func AppList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isCSVRequested(r) {
+		header, rows := appListCSV(appList)
+		RespondWithCSV(w, "apps-"+namespace, header, rows)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, appList)
 }
 
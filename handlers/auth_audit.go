@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// Authentication and authorization events recorded in the audit log
+const (
+	authEventAccess  = "access"
+	authEventLogin   = "login"
+	authEventLogout  = "logout"
+	authEventRefresh = "session_refresh"
+
+	authOutcomeFailure = "failure"
+	authOutcomeSuccess = "success"
+)
+
+// authAuditLogGroup is the log group of the audit events, to tell them apart from the rest of the Kiali logs
+const authAuditLogGroup = "auth_audit"
+
+// authAuditEvent is an authentication or authorization event of the audit log
+type authAuditEvent struct {
+	Time         time.Time `json:"time"`
+	Event        string    `json:"event"`
+	Outcome      string    `json:"outcome"`
+	User         string    `json:"user,omitempty"`
+	Strategy     string    `json:"strategy"`
+	SourceIP     string    `json:"sourceIp"`
+	ForwardedFor string    `json:"forwardedFor,omitempty"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// Events are delivered to the webhook asynchronously so requests are not slowed down.
+// When the webhook can't keep up, events are dropped from the webhook delivery; they are logged anyway.
+var authAuditWebhook struct {
+	once   sync.Once
+	events chan authAuditEvent
+	// Closed once the queued events are delivered, after the queue is closed
+	delivered chan struct{}
+	lock      sync.RWMutex
+	closed    bool
+}
+
+const authAuditWebhookQueueSize = 100
+
+// auditAuth records an authentication or authorization event in the audit log
+func auditAuth(r *http.Request, event, user, outcome, reason string) {
+	conf := config.Get()
+	if !conf.Auth.Audit.Enabled {
+		return
+	}
+
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+
+	auditEvent := authAuditEvent{
+		Time:         time.Now(),
+		Event:        event,
+		Outcome:      outcome,
+		User:         user,
+		Strategy:     conf.Auth.Strategy,
+		SourceIP:     sourceIP,
+		ForwardedFor: r.Header.Get("X-Forwarded-For"),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		Reason:       reason,
+	}
+
+	log.Audit(authAuditLogGroup, map[string]interface{}{
+		"event":        auditEvent.Event,
+		"outcome":      auditEvent.Outcome,
+		"user":         auditEvent.User,
+		"strategy":     auditEvent.Strategy,
+		"sourceIp":     auditEvent.SourceIP,
+		"forwardedFor": auditEvent.ForwardedFor,
+		"method":       auditEvent.Method,
+		"path":         auditEvent.Path,
+		"reason":       auditEvent.Reason,
+	}, "AUTH "+event+" "+outcome)
+
+	if conf.Auth.Audit.WebhookUrl != "" {
+		authAuditWebhook.once.Do(func() {
+			authAuditWebhook.events = make(chan authAuditEvent, authAuditWebhookQueueSize)
+			authAuditWebhook.delivered = make(chan struct{})
+			go sendAuthAuditEvents(authAuditWebhook.events, authAuditWebhook.delivered)
+		})
+		authAuditWebhook.lock.RLock()
+		defer authAuditWebhook.lock.RUnlock()
+		if authAuditWebhook.closed {
+			log.Warningf("Audit webhook queue is closed, event [%s] for user [%s] is not delivered", event, user)
+			return
+		}
+		select {
+		case authAuditWebhook.events <- auditEvent:
+		default:
+			log.Warningf("Audit webhook queue is full, event [%s] for user [%s] is not delivered", event, user)
+		}
+	}
+}
+
+// sendAuthAuditEvents delivers the queued audit events to the configured webhook
+func sendAuthAuditEvents(events <-chan authAuditEvent, delivered chan<- struct{}) {
+	defer close(delivered)
+	client := http.Client{Timeout: 5 * time.Second}
+	for event := range events {
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Errorf("Cannot serialize audit event: %v", err)
+			continue
+		}
+		resp, err := client.Post(config.Get().Auth.Audit.WebhookUrl, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Warningf("Cannot deliver audit event to the webhook: %v", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Warningf("Audit webhook rejected the event with status [%d]", resp.StatusCode)
+		}
+	}
+}
+
+// FlushAuthAuditEvents delivers the events queued for the audit webhook, until the context is done. The events
+// audited afterwards are not delivered: the server must not serve requests anymore.
+func FlushAuthAuditEvents(ctx context.Context) {
+	queued := true
+	authAuditWebhook.once.Do(func() {
+		queued = false
+	})
+	authAuditWebhook.lock.Lock()
+	if queued && !authAuditWebhook.closed {
+		close(authAuditWebhook.events)
+	}
+	authAuditWebhook.closed = true
+	authAuditWebhook.lock.Unlock()
+	if !queued {
+		return
+	}
+	select {
+	case <-authAuditWebhook.delivered:
+	case <-ctx.Done():
+		log.Warningf("Audit webhook events are not all delivered: %v", ctx.Err())
+	}
+}
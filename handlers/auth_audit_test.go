@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestAuthAuditWebhook(t *testing.T) {
+	received := make(chan authAuditEvent, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event authAuditEvent
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer webhook.Close()
+
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyToken
+	cfg.Auth.Audit.WebhookUrl = webhook.URL
+	config.Set(cfg)
+
+	request := httptest.NewRequest("POST", "http://kiali/api/authenticate", nil)
+	request.RemoteAddr = "10.0.0.5:51234"
+	request.Header.Set("X-Forwarded-For", "203.0.113.7")
+	auditAuth(request, authEventLogin, "alice", authOutcomeSuccess, "")
+
+	select {
+	case event := <-received:
+		assert.Equal(t, authEventLogin, event.Event)
+		assert.Equal(t, authOutcomeSuccess, event.Outcome)
+		assert.Equal(t, "alice", event.User)
+		assert.Equal(t, config.AuthStrategyToken, event.Strategy)
+		assert.Equal(t, "10.0.0.5", event.SourceIP)
+		assert.Equal(t, "203.0.113.7", event.ForwardedFor)
+		assert.Equal(t, "/api/authenticate", event.Path)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "audit event not delivered to the webhook")
+	}
+}
+
+func TestAuthAuditUnauthorizedAccess(t *testing.T) {
+	received := make(chan authAuditEvent, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event authAuditEvent
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer webhook.Close()
+
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyHeader
+	cfg.Auth.Header.TrustedProxies = []string{"10.0.0.0/8"}
+	cfg.Auth.Audit.WebhookUrl = webhook.URL
+	config.Set(cfg)
+
+	request := httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+	request.RemoteAddr = "192.168.0.1:51234"
+	responseRecorder := httptest.NewRecorder()
+	AuthenticationHandler{saToken: "kiali-sa"}.Handle(dummyHandler{}).ServeHTTP(responseRecorder, request)
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+
+	select {
+	case event := <-received:
+		assert.Equal(t, authEventAccess, event.Event)
+		assert.Equal(t, authOutcomeFailure, event.Outcome)
+		assert.Equal(t, "/api/namespaces", event.Path)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "audit event not delivered to the webhook")
+	}
+}
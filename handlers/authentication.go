@@ -16,11 +16,13 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/util"
 	"github.com/kiali/kiali/util/httputil"
 )
@@ -30,12 +32,13 @@ type AuthenticationHandler struct {
 }
 
 type AuthInfo struct {
-	Strategy              string      `json:"strategy"`
-	AuthorizationEndpoint string      `json:"authorizationEndpoint,omitempty"`
-	LogoutEndpoint        string      `json:"logoutEndpoint,omitempty"`
-	LogoutRedirect        string      `json:"logoutRedirect,omitempty"`
-	SessionInfo           sessionInfo `json:"sessionInfo"`
-	SecretMissing         bool        `json:"secretMissing,omitempty"`
+	Strategy              string            `json:"strategy"`
+	AuthorizationEndpoint string            `json:"authorizationEndpoint,omitempty"`
+	LogoutEndpoint        string            `json:"logoutEndpoint,omitempty"`
+	LogoutRedirect        string            `json:"logoutRedirect,omitempty"`
+	SessionInfo           sessionInfo       `json:"sessionInfo"`
+	SecretMissing         bool              `json:"secretMissing,omitempty"`
+	Clusters              []clusterAuthInfo `json:"clusters,omitempty"`
 }
 
 type sessionInfo struct {
@@ -43,6 +46,14 @@ type sessionInfo struct {
 	ExpiresOn string `json:"expiresOn,omitempty"`
 }
 
+// clusterAuthInfo tells how the user authenticates against a remote cluster
+// and if the current session holds credentials for it
+type clusterAuthInfo struct {
+	Name           string `json:"name"`
+	Strategy       string `json:"strategy"`
+	HasCredentials bool   `json:"hasCredentials"`
+}
+
 // TokenResponse tokenResponse
 //
 // This is used for returning the token
@@ -75,8 +86,8 @@ func getTokenStringFromRequest(r *http.Request) string {
 	// Token can be provided by a browser in a Cookie or
 	// in an authorization HTTP header.
 	// The token in the cookie has priority.
-	if authCookie, err := r.Cookie(config.TokenCookieName); err != http.ErrNoCookie {
-		tokenString = authCookie.Value
+	if cookieValue, err := httputil.GetChunkedCookie(r, config.TokenCookieName); err != http.ErrNoCookie {
+		tokenString = cookieValue
 	} else if headerValue := r.Header.Get("Authorization"); strings.Contains(headerValue, "Bearer") {
 		tokenString = strings.TrimPrefix(headerValue, "Bearer ")
 	}
@@ -106,6 +117,7 @@ func performOpenshiftAuthentication(w http.ResponseWriter, r *http.Request) bool
 	}
 
 	expiresOn := time.Now().Add(time.Second * time.Duration(expiresInNumber))
+	clusterTokens := business.GetClusterCredentials(r.Form, "")
 
 	business, err := getBusiness(r)
 	if err != nil {
@@ -119,14 +131,7 @@ func performOpenshiftAuthentication(w http.ResponseWriter, r *http.Request) bool
 		return false
 	}
 
-	tokenClaims := config.IanaClaims{
-		SessionId: token,
-		StandardClaims: jwt.StandardClaims{
-			Subject:   user.Metadata.Name,
-			ExpiresAt: expiresOn.Unix(),
-			Issuer:    config.AuthStrategyOpenshiftIssuer,
-		},
-	}
+	tokenClaims := config.NewSessionClaims(token, clusterTokens, user.Metadata.Name, expiresOn, config.AuthStrategyOpenshiftIssuer)
 	tokenString, err := config.GetSignedTokenString(tokenClaims)
 	if err != nil {
 		RespondWithJSONIndent(w, http.StatusInternalServerError, err)
@@ -141,8 +146,9 @@ func performOpenshiftAuthentication(w http.ResponseWriter, r *http.Request) bool
 		Path:     config.Get().Server.WebRoot,
 		SameSite: http.SameSiteStrictMode,
 	}
-	http.SetCookie(w, &tokenCookie)
+	httputil.SetChunkedCookie(w, r, tokenCookie)
 
+	auditAuth(r, authEventLogin, user.Metadata.Name, authOutcomeSuccess, "")
 	RespondWithJSONIndent(w, http.StatusOK, TokenResponse{Token: tokenString, ExpiresOn: expiresOn.Format(time.RFC1123Z), Username: user.Metadata.Name})
 	return true
 }
@@ -197,6 +203,7 @@ func performOpenIdAuthentication(w http.ResponseWriter, r *http.Request) bool {
 	// Now that we know that the OpenId token is valid, build our session cookie
 	// and send it to the browser.
 	tokenClaims := business.BuildOpenIdJwtClaims(openIdParams)
+	tokenClaims.ClusterTokens = business.GetClusterCredentials(r.Form, openIdParams.IdToken)
 	tokenString, err := config.GetSignedTokenString(tokenClaims)
 	if err != nil {
 		RespondWithJSONIndent(w, http.StatusInternalServerError, err)
@@ -211,8 +218,9 @@ func performOpenIdAuthentication(w http.ResponseWriter, r *http.Request) bool {
 		Path:     conf.Server.WebRoot,
 		SameSite: http.SameSiteStrictMode,
 	}
-	http.SetCookie(w, &tokenCookie)
+	httputil.SetChunkedCookie(w, r, tokenCookie)
 
+	auditAuth(r, authEventLogin, openIdParams.Subject, authOutcomeSuccess, "")
 	RespondWithJSONIndent(w, http.StatusOK, TokenResponse{Token: tokenString, ExpiresOn: openIdParams.ExpiresOn.Format(time.RFC1123Z), Username: openIdParams.Subject})
 	return true
 }
@@ -232,6 +240,8 @@ func performTokenAuthentication(w http.ResponseWriter, r *http.Request) bool {
 		return false
 	}
 
+	clusterTokens := business.GetClusterCredentials(r.Form, "")
+
 	business, err := business.Get(token)
 	if err != nil {
 		RespondWithDetailedError(w, http.StatusInternalServerError, "Error instantiating the business layer", err.Error())
@@ -264,14 +274,7 @@ func performTokenAuthentication(w http.ResponseWriter, r *http.Request) bool {
 
 	// Build the Kiali token
 	timeExpire := util.Clock.Now().Add(time.Second * time.Duration(config.Get().LoginToken.ExpirationSeconds))
-	tokenClaims := config.IanaClaims{
-		SessionId: token,
-		StandardClaims: jwt.StandardClaims{
-			Subject:   tokenSubject,
-			ExpiresAt: timeExpire.Unix(),
-			Issuer:    config.AuthStrategyTokenIssuer,
-		},
-	}
+	tokenClaims := config.NewSessionClaims(token, clusterTokens, tokenSubject, timeExpire, config.AuthStrategyTokenIssuer)
 	tokenString, err := config.GetSignedTokenString(tokenClaims)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
@@ -286,8 +289,9 @@ func performTokenAuthentication(w http.ResponseWriter, r *http.Request) bool {
 		Path:     config.Get().Server.WebRoot,
 		SameSite: http.SameSiteStrictMode,
 	}
-	http.SetCookie(w, &tokenCookie)
+	httputil.SetChunkedCookie(w, r, tokenCookie)
 
+	auditAuth(r, authEventLogin, tokenSubject, authOutcomeSuccess, "")
 	RespondWithJSONIndent(w, http.StatusOK, TokenResponse{Token: tokenString, ExpiresOn: timeExpire.Format(time.RFC1123Z), Username: tokenSubject})
 	return true
 }
@@ -318,7 +322,7 @@ func performOpenshiftLogout(r *http.Request) (int, error) {
 	}
 }
 
-func checkOpenshiftSession(w http.ResponseWriter, r *http.Request) (int, string) {
+func checkOpenshiftSession(w http.ResponseWriter, r *http.Request) (int, string, map[string]string) {
 	tokenString := getTokenStringFromRequest(r)
 	if claims, err := config.GetTokenClaimsIfValid(tokenString); err != nil {
 		log.Warningf("Token is invalid: %s", err.Error())
@@ -326,29 +330,34 @@ func checkOpenshiftSession(w http.ResponseWriter, r *http.Request) (int, string)
 		// Session ID claim must be present
 		if len(claims.SessionId) == 0 {
 			log.Warning("Token is invalid: sid claim is required")
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
+		}
+		if isReplacedSession(claims) {
+			log.Warning("Token is invalid: the session was renewed or closed")
+			return http.StatusUnauthorized, "", nil
 		}
 
 		business, err := business.Get(claims.SessionId)
 		if err != nil {
 			log.Warning("Could not get the business layer : ", err)
-			return http.StatusInternalServerError, ""
+			return http.StatusInternalServerError, "", nil
 		}
 
 		_, err = business.OpenshiftOAuth.GetUserInfo(claims.SessionId)
 		if err == nil {
 			// Internal header used to propagate the subject of the request for audit purposes
 			r.Header.Add("Kiali-User", claims.Subject)
-			return http.StatusOK, claims.SessionId
+			renewSession(w, r, claims)
+			return http.StatusOK, claims.SessionId, claims.ClusterTokens
 		}
 
 		log.Warning("Token error: ", err)
 	}
 
-	return http.StatusUnauthorized, ""
+	return http.StatusUnauthorized, "", nil
 }
 
-func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string) {
+func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string, map[string]string) {
 	// First, check presence of a session for the "implicit flow"
 	var claims *config.IanaClaims
 
@@ -357,7 +366,11 @@ func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string) {
 		var err error = nil
 		if claims, err = config.GetTokenClaimsIfValid(tokenString); err != nil {
 			log.Warningf("Token is invalid: %s", err.Error())
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
+		}
+		if isReplacedSession(claims) {
+			log.Warning("Token is invalid: the session was closed")
+			return http.StatusUnauthorized, "", nil
 		}
 	} else {
 		// If not present, check presence of a session for the "authorization code" flow
@@ -365,35 +378,39 @@ func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string) {
 		claims, err = business.GetOpenIdAesSession(r)
 		if err != nil {
 			log.Warningf("There was an error when decoding the session: %s", err.Error())
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
 		}
 		if claims == nil {
 			log.Warningf("User seems to not be logged in")
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
+		}
+		if isReplacedSession(claims) {
+			log.Warning("Session is invalid: the session was renewed or closed")
+			return http.StatusUnauthorized, "", nil
 		}
 	}
 
 	// Session ID claim must be present
 	if len(claims.SessionId) == 0 {
 		log.Warning("Token is invalid: sid claim is required")
-		return http.StatusUnauthorized, ""
+		return http.StatusUnauthorized, "", nil
 	}
 
 	business, err := business.Get(claims.SessionId)
 	if err != nil {
 		log.Warning("Could not get the business layer : ", err)
-		return http.StatusInternalServerError, ""
+		return http.StatusInternalServerError, "", nil
 	}
 
 	// Parse the sid claim (id_token) to check that the sub claim matches to the configured "username" claim of the id_token
 	parsedIdToken, _, err := new(jwt.Parser).ParseUnverified(claims.SessionId, jwt.MapClaims{})
 	if err != nil {
 		log.Warning("Cannot parse sid claim of the Kiali token : ", err)
-		return http.StatusInternalServerError, ""
+		return http.StatusInternalServerError, "", nil
 	}
 	if userClaim, ok := parsedIdToken.Claims.(jwt.MapClaims)[config.Get().Auth.OpenId.UsernameClaim]; ok && claims.Subject != userClaim {
 		log.Warning("Kiali token rejected because of subject claim mismatch")
-		return http.StatusUnauthorized, ""
+		return http.StatusUnauthorized, "", nil
 	}
 
 	if !config.Get().Auth.OpenId.DisableRBAC {
@@ -401,16 +418,17 @@ func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string) {
 		_, err = business.Namespace.GetNamespaces()
 		if err != nil {
 			log.Warning("Token error: ", err)
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
 		}
 	}
 
 	// Internal header used to propagate the subject of the request for audit purposes
 	r.Header.Add("Kiali-User", claims.Subject)
-	return http.StatusOK, claims.SessionId
+	renewOpenIdSession(w, r, claims)
+	return http.StatusOK, claims.SessionId, claims.ClusterTokens
 }
 
-func checkTokenSession(w http.ResponseWriter, r *http.Request) (int, string) {
+func checkTokenSession(w http.ResponseWriter, r *http.Request) (int, string, map[string]string) {
 	tokenString := getTokenStringFromRequest(r)
 	if claims, err := config.GetTokenClaimsIfValid(tokenString); err != nil {
 		log.Warningf("Token is invalid: %s", err.Error())
@@ -418,26 +436,116 @@ func checkTokenSession(w http.ResponseWriter, r *http.Request) (int, string) {
 		// Session ID claim must be present
 		if len(claims.SessionId) == 0 {
 			log.Warning("Token is invalid: sid claim is required")
-			return http.StatusUnauthorized, ""
+			return http.StatusUnauthorized, "", nil
+		}
+		if isReplacedSession(claims) {
+			log.Warning("Token is invalid: the session was renewed or closed")
+			return http.StatusUnauthorized, "", nil
 		}
 
 		business, err := business.Get(claims.SessionId)
 		if err != nil {
 			log.Warning("Could not get the business layer : ", err)
-			return http.StatusInternalServerError, ""
+			return http.StatusInternalServerError, "", nil
 		}
 
 		_, err = business.Namespace.GetNamespaces()
 		if err == nil {
 			// Internal header used to propagate the subject of the request for audit purposes
 			r.Header.Add("Kiali-User", claims.Subject)
-			return http.StatusOK, claims.SessionId
+			renewSession(w, r, claims)
+			return http.StatusOK, claims.SessionId, claims.ClusterTokens
 		}
 
 		log.Warning("Token error: ", err)
 	}
 
-	return http.StatusUnauthorized, ""
+	return http.StatusUnauthorized, "", nil
+}
+
+// checkHeaderSession reads the identity of the user from the headers set by a trusted proxy.
+// The headers are only trusted when the request comes directly from one of the configured proxies.
+func checkHeaderSession(r *http.Request) (int, *models.UserIdentity) {
+	headerConfig := config.Get().Auth.Header
+	if !headerConfig.IsTrustedProxy(r.RemoteAddr) {
+		log.Warningf("Rejecting request from [%s]: it is not a trusted proxy", r.RemoteAddr)
+		return http.StatusUnauthorized, nil
+	}
+
+	username := strings.TrimSpace(r.Header.Get(headerConfig.UserHeader))
+	if username == "" {
+		log.Warningf("Rejecting request without the [%s] header", headerConfig.UserHeader)
+		return http.StatusUnauthorized, nil
+	}
+
+	identity := &models.UserIdentity{Username: username, Groups: []string{}}
+	if headerConfig.GroupsHeader != "" {
+		for _, rawGroups := range r.Header.Values(headerConfig.GroupsHeader) {
+			for _, group := range strings.Split(rawGroups, headerConfig.GroupsSeparator) {
+				if group = strings.TrimSpace(group); group != "" {
+					identity.Groups = append(identity.Groups, group)
+				}
+			}
+		}
+	}
+
+	// Internal header used to propagate the subject of the request for audit purposes
+	r.Header.Add("Kiali-User", username)
+	return http.StatusOK, identity
+}
+
+// apiTokenUnscopedRoutes are the routes not tied to a namespace that can be called with an API token.
+// Any other route must target namespaces included in the scope of the API token.
+var apiTokenUnscopedRoutes = map[string]bool{
+	"NamespaceList": true,
+}
+
+func checkApiTokenSession(r *http.Request) (int, *models.ApiToken) {
+	apiToken, err := business.ValidateApiToken(getTokenStringFromRequest(r))
+	if err != nil {
+		log.Warningf("API token rejected: %s", err.Error())
+		return http.StatusUnauthorized, nil
+	}
+
+	if apiToken.Scope.ViewOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		log.Warningf("API token [%s] is view only and cannot be used for a %s request", apiToken.ID, r.Method)
+		return http.StatusForbidden, nil
+	}
+
+	scoped := false
+	if namespace, ok := mux.Vars(r)["namespace"]; ok {
+		if !apiToken.Scope.AllowsNamespace(namespace) {
+			log.Warningf("API token [%s] has no access to namespace [%s]", apiToken.ID, namespace)
+			return http.StatusForbidden, nil
+		}
+		scoped = true
+	}
+	if namespaces := r.URL.Query().Get("namespaces"); namespaces != "" {
+		for _, namespace := range strings.Split(namespaces, ",") {
+			if !apiToken.Scope.AllowsNamespace(strings.TrimSpace(namespace)) {
+				log.Warningf("API token [%s] has no access to namespace [%s]", apiToken.ID, namespace)
+				return http.StatusForbidden, nil
+			}
+		}
+		scoped = true
+	}
+	if !scoped {
+		if route := mux.CurrentRoute(r); route == nil || !apiTokenUnscopedRoutes[route.GetName()] {
+			log.Warningf("API token [%s] cannot be used for a request not scoped to its namespaces", apiToken.ID)
+			return http.StatusForbidden, nil
+		}
+	}
+
+	// Internal header used to propagate the subject of the request for audit purposes
+	r.Header.Add("Kiali-User", fmt.Sprintf("%s (API token %s)", apiToken.Owner, apiToken.Name))
+	return http.StatusOK, apiToken
+}
+
+func isApiTokenRequest(r *http.Request) bool {
+	conf := config.Get()
+	return conf.Auth.ApiTokens.Enabled &&
+		conf.Auth.Strategy != config.AuthStrategyAnonymous &&
+		strings.HasPrefix(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), business.ApiTokenPrefix)
 }
 
 func NewAuthenticationHandler() (AuthenticationHandler, error) {
@@ -455,32 +563,67 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 		conf := config.Get()
 
 		var token string
-
-		switch conf.Auth.Strategy {
-		case config.AuthStrategyOpenshift:
-			statusCode, token = checkOpenshiftSession(w, r)
-		case config.AuthStrategyOpenId:
-			statusCode, token = checkOpenIdSession(w, r)
+		var clusterTokens map[string]string
+		var apiToken *models.ApiToken
+		var identity *models.UserIdentity
+
+		// The header is set by Kiali once the user is authenticated; never trust the one sent by the client
+		r.Header.Del("Kiali-User")
+
+		switch {
+		case isApiTokenRequest(r):
+			// API tokens are not bound to the user credentials, so requests use the Kiali ServiceAccount
+			// impersonating the owner of the API token, and are restricted to the scope of the API token.
+			// The cluster keeps applying the current permissions of the owner.
+			statusCode, apiToken = checkApiTokenSession(r)
+			token = aHandler.saToken
+			if apiToken != nil {
+				groups := apiToken.OwnerGroups
+				if groups == nil {
+					groups = []string{}
+				}
+				identity = &models.UserIdentity{Username: apiToken.Owner, Groups: groups}
+			}
+		case conf.Auth.Strategy == config.AuthStrategyOpenshift:
+			statusCode, token, clusterTokens = checkOpenshiftSession(w, r)
+		case conf.Auth.Strategy == config.AuthStrategyOpenId:
+			statusCode, token, clusterTokens = checkOpenIdSession(w, r)
 			if conf.Auth.OpenId.DisableRBAC {
 				// If RBAC is off, it's assumed that the kubernetes cluster will reject the OpenId token.
 				// Instead, we use the Kiali token an this has the side effect that all users will share the
 				// same privileges.
 				token = aHandler.saToken
 			}
-		case config.AuthStrategyToken:
-			statusCode, token = checkTokenSession(w, r)
-		case config.AuthStrategyAnonymous:
+		case conf.Auth.Strategy == config.AuthStrategyToken:
+			statusCode, token, clusterTokens = checkTokenSession(w, r)
+		case conf.Auth.Strategy == config.AuthStrategyHeader:
+			// The proxy in front of Kiali is in charge of the authentication, so
+			// the Kiali ServiceAccount is used for the access to the cluster
+			statusCode, identity = checkHeaderSession(r)
+			token = aHandler.saToken
+		case conf.Auth.Strategy == config.AuthStrategyAnonymous:
 			log.Tracef("Access to the server endpoint is not secured with credentials - letting request come in. Url: [%s]", r.URL.String())
 			token = aHandler.saToken
 		}
 
 		switch statusCode {
 		case http.StatusOK:
-			context := context.WithValue(r.Context(), "token", token)
-			next.ServeHTTP(w, r.WithContext(context))
+			ctx := context.WithValue(r.Context(), "token", token)
+			ctx = context.WithValue(ctx, "clusterTokens", clusterTokens)
+			if apiToken != nil {
+				ctx = context.WithValue(ctx, "apiTokenScope", apiToken.Scope)
+				ctx = context.WithValue(ctx, "impersonatedUser", *identity)
+			}
+			if identity == nil {
+				identity = &models.UserIdentity{Username: r.Header.Get("Kiali-User"), Groups: []string{}}
+			}
+			ctx = context.WithValue(ctx, "userIdentity", *identity)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		case http.StatusUnauthorized:
+			auditAuth(r, authEventAccess, "", authOutcomeFailure, http.StatusText(statusCode))
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		default:
+			auditAuth(r, authEventAccess, r.Header.Get("Kiali-User"), authOutcomeFailure, http.StatusText(statusCode))
 			http.Error(w, http.StatusText(statusCode), statusCode)
 			log.Errorf("Cannot send response to unauthorized user: %v", statusCode)
 		}
@@ -496,13 +639,21 @@ func (aHandler AuthenticationHandler) HandleUnauthenticated(next http.Handler) h
 
 func Authenticate(w http.ResponseWriter, r *http.Request) {
 	conf := config.Get()
+	authenticated := true
 	switch conf.Auth.Strategy {
 	case config.AuthStrategyOpenshift:
-		performOpenshiftAuthentication(w, r)
+		authenticated = performOpenshiftAuthentication(w, r)
 	case config.AuthStrategyOpenId:
-		performOpenIdAuthentication(w, r)
+		authenticated = performOpenIdAuthentication(w, r)
 	case config.AuthStrategyToken:
-		performTokenAuthentication(w, r)
+		authenticated = performTokenAuthentication(w, r)
+	case config.AuthStrategyHeader:
+		// There is no login: the identity comes with every request from the proxy
+		if statusCode, identity := checkHeaderSession(r); statusCode != http.StatusOK {
+			RespondWithError(w, statusCode, "Identity of the user not found in the request")
+		} else {
+			RespondWithJSONIndent(w, http.StatusOK, TokenResponse{Username: identity.Username})
+		}
 	case config.AuthStrategyAnonymous:
 		log.Warning("Authentication attempt with anonymous access enabled.")
 	default:
@@ -510,6 +661,10 @@ func Authenticate(w http.ResponseWriter, r *http.Request) {
 		log.Errorf(message)
 		RespondWithError(w, http.StatusInternalServerError, message)
 	}
+
+	if !authenticated {
+		auditAuth(r, authEventLogin, "", authOutcomeFailure, "login rejected")
+	}
 }
 
 func AuthenticationInfo(w http.ResponseWriter, r *http.Request) {
@@ -548,38 +703,56 @@ func AuthenticationInfo(w http.ResponseWriter, r *http.Request) {
 		claims, _ = business.GetOpenIdAesSession(r)
 	}
 
-	if claims != nil {
+	if conf.Auth.Strategy == config.AuthStrategyHeader {
+		if statusCode, identity := checkHeaderSession(r); statusCode == http.StatusOK {
+			response.SessionInfo = sessionInfo{Username: identity.Username}
+		}
+	} else if claims != nil {
 		response.SessionInfo = sessionInfo{
 			ExpiresOn: time.Unix(claims.ExpiresAt, 0).Format(time.RFC1123Z),
 			Username:  claims.Subject,
 		}
 	}
 
+	for _, cluster := range conf.Clustering.Clusters {
+		clusterInfo := clusterAuthInfo{
+			Name:     cluster.Name,
+			Strategy: cluster.Auth.Strategy,
+		}
+		if cluster.Auth.Strategy == config.ClusterAuthStrategyServiceAccount {
+			clusterInfo.HasCredentials = true
+		} else if claims != nil {
+			_, clusterInfo.HasCredentials = claims.ClusterTokens[cluster.Name]
+		}
+		response.Clusters = append(response.Clusters, clusterInfo)
+	}
+
 	RespondWithJSON(w, http.StatusOK, response)
 }
 
 func Logout(w http.ResponseWriter, r *http.Request) {
 	conf := config.Get()
 
+	// Tokens are stateless: remember the closed session to not accept its token anymore
+	if claims, err := config.GetTokenClaimsIfValid(getTokenStringFromRequest(r)); err == nil {
+		replaceSession(claims, 0)
+		auditAuth(r, authEventLogout, claims.Subject, authOutcomeSuccess, "")
+	} else if claims, _ := business.GetOpenIdAesSession(r); claims != nil {
+		replaceSession(claims, 0)
+		auditAuth(r, authEventLogout, claims.Subject, authOutcomeSuccess, "")
+	}
+
 	cookiesToDrop := []string{
 		config.TokenCookieName,
 		config.TokenCookieName + "-aes",
 	}
 	for _, cookieName := range cookiesToDrop {
-		_, err := r.Cookie(cookieName)
-
-		if err != http.ErrNoCookie {
-			tokenCookie := http.Cookie{
-				Name:     cookieName,
-				Value:    "",
-				Expires:  time.Unix(0, 0),
-				HttpOnly: true,
-				MaxAge:   -1,
-				Path:     conf.Server.WebRoot,
-				SameSite: http.SameSiteStrictMode,
-			}
-			http.SetCookie(w, &tokenCookie)
-		}
+		httputil.DropChunkedCookie(w, r, http.Cookie{
+			Name:     cookieName,
+			HttpOnly: true,
+			Path:     conf.Server.WebRoot,
+			SameSite: http.SameSiteStrictMode,
+		})
 	}
 
 	// We need to perform an extra step to invalidate the user token when using OpenShift OAuth
@@ -676,6 +849,42 @@ func OpenIdRedirect(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirectUri, http.StatusFound)
 }
 
+// setOpenIdAesSession ciphers the session data of the "authorization code" flow and sets it in the session cookie
+func setOpenIdAesSession(w http.ResponseWriter, r *http.Request, sessionData *config.IanaClaims) error {
+	sessionDataJson, err := json.Marshal(sessionData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+
+	// Cipher the session data
+	block, err := aes.NewCipher([]byte(config.GetSigningKey()))
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %v", err)
+	}
+
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("failed to create gcm: %v", err)
+	}
+
+	aesGcmNonce, err := util.CryptoRandomBytes(aesGcm.NonceSize())
+	if err != nil {
+		return fmt.Errorf("failed to generate random bytes: %v", err)
+	}
+
+	cipherSessionData := aesGcm.Seal(aesGcmNonce, aesGcmNonce, sessionDataJson, nil)
+	authCookie := http.Cookie{
+		Name:     config.TokenCookieName + "-aes",
+		Value:    base64.StdEncoding.EncodeToString(cipherSessionData),
+		Expires:  time.Unix(sessionData.ExpiresAt, 0),
+		HttpOnly: true,
+		Path:     config.Get().Server.WebRoot,
+		SameSite: http.SameSiteStrictMode,
+	}
+	httputil.SetChunkedCookie(w, r, authCookie)
+	return nil
+}
+
 func OpenIdCodeFlowHandler(w http.ResponseWriter, r *http.Request) bool {
 	conf := config.Get()
 
@@ -696,23 +905,27 @@ func OpenIdCodeFlowHandler(w http.ResponseWriter, r *http.Request) bool {
 
 	// CSRF mitigation
 	if stateError := business.ValidateOpenIdState(openIdParams); len(stateError) > 0 {
+		auditAuth(r, authEventLogin, "", authOutcomeFailure, stateError)
 		RespondWithError(w, http.StatusForbidden, fmt.Sprintf("Request rejected: %s", stateError))
 		return true
 	}
 
 	// Exchange the received code for a token
 	if err := business.RequestOpenIdToken(openIdParams, httputil.GuessKialiURL(r)); err != nil {
+		auditAuth(r, authEventLogin, "", authOutcomeFailure, "failure when retrieving user identity")
 		RespondWithDetailedError(w, http.StatusForbidden, "failure when retrieving user identity", err.Error())
 		return true
 	}
 
 	if err := business.ParseOpenIdToken(openIdParams); err != nil {
+		auditAuth(r, authEventLogin, "", authOutcomeFailure, err.Error())
 		RespondWithError(w, http.StatusUnauthorized, err.Error())
 		return true
 	}
 
 	// Replay attack mitigation
 	if nonceError := business.ValidateOpenIdNonceCode(openIdParams); len(nonceError) > 0 {
+		auditAuth(r, authEventLogin, openIdParams.Subject, authOutcomeFailure, nonceError)
 		RespondWithError(w, http.StatusForbidden, fmt.Sprintf("OpenId token rejected: %s", nonceError))
 		return true
 	}
@@ -723,6 +936,7 @@ func OpenIdCodeFlowHandler(w http.ResponseWriter, r *http.Request) bool {
 		// Since the configuration indicates RBAC is off, we do the validations:
 		err = business.ValidateOpenTokenInHouse(openIdParams)
 		if err != nil {
+			auditAuth(r, authEventLogin, openIdParams.Subject, authOutcomeFailure, "the OpenID token was rejected")
 			RespondWithDetailedError(w, http.StatusForbidden, "the OpenID token was rejected", err.Error())
 			return true
 		}
@@ -731,6 +945,9 @@ func OpenIdCodeFlowHandler(w http.ResponseWriter, r *http.Request) bool {
 		// config indicates that RBAC is on. For cases where RBAC is off, we simply assume that the
 		// Kiali ServiceAccount token should have enough privileges and skip this privilege check.
 		httpStatus, errMsg, detailedError := business.VerifyOpenIdUserAccess(openIdParams.IdToken)
+		if httpStatus != http.StatusOK {
+			auditAuth(r, authEventLogin, openIdParams.Subject, authOutcomeFailure, errMsg)
+		}
 		if detailedError != nil {
 			RespondWithDetailedError(w, httpStatus, errMsg, detailedError.Error())
 			return true
@@ -751,45 +968,17 @@ func OpenIdCodeFlowHandler(w http.ResponseWriter, r *http.Request) bool {
 	// "IanaClaims" type just for convenience to avoid creating new types and
 	// to bring some type convergence on types for the auth source code.
 	sessionData := business.BuildOpenIdJwtClaims(openIdParams)
-	sessionDataJson, err := json.Marshal(sessionData)
-	if err != nil {
-		RespondWithDetailedError(w, http.StatusInternalServerError, "Error when creating credentials - failed to marshal json", err.Error())
+	sessionData.ClusterTokens = business.GetClusterCredentials(nil, openIdParams.IdToken)
+	sessionData.RefreshToken = openIdParams.RefreshToken
+	if err := setOpenIdAesSession(w, r, sessionData); err != nil {
+		RespondWithDetailedError(w, http.StatusInternalServerError, "Error when creating credentials", err.Error())
 		return true
 	}
 
-	// Cipher the session data
-	block, err := aes.NewCipher([]byte(config.GetSigningKey()))
-	if err != nil {
-		RespondWithDetailedError(w, http.StatusInternalServerError, "Error when creating credentials - failed to create cipher", err.Error())
-		return true
-	}
-
-	aesGcm, err := cipher.NewGCM(block)
-	if err != nil {
-		RespondWithDetailedError(w, http.StatusInternalServerError, "Error when creating credentials - failed to create gcm", err.Error())
-		return true
-	}
-
-	aesGcmNonce, err := util.CryptoRandomBytes(aesGcm.NonceSize())
-	if err != nil {
-		RespondWithDetailedError(w, http.StatusInternalServerError, "Error when creating credentials - failed to generate random bytes", err.Error())
-		return true
-	}
-
-	cipherSessionData := aesGcm.Seal(aesGcmNonce, aesGcmNonce, sessionDataJson, nil)
-	authCookie := http.Cookie{
-		Name:     config.TokenCookieName + "-aes",
-		Value:    base64.StdEncoding.EncodeToString(cipherSessionData),
-		Expires:  openIdParams.ExpiresOn,
-		HttpOnly: true,
-		Path:     conf.Server.WebRoot,
-		SameSite: http.SameSiteStrictMode,
-	}
-	http.SetCookie(w, &authCookie)
-
 	// Let's redirect (remove the openid params) to let the Kiali-UI to boot
 	webRoot := conf.Server.WebRoot
 	webRootWithSlash := webRoot + "/"
+	auditAuth(r, authEventLogin, openIdParams.Subject, authOutcomeSuccess, "")
 	http.Redirect(w, r, webRootWithSlash, http.StatusFound)
 
 	return true
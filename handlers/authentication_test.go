@@ -21,6 +21,7 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 	"github.com/kiali/kiali/util"
 )
@@ -72,18 +73,64 @@ func TestStrategyTokenAuthentication(t *testing.T) {
 	assert.Equal(t, config.TokenCookieName, cookie.Name)
 	assert.True(t, cookie.HttpOnly)
 
-	// Build the token that we known we should receive
-	newToken, _ := config.GetSignedTokenString(config.IanaClaims{
-		SessionId: "foo",
-		StandardClaims: jwt.StandardClaims{
-			Subject:   "token",
-			ExpiresAt: clockTime.Add(time.Second * time.Duration(config.Get().LoginToken.ExpirationSeconds)).Unix(),
-			Issuer:    config.AuthStrategyTokenIssuer,
+	// Check the token has the claims that we known we should receive
+	claims := &config.IanaClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(cookie.Value, claims)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", claims.SessionId)
+	assert.Equal(t, "token", claims.Subject)
+	assert.Equal(t, config.AuthStrategyTokenIssuer, claims.Issuer)
+	assert.Equal(t, clockTime.Add(time.Second*time.Duration(config.Get().LoginToken.ExpirationSeconds)).Unix(), claims.ExpiresAt)
+	assert.Equal(t, clockTime.Unix(), claims.AuthTime)
+	assert.NotEmpty(t, claims.Id)
+	assert.Equal(t, clockTime.Add(time.Second*time.Duration(cfg.LoginToken.ExpirationSeconds)), cookie.Expires)
+}
+
+// TestStrategyTokenStoresClusterTokens checks that the credentials given for
+// remote clusters at login are stored in the session
+func TestStrategyTokenStoresClusterTokens(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyToken
+	cfg.LoginToken.SigningKey = util.RandomString(10)
+	cfg.KubernetesConfig.CacheEnabled = false
+	cfg.Clustering.Clusters = []config.RemoteCluster{
+		{
+			Name: "east",
+			Auth: config.RemoteClusterAuth{Strategy: config.ClusterAuthStrategyToken},
 		},
-	})
+		{
+			Name: "west",
+			Auth: config.RemoteClusterAuth{Strategy: config.ClusterAuthStrategyServiceAccount},
+		},
+	}
+	config.Set(cfg)
 
-	assert.Equal(t, cookie.Value, newToken)
-	assert.Equal(t, clockTime.Add(time.Second*time.Duration(cfg.LoginToken.ExpirationSeconds)), cookie.Expires)
+	clockTime := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: clockTime}
+
+	mockK8s(false)
+
+	form := url.Values{}
+	form.Add("token", "foo")
+	form.Add(business.ClusterTokenParamPrefix+"east", "east-token")
+	form.Add(business.ClusterTokenParamPrefix+"west", "west-token")
+	request := httptest.NewRequest("POST", "http://kiali/api/authenticate", nil)
+	request.PostForm = form
+
+	responseRecorder := httptest.NewRecorder()
+	Authenticate(responseRecorder, request)
+	response := responseRecorder.Result()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, response.Cookies(), 1)
+
+	claims := &config.IanaClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(response.Cookies()[0].Value, claims)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", claims.SessionId)
+	// Service account clusters don't take credentials from the user
+	assert.Equal(t, map[string]string{"east": "east-token"}, claims.ClusterTokens)
 }
 
 // TestStrategyTokenInvalidSignature checks that an altered JWT token is
@@ -360,6 +407,53 @@ func TestLogoutWhenNoSession(t *testing.T) {
 	assert.Zero(t, len(response.Cookies()))
 }
 
+// TestStrategyHeaderAuthentication checks that the identity set by a trusted
+// proxy is accepted and that requests from other sources are rejected
+func TestStrategyHeaderAuthentication(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyHeader
+	cfg.Auth.Header.TrustedProxies = []string{"10.0.0.0/8", "192.168.1.10"}
+	config.Set(cfg)
+
+	var identity models.UserIdentity
+	var user, token string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ = getUserIdentity(r)
+		user = r.Header.Get("Kiali-User")
+		token, _ = getToken(r)
+	})
+	handler := AuthenticationHandler{saToken: "kiali-sa"}.Handle(next)
+
+	request := httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+	request.RemoteAddr = "10.1.2.3:43210"
+	request.Header.Set("X-Forwarded-User", "alice")
+	request.Header.Set("X-Forwarded-Groups", "dev, ops")
+	request.Header.Set("Kiali-User", "admin")
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, "alice", identity.Username)
+	assert.Equal(t, []string{"dev", "ops"}, identity.Groups)
+	assert.Equal(t, "alice", user)
+	assert.Equal(t, "kiali-sa", token)
+
+	for _, remoteAddr := range []string{"192.168.1.11:43210", "invalid"} {
+		request = httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("X-Forwarded-User", "alice")
+		responseRecorder = httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, request)
+		assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+	}
+
+	request = httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+	request.RemoteAddr = "192.168.1.10:43210"
+	responseRecorder = httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
 // TestLogout checks that the Logout handler
 // sets a blank cookie to terminate the user's session
 func TestLogout(t *testing.T) {
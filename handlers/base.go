@@ -3,8 +3,14 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/kiali/kiali/business"
 )
 
+// cacheWarmingHeader tells clients that the Kiali cache is warming up in the background: responses are served
+// without cache meanwhile, so they can be slower
+const cacheWarmingHeader = "Kiali-Cache-Warming"
+
 type responseError struct {
 	Error  string `json:"error,omitempty"`
 	Detail string `json:"detail,omitempty"`
@@ -18,6 +24,9 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if business.IsCacheWarming() {
+		w.Header().Set(cacheWarmingHeader, "true")
+	}
 	w.WriteHeader(code)
 	_, _ = w.Write(response)
 }
@@ -30,6 +39,9 @@ func RespondWithJSONIndent(w http.ResponseWriter, code int, payload interface{})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if business.IsCacheWarming() {
+		w.Header().Set(cacheWarmingHeader, "true")
+	}
 	w.WriteHeader(code)
 	_, _ = w.Write(response)
 }
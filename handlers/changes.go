@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
+)
+
+// Time window of the timelines of changes when no duration is given
+const defaultChangesDuration = time.Hour
+
+// NamespaceChanges is the API handler to fetch the timeline of the changes of a namespace, or of one of its services
+// with ?service=, in the time window given by the duration and the query time, as for the metrics
+func NamespaceChanges(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	end := util.Clock.Now()
+	if queryTime := queryParams.Get("queryTime"); queryTime != "" {
+		num, err := strconv.ParseInt(queryTime, 10, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Cannot parse query parameter 'queryTime'")
+			return
+		}
+		end = time.Unix(num, 0)
+	}
+	duration := defaultChangesDuration
+	if dur := queryParams.Get("duration"); dur != "" {
+		num, err := strconv.ParseInt(dur, 10, 64)
+		if err != nil || num <= 0 {
+			RespondWithError(w, http.StatusBadRequest, "Cannot parse query parameter 'duration', positive number of seconds expected")
+			return
+		}
+		duration = time.Duration(num) * time.Second
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	timeline, err := layer.Change.GetChangeTimeline(mux.Vars(r)["namespace"], queryParams.Get("service"), end.Add(-duration), end)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, timeline)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// Clusters is the API handler to list the remote clusters, configured or registered
+func Clusters(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, business.Cluster.GetClusters(selector))
+}
+
+// ClusterRegister is the API handler to register a remote cluster, reached with the remote secret of a Secret
+func ClusterRegister(w http.ResponseWriter, r *http.Request) {
+	var request models.ClusterRegistration
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Register request with bad json: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	cluster, err := business.Cluster.RegisterCluster(request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "REGISTER on Cluster: "+cluster.Name+" Secret: "+request.SecretRef.Namespace+"/"+request.SecretRef.Name)
+	RespondWithJSON(w, http.StatusOK, cluster)
+}
+
+// ClusterDeregister is the API handler to deregister a remote cluster registered through the API
+func ClusterDeregister(w http.ResponseWriter, r *http.Request) {
+	cluster := mux.Vars(r)["cluster"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	if err := business.Cluster.DeregisterCluster(cluster); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "DEREGISTER on Cluster: "+cluster)
+	RespondWithCode(w, http.StatusNoContent)
+}
+
+// ClustersStatus is the API handler to check the connections of Kiali to every cluster and to their telemetry
+// backends
+func ClustersStatus(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, business.Cluster.GetClustersStatus(getClusterTokens(r), selector))
+}
+
+// ClustersCapabilities is the API handler to list the optional APIs supported by every cluster
+func ClustersCapabilities(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, business.Cluster.GetClustersCapabilities(getClusterTokens(r), selector))
+}
+
+// ClusterTraffic is the API handler to fetch the matrix of the traffic between the clusters
+func ClusterTraffic(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	matrix, err := business.Cluster.GetClusterTrafficMatrix(r.URL.Query().Get("window"), selector)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, matrix)
+}
+
+// ClustersEastWestProbe is the API handler to probe the connectivity between the clusters through their east-west
+// gateways
+func ClustersEastWestProbe(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, business.Cluster.ProbeEastWestGateways(getClusterTokens(r), selector))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/status"
 )
 
 const (
@@ -85,9 +86,18 @@ func Config(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	// The features not set in the configuration are reported with their defaults
+	publicConfig.KialiFeatureFlags.Features = config.KialiFeatureFlags.EnabledFeatures()
+
 	RespondWithJSONIndent(w, http.StatusOK, publicConfig)
 }
 
+// ConfigValidate is the API handler reporting the problems of the configuration of the running instance, as
+// contradictory settings or unreachable external services
+func ConfigValidate(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, status.ValidateConfig(config.Get()))
+}
+
 type PrometheusPartialConfig struct {
 	Global struct {
 		Scrape_interval string
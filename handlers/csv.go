@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+// Value of the "format" query parameter of the list handlers to export the list as CSV
+const csvFormat = "csv"
+
+// isCSVRequested returns true when the list is requested as CSV, with ?format=csv
+func isCSVRequested(r *http.Request) bool {
+	return r.URL.Query().Get("format") == csvFormat
+}
+
+// RespondWithCSV writes the header and the rows as a CSV attachment, i.e. to open the list in a spreadsheet
+func RespondWithCSV(w http.ResponseWriter, filename string, header []string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(header)
+	_ = writer.WriteAll(rows)
+}
+
+// csvLabels flattens labels in a cell, i.e. "app=reviews;version=v1"
+func csvLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+func workloadListCSV(list models.WorkloadList) ([]string, [][]string) {
+	header := []string{"namespace", "name", "type", "createdAt", "istioSidecar", "appLabel", "versionLabel", "podCount", "labels"}
+	rows := [][]string{}
+	for _, workload := range list.Workloads {
+		rows = append(rows, []string{
+			list.Namespace.Name,
+			workload.Name,
+			workload.Type,
+			workload.CreatedAt,
+			strconv.FormatBool(workload.IstioSidecar),
+			strconv.FormatBool(workload.AppLabel),
+			strconv.FormatBool(workload.VersionLabel),
+			strconv.Itoa(workload.PodCount),
+			csvLabels(workload.Labels),
+		})
+	}
+	return header, rows
+}
+
+func serviceListCSV(list models.ServiceList) ([]string, [][]string) {
+	header := []string{"namespace", "name", "istioSidecar", "appLabel", "labels"}
+	rows := [][]string{}
+	for _, service := range list.Services {
+		rows = append(rows, []string{
+			list.Namespace.Name,
+			service.Name,
+			strconv.FormatBool(service.IstioSidecar),
+			strconv.FormatBool(service.AppLabel),
+			csvLabels(service.Labels),
+		})
+	}
+	return header, rows
+}
+
+func appListCSV(list models.AppList) ([]string, [][]string) {
+	header := []string{"namespace", "name", "istioSidecar", "labels"}
+	rows := [][]string{}
+	for _, app := range list.Apps {
+		rows = append(rows, []string{
+			list.Namespace.Name,
+			app.Name,
+			strconv.FormatBool(app.IstioSidecar),
+			csvLabels(app.Labels),
+		})
+	}
+	return header, rows
+}
+
+// istioConfigListCSV lists one Istio object by row, with the result of its validation when the list is validated
+func istioConfigListCSV(list models.IstioConfigList) ([]string, [][]string) {
+	header := []string{"namespace", "objectType", "name", "createdAt", "resourceVersion", "valid", "errors", "warnings"}
+	rows := [][]string{}
+	add := func(objectType string, meta meta_v1.ObjectMeta) {
+		row := []string{meta.Namespace, objectType, meta.Name, "", meta.ResourceVersion, "", "", ""}
+		if !meta.CreationTimestamp.IsZero() {
+			row[3] = meta.CreationTimestamp.UTC().Format(time.RFC3339)
+		}
+		key := models.IstioValidationKey{ObjectType: models.ObjectTypeSingular[objectType], Name: meta.Name, Namespace: meta.Namespace}
+		if validation, ok := list.IstioValidations[key]; ok {
+			errors, warnings := 0, 0
+			for _, check := range validation.Checks {
+				if check.Severity == models.ErrorSeverity {
+					errors++
+				} else if check.Severity == models.WarningSeverity {
+					warnings++
+				}
+			}
+			row[5], row[6], row[7] = strconv.FormatBool(validation.Valid), strconv.Itoa(errors), strconv.Itoa(warnings)
+		}
+		rows = append(rows, row)
+	}
+
+	for _, o := range list.Gateways {
+		add("gateways", o.Metadata)
+	}
+	for _, o := range list.VirtualServices.Items {
+		add("virtualservices", o.Metadata)
+	}
+	for _, o := range list.DestinationRules.Items {
+		add("destinationrules", o.Metadata)
+	}
+	for _, o := range list.ServiceEntries {
+		add("serviceentries", o.Metadata)
+	}
+	for _, o := range list.WorkloadEntries {
+		add("workloadentries", o.Metadata)
+	}
+	for _, o := range list.EnvoyFilters {
+		add("envoyfilters", o.Metadata)
+	}
+	for _, o := range list.Sidecars {
+		add("sidecars", o.Metadata)
+	}
+	for _, o := range list.AuthorizationPolicies {
+		add("authorizationpolicies", o.Metadata)
+	}
+	for _, o := range list.PeerAuthentications {
+		add("peerauthentications", o.Metadata)
+	}
+	for _, o := range list.RequestAuthentications {
+		add("requestauthentications", o.Metadata)
+	}
+	return header, rows
+}
+
+// validationsCSV lists one check of the validations by row, and the objects without check, sorted by object
+func validationsCSV(validations models.IstioValidations) ([]string, [][]string) {
+	header := []string{"namespace", "objectType", "name", "valid", "severity", "message", "path"}
+	keys := make([]models.IstioValidationKey, 0, len(validations))
+	for key := range validations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	rows := [][]string{}
+	for _, key := range keys {
+		validation := validations[key]
+		valid := strconv.FormatBool(validation.Valid)
+		if len(validation.Checks) == 0 {
+			rows = append(rows, []string{key.Namespace, key.ObjectType, key.Name, valid, "", "", ""})
+		}
+		for _, check := range validation.Checks {
+			rows = append(rows, []string{key.Namespace, key.ObjectType, key.Name, valid, string(check.Severity), check.Message, check.Path})
+		}
+	}
+	return header, rows
+}
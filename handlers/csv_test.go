@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestRespondWithCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	list := models.WorkloadList{
+		Namespace: models.Namespace{Name: "bookinfo"},
+		Workloads: []models.WorkloadListItem{
+			{Name: "reviews-v1", Type: "Deployment", IstioSidecar: true, PodCount: 2, Labels: map[string]string{"version": "v1", "app": "reviews"}},
+		},
+	}
+	header, rows := workloadListCSV(list)
+	rr := httptest.NewRecorder()
+	RespondWithCSV(rr, "workloads-bookinfo", header, rows)
+
+	assert.Equal("text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(`attachment; filename="workloads-bookinfo.csv"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal("namespace,name,type,createdAt,istioSidecar,appLabel,versionLabel,podCount,labels\n"+
+		"bookinfo,reviews-v1,Deployment,,true,false,false,2,app=reviews;version=v1\n", rr.Body.String())
+}
+
+func TestIstioConfigListCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := models.VirtualService{}
+	vs.Metadata = meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo", ResourceVersion: "42"}
+	gw := models.Gateway{}
+	gw.Metadata = meta_v1.ObjectMeta{Name: "bookinfo-gateway", Namespace: "bookinfo"}
+	list := models.IstioConfigList{
+		Gateways:        models.Gateways{gw},
+		VirtualServices: models.VirtualServices{Items: []models.VirtualService{vs}},
+		IstioValidations: models.IstioValidations{
+			{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}: {
+				Valid:  false,
+				Checks: []*models.IstioCheck{{Severity: models.ErrorSeverity}, {Severity: models.WarningSeverity}, {Severity: models.ErrorSeverity}},
+			},
+		},
+	}
+
+	_, rows := istioConfigListCSV(list)
+	assert.Len(rows, 2)
+	assert.Equal([]string{"bookinfo", "gateways", "bookinfo-gateway"}, rows[0][:3])
+	assert.Equal([]string{"", "", ""}, rows[0][5:])
+	assert.Equal([]string{"bookinfo", "virtualservices", "reviews"}, rows[1][:3])
+	assert.Equal([]string{"", "42", "false", "2", "1"}, rows[1][3:])
+}
+
+func TestValidationsCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	validations := models.IstioValidations{
+		{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}: {
+			Valid:  false,
+			Checks: []*models.IstioCheck{{Message: "KIA1104 The weight is assumed to be 100 because there is only one route destination", Severity: models.WarningSeverity, Path: "spec/http[0]/route[0]/weight"}},
+		},
+		{ObjectType: "destinationrule", Name: "reviews", Namespace: "bookinfo"}: {Valid: true},
+	}
+
+	_, rows := validationsCSV(validations)
+	assert.Equal([][]string{
+		{"bookinfo", "destinationrule", "reviews", "true", "", "", ""},
+		{"bookinfo", "virtualservice", "reviews", "false", "warning", "KIA1104 The weight is assumed to be 100 because there is only one route destination", "spec/http[0]/route[0]/weight"},
+	}, rows)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	// The handlers registered in the default mux are not served: the server replaces the default mux
+	"net/http/pprof"
+	runtime_pprof "runtime/pprof"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+)
+
+// CacheStatus is the API handler to diagnose the caches of Kiali: sync status, object counts and memory of the
+// informers, and hits and misses of the caches
+func CacheStatus(w http.ResponseWriter, r *http.Request) {
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	namespaces, err := layer.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, business.GetCacheStatus(namespaces))
+}
+
+// TracingDiagnose is the API handler to diagnose the connection to the tracing backend: TLS handshake, credentials,
+// flavor of the API and clock skew, with hints to fix the configuration
+func TracingDiagnose(w http.ResponseWriter, r *http.Request) {
+	token, err := getToken(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Tracing diagnose initialization error: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, business.DiagnoseTracing(token))
+}
+
+// Seconds of CPU profiling or tracing when not requested, to complete within the write timeout of the server
+const defaultProfileSeconds = "10"
+
+// Maximum seconds of profiling or tracing that can be requested
+const maxProfileSeconds = 60
+
+// DebugProfile is a profile of Kiali which can be fetched in the pprof format
+type DebugProfile struct {
+	Name string `json:"name"`
+	// Number of instances, i.e. of goroutines, in the profile
+	Count int `json:"count"`
+}
+
+// checkProfilerAccess responds with an error when the profiler is disabled or the user is not an administrator of
+// Kiali, and returns false
+func checkProfilerAccess(w http.ResponseWriter, r *http.Request) bool {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return false
+	}
+	if err := business.Debug.CheckProfilerAccess(); err != nil {
+		handleErrorResponse(w, err)
+		return false
+	}
+	return true
+}
+
+// DebugProfiles is the API handler to list the profiles of Kiali, in addition to the CPU profile and the trace
+func DebugProfiles(w http.ResponseWriter, r *http.Request) {
+	if !checkProfilerAccess(w, r) {
+		return
+	}
+	profiles := []DebugProfile{}
+	for _, p := range runtime_pprof.Profiles() {
+		profiles = append(profiles, DebugProfile{Name: p.Name(), Count: p.Count()})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	RespondWithJSON(w, http.StatusOK, profiles)
+}
+
+// DebugProfileHandler is the API handler to fetch a profile of Kiali, i.e. goroutine or allocs, in the pprof format.
+// The CPU profile is "profile" and the execution trace is "trace".
+func DebugProfileHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkProfilerAccess(w, r) {
+		return
+	}
+	profile := mux.Vars(r)["profile"]
+	query := r.URL.Query()
+	if seconds := query.Get("seconds"); seconds != "" {
+		if s, err := strconv.Atoi(seconds); err != nil || s < 1 || s > maxProfileSeconds {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid seconds [%s]: expected an integer between 1 and %d", seconds, maxProfileSeconds))
+			return
+		}
+	}
+	audit(r, "PROFILE on Kiali: "+profile)
+
+	switch profile {
+	case "profile", "trace":
+		if query.Get("seconds") == "" {
+			query.Set("seconds", defaultProfileSeconds)
+			r.URL.RawQuery = query.Encode()
+		}
+		if profile == "profile" {
+			pprof.Profile(w, r)
+		} else {
+			pprof.Trace(w, r)
+		}
+	default:
+		if runtime_pprof.Lookup(profile) == nil {
+			RespondWithError(w, http.StatusNotFound, "Unknown profile: "+profile)
+			return
+		}
+		pprof.Handler(profile).ServeHTTP(w, r)
+	}
+}
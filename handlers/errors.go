@@ -11,7 +11,7 @@ import (
 )
 
 // Helper method to adjust error code in the handler's response
-// It helps for business methods that can respond AccessibleError and NotFound cases
+// It helps for business methods that can respond AccessibleError, NotFound, Forbidden, BadRequest and TooManyRequests cases
 // Some handlers can use a direct response
 func handleErrorResponse(w http.ResponseWriter, err error, extraMesg ...string) {
 	errorMsg := err.Error()
@@ -23,6 +23,14 @@ func handleErrorResponse(w http.ResponseWriter, err error, extraMesg ...string)
 		RespondWithError(w, http.StatusForbidden, errorMsg)
 	} else if errors.IsNotFound(err) {
 		RespondWithError(w, http.StatusNotFound, errorMsg)
+	} else if errors.IsForbidden(err) {
+		RespondWithError(w, http.StatusForbidden, errorMsg)
+	} else if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, errorMsg)
+	} else if errors.IsTooManyRequests(err) {
+		RespondWithError(w, http.StatusTooManyRequests, errorMsg)
+	} else if errors.IsConflict(err) {
+		RespondWithError(w, http.StatusConflict, errorMsg)
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
 		errorMsg = statusError.ErrStatus.Message
 		RespondWithError(w, http.StatusInternalServerError, errorMsg)
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// FederationInstances is the API handler to check the remote Kiali instances aggregated by the federation
+func FederationInstances(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	instances, err := business.Federation.GetInstances()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, instances)
+}
+
+// FederationNamespaces is the API handler to list the namespaces of all the remote Kiali instances
+func FederationNamespaces(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	namespaces, err := business.Federation.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, namespaces)
+}
+
+// FederationProxy is the API handler to read the API of the remote Kiali instance of a cluster
+func FederationProxy(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := business.Federation.Proxy(params["cluster"], params["path"], r.URL.RawQuery)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/util"
 )
 
@@ -236,11 +237,16 @@ func (p *workloadHealthParams) extract(r *http.Request) {
 	p.WorkloadType = query.Get("type")
 }
 
+// adjustRateInterval shortens the rate interval of a namespace created more recently. The rate interval annotation of
+// the namespace overrides the requested one, i.e. to smooth the errors of batch jobs.
 func adjustRateInterval(business *business.Layer, namespace, rateInterval string, queryTime time.Time) (string, error) {
 	namespaceInfo, err := business.Namespace.GetNamespace(namespace)
 	if err != nil {
 		return "", err
 	}
+	if annotated, ok := namespaceInfo.HealthAnnotations[models.RateIntervalHealthAnnotation]; ok {
+		rateInterval = annotated
+	}
 	interval, err := util.AdjustRateInterval(namespaceInfo.CreationTimestamp, queryTime, rateInterval)
 	if err != nil {
 		return "", err
@@ -83,6 +83,11 @@ func IstioConfigList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isCSVRequested(r) {
+		header, rows := istioConfigListCSV(istioConfig)
+		RespondWithCSV(w, "istio-config-"+namespace, header, rows)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, istioConfig)
 }
 
@@ -249,6 +254,7 @@ func audit(r *http.Request, message string) {
 	if config.Get().Server.AuditLog {
 		user := r.Header.Get("Kiali-User")
 		log.Infof("AUDIT User [%s] Msg [%s]", user, message)
+		business.RecordAudit(user, message)
 	}
 }
 
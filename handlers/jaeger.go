@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/models"
 )
 
@@ -23,6 +25,7 @@ func GetJaegerInfo(w http.ResponseWriter, r *http.Request) {
 			Integration:          jaegerConfig.InClusterURL != "",
 			URL:                  jaegerConfig.URL,
 			NamespaceSelector:    jaegerConfig.NamespaceSelector,
+			Provider:             jaegerConfig.Provider,
 			WhiteListIstioSystem: jaegerConfig.WhiteListIstioSystem,
 		}
 	} else {
@@ -118,6 +121,22 @@ func ErrorTraces(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Cannot parse parameter 'duration': "+err.Error())
 		return
 	}
+	if groups, _ := strconv.ParseBool(queryParams.Get("groups")); groups {
+		limit := 200
+		if rawLimit := queryParams.Get("limit"); rawLimit != "" {
+			if limit, err = strconv.Atoi(rawLimit); err != nil {
+				RespondWithError(w, http.StatusBadRequest, "Cannot parse parameter 'limit': "+err.Error())
+				return
+			}
+		}
+		traceGroups, err := business.Jaeger.GetErrorTraceGroups(namespace, app, time.Second*time.Duration(conv), limit)
+		if err != nil {
+			RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, traceGroups)
+		return
+	}
 	traces, err := business.Jaeger.GetErrorTraces(namespace, app, time.Second*time.Duration(conv))
 	if err != nil {
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
@@ -126,6 +145,33 @@ func ErrorTraces(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, traces)
 }
 
+// AppTraceQLSearch is the API handler to search the traces of a specific app with a TraceQL query
+func AppTraceQLSearch(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "TraceQL search initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	app := params["app"]
+	q, err := readTraceQLQuery(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	traces, err := business.Jaeger.SearchAppTraceQL(namespace, app, q)
+	if err != nil {
+		if traceQLErr, ok := err.(*jaeger.TraceQLError); ok && traceQLErr.Code == http.StatusBadRequest {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, traces)
+}
+
 func TraceDetails(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
 	if err != nil {
@@ -245,5 +291,74 @@ func readQuery(values url.Values) (models.TracingQuery, error) {
 		Tags:        tags,
 		Limit:       limit,
 		MinDuration: minDuration,
+		Cluster:     values.Get("cluster"),
 	}, nil
 }
+
+func readTraceQLQuery(values url.Values) (models.TraceQLQuery, error) {
+	q := models.TraceQLQuery{
+		Query:           values.Get("q"),
+		Cluster:         values.Get("cluster"),
+		Limit:           20,
+		SpansPerSpanSet: 3,
+	}
+	for param, t := range map[string]*time.Time{"startMicros": &q.Start, "endMicros": &q.End} {
+		if raw := values.Get(param); raw != "" {
+			micros, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return q, fmt.Errorf("Cannot parse parameter '%s': %v", param, err)
+			}
+			*t = time.Unix(0, micros*int64(time.Microsecond))
+		}
+	}
+	for param, d := range map[string]*time.Duration{"minDuration": &q.MinDuration, "maxDuration": &q.MaxDuration} {
+		if raw := values.Get(param); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil {
+				return q, fmt.Errorf("Cannot parse parameter '%s': %v", param, err)
+			}
+			*d = parsed
+		}
+	}
+	for param, n := range map[string]*int{"limit": &q.Limit, "spss": &q.SpansPerSpanSet} {
+		if raw := values.Get(param); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil {
+				return q, fmt.Errorf("Cannot parse parameter '%s': %v", param, err)
+			}
+			*n = parsed
+		}
+	}
+	if attributes := values.Get("attributes"); attributes != "" {
+		if err := json.Unmarshal([]byte(attributes), &q.Attributes); err != nil {
+			return q, fmt.Errorf("Cannot parse parameter 'attributes': %v", err)
+		}
+	}
+	return q, nil
+}
+
+// ServiceOperations is the API handler aggregating the spans of a specific service by operation
+func ServiceOperations(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	q, err := readQuery(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := business.Jaeger.GetServiceOperationStats(namespace, service, q)
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
+)
+
+// NamespaceEvents is the API handler to fetch the recent Kubernetes events of the objects of a namespace
+func NamespaceEvents(w http.ResponseWriter, r *http.Request) {
+	respondWithEvents(w, r, func(layer *business.Layer, namespace string, warningsOnly bool) (models.KubernetesEvents, error) {
+		return layer.Event.GetNamespaceEvents(namespace, warningsOnly)
+	})
+}
+
+// WorkloadEvents is the API handler to fetch the recent Kubernetes events of a workload and of its pods
+func WorkloadEvents(w http.ResponseWriter, r *http.Request) {
+	workload := mux.Vars(r)["workload"]
+	respondWithEvents(w, r, func(layer *business.Layer, namespace string, warningsOnly bool) (models.KubernetesEvents, error) {
+		return layer.Event.GetWorkloadEvents(namespace, workload, warningsOnly)
+	})
+}
+
+// ServiceEvents is the API handler to fetch the recent Kubernetes events of a service and of the pods it selects
+func ServiceEvents(w http.ResponseWriter, r *http.Request) {
+	service := mux.Vars(r)["service"]
+	respondWithEvents(w, r, func(layer *business.Layer, namespace string, warningsOnly bool) (models.KubernetesEvents, error) {
+		return layer.Event.GetServiceEvents(namespace, service, warningsOnly)
+	})
+}
+
+// respondWithEvents reads the events with the given function, keeping only the warnings with ?type=Warning
+func respondWithEvents(w http.ResponseWriter, r *http.Request, getEvents func(*business.Layer, string, bool) (models.KubernetesEvents, error)) {
+	eventType := r.URL.Query().Get("type")
+	if eventType != "" && eventType != core_v1.EventTypeWarning {
+		RespondWithError(w, http.StatusBadRequest, "Invalid event type ["+eventType+"], only Warning is supported")
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	events, err := getEvents(layer, mux.Vars(r)["namespace"], eventType == core_v1.EventTypeWarning)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// ControlPlaneRevisions is the API handler to fetch the revisions of the control plane running in the mesh
+func ControlPlaneRevisions(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	revisions, err := business.Mesh.GetControlPlaneRevisions()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, revisions)
+}
+
+// ControlPlaneRevisionsCompare is the API handler comparing two revisions of the control plane, to prepare a canary
+// upgrade
+func ControlPlaneRevisionsCompare(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		RespondWithError(w, http.StatusBadRequest, "The revisions to compare are required, in the 'from' and 'to' parameters")
+		return
+	}
+	comparison, err := business.Mesh.CompareRevisions(from, to)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, comparison)
+}
+
+// AmbientReadiness is the API handler reporting what blocks or changes when migrating a namespace from sidecars to
+// ambient
+func AmbientReadiness(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	readiness, err := business.Mesh.GetAmbientReadiness(mux.Vars(r)["namespace"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, readiness)
+}
+
+// MeshCertificates is the API handler reporting the expiration of the certificates of the control plane and, when a
+// namespace is given, of the proxies of the namespace
+func MeshCertificates(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	certificates, err := business.Mesh.GetCertificates(r.URL.Query().Get("namespace"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, certificates)
+}
+
+// MeshDrift is the API handler comparing the configuration of the mesh between the home cluster and the remote
+// clusters the user has credentials for, optionally selected by their labels. The home cluster is the reference of
+// the comparison: it is never filtered out.
+func MeshDrift(w http.ResponseWriter, r *http.Request) {
+	selector, err := getClusterSelector(r)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	clusterTokens := getClusterTokens(r)
+	remotes := map[string]kubernetes.ClientInterface{}
+	clientErrors := map[string]string{}
+	for _, cluster := range config.Get().Clustering.Clusters {
+		if !selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		client, err := business.GetClusterClient(cluster.Name, clusterTokens[cluster.Name])
+		if err != nil {
+			clientErrors[cluster.Name] = err.Error()
+			continue
+		}
+		remotes[cluster.Name] = client
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	drift := business.Mesh.GetMeshDrift(remotes)
+	for cluster, message := range clientErrors {
+		if drift.Errors == nil {
+			drift.Errors = map[string]string{}
+		}
+		drift.Errors[cluster] = message
+	}
+	RespondWithJSON(w, http.StatusOK, drift)
+}
+
+// MeshSidecarSizing is the API handler comparing the resources used by the injected proxies to their requests and
+// limits, recommending their sizing
+func MeshSidecarSizing(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	report, err := business.Mesh.GetSidecarSizing(r.URL.Query().Get("window"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
+
+// MeshOutboundTrafficPolicy is the API handler listing the external hosts which need a ServiceEntry with the
+// REGISTRY_ONLY outbound traffic policy
+func MeshOutboundTrafficPolicy(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	analysis, err := business.Mesh.AnalyzeOutboundTrafficPolicy(r.URL.Query().Get("window"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, analysis)
+}
+
+// MeshExternalServices is the API handler listing the external hosts of the mesh, with the workloads calling them and
+// their TLS posture
+func MeshExternalServices(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	inventory, err := business.Mesh.GetExternalServices(r.URL.Query().Get("window"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, inventory)
+}
+
+// MeshInjectionCoverage is the API handler reporting the pods of each namespace in and out of the data plane
+func MeshInjectionCoverage(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	coverage, err := business.Mesh.GetInjectionCoverage()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, coverage)
+}
@@ -213,6 +213,13 @@ func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery
 		}
 		q.Reporter = reporter
 	}
+	if exemplars := queryParams.Get("exemplars"); exemplars != "" {
+		if b, err := strconv.ParseBool(exemplars); err == nil {
+			q.Exemplars = b
+		} else {
+			return errors.New("bad request, query parameter 'exemplars' must be either 'true' or 'false'")
+		}
+	}
 	return extractBaseMetricsQueryParams(queryParams, &q.RangeQuery, namespaceInfo)
 }
 
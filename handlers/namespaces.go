@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 
@@ -26,6 +27,17 @@ func NamespaceList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// API tokens only see the namespaces of their scope
+	if scope, isApiToken := getApiTokenScope(r); isApiToken {
+		scoped := []models.Namespace{}
+		for _, ns := range namespaces {
+			if scope.AllowsNamespace(ns.Name) {
+				scoped = append(scoped, ns)
+			}
+		}
+		namespaces = scoped
+	}
+
 	RespondWithJSON(w, http.StatusOK, namespaces)
 }
 
@@ -48,6 +60,11 @@ func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 	if errValidations != nil {
 		log.Error(errValidations)
 		RespondWithError(w, http.StatusInternalServerError, errValidations.Error())
+	} else if isCSVRequested(r) {
+		// The checks of the validations, instead of their summary
+		header, rows := validationsCSV(istioConfigValidationResults)
+		RespondWithCSV(w, "validations-"+namespace, header, rows)
+		return
 	} else {
 		validationSummary = istioConfigValidationResults.SummarizeValidation(namespace)
 	}
@@ -78,3 +95,78 @@ func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	audit(r, "UPDATE on Namespace: "+namespace+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, ns)
 }
+
+// NamespaceEnrollment is the API handler reporting the enrollment of a namespace in the mesh
+func NamespaceEnrollment(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Namespace initialization error: "+err.Error())
+		return
+	}
+	enrollment, err := business.Mesh.GetNamespaceEnrollment(mux.Vars(r)["namespace"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, enrollment)
+}
+
+// NamespaceEnrollmentPreflight is the API handler running the checks of the enrollment of a namespace, without
+// enrolling it
+func NamespaceEnrollmentPreflight(w http.ResponseWriter, r *http.Request) {
+	var request models.EnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Enrollment request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Namespace initialization error: "+err.Error())
+		return
+	}
+	preflight, err := business.Mesh.PreflightNamespaceEnrollment(mux.Vars(r)["namespace"], request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, preflight)
+}
+
+// NamespaceEnroll is the API handler enrolling a namespace in the sidecar or ambient data plane
+func NamespaceEnroll(w http.ResponseWriter, r *http.Request) {
+	var request models.EnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Enrollment request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Namespace initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	enrollment, err := business.Mesh.EnrollNamespace(namespace, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "ENROLL on Namespace: "+namespace+" Mode: "+request.Mode+" Revision: "+request.Revision)
+	RespondWithJSON(w, http.StatusOK, enrollment)
+}
+
+// NamespaceUnenroll is the API handler removing a namespace from the mesh
+func NamespaceUnenroll(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Namespace initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	enrollment, err := business.Mesh.UnenrollNamespace(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "UNENROLL on Namespace: "+namespace)
+	RespondWithJSON(w, http.StatusOK, enrollment)
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"regexp"
 
 	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
 )
 
 func ConfigDump(w http.ResponseWriter, r *http.Request) {
@@ -50,3 +53,142 @@ func ConfigDumpResourceEntries(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, dump)
 }
+
+// NamespaceProxiesStatus is the API handler reporting the xDS distribution status of the proxies of a namespace
+func NamespaceProxiesStatus(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	if _, err := checkNamespaceAccess(business.Namespace, namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	statuses, err := business.ProxyStatus.GetNamespaceProxiesStatus(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, statuses)
+}
+
+// MeshProxiesStatus is the API handler reporting the xDS distribution status of the proxies of every namespace
+// accessible by the user
+func MeshProxiesStatus(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	statuses := []models.ProxySyncStatus{}
+	for _, ns := range namespaces {
+		nsStatuses, err := business.ProxyStatus.GetNamespaceProxiesStatus(ns.Name)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		statuses = append(statuses, nsStatuses...)
+	}
+	RespondWithJSON(w, http.StatusOK, statuses)
+}
+
+// EnvoyStats is the API handler returning the stats of the Envoy of a pod, among the ones allowed by the configuration
+func EnvoyStats(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var filter *regexp.Regexp
+	if f := r.URL.Query().Get("filter"); f != "" {
+		if filter, err = regexp.Compile(f); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid filter: "+err.Error())
+			return
+		}
+	}
+
+	stats, err := business.ProxyStatus.GetEnvoyStats(params["namespace"], params["pod"], filter)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, stats)
+}
+
+// ConfigDumpDiff is the API handler comparing the clusters, listeners and routes of the Envoys of two pods
+func ConfigDumpDiff(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	otherPod := queryParams.Get("otherPod")
+	if otherPod == "" {
+		RespondWithError(w, http.StatusBadRequest, "The pod to compare with is required, in the 'otherPod' parameter")
+		return
+	}
+	otherNamespace := queryParams.Get("otherNamespace")
+	if otherNamespace == "" {
+		otherNamespace = params["namespace"]
+	}
+
+	diff, err := business.ProxyStatus.DiffConfigDumps(params["namespace"], params["pod"], otherNamespace, otherPod)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, diff)
+}
+
+// ZtunnelConfigDump is the API handler aggregating the config dumps of the ztunnels of the cluster, for a namespace
+// or else for every namespace accessible by the user, optionally for a workload only
+func ZtunnelConfigDump(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	namespaces := []string{}
+	if namespace := queryParams.Get("namespace"); namespace != "" {
+		if _, err := checkNamespaceAccess(business.Namespace, namespace); err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		namespaces = append(namespaces, namespace)
+	} else {
+		accessible, err := business.Namespace.GetNamespaces()
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		for _, ns := range accessible {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	dump, err := business.ProxyStatus.GetZtunnelConfig(namespaces, queryParams.Get("workload"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dump)
+}
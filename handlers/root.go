@@ -2,17 +2,34 @@ package handlers
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/kiali/kiali/status"
 )
 
 // Healthz is a trivial endpoint that simply returns a 200 status code with no response body.
-// This is to simply confirm the readiness of the server.
+// This is to simply confirm the readiness of the server: it returns a 503 once the server is stopping.
 // You can use this for readiness and liveness probes.
 func Healthz(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&notReady) == 1 {
+		RespondWithCode(w, http.StatusServiceUnavailable)
+		return
+	}
 	RespondWithCode(w, http.StatusOK)
 }
 
+// Set while the server stops, for the probes to fail so that no new request is routed to the server
+var notReady int32
+
+// SetReady sets whether the server is ready to serve new requests, as answered to the probes
+func SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&notReady, 0)
+	} else {
+		atomic.StoreInt32(&notReady, 1)
+	}
+}
+
 // Root provides basic status of the server.
 func Root(w http.ResponseWriter, r *http.Request) {
 	getStatus(w, r)
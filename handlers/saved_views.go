@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// SavedViewList is the API handler to list the views of the user and the views shared by the other users
+func SavedViewList(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureSavedViews)
+	if !ok {
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	views, err := business.SavedView.ListSavedViews(user)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, views)
+}
+
+// SavedViewDetails is the API handler to fetch a view of the user, or a view shared by another user
+func SavedViewDetails(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureSavedViews)
+	if !ok {
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	view, err := business.SavedView.GetSavedView(user, mux.Vars(r)["id"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, view)
+}
+
+// SavedViewCreate is the API handler to save a view of the user
+func SavedViewCreate(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureSavedViews)
+	if !ok {
+		return
+	}
+
+	var request models.SavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Create request with bad json: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	view, err := business.SavedView.CreateSavedView(user, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, view)
+}
+
+// SavedViewUpdate is the API handler to replace a view of the user
+func SavedViewUpdate(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureSavedViews)
+	if !ok {
+		return
+	}
+
+	var request models.SavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Update request with bad json: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	view, err := business.SavedView.UpdateSavedView(user, mux.Vars(r)["id"], request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, view)
+}
+
+// SavedViewDelete is the API handler to delete a view of the user
+func SavedViewDelete(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureSavedViews)
+	if !ok {
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	if err := business.SavedView.DeleteSavedView(user, mux.Vars(r)["id"]); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithCode(w, http.StatusNoContent)
+}
@@ -29,6 +29,11 @@ func ServiceList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isCSVRequested(r) {
+		header, rows := serviceListCSV(*serviceList)
+		RespondWithCSV(w, "services-"+namespace, header, rows)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, serviceList)
 }
 
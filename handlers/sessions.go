@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// rotationGracePeriod is how long a session token is still accepted after being replaced by a renewed one.
+// Requests sent by the browser before receiving the renewed token are not rejected.
+const rotationGracePeriod = 30 * time.Second
+
+// isReplacedSession returns true if the session token was renewed or revoked and can't be used anymore
+func isReplacedSession(claims *config.IanaClaims) bool {
+	return business.IsReplacedSession(claims.Id)
+}
+
+// replaceSession stops accepting the session token after the given grace period
+func replaceSession(claims *config.IanaClaims, gracePeriod time.Duration) {
+	business.ReplaceSession(claims.Id, time.Unix(claims.ExpiresAt, 0), gracePeriod)
+}
+
+// renewSession implements the sliding expiration of the sessions: once half of the lifetime of the session token
+// has passed, a new token is issued to the user and the current one is rotated out. Sessions are never renewed
+// beyond the maximum duration of a session.
+func renewSession(w http.ResponseWriter, r *http.Request, claims *config.IanaClaims) {
+	conf := config.Get()
+	if !conf.LoginToken.SlidingExpiration {
+		return
+	}
+
+	now := util.Clock.Now()
+	lifetime := time.Duration(conf.LoginToken.ExpirationSeconds) * time.Second
+	if time.Unix(claims.ExpiresAt, 0).Sub(now) > lifetime/2 {
+		return
+	}
+
+	authTime := claims.AuthTime
+	if authTime == 0 {
+		authTime = claims.IssuedAt
+	}
+	if authTime == 0 {
+		// Tokens created before sliding sessions were available can't be renewed
+		return
+	}
+
+	expiresOn := now.Add(lifetime)
+	if maxExpiresOn := time.Unix(authTime, 0).Add(time.Duration(conf.LoginToken.MaxSessionSeconds) * time.Second); expiresOn.After(maxExpiresOn) {
+		expiresOn = maxExpiresOn
+	}
+	if expiresOn.Unix() <= claims.ExpiresAt || business.IsRenewedSession(claims.Id) {
+		return
+	}
+
+	renewed := config.NewSessionClaims(claims.SessionId, claims.ClusterTokens, claims.Subject, expiresOn, claims.Issuer)
+	renewed.AuthTime = authTime
+	tokenString, err := config.GetSignedTokenString(renewed)
+	if err != nil {
+		log.Errorf("Cannot renew the session of user [%s]: %v", claims.Subject, err)
+		return
+	}
+
+	tokenCookie := http.Cookie{
+		Name:     config.TokenCookieName,
+		Value:    tokenString,
+		Expires:  expiresOn,
+		HttpOnly: true,
+		Path:     conf.Server.WebRoot,
+		SameSite: http.SameSiteStrictMode,
+	}
+	httputil.SetChunkedCookie(w, r, tokenCookie)
+	replaceSession(claims, rotationGracePeriod)
+	auditAuth(r, authEventRefresh, claims.Subject, authOutcomeSuccess, "")
+	log.Debugf("Session of user [%s] renewed until %s", claims.Subject, expiresOn.Format(time.RFC1123Z))
+}
+
+// renewOpenIdSession implements the sliding expiration of the sessions of the OpenId "authorization code" flow. These
+// sessions last as long as the id_token of the user: once half of the lifetime of the session has passed, the id_token
+// is renewed with the refresh token of the session, which the OpenId provider may rotate, and the current session is
+// rotated out. Sessions are never renewed beyond the maximum duration of a session.
+func renewOpenIdSession(w http.ResponseWriter, r *http.Request, claims *config.IanaClaims) {
+	conf := config.Get()
+	if !conf.LoginToken.SlidingExpiration || claims.RefreshToken == "" || claims.IssuedAt == 0 || claims.AuthTime == 0 {
+		return
+	}
+
+	now := util.Clock.Now()
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if expiresAt.Sub(now) > expiresAt.Sub(time.Unix(claims.IssuedAt, 0))/2 {
+		return
+	}
+	maxExpiresOn := time.Unix(claims.AuthTime, 0).Add(time.Duration(conf.LoginToken.MaxSessionSeconds) * time.Second)
+	if !expiresAt.Before(maxExpiresOn) || business.IsRenewedSession(claims.Id) {
+		return
+	}
+
+	openIdParams, err := business.RefreshOpenIdToken(claims.RefreshToken)
+	if err == nil && openIdParams.Subject != claims.Subject {
+		err = fmt.Errorf("the renewed id_token belongs to [%s]", openIdParams.Subject)
+	}
+	if err == nil && conf.Auth.OpenId.DisableRBAC {
+		err = business.ValidateOpenTokenInHouse(openIdParams)
+	}
+	if err != nil {
+		log.Warningf("Cannot renew the session of user [%s]: %v", claims.Subject, err)
+		auditAuth(r, authEventRefresh, claims.Subject, authOutcomeFailure, "the OpenId token cannot be renewed")
+		return
+	}
+
+	renewed := business.BuildOpenIdJwtClaims(openIdParams)
+	renewed.AuthTime = claims.AuthTime
+	renewed.ClusterTokens = claims.ClusterTokens
+	renewed.RefreshToken = openIdParams.RefreshToken
+	if openIdParams.ExpiresOn.After(maxExpiresOn) {
+		renewed.ExpiresAt = maxExpiresOn.Unix()
+	}
+	if err := setOpenIdAesSession(w, r, renewed); err != nil {
+		log.Errorf("Cannot renew the session of user [%s]: %v", claims.Subject, err)
+		return
+	}
+	replaceSession(claims, rotationGracePeriod)
+	auditAuth(r, authEventRefresh, claims.Subject, authOutcomeSuccess, "")
+	log.Debugf("Session of user [%s] renewed until %s", claims.Subject, time.Unix(renewed.ExpiresAt, 0).Format(time.RFC1123Z))
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/util"
+)
+
+func setupSlidingSessions() time.Time {
+	cfg := config.NewConfig()
+	cfg.LoginToken.SigningKey = "kiali-test-key"
+	cfg.LoginToken.ExpirationSeconds = 3600
+	cfg.LoginToken.MaxSessionSeconds = 3 * 3600
+	cfg.LoginToken.SlidingExpiration = true
+	config.Set(cfg)
+
+	loginTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: loginTime}
+	return loginTime
+}
+
+func TestRenewSessionAfterHalfLifetime(t *testing.T) {
+	loginTime := setupSlidingSessions()
+	claims := config.NewSessionClaims("sa-token", nil, "alice", loginTime.Add(time.Hour), config.AuthStrategyTokenIssuer)
+
+	// Not renewed while most of the lifetime is ahead
+	util.Clock = util.ClockMock{Time: loginTime.Add(10 * time.Minute)}
+	responseRecorder := httptest.NewRecorder()
+	renewSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	assert.Empty(t, responseRecorder.Result().Cookies())
+
+	now := loginTime.Add(40 * time.Minute)
+	util.Clock = util.ClockMock{Time: now}
+	responseRecorder = httptest.NewRecorder()
+	renewSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	cookies := responseRecorder.Result().Cookies()
+	assert.Len(t, cookies, 1)
+
+	renewed := &config.IanaClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(cookies[0].Value, renewed)
+	assert.Nil(t, err)
+	assert.Equal(t, "sa-token", renewed.SessionId)
+	assert.Equal(t, "alice", renewed.Subject)
+	assert.Equal(t, now.Add(time.Hour).Unix(), renewed.ExpiresAt)
+	assert.Equal(t, loginTime.Unix(), renewed.AuthTime)
+	assert.NotEqual(t, claims.Id, renewed.Id)
+
+	// The previous token is rotated out once the grace period ends
+	assert.False(t, isReplacedSession(&claims))
+	util.Clock = util.ClockMock{Time: now.Add(rotationGracePeriod)}
+	assert.True(t, isReplacedSession(&claims))
+	assert.False(t, isReplacedSession(renewed))
+}
+
+func TestRenewSessionStopsAtMaxSession(t *testing.T) {
+	loginTime := setupSlidingSessions()
+	claims := config.NewSessionClaims("sa-token", nil, "alice", loginTime.Add(time.Hour), config.AuthStrategyTokenIssuer)
+	claims.ExpiresAt = loginTime.Add(150 * time.Minute).Unix()
+
+	now := loginTime.Add(130 * time.Minute)
+	util.Clock = util.ClockMock{Time: now}
+	responseRecorder := httptest.NewRecorder()
+	renewSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	cookies := responseRecorder.Result().Cookies()
+	assert.Len(t, cookies, 1)
+
+	renewed := &config.IanaClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(cookies[0].Value, renewed)
+	assert.Nil(t, err)
+	assert.Equal(t, loginTime.Add(3*time.Hour).Unix(), renewed.ExpiresAt)
+
+	// Once at the maximum, the session is not renewed anymore
+	util.Clock = util.ClockMock{Time: loginTime.Add(170 * time.Minute)}
+	responseRecorder = httptest.NewRecorder()
+	renewSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), renewed)
+	assert.Empty(t, responseRecorder.Result().Cookies())
+}
+
+func TestRenewSessionDisabled(t *testing.T) {
+	loginTime := setupSlidingSessions()
+	cfg := config.Get()
+	cfg.LoginToken.SlidingExpiration = false
+	config.Set(cfg)
+	claims := config.NewSessionClaims("sa-token", nil, "alice", loginTime.Add(time.Hour), config.AuthStrategyTokenIssuer)
+
+	util.Clock = util.ClockMock{Time: loginTime.Add(50 * time.Minute)}
+	responseRecorder := httptest.NewRecorder()
+	renewSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	assert.Empty(t, responseRecorder.Result().Cookies())
+}
+
+func TestRenewOpenIdSessionRotatesRefreshToken(t *testing.T) {
+	assert := assert.New(t)
+	loginTime := setupSlidingSessions()
+
+	var provider *httptest.Server
+	provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_, _ = w.Write([]byte(`{"issuer":"` + provider.URL + `","authorization_endpoint":"` + provider.URL + `/auth","token_endpoint":"` + provider.URL + `/token"}`))
+		case "/token":
+			assert.NoError(r.ParseForm())
+			assert.Equal("refresh_token", r.Form.Get("grant_type"))
+			assert.Equal("refresh-1", r.Form.Get("refresh_token"))
+			idToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub": "alice",
+				"exp": loginTime.Add(100 * time.Minute).Unix(),
+			}).SignedString([]byte("idp-key"))
+			_, _ = w.Write([]byte(`{"id_token":"` + idToken + `","refresh_token":"refresh-2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer provider.Close()
+	cfg := config.Get()
+	cfg.Auth.Strategy = config.AuthStrategyOpenId
+	cfg.Auth.OpenId.IssuerUri = provider.URL
+	// The session of the openid strategy is encrypted with the signing key, which must be a valid AES key
+	cfg.LoginToken.SigningKey = "kiali-test-key-1"
+	config.Set(cfg)
+
+	claims := config.NewSessionClaims("id-token-1", map[string]string{"east": "east-token"}, "alice", loginTime.Add(time.Hour), config.AuthStrategyOpenIdIssuer)
+	claims.RefreshToken = "refresh-1"
+
+	// Not renewed while most of the lifetime is ahead
+	util.Clock = util.ClockMock{Time: loginTime.Add(10 * time.Minute)}
+	responseRecorder := httptest.NewRecorder()
+	renewOpenIdSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	assert.Empty(responseRecorder.Result().Cookies())
+
+	now := loginTime.Add(40 * time.Minute)
+	util.Clock = util.ClockMock{Time: now}
+	responseRecorder = httptest.NewRecorder()
+	renewOpenIdSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	cookies := responseRecorder.Result().Cookies()
+	assert.Len(cookies, 1)
+	assert.Equal(config.TokenCookieName+"-aes", cookies[0].Name)
+
+	request := httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+	request.AddCookie(cookies[0])
+	renewed, err := business.GetOpenIdAesSession(request)
+	assert.NoError(err)
+	assert.Equal("refresh-2", renewed.RefreshToken)
+	assert.Equal("alice", renewed.Subject)
+	assert.Equal(loginTime.Add(100*time.Minute).Unix(), renewed.ExpiresAt)
+	assert.Equal(loginTime.Unix(), renewed.AuthTime)
+	assert.Equal("east-token", renewed.ClusterTokens["east"])
+
+	// The previous session is rotated out, and is not renewed again during the grace period
+	responseRecorder = httptest.NewRecorder()
+	renewOpenIdSession(responseRecorder, httptest.NewRequest("GET", "http://kiali/api/namespaces", nil), &claims)
+	assert.Empty(responseRecorder.Result().Cookies())
+	util.Clock = util.ClockMock{Time: now.Add(rotationGracePeriod)}
+	assert.True(isReplacedSession(&claims))
+	assert.False(isReplacedSession(renewed))
+}
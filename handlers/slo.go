@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
+)
+
+// ServiceSLO is the API handler to get the attainment and burn rates of the objectives of a service
+func ServiceSLO(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	queryTime, ok := extractSLOQueryTime(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	slo, err := business.SLO.GetServiceSLO(vars["namespace"], vars["service"], queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, slo)
+}
+
+// WorkloadSLO is the API handler to get the attainment and burn rates of the objectives of a workload
+func WorkloadSLO(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	queryTime, ok := extractSLOQueryTime(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	slo, err := business.SLO.GetWorkloadSLO(vars["namespace"], vars["workload"], queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, slo)
+}
+
+// extractSLOQueryTime returns the time of the query, in unix seconds, defaulting to now
+func extractSLOQueryTime(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	queryTime := r.URL.Query().Get("queryTime")
+	if queryTime == "" {
+		return util.Clock.Now(), true
+	}
+	num, err := strconv.ParseInt(queryTime, 10, 64)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "bad request, cannot parse query parameter 'queryTime'")
+		return time.Time{}, false
+	}
+	return time.Unix(num, 0), true
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+)
+
+const (
+	// The browsers reconnect after this delay when a stream ends
+	sseRetry = time.Second
+	// A comment is sent when no event was sent for this duration, so that the proxies don't close idle streams
+	sseKeepAliveInterval = 10 * time.Second
+	// Duration of the streams when the server has no write timeout
+	defaultStreamDuration = 25 * time.Second
+	// Time left to the streams, before the write timeout of the server, to write their last events
+	maxStreamEndMargin = 5 * time.Second
+)
+
+// streamDuration returns how long a stream of events lasts: it ends before the write timeout of the server, which
+// would cut it in the middle of an event.
+func streamDuration() time.Duration {
+	writeTimeout := time.Duration(config.Get().Server.WriteTimeout) * time.Second
+	if writeTimeout <= 0 {
+		return defaultStreamDuration
+	}
+	margin := writeTimeout / 5
+	if margin > maxStreamEndMargin {
+		margin = maxStreamEndMargin
+	}
+	return writeTimeout - margin
+}
+
+type shutdownContextKey struct{}
+
+// WithShutdown returns the base context of the requests of a server, holding the channel closed when the server
+// shuts down
+func WithShutdown(ctx context.Context, shutdown <-chan struct{}) context.Context {
+	return context.WithValue(ctx, shutdownContextKey{}, shutdown)
+}
+
+// streamContext returns the context of a stream of events, done when the client leaves, after the given duration,
+// or when the server shuts down: the shutdown doesn't wait for the streams, whose clients reconnect.
+func streamContext(r *http.Request, duration time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), duration)
+	if shutdown, ok := r.Context().Value(shutdownContextKey{}).(<-chan struct{}); ok {
+		go func() {
+			select {
+			case <-shutdown:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
+// sseWriter writes server-sent events. The events and the keepalives may be written concurrently.
+type sseWriter struct {
+	lock    sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// newSSEWriter starts a stream of server-sent events: the headers are flushed at once, so that the clients don't
+// wait for the first event to know that the stream is open. It returns false when the response can't be streamed.
+func newSSEWriter(w http.ResponseWriter) (*sseWriter, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Disable the buffering of the proxies, i.e. nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	s := &sseWriter{w: w, flusher: flusher}
+	s.write(fmt.Sprintf("retry: %d\n\n", sseRetry.Milliseconds()))
+	return s, true
+}
+
+// Send writes an event holding the data encoded in JSON. The ID and the name of the event are optional.
+func (s *sseWriter) Send(id, event string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	message := ""
+	if id != "" {
+		message += fmt.Sprintf("id: %s\n", id)
+	}
+	if event != "" {
+		message += fmt.Sprintf("event: %s\n", event)
+	}
+	return s.write(fmt.Sprintf("%sdata: %s\n\n", message, encoded))
+}
+
+// SendError writes an "error" event holding the message of the error encoded in JSON
+func (s *sseWriter) SendError(err error) {
+	_ = s.Send("", "error", err.Error())
+}
+
+// KeepAlive writes a comment at regular intervals until the returned function is called, which must be before the
+// handler returns.
+func (s *sseWriter) KeepAlive(interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if s.write(": keepalive\n\n") != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (s *sseWriter) write(message string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := fmt.Fprint(s.w, message); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestSSEWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	rr := httptest.NewRecorder()
+	events, ok := newSSEWriter(rr)
+	assert.True(ok)
+	// The headers are flushed before any event
+	assert.True(rr.Flushed)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("text/event-stream", rr.Header().Get("Content-Type"))
+	assert.Equal("no", rr.Header().Get("X-Accel-Buffering"))
+
+	assert.NoError(events.Send("42", "trace", map[string]string{"traceID": "abc"}))
+	events.SendError(errors.New("backend unavailable\nretry later"))
+	assert.Equal("retry: 1000\n\n"+
+		"id: 42\nevent: trace\ndata: {\"traceID\":\"abc\"}\n\n"+
+		"event: error\ndata: \"backend unavailable\\nretry later\"\n\n", rr.Body.String())
+}
+
+func TestSSEWriterKeepAlive(t *testing.T) {
+	rr := httptest.NewRecorder()
+	events, _ := newSSEWriter(rr)
+	stop := events.KeepAlive(time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	assert.True(t, strings.Contains(rr.Body.String(), ": keepalive\n\n"))
+}
+
+func TestStreamContextEndsOnShutdown(t *testing.T) {
+	shutdown := make(chan struct{})
+	r := httptest.NewRequest("GET", "/api/namespaces/bookinfo/apps/reviews/traces/tail", nil)
+	r = r.WithContext(WithShutdown(r.Context(), shutdown))
+	ctx, cancel := streamContext(r, time.Minute)
+	defer cancel()
+
+	close(shutdown)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the stream should end when the server shuts down")
+	}
+}
+
+func TestStreamDuration(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+
+	conf.Server.WriteTimeout = 30
+	config.Set(conf)
+	assert.Equal(25*time.Second, streamDuration())
+
+	conf.Server.WriteTimeout = 10
+	config.Set(conf)
+	assert.Equal(8*time.Second, streamDuration())
+
+	conf.Server.WriteTimeout = 0
+	config.Set(conf)
+	assert.Equal(defaultStreamDuration, streamDuration())
+}
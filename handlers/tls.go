@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 )
 
 // NamespaceTls is the API to get namespace-wide mTLS status
@@ -64,3 +66,82 @@ func MeshTls(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, globalmTLSStatus)
 }
+
+// NamespaceMTLSRollout is the API to get the state of the staged mTLS rollout of a namespace
+func NamespaceMTLSRollout(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	rollout, err := business.TLS.GetMTLSRollout(mux.Vars(r)["namespace"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// NamespaceMTLSRolloutStart is the API to start, or resume, the staged mTLS rollout of a namespace
+func NamespaceMTLSRolloutStart(w http.ResponseWriter, r *http.Request) {
+	var request models.MTLSRolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "mTLS rollout request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	rollout, err := business.TLS.StartMTLSRollout(namespace, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "MTLS ROLLOUT permissive on Namespace: "+namespace+" Soak: "+rollout.Soak)
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// NamespaceMTLSRolloutStrict is the API to flip the mTLS rollout of a namespace to STRICT
+func NamespaceMTLSRolloutStrict(w http.ResponseWriter, r *http.Request) {
+	var request models.MTLSRolloutStrictRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "mTLS rollout request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	rollout, err := business.TLS.StrictMTLSRollout(namespace, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	if request.Force {
+		audit(r, "MTLS ROLLOUT strict (forced) on Namespace: "+namespace)
+	} else {
+		audit(r, "MTLS ROLLOUT strict on Namespace: "+namespace)
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// NamespaceMTLSRolloutAbort is the API to restore the mTLS of a namespace as it was before its rollout
+func NamespaceMTLSRolloutAbort(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	if err = business.TLS.AbortMTLSRollout(namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "MTLS ROLLOUT abort on Namespace: "+namespace)
+	RespondWithCode(w, http.StatusOK)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultTraceTailInterval = 2 * time.Second
+	minTraceTailInterval     = time.Second
+)
+
+// TracesTail is the API handler streaming the new traces of an app, service or workload, as server-sent events
+func TracesTail(w http.ResponseWriter, r *http.Request) {
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Traces tail initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	q := business.TraceTailQuery{Namespace: params["namespace"], Interval: defaultTraceTailInterval}
+	for _, kind := range []string{"app", "service", "workload"} {
+		if name, ok := params[kind]; ok {
+			q.Kind, q.Name = kind, name
+		}
+	}
+	if err := readTraceTailQuery(r, &q); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := checkNamespaceAccess(layer.Namespace, q.Namespace); err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return
+	}
+	events, ok := newSSEWriter(w)
+	if !ok {
+		RespondWithError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+	stopKeepAlive := events.KeepAlive(sseKeepAliveInterval)
+	defer stopKeepAlive()
+
+	// The clients reconnect at the end of the stream, the EventSource of the browsers sending the ID of the last event
+	// received, the cursor of the last trace, to resume the tail
+	ctx, cancel := streamContext(r, streamDuration())
+	defer cancel()
+	err = layer.Jaeger.TailTraces(ctx, q, func(trace jaegerModels.Trace, cursor int64) error {
+		return events.Send(strconv.FormatInt(cursor, 10), "trace", trace)
+	})
+	if err != nil && r.Context().Err() == nil {
+		log.Debugf("Traces tail of %s [%s/%s] failed: %v", q.Kind, q.Namespace, q.Name, err)
+		events.SendError(err)
+	}
+}
+
+// readTraceTailQuery reads the cursor of the tail, from the ID of the last event or the "since" parameter, and the
+// polling interval
+func readTraceTailQuery(r *http.Request, q *business.TraceTailQuery) error {
+	q.Since = util.Clock.Now().UnixNano() / 1000
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	if since != "" {
+		parsed, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Cannot parse the cursor [%s]: %v", since, err)
+		}
+		q.Since = parsed
+	}
+	if interval := r.URL.Query().Get("interval"); interval != "" {
+		parsed, err := time.ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("Cannot parse parameter 'interval': %v", err)
+		}
+		if parsed < minTraceTailInterval {
+			return fmt.Errorf("The interval must be at least %v", minTraceTailInterval)
+		}
+		q.Interval = parsed
+	}
+	return nil
+}
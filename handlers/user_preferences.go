@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// UserPreferences is the API handler to fetch the preferences of the user
+func UserPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureUserPreferences)
+	if !ok {
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	preferences, err := business.Preferences.GetUserPreferences(user)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, preferences)
+}
+
+// UserPreferencesUpdate is the API handler to replace the preferences of the user
+func UserPreferencesUpdate(w http.ResponseWriter, r *http.Request) {
+	user, ok := getFeatureUser(w, r, config.FeatureUserPreferences)
+	if !ok {
+		return
+	}
+
+	var request models.UserPreferences
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Update request with bad json: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	preferences, err := business.Preferences.UpdateUserPreferences(user, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, preferences)
+}
@@ -5,7 +5,10 @@ import (
 	"net/http"
 	"net/url"
 
+	"k8s.io/apimachinery/pkg/labels"
+
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
@@ -80,6 +83,52 @@ func getToken(r *http.Request) (string, error) {
 	}
 }
 
+// getClusterTokens retrieves the credentials of the user for the remote clusters from the request's context, by
+// cluster name
+func getClusterTokens(r *http.Request) map[string]string {
+	if clusterTokens, ok := r.Context().Value("clusterTokens").(map[string]string); ok {
+		return clusterTokens
+	}
+	return map[string]string{}
+}
+
+// getClusterSelector parses the selector of the clusters, on their labels, of the "clusters" query parameter, i.e.
+// "region=eu-west,environment!=dev". All the clusters are selected without the parameter.
+func getClusterSelector(r *http.Request) (labels.Selector, error) {
+	selector, err := labels.Parse(r.URL.Query().Get("clusters"))
+	if err != nil {
+		return nil, errors.New("invalid selector of the clusters: " + err.Error())
+	}
+	return selector, nil
+}
+
+// getUserIdentity retrieves the identity of the user from the request's context, if known
+func getUserIdentity(r *http.Request) (models.UserIdentity, bool) {
+	identity, ok := r.Context().Value("userIdentity").(models.UserIdentity)
+	return identity, ok
+}
+
+// getFeatureUser returns the name of the authenticated user of a request using a feature which keeps data by user,
+// i.e. the saved views or the preferences. It responds with an error when the feature is disabled or the user is anonymous.
+func getFeatureUser(w http.ResponseWriter, r *http.Request, feature string) (string, bool) {
+	if !config.Get().KialiFeatureFlags.IsEnabled(feature) {
+		RespondWithError(w, http.StatusNotFound, "Feature "+feature+" is not enabled")
+		return "", false
+	}
+	identity, ok := getUserIdentity(r)
+	if !ok || identity.Username == "" {
+		RespondWithError(w, http.StatusForbidden, "Feature "+feature+" requires an authenticated user")
+		return "", false
+	}
+	return identity.Username, true
+}
+
+// getApiTokenScope retrieves the scope of the API token used to authenticate the request, if any
+func getApiTokenScope(r *http.Request) (models.ApiTokenScope, bool) {
+	scope, ok := r.Context().Value("apiTokenScope").(models.ApiTokenScope)
+	return scope, ok
+}
+
 // getBusiness returns the business layer specific to the users's request
 func getBusiness(r *http.Request) (*business.Layer, error) {
 	token, err := getToken(r)
@@ -87,5 +136,19 @@ func getBusiness(r *http.Request) (*business.Layer, error) {
 		return nil, err
 	}
 
-	return business.Get(token)
+	var layer *business.Layer
+	if user, ok := r.Context().Value("impersonatedUser").(models.UserIdentity); ok {
+		layer, err = business.GetImpersonating(token, user)
+	} else {
+		layer, err = business.Get(token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiali may restrict the namespaces of the user beyond what the cluster allows
+	if identity, ok := getUserIdentity(r); ok {
+		layer.Namespace.RestrictToUser(identity)
+	}
+	return layer, nil
 }
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// WizardTrafficShifting is the API handler routing the traffic of a service to its workloads by weight, reverted
+// after a ttl unless confirmed
+func WizardTrafficShifting(w http.ResponseWriter, r *http.Request) {
+	var request models.TrafficShiftingRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Traffic shifting request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	change, err := business.Wizard.ApplyTrafficShifting(namespace, service, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "WIZARD traffic_shifting on Namespace: "+namespace+" Service name: "+service+" TTL: "+request.TTL)
+	RespondWithJSON(w, http.StatusOK, change)
+}
+
+// WizardCanaryPromotion is the API handler raising the weight of the canary subset of a service by a step, when its
+// metrics meet the success criteria
+func WizardCanaryPromotion(w http.ResponseWriter, r *http.Request) {
+	var request models.CanaryPromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Canary promotion request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	promotion, err := business.Wizard.PromoteCanary(namespace, service, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	if promotion.Change != nil {
+		audit(r, fmt.Sprintf("WIZARD canary_promotion on Namespace: %s Service name: %s Subset: %s Weight: %d TTL: %s", namespace, service, request.Subset, promotion.Weight, request.TTL))
+	}
+	RespondWithJSON(w, http.StatusOK, promotion)
+}
+
+// WizardFaultInjection is the API handler injecting delays and aborts in the routes of a service, until their
+// expiration
+func WizardFaultInjection(w http.ResponseWriter, r *http.Request) {
+	var request models.FaultInjectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Fault injection request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	change, err := business.Wizard.ApplyFaultInjection(namespace, service, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "WIZARD fault_injection on Namespace: "+namespace+" Service name: "+service+" TTL: "+request.TTL)
+	RespondWithJSON(w, http.StatusOK, change)
+}
+
+// FaultInjections is the API handler listing the faults injected in the VirtualServices of the mesh
+func FaultInjections(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	faults, err := business.Wizard.ListFaultInjections()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, faults)
+}
+
+// FaultInjectionsRemove is the API handler removing the faults injected in a VirtualService
+func FaultInjectionsRemove(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, object := params["namespace"], params["object"]
+	if err = business.Wizard.RemoveFaultInjections(namespace, object); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "DELETE fault injections on Namespace: "+namespace+" VirtualService name: "+object)
+	RespondWithCode(w, http.StatusOK)
+}
+
+// WizardTrafficMirroring is the API handler mirroring the traffic of a service to a shadow destination
+func WizardTrafficMirroring(w http.ResponseWriter, r *http.Request) {
+	var request models.TrafficMirroringRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Traffic mirroring request with bad json: "+err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	change, err := business.Wizard.ApplyTrafficMirroring(namespace, service, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "WIZARD traffic_mirroring on Namespace: "+namespace+" Service name: "+service+" Mirror: "+request.Host+" "+request.Subset+" TTL: "+request.TTL)
+	RespondWithJSON(w, http.StatusOK, change)
+}
+
+// TrafficMirroringReport is the API handler comparing the responses of the primary and shadow destinations of a
+// service
+func TrafficMirroringReport(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	report, err := business.Wizard.GetTrafficMirroringReport(params["namespace"], params["service"], r.URL.Query().Get("window"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
+
+// TrafficPolicyRecommendation is the API handler recommending the connection pool and outlier detection of a
+// service from its traffic
+func TrafficPolicyRecommendation(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	recommendation, err := business.Wizard.GetTrafficPolicyRecommendation(params["namespace"], params["service"], r.URL.Query().Get("window"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, recommendation)
+}
+
+// WizardChange is the API handler returning the change of a service waiting for its confirmation
+func WizardChange(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	change, err := business.Wizard.GetWizardChange(params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, change)
+}
+
+// WizardConfirm is the API handler keeping the change of a service, cancelling its rollback
+func WizardConfirm(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	change, err := business.Wizard.ConfirmWizardChange(namespace, service)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "WIZARD CONFIRM "+change.Wizard+" on Namespace: "+namespace+" Service name: "+service)
+	RespondWithJSON(w, http.StatusOK, change)
+}
+
+// WizardRollback is the API handler reverting now the change of a service waiting for its confirmation
+func WizardRollback(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace, service := params["namespace"], params["service"]
+	change, err := business.Wizard.RollbackWizardChange(namespace, service)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "WIZARD ROLLBACK "+change.Wizard+" on Namespace: "+namespace+" Service name: "+service)
+	RespondWithJSON(w, http.StatusOK, change)
+}
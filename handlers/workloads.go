@@ -1,10 +1,21 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
 )
 
 // WorkloadList is the API handler to fetch all the workloads to be displayed, related to a single namespace
@@ -26,6 +37,11 @@ func WorkloadList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isCSVRequested(r) {
+		header, rows := workloadListCSV(workloadList)
+		RespondWithCSV(w, "workloads-"+namespace, header, rows)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, workloadList)
 }
 
@@ -85,6 +101,64 @@ func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
+// WorkloadScale is the API handler to set the number of replicas of a Deployment, StatefulSet or Rollout
+func WorkloadScale(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+	workload := params["workload"]
+	workloadType := query.Get("type")
+
+	var scale struct {
+		Replicas *int32 `json:"replicas"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&scale); err != nil || scale.Replicas == nil {
+		RespondWithError(w, http.StatusBadRequest, "Scale request without number of replicas")
+		return
+	}
+
+	result, err := business.Workload.ScaleWorkload(namespace, workload, workloadType, *scale.Replicas)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, fmt.Sprintf("SCALE on Namespace: %s Workload name: %s Type: %s Replicas: %d", namespace, workload, result.Type, result.Replicas))
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// WorkloadRestart is the API handler to restart the pods of a Deployment, StatefulSet or Rollout
+func WorkloadRestart(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+	workload := params["workload"]
+	workloadType := query.Get("type")
+
+	result, err := business.Workload.RestartWorkload(namespace, workload, workloadType)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "RESTART on Namespace: "+namespace+" Workload name: "+workload+" Type: "+result.Type)
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
 // PodDetails is the API handler to fetch all details to be displayed, related to a single pod
 func PodDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -108,6 +182,27 @@ func PodDetails(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, podDetails)
 }
 
+// PodEvict is the API handler to evict a pod, respecting the PodDisruptionBudgets
+func PodEvict(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pods initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	if err := business.Workload.EvictPod(namespace, pod); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	audit(r, "EVICT on Namespace: "+namespace+" Pod name: "+pod)
+	RespondWithJSON(w, http.StatusOK, "Pod "+pod+" evicted")
+}
+
 // PodLogs is the API handler to fetch logs for a single pod container
 func PodLogs(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -122,8 +217,57 @@ func PodLogs(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	pod := vars["pod"]
 
-	// Get log options
-	opts, err := business.Workload.BuildLogOptionsCriteria(
+	opts, ok := logOptions(w, business, queryParams)
+	if !ok {
+		return
+	}
+
+	if queryParams.Get("follow") == "true" {
+		streamPodLogs(w, r, business, namespace, pod, opts)
+		return
+	}
+
+	// Fetch pod logs
+	podLogs, err := business.Workload.GetPodLogs(namespace, pod, opts)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, podLogs)
+}
+
+// WorkloadLogs is the API handler to fetch the logs of all the pods of a workload, merged
+func WorkloadLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workload Logs initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	workload := vars["workload"]
+
+	opts, ok := logOptions(w, business, queryParams)
+	if !ok {
+		return
+	}
+
+	logs, err := business.Workload.GetWorkloadLogs(namespace, workload, opts)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, logs)
+}
+
+// logOptions reads the log options from the query parameters, responding with the error when they are invalid
+func logOptions(w http.ResponseWriter, layer *business.Layer, queryParams url.Values) (*business.LogOptions, bool) {
+	opts, err := layer.Workload.BuildLogOptionsCriteria(
 		queryParams.Get("container"),
 		queryParams.Get("duration"),
 		queryParams.Get("sinceTime"),
@@ -131,15 +275,70 @@ func PodLogs(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		handleErrorResponse(w, err)
+		return nil, false
+	}
+	opts.TraceID = queryParams.Get("traceId")
+	if filter := queryParams.Get("filter"); filter != "" {
+		if opts.Filter, err = regexp.Compile(filter); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid filter: "+err.Error())
+			return nil, false
+		}
+	}
+	if flags := queryParams.Get("responseFlags"); flags != "" {
+		opts.ResponseFlags = strings.Split(flags, ",")
+	}
+	switch opts.Source = queryParams.Get("source"); opts.Source {
+	case "", business.LogSourceKubernetes:
+	case business.LogSourceLoki:
+		if !config.Get().ExternalServices.Loki.Enabled {
+			RespondWithError(w, http.StatusBadRequest, "Loki is not enabled")
+			return nil, false
+		}
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Invalid source: "+opts.Source)
+		return nil, false
+	}
+	return opts, true
+}
+
+// streamPodLogs sends the logs of a pod as server-sent events while they are written, each event being identified by
+// the time of its line, so that a reconnecting client gets the next lines only
+func streamPodLogs(w http.ResponseWriter, r *http.Request, layer *business.Layer, namespace, pod string, opts *business.LogOptions) {
+	if opts.Source == business.LogSourceLoki {
+		RespondWithError(w, http.StatusBadRequest, "The logs read from Loki cannot be followed")
 		return
 	}
 
-	// Fetch pod logs
-	podLogs, err := business.Workload.GetPodLogs(namespace, pod, opts)
-	if err != nil {
-		handleErrorResponse(w, err)
+	var lastEvent time.Time
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		if t, err := time.Parse(time.RFC3339Nano, id); err == nil {
+			lastEvent = t
+			opts.SinceTime = &meta_v1.Time{Time: t}
+			opts.TailLines = nil
+		}
+	}
+
+	events, ok := newSSEWriter(w)
+	if !ok {
+		RespondWithError(w, http.StatusInternalServerError, "Streaming of the logs not supported")
 		return
 	}
+	stopKeepAlive := events.KeepAlive(sseKeepAliveInterval)
+	defer stopKeepAlive()
 
-	RespondWithJSON(w, http.StatusOK, podLogs)
+	send := func(entry business.LogEntry, at time.Time) error {
+		// The lines of the second of the last event are sent again by the API
+		if !at.After(lastEvent) {
+			return nil
+		}
+		return events.Send(at.Format(time.RFC3339Nano), "", entry)
+	}
+
+	// The clients reconnect at the end of the stream, with the Last-Event-ID header
+	ctx, cancel := streamContext(r, streamDuration())
+	defer cancel()
+	if err := layer.Workload.StreamPodLogs(namespace, pod, opts, ctx.Done(), send); err != nil && r.Context().Err() == nil {
+		log.Debugf("Logs stream of pod [%s/%s] failed: %v", namespace, pod, err)
+		events.SendError(err)
+	}
 }
@@ -17,42 +17,93 @@ type ClientInterface interface {
 	GetAppTraces(ns, app string, query models.TracingQuery) (traces *JaegerResponse, err error)
 	GetTraceDetail(traceId string) (*JaegerSingleTrace, error)
 	GetErrorTraces(ns, app string, duration time.Duration) (errorTraces int, err error)
+	GetServices() ([]string, error)
+	SearchTraceQL(ns, app string, query models.TraceQLQuery) (*TraceQLResponse, error)
 }
 
 // Client for Jaeger API.
 type Client struct {
 	ClientInterface
-	client  http.Client
-	baseURL *url.URL
+	client   http.Client
+	baseURL  *url.URL
+	tempoURL *url.URL
 }
 
-func NewClient(token string) (*Client, error) {
+// NewClient creates a client for the tracing API of the configured provider. When the traces of other clusters are
+// stored in their own backends, the client queries the backends of all the clusters.
+func NewClient(token string) (ClientInterface, error) {
 	cfg := config.Get()
 	cfgTracing := cfg.ExternalServices.Tracing
 
 	if !cfgTracing.Enabled {
 		return nil, errors.New("jaeger is not available")
 	} else {
-		auth := cfgTracing.Auth
-		if auth.UseKialiToken {
-			auth.Token = token
-		}
-		u, errParse := url.Parse(cfgTracing.InClusterURL)
-		if !cfg.InCluster {
-			u, errParse = url.Parse(cfgTracing.URL)
-		}
-		if errParse != nil {
-			log.Errorf("Error parse Jaeger URL: %s", errParse)
-			return nil, errParse
-		}
-		timeout := time.Duration(5000 * time.Millisecond)
-		transport, err := httputil.AuthTransport(&auth, &http.Transport{})
+		home, err := newBackendClient(token, cfg.InCluster, config.TracingClusterConfig{
+			Auth:         cfgTracing.Auth,
+			InClusterURL: cfgTracing.InClusterURL,
+			Provider:     cfgTracing.Provider,
+			Tempo:        cfgTracing.Tempo,
+			URL:          cfgTracing.URL,
+		})
 		if err != nil {
 			return nil, err
 		}
-		client := http.Client{Transport: transport, Timeout: timeout}
-		return &Client{client: client, baseURL: u}, nil
+		homeCluster := cfg.KubernetesConfig.ClusterName
+		clusters := map[string]ClientInterface{}
+		for cluster, clusterConfig := range cfgTracing.Clusters {
+			if cluster == homeCluster || (clusterConfig.InClusterURL == "" && clusterConfig.URL == "") {
+				continue
+			}
+			// Backends of remote clusters are usually reached through their external URL
+			if clusterConfig.InClusterURL == "" {
+				clusterConfig.InClusterURL = clusterConfig.URL
+			}
+			// Backends without credentials or provider use the global ones
+			if clusterConfig.Auth.Type == "" {
+				clusterConfig.Auth = cfgTracing.Auth
+			}
+			if clusterConfig.Provider == "" {
+				clusterConfig.Provider = cfgTracing.Provider
+			}
+			if clusters[cluster], err = newBackendClient(token, cfg.InCluster, clusterConfig); err != nil {
+				return nil, err
+			}
+		}
+		if len(clusters) == 0 {
+			return home, nil
+		}
+		return NewMultiClusterClient(homeCluster, home, clusters), nil
+	}
+}
+
+// newBackendClient creates a client for a tracing backend
+func newBackendClient(token string, inCluster bool, cfg config.TracingClusterConfig) (ClientInterface, error) {
+	auth := cfg.Auth
+	if auth.UseKialiToken {
+		auth.Token = token
+	}
+	u, errParse := url.Parse(cfg.InClusterURL)
+	if !inCluster {
+		u, errParse = url.Parse(cfg.URL)
+	}
+	if errParse != nil {
+		log.Errorf("Error parse Jaeger URL: %s", errParse)
+		return nil, errParse
+	}
+	timeout := time.Duration(5000 * time.Millisecond)
+	transport, err := httputil.AuthTransport(&auth, &http.Transport{})
+	if err != nil {
+		return nil, err
 	}
+	client := http.Client{Transport: transport, Timeout: timeout}
+	tempoURL, err := parseTempoURL(inCluster, cfg.Tempo)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Provider == config.TracingProviderOTLP {
+		return &OTLPClient{client: client, baseURL: u, tempoURL: tempoURL}, nil
+	}
+	return &Client{client: client, baseURL: u, tempoURL: tempoURL}, nil
 }
 
 // GetAppTraces fetches traces of an app
@@ -69,3 +120,29 @@ func (in *Client) GetTraceDetail(traceId string) (*JaegerSingleTrace, error) {
 func (in *Client) GetErrorTraces(ns, app string, duration time.Duration) (errorTraces int, err error) {
 	return getErrorTraces(in.client, in.baseURL, ns, app, duration)
 }
+
+// GetServices fetches the names of the services reporting traces
+func (in *Client) GetServices() ([]string, error) {
+	return getServices(in.client, in.baseURL)
+}
+
+// SearchTraceQL searches the traces of an app with a TraceQL query
+func (in *Client) SearchTraceQL(ns, app string, query models.TraceQLQuery) (*TraceQLResponse, error) {
+	return searchTraceQL(in.client, in.tempoURL, ns, app, query)
+}
+
+func parseTempoURL(inCluster bool, cfg config.TempoConfig) (*url.URL, error) {
+	rawURL := cfg.InClusterURL
+	if !inCluster {
+		rawURL = cfg.URL
+	}
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		log.Errorf("Error parse Tempo URL: %s", err)
+		return nil, err
+	}
+	return u, nil
+}
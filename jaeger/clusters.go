@@ -0,0 +1,288 @@
+package jaeger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// ClusterTag is the tag added to the processes of the traces of a multi-cluster client, holding the cluster of
+// the backend the trace comes from
+const ClusterTag = "kiali.cluster"
+
+// clusterClient is the tracing backend of a cluster
+type clusterClient struct {
+	cluster string
+	client  ClientInterface
+}
+
+// MultiClusterClient queries the tracing backends of several clusters. Queries for a cluster (see
+// models.TracingQuery) are sent to the backend of that cluster only, the other queries are sent to all the backends
+// and their results merged. A failure of the backend of the home cluster fails the query; the results of the other
+// backends failing are skipped.
+type MultiClusterClient struct {
+	// The backend of the home cluster comes first
+	clients []clusterClient
+}
+
+// NewMultiClusterClient returns a client of the backend of the home cluster and of the backends of other clusters, by
+// cluster name
+func NewMultiClusterClient(homeCluster string, home ClientInterface, clusters map[string]ClientInterface) *MultiClusterClient {
+	m := &MultiClusterClient{clients: []clusterClient{{cluster: homeCluster, client: home}}}
+	names := make([]string, 0, len(clusters))
+	for cluster := range clusters {
+		names = append(names, cluster)
+	}
+	sort.Strings(names)
+	for _, cluster := range names {
+		m.clients = append(m.clients, clusterClient{cluster: cluster, client: clusters[cluster]})
+	}
+	return m
+}
+
+// clientsOf returns the backend of the given cluster, or all the backends when no cluster is given. A cluster
+// without its own backend has its traces in the backend of the home cluster.
+func (in *MultiClusterClient) clientsOf(cluster string) []clusterClient {
+	if cluster == "" {
+		return in.clients
+	}
+	for _, c := range in.clients {
+		if c.cluster == cluster {
+			return []clusterClient{c}
+		}
+	}
+	return in.clients[:1]
+}
+
+// queryAll runs a query on the given backends concurrently, returning the results of the backends that didn't fail
+// along with the errors of the others
+func queryAll(clients []clusterClient, query func(c clusterClient) (interface{}, error)) ([]interface{}, []error) {
+	results := make([]interface{}, len(clients))
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	wg.Add(len(clients))
+	for i, c := range clients {
+		go func(i int, c clusterClient) {
+			defer wg.Done()
+			results[i], errs[i] = query(c)
+		}(i, c)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// GetAppTraces fetches the traces of an app from the backends of the clusters. The spans of a trace going through
+// several clusters are merged into one trace, and the processes of the traces are tagged with their cluster. As each
+// backend applies the limit of the query to its own traces, the limit is applied again to the most recent merged traces.
+func (in *MultiClusterClient) GetAppTraces(ns, app string, query models.TracingQuery) (*JaegerResponse, error) {
+	clients := in.clientsOf(query.Cluster)
+	results, errs := queryAll(clients, func(c clusterClient) (interface{}, error) {
+		return c.client.GetAppTraces(ns, app, query)
+	})
+	merged := &JaegerResponse{Data: []jaegerModels.Trace{}, Errors: []structuredError{}}
+	index := map[jaegerModels.TraceID]int{}
+	for i, c := range clients {
+		if errs[i] != nil {
+			// A failure of the home cluster, or of the only cluster queried, fails the query
+			if i == 0 {
+				return nil, errs[i]
+			}
+			log.Warningf("Skipping the traces of cluster [%s]: %v", c.cluster, errs[i])
+			merged.Errors = append(merged.Errors, structuredError{Msg: fmt.Sprintf("cluster %s: %v", c.cluster, errs[i])})
+			continue
+		}
+		r, _ := results[i].(*JaegerResponse)
+		if r == nil {
+			continue
+		}
+		merged.JaegerServiceName = r.JaegerServiceName
+		merged.Errors = append(merged.Errors, r.Errors...)
+		for _, trace := range r.Data {
+			trace = tagCluster(trace, c.cluster)
+			if j, found := index[trace.TraceID]; found {
+				merged.Data[j] = mergeTraces(merged.Data[j], trace, c.cluster)
+				continue
+			}
+			index[trace.TraceID] = len(merged.Data)
+			merged.Data = append(merged.Data, trace)
+		}
+	}
+	if len(clients) > 1 {
+		sort.SliceStable(merged.Data, func(i, j int) bool {
+			return traceStartTime(merged.Data[i]) > traceStartTime(merged.Data[j])
+		})
+		if query.Limit > 0 && len(merged.Data) > query.Limit {
+			merged.Data = merged.Data[:query.Limit]
+		}
+	}
+	return merged, nil
+}
+
+// mergeTraces adds the spans of a trace fetched from the backend of another cluster to the trace. The processes of
+// the other cluster are renamed when their IDs are already used, as each backend numbers the processes of its traces.
+func mergeTraces(trace, other jaegerModels.Trace, cluster string) jaegerModels.Trace {
+	processes := make(map[jaegerModels.ProcessID]jaegerModels.Process, len(trace.Processes)+len(other.Processes))
+	for id, process := range trace.Processes {
+		processes[id] = process
+	}
+	renamed := make(map[jaegerModels.ProcessID]jaegerModels.ProcessID, len(other.Processes))
+	for id, process := range other.Processes {
+		newID := id
+		if _, used := processes[id]; used {
+			newID = jaegerModels.ProcessID(fmt.Sprintf("%s-%s", id, cluster))
+		}
+		renamed[id] = newID
+		processes[newID] = process
+	}
+	spanIDs := make(map[jaegerModels.SpanID]bool, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spanIDs[span.SpanID] = true
+	}
+	spans := make([]jaegerModels.Span, 0, len(trace.Spans)+len(other.Spans))
+	spans = append(spans, trace.Spans...)
+	for _, span := range other.Spans {
+		// The spans reported to several backends are kept once
+		if spanIDs[span.SpanID] {
+			continue
+		}
+		spanIDs[span.SpanID] = true
+		if id, found := renamed[span.ProcessID]; found {
+			span.ProcessID = id
+		}
+		spans = append(spans, span)
+	}
+	trace.Processes = processes
+	trace.Spans = spans
+	trace.Warnings = append(append([]string{}, trace.Warnings...), other.Warnings...)
+	return trace
+}
+
+// traceStartTime returns the start time of the earliest span of a trace
+func traceStartTime(trace jaegerModels.Trace) uint64 {
+	var start uint64
+	for i, span := range trace.Spans {
+		if i == 0 || span.StartTime < start {
+			start = span.StartTime
+		}
+	}
+	return start
+}
+
+// GetTraceDetail fetches a trace from the first backend storing it, the backend of the home cluster first
+func (in *MultiClusterClient) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	var firstErr error
+	for _, c := range in.clients {
+		trace, err := c.client.GetTraceDetail(traceID)
+		if err != nil {
+			log.Debugf("Trace [%s] could not be fetched from cluster [%s]: %v", traceID, c.cluster, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if trace != nil && trace.Data.TraceID != "" {
+			trace.Data = tagCluster(trace.Data, c.cluster)
+			return trace, nil
+		}
+	}
+	return nil, firstErr
+}
+
+// GetErrorTraces counts the traces in error of an app in all the backends
+func (in *MultiClusterClient) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	results, errs := queryAll(in.clients, func(c clusterClient) (interface{}, error) {
+		return c.client.GetErrorTraces(ns, app, duration)
+	})
+	if errs[0] != nil {
+		return -1, errs[0]
+	}
+	total := 0
+	for i, c := range in.clients {
+		if errs[i] != nil {
+			log.Warningf("Skipping the error traces of cluster [%s]: %v", c.cluster, errs[i])
+			continue
+		}
+		total += results[i].(int)
+	}
+	return total, nil
+}
+
+// GetServices fetches the names of the services reporting traces to any of the backends
+func (in *MultiClusterClient) GetServices() ([]string, error) {
+	results, errs := queryAll(in.clients, func(c clusterClient) (interface{}, error) {
+		return c.client.GetServices()
+	})
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	seen := map[string]bool{}
+	services := []string{}
+	for i, c := range in.clients {
+		if errs[i] != nil {
+			log.Warningf("Skipping the services of cluster [%s]: %v", c.cluster, errs[i])
+			continue
+		}
+		for _, service := range results[i].([]string) {
+			if !seen[service] {
+				seen[service] = true
+				services = append(services, service)
+			}
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// SearchTraceQL searches the traces of an app in the backends supporting TraceQL. The search fails only when no
+// backend could run it.
+func (in *MultiClusterClient) SearchTraceQL(ns, app string, query models.TraceQLQuery) (*TraceQLResponse, error) {
+	clients := in.clientsOf(query.Cluster)
+	results, errs := queryAll(clients, func(c clusterClient) (interface{}, error) {
+		return c.client.SearchTraceQL(ns, app, query)
+	})
+	var merged *TraceQLResponse
+	for i, c := range clients {
+		if errs[i] != nil {
+			// Invalid queries fail on every backend
+			if traceQLErr, ok := errs[i].(*TraceQLError); ok && traceQLErr.Code < 500 {
+				return nil, errs[i]
+			}
+			log.Debugf("Skipping the TraceQL search of cluster [%s]: %v", c.cluster, errs[i])
+			continue
+		}
+		r := results[i].(*TraceQLResponse)
+		if merged == nil {
+			merged = &TraceQLResponse{Traces: []TraceQLTrace{}, Query: r.Query, JaegerServiceName: r.JaegerServiceName}
+		}
+		for _, trace := range r.Traces {
+			trace.Cluster = c.cluster
+			merged.Traces = append(merged.Traces, trace)
+		}
+	}
+	if merged == nil {
+		return nil, errs[0]
+	}
+	return merged, nil
+}
+
+// tagCluster adds the cluster tag to the processes of a trace
+func tagCluster(trace jaegerModels.Trace, cluster string) jaegerModels.Trace {
+	if cluster == "" {
+		return trace
+	}
+	processes := make(map[jaegerModels.ProcessID]jaegerModels.Process, len(trace.Processes))
+	for id, process := range trace.Processes {
+		tags := make([]jaegerModels.KeyValue, 0, len(process.Tags)+1)
+		tags = append(tags, process.Tags...)
+		process.Tags = append(tags, jaegerModels.KeyValue{Key: ClusterTag, Type: jaegerModels.StringType, Value: cluster})
+		processes[id] = process
+	}
+	trace.Processes = processes
+	return trace
+}
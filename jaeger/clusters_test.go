@@ -0,0 +1,182 @@
+package jaeger
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+type fakeBackend struct {
+	traces   []jaegerModels.Trace
+	services []string
+	err      error
+}
+
+func (f fakeBackend) GetAppTraces(ns, app string, query models.TracingQuery) (*JaegerResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &JaegerResponse{Data: f.traces, JaegerServiceName: app + "." + ns}, nil
+}
+
+func (f fakeBackend) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, trace := range f.traces {
+		if string(trace.TraceID) == traceID {
+			return &JaegerSingleTrace{Data: trace}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f fakeBackend) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	return len(f.traces), f.err
+}
+
+func (f fakeBackend) GetServices() ([]string, error) {
+	return f.services, f.err
+}
+
+func (f fakeBackend) SearchTraceQL(ns, app string, query models.TraceQLQuery) (*TraceQLResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	response := &TraceQLResponse{Traces: []TraceQLTrace{}}
+	for _, trace := range f.traces {
+		response.Traces = append(response.Traces, TraceQLTrace{TraceID: string(trace.TraceID)})
+	}
+	return response, nil
+}
+
+func clusterTrace(id string) jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID:   jaegerModels.TraceID(id),
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{"p1": {ServiceName: "reviews.bookinfo"}},
+	}
+}
+
+func traceCluster(trace jaegerModels.Trace) string {
+	for _, tag := range trace.Processes["p1"].Tags {
+		if tag.Key == ClusterTag {
+			return tag.Value.(string)
+		}
+	}
+	return ""
+}
+
+func TestMultiClusterClientGetAppTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	client := NewMultiClusterClient("east", fakeBackend{traces: []jaegerModels.Trace{clusterTrace("a"), clusterTrace("b")}},
+		map[string]ClientInterface{
+			"west":  fakeBackend{traces: []jaegerModels.Trace{clusterTrace("b"), clusterTrace("c")}},
+			"south": fakeBackend{err: errors.New("unavailable")},
+		})
+
+	r, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{})
+	assert.NoError(err)
+	assert.Equal("reviews.bookinfo", r.JaegerServiceName)
+	assert.Len(r.Data, 3)
+	clusters := map[jaegerModels.TraceID]string{}
+	for _, trace := range r.Data {
+		clusters[trace.TraceID] = traceCluster(trace)
+	}
+	assert.Equal(map[jaegerModels.TraceID]string{"a": "east", "b": "east", "c": "west"}, clusters)
+	assert.Len(r.Errors, 1)
+	assert.Contains(r.Errors[0].Msg, "south")
+
+	// Routed by cluster
+	r, err = client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{Cluster: "west"})
+	assert.NoError(err)
+	assert.Len(r.Data, 2)
+	assert.Equal("west", traceCluster(r.Data[0]))
+	_, err = client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{Cluster: "south"})
+	assert.Error(err)
+
+	// Clusters without their own backend are in the home backend
+	r, err = client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{Cluster: "north"})
+	assert.NoError(err)
+	assert.Len(r.Data, 2)
+	assert.Equal("east", traceCluster(r.Data[0]))
+}
+
+func TestMultiClusterClientHomeFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	client := NewMultiClusterClient("east", fakeBackend{err: errors.New("unavailable")},
+		map[string]ClientInterface{"west": fakeBackend{traces: []jaegerModels.Trace{clusterTrace("c")}, services: []string{"reviews.bookinfo"}}})
+
+	_, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{})
+	assert.Error(err)
+	_, err = client.GetServices()
+	assert.Error(err)
+
+	// Trace details are looked up in every backend
+	trace, err := client.GetTraceDetail("c")
+	assert.NoError(err)
+	assert.Equal("west", traceCluster(trace.Data))
+
+	// TraceQL searches need one backend supporting them
+	search, err := client.SearchTraceQL("bookinfo", "reviews", models.TraceQLQuery{})
+	assert.NoError(err)
+	assert.Len(search.Traces, 1)
+	assert.Equal("west", search.Traces[0].Cluster)
+}
+
+func TestMultiClusterClientMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	client := NewMultiClusterClient("east", fakeBackend{traces: []jaegerModels.Trace{clusterTrace("a")}, services: []string{"reviews.bookinfo", "details.bookinfo"}},
+		map[string]ClientInterface{"west": fakeBackend{traces: []jaegerModels.Trace{clusterTrace("b"), clusterTrace("c")}, services: []string{"ratings.bookinfo", "reviews.bookinfo"}}})
+
+	services, err := client.GetServices()
+	assert.NoError(err)
+	assert.Equal([]string{"details.bookinfo", "ratings.bookinfo", "reviews.bookinfo"}, services)
+
+	errorTraces, err := client.GetErrorTraces("bookinfo", "reviews", time.Minute)
+	assert.NoError(err)
+	assert.Equal(3, errorTraces)
+
+	// Invalid TraceQL queries fail
+	client = NewMultiClusterClient("east", fakeBackend{err: &TraceQLError{Code: http.StatusBadRequest, Message: "invalid"}}, map[string]ClientInterface{"west": fakeBackend{}})
+	_, err = client.SearchTraceQL("bookinfo", "reviews", models.TraceQLQuery{})
+	assert.Error(err)
+}
+
+func TestMultiClusterClientMergeTraceSpans(t *testing.T) {
+	assert := assert.New(t)
+
+	span := func(trace jaegerModels.Trace, id string, start uint64) jaegerModels.Trace {
+		trace.Spans = append(trace.Spans, jaegerModels.Span{TraceID: trace.TraceID, SpanID: jaegerModels.SpanID(id), ProcessID: "p1", StartTime: start})
+		return trace
+	}
+	eastB := span(span(clusterTrace("b"), "b1", 200), "b2", 210)
+	westB := span(span(clusterTrace("b"), "b2", 210), "b3", 220)
+	client := NewMultiClusterClient("east", fakeBackend{traces: []jaegerModels.Trace{span(clusterTrace("a"), "a1", 100), eastB}},
+		map[string]ClientInterface{"west": fakeBackend{traces: []jaegerModels.Trace{westB, span(clusterTrace("c"), "c1", 300)}}})
+
+	r, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{Limit: 2})
+	assert.NoError(err)
+	// The most recent traces are kept
+	assert.Len(r.Data, 2)
+	assert.Equal(jaegerModels.TraceID("c"), r.Data[0].TraceID)
+	trace := r.Data[1]
+	assert.Equal(jaegerModels.TraceID("b"), trace.TraceID)
+	assert.Len(trace.Spans, 3)
+	assert.Equal(jaegerModels.ProcessID("p1"), trace.Spans[1].ProcessID)
+	assert.Equal(jaegerModels.ProcessID("p1-west"), trace.Spans[2].ProcessID)
+	assert.Equal("east", traceCluster(trace))
+	for _, tag := range trace.Processes["p1-west"].Tags {
+		if tag.Key == ClusterTag {
+			assert.Equal("west", tag.Value)
+		}
+	}
+}
@@ -1,30 +1,39 @@
 package jaegertest
 
 import (
-	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"time"
+
 	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/models"
 )
 
 type JaegerClientMock struct {
 	mock.Mock
 }
 
-func (j *JaegerClientMock) GetJaegerServices() (services []string, code int, err error) {
-	args := j.Called()
-	return args.Get(0).([]string), 200, args.Error(1)
-}
-
-func (j *JaegerClientMock) GetTraces(namespace string, service string, rawQuery string) (traces []*jaegerModels.Trace, code int, err error) {
-	args := j.Called(namespace, service, rawQuery)
-	return args.Get(0).([]*jaegerModels.Trace), 200, args.Error(1)
+func (j *JaegerClientMock) GetAppTraces(ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
+	args := j.Called(ns, app, query)
+	return args.Get(0).(*jaeger.JaegerResponse), args.Error(1)
 }
 
-func (j *JaegerClientMock) GetTraceDetail(traceId string) (trace []*jaegerModels.Trace, code int, err error) {
+func (j *JaegerClientMock) GetTraceDetail(traceId string) (*jaeger.JaegerSingleTrace, error) {
 	args := j.Called(traceId)
-	return args.Get(0).([]*jaegerModels.Trace), 200, args.Error(1)
+	return args.Get(0).(*jaeger.JaegerSingleTrace), args.Error(1)
 }
 
-func (j *JaegerClientMock) GetErrorTraces(ns string, srv string) (errorTraces int, err error) {
-	args := j.Called(ns, srv)
+func (j *JaegerClientMock) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	args := j.Called(ns, app, duration)
 	return args.Get(0).(int), args.Error(1)
 }
+
+func (j *JaegerClientMock) GetServices() ([]string, error) {
+	args := j.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (j *JaegerClientMock) SearchTraceQL(ns, app string, query models.TraceQLQuery) (*jaeger.TraceQLResponse, error) {
+	args := j.Called(ns, app, query)
+	return args.Get(0).(*jaeger.TraceQLResponse), args.Error(1)
+}
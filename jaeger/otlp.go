@@ -0,0 +1,399 @@
+package jaeger
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// OTLPClient queries a trace store serving the traces in the OTLP format, through the query API v3 of Jaeger v2
+// (also served by its ClickHouse and Elasticsearch backends and by the OpenTelemetry gateways). The traces are
+// converted to the Jaeger JSON model, so they are handled like the traces of the Jaeger API.
+type OTLPClient struct {
+	client   http.Client
+	baseURL  *url.URL
+	tempoURL *url.URL
+}
+
+// otlpResponse is the response of the query API v3: the traces, or the error
+type otlpResponse struct {
+	Result otlpTracesData `json:"result"`
+	Error  *struct {
+		HTTPCode int    `json:"httpCode"`
+		Message  string `json:"message"`
+	} `json:"error"`
+}
+
+type otlpTracesData struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	// Before OTLP 0.19
+	InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId"`
+	Name              string         `json:"name"`
+	Kind              interface{}    `json:"kind"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64     `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Events            []struct {
+		TimeUnixNano otlpUint64     `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes"`
+	} `json:"events"`
+	Links []struct {
+		TraceID string `json:"traceId"`
+		SpanID  string `json:"spanId"`
+	} `json:"links"`
+	Status struct {
+		Code    interface{} `json:"code"`
+		Message string      `json:"message"`
+	} `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string                     `json:"key"`
+	Value map[string]json.RawMessage `json:"value"`
+}
+
+// otlpUint64 is a 64 bits integer, encoded as a string or as a number
+type otlpUint64 uint64
+
+func (n *otlpUint64) UnmarshalJSON(data []byte) error {
+	parsed, err := strconv.ParseUint(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = otlpUint64(parsed)
+	return nil
+}
+
+// OTLP enums are encoded as their number or as their name
+var otlpSpanKinds = map[string]string{
+	"1": "internal", "SPAN_KIND_INTERNAL": "internal",
+	"2": "server", "SPAN_KIND_SERVER": "server",
+	"3": "client", "SPAN_KIND_CLIENT": "client",
+	"4": "producer", "SPAN_KIND_PRODUCER": "producer",
+	"5": "consumer", "SPAN_KIND_CONSUMER": "consumer",
+}
+
+func otlpEnum(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.Itoa(int(v))
+	case string:
+		return v
+	}
+	return ""
+}
+
+func isOTLPError(code interface{}) bool {
+	c := otlpEnum(code)
+	return c == "2" || c == "STATUS_CODE_ERROR"
+}
+
+// GetAppTraces fetches traces of an app
+func (in *OTLPClient) GetAppTraces(ns, app string, query models.TracingQuery) (*JaegerResponse, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/v3/traces")
+	jaegerServiceName := buildJaegerServiceName(ns, app)
+	values, err := prepareOTLPQuery(jaegerServiceName, query)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = values.Encode()
+	log.Debugf("Prepared OTLP query: %v", &u)
+	traces, err := in.queryTraces(&u)
+	if err != nil {
+		return &JaegerResponse{}, err
+	}
+	return &JaegerResponse{Data: traces, JaegerServiceName: jaegerServiceName}, nil
+}
+
+// GetTraceDetail fetches a specific trace from its ID
+func (in *OTLPClient) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/v3/traces", traceID)
+	traces, err := in.queryTraces(&u)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		return nil, nil
+	}
+	return &JaegerSingleTrace{Data: traces[0]}, nil
+}
+
+// GetErrorTraces fetches number of traces in error for the given app. Errors are flagged by the status of the spans
+// in OTLP, not by a tag, so the traces are counted after their conversion.
+func (in *OTLPClient) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	now := time.Now()
+	query := models.TracingQuery{
+		StartMicros: strconv.FormatInt(now.Add(-duration).UnixNano()/1000, 10),
+		EndMicros:   strconv.FormatInt(now.UnixNano()/1000, 10),
+	}
+	response, err := in.GetAppTraces(ns, app, query)
+	if err != nil {
+		return -1, err
+	}
+	errorTraces := 0
+	for _, trace := range response.Data {
+		for _, span := range trace.Spans {
+			if hasErrorTag(span) {
+				errorTraces++
+				break
+			}
+		}
+	}
+	return errorTraces, nil
+}
+
+// GetServices fetches the names of the services reporting traces
+func (in *OTLPClient) GetServices() ([]string, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/v3/services")
+	resp, code, reqError := makeRequest(in.client, u.String(), nil)
+	if reqError != nil {
+		log.Errorf("OTLP query error: %s [code: %d, URL: %v]", reqError, code, &u)
+		return nil, reqError
+	}
+	var services struct {
+		Services []string `json:"services"`
+	}
+	if err := json.Unmarshal(resp, &services); err != nil {
+		log.Errorf("Error unmarshalling OTLP response: %s [code: %d, URL: %v]", err, code, &u)
+		return nil, err
+	}
+	return services.Services, nil
+}
+
+// SearchTraceQL searches the traces of an app with a TraceQL query, when Tempo is configured along with the store
+func (in *OTLPClient) SearchTraceQL(ns, app string, query models.TraceQLQuery) (*TraceQLResponse, error) {
+	return searchTraceQL(in.client, in.tempoURL, ns, app, query)
+}
+
+func hasErrorTag(span jaegerModels.Span) bool {
+	for _, tag := range span.Tags {
+		if tag.Key == "error" && tag.Value == true {
+			return true
+		}
+	}
+	return false
+}
+
+func prepareOTLPQuery(serviceName string, query models.TracingQuery) (url.Values, error) {
+	values := url.Values{}
+	values.Set("query.service_name", serviceName)
+	for param, micros := range map[string]string{"query.start_time_min": query.StartMicros, "query.start_time_max": query.EndMicros} {
+		if micros == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(micros, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time [%s]: %v", micros, err)
+		}
+		values.Set(param, time.Unix(0, parsed*1000).UTC().Format(time.RFC3339Nano))
+	}
+	// The v3 API requires both bounds
+	if values.Get("query.start_time_max") == "" {
+		values.Set("query.start_time_max", time.Now().UTC().Format(time.RFC3339Nano))
+	}
+	if query.MinDuration != "" {
+		values.Set("query.duration_min", query.MinDuration)
+	}
+	if query.Tags != "" {
+		values.Set("query.attributes", query.Tags)
+	}
+	if query.Limit > 0 {
+		values.Set("query.num_traces", strconv.Itoa(query.Limit))
+	}
+	return values, nil
+}
+
+func (in *OTLPClient) queryTraces(u *url.URL) ([]jaegerModels.Trace, error) {
+	resp, code, reqError := makeRequest(in.client, u.String(), nil)
+	if reqError != nil {
+		log.Errorf("OTLP query error: %s [code: %d, URL: %v]", reqError, code, u)
+		return nil, reqError
+	}
+	// No trace matching the query
+	if code == http.StatusNotFound {
+		return []jaegerModels.Trace{}, nil
+	}
+	var response otlpResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		log.Errorf("Error unmarshalling OTLP response: %s [code: %d, URL: %v]", err, code, u)
+		return nil, err
+	}
+	if response.Error != nil {
+		if response.Error.HTTPCode == http.StatusNotFound {
+			return []jaegerModels.Trace{}, nil
+		}
+		return nil, fmt.Errorf("OTLP query error [code: %d]: %s", response.Error.HTTPCode, response.Error.Message)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("OTLP query error [code: %d]", code)
+	}
+	return convertOTLPTraces(response.Result), nil
+}
+
+// convertOTLPTraces converts the spans of OTLP into the traces of the Jaeger model, in the order of the spans
+func convertOTLPTraces(data otlpTracesData) []jaegerModels.Trace {
+	traces := []jaegerModels.Trace{}
+	index := map[jaegerModels.TraceID]int{}
+	for _, resourceSpans := range data.ResourceSpans {
+		process := jaegerModels.Process{Tags: []jaegerModels.KeyValue{}}
+		for _, attribute := range resourceSpans.Resource.Attributes {
+			kv := convertOTLPKeyValue(attribute)
+			if kv.Key == "service.name" {
+				process.ServiceName, _ = kv.Value.(string)
+			} else {
+				process.Tags = append(process.Tags, kv)
+			}
+		}
+		// IDs of the process in the traces of the spans
+		processIDs := map[int]jaegerModels.ProcessID{}
+		for _, scopeSpans := range append(resourceSpans.ScopeSpans, resourceSpans.InstrumentationLibrarySpans...) {
+			for _, otlpSpan := range scopeSpans.Spans {
+				span := convertOTLPSpan(otlpSpan, scopeSpans.Scope.Name)
+				i, ok := index[span.TraceID]
+				if !ok {
+					i = len(traces)
+					index[span.TraceID] = i
+					traces = append(traces, jaegerModels.Trace{
+						TraceID:   span.TraceID,
+						Spans:     []jaegerModels.Span{},
+						Processes: map[jaegerModels.ProcessID]jaegerModels.Process{},
+					})
+				}
+				trace := &traces[i]
+				if _, ok := processIDs[i]; !ok {
+					processIDs[i] = jaegerModels.ProcessID(fmt.Sprintf("p%d", len(trace.Processes)+1))
+					trace.Processes[processIDs[i]] = process
+				}
+				span.ProcessID = processIDs[i]
+				trace.Spans = append(trace.Spans, span)
+			}
+		}
+	}
+	return traces
+}
+
+func convertOTLPSpan(s otlpSpan, scope string) jaegerModels.Span {
+	traceID := jaegerModels.TraceID(otlpID(s.TraceID))
+	span := jaegerModels.Span{
+		TraceID:       traceID,
+		SpanID:        jaegerModels.SpanID(otlpID(s.SpanID)),
+		OperationName: s.Name,
+		References:    []jaegerModels.Reference{},
+		StartTime:     uint64(s.StartTimeUnixNano) / 1000,
+		Tags:          []jaegerModels.KeyValue{},
+		Logs:          []jaegerModels.Log{},
+	}
+	if s.EndTimeUnixNano > s.StartTimeUnixNano {
+		span.Duration = uint64(s.EndTimeUnixNano-s.StartTimeUnixNano) / 1000
+	}
+	if s.ParentSpanID != "" {
+		span.References = append(span.References, jaegerModels.Reference{RefType: jaegerModels.ChildOf, TraceID: traceID, SpanID: jaegerModels.SpanID(otlpID(s.ParentSpanID))})
+	}
+	for _, link := range s.Links {
+		span.References = append(span.References, jaegerModels.Reference{RefType: jaegerModels.FollowsFrom, TraceID: jaegerModels.TraceID(otlpID(link.TraceID)), SpanID: jaegerModels.SpanID(otlpID(link.SpanID))})
+	}
+	for _, attribute := range s.Attributes {
+		span.Tags = append(span.Tags, convertOTLPKeyValue(attribute))
+	}
+	// Tags set by the translation of OTLP to Jaeger of the OpenTelemetry collector
+	if kind, ok := otlpSpanKinds[otlpEnum(s.Kind)]; ok {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: "span.kind", Type: jaegerModels.StringType, Value: kind})
+	}
+	if scope != "" {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: "otel.library.name", Type: jaegerModels.StringType, Value: scope})
+	}
+	if isOTLPError(s.Status.Code) {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: "error", Type: jaegerModels.BoolType, Value: true})
+		if s.Status.Message != "" {
+			span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: "otel.status_description", Type: jaegerModels.StringType, Value: s.Status.Message})
+		}
+	}
+	for _, event := range s.Events {
+		fields := []jaegerModels.KeyValue{{Key: "event", Type: jaegerModels.StringType, Value: event.Name}}
+		for _, attribute := range event.Attributes {
+			fields = append(fields, convertOTLPKeyValue(attribute))
+		}
+		span.Logs = append(span.Logs, jaegerModels.Log{Timestamp: uint64(event.TimeUnixNano) / 1000, Fields: fields})
+	}
+	return span
+}
+
+func convertOTLPKeyValue(kv otlpKeyValue) jaegerModels.KeyValue {
+	if raw, ok := kv.Value["stringValue"]; ok {
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.StringType, Value: s}
+		}
+	}
+	if raw, ok := kv.Value["boolValue"]; ok {
+		var b bool
+		if json.Unmarshal(raw, &b) == nil {
+			return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.BoolType, Value: b}
+		}
+	}
+	if raw, ok := kv.Value["intValue"]; ok {
+		// int64 values are encoded as strings
+		if i, err := strconv.ParseInt(strings.Trim(string(raw), `"`), 10, 64); err == nil {
+			return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.Int64Type, Value: i}
+		}
+	}
+	if raw, ok := kv.Value["doubleValue"]; ok {
+		if f, err := strconv.ParseFloat(strings.Trim(string(raw), `"`), 64); err == nil {
+			return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.Float64Type, Value: f}
+		}
+	}
+	// Arrays, maps and bytes are kept as their JSON
+	for _, raw := range kv.Value {
+		return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.StringType, Value: string(raw)}
+	}
+	return jaegerModels.KeyValue{Key: kv.Key, Type: jaegerModels.StringType, Value: ""}
+}
+
+// otlpID returns the hexadecimal form of a trace or span ID, encoded in hexadecimal by the OTLP JSON encoding but in
+// base64 by the encoding of protobuf
+func otlpID(id string) string {
+	if _, err := hex.DecodeString(id); err == nil {
+		return strings.ToLower(id)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(id); err == nil {
+		return hex.EncodeToString(decoded)
+	}
+	return id
+}
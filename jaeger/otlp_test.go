@@ -0,0 +1,147 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+const otlpTraces = `{"result":{"resourceSpans":[{
+  "resource":{"attributes":[{"key":"service.name","value":{"stringValue":"reviews.bookinfo"}},{"key":"k8s.pod.name","value":{"stringValue":"reviews-v1-545db77b95-wnvc9"}}]},
+  "scopeSpans":[{"scope":{"name":"envoy"},"spans":[{
+    "traceId":"5b8efff798038103d269b633813fc60c","spanId":"eee19b7ec3c1b174","parentSpanId":"eee19b7ec3c1b173",
+    "name":"reviews.bookinfo.svc.cluster.local:9080/*","kind":"SPAN_KIND_SERVER",
+    "startTimeUnixNano":"1544712660000000000","endTimeUnixNano":"1544712661000000000",
+    "attributes":[{"key":"http.status_code","value":{"intValue":"503"}},{"key":"upstream_cluster","value":{"stringValue":"inbound|9080||"}}],
+    "events":[{"timeUnixNano":"1544712660500000000","name":"retry","attributes":[{"key":"attempt","value":{"intValue":2}}]}],
+    "status":{"code":2,"message":"upstream connect error"}
+  }]}]},{
+  "resource":{"attributes":[{"key":"service.name","value":{"stringValue":"ratings.bookinfo"}}]},
+  "scopeSpans":[{"spans":[{
+    "traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXU=","parentSpanId":"7uGbfsPBsXQ=",
+    "name":"ratings.bookinfo.svc.cluster.local:9080/*","kind":3,
+    "startTimeUnixNano":1544712660100000000,"endTimeUnixNano":1544712660300000000,
+    "attributes":[{"key":"ratio","value":{"doubleValue":0.5}},{"key":"sampled","value":{"boolValue":true}}],
+    "links":[{"traceId":"1b8efff798038103d269b633813fc60c","spanId":"aee19b7ec3c1b174"}],
+    "status":{}
+  },{
+    "traceId":"0000000000000000d269b633813fc60d","spanId":"fee19b7ec3c1b174",
+    "name":"ratings.bookinfo.svc.cluster.local:9080/*","kind":2,
+    "startTimeUnixNano":"1544712670000000000","endTimeUnixNano":"1544712670100000000"
+  }]}]}
+]}}`
+
+func TestConvertOTLPTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	var response otlpResponse
+	assert.NoError(json.Unmarshal([]byte(otlpTraces), &response))
+	traces := convertOTLPTraces(response.Result)
+
+	assert.Len(traces, 2)
+	trace := traces[0]
+	assert.Equal(jaegerModels.TraceID("5b8efff798038103d269b633813fc60c"), trace.TraceID)
+	assert.Len(trace.Spans, 2)
+	assert.Len(trace.Processes, 2)
+
+	reviews := trace.Spans[0]
+	assert.Equal(jaegerModels.SpanID("eee19b7ec3c1b174"), reviews.SpanID)
+	assert.Equal("reviews.bookinfo.svc.cluster.local:9080/*", reviews.OperationName)
+	assert.Equal(uint64(1544712660000000), reviews.StartTime)
+	assert.Equal(uint64(1000000), reviews.Duration)
+	assert.Equal([]jaegerModels.Reference{{RefType: jaegerModels.ChildOf, TraceID: trace.TraceID, SpanID: "eee19b7ec3c1b173"}}, reviews.References)
+	assert.Equal([]jaegerModels.KeyValue{
+		{Key: "http.status_code", Type: jaegerModels.Int64Type, Value: int64(503)},
+		{Key: "upstream_cluster", Type: jaegerModels.StringType, Value: "inbound|9080||"},
+		{Key: "span.kind", Type: jaegerModels.StringType, Value: "server"},
+		{Key: "otel.library.name", Type: jaegerModels.StringType, Value: "envoy"},
+		{Key: "error", Type: jaegerModels.BoolType, Value: true},
+		{Key: "otel.status_description", Type: jaegerModels.StringType, Value: "upstream connect error"},
+	}, reviews.Tags)
+	assert.Equal([]jaegerModels.Log{{Timestamp: 1544712660500000, Fields: []jaegerModels.KeyValue{
+		{Key: "event", Type: jaegerModels.StringType, Value: "retry"},
+		{Key: "attempt", Type: jaegerModels.Int64Type, Value: int64(2)},
+	}}}, reviews.Logs)
+	assert.Equal(jaegerModels.Process{
+		ServiceName: "reviews.bookinfo",
+		Tags:        []jaegerModels.KeyValue{{Key: "k8s.pod.name", Type: jaegerModels.StringType, Value: "reviews-v1-545db77b95-wnvc9"}},
+	}, trace.Processes[reviews.ProcessID])
+
+	// IDs encoded in base64 by protobuf
+	ratings := trace.Spans[1]
+	assert.Equal(jaegerModels.SpanID("eee19b7ec3c1b175"), ratings.SpanID)
+	assert.Equal(uint64(200000), ratings.Duration)
+	assert.Equal(jaegerModels.FollowsFrom, ratings.References[1].RefType)
+	assert.Equal([]jaegerModels.KeyValue{
+		{Key: "ratio", Type: jaegerModels.Float64Type, Value: 0.5},
+		{Key: "sampled", Type: jaegerModels.BoolType, Value: true},
+		{Key: "span.kind", Type: jaegerModels.StringType, Value: "client"},
+	}, ratings.Tags)
+	assert.Equal("ratings.bookinfo", trace.Processes[ratings.ProcessID].ServiceName)
+	assert.False(hasErrorTag(ratings))
+
+	assert.Equal(jaegerModels.TraceID("0000000000000000d269b633813fc60d"), traces[1].TraceID)
+	assert.Equal("ratings.bookinfo", traces[1].Processes[traces[1].Spans[0].ProcessID].ServiceName)
+}
+
+func TestOTLPClient(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jaeger/api/v3/traces":
+			received = r.URL.Query()
+			_, _ = w.Write([]byte(otlpTraces))
+		case "/jaeger/api/v3/traces/5b8efff798038103d269b633813fc60c":
+			_, _ = w.Write([]byte(otlpTraces))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"httpCode":404,"message":"trace not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/jaeger")
+	client := OTLPClient{client: http.Client{}, baseURL: baseURL}
+
+	response, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{
+		StartMicros: "1544712600000000",
+		EndMicros:   "1544712700000000",
+		Tags:        `{"http.status_code":"503"}`,
+		MinDuration: "100ms",
+		Limit:       20,
+	})
+	assert.NoError(err)
+	assert.Equal("reviews.bookinfo", response.JaegerServiceName)
+	assert.Len(response.Data, 2)
+	assert.Equal("reviews.bookinfo", received.Get("query.service_name"))
+	assert.Equal("2018-12-13T14:50:00Z", received.Get("query.start_time_min"))
+	assert.Equal("2018-12-13T14:51:40Z", received.Get("query.start_time_max"))
+	assert.Equal("100ms", received.Get("query.duration_min"))
+	assert.Equal(`{"http.status_code":"503"}`, received.Get("query.attributes"))
+	assert.Equal("20", received.Get("query.num_traces"))
+
+	errorTraces, err := client.GetErrorTraces("bookinfo", "reviews", time.Hour)
+	assert.NoError(err)
+	assert.Equal(1, errorTraces)
+
+	trace, err := client.GetTraceDetail("5b8efff798038103d269b633813fc60c")
+	assert.NoError(err)
+	assert.Equal(jaegerModels.TraceID("5b8efff798038103d269b633813fc60c"), trace.Data.TraceID)
+
+	trace, err = client.GetTraceDetail("0123")
+	assert.NoError(err)
+	assert.Nil(trace)
+}
@@ -0,0 +1,257 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// TraceQLResponse is the result of a TraceQL search: the matching traces, with the spans matching the query
+type TraceQLResponse struct {
+	Traces            []TraceQLTrace `json:"traces"`
+	Query             string         `json:"query"`
+	JaegerServiceName string         `json:"jaegerServiceName"`
+}
+
+// TraceQLTrace is the summary of a trace matching a TraceQL search
+type TraceQLTrace struct {
+	TraceID           string           `json:"traceID"`
+	RootServiceName   string           `json:"rootServiceName"`
+	RootTraceName     string           `json:"rootTraceName"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	DurationMs        int64            `json:"durationMs"`
+	SpanSets          []TraceQLSpanSet `json:"spanSets"`
+	// Older versions of Tempo return a single spanset
+	SpanSet *TraceQLSpanSet `json:"spanSet,omitempty"`
+	// Cluster of the backend the trace comes from, when the traces of several clusters are searched
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// TraceQLSpanSet holds the spans of a trace matching a spanset filter
+type TraceQLSpanSet struct {
+	Spans   []TraceQLSpan `json:"spans"`
+	Matched int           `json:"matched"`
+}
+
+// TraceQLSpan is a span matching a TraceQL search, with the attributes selected by the query
+type TraceQLSpan struct {
+	SpanID            string             `json:"spanID"`
+	Name              string             `json:"name"`
+	StartTimeUnixNano string             `json:"startTimeUnixNano"`
+	DurationNanos     string             `json:"durationNanos"`
+	Attributes        []TraceQLAttribute `json:"attributes,omitempty"`
+}
+
+// TraceQLAttribute is an OTLP attribute
+type TraceQLAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// TraceQLError is an error returned by Tempo; a bad request usually means the query is invalid
+type TraceQLError struct {
+	Code    int
+	Message string
+}
+
+func (e *TraceQLError) Error() string {
+	return fmt.Sprintf("TraceQL search error [code: %d]: %s", e.Code, e.Message)
+}
+
+var traceQLAttributeKey = regexp.MustCompile(`^(\.|span\.|resource\.)?[A-Za-z_][\w.\-]*$`)
+
+// Intrinsic fields of the spans accepted as attribute filters, with their allowed values for the enums
+var traceQLIntrinsics = map[string][]string{
+	"name":   nil,
+	"status": {"error", "ok", "unset"},
+	"kind":   {"client", "consumer", "internal", "producer", "server", "unspecified"},
+}
+
+// BuildTraceQL returns the TraceQL query searching the traces of a service: a span of the service must match the
+// duration and attribute filters, and the trace must match the spanset filters of the query, if any. The query is
+// checked to be a well-formed expression, so it cannot widen the search beyond the traces of the service.
+func BuildTraceQL(serviceName string, q models.TraceQLQuery) (string, error) {
+	conditions := []string{"resource.service.name = " + traceQLString(serviceName)}
+	if q.MinDuration > 0 {
+		conditions = append(conditions, "duration >= "+traceQLDuration(q.MinDuration))
+	}
+	if q.MaxDuration > 0 {
+		conditions = append(conditions, "duration <= "+traceQLDuration(q.MaxDuration))
+	}
+	keys := make([]string, 0, len(q.Attributes))
+	for key := range q.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		condition, err := traceQLAttributeCondition(key, q.Attributes[key])
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+	query := "{ " + strings.Join(conditions, " && ") + " }"
+
+	filters := strings.TrimSpace(q.Query)
+	if filters == "" {
+		return query, nil
+	}
+	if err := checkTraceQLExpression(filters); err != nil {
+		return "", err
+	}
+	return query + " && (" + filters + ")", nil
+}
+
+func traceQLAttributeCondition(key, value string) (string, error) {
+	if allowed, intrinsic := traceQLIntrinsics[key]; intrinsic {
+		if allowed == nil {
+			return key + " = " + traceQLString(value), nil
+		}
+		for _, v := range allowed {
+			if v == value {
+				return key + " = " + value, nil
+			}
+		}
+		return "", fmt.Errorf("invalid value [%s] of %s, expected one of %v", value, key, allowed)
+	}
+	if !traceQLAttributeKey.MatchString(key) {
+		return "", fmt.Errorf("invalid attribute name [%s]", key)
+	}
+	if !strings.HasPrefix(key, ".") && !strings.HasPrefix(key, "span.") && !strings.HasPrefix(key, "resource.") {
+		// Unscoped attributes match span and resource attributes
+		key = "." + key
+	}
+	// Typed values are compared as such, i.e. http.status_code is an integer
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return key + " = " + value, nil
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return key + " = " + value, nil
+	}
+	if value == "true" || value == "false" {
+		return key + " = " + value, nil
+	}
+	return key + " = " + traceQLString(value), nil
+}
+
+func traceQLString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func traceQLDuration(d time.Duration) string {
+	if d%time.Millisecond == 0 {
+		return strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+	}
+	return strconv.FormatInt(int64(d/time.Microsecond), 10) + "us"
+}
+
+// checkTraceQLExpression checks the braces and parentheses of an expression are balanced, outside of the strings
+// (quoted, or raw between backticks)
+func checkTraceQLExpression(expr string) error {
+	stack := []rune{}
+	var quote rune
+	escaped := false
+	for _, c := range expr {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\' && quote == '"':
+				escaped = true
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '`':
+			quote = c
+		case '{', '(':
+			stack = append(stack, c)
+		case '}', ')':
+			open := '{'
+			if c == ')' {
+				open = '('
+			}
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return fmt.Errorf("invalid TraceQL query, unexpected '%c'", c)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if quote != 0 {
+		return errors.New("invalid TraceQL query, unterminated string")
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("invalid TraceQL query, unclosed '%c'", stack[len(stack)-1])
+	}
+	return nil
+}
+
+func searchTraceQL(client http.Client, tempoURL *url.URL, namespace, app string, q models.TraceQLQuery) (*TraceQLResponse, error) {
+	if tempoURL == nil {
+		return nil, errors.New("TraceQL search is not available, the Tempo URL is not configured")
+	}
+	jaegerServiceName := buildJaegerServiceName(namespace, app)
+	query, err := BuildTraceQL(jaegerServiceName, q)
+	if err != nil {
+		return nil, &TraceQLError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	u := *tempoURL
+	u.Path = path.Join(u.Path, "/api/search")
+	values := url.Values{}
+	values.Set("q", query)
+	if !q.Start.IsZero() {
+		values.Set("start", strconv.FormatInt(q.Start.Unix(), 10))
+	}
+	if !q.End.IsZero() {
+		values.Set("end", strconv.FormatInt(q.End.Unix(), 10))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.SpansPerSpanSet > 0 {
+		values.Set("spss", strconv.Itoa(q.SpansPerSpanSet))
+	}
+	u.RawQuery = values.Encode()
+	log.Debugf("Prepared TraceQL search: %v", &u)
+
+	resp, code, reqError := makeRequest(client, u.String(), nil)
+	if reqError != nil {
+		log.Errorf("TraceQL search error: %s [code: %d, URL: %v]", reqError, code, &u)
+		return nil, reqError
+	}
+	if code != http.StatusOK {
+		return nil, &TraceQLError{Code: code, Message: strings.TrimSpace(string(resp))}
+	}
+	response := TraceQLResponse{}
+	if err := json.Unmarshal(resp, &response); err != nil {
+		log.Errorf("Error unmarshalling TraceQL response: %s [URL: %v]", err, &u)
+		return nil, err
+	}
+	for i := range response.Traces {
+		trace := &response.Traces[i]
+		if len(trace.SpanSets) == 0 && trace.SpanSet != nil {
+			trace.SpanSets = []TraceQLSpanSet{*trace.SpanSet}
+		}
+		trace.SpanSet = nil
+	}
+	if response.Traces == nil {
+		response.Traces = []TraceQLTrace{}
+	}
+	response.Query = query
+	response.JaegerServiceName = jaegerServiceName
+	return &response, nil
+}
@@ -0,0 +1,127 @@
+package jaeger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestBuildTraceQL(t *testing.T) {
+	assert := assert.New(t)
+
+	query, err := BuildTraceQL("reviews.bookinfo", models.TraceQLQuery{
+		MinDuration: 100 * time.Millisecond,
+		MaxDuration: 1500 * time.Microsecond,
+		Attributes: map[string]string{
+			"http.status_code":  "503",
+			"span.http.method":  "POST",
+			"status":            "error",
+			"resource.version":  `v"1"`,
+			"upstream_cluster":  "outbound|9080||ratings",
+			"name":              "reviews:9080/*",
+			"resource.canary":   "true",
+			".response_flags":   "UF",
+			"http.request.size": "1.5",
+		},
+		Query: `{ span.http.url =~ ".*/ratings/.*" } >> { status = error }`,
+	})
+	assert.NoError(err)
+	assert.Equal(`{ resource.service.name = "reviews.bookinfo" && duration >= 100ms && duration <= 1500us`+
+		` && .response_flags = "UF" && .http.request.size = 1.5 && .http.status_code = 503 && name = "reviews:9080/*"`+
+		` && resource.canary = true && resource.version = "v\"1\"" && span.http.method = "POST" && status = error`+
+		` && .upstream_cluster = "outbound|9080||ratings" }`+
+		` && ({ span.http.url =~ ".*/ratings/.*" } >> { status = error })`, query)
+
+	query, err = BuildTraceQL("reviews", models.TraceQLQuery{})
+	assert.NoError(err)
+	assert.Equal(`{ resource.service.name = "reviews" }`, query)
+}
+
+func TestBuildTraceQLRejectsInvalidFilters(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, q := range []models.TraceQLQuery{
+		{Attributes: map[string]string{"http.method = \"GET\" } || { true": "x"}},
+		{Attributes: map[string]string{"status": "failed"}},
+		// Escaping the parentheses would match the traces of any service
+		{Query: `{ true }) || ({ true }`},
+		{Query: "{ .a = `\"` }) || ({ .b = `\"` }"},
+		{Query: `{ .a = "x }`},
+		{Query: `{ .a = "x" `},
+	} {
+		_, err := BuildTraceQL("reviews", q)
+		assert.Error(err, "%v", q)
+	}
+
+	// Parentheses and braces within strings are not part of the expression
+	_, err := BuildTraceQL("reviews", models.TraceQLQuery{Query: `{ .a = "})\"(" } && { .b = ` + "`)`" + ` }`})
+	assert.NoError(err)
+}
+
+func TestSearchTraceQL(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/tempo/api/search", r.URL.Path)
+		received = r.URL.Query()
+		if received.Get("limit") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid TraceQL query: parse error at line 1, col 40\n"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"traces":[{"traceID":"2f3e0cee77ae5dc9","rootServiceName":"productpage.bookinfo","rootTraceName":"productpage:9080/*",` +
+			`"startTimeUnixNano":"1684778327699392724","durationMs":557,"spanSet":{"spans":[{"spanID":"563d623c76514f8e",` +
+			`"startTimeUnixNano":"1684778327735077898","durationNanos":"446979497","attributes":[{"key":"http.status_code","value":{"intValue":"503"}}]}],"matched":1}}],` +
+			`"metrics":{"inspectedTraces":22,"inspectedBytes":"83720","completedJobs":1}}`))
+	}))
+	defer server.Close()
+
+	tempoURL, _ := url.Parse(server.URL + "/tempo")
+	start := time.Unix(1684778000, 0)
+	response, err := searchTraceQL(http.Client{}, tempoURL, "bookinfo", "reviews", models.TraceQLQuery{
+		Start:           start,
+		End:             start.Add(time.Hour),
+		Limit:           20,
+		SpansPerSpanSet: 3,
+		Attributes:      map[string]string{"http.status_code": "503"},
+	})
+	assert.NoError(err)
+	assert.Equal(`{ resource.service.name = "reviews.bookinfo" && .http.status_code = 503 }`, received.Get("q"))
+	assert.Equal("1684778000", received.Get("start"))
+	assert.Equal("1684781600", received.Get("end"))
+	assert.Equal("20", received.Get("limit"))
+	assert.Equal("3", received.Get("spss"))
+
+	assert.Equal("reviews.bookinfo", response.JaegerServiceName)
+	assert.Equal(received.Get("q"), response.Query)
+	assert.Len(response.Traces, 1)
+	trace := response.Traces[0]
+	assert.Equal("2f3e0cee77ae5dc9", trace.TraceID)
+	assert.Equal(int64(557), trace.DurationMs)
+	assert.Nil(trace.SpanSet)
+	assert.Len(trace.SpanSets, 1)
+	assert.Equal("563d623c76514f8e", trace.SpanSets[0].Spans[0].SpanID)
+	assert.Equal("503", trace.SpanSets[0].Spans[0].Attributes[0].Value["intValue"])
+
+	// Query errors of Tempo are bad requests
+	_, err = searchTraceQL(http.Client{}, tempoURL, "bookinfo", "reviews", models.TraceQLQuery{})
+	assert.Error(err)
+	traceQLErr, ok := err.(*TraceQLError)
+	assert.True(ok)
+	assert.Equal(http.StatusBadRequest, traceQLErr.Code)
+	assert.Equal("invalid TraceQL query: parse error at line 1, col 40", traceQLErr.Message)
+
+	// Tempo is optional
+	_, err = searchTraceQL(http.Client{}, nil, "bookinfo", "reviews", models.TraceQLQuery{})
+	assert.Error(err)
+}
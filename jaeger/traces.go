@@ -74,6 +74,22 @@ func getErrorTraces(client http.Client, endpoint *url.URL, namespace, app string
 	return len(response.Data), err
 }
 
+func getServices(client http.Client, baseURL *url.URL) ([]string, error) {
+	u := *baseURL
+	u.Path = path.Join(u.Path, "/api/services")
+	resp, code, reqError := makeRequest(client, u.String(), nil)
+	if reqError != nil {
+		log.Errorf("Jaeger query error: %s [code: %d, URL: %v]", reqError, code, &u)
+		return nil, reqError
+	}
+	var services JaegerServices
+	if err := json.Unmarshal(resp, &services); err != nil {
+		log.Errorf("Error unmarshalling Jaeger response: %s [code: %d, URL: %v]", err, code, &u)
+		return nil, err
+	}
+	return services.Data, nil
+}
+
 func queryTraces(client http.Client, u *url.URL) (*JaegerResponse, error) {
 	resp, code, reqError := makeRequest(client, u.String(), nil)
 	if reqError != nil {
@@ -19,13 +19,18 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
+
+	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 	"github.com/kiali/kiali/server"
@@ -41,7 +46,8 @@ var (
 
 // Command line arguments
 var (
-	argConfigFile = flag.String("config", "", "Path to the YAML configuration file. If not specified, environment variables will be used for configuration.")
+	argConfigFile     = flag.String("config", "", "Path to the YAML configuration file. If not specified, environment variables will be used for configuration.")
+	argValidateConfig = flag.Bool("validate-config", false, "Validate the configuration, reporting its problems, and exit. The exit code is 1 when the configuration has errors.")
 )
 
 func init() {
@@ -76,6 +82,10 @@ func main() {
 	}
 	log.Tracef("Kiali Configuration:\n%s", config.Get())
 
+	if *argValidateConfig {
+		os.Exit(runConfigValidation())
+	}
+
 	if err := validateConfig(); err != nil {
 		log.Fatal(err)
 	}
@@ -112,7 +122,8 @@ func waitForTermination() {
 	var doneChan = make(chan bool)
 
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt)
+	// Kubernetes sends SIGTERM to stop the pod
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		for range signalChan {
 			log.Info("Termination Signal Received")
@@ -135,6 +146,13 @@ func validateConfig() error {
 		return fmt.Errorf("server static content root directory does not exist: %v", config.Get().Server.StaticContentRootDirectory)
 	}
 
+	if _, err := config.Get().Server.TLS.TLSVersion(); err != nil {
+		return fmt.Errorf("server TLS: %v", err)
+	}
+	if _, err := config.Get().Server.TLS.CipherSuiteIDs(); err != nil {
+		return fmt.Errorf("server TLS: %v", err)
+	}
+
 	validPathRegEx := regexp.MustCompile(`^\/[a-zA-Z0-9\-\._~!\$&\'()\*\+\,;=:@%/]*$`)
 	webRoot := config.Get().Server.WebRoot
 	if !validPathRegEx.MatchString(webRoot) {
@@ -154,10 +172,142 @@ func validateConfig() error {
 		log.Warningf("Kiali auth strategy is configured for anonymous access - users will not be authenticated.")
 	} else if auth.Strategy != config.AuthStrategyOpenId &&
 		auth.Strategy != config.AuthStrategyOpenshift &&
-		auth.Strategy != config.AuthStrategyToken {
+		auth.Strategy != config.AuthStrategyToken &&
+		auth.Strategy != config.AuthStrategyHeader {
 		return fmt.Errorf("Invalid authentication strategy [%v]", auth.Strategy)
 	}
 
+	if auth.Strategy == config.AuthStrategyHeader {
+		if auth.Header.UserHeader == "" {
+			return fmt.Errorf("the [%v] authentication strategy requires the header holding the user name", auth.Strategy)
+		}
+		// Anybody reaching Kiali could impersonate any user, so the proxies must be explicitly trusted
+		if len(auth.Header.TrustedProxies) == 0 {
+			return fmt.Errorf("the [%v] authentication strategy requires the list of trusted proxies", auth.Strategy)
+		}
+		for _, trusted := range auth.Header.TrustedProxies {
+			if _, _, err := net.ParseCIDR(trusted); err != nil && net.ParseIP(trusted) == nil {
+				return fmt.Errorf("trusted proxy [%v] is not a valid IP or CIDR", trusted)
+			}
+		}
+		log.Warningf("Kiali auth strategy is configured to trust the identity set by a proxy - all users will share the privileges of the Kiali service account.")
+	}
+
+	for _, rule := range auth.NamespaceAccess {
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return fmt.Errorf("namespace access rules must apply to some users or groups")
+		}
+		for _, pattern := range append(append([]string{}, rule.Allow...), rule.Deny...) {
+			if _, err := config.CompileNamespacePattern(pattern); err != nil {
+				return fmt.Errorf("namespace access rule has an invalid namespace pattern [%v]: %v", pattern, err)
+			}
+		}
+	}
+
+	for _, excluded := range config.Get().KubernetesConfig.ExcludeIstioTypes {
+		known := false
+		for _, istioType := range kubernetes.PluralType {
+			known = known || istioType == excluded
+		}
+		if !known {
+			return fmt.Errorf("excluded Istio type [%v] is not a known Istio type", excluded)
+		}
+	}
+
+	for _, pattern := range config.Get().ExternalServices.Istio.EnvoyStatsAllowlist {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("the allowlist of the Envoy stats has an invalid pattern [%v]: %v", pattern, err)
+		}
+	}
+
+	switch kubernetesConfig := config.Get().KubernetesConfig; kubernetesConfig.CacheScope {
+	case "", config.CacheScopeOnDemand:
+	case config.CacheScopeAccessibleNamespaces:
+		if kubernetesConfig.CacheNamespaceSyncPeriod <= 0 {
+			return fmt.Errorf("the [%v] cache scope requires a positive namespace sync period", kubernetesConfig.CacheScope)
+		}
+	default:
+		return fmt.Errorf("invalid cache scope [%v]", kubernetesConfig.CacheScope)
+	}
+
+	for kind, limit := range config.Get().KubernetesConfig.CacheLimits.ObjectsPerKind {
+		if limit < 0 {
+			return fmt.Errorf("invalid cache limit [%v] for kind [%v]", limit, kind)
+		}
+	}
+
+	if provider := config.Get().ExternalServices.Tracing.Provider; provider != config.TracingProviderJaeger && provider != config.TracingProviderOTLP {
+		return fmt.Errorf("invalid tracing provider [%v]", provider)
+	}
+	for cluster, clusterConfig := range config.Get().ExternalServices.Tracing.Clusters {
+		if provider := clusterConfig.Provider; provider != "" && provider != config.TracingProviderJaeger && provider != config.TracingProviderOTLP {
+			return fmt.Errorf("invalid tracing provider [%v] of cluster [%v]", provider, cluster)
+		}
+	}
+
+	if config.Get().ExternalServices.Prometheus.MaxDataPoints < 0 {
+		return fmt.Errorf("the maximum number of data points of the Prometheus queries can't be negative")
+	}
+
+	for _, rule := range config.Get().ExternalServices.Prometheus.RecordingRules {
+		if rule.Metric == "" || rule.Record == "" {
+			return fmt.Errorf("recording rules require a metric and a record")
+		}
+		if _, err := model.ParseDuration(rule.Interval); err != nil {
+			return fmt.Errorf("invalid interval [%v] of recording rule [%v]: %v", rule.Interval, rule.Record, err)
+		}
+	}
+
+	for _, objective := range config.Get().SLO.Objectives {
+		if err := objective.CompilePatterns(); err != nil {
+			return err
+		}
+		if objective.Availability < 0 || objective.Availability >= 100 || objective.LatencyTarget < 0 || objective.LatencyTarget >= 100 {
+			return fmt.Errorf("SLO objectives must be percentages lower than 100")
+		}
+		if objective.LatencyTarget > 0 && objective.LatencyThreshold <= 0 {
+			return fmt.Errorf("SLO latency objectives require a latency threshold")
+		}
+		if _, err := model.ParseDuration(objective.Window); objective.Window != "" && err != nil {
+			return fmt.Errorf("invalid window [%v] of SLO objective: %v", objective.Window, err)
+		}
+	}
+
+	resultsCache := config.Get().KubernetesConfig.ResultsCache
+	switch resultsCache.Backend {
+	case "", config.ResultsCacheBackendNone, config.ResultsCacheBackendMemory:
+	case config.ResultsCacheBackendRedis:
+		if resultsCache.Redis.Address == "" {
+			return fmt.Errorf("the [%v] results cache backend requires the address of the server", resultsCache.Backend)
+		}
+	default:
+		return fmt.Errorf("invalid results cache backend [%v]", resultsCache.Backend)
+	}
+
+	// Check the remote clusters are properly configured
+	clusterNames := make(map[string]bool)
+	for _, cluster := range config.Get().Clustering.Clusters {
+		if cluster.Name == "" {
+			return fmt.Errorf("remote clusters must have a name")
+		}
+		if clusterNames[cluster.Name] {
+			return fmt.Errorf("remote cluster [%v] is configured more than once", cluster.Name)
+		}
+		clusterNames[cluster.Name] = true
+		if cluster.SecretFile == "" && cluster.SecretRef == nil {
+			return fmt.Errorf("remote cluster [%v] has no secret file nor secret reference", cluster.Name)
+		}
+		switch cluster.Auth.Strategy {
+		case config.ClusterAuthStrategyServiceAccount, config.ClusterAuthStrategyToken:
+		case config.ClusterAuthStrategyOpenIdExchange:
+			if auth.Strategy != config.AuthStrategyOpenId {
+				return fmt.Errorf("remote cluster [%v] uses the [%v] strategy which requires the [%v] authentication strategy", cluster.Name, cluster.Auth.Strategy, config.AuthStrategyOpenId)
+			}
+		default:
+			return fmt.Errorf("Invalid authentication strategy [%v] for remote cluster [%v]", cluster.Auth.Strategy, cluster.Name)
+		}
+	}
+
 	// Check the signing key for the JWT token is valid
 	signingKey := config.Get().LoginToken.SigningKey
 	if err := config.ValidateSigningKey(signingKey, auth.Strategy); err != nil {
@@ -167,6 +317,34 @@ func validateConfig() error {
 	return nil
 }
 
+// runConfigValidation prints the problems of the configuration, returning the exit code: 1 when the configuration
+// has errors
+func runConfigValidation() int {
+	findings := []config.LintFinding{}
+	if *argConfigFile != "" {
+		if fileContent, err := ioutil.ReadFile(*argConfigFile); err == nil {
+			findings = append(findings, config.LintYAML(string(fileContent))...)
+		}
+	}
+	if err := validateConfig(); err != nil {
+		findings = append(findings, config.LintFinding{Severity: config.LintError, Message: err.Error()})
+	}
+	findings = append(findings, status.ValidateConfig(config.Get())...)
+
+	errors := 0
+	for _, finding := range findings {
+		fmt.Println(finding)
+		if finding.Severity == config.LintError {
+			errors++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errors, len(findings)-errors)
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
+
 func validateFlags() {
 	if *argConfigFile != "" {
 		if _, err := os.Stat(*argConfigFile); err != nil {
@@ -0,0 +1,142 @@
+package kubernetes
+
+import (
+	"encoding/json"
+
+	autoscaling_v1 "k8s.io/api/autoscaling/v1"
+	autoscaling_v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticalPodAutoscaler holds the fields of the VerticalPodAutoscalers (autoscaling.k8s.io/v1) read by Kiali
+type VerticalPodAutoscaler struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec               struct {
+		TargetRef    *autoscaling_v1.CrossVersionObjectReference `json:"targetRef,omitempty"`
+		UpdatePolicy *struct {
+			UpdateMode string `json:"updateMode,omitempty"`
+		} `json:"updatePolicy,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Recommendation *RecommendedPodResources         `json:"recommendation,omitempty"`
+		Conditions     []VerticalPodAutoscalerCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// RecommendedPodResources holds the resources recommended for the containers of the pods
+type RecommendedPodResources struct {
+	ContainerRecommendations []VerticalPodAutoscalerRecommendation `json:"containerRecommendations,omitempty"`
+}
+
+// VerticalPodAutoscalerRecommendation holds the resources recommended for a container
+type VerticalPodAutoscalerRecommendation struct {
+	ContainerName string               `json:"containerName,omitempty"`
+	Target        core_v1.ResourceList `json:"target"`
+	LowerBound    core_v1.ResourceList `json:"lowerBound,omitempty"`
+	UpperBound    core_v1.ResourceList `json:"upperBound,omitempty"`
+}
+
+// VerticalPodAutoscalerCondition is a condition of a VerticalPodAutoscaler, such as RecommendationProvided
+type VerticalPodAutoscalerCondition struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	LastTransitionTime meta_v1.Time `json:"lastTransitionTime,omitempty"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+}
+
+// GetHorizontalPodAutoscalers returns the HorizontalPodAutoscalers of a namespace, with the metrics and conditions of
+// the autoscaling/v2beta2 API
+func (in *K8SClient) GetHorizontalPodAutoscalers(namespace string) ([]autoscaling_v2beta2.HorizontalPodAutoscaler, error) {
+	if hpaList, err := in.k8s.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).List(emptyListOptions); err == nil {
+		return hpaList.Items, nil
+	} else {
+		return []autoscaling_v2beta2.HorizontalPodAutoscaler{}, err
+	}
+}
+
+// GetVerticalPodAutoscalers returns the VerticalPodAutoscalers of a namespace, none when the VPA is not installed
+func (in *K8SClient) GetVerticalPodAutoscalers(namespace string) ([]VerticalPodAutoscaler, error) {
+	result, err := in.k8s.RESTClient().Get().Prefix("apis", "autoscaling.k8s.io", "v1").Namespace(namespace).Resource("verticalpodautoscalers").DoRaw()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return []VerticalPodAutoscaler{}, nil
+		}
+		return []VerticalPodAutoscaler{}, err
+	}
+	var vpaList struct {
+		Items []VerticalPodAutoscaler `json:"items"`
+	}
+	if err := json.Unmarshal(result, &vpaList); err != nil {
+		return []VerticalPodAutoscaler{}, err
+	}
+	return vpaList.Items, nil
+}
+
+// ScaledObject holds the fields of the KEDA ScaledObjects (keda.sh/v1alpha1) read by Kiali
+type ScaledObject struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec               struct {
+		ScaleTargetRef struct {
+			APIVersion string `json:"apiVersion,omitempty"`
+			// Deployment when empty
+			Kind string `json:"kind,omitempty"`
+			Name string `json:"name"`
+		} `json:"scaleTargetRef"`
+		MinReplicaCount  *int32                `json:"minReplicaCount,omitempty"`
+		MaxReplicaCount  *int32                `json:"maxReplicaCount,omitempty"`
+		IdleReplicaCount *int32                `json:"idleReplicaCount,omitempty"`
+		Triggers         []ScaledObjectTrigger `json:"triggers"`
+	} `json:"spec"`
+	Status struct {
+		LastActiveTime *meta_v1.Time `json:"lastActiveTime,omitempty"`
+		// HorizontalPodAutoscaler created by KEDA for the ScaledObject
+		HpaName string `json:"hpaName,omitempty"`
+		// Conditions such as Ready, Active, Fallback or Paused
+		Conditions []ScaledObjectCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// ScaledObjectCondition is a condition of a ScaledObject
+type ScaledObjectCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ScaledObjectTrigger is a scaler of a ScaledObject, such as prometheus, kafka or cpu
+type ScaledObjectTrigger struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name,omitempty"`
+	MetricType string            `json:"metricType,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Annotations pausing the autoscaling of a ScaledObject, the first one at a fixed number of replicas
+const (
+	ScaledObjectPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+	ScaledObjectPausedAnnotation         = "autoscaling.keda.sh/paused"
+)
+
+// GetScaledObjects returns the KEDA ScaledObjects of a namespace, none when KEDA is not installed
+func (in *K8SClient) GetScaledObjects(namespace string) ([]ScaledObject, error) {
+	result, err := in.k8s.RESTClient().Get().Prefix("apis", "keda.sh", "v1alpha1").Namespace(namespace).Resource("scaledobjects").DoRaw()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return []ScaledObject{}, nil
+		}
+		return []ScaledObject{}, err
+	}
+	var scaledObjectList struct {
+		Items []ScaledObject `json:"items"`
+	}
+	if err := json.Unmarshal(result, &scaledObjectList); err != nil {
+		return []ScaledObject{}, err
+	}
+	return scaledObjectList.Items, nil
+}
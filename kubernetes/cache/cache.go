@@ -2,10 +2,13 @@ package cache
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -34,7 +37,9 @@ type (
 		KubernetesCache
 		IstioCache
 		NamespacesCache
+		NotifyCache
 		ProxyStatusCache
+		StatusCache
 	}
 
 	// This map will store Informers per specific types
@@ -63,6 +68,8 @@ type (
 		cacheIstioTypes        map[string]bool
 		stopChan               map[string]chan struct{}
 		nsCache                map[string]typeCache
+		nsCacheCreated         map[string]time.Time
+		nsCacheLock            sync.RWMutex
 		cacheLock              sync.Mutex
 		tokenLock              sync.RWMutex
 		tokenNamespaces        map[string]namespaceCache
@@ -70,6 +77,17 @@ type (
 		proxyStatusLock        sync.RWMutex
 		proxyStatusCreated     *time.Time
 		proxyStatusNamespaces  map[string]map[string]podProxyStatus
+		backgroundStopChan     chan struct{}
+		warmupQueue            chan string
+		warmingLock            sync.Mutex
+		warmingNamespaces      map[string]bool
+		stripUnusedFields      bool
+		objectLimits           map[string]int
+		usageLock              sync.Mutex
+		lastUsed               map[string]time.Time
+		evicted                map[string]time.Time
+		waitersLock            sync.Mutex
+		waiters                map[string][]*objectWaiter
 	}
 )
 
@@ -110,7 +128,10 @@ func NewKialiCache() (KialiCache, error) {
 	cacheNamespaces := kConfig.KubernetesConfig.CacheNamespaces
 	cacheIstioTypes := make(map[string]bool)
 	for _, iType := range kConfig.KubernetesConfig.CacheIstioTypes {
-		cacheIstioTypes[iType] = true
+		// Excluded types are not watched
+		if !kConfig.KubernetesConfig.IsIstioTypeExcluded(iType) {
+			cacheIstioTypes[iType] = true
+		}
 	}
 	log.Tracef("[Kiali Cache] cacheIstioTypes %v", cacheIstioTypes)
 
@@ -130,18 +151,42 @@ func NewKialiCache() (KialiCache, error) {
 		tokenNamespaces:        make(map[string]namespaceCache),
 		tokenNamespaceDuration: tokenNamespaceDuration,
 		proxyStatusNamespaces:  make(map[string]map[string]podProxyStatus),
+		backgroundStopChan:     make(chan struct{}),
+		stripUnusedFields:      kConfig.KubernetesConfig.CacheLimits.StripUnusedFields,
+		objectLimits:           kConfig.KubernetesConfig.CacheLimits.ObjectsPerKind,
+		lastUsed:               make(map[string]time.Time),
+		evicted:                make(map[string]time.Time),
+		waiters:                make(map[string][]*objectWaiter),
 	}
 
 	kialiCacheImpl.k8sApi = istioClient.GetK8sApi()
 	kialiCacheImpl.istioNetworkingGetter = istioClient.GetIstioNetworkingApi()
 	kialiCacheImpl.istioSecurityGetter = istioClient.GetIstioSecurityApi()
 
+	go kialiCacheImpl.updateStatusMetrics(kialiCacheImpl.backgroundStopChan)
+	if kConfig.KubernetesConfig.CacheWarmup.Background {
+		kialiCacheImpl.warmupQueue = make(chan string, warmupQueueSize)
+		kialiCacheImpl.warmingNamespaces = make(map[string]bool)
+		go kialiCacheImpl.warmup(kialiCacheImpl.backgroundStopChan)
+		for _, namespace := range kConfig.KubernetesConfig.CacheWarmup.Namespaces {
+			kialiCacheImpl.scheduleWarmup(namespace)
+		}
+	}
+	if kConfig.KubernetesConfig.CacheScope == kialiConfig.CacheScopeAccessibleNamespaces {
+		namespaceSyncPeriod := time.Duration(kConfig.KubernetesConfig.CacheNamespaceSyncPeriod) * time.Second
+		go kialiCacheImpl.syncNamespaces(namespaceSyncPeriod, kialiCacheImpl.backgroundStopChan)
+	}
+
 	log.Infof("Kiali Cache is active for namespaces %v", cacheNamespaces)
 	return &kialiCacheImpl, nil
 }
 
-// It will indicate if a namespace should have a cache
+// It will indicate if a namespace should have a cache.
+// Namespaces not accessible by Kiali are never cached, as Kiali can't watch them.
 func (c *kialiCacheImpl) isCached(namespace string) bool {
+	if !isAccessibleNamespace(namespace) || c.isEvicted(namespace) {
+		return false
+	}
 	for _, cacheNs := range c.cacheNamespaces {
 		if matches, _ := regexp.MatchString(strings.TrimSpace(cacheNs), namespace); matches {
 			return true
@@ -150,21 +195,38 @@ func (c *kialiCacheImpl) isCached(namespace string) bool {
 	return false
 }
 
+// getNamespaceCache returns the informers of a namespace, if the namespace is cached
+func (c *kialiCacheImpl) getNamespaceCache(namespace string) (typeCache, bool) {
+	c.nsCacheLock.RLock()
+	defer c.nsCacheLock.RUnlock()
+	informers, exist := c.nsCache[namespace]
+	return informers, exist
+}
+
 func (c *kialiCacheImpl) createCache(namespace string) bool {
-	if _, exist := c.nsCache[namespace]; exist {
+	if _, exist := c.getNamespaceCache(namespace); exist {
 		return true
 	}
-	informer := make(typeCache)
-	c.createKubernetesInformers(namespace, &informer)
-	c.createIstioInformers(namespace, &informer)
-	c.nsCache[namespace] = informer
+	informers := make(typeCache)
+	c.createKubernetesInformers(namespace, &informers)
+	c.createIstioInformers(namespace, &informers)
+	for informerType, informer := range informers {
+		informer.AddEventHandler(c.notifyHandler(namespace, informerType))
+	}
+	c.nsCacheLock.Lock()
+	c.nsCache[namespace] = informers
+	if c.nsCacheCreated == nil {
+		c.nsCacheCreated = make(map[string]time.Time)
+	}
+	c.nsCacheCreated[namespace] = time.Now()
+	c.nsCacheLock.Unlock()
 
 	if _, exist := c.stopChan[namespace]; !exist {
 		c.stopChan[namespace] = make(chan struct{})
 	}
 
 	go func(stopCh <-chan struct{}) {
-		for _, informer := range c.nsCache[namespace] {
+		for _, informer := range informers {
 			go informer.Run(stopCh)
 		}
 		<-stopCh
@@ -174,7 +236,7 @@ func (c *kialiCacheImpl) createCache(namespace string) bool {
 	log.Infof("Waiting for Kiali cache for [namespace: %s] to sync", namespace)
 	isSynced := func() bool {
 		hasSynced := true
-		for _, informer := range c.nsCache[namespace] {
+		for _, informer := range informers {
 			hasSynced = hasSynced && informer.HasSynced()
 		}
 		return hasSynced
@@ -186,47 +248,214 @@ func (c *kialiCacheImpl) createCache(namespace string) bool {
 	}
 	log.Infof("Kiali cache for [namespace: %s] started", namespace)
 
-	return true
+	c.touchNamespace(namespace)
+	c.enforceLimits(namespace)
+	_, exist := c.getNamespaceCache(namespace)
+	return exist
 }
 
 // CheckNamespace will
 // - Validate if a namespace is included in the cache
-// - Create and initialize a cache
+// - Create and initialize a cache, or schedule its creation when caches are warmed up in the background
 // - Validate if a cache is synced
 func (c *kialiCacheImpl) CheckNamespace(namespace string) bool {
 	if !c.isCached(namespace) {
+		recordCacheRequest(KubernetesCacheName, false)
 		return false
 	}
-	if _, exist := c.nsCache[namespace]; !exist {
+	c.touchNamespace(namespace)
+	if _, exist := c.getNamespaceCache(namespace); !exist {
+		recordCacheRequest(KubernetesCacheName, false)
+		if c.warmupQueue != nil {
+			c.scheduleWarmup(namespace)
+			return false
+		}
 		defer c.cacheLock.Unlock()
 		c.cacheLock.Lock()
 		return c.createCache(namespace)
 	}
-	return c.isKubernetesSynced(namespace) && c.isIstioSynced(namespace)
+	synced := c.isKubernetesSynced(namespace) && c.isIstioSynced(namespace)
+	recordCacheRequest(KubernetesCacheName, synced)
+	return synced
+}
+
+// isAccessibleNamespace returns true if Kiali is allowed to access the namespace (Deployment.AccessibleNamespaces)
+func isAccessibleNamespace(namespace string) bool {
+	for _, accessible := range kialiConfig.Get().Deployment.AccessibleNamespaces {
+		if accessible == "**" || accessible == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// listAccessibleNamespaces returns the names of the namespaces of the cluster accessible by Kiali.
+// When Kiali can access all the namespaces, they are listed; otherwise each accessible namespace is checked, so
+// Kiali doesn't need permissions to list the namespaces of the cluster.
+func (c *kialiCacheImpl) listAccessibleNamespaces() ([]string, error) {
+	accessibleNamespaces := kialiConfig.Get().Deployment.AccessibleNamespaces
+	names := []string{}
+	for _, accessible := range accessibleNamespaces {
+		if accessible == "**" {
+			nsList, err := c.k8sApi.CoreV1().Namespaces().List(meta_v1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for _, ns := range nsList.Items {
+				names = append(names, ns.Name)
+			}
+			return names, nil
+		}
+	}
+	for _, accessible := range accessibleNamespaces {
+		if _, err := c.k8sApi.CoreV1().Namespaces().Get(accessible, meta_v1.GetOptions{}); err == nil {
+			names = append(names, accessible)
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// syncNamespaces periodically creates the informers of the accessible namespaces included in the cache, and stops
+// the informers of the namespaces that are gone
+func (c *kialiCacheImpl) syncNamespaces(period time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		c.syncNamespacesOnce()
+		select {
+		case <-ticker.C:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (c *kialiCacheImpl) syncNamespacesOnce() {
+	namespaces, err := c.listAccessibleNamespaces()
+	if err != nil {
+		log.Errorf("Kiali cache cannot sync the cached namespaces: %v", err)
+		return
+	}
+
+	existing := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		existing[namespace] = true
+		if !c.isCached(namespace) {
+			continue
+		}
+		if _, exist := c.getNamespaceCache(namespace); !exist {
+			c.cacheLock.Lock()
+			c.createCache(namespace)
+			c.cacheLock.Unlock()
+		}
+	}
+
+	c.nsCacheLock.RLock()
+	gone := []string{}
+	for namespace := range c.nsCache {
+		if !existing[namespace] {
+			gone = append(gone, namespace)
+		}
+	}
+	c.nsCacheLock.RUnlock()
+	for _, namespace := range gone {
+		log.Infof("Kiali cache for [namespace: %s] removed, the namespace is gone", namespace)
+		c.cacheLock.Lock()
+		c.deleteCache(namespace)
+		c.cacheLock.Unlock()
+	}
+}
+
+// warmupQueueSize is the maximum number of namespaces waiting to be warmed up.
+// Namespaces not fitting in the queue are scheduled again on their next request.
+const warmupQueueSize = 1000
+
+// scheduleWarmup queues the creation of the cache of a namespace, unless it is already queued
+func (c *kialiCacheImpl) scheduleWarmup(namespace string) {
+	if !c.isCached(namespace) {
+		return
+	}
+	c.warmingLock.Lock()
+	defer c.warmingLock.Unlock()
+	if c.warmingNamespaces[namespace] {
+		return
+	}
+	select {
+	case c.warmupQueue <- namespace:
+		c.warmingNamespaces[namespace] = true
+	default:
+		log.Debugf("Kiali cache warmup queue is full, [namespace: %s] is not scheduled", namespace)
+	}
+}
+
+// warmup creates the caches of the queued namespaces, one at a time, in the order they were scheduled
+func (c *kialiCacheImpl) warmup(stopCh <-chan struct{}) {
+	for {
+		select {
+		case namespace := <-c.warmupQueue:
+			c.cacheLock.Lock()
+			c.createCache(namespace)
+			c.cacheLock.Unlock()
+			c.warmingLock.Lock()
+			delete(c.warmingNamespaces, namespace)
+			c.warmingLock.Unlock()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// GetWarmingNamespaces returns the namespaces whose cache is being warmed up
+func (c *kialiCacheImpl) GetWarmingNamespaces() []string {
+	c.warmingLock.Lock()
+	defer c.warmingLock.Unlock()
+	namespaces := make([]string, 0, len(c.warmingNamespaces))
+	for namespace := range c.warmingNamespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
 }
 
 // RefreshNamespace will delete the specific namespace's cache and create a new one.
 func (c *kialiCacheImpl) RefreshNamespace(namespace string) {
 	defer c.cacheLock.Unlock()
 	c.cacheLock.Lock()
+	c.deleteCache(namespace)
+	c.createCache(namespace)
+}
+
+// deleteCache stops the informers of a namespace and removes them from the cache
+func (c *kialiCacheImpl) deleteCache(namespace string) {
 	if nsChan, exist := c.stopChan[namespace]; exist {
 		close(nsChan)
 		delete(c.stopChan, namespace)
 	}
+	c.nsCacheLock.Lock()
 	delete(c.nsCache, namespace)
-	c.createCache(namespace)
+	delete(c.nsCacheCreated, namespace)
+	c.nsCacheLock.Unlock()
 }
 
 func (c *kialiCacheImpl) Stop() {
 	log.Infof("Stopping Kiali Cache")
 	defer c.cacheLock.Unlock()
 	c.cacheLock.Lock()
+	if c.backgroundStopChan != nil {
+		close(c.backgroundStopChan)
+		c.backgroundStopChan = nil
+	}
 	for namespace, nsChan := range c.stopChan {
 		close(nsChan)
 		delete(c.stopChan, namespace)
 	}
 	log.Infof("Clearing Kiali Cache")
+	c.nsCacheLock.Lock()
+	defer c.nsCacheLock.Unlock()
 	for ns := range c.nsCache {
 		delete(c.nsCache, ns)
+		delete(c.nsCacheCreated, ns)
 	}
 }
@@ -5,11 +5,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	kialiConfig "github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 )
 
 func TestNewKialiCache_isCached(t *testing.T) {
 	assert := assert.New(t)
+	kialiConfig.Set(kialiConfig.NewConfig())
 
 	kialiCacheImpl := kialiCacheImpl{
 		istioClient:     kubernetes.K8SClient{},
@@ -26,3 +28,38 @@ func TestNewKialiCache_isCached(t *testing.T) {
 	assert.False(kialiCacheImpl.isCached("bbcdefghi"))
 	assert.True(kialiCacheImpl.isCached("galicia"))
 }
+
+func TestIsCachedOnlyAccessibleNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	conf := kialiConfig.NewConfig()
+	conf.Deployment.AccessibleNamespaces = []string{"bookinfo", "istio-system"}
+	kialiConfig.Set(conf)
+	defer kialiConfig.Set(kialiConfig.NewConfig())
+
+	kialiCacheImpl := kialiCacheImpl{
+		cacheNamespaces: []string{".*"},
+		nsCache:         map[string]typeCache{},
+	}
+
+	assert.True(kialiCacheImpl.isCached("bookinfo"))
+	assert.True(kialiCacheImpl.isCached("istio-system"))
+	assert.False(kialiCacheImpl.isCached("travels"))
+}
+
+func TestCheckNamespaceSchedulesWarmup(t *testing.T) {
+	assert := assert.New(t)
+	kialiConfig.Set(kialiConfig.NewConfig())
+
+	kialiCacheImpl := kialiCacheImpl{
+		cacheNamespaces:   []string{"bookinfo"},
+		nsCache:           map[string]typeCache{},
+		warmupQueue:       make(chan string, warmupQueueSize),
+		warmingNamespaces: map[string]bool{},
+	}
+
+	assert.False(kialiCacheImpl.CheckNamespace("bookinfo"))
+	assert.False(kialiCacheImpl.CheckNamespace("bookinfo"))
+	assert.False(kialiCacheImpl.CheckNamespace("travels"))
+	assert.Equal([]string{"bookinfo"}, kialiCacheImpl.GetWarmingNamespaces())
+	assert.Len(kialiCacheImpl.warmupQueue, 1)
+}
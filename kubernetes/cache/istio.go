@@ -2,11 +2,8 @@ package cache
 
 import (
 	"fmt"
-	"time"
 
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/tools/cache"
 
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
@@ -28,34 +25,34 @@ func (c *kialiCacheImpl) CheckIstioResource(resourceType string) bool {
 func (c *kialiCacheImpl) createIstioInformers(namespace string, informer *typeCache) {
 	// Networking API
 	if c.CheckIstioResource(kubernetes.VirtualServices) {
-		(*informer)[kubernetes.VirtualServices] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.VirtualServices, c.refreshDuration, namespace)
+		(*informer)[kubernetes.VirtualServices] = c.createIndexInformer(c.istioNetworkingGetter, kubernetes.VirtualServices, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.DestinationRules) {
-		(*informer)[kubernetes.DestinationRules] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.DestinationRules, c.refreshDuration, namespace)
+		(*informer)[kubernetes.DestinationRules] = c.createIndexInformer(c.istioNetworkingGetter, kubernetes.DestinationRules, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.Gateways) {
-		(*informer)[kubernetes.Gateways] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Gateways, c.refreshDuration, namespace)
+		(*informer)[kubernetes.Gateways] = c.createIndexInformer(c.istioNetworkingGetter, kubernetes.Gateways, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.ServiceEntries) {
-		(*informer)[kubernetes.ServiceEntries] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.ServiceEntries, c.refreshDuration, namespace)
+		(*informer)[kubernetes.ServiceEntries] = c.createIndexInformer(c.istioNetworkingGetter, kubernetes.ServiceEntries, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.Sidecars) {
-		(*informer)[kubernetes.Sidecars] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Sidecars, c.refreshDuration, namespace)
+		(*informer)[kubernetes.Sidecars] = c.createIndexInformer(c.istioNetworkingGetter, kubernetes.Sidecars, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.PeerAuthentications) {
-		(*informer)[kubernetes.PeerAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.PeerAuthentications, c.refreshDuration, namespace)
+		(*informer)[kubernetes.PeerAuthentications] = c.createIndexInformer(c.istioSecurityGetter, kubernetes.PeerAuthentications, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.RequestAuthentications) {
-		(*informer)[kubernetes.RequestAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.RequestAuthentications, c.refreshDuration, namespace)
+		(*informer)[kubernetes.RequestAuthentications] = c.createIndexInformer(c.istioSecurityGetter, kubernetes.RequestAuthentications, namespace, &kubernetes.GenericIstioObject{})
 	}
 	if c.CheckIstioResource(kubernetes.AuthorizationPolicies) {
-		(*informer)[kubernetes.AuthorizationPolicies] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.AuthorizationPolicies, c.refreshDuration, namespace)
+		(*informer)[kubernetes.AuthorizationPolicies] = c.createIndexInformer(c.istioSecurityGetter, kubernetes.AuthorizationPolicies, namespace, &kubernetes.GenericIstioObject{})
 	}
 }
 
 func (c *kialiCacheImpl) isIstioSynced(namespace string) bool {
 	var isSynced bool
-	if nsCache, exist := c.nsCache[namespace]; exist {
+	if nsCache, exist := c.getNamespaceCache(namespace); exist {
 		isSynced = true
 		if c.CheckIstioResource(kubernetes.VirtualServices) {
 			isSynced = isSynced && nsCache[kubernetes.VirtualServices].HasSynced()
@@ -87,19 +84,11 @@ func (c *kialiCacheImpl) isIstioSynced(namespace string) bool {
 	return isSynced
 }
 
-func createIstioIndexInformer(getter cache.Getter, resourceType string, refreshDuration time.Duration, namespace string) cache.SharedIndexInformer {
-	return cache.NewSharedIndexInformer(cache.NewListWatchFromClient(getter, resourceType, namespace, fields.Everything()),
-		&kubernetes.GenericIstioObject{},
-		refreshDuration,
-		cache.Indexers{},
-	)
-}
-
 func (c *kialiCacheImpl) GetIstioObjects(namespace string, resourceType string, labelSelector string) ([]kubernetes.IstioObject, error) {
 	if !c.CheckIstioResource(resourceType) {
 		return nil, fmt.Errorf("Kiali cache doesn't support [resourceType: %s]", resourceType)
 	}
-	if nsCache, nsOk := c.nsCache[namespace]; nsOk {
+	if nsCache, nsOk := c.getNamespaceCache(namespace); nsOk {
 		resources := nsCache[resourceType].GetStore().List()
 		lenResources := len(resources)
 		if lenResources > 0 {
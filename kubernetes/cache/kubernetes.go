@@ -6,8 +6,10 @@ import (
 
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/informers"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
@@ -29,19 +31,30 @@ type (
 )
 
 func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *typeCache) {
-	sharedInformers := informers.NewSharedInformerFactoryWithOptions(c.k8sApi, c.refreshDuration, informers.WithNamespace(namespace))
-	(*informer)[kubernetes.DeploymentType] = sharedInformers.Apps().V1().Deployments().Informer()
-	(*informer)[kubernetes.StatefulSetType] = sharedInformers.Apps().V1().StatefulSets().Informer()
-	(*informer)[kubernetes.ReplicaSetType] = sharedInformers.Apps().V1().ReplicaSets().Informer()
-	(*informer)[kubernetes.ServiceType] = sharedInformers.Core().V1().Services().Informer()
-	(*informer)[kubernetes.PodType] = sharedInformers.Core().V1().Pods().Informer()
-	(*informer)[kubernetes.ConfigMapType] = sharedInformers.Core().V1().ConfigMaps().Informer()
-	(*informer)[kubernetes.EndpointsType] = sharedInformers.Core().V1().Endpoints().Informer()
+	appsApi := c.k8sApi.AppsV1().RESTClient()
+	coreApi := c.k8sApi.CoreV1().RESTClient()
+	(*informer)[kubernetes.DeploymentType] = c.createIndexInformer(appsApi, "deployments", namespace, &apps_v1.Deployment{})
+	(*informer)[kubernetes.StatefulSetType] = c.createIndexInformer(appsApi, "statefulsets", namespace, &apps_v1.StatefulSet{})
+	(*informer)[kubernetes.ReplicaSetType] = c.createIndexInformer(appsApi, "replicasets", namespace, &apps_v1.ReplicaSet{})
+	(*informer)[kubernetes.ServiceType] = c.createIndexInformer(coreApi, "services", namespace, &core_v1.Service{})
+	(*informer)[kubernetes.PodType] = c.createIndexInformer(coreApi, "pods", namespace, &core_v1.Pod{})
+	(*informer)[kubernetes.ConfigMapType] = c.createIndexInformer(coreApi, "configmaps", namespace, &core_v1.ConfigMap{})
+	(*informer)[kubernetes.EndpointsType] = c.createIndexInformer(coreApi, "endpoints", namespace, &core_v1.Endpoints{})
+}
+
+// createIndexInformer creates an informer for a resource of a namespace. When configured, the fields Kiali never
+// reads are removed from the objects before they are stored.
+func (c *kialiCacheImpl) createIndexInformer(getter cache.Getter, resource string, namespace string, objType runtime.Object) cache.SharedIndexInformer {
+	var listWatch cache.ListerWatcher = cache.NewListWatchFromClient(getter, resource, namespace, fields.Everything())
+	if c.stripUnusedFields {
+		listWatch = newTransformingListWatch(listWatch)
+	}
+	return cache.NewSharedIndexInformer(listWatch, objType, c.refreshDuration, cache.Indexers{})
 }
 
 func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 	var isSynced bool
-	if nsCache, exist := c.nsCache[namespace]; exist {
+	if nsCache, exist := c.getNamespaceCache(namespace); exist {
 		isSynced = nsCache[kubernetes.DeploymentType].HasSynced() &&
 			nsCache[kubernetes.StatefulSetType].HasSynced() &&
 			nsCache[kubernetes.ReplicaSetType].HasSynced() &&
@@ -56,7 +69,7 @@ func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 }
 
 func (c *kialiCacheImpl) GetConfigMap(namespace, name string) (*core_v1.ConfigMap, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.ConfigMapType].GetStore().GetByKey(key)
@@ -76,7 +89,7 @@ func (c *kialiCacheImpl) GetConfigMap(namespace, name string) (*core_v1.ConfigMa
 }
 
 func (c *kialiCacheImpl) GetDeployments(namespace string) ([]apps_v1.Deployment, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		deps := nsCache[kubernetes.DeploymentType].GetStore().List()
 		lenDeps := len(deps)
 		if lenDeps > 0 {
@@ -96,7 +109,7 @@ func (c *kialiCacheImpl) GetDeployments(namespace string) ([]apps_v1.Deployment,
 }
 
 func (c *kialiCacheImpl) GetDeployment(namespace, name string) (*apps_v1.Deployment, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.DeploymentType].GetStore().GetByKey(key)
@@ -116,7 +129,7 @@ func (c *kialiCacheImpl) GetDeployment(namespace, name string) (*apps_v1.Deploym
 }
 
 func (c *kialiCacheImpl) GetEndpoints(namespace, name string) (*core_v1.Endpoints, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.EndpointsType].GetStore().GetByKey(key)
@@ -136,7 +149,7 @@ func (c *kialiCacheImpl) GetEndpoints(namespace, name string) (*core_v1.Endpoint
 }
 
 func (c *kialiCacheImpl) GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		ss := nsCache[kubernetes.StatefulSetType].GetStore().List()
 		lenSs := len(ss)
 		if lenSs > 0 {
@@ -156,7 +169,7 @@ func (c *kialiCacheImpl) GetStatefulSets(namespace string) ([]apps_v1.StatefulSe
 }
 
 func (c *kialiCacheImpl) GetStatefulSet(namespace, name string) (*apps_v1.StatefulSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.StatefulSetType].GetStore().GetByKey(key)
@@ -176,7 +189,7 @@ func (c *kialiCacheImpl) GetStatefulSet(namespace, name string) (*apps_v1.Statef
 }
 
 func (c *kialiCacheImpl) GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		services := nsCache[kubernetes.ServiceType].GetStore().List()
 		lenServices := len(services)
 		if lenServices > 0 {
@@ -207,7 +220,7 @@ func (c *kialiCacheImpl) GetServices(namespace string, selectorLabels map[string
 }
 
 func (c *kialiCacheImpl) GetService(namespace, name string) (*core_v1.Service, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.ServiceType].GetStore().GetByKey(key)
@@ -227,7 +240,7 @@ func (c *kialiCacheImpl) GetService(namespace, name string) (*core_v1.Service, e
 }
 
 func (c *kialiCacheImpl) GetPods(namespace, labelSelector string) ([]core_v1.Pod, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		pods := nsCache[kubernetes.PodType].GetStore().List()
 		lenPods := len(pods)
 		if lenPods > 0 {
@@ -260,7 +273,7 @@ func (c *kialiCacheImpl) GetPods(namespace, labelSelector string) ([]core_v1.Pod
 }
 
 func (c *kialiCacheImpl) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.getNamespaceCache(namespace); ok {
 		reps := nsCache[kubernetes.ReplicaSetType].GetStore().List()
 		lenReps := len(reps)
 		if lenReps > 0 {
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kiali/kiali/log"
+)
+
+// touchNamespace records the use of the cache of a namespace, to evict the least recently used namespaces first
+func (c *kialiCacheImpl) touchNamespace(namespace string) {
+	c.usageLock.Lock()
+	defer c.usageLock.Unlock()
+	if c.lastUsed == nil {
+		c.lastUsed = make(map[string]time.Time)
+	}
+	c.lastUsed[namespace] = time.Now()
+}
+
+// defaultEvictionDuration is how long an evicted namespace is not cached, when the caches are not resynced
+const defaultEvictionDuration = 5 * time.Minute
+
+// isEvicted returns true if the cache of the namespace was dropped to respect the object limits. The namespace is
+// served from the API until the next resync period, when it can be cached again.
+func (c *kialiCacheImpl) isEvicted(namespace string) bool {
+	c.usageLock.Lock()
+	defer c.usageLock.Unlock()
+	until, ok := c.evicted[namespace]
+	if ok && !time.Now().Before(until) {
+		delete(c.evicted, namespace)
+		return false
+	}
+	return ok
+}
+
+// evict drops the cache of a namespace, which is not cached again until the next resync period. Otherwise, the next
+// request would create its cache again and evict another namespace. It must be called holding the cacheLock.
+func (c *kialiCacheImpl) evict(namespace string) {
+	duration := c.refreshDuration
+	if duration <= 0 {
+		duration = defaultEvictionDuration
+	}
+	c.usageLock.Lock()
+	if c.evicted == nil {
+		c.evicted = make(map[string]time.Time)
+	}
+	c.evicted[namespace] = time.Now().Add(duration)
+	c.usageLock.Unlock()
+	c.deleteCache(namespace)
+}
+
+// countObjects returns the number of cached objects of each kind, per namespace
+func (c *kialiCacheImpl) countObjects() map[string]map[string]int {
+	c.nsCacheLock.RLock()
+	defer c.nsCacheLock.RUnlock()
+	counts := make(map[string]map[string]int, len(c.nsCache))
+	for namespace, informers := range c.nsCache {
+		counts[namespace] = make(map[string]int, len(informers))
+		for informerType, informer := range informers {
+			counts[namespace][informerGVK(informerType).Kind] = len(informer.GetStore().ListKeys())
+		}
+	}
+	return counts
+}
+
+// enforceLimits drops the caches of the least recently used namespaces while any kind exceeds its object limit.
+// The cache of the namespace that was just created is dropped last, when it exceeds the limits on its own. The
+// evicted namespaces are served from the API until the next resync period. It must be called holding the cacheLock.
+func (c *kialiCacheImpl) enforceLimits(created string) {
+	if len(c.objectLimits) == 0 {
+		return
+	}
+
+	counts := c.countObjects()
+	exceeded := func() string {
+		for kind, limit := range c.objectLimits {
+			total := 0
+			for _, nsCounts := range counts {
+				total += nsCounts[kind]
+			}
+			if total > limit {
+				return kind
+			}
+		}
+		return ""
+	}
+
+	c.usageLock.Lock()
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		if namespace != created {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return c.lastUsed[namespaces[i]].Before(c.lastUsed[namespaces[j]])
+	})
+	c.usageLock.Unlock()
+	namespaces = append(namespaces, created)
+
+	for _, namespace := range namespaces {
+		kind := exceeded()
+		if kind == "" {
+			return
+		}
+		if counts[namespace][kind] == 0 {
+			continue
+		}
+		if namespace == created {
+			log.Warningf("Kiali cache for [namespace: %s] exceeds the limit of %d objects of kind [%s], the namespace won't be cached until the next resync", namespace, c.objectLimits[kind], kind)
+		} else {
+			log.Infof("Kiali cache exceeds the limit of %d objects of kind [%s], dropping the cache of the least recently used [namespace: %s] until the next resync", c.objectLimits[kind], kind, namespace)
+		}
+		c.evict(namespace)
+		delete(counts, namespace)
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func fakePodInformer(namespace string, pods int) fakeInformer {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for i := 0; i < pods; i++ {
+		_ = store.Add(&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: namespace}})
+	}
+	return fakeInformer{store: store}
+}
+
+func newLimitedCache(limits map[string]int) *kialiCacheImpl {
+	return &kialiCacheImpl{
+		stopChan:     make(map[string]chan struct{}),
+		nsCache:      make(map[string]typeCache),
+		objectLimits: limits,
+		lastUsed:     make(map[string]time.Time),
+		evicted:      make(map[string]time.Time),
+	}
+}
+
+func TestEnforceLimitsEvictsLeastRecentlyUsed(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newLimitedCache(map[string]int{"Pod": 5})
+	c.nsCache["old"] = typeCache{kubernetes.PodType: fakePodInformer("old", 2)}
+	c.nsCache["recent"] = typeCache{kubernetes.PodType: fakePodInformer("recent", 2)}
+	c.nsCache["new"] = typeCache{kubernetes.PodType: fakePodInformer("new", 2)}
+	c.lastUsed["old"] = time.Now().Add(-time.Hour)
+	c.lastUsed["recent"] = time.Now().Add(-time.Minute)
+	c.touchNamespace("new")
+
+	c.enforceLimits("new")
+
+	_, old := c.getNamespaceCache("old")
+	_, recent := c.getNamespaceCache("recent")
+	_, created := c.getNamespaceCache("new")
+	assert.False(old)
+	assert.True(recent)
+	assert.True(created)
+	assert.True(c.isEvicted("old"))
+	assert.False(c.isEvicted("recent"))
+	assert.False(c.isEvicted("new"))
+}
+
+func TestEnforceLimitsOversizedNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newLimitedCache(map[string]int{"Pod": 5})
+	c.nsCache["small"] = typeCache{kubernetes.PodType: fakePodInformer("small", 1)}
+	c.nsCache["huge"] = typeCache{kubernetes.PodType: fakePodInformer("huge", 10)}
+	c.touchNamespace("small")
+	c.touchNamespace("huge")
+
+	c.enforceLimits("huge")
+
+	_, small := c.getNamespaceCache("small")
+	_, huge := c.getNamespaceCache("huge")
+	assert.False(small)
+	assert.False(huge)
+	assert.True(c.isEvicted("small"))
+	assert.True(c.isEvicted("huge"))
+}
+
+func TestEvictedNamespaceExpires(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newLimitedCache(map[string]int{"Pod": 5})
+	c.refreshDuration = time.Minute
+	c.nsCache["bookinfo"] = typeCache{kubernetes.PodType: fakePodInformer("bookinfo", 1)}
+
+	c.evict("bookinfo")
+	_, exist := c.getNamespaceCache("bookinfo")
+	assert.False(exist)
+	assert.True(c.isEvicted("bookinfo"))
+
+	c.evicted["bookinfo"] = time.Now().Add(-time.Second)
+	assert.False(c.isEvicted("bookinfo"))
+	assert.NotContains(c.evicted, "bookinfo")
+}
+
+func TestEnforceLimitsIgnoresOtherKinds(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newLimitedCache(map[string]int{"Service": 1})
+	c.nsCache["bookinfo"] = typeCache{kubernetes.PodType: fakePodInformer("bookinfo", 10)}
+
+	c.enforceLimits("bookinfo")
+
+	_, exist := c.getNamespaceCache("bookinfo")
+	assert.True(exist)
+}
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+type (
+	NotifyCache interface {
+		// GetObjectVersion returns the UID and resourceVersion of a cached object.
+		// It returns false if the object, or its kind, is not cached.
+		GetObjectVersion(namespace, informerType, name string) (types.UID, string, bool)
+		// WaitForObject blocks until the informer of the kind delivers a version of the object matching the
+		// condition, or the timeout expires. It returns false only when the timeout expires; objects of
+		// namespaces or kinds not cached have nothing to wait for.
+		WaitForObject(namespace, informerType, name string, condition ObjectCondition, timeout time.Duration) bool
+	}
+
+	// ObjectCondition checks the version of an object delivered by an informer. obj is nil when the object
+	// doesn't exist.
+	ObjectCondition func(obj meta_v1.Object) bool
+
+	objectWaiter struct {
+		condition ObjectCondition
+		done      chan struct{}
+	}
+)
+
+// ObjectVersion is satisfied once the cache holds the version of the object returned by a create or an update.
+// Later versions of the object satisfy it too, when resource versions are comparable.
+func ObjectVersion(uid types.UID, resourceVersion string) ObjectCondition {
+	return func(obj meta_v1.Object) bool {
+		return obj != nil && obj.GetUID() == uid && !isOlderVersion(obj.GetResourceVersion(), resourceVersion)
+	}
+}
+
+// ObjectChanged is satisfied once the cache holds a version of the object other than the stale one, read before
+// a patch whose result is unknown
+func ObjectChanged(uid types.UID, staleVersion string) ObjectCondition {
+	return func(obj meta_v1.Object) bool {
+		return obj == nil || obj.GetUID() != uid || obj.GetResourceVersion() != staleVersion
+	}
+}
+
+// ObjectDeleted is satisfied once the object is removed from the cache
+func ObjectDeleted() ObjectCondition {
+	return func(obj meta_v1.Object) bool {
+		return obj == nil
+	}
+}
+
+// isOlderVersion compares two resource versions. Resource versions are opaque, so only versions encoded as
+// numbers, as done by etcd, are compared; other versions are only checked for equality.
+func isOlderVersion(version, target string) bool {
+	if version == target {
+		return false
+	}
+	v, vErr := strconv.ParseUint(version, 10, 64)
+	t, tErr := strconv.ParseUint(target, 10, 64)
+	return vErr != nil || tErr != nil || v < t
+}
+
+func waiterKey(namespace, informerType, name string) string {
+	return informerType + ":" + namespace + "/" + name
+}
+
+// notifyHandler returns the handler of the events of an informer, which wakes up the writes waiting for them
+func (c *kialiCacheImpl) notifyHandler(namespace, informerType string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.notify(namespace, informerType, obj, false)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.notify(namespace, informerType, newObj, false)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			c.notify(namespace, informerType, obj, true)
+		},
+	}
+}
+
+func (c *kialiCacheImpl) notify(namespace, informerType string, obj interface{}, deleted bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	key := waiterKey(namespace, informerType, accessor.GetName())
+
+	c.waitersLock.Lock()
+	defer c.waitersLock.Unlock()
+	var current meta_v1.Object
+	if !deleted {
+		current = accessor
+	}
+	pending := []*objectWaiter{}
+	ready := []*objectWaiter{}
+	for _, waiter := range c.waiters[key] {
+		if waiter.condition(current) {
+			ready = append(ready, waiter)
+		} else {
+			pending = append(pending, waiter)
+		}
+	}
+	if len(pending) == 0 {
+		delete(c.waiters, key)
+	} else {
+		c.waiters[key] = pending
+	}
+	for _, waiter := range ready {
+		close(waiter.done)
+	}
+}
+
+// getCachedObject returns the object of the cache, nil if it doesn't exist, and whether the kind is cached
+func (c *kialiCacheImpl) getCachedObject(namespace, informerType, name string) (meta_v1.Object, bool) {
+	informers, exist := c.getNamespaceCache(namespace)
+	if !exist {
+		return nil, false
+	}
+	informer, exist := informers[informerType]
+	if !exist {
+		return nil, false
+	}
+	obj, found, err := informer.GetStore().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil, true
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, true
+	}
+	return accessor, true
+}
+
+func (c *kialiCacheImpl) GetObjectVersion(namespace, informerType, name string) (types.UID, string, bool) {
+	if obj, _ := c.getCachedObject(namespace, informerType, name); obj != nil {
+		return obj.GetUID(), obj.GetResourceVersion(), true
+	}
+	return "", "", false
+}
+
+func (c *kialiCacheImpl) WaitForObject(namespace, informerType, name string, condition ObjectCondition, timeout time.Duration) bool {
+	if _, cached := c.getCachedObject(namespace, informerType, name); !cached {
+		return true
+	}
+
+	// The waiter is registered before the store is checked, so an event delivered in between is not lost
+	key := waiterKey(namespace, informerType, name)
+	waiter := &objectWaiter{condition: condition, done: make(chan struct{})}
+	c.waitersLock.Lock()
+	if c.waiters == nil {
+		c.waiters = make(map[string][]*objectWaiter)
+	}
+	c.waiters[key] = append(c.waiters[key], waiter)
+	c.waitersLock.Unlock()
+
+	if obj, _ := c.getCachedObject(namespace, informerType, name); condition(obj) {
+		c.removeWaiter(key, waiter)
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-waiter.done:
+		return true
+	case <-timer.C:
+		c.removeWaiter(key, waiter)
+		return false
+	}
+}
+
+func (c *kialiCacheImpl) removeWaiter(key string, waiter *objectWaiter) {
+	c.waitersLock.Lock()
+	defer c.waitersLock.Unlock()
+	for i, w := range c.waiters[key] {
+		if w == waiter {
+			c.waiters[key] = append(c.waiters[key][:i], c.waiters[key][i+1:]...)
+			break
+		}
+	}
+	if len(c.waiters[key]) == 0 {
+		delete(c.waiters, key)
+	}
+}
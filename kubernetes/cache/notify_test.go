@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func fakeNotifyCache(pods ...*core_v1.Pod) (*kialiCacheImpl, cache.Store) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, pod := range pods {
+		_ = store.Add(pod)
+	}
+	c := &kialiCacheImpl{nsCache: map[string]typeCache{
+		"bookinfo": {kubernetes.PodType: fakeInformer{store: store}},
+	}}
+	return c, store
+}
+
+func fakePod(uid types.UID, resourceVersion string) *core_v1.Pod {
+	return &core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo", UID: uid, ResourceVersion: resourceVersion}}
+}
+
+func TestWaitForObjectAlreadyCached(t *testing.T) {
+	assert := assert.New(t)
+
+	c, _ := fakeNotifyCache(fakePod("1234", "10"))
+	assert.True(c.WaitForObject("bookinfo", kubernetes.PodType, "reviews-v1", ObjectVersion("1234", "10"), time.Second))
+	assert.True(c.WaitForObject("bookinfo", kubernetes.PodType, "reviews-v1", ObjectVersion("1234", "9"), time.Second))
+	assert.Empty(c.waiters)
+}
+
+func TestWaitForObjectNotified(t *testing.T) {
+	assert := assert.New(t)
+
+	c, store := fakeNotifyCache(fakePod("1234", "10"))
+	handler := c.notifyHandler("bookinfo", kubernetes.PodType)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		updated := fakePod("1234", "11")
+		_ = store.Update(updated)
+		handler.OnUpdate(fakePod("1234", "10"), updated)
+	}()
+	assert.True(c.WaitForObject("bookinfo", kubernetes.PodType, "reviews-v1", ObjectVersion("1234", "11"), 5*time.Second))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		deleted := fakePod("1234", "11")
+		_ = store.Delete(deleted)
+		handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "bookinfo/reviews-v1", Obj: deleted})
+	}()
+	assert.True(c.WaitForObject("bookinfo", kubernetes.PodType, "reviews-v1", ObjectDeleted(), 5*time.Second))
+	assert.Empty(c.waiters)
+}
+
+func TestWaitForObjectTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	c, _ := fakeNotifyCache(fakePod("1234", "10"))
+	uid, version, ok := c.GetObjectVersion("bookinfo", kubernetes.PodType, "reviews-v1")
+	assert.True(ok)
+	assert.Equal("10", version)
+
+	assert.False(c.WaitForObject("bookinfo", kubernetes.PodType, "reviews-v1", ObjectChanged(uid, version), 10*time.Millisecond))
+	assert.Empty(c.waiters)
+}
+
+func TestWaitForObjectNotCached(t *testing.T) {
+	assert := assert.New(t)
+
+	c, _ := fakeNotifyCache()
+	assert.True(c.WaitForObject("travels", kubernetes.PodType, "reviews-v1", ObjectDeleted(), time.Minute))
+	assert.True(c.WaitForObject("bookinfo", kubernetes.DeploymentType, "reviews-v1", ObjectDeleted(), time.Minute))
+	_, _, ok := c.GetObjectVersion("bookinfo", kubernetes.PodType, "reviews-v1")
+	assert.False(ok)
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	kialiConfig "github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+const (
+	redisTimeout      = 2 * time.Second
+	redisMaxIdleConns = 10
+)
+
+// errRedisNil is returned when the Redis server replies with a null value, i.e. the key doesn't exist
+var errRedisNil = errors.New("redis: nil")
+
+// redisResultsCache is a ResultsCache backed by a Redis (or compatible) server, shared by the replicas of Kiali.
+// Only GET and SET are needed, so it speaks the Redis protocol (RESP) directly over a small pool of connections.
+type redisResultsCache struct {
+	conf  kialiConfig.RedisConfig
+	conns chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRedisResultsCache(conf kialiConfig.RedisConfig) (*redisResultsCache, error) {
+	if conf.Address == "" {
+		return nil, errors.New("the address of the Redis server of the results cache is required")
+	}
+	c := &redisResultsCache{
+		conf:  conf,
+		conns: make(chan *redisConn, redisMaxIdleConns),
+	}
+
+	// Check the server is reachable and the credentials are valid on start
+	rc, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.release(rc, nil)
+	return c, nil
+}
+
+func (c *redisResultsCache) Get(key string, value interface{}) bool {
+	reply, err := c.do("GET", c.conf.KeyPrefix+key)
+	if err != nil {
+		if err != errRedisNil {
+			log.Warningf("Cannot read [%s] from the results cache: %v", key, err)
+		}
+		return false
+	}
+	if err := json.Unmarshal([]byte(reply), value); err != nil {
+		log.Warningf("Cannot read [%s] from the results cache: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (c *redisResultsCache) Set(key string, value interface{}, ttl time.Duration) {
+	// As in the memory cache, a value without TTL is expired right away: it is not stored, Redis would keep it
+	// forever otherwise
+	if ttl.Milliseconds() <= 0 {
+		return
+	}
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		log.Warningf("Cannot store [%s] in the results cache: %v", key, err)
+		return
+	}
+	if _, err := c.do("SET", c.conf.KeyPrefix+key, string(rawValue), "PX", strconv.FormatInt(ttl.Milliseconds(), 10)); err != nil {
+		log.Warningf("Cannot store [%s] in the results cache: %v", key, err)
+	}
+}
+
+// do sends a command to the server and returns its reply
+func (c *redisResultsCache) do(args ...string) (string, error) {
+	rc, err := c.acquire()
+	if err != nil {
+		return "", err
+	}
+	reply, err := rc.do(args...)
+	c.release(rc, err)
+	return reply, err
+}
+
+func (c *redisResultsCache) acquire() (*redisConn, error) {
+	select {
+	case rc := <-c.conns:
+		return rc, nil
+	default:
+		return c.dial()
+	}
+}
+
+// release returns the connection to the pool, unless it failed or the pool is full
+func (c *redisResultsCache) release(rc *redisConn, err error) {
+	if err != nil && err != errRedisNil {
+		// Server errors leave the connection usable, network errors don't
+		if _, isServerError := err.(redisServerError); !isServerError {
+			rc.conn.Close()
+			return
+		}
+	}
+	select {
+	case c.conns <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+func (c *redisResultsCache) dial() (*redisConn, error) {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: redisTimeout}
+	if c.conf.UseTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.conf.Address, &tls.Config{InsecureSkipVerify: c.conf.InsecureSkipVerify})
+	} else {
+		conn, err = dialer.Dial("tcp", c.conf.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	if c.conf.Password != "" {
+		if _, err := rc.do("AUTH", c.conf.Password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot authenticate to the Redis server: %v", err)
+		}
+	}
+	if c.conf.Database != 0 {
+		if _, err := rc.do("SELECT", strconv.Itoa(c.conf.Database)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot select database [%d] of the Redis server: %v", c.conf.Database, err)
+		}
+	}
+	return rc, nil
+}
+
+// redisServerError is an error reply of the server
+type redisServerError string
+
+func (e redisServerError) Error() string {
+	return string(e)
+}
+
+// do writes the command as a RESP array of bulk strings and reads the reply
+func (rc *redisConn) do(args ...string) (string, error) {
+	if err := rc.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(rc.conn, sb.String()); err != nil {
+		return "", err
+	}
+
+	line, err := rc.readLine()
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 {
+		return "", errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", redisServerError(line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("redis: invalid bulk size [%s]", line[1:])
+		}
+		if size < 0 {
+			return "", errRedisNil
+		}
+		buf := make([]byte, size+2) // Value followed by \r\n
+		if _, err := io.ReadFull(rc.reader, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:size]), nil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply [%s]", line)
+	}
+}
+
+func (rc *redisConn) readLine() (string, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	kialiConfig "github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// ResultsCache stores results computed by Kiali, like validations and health, so they are not recomputed on
+// every request. Values are stored as JSON, so a backend can be shared by several replicas of Kiali.
+// Callers are in charge of building keys that change when the inputs of the result change.
+type ResultsCache interface {
+	// Get reads the value stored for the key into value. It returns false if there is no value or it can't be read.
+	Get(key string, value interface{}) bool
+	// Set stores the value for the key for the given time. Failures are logged but not reported, as the
+	// value can always be recomputed.
+	Set(key string, value interface{}, ttl time.Duration)
+}
+
+// NewResultsCache creates the results cache for the configured backend. It returns nil when the cache is disabled.
+func NewResultsCache(conf kialiConfig.ResultsCacheConfig) (ResultsCache, error) {
+	switch conf.Backend {
+	case "", kialiConfig.ResultsCacheBackendNone:
+		return nil, nil
+	case kialiConfig.ResultsCacheBackendMemory:
+		log.Infof("Kiali results cache is stored in memory")
+		return countingResultsCache{newMemoryResultsCache()}, nil
+	case kialiConfig.ResultsCacheBackendRedis:
+		log.Infof("Kiali results cache is stored in Redis at [%s]", conf.Redis.Address)
+		redisCache, err := newRedisResultsCache(conf.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return countingResultsCache{redisCache}, nil
+	default:
+		return nil, fmt.Errorf("unknown results cache backend [%s]", conf.Backend)
+	}
+}
+
+// countingResultsCache counts the hits and misses of a ResultsCache
+type countingResultsCache struct {
+	ResultsCache
+}
+
+func (c countingResultsCache) Get(key string, value interface{}) bool {
+	hit := c.ResultsCache.Get(key, value)
+	recordCacheRequest(ResultsCacheName, hit)
+	return hit
+}
+
+type memoryResultsEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// memoryResultsCache is a ResultsCache local to this Kiali replica
+type memoryResultsCache struct {
+	lock    sync.RWMutex
+	entries map[string]memoryResultsEntry
+}
+
+func newMemoryResultsCache() *memoryResultsCache {
+	return &memoryResultsCache{entries: make(map[string]memoryResultsEntry)}
+}
+
+func (c *memoryResultsCache) Get(key string, value interface{}) bool {
+	c.lock.RLock()
+	entry, ok := c.entries[key]
+	c.lock.RUnlock()
+	if !ok || time.Now().After(entry.expires) {
+		return false
+	}
+	if err := json.Unmarshal(entry.value, value); err != nil {
+		log.Warningf("Cannot read [%s] from the results cache: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (c *memoryResultsCache) Set(key string, value interface{}, ttl time.Duration) {
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		log.Warningf("Cannot store [%s] in the results cache: %v", key, err)
+		return
+	}
+
+	now := time.Now()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// Keys change when the inputs change, so old entries are never read again: drop the expired ones
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memoryResultsEntry{value: rawValue, expires: now.Add(ttl)}
+}
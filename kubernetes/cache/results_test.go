@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	kialiConfig "github.com/kiali/kiali/config"
+)
+
+type resultsValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestMemoryResultsCache(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newMemoryResultsCache()
+	var value resultsValue
+	assert.False(c.Get("key", &value))
+
+	c.Set("key", resultsValue{Name: "reviews", Count: 3}, time.Minute)
+	assert.True(c.Get("key", &value))
+	assert.Equal(resultsValue{Name: "reviews", Count: 3}, value)
+
+	c.Set("expired", resultsValue{Name: "ratings"}, -time.Second)
+	assert.False(c.Get("expired", &value))
+	c.Set("other", resultsValue{Name: "details"}, time.Minute)
+	assert.NotContains(c.entries, "expired")
+}
+
+func TestNewResultsCacheBackends(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewResultsCache(kialiConfig.ResultsCacheConfig{Backend: kialiConfig.ResultsCacheBackendNone})
+	assert.NoError(err)
+	assert.Nil(c)
+
+	c, err = NewResultsCache(kialiConfig.ResultsCacheConfig{Backend: kialiConfig.ResultsCacheBackendMemory})
+	assert.NoError(err)
+	assert.NotNil(c)
+
+	_, err = NewResultsCache(kialiConfig.ResultsCacheConfig{Backend: kialiConfig.ResultsCacheBackendRedis})
+	assert.Error(err)
+
+	_, err = NewResultsCache(kialiConfig.ResultsCacheConfig{Backend: "memcached"})
+	assert.Error(err)
+}
+
+// fakeRedis is a minimal Redis server supporting the commands used by the results cache
+type fakeRedis struct {
+	lock     sync.Mutex
+	password string
+	values   map[string]string
+}
+
+func startFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{password: password, values: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server, listener.Addr().String()
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		s.lock.Lock()
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			if args[1] == s.password {
+				authenticated = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required\r\n"
+		case args[0] == "SET" && len(args) == 5 && args[3] == "PX" && !isPositive(args[4]):
+			reply = "-ERR invalid expire time in 'set' command\r\n"
+		case args[0] == "SET":
+			s.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if value, ok := s.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.lock.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func isPositive(number string) bool {
+	n, err := strconv.Atoi(number)
+	return err == nil && n > 0
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func TestRedisResultsCache(t *testing.T) {
+	assert := assert.New(t)
+	server, address := startFakeRedis(t, "secret")
+
+	c, err := newRedisResultsCache(kialiConfig.RedisConfig{Address: address, KeyPrefix: "kiali:", Password: "secret"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	var value resultsValue
+	assert.False(c.Get("key", &value))
+
+	c.Set("key", resultsValue{Name: "reviews", Count: 3}, time.Minute)
+	assert.True(c.Get("key", &value))
+	assert.Equal(resultsValue{Name: "reviews", Count: 3}, value)
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	assert.Contains(server.values, "kiali:key")
+}
+
+func TestRedisResultsCacheWithoutTTL(t *testing.T) {
+	assert := assert.New(t)
+	server, address := startFakeRedis(t, "")
+
+	c, err := newRedisResultsCache(kialiConfig.RedisConfig{Address: address})
+	if !assert.NoError(err) {
+		return
+	}
+
+	var value resultsValue
+	c.Set("key", resultsValue{Name: "reviews", Count: 3}, 0)
+	assert.False(c.Get("key", &value))
+	assert.NotContains(server.values, "key")
+}
+
+func TestRedisResultsCacheWrongPassword(t *testing.T) {
+	_, address := startFakeRedis(t, "secret")
+
+	_, err := newRedisResultsCache(kialiConfig.RedisConfig{Address: address, Password: "wrong"})
+	assert.Error(t, err)
+}
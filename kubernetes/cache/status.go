@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	kialiConfig "github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Names of the caches whose hits and misses are counted
+const (
+	KubernetesCacheName = "kubernetes"
+	ResultsCacheName    = "results"
+)
+
+// statusMetricsPeriod is how often the metrics of the informers are refreshed
+const statusMetricsPeriod = time.Minute
+
+type (
+	StatusCache interface {
+		// GetStatus returns the status of the informers of the cache
+		GetStatus() models.ClusterCacheStatus
+		// GetWarmingNamespaces returns the namespaces whose cache is being warmed up in the background
+		GetWarmingNamespaces() []string
+	}
+
+	cacheCounter struct {
+		hits   uint64
+		misses uint64
+	}
+)
+
+var cacheCounters = map[string]*cacheCounter{
+	KubernetesCacheName: {},
+	ResultsCacheName:    {},
+}
+
+// recordCacheRequest counts a request to one of the caches, for the debug endpoint and the internal metrics
+func recordCacheRequest(cache string, hit bool) {
+	if hit {
+		atomic.AddUint64(&cacheCounters[cache].hits, 1)
+	} else {
+		atomic.AddUint64(&cacheCounters[cache].misses, 1)
+	}
+	internalmetrics.IncCacheRequests(cache, hit)
+}
+
+// GetCacheCounters returns the hits and misses of the caches since Kiali started
+func GetCacheCounters() map[string]models.CacheCounters {
+	counters := make(map[string]models.CacheCounters, len(cacheCounters))
+	for name, counter := range cacheCounters {
+		counters[name] = models.CacheCounters{
+			Hits:   atomic.LoadUint64(&counter.hits),
+			Misses: atomic.LoadUint64(&counter.misses),
+		}
+	}
+	return counters
+}
+
+// informerGVK returns the group, version and kind of the objects watched by an informer of the cache.
+// Kubernetes informers are registered by kind, Istio informers by resource name.
+func informerGVK(informerType string) schema.GroupVersionKind {
+	switch informerType {
+	case kubernetes.DeploymentType, kubernetes.ReplicaSetType, kubernetes.StatefulSetType:
+		return schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: informerType}
+	}
+	if group, ok := kubernetes.ResourceTypesToAPI[informerType]; ok {
+		gv, _ := schema.ParseGroupVersion(kubernetes.ApiToVersion[group])
+		return gv.WithKind(kubernetes.PluralType[informerType])
+	}
+	return schema.GroupVersionKind{Version: "v1", Kind: informerType}
+}
+
+// estimateSize returns an approximation of the memory used by an object of the cache. Kubernetes types
+// provide the size of their protobuf encoding; the size of the JSON encoding is used for other types.
+func estimateSize(obj interface{}) int64 {
+	if sized, ok := obj.(interface{ Size() int }); ok {
+		return int64(sized.Size())
+	}
+	if raw, err := json.Marshal(obj); err == nil {
+		return int64(len(raw))
+	}
+	return 0
+}
+
+// cachedInformer is an informer of the cache, with its namespace and type
+type cachedInformer struct {
+	namespace    string
+	informerType string
+	informer     cache.SharedIndexInformer
+	since        time.Time
+}
+
+func (c *kialiCacheImpl) GetStatus() models.ClusterCacheStatus {
+	// The informers are listed under the lock, and measured out of it: the writers of the cache must not wait for
+	// the encoding of all the objects
+	informers := []cachedInformer{}
+	c.nsCacheLock.RLock()
+	for namespace, nsInformers := range c.nsCache {
+		for informerType, informer := range nsInformers {
+			informers = append(informers, cachedInformer{namespace: namespace, informerType: informerType, informer: informer, since: c.nsCacheCreated[namespace]})
+		}
+	}
+	c.nsCacheLock.RUnlock()
+
+	status := models.ClusterCacheStatus{
+		Cluster:   kialiConfig.Get().KubernetesConfig.ClusterName,
+		Cached:    true,
+		Informers: []models.InformerStatus{},
+		Warming:   c.GetWarmingNamespaces(),
+	}
+	for _, cached := range informers {
+		gvk := informerGVK(cached.informerType)
+		objects := cached.informer.GetStore().List()
+		informerStatus := models.InformerStatus{
+			Namespace:               cached.namespace,
+			Group:                   gvk.Group,
+			Version:                 gvk.Version,
+			Kind:                    gvk.Kind,
+			Since:                   cached.since,
+			Synced:                  cached.informer.HasSynced(),
+			LastSyncResourceVersion: cached.informer.LastSyncResourceVersion(),
+			Objects:                 len(objects),
+		}
+		for _, obj := range objects {
+			informerStatus.EstimatedBytes += estimateSize(obj)
+		}
+		status.Informers = append(status.Informers, informerStatus)
+	}
+	sort.Slice(status.Informers, func(i, j int) bool {
+		if status.Informers[i].Namespace != status.Informers[j].Namespace {
+			return status.Informers[i].Namespace < status.Informers[j].Namespace
+		}
+		return status.Informers[i].Kind < status.Informers[j].Kind
+	})
+	return status
+}
+
+// updateStatusMetrics periodically exposes the status of the informers as internal metrics
+func (c *kialiCacheImpl) updateStatusMetrics(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(statusMetricsPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			status := c.GetStatus()
+			internalmetrics.ResetCacheInformerStatus()
+			for _, informer := range status.Informers {
+				internalmetrics.SetCacheInformerStatus(status.Cluster, informer.Namespace, informer.Kind, informer.Synced, informer.Objects, informer.EstimatedBytes)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kialiConfig "github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func TestInformerGVK(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("apps/v1, Kind=Deployment", informerGVK(kubernetes.DeploymentType).String())
+	assert.Equal("/v1, Kind=Pod", informerGVK(kubernetes.PodType).String())
+	assert.Equal("networking.istio.io/v1alpha3, Kind=VirtualService", informerGVK(kubernetes.VirtualServices).String())
+	assert.Equal("security.istio.io/v1beta1, Kind=AuthorizationPolicy", informerGVK(kubernetes.AuthorizationPolicies).String())
+}
+
+// fakeInformer is a synced informer holding the objects of a store
+type fakeInformer struct {
+	cache.SharedIndexInformer
+	store           cache.Store
+	resourceVersion string
+}
+
+func (f fakeInformer) GetStore() cache.Store {
+	return f.store
+}
+
+func (f fakeInformer) HasSynced() bool {
+	return true
+}
+
+func (f fakeInformer) LastSyncResourceVersion() string {
+	return f.resourceVersion
+}
+
+func TestGetStatus(t *testing.T) {
+	assert := assert.New(t)
+	kialiConfig.Set(kialiConfig.NewConfig())
+
+	pod := &core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo"}}
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.NoError(store.Add(pod))
+	podInformer := fakeInformer{store: store, resourceVersion: "1234"}
+
+	c := kialiCacheImpl{nsCache: map[string]typeCache{
+		"bookinfo": {kubernetes.PodType: podInformer},
+	}}
+	status := c.GetStatus()
+	assert.Equal("Kubernetes", status.Cluster)
+	assert.True(status.Cached)
+	assert.Len(status.Informers, 1)
+	assert.Equal("bookinfo", status.Informers[0].Namespace)
+	assert.Equal("Pod", status.Informers[0].Kind)
+	assert.True(status.Informers[0].Synced)
+	assert.Equal("1234", status.Informers[0].LastSyncResourceVersion)
+	assert.Equal(1, status.Informers[0].Objects)
+	assert.Equal(int64(pod.Size()), status.Informers[0].EstimatedBytes)
+}
+
+func TestCacheCounters(t *testing.T) {
+	assert := assert.New(t)
+
+	before := GetCacheCounters()[ResultsCacheName]
+	c := countingResultsCache{newMemoryResultsCache()}
+	var value string
+	c.Get("key", &value)
+	c.Set("key", "value", time.Minute)
+	c.Get("key", &value)
+
+	after := GetCacheCounters()[ResultsCacheName]
+	assert.Equal(before.Hits+1, after.Hits)
+	assert.Equal(before.Misses+1, after.Misses)
+}
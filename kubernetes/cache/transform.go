@@ -0,0 +1,65 @@
+package cache
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// lastAppliedConfigAnnotation holds a full copy of the object applied with kubectl, never read by Kiali
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// transformObject removes from an object the fields that Kiali never reads, to reduce the memory used by the cache
+func transformObject(obj runtime.Object) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+		if annotations := accessor.GetAnnotations(); annotations[lastAppliedConfigAnnotation] != "" {
+			delete(annotations, lastAppliedConfigAnnotation)
+			accessor.SetAnnotations(annotations)
+		}
+	}
+
+	// Kinds whose status is never read
+	switch o := obj.(type) {
+	case *core_v1.Service:
+		o.Status = core_v1.ServiceStatus{}
+	}
+}
+
+// transformingListWatch applies transformObject to the objects listed and watched by an informer, before they
+// are stored in the cache
+type transformingListWatch struct {
+	listWatch cache.ListerWatcher
+}
+
+func newTransformingListWatch(listWatch cache.ListerWatcher) cache.ListerWatcher {
+	return transformingListWatch{listWatch: listWatch}
+}
+
+func (t transformingListWatch) List(options meta_v1.ListOptions) (runtime.Object, error) {
+	list, err := t.listWatch.List(options)
+	if err != nil {
+		return nil, err
+	}
+	err = meta.EachListItem(list, func(obj runtime.Object) error {
+		transformObject(obj)
+		return nil
+	})
+	return list, err
+}
+
+func (t transformingListWatch) Watch(options meta_v1.ListOptions) (watch.Interface, error) {
+	w, err := t.listWatch.Watch(options)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		if event.Object != nil {
+			transformObject(event.Object)
+		}
+		return event, true
+	}), nil
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformObject(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews",
+			Namespace: "bookinfo",
+			Annotations: map[string]string{
+				lastAppliedConfigAnnotation: "{\"kind\":\"Service\"}",
+				"kiali.io/api-spec":         "https://petstore.swagger.io/v2/swagger.json",
+			},
+			ManagedFields: []meta_v1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: core_v1.ServiceSpec{ClusterIP: "10.0.0.1"},
+		Status: core_v1.ServiceStatus{
+			LoadBalancer: core_v1.LoadBalancerStatus{Ingress: []core_v1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+		},
+	}
+	transformObject(svc)
+
+	assert.Nil(svc.ManagedFields)
+	assert.NotContains(svc.Annotations, lastAppliedConfigAnnotation)
+	assert.Equal("https://petstore.swagger.io/v2/swagger.json", svc.Annotations["kiali.io/api-spec"])
+	assert.Equal("10.0.0.1", svc.Spec.ClusterIP)
+	assert.Empty(svc.Status.LoadBalancer.Ingress)
+}
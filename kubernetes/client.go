@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"strings"
@@ -12,7 +13,9 @@ import (
 	osproject_v1 "github.com/openshift/api/project/v1"
 	osroutes_v1 "github.com/openshift/api/route/v1"
 	apps_v1 "k8s.io/api/apps/v1"
+	authn_v1 "k8s.io/api/authentication/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
+	autoscaling_v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
@@ -47,9 +50,14 @@ type IstioClientInterface interface {
 	UpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) (IstioObject, error)
 	GetProxyStatus() ([]*ProxyStatus, error)
 	GetConfigDump(namespace, podName string) (*ConfigDump, error)
+	GetEnvoyStats(namespace, podName, filter string) (*EnvoyStats, error)
+	GetZtunnelConfigDump(namespace, podName string) (*ZtunnelConfigDump, error)
 }
 
 type K8SClientInterface interface {
+	CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
+	CreateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error)
+	EvictPod(namespace, name string) error
 	GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error)
 	GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error)
 	GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error)
@@ -58,21 +66,31 @@ type K8SClientInterface interface {
 	GetDeploymentConfig(namespace string, deploymentconfigName string) (*osapps_v1.DeploymentConfig, error)
 	GetDeploymentConfigs(namespace string) ([]osapps_v1.DeploymentConfig, error)
 	GetEndpoints(namespace string, serviceName string) (*core_v1.Endpoints, error)
+	GetEvents(namespace string) ([]core_v1.Event, error)
+	GetHorizontalPodAutoscalers(namespace string) ([]autoscaling_v2beta2.HorizontalPodAutoscaler, error)
 	GetJobs(namespace string) ([]batch_v1.Job, error)
 	GetNamespace(namespace string) (*core_v1.Namespace, error)
 	GetNamespaces(labelSelector string) ([]core_v1.Namespace, error)
 	GetPod(namespace, name string) (*core_v1.Pod, error)
 	GetPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (*PodLogs, error)
+	StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error)
 	GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 	GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error)
 	GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
+	GetRollouts(namespace string) ([]Rollout, error)
+	GetScaledObjects(namespace string) ([]ScaledObject, error)
+	GetSecret(namespace, name string) (*core_v1.Secret, error)
 	GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error)
 	GetService(namespace string, serviceName string) (*core_v1.Service, error)
+	GetTokenReview(token string) (*authn_v1.TokenReview, error)
 	GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
 	GetStatefulSet(namespace string, statefulsetName string) (*apps_v1.StatefulSet, error)
 	GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error)
+	GetVerticalPodAutoscalers(namespace string) ([]VerticalPodAutoscaler, error)
+	UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
 	UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error)
-	UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error
+	UpdateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error)
+	UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) (string, error)
 }
 
 type OSClientInterface interface {
@@ -84,6 +102,7 @@ type OSClientInterface interface {
 
 // ClientInterface for mocks (only mocked function are necessary here)
 type ClientInterface interface {
+	GetServerResources(groupVersion string) (*meta_v1.APIResourceList, error)
 	GetServerVersion() (*version.Info, error)
 	GetToken() string
 	IsOpenShift() bool
@@ -112,6 +131,10 @@ type K8SClient struct {
 	// See iter8.go#IsIter8Api() for more details
 	isIter8Api *bool
 
+	// isRolloutsApi private variable will check if the API of Argo Rollouts is present.
+	// See rollouts.go#hasRolloutsApi() for more details
+	isRolloutsApi *bool
+
 	// networkingResources private variable will check which resources kiali has access to from networking.istio.io group
 	// It is represented as a pointer to include the initialization phase.
 	// See istio_details_service.go#hasNetworkingResource() for more details.
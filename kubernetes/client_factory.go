@@ -1,6 +1,8 @@
 package kubernetes
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +23,8 @@ const expirationTime = time.Minute * 15
 // ClientFactory interface for the clientFactory object
 type ClientFactory interface {
 	GetClient(token string) (ClientInterface, error)
+	GetClusterClient(cluster string, token string) (ClientInterface, error)
+	GetImpersonatingClient(token string, user string, groups []string) (ClientInterface, error)
 }
 
 // clientFactory used to generate per users clients
@@ -28,6 +32,8 @@ type clientFactory struct {
 	ClientFactory
 	baseIstioConfig *rest.Config
 	clientEntries   map[string]*clientEntry
+	// Parsed remote secrets, by cluster name
+	remoteSecrets map[string]*remoteSecretEntry
 }
 
 // clientEntry stored the client and its created timestamp
@@ -36,6 +42,12 @@ type clientEntry struct {
 	created time.Time
 }
 
+// remoteSecretEntry stores the parsed remote secret of a cluster and its read timestamp
+type remoteSecretEntry struct {
+	secret  *RemoteSecret
+	created time.Time
+}
+
 // GetClientFactory returns the client factory. Creates a new one if necessary
 func GetClientFactory() (ClientFactory, error) {
 	if factory == nil {
@@ -69,9 +81,10 @@ func getClientFactory(istioConfig *rest.Config, expiry time.Duration) (*clientFa
 		factory = &clientFactory{
 			baseIstioConfig: istioConfig,
 			clientEntries:   clientEntriesMap,
+			remoteSecrets:   make(map[string]*remoteSecretEntry),
 		}
 
-		go watchClients(clientEntriesMap, expiry)
+		go watchClients(factory, expiry)
 	}
 	mutex.Unlock()
 	return factory, nil
@@ -137,15 +150,141 @@ func (cf *clientFactory) GetClient(token string) (ClientInterface, error) {
 	return clientEntry.client, nil
 }
 
+// GetImpersonatingClient returns a client authenticated with the specified token, acting as the specified user and
+// groups. Creating one if necessary. The cluster applies the permissions of the impersonated user, once it has checked
+// that the token is allowed to impersonate users and groups.
+func (cf *clientFactory) GetImpersonatingClient(token string, user string, groups []string) (ClientInterface, error) {
+	key := token + ":as:" + user + ":" + strings.Join(groups, ",")
+	clientEntry, err := cf.getEntry(key, func() (ClientInterface, error) {
+		config := *cf.baseIstioConfig
+		config.BearerToken = token
+		config.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+		return NewClientFromConfig(&config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clientEntry.client, nil
+}
+
+// GetClusterClient returns a client for the specified remote cluster. Creating one if necessary.
+// The token is the credential of the user for that cluster; it is ignored when the cluster
+// is configured with the service_account strategy.
+func (cf *clientFactory) GetClusterClient(cluster string, token string) (ClientInterface, error) {
+	remoteCluster := kialiConfig.Get().Clustering.GetCluster(cluster)
+	if remoteCluster == nil {
+		return nil, fmt.Errorf("cluster [%s] is not configured", cluster)
+	}
+
+	remoteSecret, err := cf.getRemoteSecret(remoteCluster)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the remote secret of cluster [%s]: %v", cluster, err)
+	}
+
+	if remoteCluster.Auth.Strategy == kialiConfig.ClusterAuthStrategyServiceAccount {
+		if len(remoteSecret.Users) == 0 || remoteSecret.Users[0].User.Token == "" {
+			return nil, fmt.Errorf("the remote secret of cluster [%s] has no service account token", cluster)
+		}
+		token = remoteSecret.Users[0].User.Token
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("no credentials for cluster [%s] in the user session", cluster)
+	}
+
+	clientEntry, err := cf.getEntry(cluster+":"+token, func() (ClientInterface, error) {
+		config, err := UseRemoteCreds(remoteSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.BearerToken = token
+		config.QPS = cf.baseIstioConfig.QPS
+		config.Burst = cf.baseIstioConfig.Burst
+		return NewClientFromConfig(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clientEntry.client, nil
+}
+
+// getRemoteSecret returns the remote secret of a cluster, read once and kept as long as the clients
+func (cf *clientFactory) getRemoteSecret(remoteCluster *kialiConfig.RemoteCluster) (*RemoteSecret, error) {
+	mutex.RLock()
+	entry, ok := cf.remoteSecrets[remoteCluster.Name]
+	mutex.RUnlock()
+	if ok {
+		return entry.secret, nil
+	}
+
+	remoteSecret, err := cf.readRemoteSecret(remoteCluster)
+	if err != nil {
+		return nil, err
+	}
+	mutex.Lock()
+	if cf.remoteSecrets == nil {
+		cf.remoteSecrets = make(map[string]*remoteSecretEntry)
+	}
+	cf.remoteSecrets[remoteCluster.Name] = &remoteSecretEntry{secret: remoteSecret, created: time.Now()}
+	mutex.Unlock()
+	return remoteSecret, nil
+}
+
+// readRemoteSecret reads the remote secret of a cluster, from its file or from its Secret. The Secret is read with the
+// Kiali service account.
+func (cf *clientFactory) readRemoteSecret(remoteCluster *kialiConfig.RemoteCluster) (*RemoteSecret, error) {
+	if remoteCluster.SecretRef == nil {
+		return GetRemoteSecret(remoteCluster.SecretFile)
+	}
+	kialiToken, err := GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+	client, err := cf.GetClient(kialiToken)
+	if err != nil {
+		return nil, err
+	}
+	ref := remoteCluster.SecretRef
+	secret, err := client.GetSecret(ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	return GetRemoteSecretFromSecret(secret, ref.Key)
+}
+
+// PurgeClusterClients removes the clients and the remote secret of a remote cluster, so they are not used once the
+// cluster is deregistered or its remote secret changes
+func PurgeClusterClients(cluster string) {
+	if factory == nil {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(factory.remoteSecrets, cluster)
+	for key := range factory.clientEntries {
+		if strings.HasPrefix(key, cluster+":") {
+			delete(factory.clientEntries, key)
+		}
+	}
+	internalmetrics.SetKubernetesClients(len(factory.clientEntries))
+}
+
 // getClientEntry returns a clientEntry for the specified token. Creating one if necessary.
 func (cf *clientFactory) getClientEntry(token string) (*clientEntry, error) {
+	return cf.getEntry(token, func() (ClientInterface, error) {
+		return cf.newClient(token)
+	})
+}
+
+// getEntry returns the clientEntry stored with the specified key. Creating one with newClient if necessary.
+func (cf *clientFactory) getEntry(key string, newClient func() (ClientInterface, error)) (*clientEntry, error) {
 	mutex.RLock()
-	cEntry, ok := cf.clientEntries[token]
+	cEntry, ok := cf.clientEntries[key]
 	mutex.RUnlock()
 	if ok {
 		return cEntry, nil
 	} else {
-		client, err := cf.newClient(token)
+		client, err := newClient()
 		if err != nil {
 			log.Errorf("Error fetching the Kubernetes client: %v", err)
 			return nil, err
@@ -157,24 +296,30 @@ func (cf *clientFactory) getClientEntry(token string) (*clientEntry, error) {
 		}
 
 		mutex.Lock()
-		cf.clientEntries[token] = &cEntry
+		cf.clientEntries[key] = &cEntry
 		mutex.Unlock()
 		internalmetrics.SetKubernetesClients(len(cf.clientEntries))
 		return &cEntry, nil
 	}
 }
 
-// watchClients loops over clients and removes ones which are too old
-func watchClients(clientEntries map[string]*clientEntry, expiry time.Duration) {
+// watchClients loops over clients and remote secrets and removes ones which are too old. The remote secrets are read
+// again, in case their files changed.
+func watchClients(cf *clientFactory, expiry time.Duration) {
 	for {
 		time.Sleep(expiry)
 		mutex.Lock()
-		for token, clientEntry := range clientEntries {
+		for token, clientEntry := range cf.clientEntries {
 			if time.Since(clientEntry.created) > expiry {
-				delete(clientEntries, token)
+				delete(cf.clientEntries, token)
+			}
+		}
+		for cluster, secretEntry := range cf.remoteSecrets {
+			if time.Since(secretEntry.created) > expiry {
+				delete(cf.remoteSecrets, cluster)
 			}
 		}
-		internalmetrics.SetKubernetesClients(len(clientEntries))
+		internalmetrics.SetKubernetesClients(len(cf.clientEntries))
 		mutex.Unlock()
 	}
 }
@@ -1,11 +1,15 @@
 package kubernetes
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/rest"
+
+	kialiConfig "github.com/kiali/kiali/config"
 )
 
 // TestClientExpiry Verify that clients expire
@@ -67,3 +71,73 @@ func TestClientExpiry(t *testing.T) {
 	assert.Equal(t, 0, len(clientEntries))
 	mutex.RUnlock()
 }
+
+// TestClusterClientStrategies Verify that remote cluster clients use the credentials matching the cluster auth strategy
+func TestClusterClientStrategies(t *testing.T) {
+	assert := assert.New(t)
+
+	secretFile, err := ioutil.TempFile("", "remote-secret")
+	assert.NoError(err)
+	defer os.Remove(secretFile.Name())
+	_, err = secretFile.WriteString(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Y2EtZGF0YQ==
+    server: https://cluster-a.example.com:6443
+  name: cluster-a
+users:
+- name: kiali
+  user:
+    token: sa-token
+`)
+	assert.NoError(err)
+	secretFile.Close()
+
+	conf := kialiConfig.NewConfig()
+	conf.Clustering.Clusters = []kialiConfig.RemoteCluster{
+		{
+			Name:       "sa",
+			SecretFile: secretFile.Name(),
+			Auth:       kialiConfig.RemoteClusterAuth{Strategy: kialiConfig.ClusterAuthStrategyServiceAccount},
+		},
+		{
+			Name:       "token",
+			SecretFile: secretFile.Name(),
+			Auth:       kialiConfig.RemoteClusterAuth{Strategy: kialiConfig.ClusterAuthStrategyToken},
+		},
+	}
+	kialiConfig.Set(conf)
+
+	cf := &clientFactory{
+		baseIstioConfig: &rest.Config{},
+		clientEntries:   make(map[string]*clientEntry),
+	}
+
+	// The service account strategy ignores the user credentials
+	client, err := cf.GetClusterClient("sa", "")
+	assert.NoError(err)
+	assert.Equal("sa-token", client.GetToken())
+
+	// The token strategy uses the user credentials
+	client, err = cf.GetClusterClient("token", "user-token")
+	assert.NoError(err)
+	assert.Equal("user-token", client.GetToken())
+	_, found := cf.clientEntries["token:user-token"]
+	assert.True(found)
+
+	// The token strategy requires user credentials
+	_, err = cf.GetClusterClient("token", "")
+	assert.Error(err)
+
+	// Unknown clusters are rejected
+	_, err = cf.GetClusterClient("unknown", "user-token")
+	assert.Error(err)
+
+	// The remote secrets are parsed once
+	os.Remove(secretFile.Name())
+	client, err = cf.GetClusterClient("token", "other-user-token")
+	assert.NoError(err)
+	assert.Equal("other-user-token", client.GetToken())
+}
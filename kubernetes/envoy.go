@@ -7,6 +7,17 @@ type ConfigDump struct {
 	Configs []interface{} `json:"configs"`
 }
 
+// EnvoyStats are the stats of an Envoy, in the JSON format of its admin /stats endpoint
+type EnvoyStats struct {
+	// Counters and gauges. The histograms come in an entry without name.
+	Stats []EnvoyStat `json:"stats"`
+}
+
+type EnvoyStat struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
 type ClusterDump struct {
 	DynamicClusters []EnvoyClusterWrapper `mapstructure:"dynamic_active_clusters"`
 	StaticClusters  []EnvoyClusterWrapper `mapstructure:"static_clusters"`
@@ -69,6 +80,7 @@ type EnvoyListener struct {
 type EnvoyFilterChain struct {
 	Filters          []EnvoyListenerFilter `mapstructure:"filters"`
 	FilterChainMatch *FilterChainMatch     `mapstructure:"filter_chain_match"`
+	Metadata         *EnvoyMetadata        `mapstructure:"metadata,omitempty"`
 }
 
 type EnvoyListenerFilter struct {
@@ -112,6 +124,32 @@ type FilterChainMatch struct {
 	} `mapstructure:"prefix_ranges"`
 }
 
+type SecretDump struct {
+	StaticSecrets        []EnvoySecretWrapper `mapstructure:"static_secrets"`
+	DynamicActiveSecrets []EnvoySecretWrapper `mapstructure:"dynamic_active_secrets"`
+}
+
+type EnvoySecretWrapper struct {
+	Name        string      `mapstructure:"name"`
+	LastUpdated string      `mapstructure:"last_updated"`
+	Secret      EnvoySecret `mapstructure:"secret"`
+}
+
+type EnvoySecret struct {
+	Name           string `mapstructure:"name"`
+	TlsCertificate *struct {
+		CertificateChain EnvoyDataSource `mapstructure:"certificate_chain"`
+	} `mapstructure:"tls_certificate,omitempty"`
+	ValidationContext *struct {
+		TrustedCa EnvoyDataSource `mapstructure:"trusted_ca"`
+	} `mapstructure:"validation_context,omitempty"`
+}
+
+// EnvoyDataSource holds inlined data, base64 encoded in the config dump
+type EnvoyDataSource struct {
+	InlineBytes string `mapstructure:"inline_bytes"`
+}
+
 func (cd *ConfigDump) GetListeners() (*ListenerDump, error) {
 	listenersDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.ListenersConfigDump")
 	var listenersDump ListenerDump
@@ -130,6 +168,12 @@ func (cd *ConfigDump) GetRoutes() (*RouteDump, error) {
 	return &routeDump, mapstructure.Decode(routeDumpRaw, &routeDump)
 }
 
+func (cd *ConfigDump) GetSecrets() (*SecretDump, error) {
+	secretDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.SecretsConfigDump")
+	var secretDump SecretDump
+	return &secretDump, mapstructure.Decode(secretDumpRaw, &secretDump)
+}
+
 func (cd *ConfigDump) GetConfig(objectType string) map[string]interface{} {
 	for _, configRaw := range cd.Configs {
 		conf, ok := configRaw.(map[string]interface{})
@@ -148,3 +192,41 @@ func (cd *ConfigDump) GetConfig(objectType string) map[string]interface{} {
 	}
 	return nil
 }
+
+// ZtunnelConfigDump is the JSON returned by the /config_dump endpoint of the admin of a ztunnel
+type ZtunnelConfigDump struct {
+	Workloads []ZtunnelWorkload `json:"workloads"`
+	Services  []ZtunnelService  `json:"services"`
+	Policies  []ZtunnelPolicy   `json:"policies"`
+}
+
+type ZtunnelWorkload struct {
+	UID            string                 `json:"uid"`
+	Name           string                 `json:"name"`
+	Namespace      string                 `json:"namespace"`
+	WorkloadName   string                 `json:"workloadName"`
+	WorkloadIPs    []string               `json:"workloadIps"`
+	ServiceAccount string                 `json:"serviceAccount"`
+	Node           string                 `json:"node"`
+	Protocol       string                 `json:"protocol"`
+	Status         string                 `json:"status"`
+	Waypoint       map[string]interface{} `json:"waypoint,omitempty"`
+	Services       map[string]interface{} `json:"services,omitempty"`
+}
+
+type ZtunnelService struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Hostname  string                 `json:"hostname"`
+	Vips      []string               `json:"vips"`
+	Ports     map[string]interface{} `json:"ports,omitempty"`
+	Waypoint  map[string]interface{} `json:"waypoint,omitempty"`
+}
+
+type ZtunnelPolicy struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Scope     string        `json:"scope"`
+	Action    string        `json:"action"`
+	Rules     []interface{} `json:"rules"`
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -161,6 +162,10 @@ func (in *K8SClient) GetIstioObject(namespace, resourceType, name string) (Istio
 		return nil, fmt.Errorf("%s not found in ResourcesTypeToAPI", resourceType)
 	}
 
+	if IsIstioResourceExcluded(resourceType) {
+		return nil, NewNotFound(name, apiGroup, resourceType)
+	}
+
 	var result runtime.Object
 	var err error
 	result, err = apiClient.Get().Namespace(namespace).Resource(resourceType).SubResource(name).Do().Get()
@@ -185,6 +190,11 @@ type ProxyStatus struct {
 	SyncStatus
 }
 
+// Pilot returns the name of the istiod pod the proxy is connected to
+func (ps ProxyStatus) Pilot() string {
+	return ps.pilot
+}
+
 // SyncStatus is the synchronization status between Pilot and a given Envoy
 type SyncStatus struct {
 	ProxyID       string `json:"proxy,omitempty"`
@@ -269,6 +279,39 @@ func (in *K8SClient) GetConfigDump(namespace, podName string) (*ConfigDump, erro
 	return cd, err
 }
 
+// GetEnvoyStats returns the counters and gauges of the Envoy of a pod whose names match the filter, a regular expression
+func (in *K8SClient) GetEnvoyStats(namespace, podName, filter string) (*EnvoyStats, error) {
+	resp, err := in.EnvoyForward(namespace, podName, "/stats?format=json&filter="+url.QueryEscape(filter))
+	if err != nil {
+		log.Errorf("Error fetching the Envoy stats: %v", err)
+		return nil, err
+	}
+
+	stats := &EnvoyStats{}
+	if err = json.Unmarshal(resp, stats); err != nil {
+		log.Errorf("Error Unmarshalling the Envoy stats: %v", err)
+	}
+
+	return stats, err
+}
+
+// GetZtunnelConfigDump returns the workloads, services and policies known by a ztunnel, its admin listening on the
+// same port as the one of Envoy
+func (in *K8SClient) GetZtunnelConfigDump(namespace, podName string) (*ZtunnelConfigDump, error) {
+	resp, err := in.EnvoyForward(namespace, podName, "/config_dump")
+	if err != nil {
+		log.Errorf("Error fetching the ztunnel config_dump: %v", err)
+		return nil, err
+	}
+
+	cd := &ZtunnelConfigDump{}
+	if err = json.Unmarshal(resp, cd); err != nil {
+		log.Errorf("Error Unmarshalling the ztunnel config_dump: %v", err)
+	}
+
+	return cd, err
+}
+
 func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, error) {
 	writer := new(bytes.Buffer)
 
@@ -285,9 +328,9 @@ func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, erro
 		return nil, err
 	}
 
-	// Building the port mapping local:target port
-	envoyLocalPort := config.Get().ExternalServices.Istio.EnvoyAdminLocalPort
-	portMap := fmt.Sprintf("%d:15000", envoyLocalPort)
+	// Building the port mapping local:target port. The local port is chosen by the system, so that the
+	// port-forwards to several proxies can run at the same time.
+	portMap := "0:15000"
 
 	// Create a Port Forwarder
 	f, err := config_dump.NewPortForwarder(in.k8s.CoreV1().RESTClient(), clientConfig,
@@ -304,17 +347,29 @@ func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, erro
 	// Defering the finish of the port-forwarding
 	defer f.Stop()
 
+	envoyLocalPort, err := f.LocalPort()
+	if err != nil {
+		return nil, err
+	}
+
 	// Ready to create a request
 	resp, code, err := httputil.HttpGet(fmt.Sprintf("http://localhost:%d%s", envoyLocalPort, path), nil, 10*time.Second)
 	if code >= 400 {
-		return resp, fmt.Errorf("error fetching the /config_dump for the Envoy. Response code: %d", code)
+		return resp, fmt.Errorf("error fetching the %s for the Envoy. Response code: %d", path, code)
 	}
 
 	return resp, err
 }
 
+// IsIstioResourceExcluded returns true if the Istio resource (i.e. envoyfilters) is excluded by configuration.
+// Excluded resources are handled as if they were not installed in the cluster.
+func IsIstioResourceExcluded(resource string) bool {
+	kConfig := config.Get().KubernetesConfig
+	return kConfig.IsIstioTypeExcluded(PluralType[resource])
+}
+
 func (in *K8SClient) hasNetworkingResource(resource string) bool {
-	return in.getNetworkingResources()[resource]
+	return !IsIstioResourceExcluded(resource) && in.getNetworkingResources()[resource]
 }
 
 func (in *K8SClient) getNetworkingResources() map[string]bool {
@@ -339,7 +394,7 @@ func (in *K8SClient) getNetworkingResources() map[string]bool {
 }
 
 func (in *K8SClient) hasSecurityResource(resource string) bool {
-	return in.getSecurityResources()[resource]
+	return !IsIstioResourceExcluded(resource) && in.getSecurityResources()[resource]
 }
 
 func (in *K8SClient) getSecurityResources() map[string]bool {
@@ -3,15 +3,18 @@ package kubernetes
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	osproject_v1 "github.com/openshift/api/project/v1"
 	osroutes_v1 "github.com/openshift/api/route/v1"
 	apps_v1 "k8s.io/api/apps/v1"
+	authn_v1 "k8s.io/api/authentication/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	policy_v1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -32,6 +35,37 @@ func (in *K8SClient) GetConfigMap(namespace, configName string) (*core_v1.Config
 	return configMap, nil
 }
 
+// CreateConfigMap creates the given ConfigMap in the cluster
+func (in *K8SClient) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Create(configMap)
+}
+
+// UpdateConfigMap replaces the given ConfigMap in the cluster
+func (in *K8SClient) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Update(configMap)
+}
+
+// GetSecret fetches and returns the specified Secret definition
+// from the cluster
+func (in *K8SClient) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	secret, err := in.k8s.CoreV1().Secrets(namespace).Get(name, emptyGetOptions)
+	if err != nil {
+		return &core_v1.Secret{}, err
+	}
+
+	return secret, nil
+}
+
+// CreateSecret creates the given Secret in the cluster
+func (in *K8SClient) CreateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error) {
+	return in.k8s.CoreV1().Secrets(namespace).Create(secret)
+}
+
+// UpdateSecret replaces the given Secret in the cluster
+func (in *K8SClient) UpdateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error) {
+	return in.k8s.CoreV1().Secrets(namespace).Update(secret)
+}
+
 // GetNamespace fetches and returns the specified namespace definition
 // from the cluster
 func (in *K8SClient) GetNamespace(namespace string) (*core_v1.Namespace, error) {
@@ -43,6 +77,12 @@ func (in *K8SClient) GetNamespace(namespace string) (*core_v1.Namespace, error)
 	return ns, nil
 }
 
+// GetServerResources fetches the resources served by the cluster for an API group version, i.e.
+// gateway.networking.k8s.io/v1. A NotFound error is returned when the group version is not served.
+func (in *K8SClient) GetServerResources(groupVersion string) (*meta_v1.APIResourceList, error) {
+	return in.k8s.Discovery().ServerResourcesForGroupVersion(groupVersion)
+}
+
 // GetServerVersion fetches and returns information about the version Kubernetes that is running
 func (in *K8SClient) GetServerVersion() (*version.Info, error) {
 	return in.k8s.Discovery().ServerVersion()
@@ -242,6 +282,16 @@ func (in *K8SClient) GetEndpoints(namespace, serviceName string) (*core_v1.Endpo
 	return in.k8s.CoreV1().Endpoints(namespace).Get(serviceName, emptyGetOptions)
 }
 
+// GetEvents returns the events of the objects of a namespace, kept by the cluster for a limited time (1h by default).
+// It returns an error on any problem.
+func (in *K8SClient) GetEvents(namespace string) ([]core_v1.Event, error) {
+	if events, err := in.k8s.CoreV1().Events(namespace).List(emptyListOptions); err == nil {
+		return events.Items, nil
+	} else {
+		return []core_v1.Event{}, err
+	}
+}
+
 // GetPods returns the pods definitions for a given set of labels.
 // An empty labelSelector will fetch all pods found per a namespace.
 // It returns an error on any problem.
@@ -266,6 +316,14 @@ func (in *K8SClient) GetPod(namespace, name string) (*core_v1.Pod, error) {
 	}
 }
 
+// EvictPod evicts a pod through the eviction API, which denies the eviction with a TooManyRequests error when it would
+// violate a PodDisruptionBudget
+func (in *K8SClient) EvictPod(namespace, name string) error {
+	return in.k8s.CoreV1().Pods(namespace).Evict(&policy_v1beta1.Eviction{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: namespace, Name: name},
+	})
+}
+
 // GetPod returns the pod definitions for a given pod name.
 // It returns an error on any problem.
 func (in *K8SClient) GetPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (*PodLogs, error) {
@@ -286,6 +344,12 @@ func (in *K8SClient) GetPodLogs(namespace, name string, opts *core_v1.PodLogOpti
 	return &PodLogs{Logs: buf.String()}, nil
 }
 
+// StreamPodLogs opens the stream of the logs of a pod, the caller closing it. The stream stays open while the
+// container runs when the logs are followed.
+func (in *K8SClient) StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error) {
+	return in.k8s.CoreV1().RESTClient().Get().Namespace(namespace).Name(name).Resource("pods").SubResource("log").VersionedParams(opts, scheme.ParameterCodec).Stream()
+}
+
 func (in *K8SClient) GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error) {
 	if cjList, err := in.k8s.BatchV1beta1().CronJobs(namespace).List(emptyListOptions); err == nil {
 		return cjList.Items, nil
@@ -308,6 +372,14 @@ func NewNotFound(name, group, resource string) error {
 	return errors.NewNotFound(schema.GroupResource{Group: group, Resource: resource}, name)
 }
 
+// GetTokenReview asks the cluster who the given token belongs to. The client needs the permission to create
+// TokenReviews, which the Kiali ServiceAccount has.
+func (in *K8SClient) GetTokenReview(token string) (*authn_v1.TokenReview, error) {
+	return in.k8s.AuthenticationV1().TokenReviews().Create(&authn_v1.TokenReview{
+		Spec: authn_v1.TokenReviewSpec{Token: token},
+	})
+}
+
 // GetSelfSubjectAccessReview provides information on Kiali permissions
 func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error) {
 	calls := len(verbs)
@@ -346,33 +418,41 @@ func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType str
 	return result, err
 }
 
-func (in *K8SClient) UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error {
+// UpdateWorkload patches a workload and returns its resource version after the patch, empty when it is unknown, i.e.
+// for the DeploymentConfigs and Rollouts
+func (in *K8SClient) UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) (string, error) {
 	bytePatch := []byte(jsonPatch)
+	var patched meta_v1.Object
 	var err error
 	switch workloadType {
 	case DeploymentType:
-		_, err = in.k8s.AppsV1().Deployments(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.AppsV1().Deployments(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case ReplicaSetType:
-		_, err = in.k8s.AppsV1().ReplicaSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.AppsV1().ReplicaSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case ReplicationControllerType:
-		_, err = in.k8s.CoreV1().ReplicationControllers(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.CoreV1().ReplicationControllers(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case DeploymentConfigType:
 		if in.IsOpenShift() {
 			result := &osapps_v1.DeploymentConfigList{}
 			err = in.k8s.RESTClient().Patch(types.MergePatchType).Prefix("apis", "apps.openshift.io", "v1").Namespace(namespace).Resource("deploymentconfigs").SubResource(workloadName).Body(bytePatch).Do().Into(result)
 		}
 	case StatefulSetType:
-		_, err = in.k8s.AppsV1().StatefulSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.AppsV1().StatefulSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case JobType:
-		_, err = in.k8s.BatchV1().Jobs(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.BatchV1().Jobs(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case CronJobType:
-		_, err = in.k8s.BatchV1beta1().CronJobs(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.BatchV1beta1().CronJobs(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case PodType:
-		_, err = in.k8s.CoreV1().Pods(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+		patched, err = in.k8s.CoreV1().Pods(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+	case RolloutType:
+		err = in.k8s.RESTClient().Patch(types.MergePatchType).Prefix("apis", "argoproj.io", "v1alpha1").Namespace(namespace).Resource("rollouts").Name(workloadName).Body(bytePatch).Do().Error()
 	default:
 		err = fmt.Errorf("Workload type %s not found", workloadType)
 	}
-	return err
+	if err != nil || patched == nil {
+		return "", err
+	}
+	return patched.GetResourceVersion(), nil
 }
 
 func (in *K8SClient) UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error) {
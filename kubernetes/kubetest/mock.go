@@ -34,6 +34,14 @@ func (o *K8SClientFactoryMock) GetClient(token string) (kubernetes.ClientInterfa
 	return o.k8s, nil
 }
 
+func (o *K8SClientFactoryMock) GetClusterClient(cluster string, token string) (kubernetes.ClientInterface, error) {
+	return o.k8s, nil
+}
+
+func (o *K8SClientFactoryMock) GetImpersonatingClient(token string, user string, groups []string) (kubernetes.ClientInterface, error) {
+	return o.k8s, nil
+}
+
 /////
 
 type K8SClientMock struct {
@@ -59,6 +67,7 @@ func (o *K8SClientMock) MockEmptyWorkloads(namespace interface{}) {
 	o.On("GetStatefulSets", namespace).Return([]apps_v1.StatefulSet{}, nil)
 	o.On("GetJobs", namespace).Return([]batch_v1.Job{}, nil)
 	o.On("GetCronJobs", namespace).Return([]batch_apps_v1.CronJob{}, nil)
+	o.On("GetRollouts", namespace).Return([]kubernetes.Rollout{}, nil)
 }
 
 // MockEmptyWorkload setup the current mock to return an empty workload for every type of workloads (deployment, dc, rs, jobs, etc.)
@@ -75,6 +84,7 @@ func (o *K8SClientMock) MockEmptyWorkload(namespace interface{}, workload interf
 	o.On("GetReplicationControllers", namespace).Return([]core_v1.ReplicationController{}, nil)
 	o.On("GetJobs", namespace).Return([]batch_v1.Job{}, nil)
 	o.On("GetCronJobs", namespace).Return([]batch_apps_v1.CronJob{}, nil)
+	o.On("GetRollouts", namespace).Return([]kubernetes.Rollout{}, nil)
 }
 
 func (o *K8SClientMock) IsOpenShift() bool {
@@ -87,6 +97,11 @@ func (o *K8SClientMock) IsMaistraApi() bool {
 	return args.Get(0).(bool)
 }
 
+func (o *K8SClientMock) GetServerResources(groupVersion string) (*meta_v1.APIResourceList, error) {
+	args := o.Called(groupVersion)
+	return args.Get(0).(*meta_v1.APIResourceList), args.Error(1)
+}
+
 func (o *K8SClientMock) GetServerVersion() (*version.Info, error) {
 	args := o.Called()
 	return args.Get(0).(*version.Info), args.Error(1)
@@ -38,3 +38,13 @@ func (o *K8SClientMock) GetConfigDump(namespace string, podName string) (*kubern
 	args := o.Called(namespace, podName)
 	return args.Get(0).(*kubernetes.ConfigDump), args.Error(1)
 }
+
+func (o *K8SClientMock) GetEnvoyStats(namespace, podName, filter string) (*kubernetes.EnvoyStats, error) {
+	args := o.Called(namespace, podName, filter)
+	return args.Get(0).(*kubernetes.EnvoyStats), args.Error(1)
+}
+
+func (o *K8SClientMock) GetZtunnelConfigDump(namespace, podName string) (*kubernetes.ZtunnelConfigDump, error) {
+	args := o.Called(namespace, podName)
+	return args.Get(0).(*kubernetes.ZtunnelConfigDump), args.Error(1)
+}
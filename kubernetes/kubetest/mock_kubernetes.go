@@ -1,8 +1,12 @@
 package kubetest
 
 import (
+	"io"
+
 	apps_v1 "k8s.io/api/apps/v1"
+	authn_v1 "k8s.io/api/authentication/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
+	autoscaling_v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_apps_v1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
@@ -10,6 +14,16 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 )
 
+func (o *K8SClientMock) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
+func (o *K8SClientMock) CreateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error) {
+	args := o.Called(namespace, secret)
+	return args.Get(0).(*core_v1.Secret), args.Error(1)
+}
+
 func (o *K8SClientMock) GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error) {
 	args := o.Called(namespace, configName)
 	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
@@ -40,6 +54,16 @@ func (o *K8SClientMock) GetEndpoints(namespace string, serviceName string) (*cor
 	return args.Get(0).(*core_v1.Endpoints), args.Error(1)
 }
 
+func (o *K8SClientMock) GetEvents(namespace string) ([]core_v1.Event, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]core_v1.Event), args.Error(1)
+}
+
+func (o *K8SClientMock) GetHorizontalPodAutoscalers(namespace string) ([]autoscaling_v2beta2.HorizontalPodAutoscaler, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]autoscaling_v2beta2.HorizontalPodAutoscaler), args.Error(1)
+}
+
 func (o *K8SClientMock) GetJobs(namespace string) ([]batch_v1.Job, error) {
 	args := o.Called(namespace)
 	return args.Get(0).([]batch_v1.Job), args.Error(1)
@@ -60,6 +84,11 @@ func (o *K8SClientMock) GetPods(namespace, labelSelector string) ([]core_v1.Pod,
 	return args.Get(0).([]core_v1.Pod), args.Error(1)
 }
 
+func (o *K8SClientMock) EvictPod(namespace, name string) error {
+	args := o.Called(namespace, name)
+	return args.Error(0)
+}
+
 func (o *K8SClientMock) GetPod(namespace, name string) (*core_v1.Pod, error) {
 	args := o.Called(namespace, name)
 	return args.Get(0).(*core_v1.Pod), args.Error(1)
@@ -70,6 +99,11 @@ func (o *K8SClientMock) GetPodLogs(namespace, name string, opts *core_v1.PodLogO
 	return args.Get(0).(*kubernetes.PodLogs), args.Error(1)
 }
 
+func (o *K8SClientMock) StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error) {
+	args := o.Called(namespace, name, opts)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
 func (o *K8SClientMock) GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error) {
 	args := o.Called(namespace)
 	return args.Get(0).([]core_v1.ReplicationController), args.Error(1)
@@ -80,11 +114,31 @@ func (o *K8SClientMock) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet,
 	return args.Get(0).([]apps_v1.ReplicaSet), args.Error(1)
 }
 
+func (o *K8SClientMock) GetRollouts(namespace string) ([]kubernetes.Rollout, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]kubernetes.Rollout), args.Error(1)
+}
+
+func (o *K8SClientMock) GetScaledObjects(namespace string) ([]kubernetes.ScaledObject, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]kubernetes.ScaledObject), args.Error(1)
+}
+
+func (o *K8SClientMock) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	args := o.Called(namespace, name)
+	return args.Get(0).(*core_v1.Secret), args.Error(1)
+}
+
 func (o *K8SClientMock) GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error) {
 	args := o.Called(namespace, api, resourceType, verbs)
 	return args.Get(0).([]*auth_v1.SelfSubjectAccessReview), args.Error(1)
 }
 
+func (o *K8SClientMock) GetTokenReview(token string) (*authn_v1.TokenReview, error) {
+	args := o.Called(token)
+	return args.Get(0).(*authn_v1.TokenReview), args.Error(1)
+}
+
 func (o *K8SClientMock) GetService(namespace string, serviceName string) (*core_v1.Service, error) {
 	args := o.Called(namespace, serviceName)
 	return args.Get(0).(*core_v1.Service), args.Error(1)
@@ -105,12 +159,27 @@ func (o *K8SClientMock) GetStatefulSets(namespace string) ([]apps_v1.StatefulSet
 	return args.Get(0).([]apps_v1.StatefulSet), args.Error(1)
 }
 
+func (o *K8SClientMock) GetVerticalPodAutoscalers(namespace string) ([]kubernetes.VerticalPodAutoscaler, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]kubernetes.VerticalPodAutoscaler), args.Error(1)
+}
+
+func (o *K8SClientMock) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
 func (o *K8SClientMock) UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error) {
 	args := o.Called(namespace, jsonPatch)
 	return args.Get(0).(*core_v1.Namespace), args.Error(1)
 }
 
-func (o *K8SClientMock) UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error {
+func (o *K8SClientMock) UpdateSecret(namespace string, secret *core_v1.Secret) (*core_v1.Secret, error) {
+	args := o.Called(namespace, secret)
+	return args.Get(0).(*core_v1.Secret), args.Error(1)
+}
+
+func (o *K8SClientMock) UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) (string, error) {
 	args := o.Called(namespace, workloadName, workloadType, jsonPatch)
-	return args.Error(1)
+	return args.String(0), args.Error(1)
 }
@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"time"
+
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	kialiConfig "github.com/kiali/kiali/config"
+)
+
+// NewLeaderElector creates the elector of the leader of the replicas of Kiali, configured by
+// kubernetes_config.leader_election. The Lease is held in the namespace of Kiali with its service account.
+func NewLeaderElector(identity string, callbacks leaderelection.LeaderCallbacks) (*leaderelection.LeaderElector, error) {
+	config, err := ConfigClient()
+	if err != nil {
+		return nil, err
+	}
+	conf := kialiConfig.Get()
+	token := ""
+	if conf.InCluster {
+		if token, err = GetKialiToken(); err != nil {
+			return nil, err
+		}
+	}
+	clientset, err := kube.NewForConfig(&rest.Config{
+		Host:            config.Host,
+		TLSClientConfig: config.TLSClientConfig,
+		BearerToken:     token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	le := conf.KubernetesConfig.LeaderElection
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock, conf.Deployment.Namespace, le.LeaseName,
+		clientset.CoreV1(), clientset.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+	if err != nil {
+		return nil, err
+	}
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   time.Duration(le.LeaseDuration) * time.Second,
+		RenewDeadline:   time.Duration(le.RenewDeadline) * time.Second,
+		RetryPeriod:     time.Duration(le.RetryPeriod) * time.Second,
+		Callbacks:       callbacks,
+		ReleaseOnCancel: true,
+		Name:            le.LeaseName,
+	})
+}
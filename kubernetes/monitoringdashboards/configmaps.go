@@ -0,0 +1,249 @@
+package monitoringdashboards
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+	"github.com/kiali/kiali/log"
+)
+
+// ConfigMapDashboards holds the dashboards defined in ConfigMaps. Each entry of the data of a ConfigMap holds a
+// MonitoringDashboard, in YAML or JSON; dashboards without name are named after their entry, i.e. "my-runtime.yaml"
+// defines the "my-runtime" dashboard. Dashboards are visible in the namespace of their ConfigMap.
+type ConfigMapDashboards struct {
+	lock sync.RWMutex
+	// Dashboards by namespace and name
+	dashboards map[string]map[string]v1alpha1.MonitoringDashboard
+	// Names of the dashboards defined by each ConfigMap, by namespace/name of the ConfigMap
+	configMaps map[string][]string
+}
+
+var watchLock sync.Mutex
+var watchedDashboards *ConfigMapDashboards
+var watchStop chan struct{}
+
+// NewConfigMapDashboards returns an empty set of ConfigMap dashboards
+func NewConfigMapDashboards() *ConfigMapDashboards {
+	return &ConfigMapDashboards{
+		dashboards: make(map[string]map[string]v1alpha1.MonitoringDashboard),
+		configMaps: make(map[string][]string),
+	}
+}
+
+// WatchConfigMaps returns the dashboards of the ConfigMaps matching the label selector, in the namespaces accessible
+// by Kiali. The ConfigMaps are watched with the service account of Kiali, so dashboards are reloaded as soon as they
+// change. The watch is started once, on first call, and runs until StopWatchingConfigMaps.
+func WatchConfigMaps(selector string, accessibleNamespaces []string, resync time.Duration) (*ConfigMapDashboards, error) {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+	if watchedDashboards != nil {
+		return watchedDashboards, nil
+	}
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dashboards := NewConfigMapDashboards()
+	stop := make(chan struct{})
+	namespaces := watchedNamespaces(accessibleNamespaces)
+	for _, namespace := range namespaces {
+		listWatch := cache.NewFilteredListWatchFromClient(clientset.CoreV1().RESTClient(), "configmaps", namespace, func(options *meta_v1.ListOptions) {
+			options.LabelSelector = selector
+		})
+		informer := cache.NewSharedIndexInformer(listWatch, &core_v1.ConfigMap{}, resync, cache.Indexers{})
+		informer.AddEventHandler(dashboards)
+		go informer.Run(stop)
+	}
+	log.Infof("Watching the custom dashboards of the ConfigMaps labeled [%s] in namespaces %v", selector, accessibleNamespaces)
+	watchedDashboards, watchStop = dashboards, stop
+	return watchedDashboards, nil
+}
+
+// StopWatchingConfigMaps stops the watch started by WatchConfigMaps
+func StopWatchingConfigMaps() {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+	if watchStop != nil {
+		close(watchStop)
+	}
+	watchedDashboards, watchStop = nil, nil
+}
+
+// watchedNamespaces returns the namespaces whose ConfigMaps are watched: all of them with a single watch when Kiali
+// can access the whole cluster, otherwise each accessible namespace, as Kiali may not be allowed to watch the others.
+func watchedNamespaces(accessibleNamespaces []string) []string {
+	for _, namespace := range accessibleNamespaces {
+		if namespace == "**" {
+			return []string{meta_v1.NamespaceAll}
+		}
+	}
+	return accessibleNamespaces
+}
+
+// OnAdd implements cache.ResourceEventHandler
+func (d *ConfigMapDashboards) OnAdd(obj interface{}) {
+	if cm, ok := obj.(*core_v1.ConfigMap); ok {
+		d.Set(cm)
+	}
+}
+
+// OnUpdate implements cache.ResourceEventHandler
+func (d *ConfigMapDashboards) OnUpdate(oldObj, newObj interface{}) {
+	d.OnAdd(newObj)
+}
+
+// OnDelete implements cache.ResourceEventHandler
+func (d *ConfigMapDashboards) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if cm, ok := obj.(*core_v1.ConfigMap); ok {
+		d.Remove(cm.Namespace, cm.Name)
+	}
+}
+
+// Set replaces the dashboards of a ConfigMap
+func (d *ConfigMapDashboards) Set(cm *core_v1.ConfigMap) {
+	dashboards := parseConfigMap(cm)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.remove(cm.Namespace, cm.Name)
+	if len(dashboards) == 0 {
+		return
+	}
+	if d.dashboards[cm.Namespace] == nil {
+		d.dashboards[cm.Namespace] = make(map[string]v1alpha1.MonitoringDashboard)
+	}
+	names := make([]string, 0, len(dashboards))
+	for _, dashboard := range dashboards {
+		if _, exists := d.dashboards[cm.Namespace][dashboard.Name]; exists {
+			log.Warningf("Dashboard [%s] of ConfigMap [%s/%s] replaces a dashboard of another ConfigMap", dashboard.Name, cm.Namespace, cm.Name)
+		}
+		d.dashboards[cm.Namespace][dashboard.Name] = dashboard
+		names = append(names, dashboard.Name)
+	}
+	d.configMaps[cm.Namespace+"/"+cm.Name] = names
+}
+
+// Remove removes the dashboards of a ConfigMap
+func (d *ConfigMapDashboards) Remove(namespace, name string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.remove(namespace, name)
+}
+
+func (d *ConfigMapDashboards) remove(namespace, name string) {
+	key := namespace + "/" + name
+	for _, dashboard := range d.configMaps[key] {
+		delete(d.dashboards[namespace], dashboard)
+	}
+	delete(d.configMaps, key)
+	if len(d.dashboards[namespace]) == 0 {
+		delete(d.dashboards, namespace)
+	}
+}
+
+// Get returns a dashboard of a namespace
+func (d *ConfigMapDashboards) Get(namespace, name string) (*v1alpha1.MonitoringDashboard, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	dashboard, ok := d.dashboards[namespace][name]
+	if !ok {
+		return nil, false
+	}
+	// Dashboards are modified when resolving their references
+	dashboard.Spec.Items = append([]v1alpha1.MonitoringDashboardItem{}, dashboard.Spec.Items...)
+	return &dashboard, true
+}
+
+// List returns the dashboards of a namespace, sorted by name
+func (d *ConfigMapDashboards) List(namespace string) []v1alpha1.MonitoringDashboard {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	dashboards := make([]v1alpha1.MonitoringDashboard, 0, len(d.dashboards[namespace]))
+	for _, dashboard := range d.dashboards[namespace] {
+		dashboards = append(dashboards, dashboard)
+	}
+	sort.Slice(dashboards, func(i, j int) bool {
+		return dashboards[i].Name < dashboards[j].Name
+	})
+	return dashboards
+}
+
+// parseConfigMap returns the dashboards of a ConfigMap, skipping the entries that are not dashboards
+func parseConfigMap(cm *core_v1.ConfigMap) []v1alpha1.MonitoringDashboard {
+	dashboards := []v1alpha1.MonitoringDashboard{}
+	for key, data := range cm.Data {
+		dashboard := v1alpha1.MonitoringDashboard{}
+		if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096).Decode(&dashboard); err != nil {
+			log.Warningf("Skipping entry [%s] of ConfigMap [%s/%s]: %v", key, cm.Namespace, cm.Name, err)
+			continue
+		}
+		if dashboard.Spec.Title == "" || len(dashboard.Spec.Items) == 0 {
+			log.Warningf("Skipping entry [%s] of ConfigMap [%s/%s]: a dashboard requires a title and items", key, cm.Namespace, cm.Name)
+			continue
+		}
+		if dashboard.Name == "" {
+			dashboard.Name = strings.TrimSuffix(key, path.Ext(key))
+		}
+		dashboard.Namespace = cm.Namespace
+		dashboards = append(dashboards, dashboard)
+	}
+	return dashboards
+}
+
+// configMapClient reads the dashboards of the ConfigMaps, then the MonitoringDashboard resources: a ConfigMap
+// dashboard overrides the resource of the same name.
+type configMapClient struct {
+	ClientInterface
+	dashboards *ConfigMapDashboards
+}
+
+// WithConfigMaps returns a client reading the dashboards of the ConfigMaps along with the dashboards of the client
+func WithConfigMaps(client ClientInterface, dashboards *ConfigMapDashboards) ClientInterface {
+	return &configMapClient{ClientInterface: client, dashboards: dashboards}
+}
+
+// GetDashboard returns the dashboard of the given name
+func (in *configMapClient) GetDashboard(namespace, name string) (*v1alpha1.MonitoringDashboard, error) {
+	if dashboard, ok := in.dashboards.Get(namespace, name); ok {
+		return dashboard, nil
+	}
+	return in.ClientInterface.GetDashboard(namespace, name)
+}
+
+// GetDashboards returns all the dashboards of the given namespace
+func (in *configMapClient) GetDashboards(namespace string) ([]v1alpha1.MonitoringDashboard, error) {
+	fromConfigMaps := in.dashboards.List(namespace)
+	resources, err := in.ClientInterface.GetDashboards(namespace)
+	if err != nil {
+		// i.e. the MonitoringDashboard resource is not installed
+		if len(fromConfigMaps) > 0 {
+			log.Debugf("Cannot list the dashboard resources of namespace [%s]: %v", namespace, err)
+			return fromConfigMaps, nil
+		}
+		return nil, err
+	}
+	all := fromConfigMaps
+	for _, resource := range resources {
+		if _, overridden := in.dashboards.Get(namespace, resource.Name); !overridden {
+			all = append(all, resource)
+		}
+	}
+	return all, nil
+}
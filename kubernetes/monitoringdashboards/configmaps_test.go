@@ -0,0 +1,119 @@
+package monitoringdashboards
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+)
+
+const yamlDashboard = `
+spec:
+  title: My runtime
+  items:
+  - chart:
+      name: Threads
+      dataType: raw
+      metrics:
+      - metricName: my_threads
+        displayName: Threads
+`
+
+const jsonDashboard = `{"metadata":{"name":"other"},"spec":{"title":"Other","items":[{"include":"my-runtime"}]}}`
+
+type fakeClient struct {
+	dashboards []v1alpha1.MonitoringDashboard
+	err        error
+}
+
+func (c fakeClient) GetDashboard(namespace, name string) (*v1alpha1.MonitoringDashboard, error) {
+	for _, d := range c.dashboards {
+		if d.Name == name {
+			return &d, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (c fakeClient) GetDashboards(namespace string) ([]v1alpha1.MonitoringDashboard, error) {
+	return c.dashboards, c.err
+}
+
+func TestConfigMapDashboards(t *testing.T) {
+	assert := assert.New(t)
+	dashboards := NewConfigMapDashboards()
+
+	cm := &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "bookinfo", Name: "dashboards"},
+		Data: map[string]string{
+			"my-runtime.yaml": yamlDashboard,
+			"other.json":      jsonDashboard,
+			"README":          "not a dashboard",
+		},
+	}
+	dashboards.OnAdd(cm)
+
+	dashboard, ok := dashboards.Get("bookinfo", "my-runtime")
+	assert.True(ok)
+	assert.Equal("My runtime", dashboard.Spec.Title)
+	assert.Equal("my_threads", dashboard.Spec.Items[0].Chart.Metrics[0].MetricName)
+	_, ok = dashboards.Get("other-namespace", "my-runtime")
+	assert.False(ok)
+	assert.Len(dashboards.List("bookinfo"), 2)
+
+	// Hot reload
+	updated := cm.DeepCopy()
+	delete(updated.Data, "other.json")
+	dashboards.OnUpdate(cm, updated)
+	_, ok = dashboards.Get("bookinfo", "other")
+	assert.False(ok)
+	assert.Len(dashboards.List("bookinfo"), 1)
+
+	dashboards.OnDelete(updated)
+	assert.Empty(dashboards.List("bookinfo"))
+}
+
+func TestConfigMapClient(t *testing.T) {
+	assert := assert.New(t)
+	dashboards := NewConfigMapDashboards()
+	dashboards.Set(&core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "bookinfo", Name: "dashboards"},
+		Data:       map[string]string{"my-runtime.yaml": yamlDashboard},
+	})
+	resources := []v1alpha1.MonitoringDashboard{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "my-runtime"}, Spec: v1alpha1.MonitoringDashboardSpec{Title: "Resource"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "vertx"}, Spec: v1alpha1.MonitoringDashboardSpec{Title: "Vert.x"}},
+	}
+	client := WithConfigMaps(fakeClient{dashboards: resources}, dashboards)
+
+	// ConfigMaps override the resources
+	dashboard, err := client.GetDashboard("bookinfo", "my-runtime")
+	assert.NoError(err)
+	assert.Equal("My runtime", dashboard.Spec.Title)
+	dashboard, err = client.GetDashboard("bookinfo", "vertx")
+	assert.NoError(err)
+	assert.Equal("Vert.x", dashboard.Spec.Title)
+
+	all, err := client.GetDashboards("bookinfo")
+	assert.NoError(err)
+	assert.Len(all, 2)
+	assert.Equal("My runtime", all[0].Spec.Title)
+
+	// Without the MonitoringDashboard resource
+	client = WithConfigMaps(fakeClient{err: errors.New("the server could not find the requested resource")}, dashboards)
+	all, err = client.GetDashboards("bookinfo")
+	assert.NoError(err)
+	assert.Len(all, 1)
+	_, err = client.GetDashboards("other-namespace")
+	assert.Error(err)
+}
+
+func TestWatchedNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]string{meta_v1.NamespaceAll}, watchedNamespaces([]string{"bookinfo", "**"}))
+	assert.Equal([]string{"bookinfo", "travels"}, watchedNamespaces([]string{"bookinfo", "travels"}))
+}
@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"encoding/json"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label set by Argo Rollouts on the ReplicaSets and pods of each revision of a Rollout
+const RolloutPodTemplateHashLabel = "rollouts-pod-template-hash"
+
+// Rollout holds the fields of the Argo Rollouts (argoproj.io/v1alpha1) read by Kiali
+type Rollout struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec               RolloutSpec   `json:"spec"`
+	Status             RolloutStatus `json:"status,omitempty"`
+}
+
+type RolloutSpec struct {
+	Replicas *int32                  `json:"replicas,omitempty"`
+	Selector *meta_v1.LabelSelector  `json:"selector,omitempty"`
+	Template core_v1.PodTemplateSpec `json:"template"`
+	Paused   bool                    `json:"paused,omitempty"`
+	Strategy RolloutStrategy         `json:"strategy"`
+}
+
+// RolloutStrategy is either a canary or a blue-green strategy
+type RolloutStrategy struct {
+	BlueGreen *RolloutBlueGreenStrategy `json:"blueGreen,omitempty"`
+	Canary    *RolloutCanaryStrategy    `json:"canary,omitempty"`
+}
+
+type RolloutBlueGreenStrategy struct {
+	ActiveService  string `json:"activeService"`
+	PreviewService string `json:"previewService,omitempty"`
+}
+
+type RolloutCanaryStrategy struct {
+	CanaryService string `json:"canaryService,omitempty"`
+	StableService string `json:"stableService,omitempty"`
+	// Steps of the canary, such as setWeight or pause, kept as is
+	Steps []map[string]interface{} `json:"steps,omitempty"`
+}
+
+type RolloutStatus struct {
+	Replicas          int32 `json:"replicas,omitempty"`
+	UpdatedReplicas   int32 `json:"updatedReplicas,omitempty"`
+	ReadyReplicas     int32 `json:"readyReplicas,omitempty"`
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+	// Hash of the pod template of the latest revision, and of the stable revision
+	CurrentPodHash   string `json:"currentPodHash,omitempty"`
+	StableRS         string `json:"stableRS,omitempty"`
+	CurrentStepIndex *int32 `json:"currentStepIndex,omitempty"`
+	// Phase of the Rollout: Progressing, Paused, Healthy or Degraded
+	Phase           string                  `json:"phase,omitempty"`
+	Message         string                  `json:"message,omitempty"`
+	PauseConditions []RolloutPauseCondition `json:"pauseConditions,omitempty"`
+	Canary          struct {
+		Weights *RolloutTrafficWeights `json:"weights,omitempty"`
+	} `json:"canary,omitempty"`
+}
+
+type RolloutPauseCondition struct {
+	Reason    string       `json:"reason"`
+	StartTime meta_v1.Time `json:"startTime"`
+}
+
+// RolloutTrafficWeights holds the weights of the traffic routed to the canary and stable revisions
+type RolloutTrafficWeights struct {
+	Canary struct {
+		Weight int32 `json:"weight"`
+	} `json:"canary"`
+	Stable struct {
+		Weight int32 `json:"weight"`
+	} `json:"stable"`
+}
+
+// hasRolloutsApi checks once if the API of Argo Rollouts is served
+func (in *K8SClient) hasRolloutsApi() bool {
+	if in.isRolloutsApi == nil {
+		isRolloutsApi := false
+		_, err := in.k8s.RESTClient().Get().AbsPath("/apis/argoproj.io/v1alpha1").Do().Raw()
+		if err == nil {
+			isRolloutsApi = true
+		}
+		in.isRolloutsApi = &isRolloutsApi
+	}
+	return *in.isRolloutsApi
+}
+
+// GetRollouts returns the Argo Rollouts of a namespace, none when Argo Rollouts is not installed
+func (in *K8SClient) GetRollouts(namespace string) ([]Rollout, error) {
+	if !in.hasRolloutsApi() {
+		return []Rollout{}, nil
+	}
+	result, err := in.k8s.RESTClient().Get().Prefix("apis", "argoproj.io", "v1alpha1").Namespace(namespace).Resource("rollouts").DoRaw()
+	if err != nil {
+		return []Rollout{}, err
+	}
+	var rolloutList struct {
+		Items []Rollout `json:"items"`
+	}
+	if err := json.Unmarshal(result, &rolloutList); err != nil {
+		return []Rollout{}, err
+	}
+	return rolloutList.Items, nil
+}
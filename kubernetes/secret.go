@@ -1,9 +1,11 @@
 package kubernetes
 
 import (
+	"fmt"
 	"io/ioutil"
 
 	yaml "gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
 )
 
 type RemoteSecretCluster struct {
@@ -39,14 +41,42 @@ type RemoteSecret struct {
 }
 
 func GetRemoteSecret(path string) (*RemoteSecret, error) {
-	secret := &RemoteSecret{}
 	secretFile, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	err = yaml.Unmarshal(secretFile, &secret)
+	return parseRemoteSecret(secretFile)
+}
+
+// GetRemoteSecretFromSecret reads the remote secret stored at the given key of a Secret. The key can be omitted
+// when the Secret holds a single key.
+func GetRemoteSecretFromSecret(secret *core_v1.Secret, key string) (*RemoteSecret, error) {
+	if key == "" {
+		if len(secret.Data) != 1 {
+			return nil, fmt.Errorf("the key of the remote secret is required, Secret [%s/%s] holds %d keys", secret.Namespace, secret.Name, len(secret.Data))
+		}
+		for k := range secret.Data {
+			key = k
+		}
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("Secret [%s/%s] has no key [%s]", secret.Namespace, secret.Name, key)
+	}
+	remoteSecret, err := parseRemoteSecret(data)
 	if err != nil {
 		return nil, err
 	}
+	if len(remoteSecret.Clusters) == 0 {
+		return nil, fmt.Errorf("the remote secret in Secret [%s/%s] has no cluster", secret.Namespace, secret.Name)
+	}
+	return remoteSecret, nil
+}
+
+func parseRemoteSecret(data []byte) (*RemoteSecret, error) {
+	secret := &RemoteSecret{}
+	if err := yaml.Unmarshal(data, &secret); err != nil {
+		return nil, err
+	}
 	return secret, nil
 }
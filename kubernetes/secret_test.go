@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const fakeRemoteSecret = `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://east.example.com:6443
+  name: east
+users:
+- name: kiali
+  user:
+    token: sa-token
+`
+
+func TestGetRemoteSecretFromSecret(t *testing.T) {
+	assert := assert.New(t)
+	secret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "istio-system", Name: "istio-remote-secret-east"},
+		Data:       map[string][]byte{"east": []byte(fakeRemoteSecret)},
+	}
+
+	// The key can be omitted when the Secret holds a single key
+	remoteSecret, err := GetRemoteSecretFromSecret(secret, "")
+	assert.NoError(err)
+	assert.Equal("https://east.example.com:6443", remoteSecret.Clusters[0].Cluster.Server)
+	assert.Equal("sa-token", remoteSecret.Users[0].User.Token)
+
+	_, err = GetRemoteSecretFromSecret(secret, "west")
+	assert.Error(err)
+
+	secret.Data["west"] = []byte("apiVersion: v1\nkind: Config\n")
+	_, err = GetRemoteSecretFromSecret(secret, "")
+	assert.Error(err)
+	_, err = GetRemoteSecretFromSecret(secret, "west")
+	assert.Error(err)
+	_, err = GetRemoteSecretFromSecret(secret, "east")
+	assert.NoError(err)
+}
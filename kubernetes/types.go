@@ -20,6 +20,7 @@ const (
 	PodType                   = "Pod"
 	ReplicationControllerType = "ReplicationController"
 	ReplicaSetType            = "ReplicaSet"
+	RolloutType               = "Rollout"
 	ServiceType               = "Service"
 	StatefulSetType           = "StatefulSet"
 
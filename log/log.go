@@ -106,6 +106,11 @@ func Tracef(format string, args ...interface{}) {
 	log.Trace().Msgf(format, args...)
 }
 
+// Audit logs a structured event in the given log group. Audit events are logged regardless of the log level.
+func Audit(group string, fields map[string]interface{}, message string) {
+	log.Log().Str("group", group).Fields(fields).Msg(message)
+}
+
 func IsTrace() bool {
 	return zerolog.GlobalLevel() == zerolog.TraceLevel
 }
@@ -0,0 +1,155 @@
+package loki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// Range queries read many chunks, they take longer than the queries of the other external services
+const queryTimeout = 20 * time.Second
+
+// ClientInterface for mocks (only mocked function are necessary here)
+type ClientInterface interface {
+	QueryLogs(query string, start, end time.Time, limit int) ([]Entry, error)
+}
+
+// Entry is a log line of a stream of Loki
+type Entry struct {
+	Time   time.Time
+	Line   string
+	Labels map[string]string
+}
+
+// Client for the Loki API.
+type Client struct {
+	ClientInterface
+	client  http.Client
+	baseURL *url.URL
+	tenant  string
+}
+
+// queryResponse is the response of the query_range endpoint, for LogQL queries returning streams
+type queryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			// Pairs of the time of the line, in nanoseconds since the epoch, and the line
+			Values [][2]string `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// NewClient creates a client for the configured Loki
+func NewClient(token string) (ClientInterface, error) {
+	cfg := config.Get()
+	cfgLoki := cfg.ExternalServices.Loki
+
+	if !cfgLoki.Enabled {
+		return nil, errors.New("loki is not available")
+	}
+	auth := cfgLoki.Auth
+	if auth.UseKialiToken {
+		auth.Token = token
+	}
+	u, err := url.Parse(cfgLoki.InClusterURL)
+	if !cfg.InCluster {
+		u, err = url.Parse(cfgLoki.URL)
+	}
+	if err != nil {
+		log.Errorf("Error parse Loki URL: %s", err)
+		return nil, err
+	}
+	transport, err := httputil.AuthTransport(&auth, &http.Transport{})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: http.Client{Transport: transport, Timeout: queryTimeout}, baseURL: u, tenant: cfgLoki.TenantID}, nil
+}
+
+// QueryLogs runs a LogQL query returning log lines, keeping the latest lines up to the limit, ordered by time
+func (in *Client) QueryLogs(query string, start, end time.Time, limit int) ([]Entry, error) {
+	u := *in.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/loki/api/v1/query_range"
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	q.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	q.Set("direction", "backward")
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	u.RawQuery = q.Encode()
+	log.Debugf("Prepared Loki query: %v", u.String())
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+	if in.tenant != "" {
+		req.Header.Add("X-Scope-OrgID", in.tenant)
+	}
+	resp, err := in.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Loki query failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response queryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("Unknown response from Loki: %v", err)
+	}
+	if response.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("LogQL query [%s] does not return log lines but %s", query, response.Data.ResultType)
+	}
+	entries := []Entry{}
+	for _, stream := range response.Data.Result {
+		for _, value := range stream.Values {
+			nanos, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid time of a Loki log line [%s]: %v", value[0], err)
+			}
+			entries = append(entries, Entry{Time: time.Unix(0, nanos).UTC(), Line: value[1], Labels: stream.Stream})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// PodLogsQuery builds the LogQL query of the logs of a container, or of all the containers of a pod, keeping the
+// lines matching the regular expression when set
+func PodLogsQuery(labels config.LokiLabelsConfig, namespace, pod, container, filter string) string {
+	matchers := []string{
+		labels.Namespace + "=" + strconv.Quote(namespace),
+		labels.Pod + "=" + strconv.Quote(pod),
+	}
+	if container != "" {
+		matchers = append(matchers, labels.Container+"="+strconv.Quote(container))
+	}
+	query := "{" + strings.Join(matchers, ",") + "}"
+	if filter != "" {
+		query += " |~ " + strconv.Quote(filter)
+	}
+	return query
+}
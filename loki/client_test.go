@@ -0,0 +1,79 @@
+package loki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+const lokiStreams = `{"status":"success","data":{"resultType":"streams","result":[
+  {"stream":{"namespace":"bookinfo","pod":"reviews-v1-545db77b95-wnvc9","container":"istio-proxy"},
+   "values":[["1613942556000000000","second line of istio-proxy"],["1613942555000000000","first line of istio-proxy"]]},
+  {"stream":{"namespace":"bookinfo","pod":"reviews-v1-545db77b95-wnvc9","container":"reviews"},
+   "values":[["1613942555500000000","line of reviews"]]}
+]}}`
+
+func TestQueryLogs(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	var received url.Values
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/loki/api/v1/query_range":
+			received = r.URL.Query()
+			tenant = r.Header.Get("X-Scope-OrgID")
+			_, _ = w.Write([]byte(lokiStreams))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("parse error at line 1, col 1: syntax error: unexpected IDENTIFIER\n"))
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	client := Client{client: http.Client{}, baseURL: baseURL, tenant: "team-a"}
+
+	start := time.Unix(1613942500, 0)
+	end := time.Unix(1613942600, 0)
+	entries, err := client.QueryLogs(`{namespace="bookinfo",pod="reviews-v1-545db77b95-wnvc9"}`, start, end, 100)
+	assert.NoError(err)
+	assert.Equal(`{namespace="bookinfo",pod="reviews-v1-545db77b95-wnvc9"}`, received.Get("query"))
+	assert.Equal("1613942500000000000", received.Get("start"))
+	assert.Equal("1613942600000000000", received.Get("end"))
+	assert.Equal("100", received.Get("limit"))
+	assert.Equal("backward", received.Get("direction"))
+	assert.Equal("team-a", tenant)
+
+	assert.Len(entries, 3)
+	assert.Equal("first line of istio-proxy", entries[0].Line)
+	assert.Equal("line of reviews", entries[1].Line)
+	assert.Equal("reviews", entries[1].Labels["container"])
+	assert.Equal("second line of istio-proxy", entries[2].Line)
+	assert.Equal(time.Unix(1613942556, 0).UTC(), entries[2].Time)
+
+	client.baseURL, _ = url.Parse(server.URL + "/other")
+	_, err = client.QueryLogs("bookinfo", start, end, 100)
+	assert.EqualError(err, "Loki query failed with status 400: parse error at line 1, col 1: syntax error: unexpected IDENTIFIER")
+}
+
+func TestPodLogsQuery(t *testing.T) {
+	assert := assert.New(t)
+	labels := config.NewConfig().ExternalServices.Loki.Labels
+
+	assert.Equal(`{namespace="bookinfo",pod="reviews-v1-545db77b95-wnvc9"}`,
+		PodLogsQuery(labels, "bookinfo", "reviews-v1-545db77b95-wnvc9", "", ""))
+	assert.Equal(`{namespace="bookinfo",pod="reviews-v1-545db77b95-wnvc9",container="istio-proxy"} |~ "\" (503|504) \\w+"`,
+		PodLogsQuery(labels, "bookinfo", "reviews-v1-545db77b95-wnvc9", "istio-proxy", `" (503|504) \w+`))
+
+	labels.Pod = "k8s_pod_name"
+	assert.Equal(`{namespace="bookinfo",k8s_pod_name="ratings-v1-b6994bb9-kc6mv"}`,
+		PodLogsQuery(labels, "bookinfo", "ratings-v1-b6994bb9-kc6mv", "", ""))
+}
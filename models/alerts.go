@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Alert is an alert firing in Alertmanager, mapped by its labels to the namespace, service, workload or app it is
+// about
+type Alert struct {
+	// The name of the alert, its label alertname
+	//
+	// required: true
+	// example: HighErrorRate
+	Name string `json:"name"`
+
+	// The severity of the alert, its label severity
+	//
+	// example: critical
+	Severity string `json:"severity,omitempty"`
+
+	// The summary of the alert, its annotation summary
+	Summary string `json:"summary,omitempty"`
+
+	// The description of the alert, its annotation description
+	Description string `json:"description,omitempty"`
+
+	// When the alert started firing
+	//
+	// required: true
+	StartsAt time.Time `json:"startsAt"`
+
+	// The namespace of the alert, empty for the alerts about the cluster
+	//
+	// example: bookinfo
+	Namespace string `json:"namespace,omitempty"`
+
+	// The service of the alert
+	//
+	// example: reviews
+	Service string `json:"service,omitempty"`
+
+	// The workload of the alert
+	//
+	// example: reviews-v1
+	Workload string `json:"workload,omitempty"`
+
+	// The app of the alert
+	//
+	// example: reviews
+	App string `json:"app,omitempty"`
+
+	// All the labels of the alert
+	Labels map[string]string `json:"labels"`
+
+	// Link to the expression of the alert, in Prometheus
+	GeneratorURL string `json:"generatorURL,omitempty"`
+
+	// Link to the alert in the UI of Alertmanager, when its URL is configured
+	URL string `json:"url,omitempty"`
+}
+
+// AlertCriteria filters the firing alerts by the objects they are about. Empty fields don't filter.
+type AlertCriteria struct {
+	App       string
+	Namespace string
+	Service   string
+	Workload  string
+}
+
+// Matches returns true when the alert is about the objects of the criteria
+func (c AlertCriteria) Matches(alert Alert) bool {
+	return (c.Namespace == "" || c.Namespace == alert.Namespace) &&
+		(c.Service == "" || c.Service == alert.Service) &&
+		(c.Workload == "" || c.Workload == alert.Workload) &&
+		(c.App == "" || c.App == alert.App)
+}
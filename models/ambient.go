@@ -0,0 +1,55 @@
+package models
+
+const (
+	// The migration is blocked until the finding is fixed
+	AmbientFindingBlocker = "blocker"
+	// The behavior is kept only by deploying a waypoint proxy
+	AmbientFindingWaypoint = "waypoint"
+	// The behavior changes, or the setting is ignored, once migrated
+	AmbientFindingChange = "change"
+)
+
+// AmbientReadiness tells what blocks or changes when migrating the workloads of a namespace from sidecars to ambient
+type AmbientReadiness struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Readiness score, from 0 to 100, lowered by every finding according to its severity
+	//
+	// required: true
+	// example: 80
+	Score int `json:"score"`
+
+	// Whether the namespace can be migrated, i.e. without blocker
+	//
+	// required: true
+	Ready bool `json:"ready"`
+
+	// Whether some findings require a waypoint proxy to keep their L7 behavior
+	//
+	// required: true
+	WaypointRequired bool `json:"waypointRequired"`
+
+	// required: true
+	Findings []AmbientFinding `json:"findings"`
+}
+
+// AmbientFinding is a workload or a configuration affected by a migration to ambient
+type AmbientFinding struct {
+	// blocker, waypoint or change
+	//
+	// required: true
+	// example: waypoint
+	Severity string `json:"severity"`
+
+	// Kind of the object, i.e. Pod or AuthorizationPolicy
+	//
+	// required: true
+	Kind string `json:"kind"`
+
+	// required: true
+	Name string `json:"name"`
+
+	// required: true
+	Message string `json:"message"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// ApiToken is an API token that automation can use to call the Kiali API.
+// The secret part of the token is only known by its owner: Kiali stores just its hash.
+//
+// swagger:model apiToken
+type ApiToken struct {
+	// The id of the token
+	//
+	// required: true
+	ID string `json:"id"`
+
+	// A name to identify the purpose of the token
+	//
+	// example: ci-pipeline
+	// required: true
+	Name string `json:"name"`
+
+	// The user that created the token, as known by the cluster
+	//
+	// required: true
+	Owner string `json:"owner"`
+
+	// The groups of the owner when the token was created. Requests with the token impersonate the owner and these groups.
+	OwnerGroups []string `json:"ownerGroups,omitempty"`
+
+	// What the token is allowed to access
+	//
+	// required: true
+	Scope ApiTokenScope `json:"scope"`
+
+	// Creation date of the token
+	//
+	// required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Expiration date of the token
+	//
+	// required: true
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ApiTokenScope restricts what an API token is allowed to access
+type ApiTokenScope struct {
+	// The namespaces the token can access
+	//
+	// required: true
+	Namespaces []string `json:"namespaces"`
+
+	// If true, the token can only be used for read operations
+	//
+	// required: true
+	ViewOnly bool `json:"viewOnly"`
+}
+
+// AllowsNamespace returns true if the scope grants access to the given namespace
+func (scope ApiTokenScope) AllowsNamespace(namespace string) bool {
+	for _, ns := range scope.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ApiTokenRequest holds the parameters to create an API token
+//
+// swagger:model apiTokenRequest
+type ApiTokenRequest struct {
+	Name              string        `json:"name"`
+	ExpirationSeconds int64         `json:"expirationSeconds"`
+	Scope             ApiTokenScope `json:"scope"`
+}
+
+// ApiTokenCreated is returned only once, when an API token is created, as it includes the secret token
+//
+// swagger:model apiTokenCreated
+type ApiTokenCreated struct {
+	ApiToken
+
+	// The secret token to be sent in the Authorization header as a Bearer token
+	//
+	// required: true
+	Token string `json:"token"`
+}
@@ -0,0 +1,310 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	autoscaling_v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// HorizontalAutoscaler is the status of a HorizontalPodAutoscaler targeting a workload
+type HorizontalAutoscaler struct {
+	// Name of the HorizontalPodAutoscaler
+	// required: true
+	Name string `json:"name"`
+
+	// Bounds of the replicas
+	// example: 1
+	MinReplicas int32 `json:"minReplicas"`
+	// example: 10
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Current and desired replicas as last computed by the autoscaler
+	// example: 2
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// example: 3
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Last time the autoscaler changed the replicas, RFC3339
+	LastScaleTime string `json:"lastScaleTime,omitempty"`
+
+	// Metrics the replicas are computed from, with their current values and targets
+	Metrics []AutoscalerMetric `json:"metrics"`
+
+	// Conditions of the autoscaler: AbleToScale, ScalingActive and ScalingLimited
+	Conditions []AutoscalerCondition `json:"conditions"`
+}
+
+// AutoscalerMetric is a metric of a HorizontalPodAutoscaler
+type AutoscalerMetric struct {
+	// Name of the metric, the resource for the cpu and memory
+	// example: cpu
+	Name string `json:"name"`
+	// example: Resource
+	Type string `json:"type"`
+	// Current value, as utilization percentage or quantity
+	// example: 85%
+	Current string `json:"current,omitempty"`
+	// example: 60%
+	Target string `json:"target,omitempty"`
+}
+
+// VerticalAutoscaler is the status of a VerticalPodAutoscaler targeting a workload
+type VerticalAutoscaler struct {
+	// Name of the VerticalPodAutoscaler
+	// required: true
+	Name string `json:"name"`
+
+	// Whether the recommendations are applied: Off, Initial, Recreate or Auto
+	// example: Auto
+	UpdateMode string `json:"updateMode,omitempty"`
+
+	// Resources recommended for each container
+	Recommendations []ContainerRecommendation `json:"recommendations"`
+
+	// Conditions of the autoscaler, such as RecommendationProvided
+	Conditions []AutoscalerCondition `json:"conditions"`
+}
+
+// ContainerRecommendation holds the resources recommended for a container by a VerticalPodAutoscaler
+type ContainerRecommendation struct {
+	Container  string            `json:"container"`
+	Target     map[string]string `json:"target"`
+	LowerBound map[string]string `json:"lowerBound,omitempty"`
+	UpperBound map[string]string `json:"upperBound,omitempty"`
+}
+
+// ScaledObject is the status of a KEDA ScaledObject scaling a workload, down to zero replicas when it is idle
+type ScaledObject struct {
+	// Name of the ScaledObject
+	// required: true
+	Name string `json:"name"`
+
+	// Bounds of the replicas, the minimum is 0 when the workload scales to zero
+	// example: 0
+	MinReplicas int32 `json:"minReplicas"`
+	// example: 100
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Whether the autoscaling is paused by annotation, and the replicas it is paused at, if any
+	Paused         bool   `json:"paused"`
+	PausedReplicas *int32 `json:"pausedReplicas,omitempty"`
+
+	// Whether a trigger is active, i.e. the workload is scaled above zero
+	Active bool `json:"active"`
+	// Last time a trigger was active, RFC3339
+	LastActiveTime string `json:"lastActiveTime,omitempty"`
+
+	// Triggers of the scaling, with their current values and targets
+	Triggers []ScaledObjectTrigger `json:"triggers"`
+
+	// Conditions of the ScaledObject: Ready, Active, Fallback and Paused
+	Conditions []AutoscalerCondition `json:"conditions"`
+}
+
+// ScaledObjectTrigger is a trigger of a ScaledObject
+type ScaledObjectTrigger struct {
+	// Type of the scaler
+	// example: prometheus
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	// Current value and target of the metric of the trigger, as computed by the HorizontalPodAutoscaler of KEDA
+	// example: 25
+	Current string `json:"current,omitempty"`
+	// example: 50
+	Target string `json:"target,omitempty"`
+}
+
+// AutoscalerCondition is a condition of an autoscaler
+type AutoscalerCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// Parse reads the status of a HorizontalPodAutoscaler
+func (a *HorizontalAutoscaler) Parse(hpa *autoscaling_v2beta2.HorizontalPodAutoscaler) {
+	a.Name = hpa.Name
+	a.MinReplicas = 1
+	if hpa.Spec.MinReplicas != nil {
+		a.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	a.MaxReplicas = hpa.Spec.MaxReplicas
+	a.CurrentReplicas = hpa.Status.CurrentReplicas
+	a.DesiredReplicas = hpa.Status.DesiredReplicas
+	if hpa.Status.LastScaleTime != nil {
+		a.LastScaleTime = formatTime(hpa.Status.LastScaleTime.Time)
+	}
+
+	a.Metrics = make([]AutoscalerMetric, 0, len(hpa.Spec.Metrics))
+	for i, spec := range hpa.Spec.Metrics {
+		metric := AutoscalerMetric{Type: string(spec.Type)}
+		var target *autoscaling_v2beta2.MetricTarget
+		switch {
+		case spec.Type == autoscaling_v2beta2.ResourceMetricSourceType && spec.Resource != nil:
+			metric.Name = string(spec.Resource.Name)
+			target = &spec.Resource.Target
+		case spec.Type == autoscaling_v2beta2.PodsMetricSourceType && spec.Pods != nil:
+			metric.Name = spec.Pods.Metric.Name
+			target = &spec.Pods.Target
+		case spec.Type == autoscaling_v2beta2.ObjectMetricSourceType && spec.Object != nil:
+			metric.Name = spec.Object.Metric.Name
+			target = &spec.Object.Target
+		case spec.Type == autoscaling_v2beta2.ExternalMetricSourceType && spec.External != nil:
+			metric.Name = spec.External.Metric.Name
+			target = &spec.External.Target
+		}
+		if target != nil {
+			metric.Target = formatMetricTarget(target.AverageUtilization, target.AverageValue, target.Value)
+		}
+		// The current metrics are in the order of the metrics of the spec
+		if i < len(hpa.Status.CurrentMetrics) {
+			current := hpa.Status.CurrentMetrics[i]
+			var value *autoscaling_v2beta2.MetricValueStatus
+			switch {
+			case current.Type == autoscaling_v2beta2.ResourceMetricSourceType && current.Resource != nil:
+				value = &current.Resource.Current
+			case current.Type == autoscaling_v2beta2.PodsMetricSourceType && current.Pods != nil:
+				value = &current.Pods.Current
+			case current.Type == autoscaling_v2beta2.ObjectMetricSourceType && current.Object != nil:
+				value = &current.Object.Current
+			case current.Type == autoscaling_v2beta2.ExternalMetricSourceType && current.External != nil:
+				value = &current.External.Current
+			}
+			if value != nil {
+				metric.Current = formatMetricTarget(value.AverageUtilization, value.AverageValue, value.Value)
+			}
+		}
+		a.Metrics = append(a.Metrics, metric)
+	}
+
+	a.Conditions = make([]AutoscalerCondition, 0, len(hpa.Status.Conditions))
+	for _, c := range hpa.Status.Conditions {
+		a.Conditions = append(a.Conditions, AutoscalerCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatTime(c.LastTransitionTime.Time),
+		})
+	}
+}
+
+// Parse reads the recommendations of a VerticalPodAutoscaler
+func (a *VerticalAutoscaler) Parse(vpa *kubernetes.VerticalPodAutoscaler) {
+	a.Name = vpa.Name
+	// Auto is the default mode of the VPA
+	a.UpdateMode = "Auto"
+	if vpa.Spec.UpdatePolicy != nil && vpa.Spec.UpdatePolicy.UpdateMode != "" {
+		a.UpdateMode = vpa.Spec.UpdatePolicy.UpdateMode
+	}
+
+	a.Recommendations = []ContainerRecommendation{}
+	if vpa.Status.Recommendation != nil {
+		for _, r := range vpa.Status.Recommendation.ContainerRecommendations {
+			a.Recommendations = append(a.Recommendations, ContainerRecommendation{
+				Container:  r.ContainerName,
+				Target:     formatResources(r.Target),
+				LowerBound: formatResources(r.LowerBound),
+				UpperBound: formatResources(r.UpperBound),
+			})
+		}
+	}
+
+	a.Conditions = make([]AutoscalerCondition, 0, len(vpa.Status.Conditions))
+	for _, c := range vpa.Status.Conditions {
+		a.Conditions = append(a.Conditions, AutoscalerCondition{
+			Type:               c.Type,
+			Status:             c.Status,
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatTime(c.LastTransitionTime.Time),
+		})
+	}
+}
+
+// formatMetricTarget formats the value of a metric, a percentage for the utilization of resources
+func formatMetricTarget(utilization *int32, averageValue, value *resource.Quantity) string {
+	switch {
+	case utilization != nil:
+		return fmt.Sprintf("%d%%", *utilization)
+	case averageValue != nil:
+		return averageValue.String()
+	case value != nil:
+		return value.String()
+	}
+	return ""
+}
+
+func formatResources(resources core_v1.ResourceList) map[string]string {
+	if len(resources) == 0 {
+		return nil
+	}
+	formatted := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		formatted[string(name)] = quantity.String()
+	}
+	return formatted
+}
+
+// Parse reads a KEDA ScaledObject. KEDA scales through a HorizontalPodAutoscaler, which holds the current values of
+// the triggers: its metrics are named after the index of the trigger, like s0-prometheus, except for cpu and memory.
+func (s *ScaledObject) Parse(so *kubernetes.ScaledObject, hpa *HorizontalAutoscaler) {
+	s.Name = so.Name
+	// Defaults of KEDA
+	s.MinReplicas = 0
+	if so.Spec.MinReplicaCount != nil {
+		s.MinReplicas = *so.Spec.MinReplicaCount
+	}
+	s.MaxReplicas = 100
+	if so.Spec.MaxReplicaCount != nil {
+		s.MaxReplicas = *so.Spec.MaxReplicaCount
+	}
+	if replicas, ok := so.Annotations[kubernetes.ScaledObjectPausedReplicasAnnotation]; ok {
+		s.Paused = true
+		if n, err := strconv.ParseInt(replicas, 10, 32); err == nil {
+			pausedReplicas := int32(n)
+			s.PausedReplicas = &pausedReplicas
+		}
+	}
+	if paused, err := strconv.ParseBool(so.Annotations[kubernetes.ScaledObjectPausedAnnotation]); err == nil && paused {
+		s.Paused = true
+	}
+	if so.Status.LastActiveTime != nil {
+		s.LastActiveTime = formatTime(so.Status.LastActiveTime.Time)
+	}
+
+	s.Conditions = make([]AutoscalerCondition, 0, len(so.Status.Conditions))
+	for _, c := range so.Status.Conditions {
+		s.Conditions = append(s.Conditions, AutoscalerCondition{Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message})
+		switch {
+		case c.Type == "Active" && c.Status == "True":
+			s.Active = true
+		case c.Type == "Paused" && c.Status == "True":
+			s.Paused = true
+		}
+	}
+
+	s.Triggers = make([]ScaledObjectTrigger, 0, len(so.Spec.Triggers))
+	for i, t := range so.Spec.Triggers {
+		trigger := ScaledObjectTrigger{Type: t.Type, Name: t.Name}
+		if hpa != nil {
+			prefix := fmt.Sprintf("s%d-", i)
+			for _, m := range hpa.Metrics {
+				if (m.Type == "External" && strings.HasPrefix(m.Name, prefix)) || (m.Type == "Resource" && m.Name == t.Type) {
+					trigger.Current = m.Current
+					trigger.Target = m.Target
+					break
+				}
+			}
+		}
+		s.Triggers = append(s.Triggers, trigger)
+	}
+}
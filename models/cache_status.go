@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// CacheStatus describes the state of the caches of Kiali, to diagnose why Kiali is slow or shows stale data
+//
+// swagger:model cacheStatus
+type CacheStatus struct {
+	// Whether the Kiali cache of Kubernetes and Istio objects is enabled
+	//
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Status of the cache for each cluster
+	//
+	// required: true
+	Clusters []ClusterCacheStatus `json:"clusters"`
+
+	// Hits and misses of each cache, i.e. "kubernetes" or "results"
+	//
+	// required: true
+	Counters map[string]CacheCounters `json:"counters"`
+}
+
+// ClusterCacheStatus is the status of the Kiali cache for a cluster
+type ClusterCacheStatus struct {
+	// Name of the cluster
+	//
+	// required: true
+	// example: Kubernetes
+	Cluster string `json:"cluster"`
+
+	// Whether the objects of the cluster are cached. Only the home cluster of Kiali is cached.
+	//
+	// required: true
+	Cached bool `json:"cached"`
+
+	// Status of the informers watching the objects of the cluster
+	//
+	// required: true
+	Informers []InformerStatus `json:"informers"`
+
+	// Namespaces whose cache is warming up in the background. Requests to these namespaces are served without cache.
+	//
+	// required: true
+	Warming []string `json:"warming"`
+}
+
+// InformerStatus is the status of the informer watching the objects of a kind in a namespace
+type InformerStatus struct {
+	// Namespace watched by the informer
+	//
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Group of the watched objects, empty for the core group
+	//
+	// example: networking.istio.io
+	Group string `json:"group"`
+
+	// Version of the watched objects
+	//
+	// required: true
+	// example: v1alpha3
+	Version string `json:"version"`
+
+	// Kind of the watched objects
+	//
+	// required: true
+	// example: VirtualService
+	Kind string `json:"kind"`
+
+	// When the informer started watching the objects
+	Since time.Time `json:"since"`
+
+	// Whether the informer completed its initial listing of the objects
+	//
+	// required: true
+	Synced bool `json:"synced"`
+
+	// The resourceVersion of the last synchronization with the API server
+	LastSyncResourceVersion string `json:"lastSyncResourceVersion"`
+
+	// Number of objects held by the informer
+	//
+	// required: true
+	Objects int `json:"objects"`
+
+	// Estimation of the memory used by the objects, in bytes
+	//
+	// required: true
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// CacheCounters counts the requests served by a cache
+type CacheCounters struct {
+	// Requests served by the cache
+	//
+	// required: true
+	Hits uint64 `json:"hits"`
+
+	// Requests not served by the cache
+	//
+	// required: true
+	Misses uint64 `json:"misses"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+const (
+	CertificateValid    = "valid"
+	CertificateExpiring = "expiring"
+	CertificateExpired  = "expired"
+)
+
+// MeshCertificates lists the certificates of the control plane and of the proxies of the mesh, with their expiration
+type MeshCertificates struct {
+	// Root and intermediate certificates of the CA of istiod
+	//
+	// required: true
+	ControlPlane []Certificate `json:"controlPlane"`
+
+	// Workload certificates of the proxies, only when a namespace is requested
+	//
+	// required: true
+	Proxies []ProxyCertificates `json:"proxies"`
+
+	// Number of certificates expiring or expired
+	//
+	// required: true
+	Warnings int `json:"warnings"`
+
+	// Sources which could not be read, i.e. a Secret not readable by the user
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ProxyCertificates lists the certificates loaded by the proxy of a pod
+type ProxyCertificates struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Pod string `json:"pod"`
+	// required: true
+	Certificates []Certificate `json:"certificates"`
+	// Set when the config dump of the proxy could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// Certificate is a X.509 certificate of the mesh
+type Certificate struct {
+	// Where the certificate was read, i.e. secret istio-system/cacerts or ROOTCA for a proxy
+	//
+	// required: true
+	// example: secret istio-system/cacerts, root-cert.pem
+	Source string `json:"source"`
+
+	// required: true
+	Subject string `json:"subject"`
+	// required: true
+	Issuer string `json:"issuer"`
+	// required: true
+	SerialNumber string `json:"serialNumber"`
+	// Subject alternative names, i.e. the SPIFFE identity of a workload
+	SANs []string `json:"sans,omitempty"`
+	// required: true
+	IsCA bool `json:"isCA"`
+	// required: true
+	NotBefore time.Time `json:"notBefore"`
+	// required: true
+	NotAfter time.Time `json:"notAfter"`
+
+	// valid, expiring or expired
+	//
+	// required: true
+	// example: valid
+	Status string `json:"status"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Kinds of the changes of a timeline
+const (
+	ChangeKindAudit    = "audit"
+	ChangeKindConfig   = "config"
+	ChangeKindRollout  = "rollout"
+	ChangeKindScaling  = "scaling"
+	ChangeOpCreated    = "created"
+	ChangeOpModified   = "modified"
+	ChangeOpRolledOut  = "rolledOut"
+	ChangeOpScaled     = "scaled"
+	ChangeOpKialiWrite = "kialiWrite"
+)
+
+// Change is a change of a namespace at a point of time: the modification of an Istio object, the rollout or the
+// scaling of a workload, or a write made through Kiali
+type Change struct {
+	// When the change occurred
+	//
+	// required: true
+	Time time.Time `json:"time"`
+
+	// The kind of the change: config, rollout, scaling or audit
+	//
+	// required: true
+	// example: config
+	Kind string `json:"kind"`
+
+	// The operation of the change: created, modified, rolledOut, scaled or kialiWrite
+	//
+	// required: true
+	// example: modified
+	Operation string `json:"operation"`
+
+	// The kind of the changed object
+	//
+	// example: VirtualService
+	ObjectKind string `json:"objectKind,omitempty"`
+
+	// The name of the changed object
+	//
+	// example: reviews
+	ObjectName string `json:"objectName,omitempty"`
+
+	// A description of the change
+	//
+	// example: revision 3 with images [docker.io/istio/examples-bookinfo-reviews-v2:1.16.2]
+	Message string `json:"message,omitempty"`
+
+	// The author of the change: the field manager of an Istio object, or the user of a write made through Kiali
+	//
+	// example: kubectl-edit
+	Author string `json:"author,omitempty"`
+}
+
+// ChangeTimeline is the ordered list of the changes of a namespace, or of a service, in a time window
+type ChangeTimeline struct {
+	// The namespace of the changes
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The service of the changes, when the timeline is limited to a service
+	Service string `json:"service,omitempty"`
+
+	// The start of the time window
+	//
+	// required: true
+	StartTime time.Time `json:"startTime"`
+
+	// The end of the time window
+	//
+	// required: true
+	EndTime time.Time `json:"endTime"`
+
+	// The changes, the oldest first
+	//
+	// required: true
+	Changes []Change `json:"changes"`
+
+	// The sources of changes which cannot be read, i.e. the events when the user cannot list them
+	Errors map[string]string `json:"errors,omitempty"`
+}
@@ -0,0 +1,297 @@
+package models
+
+import "time"
+
+// RemoteCluster is a remote cluster Kiali connects to
+type RemoteCluster struct {
+	// The name of the cluster
+	//
+	// required: true
+	// example: east
+	Name string `json:"name"`
+
+	// How Kiali authenticates the users against the cluster: token, openid_exchange or service_account
+	//
+	// required: true
+	// example: service_account
+	AuthStrategy string `json:"authStrategy"`
+
+	// Labels of the cluster, i.e. region, environment or tier
+	//
+	// example: {"region":"eu-west"}
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// True when the cluster is registered through the API, false when it is in the configuration of Kiali
+	//
+	// required: true
+	Registered bool `json:"registered"`
+
+	// The Secret holding the remote secret (kubeconfig) of a registered cluster
+	SecretRef *ClusterSecretRef `json:"secretRef,omitempty"`
+}
+
+// ClusterSecretRef references the key of a Secret holding the remote secret (kubeconfig) of a cluster
+type ClusterSecretRef struct {
+	// The namespace of Kiali, for the registered clusters
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Key of the remote secret in the data of the Secret. Optional when the Secret holds a single key.
+	Key string `json:"key,omitempty"`
+}
+
+// ClusterRegistration is the request registering a remote cluster
+type ClusterRegistration struct {
+	Name         string            `json:"name"`
+	AuthStrategy string            `json:"authStrategy"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	SecretRef    ClusterSecretRef  `json:"secretRef"`
+}
+
+// Status of a check of the clusters scoreboard
+const (
+	ClusterCheckDisabled = "disabled"
+	ClusterCheckFailing  = "failing"
+	ClusterCheckOk       = "ok"
+)
+
+// ClusterStatus is the health of the connections of Kiali to a cluster and to its telemetry backends, to find which
+// cluster Kiali is struggling with
+type ClusterStatus struct {
+	// The name of the cluster
+	//
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// True for the home cluster of Kiali
+	//
+	// required: true
+	Home bool `json:"home"`
+
+	// True when all the enabled checks are ok and the informers are synced
+	//
+	// required: true
+	Healthy bool `json:"healthy"`
+
+	// Reachability of the API server of the cluster
+	//
+	// required: true
+	API ClusterCheck `json:"api"`
+
+	// Availability of the Istio API in the cluster
+	//
+	// required: true
+	IstioAPI ClusterCheck `json:"istioApi"`
+
+	// Sync of the informers of the Kiali cache in the cluster
+	//
+	// required: true
+	Informers ClusterInformersStatus `json:"informers"`
+
+	// Reachability of the Prometheus holding the metrics of the cluster
+	//
+	// required: true
+	Prometheus ClusterCheck `json:"prometheus"`
+
+	// Reachability of the tracing backend holding the traces of the cluster
+	//
+	// required: true
+	Tracing ClusterCheck `json:"tracing"`
+}
+
+// ClusterCheck is the result of a check of the clusters scoreboard
+type ClusterCheck struct {
+	// ok, failing or disabled
+	//
+	// required: true
+	// example: ok
+	Status string `json:"status"`
+
+	// Why the check is failing
+	Message string `json:"message,omitempty"`
+
+	// Time taken by the check, in milliseconds
+	//
+	// required: true
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Last time the check was ok, since Kiali started. Unset if it never was.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// ClusterInformersStatus summarizes the informers of the Kiali cache in a cluster
+type ClusterInformersStatus struct {
+	// Whether the objects of the cluster are cached. Only the home cluster of Kiali is cached.
+	//
+	// required: true
+	Cached bool `json:"cached"`
+
+	// Number of informers
+	//
+	// required: true
+	Total int `json:"total"`
+
+	// Number of informers which completed their initial listing of the objects
+	//
+	// required: true
+	Synced int `json:"synced"`
+
+	// Seconds since the oldest informer not synced yet started watching the objects. 0 when all are synced.
+	//
+	// required: true
+	SyncLagSeconds int64 `json:"syncLagSeconds"`
+
+	// Namespaces whose cache is warming up in the background
+	//
+	// required: true
+	Warming []string `json:"warming"`
+}
+
+// ClusterCapabilities lists the optional APIs supported by a cluster
+type ClusterCapabilities struct {
+	// The name of the cluster
+	//
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// Whether the Gateway API is installed
+	//
+	// required: true
+	GatewayAPI bool `json:"gatewayApi"`
+
+	// The most recent version of the Gateway API served, empty when it is not installed
+	//
+	// example: v1
+	GatewayAPIVersion string `json:"gatewayApiVersion,omitempty"`
+
+	// Whether the experimental channel of the Gateway API is installed, i.e. TCPRoute and TLSRoute
+	//
+	// required: true
+	ExperimentalGatewayAPI bool `json:"experimentalGatewayApi"`
+
+	// Whether the Gateway API Inference Extension is installed, i.e. InferencePool
+	//
+	// required: true
+	InferenceAPI bool `json:"inferenceApi"`
+
+	// Whether the ambient mode of Istio is installed, i.e. ztunnel runs in the Istio namespace
+	//
+	// required: true
+	Ambient bool `json:"ambient"`
+
+	// Versions of the revisions of istiod deployed in the cluster
+	//
+	// required: true
+	// example: ["1.8.1"]
+	IstioVersions []string `json:"istioVersions"`
+
+	// Probes which failed: the capabilities they discover are reported as unsupported
+	//
+	// required: true
+	Errors []string `json:"errors"`
+}
+
+// ClusterTrafficMatrix is the traffic between the clusters: the rates of the requests sent by the workloads of each
+// cluster to the services of each cluster
+type ClusterTrafficMatrix struct {
+	// Window of the rates
+	//
+	// required: true
+	// example: 1h
+	Window string `json:"window"`
+
+	// The clusters, the rows and columns of the matrices, "unknown" for traffic not labeled with its cluster
+	//
+	// required: true
+	// example: ["east","west"]
+	Clusters []string `json:"clusters"`
+
+	// Requests per second, by source cluster (row) and destination cluster (column)
+	//
+	// required: true
+	Rates [][]float64 `json:"rates"`
+
+	// Ratio of failed requests, by source cluster (row) and destination cluster (column). 0 without traffic.
+	//
+	// required: true
+	ErrorRates [][]float64 `json:"errorRates"`
+}
+
+// EastWestProbe is the connectivity between the clusters through their east-west gateways, as probed from Kiali
+type EastWestProbe struct {
+	// The east-west gateways of the clusters, and the result of their probe
+	//
+	// required: true
+	Gateways []EastWestGateway `json:"gateways"`
+
+	// The reachability of each cluster from each other cluster
+	//
+	// required: true
+	Pairs []EastWestPair `json:"pairs"`
+
+	// Errors of the clusters which cannot be read, by cluster
+	//
+	// required: true
+	Errors map[string]string `json:"errors"`
+}
+
+// EastWestGateway is an east-west gateway, forwarding the traffic of the other networks to its cluster
+type EastWestGateway struct {
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// required: true
+	// example: istio-system
+	Namespace string `json:"namespace"`
+
+	// required: true
+	// example: istio-eastwestgateway
+	Name string `json:"name"`
+
+	// The network of the cluster, empty when it is not labeled
+	//
+	// example: network1
+	Network string `json:"network"`
+
+	// The address of the gateway reachable from the other networks, empty when it has none
+	//
+	// example: 203.0.113.10
+	Address string `json:"address"`
+
+	// The port of the gateway forwarding the mTLS traffic, 0 when it is not exposed
+	//
+	// example: 15443
+	Port int32 `json:"port"`
+
+	// Whether the handshake through the gateway completed
+	//
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// Why the gateway is not reachable
+	Message string `json:"message,omitempty"`
+
+	// Time taken by the handshake, in milliseconds
+	LatencyMs int64 `json:"latencyMs,omitempty"`
+}
+
+// EastWestPair is the reachability of a destination cluster from a source cluster
+type EastWestPair struct {
+	// required: true
+	// example: west
+	Source string `json:"source"`
+
+	// required: true
+	// example: east
+	Destination string `json:"destination"`
+
+	// Whether the proxies of the source cluster can reach the destination cluster
+	//
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// Why the destination is reachable or not
+	Message string `json:"message,omitempty"`
+}
@@ -18,28 +18,46 @@ type EnvoyProxyDump struct {
 
 type Listeners []*Listener
 type Listener struct {
-	Address     string  `json:"address"`
-	Port        float64 `json:"port"`
-	Match       string  `json:"match"`
-	Destination string  `json:"destination"`
+	Address     string                `json:"address"`
+	Port        float64               `json:"port"`
+	Match       string                `json:"match"`
+	Destination string                `json:"destination"`
+	IstioConfig *IstioConfigReference `json:"istio_config,omitempty"`
 }
 
 type Clusters []*Cluster
 type Cluster struct {
-	ServiceFQDN     string `json:"service_fqdn"`
-	Port            int    `json:"port"`
-	Subset          string `json:"subset"`
-	Direction       string `json:"direction"`
-	Type            string `json:"type"`
-	DestinationRule string `json:"destination_rule"`
+	ServiceFQDN     string                `json:"service_fqdn"`
+	Port            int                   `json:"port"`
+	Subset          string                `json:"subset"`
+	Direction       string                `json:"direction"`
+	Type            string                `json:"type"`
+	DestinationRule string                `json:"destination_rule"`
+	IstioConfig     *IstioConfigReference `json:"istio_config,omitempty"`
 }
 
 type Routes []*Route
 type Route struct {
-	Name           string `json:"name"`
-	Domains        string `json:"domains"`
-	Match          string `json:"match"`
-	VirtualService string `json:"virtual_service"`
+	Name           string                `json:"name"`
+	Domains        string                `json:"domains"`
+	Match          string                `json:"match"`
+	VirtualService string                `json:"virtual_service"`
+	Cluster        string                `json:"cluster,omitempty"`
+	IstioConfig    *IstioConfigReference `json:"istio_config,omitempty"`
+}
+
+// IstioConfigReference is the Istio object that produced an Envoy cluster, listener or route, as reported by istiod
+// in the metadata of the entry
+type IstioConfigReference struct {
+	// Type of the object, as in the Istio config API of Kiali
+	//
+	// required: true
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Name string `json:"name"`
 }
 
 type Bootstrap struct {
@@ -66,6 +84,7 @@ func (ls *Listeners) Parse(dump *kubernetes.ConfigDump) error {
 				Port:        listener.Address.SocketAddress.PortValue,
 				Match:       match["match"].(string),
 				Destination: match["destination"].(string),
+				IstioConfig: match["istio_config"].(*IstioConfigReference),
 			})
 		}
 	}
@@ -119,8 +138,9 @@ func listenerMatches(listener kubernetes.EnvoyListener) []map[string]interface{}
 		}
 
 		matches = append(matches, map[string]interface{}{
-			"match":       strings.Join(descriptors, "; "),
-			"destination": getListenerDestination(chain.Filters),
+			"match":        strings.Join(descriptors, "; "),
+			"destination":  getListenerDestination(chain.Filters),
+			"istio_config": istioConfigReference(chain.Metadata),
 		})
 	}
 	return matches
@@ -270,6 +290,7 @@ func (cs *Cluster) Parse(cluster kubernetes.EnvoyCluster) {
 	cs.Subset = ""
 	cs.Direction = ""
 	cs.DestinationRule = ""
+	cs.IstioConfig = nil
 
 	parts := strings.Split(cs.ServiceFQDN, "|")
 	if len(parts) > 3 {
@@ -278,6 +299,7 @@ func (cs *Cluster) Parse(cluster kubernetes.EnvoyCluster) {
 		cs.Subset = parts[2]
 		cs.Direction = strings.TrimSuffix(parts[0], "_")
 		cs.DestinationRule = istioMetadata(cluster.Metadata)
+		cs.IstioConfig = istioConfigReference(cluster.Metadata)
 	}
 }
 
@@ -299,6 +321,8 @@ func (rs *Routes) Parse(dump *kubernetes.ConfigDump) error {
 							Domains:        bestDomainMatch(vhs.Domains),
 							Match:          matchSummary(r.Match),
 							VirtualService: istioMetadata(r.Metadata),
+							Cluster:        r.Route.Cluster,
+							IstioConfig:    istioConfigReference(r.Metadata),
 						})
 					}
 				}
@@ -383,3 +407,75 @@ func renderConfig(configPath string) string {
 	}
 	return ""
 }
+
+// Object types of the Istio config API of Kiali, by kind as written by istiod in the metadata of the Envoy entries
+var istioMetadataKinds = map[string]string{
+	"destination-rule": kubernetes.DestinationRules,
+	"envoy-filter":     kubernetes.EnvoyFilters,
+	"gateway":          kubernetes.Gateways,
+	"service-entry":    kubernetes.ServiceEntries,
+	"sidecar":          kubernetes.Sidecars,
+	"virtual-service":  kubernetes.VirtualServices,
+}
+
+// istioConfigReference parses the path of the Istio object in the metadata of an Envoy entry, such as
+// /apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/reviews
+func istioConfigReference(metadata *kubernetes.EnvoyMetadata) *IstioConfigReference {
+	if metadata == nil || metadata.FilterMetadata == nil || metadata.FilterMetadata.Istio == nil {
+		return nil
+	}
+
+	parts := strings.Split(metadata.FilterMetadata.Istio.Config, "/")
+	if len(parts) != 8 || parts[2] != "networking.istio.io" || parts[4] != "namespaces" {
+		return nil
+	}
+	objectType, ok := istioMetadataKinds[parts[6]]
+	if !ok {
+		return nil
+	}
+	return &IstioConfigReference{ObjectType: objectType, Namespace: parts[5], Name: parts[7]}
+}
+
+// EnvoyStat is a counter or a gauge of an Envoy
+type EnvoyStat struct {
+	// required: true
+	// example: cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_pending_overflow
+	Name string `json:"name"`
+	// required: true
+	Value float64 `json:"value"`
+}
+
+// EnvoyConfigDiff compares the clusters, listeners and routes of the Envoys of two pods
+type EnvoyConfigDiff struct {
+	// Pods compared, as namespace/pod
+	//
+	// required: true
+	From string `json:"from"`
+	// required: true
+	To string `json:"to"`
+
+	// Whether no difference was found
+	//
+	// required: true
+	Identical bool `json:"identical"`
+
+	// required: true
+	Clusters []EnvoyConfigDelta `json:"clusters"`
+	// required: true
+	Listeners []EnvoyConfigDelta `json:"listeners"`
+	// required: true
+	Routes []EnvoyConfigDelta `json:"routes"`
+}
+
+// EnvoyConfigDelta is a cluster, listener or route differing between two Envoys
+type EnvoyConfigDelta struct {
+	// Identity of the entry, i.e. the FQDN, port, subset and direction of a cluster
+	//
+	// required: true
+	// example: outbound|9080|v1|reviews.bookinfo.svc.cluster.local
+	Key string `json:"key"`
+
+	// Entry in each Envoy, null when missing
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func istioConfigMetadata(path string) map[string]interface{} {
+	return map[string]interface{}{"filter_metadata": map[string]interface{}{"istio": map[string]interface{}{"config": path}}}
+}
+
+func TestIstioConfigReferences(t *testing.T) {
+	assert := assert.New(t)
+
+	dump := &kubernetes.ConfigDump{Configs: []interface{}{
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+			"dynamic_active_clusters": []interface{}{
+				map[string]interface{}{"cluster": map[string]interface{}{
+					"name":     "outbound|9080|v1|reviews.bookinfo.svc.cluster.local",
+					"metadata": istioConfigMetadata("/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destination-rule/reviews"),
+				}},
+				map[string]interface{}{"cluster": map[string]interface{}{
+					"name": "outbound|9080||ratings.bookinfo.svc.cluster.local",
+				}},
+			},
+		},
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+			"dynamic_listeners": []interface{}{map[string]interface{}{
+				"name": "0.0.0.0_8080",
+				"active_state": map[string]interface{}{"listener": map[string]interface{}{
+					"address": map[string]interface{}{"socket_address": map[string]interface{}{"address": "0.0.0.0", "port_value": 8080}},
+					"filter_chains": []interface{}{map[string]interface{}{
+						"metadata": istioConfigMetadata("/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/gateway/bookinfo-gateway"),
+						"filters": []interface{}{map[string]interface{}{
+							"name":         "envoy.filters.network.http_connection_manager",
+							"typed_config": map[string]interface{}{"rds": map[string]interface{}{"route_config_name": "http.8080"}},
+						}},
+					}},
+				}},
+			}},
+		},
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+			"dynamic_route_configs": []interface{}{map[string]interface{}{"route_config": map[string]interface{}{
+				"name": "http.8080",
+				"virtual_hosts": []interface{}{map[string]interface{}{
+					"domains": []interface{}{"*"},
+					"routes": []interface{}{map[string]interface{}{
+						"match":    map[string]interface{}{"prefix": "/productpage"},
+						"route":    map[string]interface{}{"cluster": "outbound|9080||productpage.bookinfo.svc.cluster.local"},
+						"metadata": istioConfigMetadata("/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/bookinfo"),
+					}},
+				}},
+			}}},
+		},
+	}}
+
+	clusters := Clusters{}
+	assert.NoError(clusters.Parse(dump))
+	assert.Len(clusters, 2)
+	assert.Equal("reviews.bookinfo", clusters[0].DestinationRule)
+	assert.Equal(&IstioConfigReference{ObjectType: "destinationrules", Namespace: "bookinfo", Name: "reviews"}, clusters[0].IstioConfig)
+	assert.Nil(clusters[1].IstioConfig)
+
+	listeners := Listeners{}
+	assert.NoError(listeners.Parse(dump))
+	assert.Len(listeners, 1)
+	assert.Equal("Route: http.8080", listeners[0].Destination)
+	assert.Equal(&IstioConfigReference{ObjectType: "gateways", Namespace: "bookinfo", Name: "bookinfo-gateway"}, listeners[0].IstioConfig)
+
+	routes := Routes{}
+	assert.NoError(routes.Parse(dump))
+	assert.Len(routes, 1)
+	assert.Equal("bookinfo.bookinfo", routes[0].VirtualService)
+	assert.Equal(&IstioConfigReference{ObjectType: "virtualservices", Namespace: "bookinfo", Name: "bookinfo"}, routes[0].IstioConfig)
+}
+
+func TestIstioConfigReferenceUnknownPath(t *testing.T) {
+	assert := assert.New(t)
+
+	parse := func(path string) *IstioConfigReference {
+		clusters := Clusters{}
+		assert.NoError(clusters.Parse(&kubernetes.ConfigDump{Configs: []interface{}{map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+			"dynamic_active_clusters": []interface{}{map[string]interface{}{"cluster": map[string]interface{}{
+				"name":     "outbound|9080||reviews.bookinfo.svc.cluster.local",
+				"metadata": istioConfigMetadata(path),
+			}}},
+		}}}))
+		return clusters[0].IstioConfig
+	}
+
+	assert.Nil(istioConfigReference(nil))
+	assert.Nil(parse(""))
+	assert.Nil(parse("/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/unknown-kind/reviews"))
+	assert.Nil(parse("/apis/security.istio.io/v1beta1/namespaces/bookinfo/destination-rule/reviews"))
+	assert.Equal(&IstioConfigReference{ObjectType: "serviceentries", Namespace: "bookinfo", Name: "external"},
+		parse("/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/service-entry/external"))
+}
@@ -0,0 +1,93 @@
+package models
+
+// TLS postures of the ports of the external services
+const (
+	// The application opens the TLS connection, the proxy passes it through
+	ExternalTLSApplication = "application"
+	// The application sends plaintext, the proxy originates TLS as set by a DestinationRule
+	ExternalTLSOrigination = "origination"
+	// The traffic leaves the mesh in plaintext
+	ExternalTLSPlaintext = "plaintext"
+	// The traffic is opaque TCP, whether it is encrypted is unknown
+	ExternalTLSUnknown = "unknown"
+)
+
+// ExternalServiceInventory lists the hosts outside of the mesh reached by the workloads of the accessible namespaces:
+// the hosts registered by the ServiceEntries, and the hosts reached through the PassthroughCluster over a window
+type ExternalServiceInventory struct {
+	// required: true
+	// example: 1d
+	Window string `json:"window"`
+
+	// The external services, sorted by host
+	//
+	// required: true
+	ExternalServices []ExternalService `json:"externalServices"`
+
+	// Rate of the requests and connections through the PassthroughCluster without a known host, such as the TCP
+	// connections to IP addresses
+	//
+	// required: true
+	UnidentifiedRate float64 `json:"unidentifiedRate"`
+}
+
+// ExternalService is a host outside of the mesh, with the workloads calling it and the TLS posture of its ports
+type ExternalService struct {
+	// The host, which is a wildcard when registered as such by a ServiceEntry
+	//
+	// required: true
+	// example: api.github.com
+	Host string `json:"host"`
+
+	// Whether the host is registered by a ServiceEntry. Unregistered hosts are reached through the PassthroughCluster.
+	//
+	// required: true
+	Registered bool `json:"registered"`
+
+	// The ServiceEntries registering the host
+	//
+	// required: true
+	ServiceEntries []IstioConfigReference `json:"serviceEntries"`
+
+	// The ports of the host, declared by its ServiceEntries or seen in the traffic
+	//
+	// required: true
+	Ports []ExternalServicePort `json:"ports"`
+
+	// Whether some traffic to the host leaves the mesh in plaintext
+	//
+	// required: true
+	Plaintext bool `json:"plaintext"`
+
+	// Whether the traffic to the host is blocked, being routed to the BlackHoleCluster
+	//
+	// required: true
+	Blocked bool `json:"blocked"`
+
+	// Rate of the requests and connections to the host over the window
+	//
+	// required: true
+	Rate float64 `json:"rate"`
+
+	// The workloads calling the host over the window
+	//
+	// required: true
+	Sources []ExternalHostSource `json:"sources"`
+}
+
+// ExternalServicePort is a port of an external service, with the TLS posture of its traffic
+type ExternalServicePort struct {
+	// required: true
+	// example: 443
+	Number int `json:"number"`
+
+	// required: true
+	// example: HTTPS
+	Protocol string `json:"protocol"`
+
+	// The TLS posture: application, origination, plaintext or unknown
+	//
+	// required: true
+	// example: application
+	TLS string `json:"tls"`
+}
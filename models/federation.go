@@ -0,0 +1,49 @@
+package models
+
+// FederatedInstanceStatus is the reachability of a remote Kiali instance aggregated by the federation
+type FederatedInstanceStatus struct {
+	// The name of the cluster of the remote Kiali
+	//
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// Base URL of the remote Kiali
+	//
+	// required: true
+	// example: https://kiali.east.example.com/kiali
+	URL string `json:"url"`
+
+	// Whether the remote Kiali answers with the credentials of the central Kiali
+	//
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// Why the remote Kiali is not reachable
+	Message string `json:"message,omitempty"`
+
+	// Time taken by the remote Kiali to list its namespaces, in milliseconds
+	LatencyMs int64 `json:"latencyMs,omitempty"`
+}
+
+// FederatedNamespace is a namespace of the cluster of a remote Kiali instance
+type FederatedNamespace struct {
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// required: true
+	// example: bookinfo
+	Name string `json:"name"`
+}
+
+// FederatedNamespaces are the namespaces of all the remote Kiali instances
+type FederatedNamespaces struct {
+	// required: true
+	Namespaces []FederatedNamespace `json:"namespaces"`
+
+	// Errors of the remote Kiali instances which cannot be read, by cluster
+	//
+	// required: true
+	Errors map[string]string `json:"errors"`
+}
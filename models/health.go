@@ -1,7 +1,15 @@
 package models
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
 )
 
 // NamespaceAppHealth is an alias of map of app name x health
@@ -16,12 +24,16 @@ type NamespaceWorkloadHealth map[string]*WorkloadHealth
 // ServiceHealth contains aggregated health from various sources, for a given service
 type ServiceHealth struct {
 	Requests RequestHealth `json:"requests"`
+	// Annotations of the service and of its namespace overriding the health configuration
+	HealthAnnotations map[string]string `json:"healthAnnotations,omitempty"`
 }
 
 // AppHealth contains aggregated health from various sources, for a given app
 type AppHealth struct {
 	WorkloadStatuses []*WorkloadStatus `json:"workloadStatuses"`
 	Requests         RequestHealth     `json:"requests"`
+	// Annotations of the namespace of the app overriding the health configuration
+	HealthAnnotations map[string]string `json:"healthAnnotations,omitempty"`
 }
 
 func NewEmptyRequestHealth() RequestHealth {
@@ -54,6 +66,10 @@ func EmptyWorkloadHealth() *WorkloadHealth {
 type WorkloadHealth struct {
 	WorkloadStatus *WorkloadStatus `json:"workloadStatus"`
 	Requests       RequestHealth   `json:"requests"`
+	// The latest warning events of the workload and of its pods, explaining an unhealthy workload
+	Events KubernetesEvents `json:"events,omitempty"`
+	// Annotations of the workload and of its namespace overriding the health configuration
+	HealthAnnotations map[string]string `json:"healthAnnotations,omitempty"`
 }
 
 // WorkloadStatus gives
@@ -157,3 +173,104 @@ func (ps ProxyStatus) IsSynced() bool {
 func isComponentStatusSynced(componentStatus string) bool {
 	return componentStatus == "Synced"
 }
+
+// Annotations of the namespaces, workloads and services overriding the health configuration of Kiali. The annotations
+// of a workload or service override the annotations of its namespace.
+const (
+	// Tolerances of the error rates, i.e. "^5\d\d$,20,40,http,inbound;^4\d\d$,-,30,http,.*": entries of a code,
+	// the degraded and failure percentages ("-" when not rated), a protocol and a direction, separated by semicolons
+	RateHealthAnnotation = "health.kiali.io/rate"
+	// Rate interval of the error rates of a namespace, i.e. "30m" to smooth the errors of batch jobs
+	RateIntervalHealthAnnotation = "health.kiali.io/rate-interval"
+)
+
+// GetHealthAnnotations returns the valid health annotations among the given annotations, nil when there is none
+func GetHealthAnnotations(annotations map[string]string) map[string]string {
+	var healthAnnotations map[string]string
+	for _, key := range []string{RateHealthAnnotation, RateIntervalHealthAnnotation} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		var err error
+		if key == RateHealthAnnotation {
+			_, err = ParseRateHealthAnnotation(value)
+		} else {
+			_, err = model.ParseDuration(value)
+		}
+		if err != nil {
+			log.Warningf("Annotation [%s] is ignored: %v", key, err)
+			continue
+		}
+		if healthAnnotations == nil {
+			healthAnnotations = map[string]string{}
+		}
+		healthAnnotations[key] = value
+	}
+	return healthAnnotations
+}
+
+// MergeHealthAnnotations returns the health annotations of a namespace overridden by the ones of an object
+func MergeHealthAnnotations(namespaceAnnotations, objectAnnotations map[string]string) map[string]string {
+	if len(namespaceAnnotations) == 0 {
+		return objectAnnotations
+	}
+	merged := make(map[string]string, len(namespaceAnnotations)+len(objectAnnotations))
+	for key, value := range namespaceAnnotations {
+		merged[key] = value
+	}
+	for key, value := range objectAnnotations {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ParseRateHealthAnnotation parses the tolerances of the annotation health.kiali.io/rate
+func ParseRateHealthAnnotation(value string) ([]config.Tolerance, error) {
+	tolerances := []config.Tolerance{}
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		fields := strings.Split(entry, ",")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("tolerance [%s] is not a code, degraded, failure, protocol and direction", entry)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		for _, pattern := range []string{fields[0], fields[3], fields[4]} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("tolerance [%s] has an invalid expression: %v", entry, err)
+			}
+		}
+		tolerance := config.Tolerance{Code: fields[0], Protocol: fields[3], Direction: fields[4]}
+		var err error
+		if tolerance.Degraded, err = parseTolerancePercent(fields[1]); err != nil {
+			return nil, fmt.Errorf("tolerance [%s] has an invalid degraded percentage: %v", entry, err)
+		}
+		if tolerance.Failure, err = parseTolerancePercent(fields[2]); err != nil {
+			return nil, fmt.Errorf("tolerance [%s] has an invalid failure percentage: %v", entry, err)
+		}
+		tolerances = append(tolerances, tolerance)
+	}
+	if len(tolerances) == 0 {
+		return nil, fmt.Errorf("no tolerance in [%s]", value)
+	}
+	return tolerances, nil
+}
+
+// parseTolerancePercent parses a percentage of a tolerance, "-" meaning not rated like the zero of the configuration
+func parseTolerancePercent(value string) (float32, error) {
+	if value == "-" {
+		return 0, nil
+	}
+	percent, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, err
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%s is not between 0 and 100", value)
+	}
+	return float32(percent), nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestParseRateHealthAnnotation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tolerances, err := ParseRateHealthAnnotation(`^5\d\d$,20,40,http,inbound; ^4\d\d$,-,30,http,.*`)
+	require.NoError(err)
+	assert.Equal([]config.Tolerance{
+		{Code: `^5\d\d$`, Degraded: 20, Failure: 40, Protocol: "http", Direction: "inbound"},
+		{Code: `^4\d\d$`, Degraded: 0, Failure: 30, Protocol: "http", Direction: ".*"},
+	}, tolerances)
+
+	for _, invalid := range []string{"", "5XX,20,40,http", "5XX,twenty,40,http,inbound", "5XX,20,140,http,inbound", "[,20,40,http,inbound"} {
+		_, err = ParseRateHealthAnnotation(invalid)
+		assert.Error(err, invalid)
+	}
+}
+
+func TestGetHealthAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	annotations := GetHealthAnnotations(map[string]string{
+		RateHealthAnnotation:         "5XX,20,40,http,inbound",
+		RateIntervalHealthAnnotation: "thirty minutes",
+		"sidecar.istio.io/inject":    "true",
+	})
+	assert.Equal(map[string]string{RateHealthAnnotation: "5XX,20,40,http,inbound"}, annotations)
+	assert.Nil(GetHealthAnnotations(map[string]string{"sidecar.istio.io/inject": "true"}))
+
+	merged := MergeHealthAnnotations(
+		map[string]string{RateHealthAnnotation: "5XX,20,40,http,inbound", RateIntervalHealthAnnotation: "30m"},
+		map[string]string{RateHealthAnnotation: "5XX,1,5,http,inbound"},
+	)
+	assert.Equal(map[string]string{RateHealthAnnotation: "5XX,1,5,http,inbound", RateIntervalHealthAnnotation: "30m"}, merged)
+}
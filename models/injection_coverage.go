@@ -0,0 +1,87 @@
+package models
+
+// Reasons of the pods outside of the data plane
+const (
+	// The pod uses the network of its node, it cannot be injected
+	InjectionReasonHostNetwork = "hostNetwork"
+	// The creation of the pods of the controller of the pod was rejected by the sidecar injector
+	InjectionReasonInjectionFailed = "injectionFailed"
+	// Neither the namespace nor the pod are enrolled
+	InjectionReasonNotEnrolled = "notEnrolled"
+	// The pod opts out of the data plane with a label or an annotation
+	InjectionReasonOptOut = "optOut"
+	// The pod was created before the enrollment of its namespace, or its injection was ignored: it has to be restarted
+	InjectionReasonRestartRequired = "restartRequired"
+)
+
+// InjectionCoverage is the coverage of the data plane of the accessible namespaces
+type InjectionCoverage struct {
+	// The namespaces, sorted by name
+	//
+	// required: true
+	Namespaces []NamespaceInjectionCoverage `json:"namespaces"`
+}
+
+// NamespaceInjectionCoverage counts the pods of a namespace in the data plane, with a sidecar or captured by ztunnel,
+// and explains why the others are not
+type NamespaceInjectionCoverage struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// sidecar or ambient, empty when the namespace is not enrolled
+	//
+	// required: true
+	// example: sidecar
+	Mode string `json:"mode"`
+
+	// Revision injecting the sidecars of the namespace, "default" for istiod deployed without revision
+	//
+	// example: 1-8-1
+	Revision string `json:"revision,omitempty"`
+
+	// required: true
+	Pods int `json:"pods"`
+
+	// Pods with a sidecar
+	//
+	// required: true
+	SidecarPods int `json:"sidecarPods"`
+
+	// Pods captured by ztunnel
+	//
+	// required: true
+	AmbientPods int `json:"ambientPods"`
+
+	// The proxies by revision and version
+	//
+	// required: true
+	Proxies []ProxyVersionCount `json:"proxies"`
+
+	// Whether some sidecars were injected by another revision than the one of the namespace
+	//
+	// required: true
+	RevisionDrift bool `json:"revisionDrift"`
+
+	// The pods outside of the data plane, sorted by name
+	//
+	// required: true
+	PodsOutOfMesh []PodInjection `json:"podsOutOfMesh"`
+
+	// The recent rejections of pods by the sidecar injector, the latest first
+	//
+	// required: true
+	InjectionFailures KubernetesEvents `json:"injectionFailures"`
+}
+
+// PodInjection is a pod outside of the data plane, with the reason
+type PodInjection struct {
+	// required: true
+	// example: reviews-v1-545db77b95-wnvc9
+	Name string `json:"name"`
+
+	// hostNetwork, injectionFailed, notEnrolled, optOut or restartRequired
+	//
+	// required: true
+	// example: optOut
+	Reason string `json:"reason"`
+}
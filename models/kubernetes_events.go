@@ -0,0 +1,120 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+)
+
+// Categories of the Kubernetes events explaining an unhealthy workload
+const (
+	EventCategoryCrashLoop  = "crashloop"
+	EventCategoryImage      = "image"
+	EventCategoryOOMKill    = "oomkill"
+	EventCategoryOther      = "other"
+	EventCategoryProbe      = "probe"
+	EventCategoryScheduling = "scheduling"
+)
+
+// KubernetesEvent is a recent event of a Kubernetes object, i.e. a pod failing its readiness probe
+type KubernetesEvent struct {
+	// The type of the event
+	//
+	// required: true
+	// example: Warning
+	Type string `json:"type"`
+
+	// The reason of the event, given by the component reporting it
+	//
+	// required: true
+	// example: FailedScheduling
+	Reason string `json:"reason"`
+
+	// The category of the reason: scheduling, oomkill, probe, image, crashloop or other
+	//
+	// required: true
+	// example: scheduling
+	Category string `json:"category"`
+
+	// The message of the event
+	Message string `json:"message"`
+
+	// The kind of the object of the event
+	//
+	// required: true
+	// example: Pod
+	ObjectKind string `json:"objectKind"`
+
+	// The name of the object of the event
+	//
+	// required: true
+	// example: reviews-v1-545db77b95-wnvc9
+	ObjectName string `json:"objectName"`
+
+	// How many times the event occurred
+	Count int32 `json:"count"`
+
+	// When the event first occurred
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+
+	// When the event last occurred
+	//
+	// required: true
+	LastTimestamp time.Time `json:"lastTimestamp"`
+}
+
+// KubernetesEvents is a list of events, the latest first
+type KubernetesEvents []KubernetesEvent
+
+// Parse fills the event from the event of Kubernetes
+func (e *KubernetesEvent) Parse(event core_v1.Event) {
+	e.Type = event.Type
+	e.Reason = event.Reason
+	e.Category = EventCategory(event.Reason, event.Message)
+	e.Message = event.Message
+	e.ObjectKind = event.InvolvedObject.Kind
+	e.ObjectName = event.InvolvedObject.Name
+	e.Count = event.Count
+	e.FirstTimestamp = event.FirstTimestamp.Time
+	e.LastTimestamp = event.LastTimestamp.Time
+	// The events of the events.k8s.io API, reported in series, have neither count nor timestamps
+	if e.Count == 0 {
+		e.Count = 1
+	}
+	if e.LastTimestamp.IsZero() {
+		e.LastTimestamp = event.EventTime.Time
+		if event.Series != nil {
+			e.LastTimestamp = event.Series.LastObservedTime.Time
+			e.Count = event.Series.Count
+		}
+	}
+	if e.FirstTimestamp.IsZero() {
+		e.FirstTimestamp = event.EventTime.Time
+	}
+}
+
+// EventCategory classifies the reason of an event, reported by the scheduler or the kubelet
+func EventCategory(reason, message string) string {
+	switch reason {
+	case "FailedScheduling", "NotTriggerScaleUp":
+		return EventCategoryScheduling
+	case "OOMKilling", "OOMKilled":
+		return EventCategoryOOMKill
+	case "Unhealthy", "ProbeWarning":
+		return EventCategoryProbe
+	case "ErrImagePull", "ImagePullBackOff", "ErrImageNeverPull", "InspectFailed":
+		return EventCategoryImage
+	case "BackOff":
+		// The kubelet reports the back-off of the restarts and of the image pulls with the same reason
+		if strings.Contains(message, "image") {
+			return EventCategoryImage
+		}
+		return EventCategoryCrashLoop
+	case "Failed":
+		if strings.Contains(message, "image") {
+			return EventCategoryImage
+		}
+	}
+	return EventCategoryOther
+}
@@ -0,0 +1,96 @@
+package models
+
+// ControlPlaneRevision is a revision of istiod running in the mesh
+type ControlPlaneRevision struct {
+	// Name of the revision, "default" for istiod deployed without revision
+	//
+	// required: true
+	// example: 1-8-1
+	Revision string `json:"revision"`
+
+	// Deployment of istiod
+	//
+	// required: true
+	// example: istiod-1-8-1
+	Deployment string `json:"deployment"`
+
+	// Image of istiod
+	//
+	// required: true
+	// example: docker.io/istio/pilot:1.8.1
+	Image string `json:"image"`
+
+	// Version of istiod, from the tag of its image
+	//
+	// required: true
+	// example: 1.8.1
+	Version string `json:"version"`
+
+	// Healthy or Unhealthy, from the replicas of the deployment
+	//
+	// required: true
+	Status string `json:"status"`
+}
+
+// RevisionComparison compares two revisions of the control plane, to prepare the migration of the namespaces from one
+// revision to the other
+type RevisionComparison struct {
+	From ControlPlaneRevision `json:"from"`
+	To   ControlPlaneRevision `json:"to"`
+
+	// Every revision discovered in the mesh
+	//
+	// required: true
+	Revisions []ControlPlaneRevision `json:"revisions"`
+
+	// Settings of the mesh configuration differing between the revisions
+	//
+	// required: true
+	MeshConfigDeltas []MeshConfigDelta `json:"meshConfigDeltas"`
+
+	// Namespaces pointing at one of the revisions, or running proxies injected by one of them
+	//
+	// required: true
+	Namespaces []NamespaceRevision `json:"namespaces"`
+}
+
+// MeshConfigDelta is a setting of the mesh configuration differing between two revisions
+type MeshConfigDelta struct {
+	// Path of the setting, i.e. defaultConfig.tracing.sampling
+	//
+	// required: true
+	Path string `json:"path"`
+
+	// Value of the setting in each revision, null when not set
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// NamespaceRevision tells the revision injecting the proxies of a namespace, and the proxies running in it
+type NamespaceRevision struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Revision the namespace points at, from its labels. Empty when the injection is not enabled.
+	//
+	// required: true
+	Revision string `json:"revision"`
+
+	// Proxies running in the namespace, by revision and version
+	//
+	// required: true
+	Proxies []ProxyVersionCount `json:"proxies"`
+
+	// Whether some proxies were injected by another revision than the one of the namespace: their pods have to be
+	// restarted to complete a migration
+	//
+	// required: true
+	RestartRequired bool `json:"restartRequired"`
+}
+
+// ProxyVersionCount counts the proxies of a revision and version
+type ProxyVersionCount struct {
+	Revision string `json:"revision"`
+	Version  string `json:"version"`
+	Count    int    `json:"count"`
+}
@@ -0,0 +1,42 @@
+package models
+
+const (
+	DriftMeshConfig  = "meshConfig"
+	DriftRootCA      = "rootCA"
+	DriftTrustDomain = "trustDomain"
+	DriftIstioConfig = "istioConfig"
+)
+
+// MeshDrift reports the settings of the mesh differing between the clusters of a multi-primary mesh
+type MeshDrift struct {
+	// Clusters compared, the home cluster first
+	//
+	// required: true
+	Clusters []string `json:"clusters"`
+
+	// required: true
+	Drifts []ConfigDrift `json:"drifts"`
+
+	// Clusters which could not be compared, with the reason
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ConfigDrift is a setting of the mesh differing between clusters
+type ConfigDrift struct {
+	// meshConfig, rootCA, trustDomain or istioConfig
+	//
+	// required: true
+	// example: meshConfig
+	Category string `json:"category"`
+
+	// Path of the mesh configuration setting, or kind and name of the Istio object
+	//
+	// required: true
+	// example: outboundTrafficPolicy.mode
+	Key string `json:"key"`
+
+	// Value of the setting in each cluster, or ztunnel, missing when not set in it
+	//
+	// required: true
+	Values map[string]interface{} `json:"values"`
+}
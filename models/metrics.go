@@ -28,6 +28,7 @@ type IstioMetricsQuery struct {
 	Reporter        string // source | destination, defaults to source if not provided
 	Aggregate       string
 	AggregateValue  string
+	Exemplars       bool // include the exemplars of the request metrics
 }
 
 // FillDefaults fills the struct with default parameters
@@ -118,8 +119,23 @@ type Metric struct {
 	Datapoints []Datapoint       `json:"datapoints"`
 	Stat       string            `json:"stat,omitempty"`
 	Name       string            `json:"name"`
+	Exemplars  []Exemplar        `json:"exemplars,omitempty"`
 }
 
+// Exemplar is a data point linked to the trace of one of the requests it measures
+type Exemplar struct {
+	Labels    map[string]string `json:"labels"`
+	TraceID   string            `json:"traceId"`
+	Timestamp pmod.Time         `json:"timestamp"`
+	Value     float64           `json:"value"`
+}
+
+// MaxExemplarsPerSeries limits the exemplars attached to a series, the most recent ones being kept
+const MaxExemplarsPerSeries = 100
+
+// Labels of the exemplars holding the trace ID, as set by the different tracers
+var traceIDLabels = []pmod.LabelName{"trace_id", "traceId", "traceID"}
+
 type Datapoint struct {
 	Timestamp int64
 	Value     float64
@@ -223,6 +239,63 @@ func convertSampleStream(from *pmod.SampleStream, name, stat string, conversionP
 	}
 }
 
+// AttachExemplars adds to the series the exemplars with a trace ID of the series they belong to, i.e. whose labels
+// include all the labels of the series
+func AttachExemplars(series []Metric, from []prometheus.ExemplarSeries, conversionParams ConversionParams) {
+	for i := range series {
+		var exemplars []Exemplar
+		for _, exemplarSeries := range from {
+			if !containsLabels(exemplarSeries.SeriesLabels, series[i].Labels) {
+				continue
+			}
+			for _, e := range exemplarSeries.Exemplars {
+				if exemplar, ok := convertExemplar(e, conversionParams.Scale); ok {
+					exemplars = append(exemplars, exemplar)
+				}
+			}
+		}
+		sort.Slice(exemplars, func(a, b int) bool {
+			return exemplars[a].Timestamp < exemplars[b].Timestamp
+		})
+		if len(exemplars) > MaxExemplarsPerSeries {
+			exemplars = exemplars[len(exemplars)-MaxExemplarsPerSeries:]
+		}
+		series[i].Exemplars = exemplars
+	}
+}
+
+func containsLabels(labelSet pmod.LabelSet, labels map[string]string) bool {
+	for k, v := range labels {
+		if string(labelSet[pmod.LabelName(k)]) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func convertExemplar(from prometheus.Exemplar, scale float64) (Exemplar, bool) {
+	traceID := ""
+	for _, label := range traceIDLabels {
+		if id, ok := from.Labels[label]; ok && id != "" {
+			traceID = string(id)
+			break
+		}
+	}
+	if traceID == "" {
+		return Exemplar{}, false
+	}
+	labels := make(map[string]string, len(from.Labels))
+	for k, v := range from.Labels {
+		labels[string(k)] = string(v)
+	}
+	return Exemplar{
+		Labels:    labels,
+		TraceID:   traceID,
+		Timestamp: from.Timestamp,
+		Value:     scale * float64(from.Value),
+	}, true
+}
+
 // MarshalJSON implements json.Marshaler.
 func (s Datapoint) MarshalJSON() ([]byte, error) {
 	return pmod.SamplePair{
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Stages of the mTLS rollout of a namespace
+const (
+	MTLSRolloutPermissive = "permissive"
+	MTLSRolloutStrict     = "strict"
+)
+
+// MTLSRolloutRequest holds the soak window of an mTLS rollout, during which the plaintext traffic is watched
+type MTLSRolloutRequest struct {
+	// Soak window, 24h by default
+	//
+	// example: 24h
+	Soak string `json:"soak,omitempty"`
+}
+
+// MTLSRolloutStrictRequest flips an mTLS rollout to STRICT
+type MTLSRolloutStrictRequest struct {
+	// Whether to flip before the end of the soak window, or despite plaintext traffic
+	Force bool `json:"force,omitempty"`
+}
+
+// MTLSRollout is the state of the staged STRICT mTLS enablement of a namespace. The state is kept in the annotations
+// of the namespace-wide PeerAuthentication, so that the rollout can be resumed.
+type MTLSRollout struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Namespace-wide PeerAuthentication driven by the rollout
+	//
+	// required: true
+	// example: default
+	PeerAuthentication string `json:"peerAuthentication"`
+
+	// Stage of the rollout, permissive or strict
+	//
+	// required: true
+	Stage string `json:"stage"`
+
+	// mTLS mode of the PeerAuthentication before the rollout, restored when it is aborted. Not set when the
+	// PeerAuthentication was created by the rollout.
+	PreviousMode string `json:"previousMode,omitempty"`
+
+	// Start and end of the soak window
+	//
+	// required: true
+	StartedAt time.Time `json:"startedAt"`
+	// required: true
+	Soak string `json:"soak"`
+	// required: true
+	SoakEndsAt time.Time `json:"soakEndsAt"`
+	// required: true
+	SoakCompleted bool `json:"soakCompleted"`
+
+	// Clients sending plaintext requests to the namespace since the start of the rollout
+	//
+	// required: true
+	PlaintextSources []PlaintextSource `json:"plaintextSources"`
+
+	// Whether the soak window completed without plaintext traffic
+	//
+	// required: true
+	ReadyForStrict bool `json:"readyForStrict"`
+}
+
+// PlaintextSource is a client sending plaintext requests to a service
+type PlaintextSource struct {
+	// Namespace and workload of the client, unknown for clients out of the mesh
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Workload string `json:"workload"`
+	// required: true
+	Service string `json:"service"`
+	// Requests per second
+	//
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+}
@@ -27,6 +27,9 @@ type Namespace struct {
 
 	// Labels for Namespace
 	Labels map[string]string `json:"labels"`
+
+	// Annotations of the namespace overriding the health configuration
+	HealthAnnotations map[string]string `json:"healthAnnotations,omitempty"`
 }
 
 type Namespaces []Namespace
@@ -46,6 +49,7 @@ func CastNamespace(ns core_v1.Namespace) Namespace {
 	namespace.Name = ns.Name
 	namespace.CreationTimestamp = ns.CreationTimestamp.Time
 	namespace.Labels = ns.Labels
+	namespace.HealthAnnotations = GetHealthAnnotations(ns.Annotations)
 
 	return namespace
 }
@@ -64,6 +68,7 @@ func CastProject(p osproject_v1.Project) Namespace {
 	namespace.Name = p.Name
 	namespace.CreationTimestamp = p.CreationTimestamp.Time
 	namespace.Labels = p.Labels
+	namespace.HealthAnnotations = GetHealthAnnotations(p.Annotations)
 
 	return namespace
 }
@@ -0,0 +1,86 @@
+package models
+
+// Data planes a namespace can be enrolled in
+const (
+	EnrollmentModeAmbient = "ambient"
+	EnrollmentModeSidecar = "sidecar"
+)
+
+// NamespaceEnrollment is the enrollment of a namespace in the mesh, from its labels, and the state of its pods
+type NamespaceEnrollment struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// sidecar or ambient, empty when the namespace is not enrolled
+	//
+	// required: true
+	// example: sidecar
+	Mode string `json:"mode"`
+
+	// Revision injecting the sidecars, "default" for istiod deployed without revision
+	//
+	// example: 1-8-1
+	Revision string `json:"revision,omitempty"`
+
+	// Pods of the namespace, and those running in its data plane, with a sidecar of the revision or captured by ztunnel
+	//
+	// required: true
+	Pods int `json:"pods"`
+	// required: true
+	EnrolledPods int `json:"enrolledPods"`
+
+	// Revisions of the control plane the namespace can be enrolled in
+	//
+	// required: true
+	Revisions []ControlPlaneRevision `json:"revisions"`
+}
+
+// EnrollmentRequest asks for the enrollment of a namespace in a data plane
+type EnrollmentRequest struct {
+	// sidecar or ambient
+	//
+	// required: true
+	// example: sidecar
+	Mode string `json:"mode"`
+
+	// Revision injecting the sidecars, the default one when empty
+	//
+	// example: 1-8-1
+	Revision string `json:"revision,omitempty"`
+}
+
+// EnrollmentPreflight holds the checks run before enrolling a namespace
+type EnrollmentPreflight struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Mode string `json:"mode"`
+	// example: 1-8-1
+	Revision string `json:"revision,omitempty"`
+
+	// Whether the namespace can be enrolled, i.e. no blocking check failed
+	//
+	// required: true
+	Ready bool `json:"ready"`
+
+	// required: true
+	Checks []EnrollmentCheck `json:"checks"`
+}
+
+// EnrollmentCheck is a check run before enrolling a namespace
+type EnrollmentCheck struct {
+	// required: true
+	// example: revision
+	Name string `json:"name"`
+
+	// required: true
+	Passed bool `json:"passed"`
+
+	// Whether the enrollment is refused when the check fails, otherwise it is a warning
+	//
+	// required: true
+	Blocking bool `json:"blocking"`
+
+	// required: true
+	Message string `json:"message"`
+}
@@ -0,0 +1,77 @@
+package models
+
+// OutboundTrafficPolicyAnalysis lists the external hosts reached by the workloads of the accessible namespaces through
+// the PassthroughCluster or blocked by the BlackHoleCluster, which need a ServiceEntry with an outbound traffic
+// policy in REGISTRY_ONLY mode
+type OutboundTrafficPolicyAnalysis struct {
+	// required: true
+	// example: 1d
+	Window string `json:"window"`
+
+	// Outbound traffic policy mode of the mesh
+	//
+	// required: true
+	// example: ALLOW_ANY
+	Mode string `json:"mode"`
+
+	// required: true
+	ExternalHosts []ExternalHost `json:"externalHosts"`
+
+	// Rate of the requests and connections to external destinations without a known host, such as the TCP
+	// connections to IP addresses, which cannot be covered by a generated ServiceEntry
+	//
+	// required: true
+	UnidentifiedRate float64 `json:"unidentifiedRate"`
+
+	// ServiceEntries registering the external hosts, in YAML
+	ServiceEntries string `json:"serviceEntries,omitempty"`
+}
+
+// ExternalHost is a host outside of the service registry reached by workloads of the mesh
+type ExternalHost struct {
+	// required: true
+	// example: api.github.com
+	Host string `json:"host"`
+
+	// required: true
+	Ports []ExternalHostPort `json:"ports"`
+
+	// Rate of the requests and connections to the host
+	//
+	// required: true
+	Rate float64 `json:"rate"`
+
+	// Whether the traffic to the host is already blocked, being routed to the BlackHoleCluster
+	//
+	// required: true
+	Blocked bool `json:"blocked"`
+
+	// required: true
+	Sources []ExternalHostSource `json:"sources"`
+
+	// Namespace of the generated ServiceEntry: the namespace of the workloads reaching the host, or the Istio
+	// namespace, exporting it to the whole mesh, when they are in several namespaces
+	//
+	// required: true
+	ServiceEntryNamespace string `json:"serviceEntryNamespace"`
+}
+
+// ExternalHostPort is a port of an external host, with the protocol of its traffic
+type ExternalHostPort struct {
+	// required: true
+	// example: 443
+	Number int `json:"number"`
+	// required: true
+	// example: TLS
+	Protocol string `json:"protocol"`
+}
+
+// ExternalHostSource is a workload reaching an external host
+type ExternalHostSource struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Workload string `json:"workload"`
+	// required: true
+	Rate float64 `json:"rate"`
+}
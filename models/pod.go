@@ -27,8 +27,18 @@ type Pod struct {
 	VersionLabel        bool              `json:"versionLabel"`
 	Annotations         map[string]string `json:"annotations"`
 	ProxyStatus         *ProxyStatus      `json:"proxyStatus"`
+	// Revision of the pod when it belongs to an Argo Rollout: stable, canary, preview or old
+	RolloutRevision string `json:"rolloutRevision,omitempty"`
 }
 
+// Revisions of the pods of an Argo Rollout
+const (
+	RolloutRevisionCanary  = "canary"
+	RolloutRevisionOld     = "old"
+	RolloutRevisionPreview = "preview"
+	RolloutRevisionStable  = "stable"
+)
+
 // Reference holds some information on the pod creator
 type Reference struct {
 	Name string `json:"name"`
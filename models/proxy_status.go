@@ -0,0 +1,29 @@
+package models
+
+// ProxySyncStatus is the xDS distribution status of a proxy, as reported by the istiod it is connected to
+type ProxySyncStatus struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Pod string `json:"pod"`
+
+	// istiod pod serving the proxy, empty when the proxy is not connected
+	Istiod string `json:"istiod"`
+
+	// Versions of the proxy and of istiod
+	ProxyVersion string `json:"proxyVersion"`
+	IstioVersion string `json:"istioVersion"`
+
+	// Status of each xDS: Synced, NOT_SENT, Stale or Stale (Never Acknowledged)
+	ProxyStatus
+
+	// Whether istiod reports the proxy as connected
+	//
+	// required: true
+	Connected bool `json:"connected"`
+
+	// Whether every xDS sent to the proxy was acknowledged, none being stale
+	//
+	// required: true
+	Synced bool `json:"synced"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+)
+
+// SavedView is a named view of the console, i.e. a graph with its filters on some namespaces over a time range, which
+// its owner can share with all the users of Kiali
+//
+// swagger:model savedView
+type SavedView struct {
+	// The id of the view
+	//
+	// required: true
+	ID string `json:"id"`
+
+	// The user that saved the view
+	//
+	// required: true
+	Owner string `json:"owner"`
+
+	// Creation date of the view
+	//
+	// required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Last update of the view
+	//
+	// required: true
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	SavedViewRequest
+}
+
+// SavedViewRequest is the request saving, or updating, a view
+type SavedViewRequest struct {
+	// A name to identify the view
+	//
+	// required: true
+	// example: bookinfo errors
+	Name string `json:"name"`
+
+	// The page of the console showing the view
+	//
+	// required: true
+	// example: graph
+	Page string `json:"page"`
+
+	// The namespaces selected in the view
+	//
+	// required: true
+	// example: ["bookinfo"]
+	Namespaces []string `json:"namespaces"`
+
+	// The time range of the view, in seconds before now
+	//
+	// example: 600
+	Duration int64 `json:"duration,omitempty"`
+
+	// The filters of the page, i.e. the graph type, the edge labels and the find expression of the graph. Kiali does
+	// not interpret them.
+	//
+	// example: {"graphType":"versionedApp","find":"%error>1"}
+	Filters map[string]string `json:"filters"`
+
+	// Whether all the users of Kiali see the view
+	//
+	// required: true
+	Shared bool `json:"shared"`
+}
@@ -0,0 +1,87 @@
+package models
+
+// SidecarSizingReport compares the resources used by the proxies injected in the accessible namespaces to their
+// requests and limits, and recommends their sizing, per namespace and for the whole mesh
+type SidecarSizingReport struct {
+	// required: true
+	// example: 1d
+	Window string `json:"window"`
+
+	// Sizing of the proxies of the namespaces with injected proxies
+	//
+	// required: true
+	Namespaces []NamespaceSidecarSizing `json:"namespaces"`
+
+	// Sizing of all the proxies, recommending the resources of the proxies configured globally
+	//
+	// required: true
+	Global SidecarSizing `json:"global"`
+
+	// Values of the Istio installation setting the recommended resources of the proxies, in YAML
+	GlobalValues string `json:"globalValues,omitempty"`
+}
+
+// NamespaceSidecarSizing is the sizing of the proxies of a namespace
+type NamespaceSidecarSizing struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	SidecarSizing
+
+	// Annotations of the pod templates of the workloads of the namespace setting the recommended resources of their
+	// proxies, overriding the global ones
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SidecarSizing is the sizing of a set of proxies
+type SidecarSizing struct {
+	// Number of injected proxies, and of the ones with usage reported over the window. Nothing is recommended
+	// without usage.
+	//
+	// required: true
+	Proxies int `json:"proxies"`
+	// required: true
+	MeasuredProxies int `json:"measuredProxies"`
+
+	// CPU of the proxies, in millicores
+	//
+	// required: true
+	CPU ResourceSizing `json:"cpu"`
+
+	// Memory of the proxies, in MiB
+	//
+	// required: true
+	Memory ResourceSizing `json:"memory"`
+
+	// Reasons of the recommendation
+	//
+	// required: true
+	Findings []string `json:"findings"`
+}
+
+// ResourceSizing compares the usage of a resource by proxies to their request and limit. The usage of a proxy is
+// its peak over the window.
+type ResourceSizing struct {
+	// Usage of the 95th percentile of the proxies, and the highest one
+	P95Usage *float64 `json:"p95Usage,omitempty"`
+	MaxUsage *float64 `json:"maxUsage,omitempty"`
+
+	// Sum of the usages, and of the requests, of the proxies
+	//
+	// required: true
+	TotalUsage float64 `json:"totalUsage"`
+	// required: true
+	TotalRequested float64 `json:"totalRequested"`
+
+	// Most common request and limit of the proxies, as quantities. Empty when unset.
+	//
+	// example: 100m
+	Request string `json:"request,omitempty"`
+	Limit   string `json:"limit,omitempty"`
+
+	// Recommended request and limit, as quantities
+	//
+	// example: 20m
+	RecommendedRequest string `json:"recommendedRequest,omitempty"`
+	RecommendedLimit   string `json:"recommendedLimit,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// SLOStatus holds the attainment of the service level objectives of a service or workload
+type SLOStatus struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	// Compliance period of the objectives, i.e. 30d
+	Window       string     `json:"window"`
+	Availability *SLIStatus `json:"availability,omitempty"`
+	Latency      *SLIStatus `json:"latency,omitempty"`
+}
+
+// SLIStatus holds the attainment of an objective and the rates its error budget is burnt at
+type SLIStatus struct {
+	// Percentage of the good requests targeted
+	Objective float64 `json:"objective"`
+	// Latency threshold of the good requests, for latency objectives
+	ThresholdMillis float64 `json:"thresholdMillis,omitempty"`
+	// False when there were no requests in the window
+	HasData bool `json:"hasData"`
+	// Percentage of the good requests in the window
+	Attainment float64 `json:"attainment"`
+	// Ratio of the error budget not yet consumed in the window, negative when the objective is missed
+	ErrorBudgetRemaining float64    `json:"errorBudgetRemaining"`
+	BurnRates            []BurnRate `json:"burnRates"`
+	// True when any of the burn rates is firing
+	Alerting bool `json:"alerting"`
+}
+
+// BurnRate is a multi-window burn rate alert: it fires when the error budget is burnt faster than the threshold in
+// both windows. The short window makes the alert stop soon after the issue is fixed.
+type BurnRate struct {
+	Severity      string  `json:"severity"` // page | ticket
+	LongWindow    string  `json:"longWindow"`
+	ShortWindow   string  `json:"shortWindow"`
+	LongBurnRate  float64 `json:"longBurnRate"`
+	ShortBurnRate float64 `json:"shortBurnRate"`
+	Threshold     float64 `json:"threshold"`
+	Firing        bool    `json:"firing"`
+}
@@ -1,10 +1,13 @@
 package models
 
+import "time"
+
 type JaegerInfo struct {
 	Enabled              bool     `json:"enabled"`
 	Integration          bool     `json:"integration"`
 	URL                  string   `json:"url"`
 	NamespaceSelector    bool     `json:"namespaceSelector"`
+	Provider             string   `json:"provider"`
 	WhiteListIstioSystem []string `json:"whiteListIstioSystem"`
 }
 
@@ -14,4 +17,62 @@ type TracingQuery struct {
 	Tags        string `json:"tags"`
 	MinDuration string `json:"minDuration"`
 	Limit       int    `json:"limit"`
+	// Cluster whose tracing backend is queried. When empty, the backends of all the clusters are queried.
+	Cluster string `json:"cluster"`
+}
+
+// TraceQLQuery is a TraceQL search: the spanset filters of Query, combined with the duration and attribute filters
+type TraceQLQuery struct {
+	Query       string            `json:"query"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	MinDuration time.Duration     `json:"minDuration"`
+	MaxDuration time.Duration     `json:"maxDuration"`
+	Attributes  map[string]string `json:"attributes"`
+	Limit       int               `json:"limit"`
+	// Maximum number of matching spans returned per spanset
+	SpansPerSpanSet int `json:"spansPerSpanSet"`
+	// Cluster whose tracing backend is queried. When empty, the backends of all the clusters are queried.
+	Cluster string `json:"cluster"`
+}
+
+// OperationStats are the rate, errors and durations of the spans of an operation, i.e. an endpoint of a service
+type OperationStats struct {
+	Operation  string  `json:"operation"`
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	ErrorRatio float64 `json:"errorRatio"`
+	// Percentiles of the span durations, in milliseconds
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	// Most frequent error messages, by decreasing count
+	TopErrors []OperationError `json:"topErrors"`
+}
+
+type OperationError struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// ErrorTraceGroups are the traces in error of an app, grouped by failure signature
+type ErrorTraceGroups struct {
+	// Number of traces in error
+	Total  int               `json:"total"`
+	Groups []ErrorTraceGroup `json:"groups"`
+}
+
+// ErrorTraceGroup holds the traces failing the same way: on the same service and operation, with the same status
+// code and a similar error message
+type ErrorTraceGroup struct {
+	Service    string `json:"service"`
+	Operation  string `json:"operation"`
+	StatusCode string `json:"statusCode"`
+	// Error message with its variable parts (numbers, IDs, addresses) replaced by placeholders
+	Fingerprint string `json:"fingerprint"`
+	// Error message of one of the traces
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+	// IDs of some of the traces of the group, the latest first
+	TraceIDs []string `json:"traceIDs"`
 }
@@ -0,0 +1,58 @@
+package models
+
+// Status of the checks of a tracing diagnosis
+const (
+	TracingCheckOK      = "ok"
+	TracingCheckWarning = "warning"
+	TracingCheckError   = "error"
+	TracingCheckSkipped = "skipped"
+)
+
+// TracingDiagnosis holds the result of the probes of the tracing backend
+type TracingDiagnosis struct {
+	// URL of the tracing backend queried by Kiali
+	//
+	// required: true
+	URL string `json:"url"`
+
+	// Provider of the tracing API in the configuration
+	//
+	// required: true
+	// example: jaeger
+	Provider string `json:"provider"`
+
+	// Flavor of the API detected at the URL: "jaeger", "otlp" (query API v3 of Jaeger v2), "tempo", "grpc" or
+	// "unknown"
+	//
+	// required: true
+	// example: jaeger
+	DetectedFlavor string `json:"detectedFlavor"`
+
+	// Results of the probes, in the order they ran
+	//
+	// required: true
+	Checks []TracingCheck `json:"checks"`
+}
+
+// TracingCheck is the result of a probe of the tracing backend
+type TracingCheck struct {
+	// Name of the probe: "url", "connection", "tls", "auth", "api", "clock" or "tempo"
+	//
+	// required: true
+	// example: tls
+	Name string `json:"name"`
+
+	// Result of the probe: "ok", "warning", "error" or "skipped"
+	//
+	// required: true
+	// example: error
+	Status string `json:"status"`
+
+	// What the probe found
+	//
+	// required: true
+	Message string `json:"message"`
+
+	// How to fix the configuration or the backend, when the probe didn't pass
+	Hint string `json:"hint,omitempty"`
+}
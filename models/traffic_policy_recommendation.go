@@ -0,0 +1,66 @@
+package models
+
+// TrafficPolicyRecommendation compares the traffic observed by a service to the traffic policy of its
+// DestinationRule, and recommends the sizes of its connection pool and its outlier detection
+type TrafficPolicyRecommendation struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	// example: 1h
+	Window string `json:"window"`
+
+	// Whether the service received requests over the window. Nothing is recommended without traffic.
+	//
+	// required: true
+	HasTraffic bool `json:"hasTraffic"`
+
+	// required: true
+	Observed ObservedTraffic `json:"observed"`
+
+	// DestinationRule of the service, named after it, and whether it has to be created
+	//
+	// required: true
+	DestinationRule string `json:"destinationRule"`
+	// required: true
+	Create bool `json:"create"`
+
+	// Traffic policy of the DestinationRule, and the recommended one
+	Current     map[string]interface{} `json:"current,omitempty"`
+	Recommended map[string]interface{} `json:"recommended,omitempty"`
+
+	// Reasons of the recommendation
+	//
+	// required: true
+	Findings []string `json:"findings"`
+
+	// JSON merge patch of the DestinationRule applying the recommendation, or the DestinationRule to create
+	Patch string `json:"patch,omitempty"`
+}
+
+// ObservedTraffic is the traffic of a service over a window. The envoy stats are not set when not scraped.
+type ObservedTraffic struct {
+	// Requests per second
+	//
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+
+	// Latencies in milliseconds
+	AvgLatency *float64 `json:"avgLatency,omitempty"`
+	P99Latency *float64 `json:"p99Latency,omitempty"`
+
+	// Requests in flight on average, and at the p99 latency, following Little's law
+	//
+	// required: true
+	Concurrency float64 `json:"concurrency"`
+	// required: true
+	PeakConcurrency float64 `json:"peakConcurrency"`
+
+	// Percentage of server errors
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+
+	// Requests per second overflowing the connection pool of the clients, and hosts ejected per second
+	PendingOverflowRate *float64 `json:"pendingOverflowRate,omitempty"`
+	EjectionRate        *float64 `json:"ejectionRate,omitempty"`
+}
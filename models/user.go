@@ -0,0 +1,10 @@
+package models
+
+// UserIdentity is the user behind a request, as known by the authentication strategy
+type UserIdentity struct {
+	// The name of the user
+	Username string `json:"username"`
+
+	// The groups of the user, when the authentication strategy provides them
+	Groups []string `json:"groups"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// UserPreferences are the settings of the console of a user, kept by Kiali to follow the user across browsers
+//
+// swagger:model userPreferences
+type UserPreferences struct {
+	// The namespaces selected when the user opens the console
+	//
+	// required: true
+	// example: ["bookinfo"]
+	DefaultNamespaces []string `json:"defaultNamespaces"`
+
+	// How often the pages are refreshed, in seconds. 0 to not refresh them.
+	//
+	// required: true
+	// example: 15
+	RefreshInterval int64 `json:"refreshInterval"`
+
+	// The time range of the pages, in seconds before now. 0 for the default of the console.
+	//
+	// required: true
+	// example: 600
+	Duration int64 `json:"duration"`
+
+	// The columns hidden by the user, by list of the console
+	//
+	// required: true
+	// example: {"workloads":["labels"]}
+	HiddenColumns map[string][]string `json:"hiddenColumns"`
+}
@@ -0,0 +1,306 @@
+package models
+
+import "time"
+
+// Wizards generating Istio config on the server side
+const (
+	WizardCanaryPromotion  = "canary_promotion"
+	WizardFaultInjection   = "fault_injection"
+	WizardTrafficMirroring = "traffic_mirroring"
+	WizardTrafficShifting  = "traffic_shifting"
+)
+
+// WizardChange is the Istio config applied by a wizard on a service
+type WizardChange struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Service string `json:"service"`
+
+	// Wizard which applied the change
+	//
+	// required: true
+	// example: traffic_shifting
+	Wizard string `json:"wizard"`
+
+	// required: true
+	AppliedAt time.Time `json:"appliedAt"`
+
+	// Time the change is reverted after, unless it is confirmed before. Not set when the change needs no confirmation.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Whether the change was confirmed, or reverted
+	//
+	// required: true
+	Confirmed bool `json:"confirmed"`
+	// required: true
+	RolledBack bool `json:"rolledBack"`
+
+	// Istio objects created or updated by the change
+	//
+	// required: true
+	Objects []WizardObject `json:"objects"`
+}
+
+// WizardObject is an Istio object created or updated by a wizard
+type WizardObject struct {
+	// required: true
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+	// required: true
+	Name string `json:"name"`
+	// Whether the object was created by the change, it is deleted when the change is reverted
+	//
+	// required: true
+	Created bool `json:"created"`
+}
+
+// TrafficShiftingRequest holds the weights of the traffic routed to each workload of a service
+type TrafficShiftingRequest struct {
+	// Workloads of the service and their weights, adding up to 100
+	//
+	// required: true
+	Workloads []WorkloadWeight `json:"workloads"`
+
+	// Delay after which the change is reverted unless it is confirmed, as a Go duration. No confirmation is needed
+	// when not set.
+	//
+	// example: 5m
+	TTL string `json:"ttl,omitempty"`
+}
+
+// WorkloadWeight is the weight of the traffic routed to a workload
+type WorkloadWeight struct {
+	// required: true
+	// example: reviews-v2
+	Name string `json:"name"`
+	// required: true
+	// example: 20
+	Weight int `json:"weight"`
+}
+
+// CanaryPromotionRequest holds the success criteria of a canary subset, and the weight added to it when they pass
+type CanaryPromotionRequest struct {
+	// Subset of the canary, in the VirtualService of the service
+	//
+	// required: true
+	// example: v2
+	Subset string `json:"subset"`
+
+	// Weight added to the canary when the criteria pass, 10 by default
+	//
+	// example: 10
+	Step int `json:"step,omitempty"`
+
+	// Window of the metrics evaluated, 5m by default
+	//
+	// example: 5m
+	Window string `json:"window,omitempty"`
+
+	// Maximum ratio of server errors of the canary, in percent, 1 by default
+	//
+	// example: 1
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+
+	// Maximum p99 latency of the canary, in milliseconds, 500 by default
+	//
+	// example: 500
+	MaxP99LatencyMillis float64 `json:"maxP99LatencyMillis,omitempty"`
+
+	// Delay after which the promotion is reverted unless it is confirmed, as for the traffic shifting
+	//
+	// example: 5m
+	TTL string `json:"ttl,omitempty"`
+}
+
+// CanaryPromotion is the evaluation of the criteria of a canary subset, and the resulting promotion
+type CanaryPromotion struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	Subset string `json:"subset"`
+	// required: true
+	Window string `json:"window"`
+
+	// required: true
+	Criteria []PromotionCriterion `json:"criteria"`
+
+	// Whether every criterion passed
+	//
+	// required: true
+	Passed bool `json:"passed"`
+
+	// Weight of the canary before and after the evaluation, unchanged when a criterion failed
+	//
+	// required: true
+	PreviousWeight int `json:"previousWeight"`
+	// required: true
+	Weight int `json:"weight"`
+
+	// Change applied on the VirtualService, when the canary was promoted
+	Change *WizardChange `json:"change,omitempty"`
+}
+
+// PromotionCriterion is a success criterion of a canary
+type PromotionCriterion struct {
+	// required: true
+	// example: errorRate
+	Name string `json:"name"`
+	// Value measured over the window, not set without traffic
+	Value *float64 `json:"value,omitempty"`
+	// required: true
+	Threshold float64 `json:"threshold"`
+	// required: true
+	Passed bool `json:"passed"`
+}
+
+// FaultInjectionRequest holds the faults injected in the http routes of a service, until their expiration
+type FaultInjectionRequest struct {
+	// Faults injected, by route
+	//
+	// required: true
+	Faults []RouteFault `json:"faults"`
+
+	// Delay after which the faults are removed, as a Go duration
+	//
+	// required: true
+	// example: 15m
+	TTL string `json:"ttl"`
+}
+
+// RouteFault is a delay and an abort injected in an http route of a VirtualService
+type RouteFault struct {
+	// Name of the http route, the fault is injected in all the routes when not set
+	//
+	// example: reviews-v2-route
+	Route string      `json:"route,omitempty"`
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay delays a percentage of the requests of a route
+type FaultDelay struct {
+	// required: true
+	// example: 10
+	Percentage float64 `json:"percentage"`
+	// required: true
+	// example: 5s
+	FixedDelay string `json:"fixedDelay"`
+}
+
+// FaultAbort aborts a percentage of the requests of a route with an http status
+type FaultAbort struct {
+	// required: true
+	// example: 10
+	Percentage float64 `json:"percentage"`
+	// required: true
+	// example: 503
+	HTTPStatus int `json:"httpStatus"`
+}
+
+// FaultInjection is a fault injected in an http route of a VirtualService of the mesh
+type FaultInjection struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	VirtualService string `json:"virtualService"`
+
+	// Name of the http route, when it has one, and its index in the http routes
+	Route string `json:"route,omitempty"`
+	// required: true
+	RouteIndex int `json:"routeIndex"`
+
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+
+	// Whether the fault was injected by the fault injection wizard
+	//
+	// required: true
+	Wizard bool `json:"wizard"`
+
+	// Time the fault is removed at, when injected by the wizard
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Whether the fault outlived its expiration, as when Kiali was restarted before it
+	//
+	// required: true
+	Expired bool `json:"expired"`
+}
+
+// TrafficMirroringRequest holds the shadow destination a percentage of the traffic of a service is mirrored to
+type TrafficMirroringRequest struct {
+	// Host of the shadow destination, the service itself when not set
+	//
+	// example: reviews
+	Host string `json:"host,omitempty"`
+
+	// Subset of the shadow destination, required when it is the service itself
+	//
+	// example: v3
+	Subset string `json:"subset,omitempty"`
+
+	// Percentage of the requests mirrored, 100 by default
+	//
+	// example: 50
+	Percentage float64 `json:"percentage,omitempty"`
+
+	// Name of the http route mirrored, all the routes are mirrored when not set
+	Route string `json:"route,omitempty"`
+
+	// Delay after which the change is reverted unless it is confirmed, as for the traffic shifting
+	//
+	// example: 30m
+	TTL string `json:"ttl,omitempty"`
+}
+
+// TrafficMirroringReport compares the responses of the primary destination of a service to those of its shadow
+// destination, over a window
+type TrafficMirroringReport struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	// example: 10m
+	Window string `json:"window"`
+
+	// Percentage of the requests mirrored
+	//
+	// required: true
+	Percentage float64 `json:"percentage"`
+
+	// required: true
+	Primary MirroringDestinationStats `json:"primary"`
+	// required: true
+	Mirror MirroringDestinationStats `json:"mirror"`
+
+	// Differences of the mirror to the primary, in points of error rate and in milliseconds, when both have traffic
+	ErrorRateDelta  *float64 `json:"errorRateDelta,omitempty"`
+	P99LatencyDelta *float64 `json:"p99LatencyDelta,omitempty"`
+}
+
+// MirroringDestinationStats are the responses of a destination, as reported by its workloads
+type MirroringDestinationStats struct {
+	// required: true
+	Host   string `json:"host"`
+	Subset string `json:"subset,omitempty"`
+
+	// Whether the destination received requests over the window. The other fields are not set without traffic.
+	//
+	// required: true
+	HasTraffic bool `json:"hasTraffic"`
+
+	// Percentage of the responses by class of http status
+	//
+	// example: {"2xx": 98.5, "5xx": 1.5}
+	ResponseCodes map[string]float64 `json:"responseCodes,omitempty"`
+
+	// Percentage of server errors
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+
+	// Latencies in milliseconds
+	P50Latency *float64 `json:"p50Latency,omitempty"`
+	P99Latency *float64 `json:"p99Latency,omitempty"`
+}
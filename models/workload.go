@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
 )
 
 type WorkloadList struct {
@@ -112,6 +113,46 @@ type Workload struct {
 
 	// Additional details to display, such as configured annotations
 	AdditionalDetails []AdditionalItem `json:"additionalDetails"`
+
+	// Annotations of the workload overriding the health configuration
+	HealthAnnotations map[string]string `json:"healthAnnotations,omitempty"`
+
+	// HorizontalPodAutoscalers targeting the workload
+	HorizontalAutoscalers []HorizontalAutoscaler `json:"horizontalAutoscalers,omitempty"`
+
+	// VerticalPodAutoscalers targeting the workload
+	VerticalAutoscalers []VerticalAutoscaler `json:"verticalAutoscalers,omitempty"`
+
+	// KEDA ScaledObjects scaling the workload
+	ScaledObjects []ScaledObject `json:"scaledObjects,omitempty"`
+
+	// Progress of the delivery of an Argo Rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStatus is the progress of the delivery of a new revision by an Argo Rollout
+type RolloutStatus struct {
+	// Strategy of the Rollout: canary or blueGreen
+	// example: canary
+	Strategy string `json:"strategy"`
+
+	// Phase of the Rollout: Progressing, Paused, Healthy or Degraded
+	// example: Paused
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// Hashes of the pod templates of the stable revision and of the revision being delivered, if any
+	StableRevision string `json:"stableRevision,omitempty"`
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// Current step of the canary, out of its number of steps
+	CurrentStep *int32 `json:"currentStep,omitempty"`
+	Steps       int    `json:"steps,omitempty"`
+
+	// Weight of the traffic routed to the canary revision, in percent
+	CanaryWeight *int32 `json:"canaryWeight,omitempty"`
+
+	Paused bool `json:"paused"`
 }
 
 type Workloads []*Workload
@@ -156,6 +197,7 @@ func (workload *Workload) parseObjectMeta(meta *meta_v1.ObjectMeta, tplMeta *met
 	workload.CreatedAt = formatTime(meta.CreationTimestamp.Time)
 	workload.ResourceVersion = meta.ResourceVersion
 	workload.AdditionalDetails = GetAdditionalDetails(conf, meta.Annotations)
+	workload.HealthAnnotations = GetHealthAnnotations(meta.Annotations)
 	workload.AdditionalDetailSample = GetFirstAdditionalIcon(conf, meta.Annotations)
 }
 
@@ -169,6 +211,55 @@ func (workload *Workload) ParseDeployment(d *apps_v1.Deployment) {
 	workload.AvailableReplicas = d.Status.AvailableReplicas
 }
 
+// ParseRollout reads an Argo Rollout. Its pods must be set first, they are labeled with their revision.
+func (workload *Workload) ParseRollout(r *kubernetes.Rollout) {
+	workload.Type = kubernetes.RolloutType
+	workload.parseObjectMeta(&r.ObjectMeta, &r.Spec.Template.ObjectMeta)
+	workload.DesiredReplicas = 1
+	if r.Spec.Replicas != nil {
+		workload.DesiredReplicas = *r.Spec.Replicas
+	}
+	workload.CurrentReplicas = r.Status.Replicas
+	workload.AvailableReplicas = r.Status.AvailableReplicas
+
+	rollout := &RolloutStatus{
+		Strategy:       "canary",
+		Phase:          r.Status.Phase,
+		Message:        r.Status.Message,
+		StableRevision: r.Status.StableRS,
+		Paused:         r.Spec.Paused || len(r.Status.PauseConditions) > 0,
+	}
+	if r.Spec.Strategy.BlueGreen != nil {
+		rollout.Strategy = "blueGreen"
+	}
+	if r.Status.CurrentPodHash != "" && r.Status.CurrentPodHash != r.Status.StableRS {
+		rollout.CanaryRevision = r.Status.CurrentPodHash
+	}
+	if canary := r.Spec.Strategy.Canary; canary != nil && len(canary.Steps) > 0 {
+		rollout.Steps = len(canary.Steps)
+		rollout.CurrentStep = r.Status.CurrentStepIndex
+	}
+	if weights := r.Status.Canary.Weights; weights != nil {
+		rollout.CanaryWeight = &weights.Canary.Weight
+	}
+	workload.Rollout = rollout
+
+	for _, pod := range workload.Pods {
+		switch pod.Labels[kubernetes.RolloutPodTemplateHashLabel] {
+		case "":
+		case rollout.StableRevision:
+			pod.RolloutRevision = RolloutRevisionStable
+		case rollout.CanaryRevision:
+			pod.RolloutRevision = RolloutRevisionCanary
+			if rollout.Strategy == "blueGreen" {
+				pod.RolloutRevision = RolloutRevisionPreview
+			}
+		default:
+			pod.RolloutRevision = RolloutRevisionOld
+		}
+	}
+}
+
 func (workload *Workload) ParseReplicaSet(r *apps_v1.ReplicaSet) {
 	workload.Type = "ReplicaSet"
 	workload.parseObjectMeta(&r.ObjectMeta, &r.Spec.Template.ObjectMeta)
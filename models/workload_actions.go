@@ -0,0 +1,37 @@
+package models
+
+// WorkloadScale is the number of replicas a workload was scaled to
+type WorkloadScale struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+	// required: true
+	// example: reviews-v1
+	Name string `json:"name"`
+	// Deployment, StatefulSet or Rollout
+	//
+	// required: true
+	// example: Deployment
+	Type string `json:"type"`
+	// required: true
+	// example: 3
+	Replicas int32 `json:"replicas"`
+}
+
+// WorkloadRestart is the time the pods of a workload were restarted at
+type WorkloadRestart struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+	// required: true
+	// example: reviews-v1
+	Name string `json:"name"`
+	// Deployment, StatefulSet or Rollout
+	//
+	// required: true
+	// example: Deployment
+	Type string `json:"type"`
+	// required: true
+	// example: 2021-03-01T10:00:00Z
+	RestartedAt string `json:"restartedAt"`
+}
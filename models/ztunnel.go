@@ -0,0 +1,57 @@
+package models
+
+import "github.com/kiali/kiali/kubernetes"
+
+const (
+	ZtunnelDriftWorkload = "workload"
+	ZtunnelDriftService  = "service"
+	ZtunnelDriftPolicy   = "policy"
+)
+
+// ZtunnelConfig aggregates the workloads, services and policies known by the ztunnels of the cluster
+type ZtunnelConfig struct {
+	// required: true
+	Ztunnels []ZtunnelPod `json:"ztunnels"`
+	// required: true
+	Workloads []ZtunnelWorkload `json:"workloads"`
+	// required: true
+	Services []ZtunnelService `json:"services"`
+	// required: true
+	Policies []ZtunnelPolicy `json:"policies"`
+
+	// Entries missing from some ztunnels or differing between them, the values being by ztunnel pod
+	//
+	// required: true
+	Drifts []ConfigDrift `json:"drifts"`
+}
+
+// ZtunnelPod is a ztunnel whose config dump was read
+type ZtunnelPod struct {
+	// required: true
+	Pod string `json:"pod"`
+	// required: true
+	Node string `json:"node"`
+	// Why the config dump could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// ZtunnelWorkload is a workload instance, with the ztunnels knowing it
+type ZtunnelWorkload struct {
+	kubernetes.ZtunnelWorkload
+	// required: true
+	Ztunnels []string `json:"ztunnels"`
+}
+
+// ZtunnelService is a service, with the ztunnels knowing it
+type ZtunnelService struct {
+	kubernetes.ZtunnelService
+	// required: true
+	Ztunnels []string `json:"ztunnels"`
+}
+
+// ZtunnelPolicy is an authorization policy, with the ztunnels enforcing it
+type ZtunnelPolicy struct {
+	kubernetes.ZtunnelPolicy
+	// required: true
+	Ztunnels []string `json:"ztunnels"`
+}
@@ -3,7 +3,6 @@ package prometheus
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"sync"
 	"time"
 
@@ -12,20 +11,25 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/config"
-	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
-	"github.com/kiali/kiali/util/httputil"
 )
 
 // ClientInterface for mocks (only mocked function are necessary here)
 type ClientInterface interface {
+	FetchExemplars(metricName string, labels []string, q *RangeQuery) ([]ExemplarSeries, error)
 	FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
+	FetchRate(series, window string, queryTime time.Time) (float64, bool, error)
+	FetchRateRatio(parts []string, total, window string, queryTime time.Time) (float64, bool, error)
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
+	GetClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetConfiguration() (prom_v1.ConfigResult, error)
+	GetContainerResourceUsage(namespace, container, window string, queryTime time.Time) (model.Vector, model.Vector, error)
+	GetEgressClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error)
+	GetExternalTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetFlags() (prom_v1.FlagsResult, error)
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
@@ -67,21 +71,7 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 	// Prom Cache will be initialized once at first use of Prometheus Client
 	once.Do(initPromCache)
 
-	// Be sure to copy config.Auth and not modify the existing
-	auth := cfg.Auth
-	if auth.UseKialiToken {
-		// Note: if we are using the 'bearer' authentication method then we want to use the Kiali
-		// service account token and not the user's token. This is because Kiali does filtering based
-		// on the user's token and prevents people who shouldn't have access to particular metrics.
-		token, err := kubernetes.GetKialiToken()
-		if err != nil {
-			log.Errorf("Could not read the Kiali Service Account token: %v", err)
-			return nil, err
-		}
-		auth.Token = token
-	}
-
-	transportConfig, err := httputil.AuthTransport(&auth, api.DefaultRoundTripper.(*http.Transport))
+	transportConfig, err := newTenancyRoundTripper(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +81,7 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s)}
+	client := Client{p8s: p8s, api: newCachingAPI(newMultiClusterAPI(newRecordingRulesAPI(prom_v1.NewAPI(p8s), cfg), cfg), cfg)}
 	return &client, nil
 }
 
@@ -217,7 +207,7 @@ func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q
 		query += fmt.Sprintf(" by (%s)", grouping)
 	}
 	query = roundSignificant(query, 0.001)
-	return fetchRange(in.api, query, q.Range)
+	return fetchRange(in.api, query, q)
 }
 
 // FetchRateRange fetches a counter's rate in given range
@@ -225,6 +215,50 @@ func (in *Client) FetchRateRange(metricName string, labels []string, grouping st
 	return fetchRateRange(in.api, metricName, labels, grouping, q)
 }
 
+// FetchRate fetches the summed rate of the series, over the window ending at the query time. The boolean is false when
+// no series matches.
+func (in *Client) FetchRate(series, window string, queryTime time.Time) (float64, bool, error) {
+	return fetchRate(in.api, series, window, queryTime)
+}
+
+// FetchRateRatio fetches the ratio of the summed rates of the parts series to the rate of the total series, over
+// a window ending at queryTime. It returns false when the total rate is zero, i.e. there were no requests.
+func (in *Client) FetchRateRatio(parts []string, total, window string, queryTime time.Time) (float64, bool, error) {
+	return fetchRateRatio(in.api, parts, total, window, queryTime)
+}
+
+// GetContainerResourceUsage queries Prometheus to fetch the peak CPU usage, in cores, and the peak memory working
+// set, in bytes, of a container of the pods of a namespace over a window, as reported by cAdvisor.
+// Returns (cpu, memory, error), with a sample per pod
+func (in *Client) GetContainerResourceUsage(namespace, container, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	log.Tracef("GetContainerResourceUsage [namespace: %s] [container: %s] [window: %s] [queryTime: %s]", namespace, container, window, queryTime.String())
+	return getContainerResourceUsage(in.api, namespace, container, window, queryTime)
+}
+
+// GetEgressClusterTraffic queries Prometheus to fetch the rates of the requests and of the TCP connections routed to
+// the PassthroughCluster or the BlackHoleCluster over a window, as reported by their source workloads.
+// Returns (requests, connections, error), by source workload, destination service and cluster
+func (in *Client) GetEgressClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	log.Tracef("GetEgressClusterTraffic [window: %s] [queryTime: %s]", window, queryTime.String())
+	return getEgressClusterTraffic(in.api, window, queryTime)
+}
+
+// GetExternalTraffic queries Prometheus to fetch the rates of the requests and of the TCP connections to destinations
+// without a workload of the mesh, such as the hosts of the ServiceEntries and the PassthroughCluster, over a window,
+// as reported by their source workloads.
+// Returns (requests, connections, error), by source workload, destination service and protocol
+func (in *Client) GetExternalTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	log.Tracef("GetExternalTraffic [window: %s] [queryTime: %s]", window, queryTime.String())
+	return getExternalTraffic(in.api, window, queryTime)
+}
+
+// GetClusterTraffic queries Prometheus to fetch the rates of all the requests and of the failed requests, reported by
+// their source, by source cluster, destination cluster and source namespace
+func (in *Client) GetClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	log.Tracef("GetClusterTraffic [window: %s] [queryTime: %s]", window, queryTime.String())
+	return getClusterTraffic(in.api, window, queryTime)
+}
+
 // FetchHistogramRange fetches bucketed metric as histogram in given range
 func (in *Client) FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram {
 	return fetchHistogramRange(in.api, metricName, labels, grouping, q)
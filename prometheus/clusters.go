@@ -0,0 +1,451 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// multiClusterAPI sends the queries that are not for a specific cluster to the Prometheus of every cluster with its
+// own endpoint, and merges the results. The samples of the series with the same labels are summed, which is right
+// for rates and counters: the ratios and the histogram averages and quantiles are computed by Kiali from the
+// merged components (see fetchRateRatio and fetchHistogramRange), as they can't be summed.
+// Queries for a specific cluster (see WithCluster) are sent to the Prometheus of that cluster only.
+type multiClusterAPI struct {
+	prom_v1.API
+	// Clusters whose metrics are stored in their own Prometheus
+	clusters []string
+}
+
+// remoteClusters returns the clusters whose metrics are not stored in the global Prometheus
+func remoteClusters(cfg config.PrometheusConfig) []string {
+	home := config.Get().KubernetesConfig.ClusterName
+	clusters := []string{}
+	for cluster, clusterConfig := range cfg.Clusters {
+		if cluster != home && clusterConfig.URL != "" && clusterConfig.URL != cfg.URL {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// newMultiClusterAPI returns the API querying the Prometheus of all the clusters, or the given API when all the
+// metrics are stored in the same Prometheus
+func newMultiClusterAPI(promAPI prom_v1.API, cfg config.PrometheusConfig) prom_v1.API {
+	clusters := remoteClusters(cfg)
+	if len(clusters) == 0 {
+		return promAPI
+	}
+	return multiClusterAPI{API: promAPI, clusters: clusters}
+}
+
+// queryAll runs a query on the Prometheus of every cluster. A failure of the Prometheus of the home cluster fails
+// the query; the results of the clusters whose Prometheus fails are skipped.
+func (m multiClusterAPI) queryAll(ctx context.Context, query func(ctx context.Context) (model.Value, api.Error)) (model.Value, api.Error) {
+	results := make([]model.Value, len(m.clusters)+1)
+	errs := make([]api.Error, len(m.clusters)+1)
+
+	var wg sync.WaitGroup
+	wg.Add(len(m.clusters) + 1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = query(ctx)
+	}()
+	// A remote Prometheus not answering in time is skipped like a failing one
+	timeout := time.Duration(config.Get().Clustering.RequestTimeout) * time.Second
+	for i, cluster := range m.clusters {
+		go func(i int, cluster string) {
+			defer wg.Done()
+			clusterCtx := WithCluster(ctx, cluster)
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				clusterCtx, cancel = context.WithTimeout(clusterCtx, timeout)
+				defer cancel()
+			}
+			results[i+1], errs[i+1] = query(clusterCtx)
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	merged := results[0]
+	for i, cluster := range m.clusters {
+		if errs[i+1] != nil {
+			log.Warningf("Skipping the metrics of cluster [%s]: %v", cluster, errs[i+1])
+			continue
+		}
+		merged = mergeValues(merged, results[i+1])
+	}
+	return merged, nil
+}
+
+// mergeValues sums the samples of the series with the same labels of vectors and matrices. Other values can't be
+// merged, the first one is kept.
+func mergeValues(value, other model.Value) model.Value {
+	switch v := value.(type) {
+	case model.Vector:
+		if o, ok := other.(model.Vector); ok {
+			return sumVectors(v, o)
+		}
+	case model.Matrix:
+		if o, ok := other.(model.Matrix); ok {
+			return sumMatrices(v, o)
+		}
+	}
+	return value
+}
+
+func sumVectors(vector, other model.Vector) model.Vector {
+	merged := make(model.Vector, 0, len(vector)+len(other))
+	index := make(map[model.Fingerprint]int, len(vector)+len(other))
+	for _, samples := range []model.Vector{vector, other} {
+		for _, sample := range samples {
+			fingerprint := sample.Metric.Fingerprint()
+			if i, found := index[fingerprint]; found {
+				merged[i].Value += sample.Value
+				continue
+			}
+			index[fingerprint] = len(merged)
+			sampleCopy := *sample
+			merged = append(merged, &sampleCopy)
+		}
+	}
+	return merged
+}
+
+func sumMatrices(matrix, other model.Matrix) model.Matrix {
+	merged := make(model.Matrix, 0, len(matrix)+len(other))
+	index := make(map[model.Fingerprint]int, len(matrix)+len(other))
+	for _, streams := range []model.Matrix{matrix, other} {
+		for _, stream := range streams {
+			fingerprint := stream.Metric.Fingerprint()
+			if i, found := index[fingerprint]; found {
+				merged[i].Values = sumSamplePairs(merged[i].Values, stream.Values)
+				continue
+			}
+			index[fingerprint] = len(merged)
+			merged = append(merged, &model.SampleStream{
+				Metric: stream.Metric,
+				Values: append([]model.SamplePair{}, stream.Values...),
+			})
+		}
+	}
+	return merged
+}
+
+// sumSamplePairs sums the values with the same timestamp, and keeps the others
+func sumSamplePairs(values, other []model.SamplePair) []model.SamplePair {
+	index := make(map[model.Time]int, len(values))
+	for i, pair := range values {
+		index[pair.Timestamp] = i
+	}
+	for _, pair := range other {
+		if i, found := index[pair.Timestamp]; found {
+			values[i].Value += pair.Value
+		} else {
+			values = append(values, pair)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Timestamp.Before(values[j].Timestamp) })
+	return values
+}
+
+// isMultiCluster tells if the queries of the API are sent to the Prometheus of several clusters
+func isMultiCluster(promAPI prom_v1.API) bool {
+	switch a := promAPI.(type) {
+	case multiClusterAPI:
+		return true
+	case cachingAPI:
+		return isMultiCluster(a.API)
+	}
+	return false
+}
+
+// fetchMultiClusterHistogramRange computes the averages and the quantiles of a histogram from the sums, the counts and
+// the buckets of all the clusters, as they can't be merged once computed by each Prometheus.
+func fetchMultiClusterHistogramRange(api prom_v1.API, metricName, labels, grouping string, q *RangeQuery) Histogram {
+	histogram := make(Histogram, len(q.Quantiles)+1)
+	if q.Avg {
+		sumQuery, countQuery := buildHistogramAvgQueries(metricName, labels, grouping, q.RateInterval)
+		sums := fetchRange(api, sumQuery, q)
+		counts := fetchRange(api, countQuery, q)
+		switch {
+		case sums.Err != nil:
+			histogram["avg"] = Metric{Err: sums.Err}
+		case counts.Err != nil:
+			histogram["avg"] = Metric{Err: counts.Err}
+		default:
+			histogram["avg"] = Metric{Matrix: roundSignificantMatrix(divideMatrices(sums.Matrix, counts.Matrix), 0.001)}
+		}
+	}
+	if len(q.Quantiles) > 0 {
+		buckets := fetchRange(api, buildHistogramBucketsQuery(metricName, labels, grouping, q.RateInterval), q)
+		for _, quantile := range q.Quantiles {
+			if buckets.Err != nil {
+				histogram[quantile] = Metric{Err: buckets.Err}
+				continue
+			}
+			value, err := strconv.ParseFloat(quantile, 64)
+			if err != nil {
+				histogram[quantile] = Metric{Err: fmt.Errorf("invalid quantile [%s]: %v", quantile, err)}
+				continue
+			}
+			histogram[quantile] = Metric{Matrix: roundSignificantMatrix(histogramQuantile(value, buckets.Matrix), 0.001)}
+		}
+	}
+	return histogram
+}
+
+// fetchMultiClusterHistogramValues is the instant version of fetchMultiClusterHistogramRange
+func fetchMultiClusterHistogramValues(api prom_v1.API, metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
+	histogram := make(map[string]model.Vector, len(quantiles)+1)
+	if avg {
+		sumQuery, countQuery := buildHistogramAvgQueries(metricName, labels, grouping, rateInterval)
+		sums, err := fetchVector(api, sumQuery, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		counts, err := fetchVector(api, countQuery, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		histogram["avg"] = matrixToVector(roundSignificantMatrix(divideMatrices(vectorToMatrix(sums), vectorToMatrix(counts)), 0.001))
+	}
+	if len(quantiles) > 0 {
+		buckets, err := fetchVector(api, buildHistogramBucketsQuery(metricName, labels, grouping, rateInterval), queryTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, quantile := range quantiles {
+			value, err := strconv.ParseFloat(quantile, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quantile [%s]: %v", quantile, err)
+			}
+			histogram[quantile] = matrixToVector(roundSignificantMatrix(histogramQuantile(value, vectorToMatrix(buckets)), 0.001))
+		}
+	}
+	return histogram, nil
+}
+
+func vectorToMatrix(vector model.Vector) model.Matrix {
+	matrix := make(model.Matrix, len(vector))
+	for i, sample := range vector {
+		matrix[i] = &model.SampleStream{
+			Metric: sample.Metric,
+			Values: []model.SamplePair{{Timestamp: sample.Timestamp, Value: sample.Value}},
+		}
+	}
+	return matrix
+}
+
+func matrixToVector(matrix model.Matrix) model.Vector {
+	vector := make(model.Vector, 0, len(matrix))
+	for _, stream := range matrix {
+		for _, pair := range stream.Values {
+			vector = append(vector, &model.Sample{Metric: stream.Metric, Timestamp: pair.Timestamp, Value: pair.Value})
+		}
+	}
+	return vector
+}
+
+// divideMatrices divides the values of the series with the same labels at the same timestamps, like the PromQL
+// division of two vectors
+func divideMatrices(dividends, divisors model.Matrix) model.Matrix {
+	index := make(map[model.Fingerprint]map[model.Time]model.SampleValue, len(divisors))
+	for _, stream := range divisors {
+		values := make(map[model.Time]model.SampleValue, len(stream.Values))
+		for _, pair := range stream.Values {
+			values[pair.Timestamp] = pair.Value
+		}
+		index[stream.Metric.Fingerprint()] = values
+	}
+	quotients := model.Matrix{}
+	for _, stream := range dividends {
+		divisorValues, found := index[stream.Metric.Fingerprint()]
+		if !found {
+			continue
+		}
+		quotient := &model.SampleStream{Metric: stream.Metric}
+		for _, pair := range stream.Values {
+			if divisor, found := divisorValues[pair.Timestamp]; found {
+				quotient.Values = append(quotient.Values, model.SamplePair{Timestamp: pair.Timestamp, Value: pair.Value / divisor})
+			}
+		}
+		if len(quotient.Values) > 0 {
+			quotients = append(quotients, quotient)
+		}
+	}
+	return quotients
+}
+
+// roundSignificantMatrix rounds the values like the query built by roundSignificant
+func roundSignificantMatrix(matrix model.Matrix, precision float64) model.Matrix {
+	for _, stream := range matrix {
+		for i, pair := range stream.Values {
+			if rounded := math.Floor(float64(pair.Value)/precision+0.5) * precision; rounded > precision {
+				stream.Values[i].Value = model.SampleValue(rounded)
+			}
+		}
+	}
+	return matrix
+}
+
+type histogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogramQuantile computes the quantile of the buckets of each series, like the PromQL histogram_quantile function
+func histogramQuantile(quantile float64, buckets model.Matrix) model.Matrix {
+	type series struct {
+		stream  *model.SampleStream
+		buckets map[model.Time][]histogramBucket
+	}
+	index := map[model.Fingerprint]*series{}
+	ordered := []*series{}
+	for _, stream := range buckets {
+		upperBound, err := strconv.ParseFloat(string(stream.Metric[model.BucketLabel]), 64)
+		if err != nil {
+			// Like Prometheus, the series without a valid upper bound are ignored
+			continue
+		}
+		metric := stream.Metric.Clone()
+		delete(metric, model.BucketLabel)
+		fingerprint := metric.Fingerprint()
+		s, found := index[fingerprint]
+		if !found {
+			s = &series{stream: &model.SampleStream{Metric: metric}, buckets: map[model.Time][]histogramBucket{}}
+			index[fingerprint] = s
+			ordered = append(ordered, s)
+		}
+		for _, pair := range stream.Values {
+			s.buckets[pair.Timestamp] = append(s.buckets[pair.Timestamp], histogramBucket{upperBound: upperBound, count: float64(pair.Value)})
+		}
+	}
+	quantiles := make(model.Matrix, 0, len(ordered))
+	for _, s := range ordered {
+		for timestamp, timestampBuckets := range s.buckets {
+			s.stream.Values = append(s.stream.Values, model.SamplePair{Timestamp: timestamp, Value: model.SampleValue(bucketQuantile(quantile, timestampBuckets))})
+		}
+		sort.Slice(s.stream.Values, func(i, j int) bool { return s.stream.Values[i].Timestamp.Before(s.stream.Values[j].Timestamp) })
+		quantiles = append(quantiles, s.stream)
+	}
+	return quantiles
+}
+
+// bucketQuantile is the algorithm of the PromQL histogram_quantile function: the quantile is interpolated linearly
+// in the bucket it falls in, the buckets holding the cumulative counts of the observations up to their upper bound.
+func bucketQuantile(quantile float64, buckets []histogramBucket) float64 {
+	if math.IsNaN(quantile) {
+		return math.NaN()
+	}
+	if quantile < 0 {
+		return math.Inf(-1)
+	}
+	if quantile > 1 {
+		return math.Inf(+1)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upperBound < buckets[j].upperBound })
+	if len(buckets) < 2 || !math.IsInf(buckets[len(buckets)-1].upperBound, +1) {
+		return math.NaN()
+	}
+	// The counts of the buckets with the same upper bound are summed, and the counts are made monotonic, as the
+	// rates of the buckets are not scraped at exactly the same time
+	coalesced := buckets[:1]
+	for _, bucket := range buckets[1:] {
+		last := &coalesced[len(coalesced)-1]
+		if bucket.upperBound == last.upperBound {
+			last.count += bucket.count
+		} else {
+			coalesced = append(coalesced, bucket)
+		}
+	}
+	buckets = coalesced
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i].count < buckets[i-1].count {
+			buckets[i].count = buckets[i-1].count
+		}
+	}
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := quantile * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	bucketStart := 0.0
+	bucketEnd := buckets[b].upperBound
+	count := buckets[b].count
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
+func (m multiClusterAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	if hasCluster(ctx) {
+		return m.API.Query(ctx, query, ts)
+	}
+	return m.queryAll(ctx, func(ctx context.Context) (model.Value, api.Error) {
+		return m.API.Query(ctx, query, ts)
+	})
+}
+
+func (m multiClusterAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, api.Error) {
+	if hasCluster(ctx) {
+		return m.API.QueryRange(ctx, query, r)
+	}
+	return m.queryAll(ctx, func(ctx context.Context) (model.Value, api.Error) {
+		return m.API.QueryRange(ctx, query, r)
+	})
+}
+
+func (m multiClusterAPI) Series(ctx context.Context, matches []string, startTime time.Time, endTime time.Time) ([]model.LabelSet, api.Error) {
+	series, err := m.API.Series(ctx, matches, startTime, endTime)
+	if err != nil || hasCluster(ctx) {
+		return series, err
+	}
+	// Series are used to discover metrics, so the series of all the clusters are listed once
+	seen := make(map[model.Fingerprint]bool, len(series))
+	for _, labelSet := range series {
+		seen[labelSet.Fingerprint()] = true
+	}
+	for _, cluster := range m.clusters {
+		clusterSeries, err := m.API.Series(WithCluster(ctx, cluster), matches, startTime, endTime)
+		if err != nil {
+			log.Warningf("Skipping the series of cluster [%s]: %v", cluster, err)
+			continue
+		}
+		for _, labelSet := range clusterSeries {
+			if !seen[labelSet.Fingerprint()] {
+				seen[labelSet.Fingerprint()] = true
+				series = append(series, labelSet)
+			}
+		}
+	}
+	return series, nil
+}
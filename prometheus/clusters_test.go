@@ -0,0 +1,164 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+// fakePrometheus answers every query with a vector holding one sample labeled with the given cluster
+func fakePrometheus(cluster string, paths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"cluster":"%s"},"value":[1600000000,"1"]}]}}`, cluster)
+	}))
+}
+
+func TestMultiClusterQuery(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	homePaths, eastPaths := []string{}, []string{}
+	home := fakePrometheus("home", &homePaths)
+	defer home.Close()
+	east := fakePrometheus("east", &eastPaths)
+	defer east.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{
+		URL: home.URL + "/prometheus",
+		Clusters: map[string]config.PrometheusClusterConfig{
+			"east":   {URL: east.URL + "/thanos"},
+			"west":   {URL: down.URL},
+			"shared": {URL: home.URL + "/prometheus"},
+		},
+	})
+	assert.NoError(err)
+
+	// Queries without cluster are sent to every Prometheus, skipping the failing one
+	result, err := client.API().Query(context.Background(), "up", time.Now())
+	assert.NoError(err)
+	vector := result.(model.Vector)
+	assert.Len(vector, 2)
+	assert.Equal(model.LabelValue("home"), vector[0].Metric["cluster"])
+	assert.Equal(model.LabelValue("east"), vector[1].Metric["cluster"])
+	assert.Equal([]string{"/prometheus/api/v1/query"}, homePaths)
+	assert.Equal([]string{"/thanos/api/v1/query"}, eastPaths)
+
+	// Queries for a cluster are sent to the Prometheus of the cluster only
+	result, err = client.API().Query(WithCluster(context.Background(), "east"), "up", time.Now())
+	assert.NoError(err)
+	assert.Len(result.(model.Vector), 1)
+	assert.Len(homePaths, 1)
+	assert.Len(eastPaths, 2)
+}
+
+func TestMultiClusterQuerySkipsSlowCluster(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Clustering.RequestTimeout = 1
+	config.Set(conf)
+
+	homePaths := []string{}
+	home := fakePrometheus("home", &homePaths)
+	defer home.Close()
+	unreachable := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unreachable
+	}))
+	defer slow.Close()
+	defer close(unreachable)
+
+	client, err := NewClientForConfig(config.PrometheusConfig{
+		URL:      home.URL,
+		Clusters: map[string]config.PrometheusClusterConfig{"east": {URL: slow.URL}},
+	})
+	assert.NoError(err)
+
+	result, err := client.API().Query(context.Background(), "up", time.Now())
+	assert.NoError(err)
+	assert.Len(result.(model.Vector), 1)
+}
+
+func TestSingleClusterAPI(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	cfg := config.PrometheusConfig{
+		URL: "http://prometheus:9090",
+		Clusters: map[string]config.PrometheusClusterConfig{
+			"Kubernetes": {URL: "http://thanos:9090"},
+			"east":       {TenantID: "east"},
+			"west":       {URL: "http://prometheus:9090"},
+		},
+	}
+	assert.Empty(remoteClusters(cfg))
+
+	cfg.Clusters["east"] = config.PrometheusClusterConfig{URL: "http://east:9090"}
+	assert.Equal([]string{"east"}, remoteClusters(cfg))
+}
+
+func TestMergeValuesSumsSameLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	vector := mergeValues(
+		model.Vector{{Metric: model.Metric{"app": "a"}, Value: 1}, {Metric: model.Metric{"app": "b"}, Value: 2}},
+		model.Vector{{Metric: model.Metric{"app": "b"}, Value: 3}, {Metric: model.Metric{"app": "c"}, Value: 4}},
+	).(model.Vector)
+	assert.Len(vector, 3)
+	assert.Equal(model.SampleValue(1), vector[0].Value)
+	assert.Equal(model.SampleValue(5), vector[1].Value)
+	assert.Equal(model.SampleValue(4), vector[2].Value)
+
+	matrix := mergeValues(
+		model.Matrix{{Metric: model.Metric{"app": "a"}, Values: []model.SamplePair{{Timestamp: 10, Value: 1}, {Timestamp: 20, Value: 2}}}},
+		model.Matrix{
+			{Metric: model.Metric{"app": "a"}, Values: []model.SamplePair{{Timestamp: 0, Value: 5}, {Timestamp: 20, Value: 3}}},
+			{Metric: model.Metric{"app": "b"}, Values: []model.SamplePair{{Timestamp: 10, Value: 7}}},
+		},
+	).(model.Matrix)
+	assert.Len(matrix, 2)
+	assert.Equal([]model.SamplePair{{Timestamp: 0, Value: 5}, {Timestamp: 10, Value: 1}, {Timestamp: 20, Value: 5}}, matrix[0].Values)
+	assert.Equal([]model.SamplePair{{Timestamp: 10, Value: 7}}, matrix[1].Values)
+}
+
+func TestHistogramQuantileOfMergedBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	bucket := func(le string, value model.SampleValue) *model.SampleStream {
+		return &model.SampleStream{
+			Metric: model.Metric{"app": "a", model.BucketLabel: model.LabelValue(le)},
+			Values: []model.SamplePair{{Timestamp: 10, Value: value}},
+		}
+	}
+	// 10 observations up to 100ms in a cluster, 10 up to 200ms in the other one
+	home := model.Matrix{bucket("100", 10), bucket("200", 10), bucket("+Inf", 10)}
+	east := model.Matrix{bucket("100", 0), bucket("200", 10), bucket("+Inf", 10)}
+	buckets := mergeValues(home, east).(model.Matrix)
+
+	median := histogramQuantile(0.5, buckets)
+	assert.Len(median, 1)
+	assert.Equal(model.Metric{"app": "a"}, median[0].Metric)
+	assert.Equal([]model.SamplePair{{Timestamp: 10, Value: 100}}, median[0].Values)
+
+	p75 := histogramQuantile(0.75, buckets)
+	assert.Equal(model.SampleValue(150), p75[0].Values[0].Value)
+
+	avg := divideMatrices(
+		model.Matrix{{Metric: model.Metric{"app": "a"}, Values: []model.SamplePair{{Timestamp: 10, Value: 3}}}},
+		model.Matrix{{Metric: model.Metric{"app": "a"}, Values: []model.SamplePair{{Timestamp: 10, Value: 2}}}},
+	)
+	assert.Equal(model.SampleValue(1.5), avg[0].Values[0].Value)
+}
@@ -0,0 +1,76 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// Steps picked for the range queries over long ranges, so consecutive queries share their timestamps
+var rangeSteps = []time.Duration{
+	15 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// Resolutions of the downsampled data of Thanos, from the coarsest
+var thanosResolutions = []time.Duration{time.Hour, 5 * time.Minute}
+
+type resolutionContextKey struct{}
+
+// withResolution returns a context for the queries of the data downsampled to the given resolution
+func withResolution(ctx context.Context, resolution time.Duration) context.Context {
+	return context.WithValue(ctx, resolutionContextKey{}, resolution)
+}
+
+// resolutionFromContext returns the resolution of the downsampled data of a query, if any
+func resolutionFromContext(ctx context.Context) (time.Duration, bool) {
+	resolution, ok := ctx.Value(resolutionContextKey{}).(time.Duration)
+	return resolution, ok
+}
+
+// autoStep returns the bounds of a range query with the requested step, or a larger one when the query would return
+// more than maxDataPoints points per series. The start is aligned to the step.
+func autoStep(bounds prom_v1.Range, maxDataPoints int) prom_v1.Range {
+	if maxDataPoints <= 0 || bounds.Step <= 0 {
+		return bounds
+	}
+	duration := bounds.End.Sub(bounds.Start)
+	if duration/bounds.Step <= time.Duration(maxDataPoints) {
+		return bounds
+	}
+	minStep := duration / time.Duration(maxDataPoints)
+	step := rangeSteps[len(rangeSteps)-1]
+	for _, s := range rangeSteps {
+		if s >= minStep {
+			step = s
+			break
+		}
+	}
+	// Ranges over several months get steps over a day
+	for duration/step > time.Duration(maxDataPoints) {
+		step *= 2
+	}
+	stepInSecs := int64(step.Seconds())
+	bounds.Start = time.Unix((bounds.Start.Unix()/stepInSecs)*stepInSecs, 0)
+	bounds.Step = step
+	return bounds
+}
+
+// downsamplingResolution returns the coarsest resolution of the Thanos downsampled data suited for a range query: at
+// least five times finer than the step, as Thanos does for its "auto" resolution, and leaving two samples in each
+// rate interval. Zero means raw data.
+func downsamplingResolution(step time.Duration, rateInterval string) time.Duration {
+	interval, err := model.ParseDuration(rateInterval)
+	if err != nil {
+		return 0
+	}
+	for _, resolution := range thanosResolutions {
+		if 5*resolution <= step && 2*resolution <= time.Duration(interval) {
+			return resolution
+		}
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestAutoStep(t *testing.T) {
+	assert := assert.New(t)
+	end := time.Unix(1600000000, 0)
+
+	// Short ranges keep the requested step
+	bounds := prom_v1.Range{Start: end.Add(-30 * time.Minute), End: end, Step: 15 * time.Second}
+	assert.Equal(bounds, autoStep(bounds, 1000))
+
+	// 30 days with 1000 points: 43m12s at least, so 1h
+	bounds = autoStep(prom_v1.Range{Start: end.Add(-30 * 24 * time.Hour), End: end, Step: time.Second}, 1000)
+	assert.Equal(time.Hour, bounds.Step)
+	assert.Equal(int64(0), bounds.Start.Unix()%3600)
+	assert.True(bounds.End.Sub(bounds.Start)/bounds.Step <= 1000)
+
+	// Beyond the largest step
+	bounds = autoStep(prom_v1.Range{Start: end.Add(-400 * 24 * time.Hour), End: end, Step: time.Minute}, 100)
+	assert.Equal(96*time.Hour, bounds.Step)
+
+	// No limit
+	bounds = prom_v1.Range{Start: end.Add(-30 * 24 * time.Hour), End: end, Step: time.Second}
+	assert.Equal(bounds, autoStep(bounds, 0))
+}
+
+func TestDownsamplingResolution(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(time.Duration(0), downsamplingResolution(15*time.Second, "1m"))
+	assert.Equal(time.Duration(0), downsamplingResolution(time.Hour, "1m"))
+	assert.Equal(5*time.Minute, downsamplingResolution(time.Hour, "10m"))
+	assert.Equal(time.Hour, downsamplingResolution(6*time.Hour, "2h"))
+	assert.Equal(5*time.Minute, downsamplingResolution(6*time.Hour, "1h"))
+}
+
+func TestFetchRangeDownsampled(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.ThanosDownsampling = true
+	conf.ExternalServices.Prometheus.QueryCacheEnabled = false
+	config.Set(conf)
+
+	var step, resolution string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		step, resolution = r.Form.Get("step"), r.Form.Get("max_source_resolution")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	q := RangeQuery{}
+	q.FillDefaults()
+	q.Start = q.End.Add(-30 * 24 * time.Hour)
+	q.RateInterval = "30m"
+	metric := client.FetchRateRange("istio_requests_total", []string{"{}"}, "", &q)
+	assert.NoError(metric.Err)
+	assert.Equal("3600.000", step)
+	assert.Equal("5m", resolution)
+
+	// Raw data for short ranges
+	q.FillDefaults()
+	metric = client.FetchRateRange("istio_requests_total", []string{"{}"}, "", &q)
+	assert.NoError(metric.Err)
+	assert.Equal("15.000", step)
+	assert.Equal("", resolution)
+}
@@ -0,0 +1,65 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+const epQueryExemplars = "/api/v1/query_exemplars"
+
+// Exemplar is a sample of a metric recorded with the labels identifying its trace
+type Exemplar struct {
+	Labels    model.LabelSet    `json:"labels"`
+	Value     model.SampleValue `json:"value"`
+	Timestamp model.Time        `json:"timestamp"`
+}
+
+// ExemplarSeries holds the exemplars of a series
+type ExemplarSeries struct {
+	SeriesLabels model.LabelSet `json:"seriesLabels"`
+	Exemplars    []Exemplar     `json:"exemplars"`
+}
+
+type exemplarsResponse struct {
+	Status string           `json:"status"`
+	Data   []ExemplarSeries `json:"data"`
+	Error  string           `json:"error"`
+}
+
+// FetchExemplars fetches the exemplars of a metric in given range, for any of the given label selectors
+func (in *Client) FetchExemplars(metricName string, labels []string, q *RangeQuery) ([]ExemplarSeries, error) {
+	selectors := make([]string, 0, len(labels))
+	for _, labelsInstance := range labels {
+		selectors = append(selectors, metricName+labelsInstance)
+	}
+
+	args := url.Values{}
+	args.Set("query", strings.Join(selectors, " or "))
+	args.Set("start", q.Start.Format("2006-01-02T15:04:05.999999999Z07:00"))
+	args.Set("end", q.End.Format("2006-01-02T15:04:05.999999999Z07:00"))
+	u := in.p8s.URL(epQueryExemplars, nil)
+	u.RawQuery = args.Encode()
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, apiErr := in.p8s.Do(context.Background(), req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	var result exemplarsResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unexpected exemplars response, status %d: %v", resp.StatusCode, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("error fetching exemplars, status %d: %s", resp.StatusCode, result.Error)
+	}
+	return result.Data, nil
+}
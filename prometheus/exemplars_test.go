@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestFetchExemplars(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/api/v1/query_exemplars", r.URL.Path)
+		query = r.URL.Query().Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":[{"seriesLabels":{"__name__":"istio_requests_total","response_code":"200"},"exemplars":[{"labels":{"trace_id":"abc"},"value":"6","timestamp":1600096945.479}]}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	q := RangeQuery{}
+	q.End = time.Unix(1600097000, 0)
+	q.Start = q.End.Add(-time.Hour)
+	series, err := client.FetchExemplars("istio_requests_total", []string{`{reporter="source"}`, `{response_code=~"5.*"}`}, &q)
+	assert.NoError(err)
+	assert.Equal(`istio_requests_total{reporter="source"} or istio_requests_total{response_code=~"5.*"}`, query)
+	assert.Len(series, 1)
+	assert.Equal(model.LabelValue("200"), series[0].SeriesLabels["response_code"])
+	assert.Len(series[0].Exemplars, 1)
+	assert.Equal(model.LabelValue("abc"), series[0].Exemplars[0].Labels["trace_id"])
+	assert.Equal(model.SampleValue(6), series[0].Exemplars[0].Value)
+	assert.Equal(model.Time(1600096945479), series[0].Exemplars[0].Timestamp)
+}
+
+func TestFetchExemplarsUnsupported(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	q := RangeQuery{}
+	q.End = time.Now()
+	q.Start = q.End.Add(-time.Hour)
+	_, err = client.FetchExemplars("istio_requests_total", []string{`{}`}, &q)
+	assert.Error(err)
+}
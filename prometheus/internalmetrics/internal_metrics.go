@@ -2,7 +2,9 @@
 package internalmetrics
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	// Because this package is used all throughout the codebase, be VERY careful adding new
@@ -17,10 +19,16 @@ const (
 	labelWithServiceNodes = "with_service_nodes"
 	labelAppender         = "appender"
 	labelRoute            = "route"
+	labelStatusClass      = "status_class"
 	labelQueryGroup       = "query_group"
 	labelPackage          = "package"
 	labelType             = "type"
 	labelFunction         = "function"
+	labelCache            = "cache"
+	labelResult           = "result"
+	labelCluster          = "cluster"
+	labelNamespace        = "namespace"
+	labelKind             = "kind"
 )
 
 // MetricsType defines all of Kiali's own internal metrics.
@@ -34,6 +42,10 @@ type MetricsType struct {
 	GoFunctionProcessingTime *prometheus.HistogramVec
 	GoFunctionFailures       *prometheus.CounterVec
 	KubernetesClients        *prometheus.GaugeVec
+	CacheRequests            *prometheus.CounterVec
+	CacheInformerSynced      *prometheus.GaugeVec
+	CacheInformerObjects     *prometheus.GaugeVec
+	CacheInformerBytes       *prometheus.GaugeVec
 }
 
 // Metrics contains all of Kiali's own internal metrics.
@@ -71,9 +83,9 @@ var Metrics = MetricsType{
 	APIProcessingTime: prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name: "kiali_api_processing_duration_seconds",
-			Help: "The time required to execute a particular REST API route request.",
+			Help: "The time required to execute a particular REST API route request, by status class of the response (i.e. 2xx or 5xx).",
 		},
-		[]string{labelRoute},
+		[]string{labelRoute, labelStatusClass},
 	),
 	PrometheusProcessingTime: prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -103,6 +115,34 @@ var Metrics = MetricsType{
 		},
 		[]string{},
 	),
+	CacheRequests: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kiali_cache_requests_total",
+			Help: "Counts the requests to a Kiali cache, by result (hit or miss).",
+		},
+		[]string{labelCache, labelResult},
+	),
+	CacheInformerSynced: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_informer_synced",
+			Help: "Whether the informer of the Kiali cache for a kind of objects in a namespace is synced (1) or not (0).",
+		},
+		[]string{labelCluster, labelNamespace, labelKind},
+	),
+	CacheInformerObjects: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_informer_objects",
+			Help: "The number of objects held by the informer of the Kiali cache for a kind of objects in a namespace.",
+		},
+		[]string{labelCluster, labelNamespace, labelKind},
+	),
+	CacheInformerBytes: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_informer_estimated_bytes",
+			Help: "An estimation of the memory used by the objects held by the informer of the Kiali cache for a kind of objects in a namespace.",
+		},
+		[]string{labelCluster, labelNamespace, labelKind},
+	),
 }
 
 // SuccessOrFailureMetricType let's you capture metrics for both successes and failures,
@@ -160,6 +200,10 @@ func RegisterInternalMetrics() {
 		Metrics.GoFunctionProcessingTime,
 		Metrics.GoFunctionFailures,
 		Metrics.KubernetesClients,
+		Metrics.CacheRequests,
+		Metrics.CacheInformerSynced,
+		Metrics.CacheInformerObjects,
+		Metrics.CacheInformerBytes,
 	)
 }
 
@@ -220,17 +264,21 @@ func GetGraphMarshalTimePrometheusTimer(graphKind string, graphType string, with
 	return timer
 }
 
-// GetAPIProcessingTimePrometheusTimer returns a timer that can be used to store
-// a value for the API processing time metric. The timer is ticking immediately
-// when this function returns.
+// ObserveAPIProcessingTime stores the time taken by a REST API route request since it started,
+// with the status class of its response. A status code of 0 means that the response was not
+// written, and is the default 200 status.
 // Typical usage is as follows:
-//    promtimer := GetAPIProcessingTimePrometheusTimer(...)
-//    defer promtimer.ObserveDuration()
-func GetAPIProcessingTimePrometheusTimer(apiRouteName string) *prometheus.Timer {
-	timer := prometheus.NewTimer(Metrics.APIProcessingTime.With(prometheus.Labels{
-		labelRoute: apiRouteName,
-	}))
-	return timer
+//    start := time.Now()
+//    ... serve the request ...
+//    ObserveAPIProcessingTime(apiRouteName, statusCode, start)
+func ObserveAPIProcessingTime(apiRouteName string, statusCode int, start time.Time) {
+	if statusCode == 0 {
+		statusCode = 200
+	}
+	Metrics.APIProcessingTime.With(prometheus.Labels{
+		labelRoute:       apiRouteName,
+		labelStatusClass: fmt.Sprintf("%dxx", statusCode/100),
+	}).Observe(time.Since(start).Seconds())
 }
 
 // GetPrometheusProcessingTimePrometheusTimer returns a timer that can be used to store
@@ -281,3 +329,38 @@ func GetGoFunctionMetric(goPkg string, goType string, goFunc string) SuccessOrFa
 func SetKubernetesClients(clientCount int) {
 	Metrics.KubernetesClients.With(prometheus.Labels{}).Set(float64(clientCount))
 }
+
+// IncCacheRequests counts a request to a Kiali cache
+func IncCacheRequests(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	Metrics.CacheRequests.With(prometheus.Labels{
+		labelCache:  cache,
+		labelResult: result,
+	}).Inc()
+}
+
+// ResetCacheInformerStatus clears the status of the informers of the Kiali cache, so informers that were stopped are not reported
+func ResetCacheInformerStatus() {
+	Metrics.CacheInformerSynced.Reset()
+	Metrics.CacheInformerObjects.Reset()
+	Metrics.CacheInformerBytes.Reset()
+}
+
+// SetCacheInformerStatus sets the sync status, object count and estimated memory of an informer of the Kiali cache
+func SetCacheInformerStatus(cluster string, namespace string, kind string, synced bool, objects int, estimatedBytes int64) {
+	labels := prometheus.Labels{
+		labelCluster:   cluster,
+		labelNamespace: namespace,
+		labelKind:      kind,
+	}
+	syncedValue := 0.0
+	if synced {
+		syncedValue = 1
+	}
+	Metrics.CacheInformerSynced.With(labels).Set(syncedValue)
+	Metrics.CacheInformerObjects.With(labels).Set(float64(objects))
+	Metrics.CacheInformerBytes.With(labels).Set(float64(estimatedBytes))
+}
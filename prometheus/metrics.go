@@ -3,11 +3,14 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
@@ -28,21 +31,179 @@ func fetchRateRange(api prom_v1.API, metricName string, labels []string, groupin
 		query = fmt.Sprintf("(%s)", query)
 	}
 	query = roundSignificant(query, 0.001)
-	return fetchRange(api, query, q.Range)
+	return fetchRange(api, query, q)
+}
+
+func fetchRate(api prom_v1.API, series, window string, queryTime time.Time) (float64, bool, error) {
+	// Example: sum(rate(a{foo=bar}[1h]))
+	query := fmt.Sprintf("sum(rate(%s[%s]))", series, window)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRate")
+	result, err := api.Query(context.Background(), query, queryTime)
+	if err != nil {
+		return 0, false, err
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return 0, false, fmt.Errorf("invalid query, vector expected: %s", query)
+	}
+	if len(vector) == 0 || math.IsNaN(float64(vector[0].Value)) {
+		return 0, false, nil
+	}
+	return float64(vector[0].Value), true, nil
+}
+
+func getContainerResourceUsage(api prom_v1.API, namespace, container, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	labels := fmt.Sprintf(`{namespace="%s",container="%s"}`, namespace, container)
+	// The rate of the CPU usage is averaged over 5m steps, so that the peaks are not smoothed out by a long window
+	// Example: max_over_time(sum by (pod) (rate(container_cpu_usage_seconds_total{namespace="ns",container="c"}[5m]))[1d:5m])
+	cpuQuery := fmt.Sprintf("max_over_time(sum by (pod) (rate(container_cpu_usage_seconds_total%s[5m]))[%s:5m])", labels, window)
+	memoryQuery := fmt.Sprintf("max_over_time(sum by (pod) (container_memory_working_set_bytes%s)[%s:5m])", labels, window)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetContainerResourceUsage")
+	vectors := make([]model.Vector, 2)
+	for i, query := range []string{cpuQuery, memoryQuery} {
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, model.Vector{}, err
+		}
+		vector, ok := result.(model.Vector)
+		if !ok {
+			return model.Vector{}, model.Vector{}, fmt.Errorf("invalid query, vector expected: %s", query)
+		}
+		vectors[i] = vector
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return vectors[0], vectors[1], nil
+}
+
+func getEgressClusterTraffic(api prom_v1.API, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	// Example: sum by (source_workload_namespace,source_workload,destination_service,destination_service_name) (rate(istio_requests_total{reporter="source",destination_service_name=~"PassthroughCluster|BlackHoleCluster"}[1d])) > 0
+	labels := `{reporter="source",destination_service_name=~"PassthroughCluster|BlackHoleCluster"}`
+	grouping := "source_workload_namespace,source_workload,destination_service,destination_service_name"
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetEgressClusterTraffic")
+	vectors := make([]model.Vector, 2)
+	for i, metric := range []string{"istio_requests_total", "istio_tcp_connections_opened_total"} {
+		query := fmt.Sprintf("sum by (%s) (rate(%s%s[%s])) > 0", grouping, metric, labels, window)
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, model.Vector{}, err
+		}
+		vector, ok := result.(model.Vector)
+		if !ok {
+			return model.Vector{}, model.Vector{}, fmt.Errorf("invalid query, vector expected: %s", query)
+		}
+		vectors[i] = vector
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return vectors[0], vectors[1], nil
+}
+
+func getExternalTraffic(api prom_v1.API, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	// Example: sum by (source_workload_namespace,source_workload,destination_service,destination_service_name,request_protocol) (rate(istio_requests_total{reporter="source",destination_workload="unknown"}[1d])) > 0
+	labels := `{reporter="source",destination_workload="unknown"}`
+	grouping := "source_workload_namespace,source_workload,destination_service,destination_service_name,request_protocol"
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetExternalTraffic")
+	vectors := make([]model.Vector, 2)
+	for i, metric := range []string{"istio_requests_total", "istio_tcp_connections_opened_total"} {
+		query := fmt.Sprintf("sum by (%s) (rate(%s%s[%s])) > 0", grouping, metric, labels, window)
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, model.Vector{}, err
+		}
+		vector, ok := result.(model.Vector)
+		if !ok {
+			return model.Vector{}, model.Vector{}, fmt.Errorf("invalid query, vector expected: %s", query)
+		}
+		vectors[i] = vector
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return vectors[0], vectors[1], nil
+}
+
+func getClusterTraffic(api prom_v1.API, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	// Example: sum by (source_cluster,destination_cluster,source_workload_namespace) (rate(istio_requests_total{reporter="source"}[1h])) > 0
+	grouping := "source_cluster,destination_cluster,source_workload_namespace"
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetClusterTraffic")
+	vectors := make([]model.Vector, 2)
+	for i, labels := range []string{`{reporter="source"}`, `{reporter="source",response_code=~"^0$|^[4-5]\\d\\d$"}`} {
+		query := fmt.Sprintf("sum by (%s) (rate(istio_requests_total%s[%s])) > 0", grouping, labels, window)
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, model.Vector{}, err
+		}
+		vector, ok := result.(model.Vector)
+		if !ok {
+			return model.Vector{}, model.Vector{}, fmt.Errorf("invalid query, vector expected: %s", query)
+		}
+		vectors[i] = vector
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return vectors[0], vectors[1], nil
+}
+
+func fetchRateRatio(api prom_v1.API, parts []string, total, window string, queryTime time.Time) (float64, bool, error) {
+	// The numerator and the denominator are queried separately, so that the rates of all the clusters are summed
+	// before the ratio is computed.
+	// Example: (sum(rate(a{foo=bar}[1h])) or vector(0)) + (sum(rate(b{foo=bar}[1h])) or vector(0))
+	//	divided by sum(rate(c{foo=bar}[1h]))
+	partQueries := make([]string, len(parts))
+	for i, part := range parts {
+		partQueries[i] = fmt.Sprintf("(sum(rate(%s[%s])) or vector(0))", part, window)
+	}
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRateRatio")
+	numerator, err := fetchVector(api, strings.Join(partQueries, " + "), queryTime)
+	if err != nil {
+		return 0, false, err
+	}
+	denominator, err := fetchVector(api, fmt.Sprintf("sum(rate(%s[%s]))", total, window), queryTime)
+	if err != nil {
+		return 0, false, err
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	if len(denominator) == 0 {
+		return 0, false, nil
+	}
+	ratio := 0.0
+	if len(numerator) > 0 {
+		ratio = float64(numerator[0].Value)
+	}
+	ratio /= float64(denominator[0].Value)
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return 0, false, nil
+	}
+	return ratio, true, nil
+}
+
+func fetchVector(api prom_v1.API, query string, queryTime time.Time) (model.Vector, error) {
+	result, err := api.Query(context.Background(), query, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("invalid query, vector expected: %s", query)
+	}
+	return vector, nil
 }
 
 func fetchHistogramRange(api prom_v1.API, metricName, labels, grouping string, q *RangeQuery) Histogram {
+	if isMultiCluster(api) {
+		return fetchMultiClusterHistogramRange(api, metricName, labels, grouping, q)
+	}
 	// Note: the p8s queries are not run in parallel here, but they are at the caller's place.
 	//	This is because we may not want to create too many threads in the lowest layer
 	queries := buildHistogramQueries(metricName, labels, grouping, q.RateInterval, q.Avg, q.Quantiles)
 	histogram := make(Histogram, len(queries))
 	for k, query := range queries {
-		histogram[k] = fetchRange(api, query, q.Range)
+		histogram[k] = fetchRange(api, query, q)
 	}
 	return histogram
 }
 
 func fetchHistogramValues(api prom_v1.API, metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
+	if isMultiCluster(api) {
+		return fetchMultiClusterHistogramValues(api, metricName, labels, grouping, rateInterval, avg, quantiles, queryTime)
+	}
 	// Note: the p8s queries are not run in parallel here, but they are at the caller's place.
 	//	This is because we may not want to create too many threads in the lowest layer
 	queries := buildHistogramQueries(metricName, labels, grouping, rateInterval, avg, quantiles)
@@ -60,26 +221,18 @@ func fetchHistogramValues(api prom_v1.API, metricName, labels, grouping, rateInt
 func buildHistogramQueries(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string) map[string]string {
 	queries := make(map[string]string)
 	if avg {
-		groupingAvg := ""
-		if grouping != "" {
-			groupingAvg = fmt.Sprintf(" by (%s)", grouping)
-		}
 		// Average
 		// Example: sum(rate(my_histogram_sum{foo=bar}[5m])) by (baz) / sum(rate(my_histogram_count{foo=bar}[5m])) by (baz)
-		query := fmt.Sprintf("sum(rate(%s_sum%s[%s]))%s / sum(rate(%s_count%s[%s]))%s",
-			metricName, labels, rateInterval, groupingAvg, metricName, labels, rateInterval, groupingAvg)
+		sumQuery, countQuery := buildHistogramAvgQueries(metricName, labels, grouping, rateInterval)
+		query := fmt.Sprintf("%s / %s", sumQuery, countQuery)
 		query = roundSignificant(query, 0.001)
 		queries["avg"] = query
 	}
 
-	groupingQuantile := ""
-	if grouping != "" {
-		groupingQuantile = fmt.Sprintf(",%s", grouping)
-	}
+	bucketsQuery := buildHistogramBucketsQuery(metricName, labels, grouping, rateInterval)
 	for _, quantile := range quantiles {
 		// Example: round(histogram_quantile(0.5, sum(rate(my_histogram_bucket{foo=bar}[5m])) by (le,baz)), 0.001)
-		query := fmt.Sprintf("histogram_quantile(%s, sum(rate(%s_bucket%s[%s])) by (le%s))",
-			quantile, metricName, labels, rateInterval, groupingQuantile)
+		query := fmt.Sprintf("histogram_quantile(%s, %s)", quantile, bucketsQuery)
 		query = roundSignificant(query, 0.001)
 		queries[quantile] = query
 	}
@@ -87,8 +240,36 @@ func buildHistogramQueries(metricName, labels, grouping, rateInterval string, av
 	return queries
 }
 
-func fetchRange(api prom_v1.API, query string, bounds prom_v1.Range) Metric {
-	result, err := api.QueryRange(context.Background(), query, bounds)
+// buildHistogramAvgQueries returns the queries of the sum and of the count of the observations, whose ratio is the average
+func buildHistogramAvgQueries(metricName, labels, grouping, rateInterval string) (string, string) {
+	groupingAvg := ""
+	if grouping != "" {
+		groupingAvg = fmt.Sprintf(" by (%s)", grouping)
+	}
+	sumQuery := fmt.Sprintf("sum(rate(%s_sum%s[%s]))%s", metricName, labels, rateInterval, groupingAvg)
+	countQuery := fmt.Sprintf("sum(rate(%s_count%s[%s]))%s", metricName, labels, rateInterval, groupingAvg)
+	return sumQuery, countQuery
+}
+
+// buildHistogramBucketsQuery returns the query of the buckets the quantiles are computed from
+func buildHistogramBucketsQuery(metricName, labels, grouping, rateInterval string) string {
+	groupingQuantile := ""
+	if grouping != "" {
+		groupingQuantile = fmt.Sprintf(",%s", grouping)
+	}
+	return fmt.Sprintf("sum(rate(%s_bucket%s[%s])) by (le%s)", metricName, labels, rateInterval, groupingQuantile)
+}
+
+func fetchRange(api prom_v1.API, query string, q *RangeQuery) Metric {
+	promConfig := config.Get().ExternalServices.Prometheus
+	bounds := autoStep(q.Range, promConfig.MaxDataPoints)
+	ctx := context.Background()
+	if promConfig.ThanosDownsampling {
+		if resolution := downsamplingResolution(bounds.Step, q.RateInterval); resolution > 0 {
+			ctx = withResolution(ctx, resolution)
+		}
+	}
+	result, err := api.QueryRange(ctx, query, bounds)
 	if err != nil {
 		return Metric{Err: err}
 	}
@@ -0,0 +1,184 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestFetchRateRatio(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queries := []string{}
+	results := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		query := r.Form.Get("query")
+		queries = append(queries, query)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, results[query])
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	numerator := `(sum(rate(a{x="1"}[1h])) or vector(0)) + (sum(rate(b{x="1"}[1h])) or vector(0))`
+	denominator := `sum(rate(c{x="1"}[1h]))`
+	results[numerator] = `[{"metric":{},"value":[1600000000,"1"]}]`
+	results[denominator] = `[{"metric":{},"value":[1600000000,"4"]}]`
+	ratio, hasData, err := client.FetchRateRatio([]string{`a{x="1"}`, `b{x="1"}`}, `c{x="1"}`, "1h", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.True(hasData)
+	assert.Equal(0.25, ratio)
+	assert.Equal([]string{numerator, denominator}, queries)
+
+	// No requests
+	results[`(sum(rate(a[5m])) or vector(0))`] = `[{"metric":{},"value":[1600000000,"0"]}]`
+	results[`sum(rate(c[5m]))`] = `[]`
+	_, hasData, err = client.FetchRateRatio([]string{`a`}, `c`, "5m", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.False(hasData)
+}
+
+func TestFetchRate(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	var query string
+	result := `[{"metric":{},"value":[1600000000,"12.5"]}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		query = r.Form.Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	rate, hasData, err := client.FetchRate(`a{x="1"}`, "10m", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.True(hasData)
+	assert.Equal(12.5, rate)
+	assert.Equal(`sum(rate(a{x="1"}[10m]))`, query)
+
+	// No series
+	result = `[]`
+	_, hasData, err = client.FetchRate(`a`, "10m", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.False(hasData)
+}
+
+func TestGetContainerResourceUsage(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		queries = append(queries, r.Form.Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"details-v1-1"},"value":[1600000000,"0.25"]}]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	cpu, memory, err := client.GetContainerResourceUsage("bookinfo", "istio-proxy", "1d", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.Len(cpu, 1)
+	assert.Len(memory, 1)
+	assert.Equal("details-v1-1", string(cpu[0].Metric["pod"]))
+	assert.Equal([]string{
+		`max_over_time(sum by (pod) (rate(container_cpu_usage_seconds_total{namespace="bookinfo",container="istio-proxy"}[5m]))[1d:5m])`,
+		`max_over_time(sum by (pod) (container_memory_working_set_bytes{namespace="bookinfo",container="istio-proxy"})[1d:5m])`,
+	}, queries)
+}
+
+func TestGetEgressClusterTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		queries = append(queries, r.Form.Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"destination_service":"api.github.com"},"value":[1600000000,"2"]}]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	requests, connections, err := client.GetEgressClusterTraffic("1d", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.Len(requests, 1)
+	assert.Len(connections, 1)
+	assert.Equal([]string{
+		`sum by (source_workload_namespace,source_workload,destination_service,destination_service_name) (rate(istio_requests_total{reporter="source",destination_service_name=~"PassthroughCluster|BlackHoleCluster"}[1d])) > 0`,
+		`sum by (source_workload_namespace,source_workload,destination_service,destination_service_name) (rate(istio_tcp_connections_opened_total{reporter="source",destination_service_name=~"PassthroughCluster|BlackHoleCluster"}[1d])) > 0`,
+	}, queries)
+}
+
+func TestGetExternalTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		queries = append(queries, r.Form.Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"destination_service":"api.github.com"},"value":[1600000000,"2"]}]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	requests, connections, err := client.GetExternalTraffic("1d", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.Len(requests, 1)
+	assert.Len(connections, 1)
+	assert.Equal([]string{
+		`sum by (source_workload_namespace,source_workload,destination_service,destination_service_name,request_protocol) (rate(istio_requests_total{reporter="source",destination_workload="unknown"}[1d])) > 0`,
+		`sum by (source_workload_namespace,source_workload,destination_service,destination_service_name,request_protocol) (rate(istio_tcp_connections_opened_total{reporter="source",destination_workload="unknown"}[1d])) > 0`,
+	}, queries)
+}
+
+func TestGetClusterTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		queries = append(queries, r.Form.Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"source_cluster":"east","destination_cluster":"west"},"value":[1600000000,"2"]}]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+
+	requests, failures, err := client.GetClusterTraffic("1h", time.Unix(1600000000, 0))
+	assert.NoError(err)
+	assert.Len(requests, 1)
+	assert.Len(failures, 1)
+	assert.Equal([]string{
+		`sum by (source_cluster,destination_cluster,source_workload_namespace) (rate(istio_requests_total{reporter="source"}[1h])) > 0`,
+		`sum by (source_cluster,destination_cluster,source_workload_namespace) (rate(istio_requests_total{reporter="source",response_code=~"^0$|^[4-5]\\d\\d$"}[1h])) > 0`,
+	}, queries)
+}
@@ -291,6 +291,41 @@ func (o *PromClientMock) FetchRateRange(metricName string, labels []string, grou
 	return args.Get(0).(prometheus.Metric)
 }
 
+func (o *PromClientMock) FetchExemplars(metricName string, labels []string, q *prometheus.RangeQuery) ([]prometheus.ExemplarSeries, error) {
+	args := o.Called(metricName, labels, q)
+	return args.Get(0).([]prometheus.ExemplarSeries), args.Error(1)
+}
+
+func (o *PromClientMock) FetchRate(series, window string, queryTime time.Time) (float64, bool, error) {
+	args := o.Called(series, window, queryTime)
+	return args.Get(0).(float64), args.Bool(1), args.Error(2)
+}
+
+func (o *PromClientMock) GetContainerResourceUsage(namespace, container, window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	args := o.Called(namespace, container, window, queryTime)
+	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
+}
+
+func (o *PromClientMock) GetClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	args := o.Called(window, queryTime)
+	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
+}
+
+func (o *PromClientMock) GetEgressClusterTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	args := o.Called(window, queryTime)
+	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
+}
+
+func (o *PromClientMock) GetExternalTraffic(window string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	args := o.Called(window, queryTime)
+	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
+}
+
+func (o *PromClientMock) FetchRateRatio(parts []string, total, window string, queryTime time.Time) (float64, bool, error) {
+	args := o.Called(parts, total, window, queryTime)
+	return args.Get(0).(float64), args.Bool(1), args.Error(2)
+}
+
 func (o *PromClientMock) FetchHistogramRange(metricName, labels, grouping string, q *prometheus.RangeQuery) prometheus.Histogram {
 	args := o.Called(metricName, labels, grouping, q)
 	return args.Get(0).(prometheus.Histogram)
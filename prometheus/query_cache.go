@@ -0,0 +1,168 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// QueryCacheName is the name of the query cache in the internal metrics
+const QueryCacheName = "prometheus"
+
+type (
+	// queryCache holds the results of the queries to Prometheus for a short time, so the same query issued by
+	// several users, i.e. watching the graph of the same namespaces, is sent once to Prometheus
+	queryCache struct {
+		duration time.Duration
+		// Timeout of the queries, which are not cancelled with the caller who sent them
+		timeout   time.Duration
+		lock      sync.RWMutex
+		entries   map[string]queryCacheEntry
+		lastSweep time.Time
+		inflight  singleflight.Group
+	}
+
+	queryCacheEntry struct {
+		value   model.Value
+		expires time.Time
+	}
+
+	// cachingAPI serves the queries from the query cache
+	cachingAPI struct {
+		prom_v1.API
+		cache   *queryCache
+		address string
+	}
+
+	// detachedContext keeps the values of a context, i.e. the cluster of the query, without its cancellation
+	detachedContext struct {
+		context.Context
+	}
+)
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+var queryCacheOnce sync.Once
+var sharedQueryCache *queryCache
+
+func newQueryCache(duration, timeout time.Duration) *queryCache {
+	return &queryCache{
+		duration:  duration,
+		timeout:   timeout,
+		entries:   make(map[string]queryCacheEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// newCachingAPI returns the API serving the queries from the query cache shared by all the clients, or the given
+// API when the query cache is disabled
+func newCachingAPI(promAPI prom_v1.API, cfg config.PrometheusConfig) prom_v1.API {
+	queryCacheOnce.Do(func() {
+		conf := config.Get()
+		promConfig := conf.ExternalServices.Prometheus
+		if promConfig.QueryCacheEnabled && promConfig.QueryCacheDuration > 0 {
+			// No response waits for a query longer than the write timeout of the server
+			sharedQueryCache = newQueryCache(time.Duration(promConfig.QueryCacheDuration)*time.Second, time.Duration(conf.Server.WriteTimeout)*time.Second)
+		}
+	})
+	if sharedQueryCache == nil {
+		return promAPI
+	}
+	return cachingAPI{API: promAPI, cache: sharedQueryCache, address: cfg.URL}
+}
+
+// normalizeQuery removes the whitespace not significant in a query, so equivalent queries share the cache
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// get returns the result of a query from the cache, or runs the query once for all the concurrent callers. The shared
+// query runs with its own timeout, so the caller who started it doesn't cancel it for the others when it leaves.
+func (c *queryCache) get(ctx context.Context, key string, query func(context.Context) (model.Value, api.Error)) (model.Value, api.Error) {
+	c.lock.RLock()
+	entry, found := c.entries[key]
+	c.lock.RUnlock()
+	if found && time.Now().Before(entry.expires) {
+		internalmetrics.IncCacheRequests(QueryCacheName, true)
+		return entry.value, nil
+	}
+	internalmetrics.IncCacheRequests(QueryCacheName, false)
+
+	result := c.inflight.DoChan(key, func() (interface{}, error) {
+		queryCtx := context.Context(detachedContext{ctx})
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			queryCtx, cancel = context.WithTimeout(queryCtx, c.timeout)
+			defer cancel()
+		}
+		value, err := query(queryCtx)
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, value)
+		return value, nil
+	})
+	select {
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err.(api.Error)
+		}
+		return r.Val.(model.Value), nil
+	case <-ctx.Done():
+		return nil, api.NewErrorAPI(ctx.Err(), nil)
+	}
+}
+
+func (c *queryCache) set(key string, value model.Value) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	c.entries[key] = queryCacheEntry{value: value, expires: now.Add(c.duration)}
+	// Expired entries are removed once per cache duration
+	if now.Sub(c.lastSweep) > c.duration {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// key identifies a query to a Prometheus. Times are aligned to the cache duration, so the queries issued within
+// the cache duration share the result.
+func (a cachingAPI) key(ctx context.Context, kind, query string, times ...time.Time) string {
+	key := fmt.Sprintf("%s|%s|%s|%s", a.address, clusterFromContext(ctx), kind, normalizeQuery(query))
+	for _, t := range times {
+		key += fmt.Sprintf("|%d", t.Truncate(a.cache.duration).Unix())
+	}
+	return key
+}
+
+func (a cachingAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return a.cache.get(ctx, a.key(ctx, "query", query, ts), func(ctx context.Context) (model.Value, api.Error) {
+		return a.API.Query(ctx, query, ts)
+	})
+}
+
+func (a cachingAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, api.Error) {
+	key := a.key(ctx, "range", query, r.Start, r.End) + fmt.Sprintf("|%v", r.Step)
+	return a.cache.get(ctx, key, func(ctx context.Context) (model.Value, api.Error) {
+		return a.API.QueryRange(ctx, query, r)
+	})
+}
@@ -0,0 +1,127 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+// countingAPI counts the queries sent to Prometheus
+type countingAPI struct {
+	prom_v1.API
+	queries int32
+}
+
+func (c *countingAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	atomic.AddInt32(&c.queries, 1)
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, api.NewErrorAPI(ctx.Err(), nil)
+	}
+	return model.Vector{&model.Sample{Value: 1}}, nil
+}
+
+func (c *countingAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, api.Error) {
+	atomic.AddInt32(&c.queries, 1)
+	return model.Matrix{}, nil
+}
+
+func TestQueryCache(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	promAPI := &countingAPI{}
+	cached := cachingAPI{API: promAPI, cache: newQueryCache(time.Minute, time.Minute), address: "http://prometheus:9090"}
+	queryTime := time.Now()
+
+	// Concurrent queries are sent once
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := cached.Query(context.Background(), "sum(rate(istio_requests_total[1m]))", queryTime)
+			assert.Nil(err)
+			assert.Len(result.(model.Vector), 1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(int32(1), atomic.LoadInt32(&promAPI.queries))
+
+	// Equivalent queries share the cache
+	_, err := cached.Query(context.Background(), "sum(rate(istio_requests_total[1m]))   ", queryTime)
+	assert.Nil(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&promAPI.queries))
+
+	// Queries of other clusters don't
+	_, err = cached.Query(WithCluster(context.Background(), "east"), "sum(rate(istio_requests_total[1m]))", queryTime)
+	assert.Nil(err)
+	assert.Equal(int32(2), atomic.LoadInt32(&promAPI.queries))
+
+	bounds := prom_v1.Range{Start: queryTime.Add(-time.Hour), End: queryTime, Step: time.Minute}
+	_, err = cached.QueryRange(context.Background(), "up", bounds)
+	assert.Nil(err)
+	_, err = cached.QueryRange(context.Background(), "up", bounds)
+	assert.Nil(err)
+	assert.Equal(int32(3), atomic.LoadInt32(&promAPI.queries))
+
+	bounds.Step = 2 * time.Minute
+	_, err = cached.QueryRange(context.Background(), "up", bounds)
+	assert.Nil(err)
+	assert.Equal(int32(4), atomic.LoadInt32(&promAPI.queries))
+}
+
+func TestQueryCacheExpiration(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	promAPI := &countingAPI{}
+	cached := cachingAPI{API: promAPI, cache: newQueryCache(20*time.Millisecond, time.Minute), address: "http://prometheus:9090"}
+
+	queryTime := time.Now()
+	_, _ = cached.Query(context.Background(), "up", queryTime)
+	time.Sleep(30 * time.Millisecond)
+	_, _ = cached.Query(context.Background(), "up", queryTime)
+	assert.Equal(int32(2), atomic.LoadInt32(&promAPI.queries))
+	assert.Len(cached.cache.entries, 1)
+}
+
+func TestQueryCacheCallerCancellation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	promAPI := &countingAPI{}
+	cached := cachingAPI{API: promAPI, cache: newQueryCache(time.Minute, time.Minute), address: "http://prometheus:9090"}
+	queryTime := time.Now()
+
+	// The caller who sends the query leaves before its result
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := cached.Query(ctx, "up", queryTime)
+		assert.NotNil(err)
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(2 * time.Millisecond)
+		result, err := cached.Query(context.Background(), "up", queryTime)
+		assert.Nil(err)
+		assert.Len(result.(model.Vector), 1)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	wg.Wait()
+	assert.Equal(int32(1), atomic.LoadInt32(&promAPI.queries))
+}
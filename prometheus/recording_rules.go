@@ -0,0 +1,185 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// recordingRulePresencePeriod is how often the presence of the recorded metrics is checked
+const recordingRulePresencePeriod = time.Minute
+
+var (
+	quotedStringRegexp  = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	selectorLabelRegexp = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*(?:=~|!~|!=|=)`)
+	groupingRegexp      = regexp.MustCompile(`\b(by|without)\s*\(([^)]*)\)`)
+	labelNameRegexp     = regexp.MustCompile(`[a-zA-Z_][a-zA-Z0-9_]*`)
+)
+
+type (
+	// recordingRule is a recording rule of the configuration, ready to rewrite queries
+	recordingRule struct {
+		config.RecordingRule
+		interval time.Duration
+		labels   map[string]bool
+		// Matches rate(metric{selector}[interval]), capturing the selector and the interval
+		rateRegexp *regexp.Regexp
+	}
+
+	rulePresence struct {
+		present bool
+		checked time.Time
+	}
+
+	// recordingRulesAPI rewrites the rates of raw metrics into the recorded metrics of the recording rules, when the
+	// recorded metrics exist in the Prometheus of the cluster of the query
+	recordingRulesAPI struct {
+		prom_v1.API
+		rules   []recordingRule
+		address string
+	}
+)
+
+// Presence of the recorded metrics, shared by all the clients
+var recordedMetricsLock sync.Mutex
+var recordedMetrics = map[string]rulePresence{}
+
+func newRecordingRule(rule config.RecordingRule) (recordingRule, error) {
+	interval, err := model.ParseDuration(rule.Interval)
+	if err != nil {
+		return recordingRule{}, fmt.Errorf("invalid interval of recording rule [%s]: %v", rule.Record, err)
+	}
+	labels := make(map[string]bool, len(rule.Labels))
+	for _, label := range rule.Labels {
+		labels[label] = true
+	}
+	return recordingRule{
+		RecordingRule: rule,
+		interval:      time.Duration(interval),
+		labels:        labels,
+		rateRegexp:    regexp.MustCompile(`\brate\(\s*` + regexp.QuoteMeta(rule.Metric) + `\s*(\{[^}]*\})?\s*\[([^\]]+)\]\s*\)`),
+	}, nil
+}
+
+// newRecordingRulesAPI returns the API rewriting the queries with the recording rules, or the given API when there
+// are no recording rules
+func newRecordingRulesAPI(promAPI prom_v1.API, cfg config.PrometheusConfig) prom_v1.API {
+	rules := []recordingRule{}
+	for _, rule := range cfg.RecordingRules {
+		recording, err := newRecordingRule(rule)
+		if err != nil {
+			// The configuration is validated on start
+			log.Errorf("Ignoring recording rule: %v", err)
+			continue
+		}
+		rules = append(rules, recording)
+	}
+	if len(rules) == 0 {
+		return promAPI
+	}
+	return recordingRulesAPI{API: promAPI, rules: rules, address: cfg.URL}
+}
+
+// keepsLabels returns true if the rule keeps all the labels
+func (r recordingRule) keepsLabels(labels []string) bool {
+	if len(r.labels) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		if !r.labels[label] {
+			return false
+		}
+	}
+	return true
+}
+
+// groupingLabels returns the labels of the "by" clauses of a query. It returns false if the query aggregates
+// "without" labels, as the labels used are unknown.
+func groupingLabels(query string) ([]string, bool) {
+	labels := []string{}
+	for _, grouping := range groupingRegexp.FindAllStringSubmatch(query, -1) {
+		if grouping[1] == "without" {
+			return nil, false
+		}
+		labels = append(labels, labelNameRegexp.FindAllString(grouping[2], -1)...)
+	}
+	return labels, true
+}
+
+// selectorLabels returns the labels filtered by a selector, i.e. {reporter="source",source_workload=~"a|b"}
+func selectorLabels(selector string) []string {
+	labels := []string{}
+	// Values could look like label matchers
+	for _, matcher := range selectorLabelRegexp.FindAllStringSubmatch(quotedStringRegexp.ReplaceAllString(selector, `""`), -1) {
+		labels = append(labels, matcher[1])
+	}
+	return labels
+}
+
+// rewrite replaces the rates of the metric of the rule by the recorded metric, when the rule keeps the labels used
+func (r recordingRule) rewrite(query string, grouping []string) string {
+	return r.rateRegexp.ReplaceAllStringFunc(query, func(rate string) string {
+		match := r.rateRegexp.FindStringSubmatch(rate)
+		selector, rateInterval := match[1], match[2]
+		if interval, err := model.ParseDuration(rateInterval); err != nil || time.Duration(interval) != r.interval {
+			return rate
+		}
+		if !r.keepsLabels(selectorLabels(selector)) || !r.keepsLabels(grouping) {
+			return rate
+		}
+		return r.Record + selector
+	})
+}
+
+// isPresent returns true if the recorded metric of a rule exists in the Prometheus of the cluster of the query
+func (a recordingRulesAPI) isPresent(ctx context.Context, rule recordingRule) bool {
+	key := a.address + "|" + clusterFromContext(ctx) + "|" + rule.Record
+	recordedMetricsLock.Lock()
+	presence, checked := recordedMetrics[key]
+	recordedMetricsLock.Unlock()
+	if checked && time.Since(presence.checked) < recordingRulePresencePeriod {
+		return presence.present
+	}
+
+	end := time.Now()
+	series, err := a.API.Series(ctx, []string{rule.Record}, end.Add(-rule.interval), end)
+	presence = rulePresence{present: err == nil && len(series) > 0, checked: end}
+	if !presence.present {
+		log.Debugf("Recorded metric [%s] not found, querying [%s]", rule.Record, rule.Metric)
+	}
+	recordedMetricsLock.Lock()
+	recordedMetrics[key] = presence
+	recordedMetricsLock.Unlock()
+	return presence.present
+}
+
+// rewrite returns the query using the recording rules whose recorded metrics exist
+func (a recordingRulesAPI) rewrite(ctx context.Context, query string) string {
+	grouping, ok := groupingLabels(query)
+	for _, rule := range a.rules {
+		if !ok && len(rule.labels) > 0 {
+			continue
+		}
+		if rewritten := rule.rewrite(query, grouping); rewritten != query && a.isPresent(ctx, rule) {
+			query = rewritten
+		}
+	}
+	return query
+}
+
+func (a recordingRulesAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	return a.API.Query(ctx, a.rewrite(ctx, query), ts)
+}
+
+func (a recordingRulesAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, api.Error) {
+	return a.API.QueryRange(ctx, a.rewrite(ctx, query), r)
+}
@@ -0,0 +1,99 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+// recordingAPI records the queries sent to Prometheus, where only the given series exist
+type recordingAPI struct {
+	prom_v1.API
+	series  []string
+	queries []string
+}
+
+func (r *recordingAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	r.queries = append(r.queries, query)
+	return model.Vector{}, nil
+}
+
+func (r *recordingAPI) Series(ctx context.Context, matches []string, startTime time.Time, endTime time.Time) ([]model.LabelSet, api.Error) {
+	result := []model.LabelSet{}
+	for _, s := range r.series {
+		if s == matches[0] {
+			result = append(result, model.LabelSet{"__name__": model.LabelValue(s)})
+		}
+	}
+	return result, nil
+}
+
+func TestRecordingRuleRewrite(t *testing.T) {
+	assert := assert.New(t)
+
+	rule, err := newRecordingRule(config.RecordingRule{
+		Interval: "5m",
+		Labels:   []string{"reporter", "source_workload_namespace", "destination_service_namespace", "response_code"},
+		Metric:   "istio_requests_total",
+		Record:   "kiali:request_total:rate5m",
+	})
+	assert.NoError(err)
+
+	rewrite := func(query string) string {
+		grouping, _ := groupingLabels(query)
+		return rule.rewrite(query, grouping)
+	}
+
+	assert.Equal(`sum(kiali:request_total:rate5m{reporter="source",source_workload_namespace="bookinfo"}) by (response_code)`,
+		rewrite(`sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"} [300s])) by (response_code)`))
+	// Values looking like matchers are not labels
+	assert.Equal(`sum(kiali:request_total:rate5m{reporter="destination_workload=bar"})`,
+		rewrite(`sum(rate(istio_requests_total{reporter="destination_workload=bar"}[5m]))`))
+	// Other intervals
+	query := `sum(rate(istio_requests_total{reporter="source"}[1m])) by (response_code)`
+	assert.Equal(query, rewrite(query))
+	// Labels not kept by the rule
+	query = `sum(rate(istio_requests_total{reporter="source"}[5m])) by (source_workload)`
+	assert.Equal(query, rewrite(query))
+	query = `sum(rate(istio_requests_total{destination_workload="reviews-v1"}[5m]))`
+	assert.Equal(query, rewrite(query))
+	// Other functions and metrics
+	query = `sum(irate(istio_requests_total{reporter="source"}[5m]))`
+	assert.Equal(query, rewrite(query))
+	query = `sum(rate(istio_requests_total_bytes{reporter="source"}[5m]))`
+	assert.Equal(query, rewrite(query))
+
+	_, ok := groupingLabels(`sum(rate(istio_requests_total[5m])) without (pod)`)
+	assert.False(ok)
+}
+
+func TestRecordingRulesAPI(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	promAPI := &recordingAPI{series: []string{"kiali:tcp_sent_bytes_total:rate5m"}}
+	rulesAPI := newRecordingRulesAPI(promAPI, config.PrometheusConfig{
+		URL: "http://recording-rules:9090",
+		RecordingRules: []config.RecordingRule{
+			{Interval: "5m", Metric: "istio_requests_total", Record: "kiali:request_total:rate5m"},
+			{Interval: "5m", Metric: "istio_tcp_sent_bytes_total", Record: "kiali:tcp_sent_bytes_total:rate5m"},
+		},
+	})
+
+	// Recorded metrics not present fall back to the raw query
+	_, _ = rulesAPI.Query(context.Background(), `sum(rate(istio_requests_total{reporter="source"}[5m]))`, time.Now())
+	_, _ = rulesAPI.Query(context.Background(), `sum(rate(istio_tcp_sent_bytes_total{reporter="source"}[5m])) by (pod)`, time.Now())
+	assert.Equal([]string{
+		`sum(rate(istio_requests_total{reporter="source"}[5m]))`,
+		`sum(kiali:tcp_sent_bytes_total:rate5m{reporter="source"}) by (pod)`,
+	}, promAPI.queries)
+
+	assert.Equal(promAPI, newRecordingRulesAPI(promAPI, config.PrometheusConfig{}))
+}
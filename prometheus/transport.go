@@ -0,0 +1,158 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// TenantHeader is the header identifying the tenant of the metrics in Mimir, Cortex and Thanos
+const TenantHeader = "X-Scope-OrgID"
+
+type clusterContextKey struct{}
+
+// WithCluster returns a context for the queries of the metrics of a cluster, which are sent with the credentials
+// and the tenant configured for that cluster
+func WithCluster(ctx context.Context, cluster string) context.Context {
+	return context.WithValue(ctx, clusterContextKey{}, cluster)
+}
+
+// hasCluster returns true if the query is for the metrics of a specific cluster
+func hasCluster(ctx context.Context) bool {
+	cluster, ok := ctx.Value(clusterContextKey{}).(string)
+	return ok && cluster != ""
+}
+
+// clusterFromContext returns the cluster of a query. Queries without cluster are for the home cluster.
+func clusterFromContext(ctx context.Context) string {
+	if hasCluster(ctx) {
+		return ctx.Value(clusterContextKey{}).(string)
+	}
+	return config.Get().KubernetesConfig.ClusterName
+}
+
+// tenancyRoundTripper sends the queries to the endpoint, and with the credentials and headers, of the cluster of
+// each query. Queries of downsampled data get the resolution of the data.
+type tenancyRoundTripper struct {
+	defaultURL     *url.URL
+	defaultRT      http.RoundTripper
+	defaultHeaders map[string]string
+	clusterURLs    map[string]*url.URL
+	clusterRTs     map[string]http.RoundTripper
+	clusterHeaders map[string]map[string]string
+}
+
+func (rt *tenancyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	cluster := clusterFromContext(req.Context())
+	transport, headers := rt.defaultRT, rt.defaultHeaders
+	if clusterRT, ok := rt.clusterRTs[cluster]; ok {
+		transport, headers = clusterRT, rt.clusterHeaders[cluster]
+	}
+	clusterURL := rt.clusterURLs[cluster]
+	resolution, downsampled := resolutionFromContext(req.Context())
+	if len(headers) == 0 && clusterURL == nil && !downsampled {
+		return transport.RoundTrip(req)
+	}
+
+	// Round trippers must not modify the original request
+	req = req.Clone(req.Context())
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if clusterURL != nil {
+		// The path of the API is relative to the global URL, i.e. a Prometheus behind a path prefix
+		target := *clusterURL
+		target.Path = strings.TrimSuffix(clusterURL.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(rt.defaultURL.Path, "/"))
+		target.RawQuery = req.URL.RawQuery
+		req.URL = &target
+		req.Host = target.Host
+	}
+	if downsampled {
+		// Thanos reads the parameters of the URL along with the parameters of the form
+		target := *req.URL
+		args := target.Query()
+		args.Set("max_source_resolution", model.Duration(resolution).String())
+		target.RawQuery = args.Encode()
+		req.URL = &target
+	}
+	return transport.RoundTrip(req)
+}
+
+// queryHeaders returns the headers added to the queries: the custom headers and the tenant header
+func queryHeaders(customHeaders map[string]string, tenantID string) map[string]string {
+	headers := make(map[string]string, len(customHeaders)+1)
+	for name, value := range customHeaders {
+		headers[name] = value
+	}
+	if tenantID != "" {
+		headers[TenantHeader] = tenantID
+	}
+	return headers
+}
+
+// authRoundTripper returns a transport authenticating the queries with the given credentials
+func authRoundTripper(auth config.Auth) (http.RoundTripper, error) {
+	if auth.UseKialiToken {
+		// Note: if we are using the 'bearer' authentication method then we want to use the Kiali
+		// service account token and not the user's token. This is because Kiali does filtering based
+		// on the user's token and prevents people who shouldn't have access to particular metrics.
+		token, err := kubernetes.GetKialiToken()
+		if err != nil {
+			log.Errorf("Could not read the Kiali Service Account token: %v", err)
+			return nil, err
+		}
+		auth.Token = token
+	}
+	// Each set of credentials gets its own transport, as TLS settings are set on the transport
+	return httputil.AuthTransport(&auth, api.DefaultRoundTripper.(*http.Transport).Clone())
+}
+
+// newTenancyRoundTripper returns the transport of the queries to Prometheus
+func newTenancyRoundTripper(cfg config.PrometheusConfig) (http.RoundTripper, error) {
+	defaultRT, err := authRoundTripper(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	defaultURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	rt := &tenancyRoundTripper{
+		defaultURL:     defaultURL,
+		defaultRT:      defaultRT,
+		defaultHeaders: queryHeaders(cfg.CustomHeaders, cfg.TenantID),
+		clusterURLs:    make(map[string]*url.URL, len(cfg.Clusters)),
+		clusterRTs:     make(map[string]http.RoundTripper, len(cfg.Clusters)),
+		clusterHeaders: make(map[string]map[string]string, len(cfg.Clusters)),
+	}
+	for cluster, clusterConfig := range cfg.Clusters {
+		if clusterConfig.URL != "" {
+			if rt.clusterURLs[cluster], err = url.Parse(clusterConfig.URL); err != nil {
+				return nil, err
+			}
+		}
+		clusterRT := defaultRT
+		// Clusters without credentials use the global credentials
+		if clusterConfig.Auth.Type != "" && clusterConfig.Auth.Type != config.AuthTypeNone {
+			if clusterRT, err = authRoundTripper(clusterConfig.Auth); err != nil {
+				return nil, err
+			}
+		}
+		rt.clusterRTs[cluster] = clusterRT
+		// Cluster headers are added to the global headers, replacing them when set
+		rt.clusterHeaders[cluster] = queryHeaders(cfg.CustomHeaders, cfg.TenantID)
+		for name, value := range queryHeaders(clusterConfig.CustomHeaders, clusterConfig.TenantID) {
+			rt.clusterHeaders[cluster][name] = value
+		}
+	}
+	return rt, nil
+}
@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestTenancyRoundTripper(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	rt, err := newTenancyRoundTripper(config.PrometheusConfig{
+		Auth:          config.Auth{Type: config.AuthTypeBearer, Token: "global-token"},
+		CustomHeaders: map[string]string{"X-Proxy": "kiali"},
+		TenantID:      "mesh",
+		Clusters: map[string]config.PrometheusClusterConfig{
+			"east": {
+				Auth:     config.Auth{Type: config.AuthTypeBasic, Username: "east", Password: "secret"},
+				TenantID: "east-tenant",
+			},
+			"west": {
+				CustomHeaders: map[string]string{"X-Proxy": "west"},
+			},
+		},
+	})
+	assert.NoError(err)
+	client := http.Client{Transport: rt}
+
+	send := func(ctx context.Context) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		assert.NoError(err)
+		resp.Body.Close()
+		assert.Empty(req.Header)
+	}
+
+	// Home cluster
+	send(context.Background())
+	assert.Equal("Bearer global-token", received.Get("Authorization"))
+	assert.Equal("mesh", received.Get(TenantHeader))
+	assert.Equal("kiali", received.Get("X-Proxy"))
+
+	send(WithCluster(context.Background(), "east"))
+	assert.Equal("Basic ZWFzdDpzZWNyZXQ=", received.Get("Authorization"))
+	assert.Equal("east-tenant", received.Get(TenantHeader))
+	assert.Equal("kiali", received.Get("X-Proxy"))
+
+	send(WithCluster(context.Background(), "west"))
+	assert.Equal("Bearer global-token", received.Get("Authorization"))
+	assert.Equal("mesh", received.Get(TenantHeader))
+	assert.Equal("west", received.Get("X-Proxy"))
+}
+
+func TestTenancyRoundTripperHomeClusterConfig(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ClusterName = "home"
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	rt, err := newTenancyRoundTripper(config.PrometheusConfig{
+		Clusters: map[string]config.PrometheusClusterConfig{"home": {TenantID: "home-tenant"}},
+	})
+	assert.NoError(err)
+	client := http.Client{Transport: rt}
+	resp, err := client.Get(server.URL)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal("home-tenant", received.Get(TenantHeader))
+	assert.Empty(received.Get("Authorization"))
+}
@@ -2,6 +2,7 @@ package routing
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -49,11 +50,12 @@ func NewRouter() *mux.Router {
 	apiRoutes := NewRoutes()
 	authenticationHandler, _ := handlers.NewAuthenticationHandler()
 	for _, route := range apiRoutes.Routes {
-		var handlerFunction http.Handler = authenticationHandler.HandleUnauthenticated(route.HandlerFunc)
-		handlerFunction = metricHandler(handlerFunction, route)
+		routeHandler := viewOnly(route)
+		var handlerFunction http.Handler = authenticationHandler.HandleUnauthenticated(routeHandler)
 		if route.Authenticated {
-			handlerFunction = authenticationHandler.Handle(route.HandlerFunc)
+			handlerFunction = authenticationHandler.Handle(routeHandler)
 		}
+		handlerFunction = metricHandler(handlerFunction, route)
 		appRouter.
 			Methods(route.Method).
 			Path(route.Pattern).
@@ -82,10 +84,81 @@ func NewRouter() *mux.Router {
 	return rootRouter
 }
 
+// statusRecorder records the status code of a response, for the metrics of the routes
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	if sr.status == 0 {
+		sr.status = code
+	}
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	return sr.ResponseWriter.Write(b)
+}
+
+// Flush keeps the streamed responses, i.e. of the logs, flushed to the client
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// metricHandler observes the processing time of the requests of a route, by status class of the response
 func metricHandler(next http.Handler, route Route) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		promtimer := internalmetrics.GetAPIProcessingTimePrometheusTimer(route.Name)
-		defer promtimer.ObserveDuration()
-		next.ServeHTTP(w, r)
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			internalmetrics.ObserveAPIProcessingTime(route.Name, recorder.status, start)
+		}()
+		next.ServeHTTP(recorder, r)
 	})
 }
+
+// viewOnlyAllowedRoutes are the routes using other methods than GET that don't change the cluster, so they are
+// served in view-only mode
+var viewOnlyAllowedRoutes = map[string]bool{
+	"OpenshiftCheckToken":          true,
+	"MetricsStats":                 true,
+	"NamespaceEnrollmentPreflight": true,
+	"ApiTokenCreate":               true,
+	"ApiTokenRevoke":               true,
+	"SavedViewCreate":              true,
+	"SavedViewUpdate":              true,
+	"SavedViewDelete":              true,
+	"UserPreferencesUpdate":        true,
+}
+
+// viewOnly rejects the requests of the routes changing the cluster when Kiali is in view-only mode
+func viewOnly(route Route) http.HandlerFunc {
+	if route.Method == http.MethodGet || viewOnlyAllowedRoutes[route.Name] {
+		return route.HandlerFunc
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.Get().Deployment.ViewOnlyMode {
+			handlers.RespondWithError(w, http.StatusForbidden, "Kiali is in view-only mode")
+			return
+		}
+		route.HandlerFunc(w, r)
+	}
+}
+
+// featureGated serves the requests with the handler only when a feature is enabled. The route is not found
+// otherwise.
+func featureGated(feature string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !config.Get().KialiFeatureFlags.IsEnabled(feature) {
+			handlers.RespondWithError(w, http.StatusNotFound, "The "+feature+" feature is disabled")
+			return
+		}
+		handler(w, r)
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
 func TestDrawPathProperly(t *testing.T) {
@@ -115,3 +118,92 @@ func TestRedirectWithSetWebRootKeepsParams(t *testing.T) {
 
 	assert.Equal(t, string(body), string(body2), "Response with and without the trailing slash on the webroot are not the same")
 }
+
+func TestFeatureGated(t *testing.T) {
+	oldConfig := config.Get()
+	defer config.Set(oldConfig)
+
+	handler := featureGated(config.FeatureAmbient, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	conf := new(config.Config)
+	config.Set(conf)
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest("GET", "/api/mesh/ambient", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code, "Disabled feature should not be found")
+
+	conf.KialiFeatureFlags.Features = map[string]bool{config.FeatureAmbient: true}
+	config.Set(conf)
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest("GET", "/api/mesh/ambient", nil))
+	assert.Equal(t, http.StatusOK, rr.Code, "Enabled feature should be served")
+}
+
+func TestViewOnly(t *testing.T) {
+	oldConfig := config.Get()
+	defer config.Set(oldConfig)
+
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	scale := viewOnly(Route{Name: "WorkloadScale", Method: "PATCH", HandlerFunc: ok})
+	savedView := viewOnly(Route{Name: "SavedViewCreate", Method: "POST", HandlerFunc: ok})
+	workload := viewOnly(Route{Name: "WorkloadDetails", Method: "GET", HandlerFunc: ok})
+
+	conf := new(config.Config)
+	conf.Deployment.ViewOnlyMode = true
+	config.Set(conf)
+	rr := httptest.NewRecorder()
+	scale(rr, httptest.NewRequest("PATCH", "/api/namespaces/bookinfo/workloads/reviews-v1/scale", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code, "Routes changing the cluster should be rejected")
+	rr = httptest.NewRecorder()
+	savedView(rr, httptest.NewRequest("POST", "/api/views", nil))
+	assert.Equal(t, http.StatusOK, rr.Code, "Routes not changing the cluster should be served")
+	rr = httptest.NewRecorder()
+	workload(rr, httptest.NewRequest("GET", "/api/namespaces/bookinfo/workloads/reviews-v1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code, "GET routes should be served")
+
+	conf.Deployment.ViewOnlyMode = false
+	config.Set(conf)
+	rr = httptest.NewRecorder()
+	scale(rr, httptest.NewRequest("PATCH", "/api/namespaces/bookinfo/workloads/reviews-v1/scale", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func apiProcessingCount(t *testing.T, route, statusClass string) uint64 {
+	metric := &dto.Metric{}
+	observer := internalmetrics.Metrics.APIProcessingTime.WithLabelValues(route, statusClass)
+	if err := observer.(prometheus.Metric).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.Histogram.GetSampleCount()
+}
+
+func TestRouteMetrics(t *testing.T) {
+	oldConfig := config.Get()
+	defer config.Set(oldConfig)
+	config.Set(config.NewConfig())
+
+	router := NewRouter()
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	healthz := apiProcessingCount(t, "Healthz", "2xx")
+	unauthorized := apiProcessingCount(t, "NamespaceList", "4xx")
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	// An authenticated route, without credentials
+	resp, err = http.Get(ts.URL + "/api/namespaces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	assert.Equal(t, healthz+1, apiProcessingCount(t, "Healthz", "2xx"))
+	assert.Equal(t, unauthorized+1, apiProcessingCount(t, "NamespaceList", "4xx"))
+}
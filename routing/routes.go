@@ -3,6 +3,7 @@ package routing
 import (
 	"net/http"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/handlers"
 )
 
@@ -227,6 +228,7 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/csv
 		//
 		//     Schemes: http, https
 		//
@@ -338,6 +340,7 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/csv
 		//
 		//     Schemes: http, https
 		//
@@ -495,7 +498,8 @@ func NewRoutes() (r *Routes) {
 		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/errortraces traces errorTraces
 		// ---
-		// Endpoint to get the number of traces in error for a given service
+		// Endpoint to get the number of traces in error for a given service, or with groups=true, the traces in error
+		// grouped by failure signature
 		//
 		//     Produces:
 		//     - application/json
@@ -506,6 +510,7 @@ func NewRoutes() (r *Routes) {
 		//      404: notFoundError
 		//      500: internalError
 		//      200: errorTracesResponse
+		//      503: serviceUnavailableError
 		//
 		{
 			"ErrorTraces",
@@ -541,6 +546,7 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/csv
 		//
 		//     Schemes: http, https
 		//
@@ -601,12 +607,62 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadUpdate,
 			true,
 		},
+		// swagger:route PATCH /namespaces/{namespace}/workloads/{workload}/scale workloads workloadScale
+		// ---
+		// Endpoint to set the number of replicas of a Deployment, StatefulSet or Argo Rollout
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadScaleResponse
+		//
+		{
+			"WorkloadScale",
+			"PATCH",
+			"/api/namespaces/{namespace}/workloads/{workload}/scale",
+			handlers.WorkloadScale,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/restart workloads workloadRestart
+		// ---
+		// Endpoint to restart the pods of a Deployment, StatefulSet or Argo Rollout, like kubectl rollout restart
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadRestartResponse
+		//
+		{
+			"WorkloadRestart",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/restart",
+			handlers.WorkloadRestart,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps apps appList
 		// ---
 		// Endpoint to get the list of apps for a namespace
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/csv
 		//
 		//     Schemes: http, https
 		//
@@ -960,12 +1016,106 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceHealth,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/events namespaces namespaceEvents
+		// ---
+		// Endpoint to fetch the recent Kubernetes events of the objects of a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: kubernetesEventsResponse
+		//
+		{
+			Name:          "NamespaceEvents",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/events",
+			HandlerFunc:   handlers.NamespaceEvents,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/changes namespaces namespaceChanges
+		// ---
+		// Endpoint to fetch the timeline of the changes of a namespace, or of one of its services: the modifications of
+		// the Istio objects, the rollouts and the scaling of the workloads, and the writes made through Kiali
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: changeTimelineResponse
+		//
+		{
+			Name:          "NamespaceChanges",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/changes",
+			HandlerFunc:   handlers.NamespaceChanges,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/events services serviceEvents
+		// ---
+		// Endpoint to fetch the recent Kubernetes events of a service, of its endpoints and of the pods it selects
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: kubernetesEventsResponse
+		//
+		{
+			Name:          "ServiceEvents",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/events",
+			HandlerFunc:   handlers.ServiceEvents,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/events workloads workloadEvents
+		// ---
+		// Endpoint to fetch the recent Kubernetes events of a workload and of its pods
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: kubernetesEventsResponse
+		//
+		{
+			Name:          "WorkloadEvents",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/workloads/{workload}/events",
+			HandlerFunc:   handlers.WorkloadEvents,
+			Authenticated: true,
+		},
 		// swagger:route GET /namespaces/{namespace}/validations namespaces namespaceValidations
 		// ---
 		// Get validation summary for all objects in the given namespace
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/csv
 		//
 		//     Schemes: http, https
 		//
@@ -1258,16 +1408,41 @@ func NewRoutes() (r *Routes) {
 			handlers.PodDetails,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/pods/{pod}/evict pods podEvict
+		// ---
+		// Endpoint to evict a pod, denied when it would violate a PodDisruptionBudget
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      429: tooManyRequestsError
+		//      500: internalError
+		//      200: evictResponse
+		//
+		{
+			"PodEvict",
+			"POST",
+			"/api/namespaces/{namespace}/pods/{pod}/evict",
+			handlers.PodEvict,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/pods/{pod}/logs pods podLogs
 		// ---
-		// Endpoint to get pod logs
+		// Endpoint to get pod logs, or to stream them
 		//
 		//     Produces:
 		//     - application/json
+		//     - text/event-stream
 		//
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      500: internalError
 		//      404: notFoundError
 		//      200: workloadDetails
@@ -1279,6 +1454,28 @@ func NewRoutes() (r *Routes) {
 			handlers.PodLogs,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/logs workloads workloadLogs
+		// ---
+		// Endpoint to get the logs of all the pods of a workload, merged by time
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      404: notFoundError
+		//      200: workloadLogsResponse
+		//
+		{
+			"WorkloadLogs",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/logs",
+			handlers.WorkloadLogs,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump pods podProxyDump
 		// ---
 		// Endpoint to get pod proxy dump
@@ -1321,6 +1518,50 @@ func NewRoutes() (r *Routes) {
 			handlers.ConfigDumpResourceEntries,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump_diff pods podProxyDumpDiff
+		// ---
+		// Endpoint to compare the clusters, listeners and routes of the proxies of two pods
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: configDumpDiff
+		//
+		{
+			"PodConfigDumpDiff",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/config_dump_diff",
+			handlers.ConfigDumpDiff,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/envoy_stats pods podProxyStats
+		// ---
+		// Endpoint to get the stats of the pod proxy allowed by the configuration, i.e. circuit breakers and retries
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: envoyStats
+		//
+		{
+			"PodEnvoyStats",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/envoy_stats",
+			handlers.EnvoyStats,
+			true,
+		},
 		// swagger:route GET /iter8
 		// ---
 		// Endpoint to check if iter8 adapter is present in the cluster and if user can write adapter config
@@ -1497,6 +1738,1442 @@ func NewRoutes() (r *Routes) {
 			HandlerFunc:   handlers.MetricsStats,
 			Authenticated: true,
 		},
+		// swagger:route GET /apitokens apitokens apiTokenList
+		// ---
+		// Endpoint to list the API tokens of the user
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      403: forbiddenError
+		//      200: apiTokenListResponse
+		//
+		{
+			Name:          "ApiTokenList",
+			Method:        "GET",
+			Pattern:       "/api/apitokens",
+			HandlerFunc:   handlers.ApiTokenList,
+			Authenticated: true,
+		},
+		// swagger:route POST /apitokens apitokens apiTokenCreate
+		// ---
+		// Endpoint to create an API token scoped to a set of namespaces
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      200: apiTokenCreatedResponse
+		//
+		{
+			Name:          "ApiTokenCreate",
+			Method:        "POST",
+			Pattern:       "/api/apitokens",
+			HandlerFunc:   handlers.ApiTokenCreate,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /apitokens/{id} apitokens apiTokenRevoke
+		// ---
+		// Endpoint to revoke an API token
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      204: noContent
+		//
+		{
+			Name:          "ApiTokenRevoke",
+			Method:        "DELETE",
+			Pattern:       "/api/apitokens/{id}",
+			HandlerFunc:   handlers.ApiTokenRevoke,
+			Authenticated: true,
+		},
+		// swagger:route GET /debug/cache debug cacheStatus
+		// ---
+		// Endpoint to diagnose the caches of Kiali: sync status, object counts and estimated memory of the informers
+		// of each cluster, and hits and misses of the caches
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: cacheStatusResponse
+		//
+		{
+			Name:          "CacheStatus",
+			Method:        "GET",
+			Pattern:       "/api/debug/cache",
+			HandlerFunc:   handlers.CacheStatus,
+			Authenticated: true,
+		},
+		// swagger:route GET /debug/tracing debug tracingDiagnose
+		// ---
+		// Endpoint to diagnose the connection to the tracing backend: connection, TLS handshake and certificate,
+		// credentials, flavor of the API (Jaeger, Jaeger v2, Tempo or gRPC) and clock skew, with hints to fix the
+		// configuration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: tracingDiagnosisResponse
+		//
+		{
+			Name:          "TracingDiagnose",
+			Method:        "GET",
+			Pattern:       "/api/debug/tracing",
+			HandlerFunc:   handlers.TracingDiagnose,
+			Authenticated: true,
+		},
+		// swagger:route GET /debug/pprof debug debugProfiles
+		// ---
+		// Endpoint to list the runtime profiles of Kiali, when the profiler is enabled, to its administrators
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: debugProfilesResponse
+		//
+		{
+			Name:          "DebugProfiles",
+			Method:        "GET",
+			Pattern:       "/api/debug/pprof",
+			HandlerFunc:   handlers.DebugProfiles,
+			Authenticated: true,
+		},
+		// swagger:route GET /debug/pprof/{profile} debug debugProfile
+		// ---
+		// Endpoint to fetch a runtime profile of Kiali in the pprof format, when the profiler is enabled, to its administrators
+		//
+		//     Produces:
+		//     - application/octet-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: debugProfileResponse
+		//
+		{
+			Name:          "DebugProfile",
+			Method:        "GET",
+			Pattern:       "/api/debug/pprof/{profile}",
+			HandlerFunc:   handlers.DebugProfileHandler,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/slo services serviceSLO
+		// ---
+		// Endpoint to get the attainment of the service level objectives of a service, and the multi-window burn
+		// rates of their error budgets. Objectives are set in the configuration or with namespace annotations.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: sloResponse
+		//
+		{
+			Name:          "ServiceSLO",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/slo",
+			HandlerFunc:   handlers.ServiceSLO,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/slo workloads workloadSLO
+		// ---
+		// Endpoint to get the attainment of the service level objectives of a workload, and the multi-window burn
+		// rates of their error budgets. Objectives are set in the configuration or with namespace annotations.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: sloResponse
+		//
+		{
+			Name:          "WorkloadSLO",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/workloads/{workload}/slo",
+			HandlerFunc:   handlers.WorkloadSLO,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces/search traces appTraceQLSearch
+		// ---
+		// Endpoint to search the traces of a given app with a TraceQL query, requires Tempo
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: traceQLSearchResponse
+		//
+		{
+			"AppTraceQLSearch",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces/search",
+			handlers.AppTraceQLSearch,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces/tail traces appTracesTail
+		// ---
+		// Endpoint streaming the new traces of a given app, as server-sent events
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: tracesTailResponse
+		//
+		{
+			"AppTracesTail",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces/tail",
+			handlers.TracesTail,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/traces/tail traces serviceTracesTail
+		// ---
+		// Endpoint streaming the new traces of a given service, as server-sent events
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: tracesTailResponse
+		//
+		{
+			"ServiceTracesTail",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/traces/tail",
+			handlers.TracesTail,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/traces/tail traces workloadTracesTail
+		// ---
+		// Endpoint streaming the new traces of a given workload, as server-sent events
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: tracesTailResponse
+		//
+		{
+			"WorkloadTracesTail",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/traces/tail",
+			handlers.TracesTail,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/operations traces serviceOperations
+		// ---
+		// Endpoint to get the requests, errors and latency percentiles of each operation of a given service, from its spans
+		//
+		//		Produces:
+		//		- application/json
+		//
+		//		Schemes: http, https
+		//
+		// responses:
+		// 		500: internalError
+		// 		503: serviceUnavailableError
+		//		200: operationStatsResponse
+		{
+			"ServiceOperations",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/operations",
+			handlers.ServiceOperations,
+			true,
+		},
+		// swagger:route GET /mesh/revisions mesh controlPlaneRevisions
+		// ---
+		// Endpoint to get the revisions of the control plane running in the mesh
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: controlPlaneRevisionsResponse
+		//
+		{
+			Name:          "ControlPlaneRevisions",
+			Method:        "GET",
+			Pattern:       "/api/mesh/revisions",
+			HandlerFunc:   handlers.ControlPlaneRevisions,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/revisions/compare mesh controlPlaneRevisionsCompare
+		// ---
+		// Endpoint to compare two revisions of the control plane: their versions, the deltas of their mesh
+		// configurations, and the namespaces pointing at them with the versions of their proxies
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: revisionComparisonResponse
+		//
+		{
+			Name:          "ControlPlaneRevisionsCompare",
+			Method:        "GET",
+			Pattern:       "/api/mesh/revisions/compare",
+			HandlerFunc:   handlers.ControlPlaneRevisionsCompare,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/ambient/readiness mesh ambientReadiness
+		// ---
+		// Endpoint to get what blocks or changes when migrating the workloads of a namespace from sidecars to ambient
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: ambientReadinessResponse
+		//
+		{
+			Name:          "AmbientReadiness",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/ambient/readiness",
+			HandlerFunc:   featureGated(config.FeatureAmbient, handlers.AmbientReadiness),
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/certificates mesh meshCertificates
+		// ---
+		// Endpoint to get the certificates of the CA of the control plane and of the proxies of a namespace, with their expiration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: meshCertificatesResponse
+		//
+		{
+			Name:          "MeshCertificates",
+			Method:        "GET",
+			Pattern:       "/api/mesh/certificates",
+			HandlerFunc:   handlers.MeshCertificates,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/proxy_status pods namespaceProxiesStatus
+		// ---
+		// Endpoint to get the xDS distribution status of the proxies of a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: proxiesSyncStatusResponse
+		//
+		{
+			Name:          "NamespaceProxiesStatus",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/proxy_status",
+			HandlerFunc:   handlers.NamespaceProxiesStatus,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/proxy_status mesh meshProxiesStatus
+		// ---
+		// Endpoint to get the xDS distribution status of the proxies of every namespace accessible by the user
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: proxiesSyncStatusResponse
+		//
+		{
+			Name:          "MeshProxiesStatus",
+			Method:        "GET",
+			Pattern:       "/api/mesh/proxy_status",
+			HandlerFunc:   handlers.MeshProxiesStatus,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/drift mesh meshDrift
+		// ---
+		// Endpoint to compare the mesh configuration, root CA, trust domain and mesh-wide Istio objects between the clusters of the mesh
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: meshDriftResponse
+		//
+		{
+			Name:          "MeshDrift",
+			Method:        "GET",
+			Pattern:       "/api/mesh/drift",
+			HandlerFunc:   handlers.MeshDrift,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/ztunnel/config_dump mesh ztunnelConfigDump
+		// ---
+		// Endpoint to get the workloads, services and policies known by the ztunnels of the cluster, and the ones they disagree on
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: ztunnelConfigResponse
+		//
+		{
+			Name:          "ZtunnelConfigDump",
+			Method:        "GET",
+			Pattern:       "/api/mesh/ztunnel/config_dump",
+			HandlerFunc:   featureGated(config.FeatureAmbient, handlers.ZtunnelConfigDump),
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/enrollment namespaces namespaceEnrollment
+		// ---
+		// Endpoint to get the enrollment of a namespace in the mesh, sidecar or ambient, with the revisions it can be enrolled in
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: namespaceEnrollmentResponse
+		//
+		{
+			Name:          "NamespaceEnrollment",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/enrollment",
+			HandlerFunc:   handlers.NamespaceEnrollment,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/enrollment/preflight namespaces namespaceEnrollmentPreflight
+		// ---
+		// Endpoint to run the checks of the enrollment of a namespace in the mesh, without enrolling it
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: enrollmentPreflightResponse
+		//
+		{
+			Name:          "NamespaceEnrollmentPreflight",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/enrollment/preflight",
+			HandlerFunc:   handlers.NamespaceEnrollmentPreflight,
+			Authenticated: true,
+		},
+		// swagger:route PUT /namespaces/{namespace}/enrollment namespaces namespaceEnroll
+		// ---
+		// Endpoint to enroll a namespace in the sidecar data plane, of a revision, or in the ambient data plane, when the
+		// preflight checks pass. The pods of the namespace have to be restarted to join the data plane.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: namespaceEnrollmentResponse
+		//
+		{
+			Name:          "NamespaceEnroll",
+			Method:        "PUT",
+			Pattern:       "/api/namespaces/{namespace}/enrollment",
+			HandlerFunc:   handlers.NamespaceEnroll,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/enrollment namespaces namespaceUnenroll
+		// ---
+		// Endpoint to remove a namespace from the mesh. Its pods keep their sidecars until restarted.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: namespaceEnrollmentResponse
+		//
+		{
+			Name:          "NamespaceUnenroll",
+			Method:        "DELETE",
+			Pattern:       "/api/namespaces/{namespace}/enrollment",
+			HandlerFunc:   handlers.NamespaceUnenroll,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/traffic_shifting services wizardTrafficShifting
+		// ---
+		// Endpoint to route the traffic of a service to its workloads by weight. With a ttl, the change is reverted
+		// at its expiration unless it is confirmed.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardTrafficShifting",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/traffic_shifting",
+			HandlerFunc:   handlers.WizardTrafficShifting,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/wizard services wizardChange
+		// ---
+		// Endpoint to get the change applied by a wizard on a service, waiting for its confirmation
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardChange",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard",
+			HandlerFunc:   handlers.WizardChange,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/confirm services wizardConfirm
+		// ---
+		// Endpoint to confirm the change applied by a wizard on a service, cancelling its rollback
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardConfirm",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/confirm",
+			HandlerFunc:   handlers.WizardConfirm,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/rollback services wizardRollback
+		// ---
+		// Endpoint to revert now the change applied by a wizard on a service, waiting for its confirmation
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardRollback",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/rollback",
+			HandlerFunc:   handlers.WizardRollback,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/canary_promotion services wizardCanaryPromotion
+		// ---
+		// Endpoint to evaluate the error rate and p99 latency of the canary subset of a service and, when they meet
+		// the success criteria, to raise its weight by a step. With a ttl, the promotion is reverted at its expiration
+		// unless it is confirmed.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: canaryPromotionResponse
+		//
+		{
+			Name:          "WizardCanaryPromotion",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/canary_promotion",
+			HandlerFunc:   handlers.WizardCanaryPromotion,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/fault_injection services wizardFaultInjection
+		// ---
+		// Endpoint to inject delays and aborts in the http routes of a service. The faults are removed at the
+		// expiration of their ttl.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardFaultInjection",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/fault_injection",
+			HandlerFunc:   handlers.WizardFaultInjection,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/fault_injections mesh meshFaultInjections
+		// ---
+		// Endpoint to list the faults injected in the VirtualServices of the accessible namespaces
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: faultInjectionsResponse
+		//
+		{
+			Name:          "MeshFaultInjections",
+			Method:        "GET",
+			Pattern:       "/api/mesh/fault_injections",
+			HandlerFunc:   handlers.FaultInjections,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/fault_injections/{object} config faultInjectionsRemove
+		// ---
+		// Endpoint to remove the faults injected in a VirtualService
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			Name:          "FaultInjectionsRemove",
+			Method:        "DELETE",
+			Pattern:       "/api/namespaces/{namespace}/fault_injections/{object}",
+			HandlerFunc:   handlers.FaultInjectionsRemove,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizard/traffic_mirroring services wizardTrafficMirroring
+		// ---
+		// Endpoint to mirror a percentage of the traffic of a service to a shadow destination. With a ttl, the change
+		// is reverted at its expiration unless it is confirmed.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: wizardChangeResponse
+		//
+		{
+			Name:          "WizardTrafficMirroring",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/traffic_mirroring",
+			HandlerFunc:   handlers.WizardTrafficMirroring,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/wizard/traffic_mirroring/report services trafficMirroringReport
+		// ---
+		// Endpoint to compare the response codes and latencies of the primary destination of a service to those of
+		// the shadow destination its traffic is mirrored to
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: trafficMirroringReportResponse
+		//
+		{
+			Name:          "TrafficMirroringReport",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/traffic_mirroring/report",
+			HandlerFunc:   handlers.TrafficMirroringReport,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/wizard/traffic_policy_recommendation services trafficPolicyRecommendation
+		// ---
+		// Endpoint to recommend the connection pool sizes and outlier detection of the DestinationRule of a service,
+		// from its observed concurrency, latency, pool overflows and ejections. It returns a patch applying them.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: trafficPolicyRecommendationResponse
+		//
+		{
+			Name:          "TrafficPolicyRecommendation",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/services/{service}/wizard/traffic_policy_recommendation",
+			HandlerFunc:   handlers.TrafficPolicyRecommendation,
+			Authenticated: true,
+		},
+		// swagger:route GET /namespaces/{namespace}/mtls_rollout tls namespaceMTLSRollout
+		// ---
+		// Endpoint to get the state of the staged STRICT mTLS rollout of a namespace, with the clients sending it
+		// plaintext requests since its start
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: mtlsRolloutResponse
+		//
+		{
+			Name:          "NamespaceMTLSRollout",
+			Method:        "GET",
+			Pattern:       "/api/namespaces/{namespace}/mtls_rollout",
+			HandlerFunc:   handlers.NamespaceMTLSRollout,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/mtls_rollout tls namespaceMTLSRolloutStart
+		// ---
+		// Endpoint to start the staged STRICT mTLS rollout of a namespace, setting its namespace-wide
+		// PeerAuthentication to PERMISSIVE for a soak window. A started rollout is resumed.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: mtlsRolloutResponse
+		//
+		{
+			Name:          "NamespaceMTLSRolloutStart",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/mtls_rollout",
+			HandlerFunc:   handlers.NamespaceMTLSRolloutStart,
+			Authenticated: true,
+		},
+		// swagger:route POST /namespaces/{namespace}/mtls_rollout/strict tls namespaceMTLSRolloutStrict
+		// ---
+		// Endpoint to flip the mTLS rollout of a namespace to STRICT, once its soak window completed without
+		// plaintext traffic, or when forced
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: mtlsRolloutResponse
+		//
+		{
+			Name:          "NamespaceMTLSRolloutStrict",
+			Method:        "POST",
+			Pattern:       "/api/namespaces/{namespace}/mtls_rollout/strict",
+			HandlerFunc:   handlers.NamespaceMTLSRolloutStrict,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/mtls_rollout tls namespaceMTLSRolloutAbort
+		// ---
+		// Endpoint to abort the mTLS rollout of a namespace, restoring its mTLS as it was before
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			Name:          "NamespaceMTLSRolloutAbort",
+			Method:        "DELETE",
+			Pattern:       "/api/namespaces/{namespace}/mtls_rollout",
+			HandlerFunc:   handlers.NamespaceMTLSRolloutAbort,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/sidecar_sizing mesh meshSidecarSizing
+		// ---
+		// Endpoint to compare the CPU and memory used by the injected proxies of each namespace to their requests and limits, recommending their sizing
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: sidecarSizingResponse
+		//
+		{
+			Name:          "MeshSidecarSizing",
+			Method:        "GET",
+			Pattern:       "/api/mesh/sidecar_sizing",
+			HandlerFunc:   handlers.MeshSidecarSizing,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/outbound_traffic_policy mesh meshOutboundTrafficPolicy
+		// ---
+		// Endpoint to list the external hosts reached through the PassthroughCluster or blocked by the BlackHoleCluster, with the ServiceEntries they need with the REGISTRY_ONLY outbound traffic policy
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: outboundTrafficPolicyResponse
+		//
+		{
+			Name:          "MeshOutboundTrafficPolicy",
+			Method:        "GET",
+			Pattern:       "/api/mesh/outbound_traffic_policy",
+			HandlerFunc:   handlers.MeshOutboundTrafficPolicy,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/injection_coverage mesh meshInjectionCoverage
+		// ---
+		// Endpoint to report, for each namespace, the pods with a sidecar or captured by ztunnel, the revisions of their proxies, and why the other pods are outside of the data plane
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: injectionCoverageResponse
+		//
+		{
+			Name:          "MeshInjectionCoverage",
+			Method:        "GET",
+			Pattern:       "/api/mesh/injection_coverage",
+			HandlerFunc:   handlers.MeshInjectionCoverage,
+			Authenticated: true,
+		},
+		// swagger:route GET /mesh/external-services mesh meshExternalServices
+		// ---
+		// Endpoint to list the external hosts of the mesh, registered by ServiceEntries or reached through the PassthroughCluster, with the workloads calling them and the TLS posture of their ports
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: externalServicesResponse
+		//
+		{
+			Name:          "MeshExternalServices",
+			Method:        "GET",
+			Pattern:       "/api/mesh/external-services",
+			HandlerFunc:   handlers.MeshExternalServices,
+			Authenticated: true,
+		},
+		// swagger:route GET /config/validate config configValidate
+		// ---
+		// Endpoint to check the configuration of Kiali for contradictory settings, missing cluster secrets and unreachable external services
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: configValidationResponse
+		//
+		{
+			Name:          "ConfigValidate",
+			Method:        "GET",
+			Pattern:       "/api/config/validate",
+			HandlerFunc:   handlers.ConfigValidate,
+			Authenticated: true,
+		},
+		// swagger:route GET /clusters clusters clusters
+		// ---
+		// Endpoint to list the remote clusters, configured or registered through the API
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: clustersResponse
+		//
+		{
+			Name:          "Clusters",
+			Method:        "GET",
+			Pattern:       "/api/clusters",
+			HandlerFunc:   handlers.Clusters,
+			Authenticated: true,
+		},
+		// swagger:route GET /clusters/status clusters clustersStatus
+		// ---
+		// Endpoint to check, for every cluster, the API server, the Istio API, the sync of the informers, and the
+		// Prometheus and tracing backends, with the last time each check was ok
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: clustersStatusResponse
+		//
+		{
+			Name:          "ClustersStatus",
+			Method:        "GET",
+			Pattern:       "/api/clusters/status",
+			HandlerFunc:   handlers.ClustersStatus,
+			Authenticated: true,
+		},
+		// swagger:route GET /clusters/capabilities clusters clustersCapabilities
+		// ---
+		// Endpoint to list, for every cluster, the optional APIs it supports: Gateway API, experimental Gateway API,
+		// Inference API, ambient mode, and the versions of Istio
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: clustersCapabilitiesResponse
+		//
+		{
+			Name:          "ClustersCapabilities",
+			Method:        "GET",
+			Pattern:       "/api/clusters/capabilities",
+			HandlerFunc:   handlers.ClustersCapabilities,
+			Authenticated: true,
+		},
+		// swagger:route GET /clusters/traffic clusters clusterTraffic
+		// ---
+		// Endpoint to fetch the request rates and error rates between the clusters, from the source and destination
+		// cluster labels of the telemetry, as a matrix
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      200: clusterTrafficResponse
+		//
+		{
+			Name:          "ClusterTraffic",
+			Method:        "GET",
+			Pattern:       "/api/clusters/traffic",
+			HandlerFunc:   handlers.ClusterTraffic,
+			Authenticated: true,
+		},
+		// swagger:route GET /clusters/eastwest_probe clusters clustersEastWestProbe
+		// ---
+		// Endpoint to probe the connectivity between the clusters through their east-west gateways. The handshakes
+		// are made from Kiali.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      200: eastWestProbeResponse
+		//
+		{
+			Name:          "ClustersEastWestProbe",
+			Method:        "GET",
+			Pattern:       "/api/clusters/eastwest_probe",
+			HandlerFunc:   handlers.ClustersEastWestProbe,
+			Authenticated: true,
+		},
+		// swagger:route POST /clusters clusters clusterRegister
+		// ---
+		// Endpoint to register a remote cluster, reached with the remote secret (kubeconfig) held by a Secret, without
+		// a restart of Kiali. Restricted to the administrators of Kiali.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      200: clusterResponse
+		//
+		{
+			Name:          "ClusterRegister",
+			Method:        "POST",
+			Pattern:       "/api/clusters",
+			HandlerFunc:   handlers.ClusterRegister,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /clusters/{cluster} clusters clusterDeregister
+		// ---
+		// Endpoint to deregister a remote cluster registered through the API. Restricted to the administrators of Kiali.
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      204: noContent
+		//
+		{
+			Name:          "ClusterDeregister",
+			Method:        "DELETE",
+			Pattern:       "/api/clusters/{cluster}",
+			HandlerFunc:   handlers.ClusterDeregister,
+			Authenticated: true,
+		},
+		// swagger:route GET /federation/instances federation federationInstances
+		// ---
+		// Endpoint to check the remote Kiali instances aggregated by the federation
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: federatedInstancesResponse
+		//
+		{
+			Name:          "FederationInstances",
+			Method:        "GET",
+			Pattern:       "/api/federation/instances",
+			HandlerFunc:   handlers.FederationInstances,
+			Authenticated: true,
+		},
+		// swagger:route GET /federation/namespaces federation federationNamespaces
+		// ---
+		// Endpoint to list the namespaces of all the remote Kiali instances, with the instances which cannot be read
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: federatedNamespacesResponse
+		//
+		{
+			Name:          "FederationNamespaces",
+			Method:        "GET",
+			Pattern:       "/api/federation/namespaces",
+			HandlerFunc:   handlers.FederationNamespaces,
+			Authenticated: true,
+		},
+		// swagger:route GET /federation/{cluster}/{path} federation federationProxy
+		// ---
+		// Endpoint to read the API of the remote Kiali instance of a cluster, with the credentials of this Kiali, i.e.
+		// /federation/east/api/namespaces/bookinfo/health. The federation is reserved to the administrators of Kiali.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: federationProxyResponse
+		//
+		{
+			Name:          "FederationProxy",
+			Method:        "GET",
+			Pattern:       "/api/federation/{cluster}/{path:.*}",
+			HandlerFunc:   handlers.FederationProxy,
+			Authenticated: true,
+		},
+		// swagger:route GET /views views savedViewList
+		// ---
+		// Endpoint to list the saved views of the user and the views shared by the other users
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: savedViewListResponse
+		//
+		{
+			Name:          "SavedViewList",
+			Method:        "GET",
+			Pattern:       "/api/views",
+			HandlerFunc:   handlers.SavedViewList,
+			Authenticated: true,
+		},
+		// swagger:route POST /views views savedViewCreate
+		// ---
+		// Endpoint to save a view of the console, i.e. a graph with its filters, shared or not with the other users
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: savedViewResponse
+		//
+		{
+			Name:          "SavedViewCreate",
+			Method:        "POST",
+			Pattern:       "/api/views",
+			HandlerFunc:   handlers.SavedViewCreate,
+			Authenticated: true,
+		},
+		// swagger:route GET /views/{id} views savedViewDetails
+		// ---
+		// Endpoint to fetch a saved view of the user, or a view shared by another user
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: savedViewResponse
+		//
+		{
+			Name:          "SavedViewDetails",
+			Method:        "GET",
+			Pattern:       "/api/views/{id}",
+			HandlerFunc:   handlers.SavedViewDetails,
+			Authenticated: true,
+		},
+		// swagger:route PUT /views/{id} views savedViewUpdate
+		// ---
+		// Endpoint to replace a saved view of the user
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: savedViewResponse
+		//
+		{
+			Name:          "SavedViewUpdate",
+			Method:        "PUT",
+			Pattern:       "/api/views/{id}",
+			HandlerFunc:   handlers.SavedViewUpdate,
+			Authenticated: true,
+		},
+		// swagger:route DELETE /views/{id} views savedViewDelete
+		// ---
+		// Endpoint to delete a saved view of the user
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      204: noContent
+		//
+		{
+			Name:          "SavedViewDelete",
+			Method:        "DELETE",
+			Pattern:       "/api/views/{id}",
+			HandlerFunc:   handlers.SavedViewDelete,
+			Authenticated: true,
+		},
+		// swagger:route GET /user/preferences user userPreferences
+		// ---
+		// Endpoint to get the preferences of the user
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: userPreferencesResponse
+		//
+		{
+			Name:          "UserPreferences",
+			Method:        "GET",
+			Pattern:       "/api/user/preferences",
+			HandlerFunc:   handlers.UserPreferences,
+			Authenticated: true,
+		},
+		// swagger:route PUT /user/preferences user userPreferencesUpdate
+		// ---
+		// Endpoint to replace the preferences of the user
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      400: badRequestError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: userPreferencesResponse
+		//
+		{
+			Name:          "UserPreferencesUpdate",
+			Method:        "PUT",
+			Pattern:       "/api/user/preferences",
+			HandlerFunc:   handlers.UserPreferencesUpdate,
+			Authenticated: true,
+		},
+		// swagger:route GET /alerts alerts alerts
+		// ---
+		// Endpoint to list the alerts firing in Alertmanager, about the namespaces accessible to the user and the cluster
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      403: forbiddenError
+		//      200: alertsResponse
+		//
+		{
+			Name:          "Alerts",
+			Method:        "GET",
+			Pattern:       "/api/alerts",
+			HandlerFunc:   handlers.Alerts,
+			Authenticated: true,
+		},
 	}
 
 	return
@@ -1,16 +1,21 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/gorilla/mux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/handlers"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/routing"
 )
@@ -36,6 +41,9 @@ func NewServer() *Server {
 	if conf.Server.GzipEnabled {
 		handler = configureGzipHandler(router)
 	}
+	if conf.Server.H2CEnabled && !isSecure(conf) {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: time.Duration(conf.Server.IdleTimeout) * time.Second})
+	}
 
 	// The Kiali server has only a single http server ever during its lifetime. But to support
 	// testing that wants to start multiple servers over the lifetime of the process,
@@ -44,18 +52,35 @@ func NewServer() *Server {
 	http.DefaultServeMux = mux
 	http.Handle("/", handler)
 
-	// Clients must use TLS 1.2 or higher
+	// Clients must use TLS 1.2 or higher. The TLS settings are validated on start.
+	minVersion, _ := conf.Server.TLS.TLSVersion()
+	cipherSuites, _ := conf.Server.TLS.CipherSuiteIDs()
 	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
 	}
 
 	// create the server definition that will handle both console and api server traffic
 	httpServer := &http.Server{
-		Addr:         fmt.Sprintf("%v:%v", conf.Server.Address, conf.Server.Port),
-		TLSConfig:    tlsConfig,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		Addr:           fmt.Sprintf("%v:%v", conf.Server.Address, conf.Server.Port),
+		TLSConfig:      tlsConfig,
+		ReadTimeout:    time.Duration(conf.Server.ReadTimeout) * time.Second,
+		WriteTimeout:   time.Duration(conf.Server.WriteTimeout) * time.Second,
+		IdleTimeout:    time.Duration(conf.Server.IdleTimeout) * time.Second,
+		MaxHeaderBytes: conf.Server.MaxHeaderBytes,
+	}
+	if !conf.Server.HTTP2Enabled {
+		// A non-nil map disables HTTP/2 over TLS
+		httpServer.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	// The streams of events end as soon as the server shuts down, instead of holding the shutdown
+	shutdown := make(chan struct{})
+	httpServer.BaseContext = func(net.Listener) context.Context {
+		return handlers.WithShutdown(context.Background(), shutdown)
 	}
+	httpServer.RegisterOnShutdown(func() {
+		close(shutdown)
+	})
 
 	// return our new Server
 	return &Server{
@@ -69,10 +94,13 @@ func (s *Server) Start() {
 	conf := config.Get()
 	log.Infof("Server endpoint will start at [%v%v]", s.httpServer.Addr, conf.Server.WebRoot)
 	log.Infof("Server endpoint will serve static content from [%v]", conf.Server.StaticContentRootDirectory)
-	secure := conf.Identity.CertFile != "" && conf.Identity.PrivateKeyFile != ""
+	if err := business.CheckApiTokenPermissions(); err != nil {
+		log.Errorf("Kiali cannot serve the API tokens: %v", err)
+	}
+	handlers.SetReady(true)
 	go func() {
 		var err error
-		if secure {
+		if isSecure(conf) {
 			log.Infof("Server endpoint will require https")
 			s.router.Use(secureHttpsMiddleware)
 			err = s.httpServer.ListenAndServeTLS(conf.Identity.CertFile, conf.Identity.PrivateKeyFile)
@@ -80,21 +108,48 @@ func (s *Server) Start() {
 			s.router.Use(plainHttpMiddleware)
 			err = s.httpServer.ListenAndServe()
 		}
-		log.Warning(err)
+		if err != http.ErrServerClosed {
+			log.Warning(err)
+		}
 	}()
 
 	// Start the Metrics Server
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
 	}
+
+	business.StartClusterRegistrySync()
+	business.StartLeaderJobs()
 }
 
-// Stop the HTTP server
+// Stop the HTTP server, in order: the readiness fails for the drain delay, the requests still being served while
+// Kubernetes stops routing new ones to the server, then no new request is accepted, the streams of events end and
+// the other requests in flight are given the shutdown timeout to complete, then the background jobs and informers
+// are stopped and the audit events are delivered.
 func (s *Server) Stop() {
-	StopMetricsServer()
-	business.Stop()
+	conf := config.Get()
+	handlers.SetReady(false)
+	if delay := time.Duration(conf.Server.ShutdownDrainDelay) * time.Second; delay > 0 {
+		log.Infof("Server endpoint is not ready, it will stop in %v", delay)
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(conf.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+
 	log.Infof("Server endpoint will stop at [%v]", s.httpServer.Addr)
-	s.httpServer.Close()
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		log.Warningf("Server endpoint requests in flight are cut: %v", err)
+		s.httpServer.Close()
+	}
+	business.Stop()
+	handlers.FlushAuthAuditEvents(ctx)
+	StopMetricsServer()
+}
+
+// isSecure returns true when the server serves its requests over TLS
+func isSecure(conf *config.Config) bool {
+	return conf.Identity.CertFile != "" && conf.Identity.PrivateKeyFile != ""
 }
 
 func corsAllowed(next http.Handler) http.Handler {
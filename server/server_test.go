@@ -51,6 +51,7 @@ func TestRootContextPath(t *testing.T) {
 
 	serverURL := fmt.Sprintf("http://%v", testServerHostPort)
 
+	util.Clock = util.RealClock{}
 	config.Set(conf)
 
 	server := NewServer()
@@ -110,6 +111,7 @@ func TestAnonymousMode(t *testing.T) {
 	apiURLWithAuthentication := serverURL + "/api/authenticate"
 	apiURL := serverURL + "/api"
 
+	util.Clock = util.RealClock{}
 	config.Set(conf)
 
 	server := NewServer()
@@ -441,3 +443,119 @@ func (conf *httpClientConfig) buildHTTPClient() (*http.Client, error) {
 
 	return &httpClient, nil
 }
+
+func TestStopDrainsRequestsInFlight(t *testing.T) {
+	testPort, err := getFreePort(testHostname)
+	if err != nil {
+		t.Fatalf("Cannot get a free port to run tests on host [%v]", testHostname)
+	}
+
+	conf := new(config.Config)
+	conf.Server.Address = testHostname
+	conf.Server.Port = testPort
+	conf.Server.StaticContentRootDirectory = tmpDir
+	conf.Server.ShutdownTimeout = 5
+	conf.Auth.Strategy = "anonymous"
+	util.Clock = util.RealClock{}
+	config.Set(conf)
+
+	server := NewServer()
+	started := make(chan struct{})
+	http.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	server.Start()
+
+	serverURL := fmt.Sprintf("http://%v:%v", testHostname, testPort)
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	checkHTTPReady(httpClient, serverURL+"/api")
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := httpClient.Get(serverURL + "/slow")
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-started
+	server.Stop()
+
+	if code := <-status; code != http.StatusOK {
+		t.Fatalf("Failed: the request in flight should have completed, got status [%v]", code)
+	}
+	if _, err := httpClient.Get(serverURL + "/slow"); err == nil {
+		t.Fatalf("Failed: the stopped server should not accept requests")
+	}
+}
+
+func TestStopFailsReadinessFirst(t *testing.T) {
+	testPort, err := getFreePort(testHostname)
+	if err != nil {
+		t.Fatalf("Cannot get a free port to run tests on host [%v]", testHostname)
+	}
+
+	conf := new(config.Config)
+	conf.Server.Address = testHostname
+	conf.Server.Port = testPort
+	conf.Server.StaticContentRootDirectory = tmpDir
+	conf.Server.ShutdownDrainDelay = 1
+	conf.Server.ShutdownTimeout = 5
+	conf.Auth.Strategy = "anonymous"
+	util.Clock = util.RealClock{}
+	config.Set(conf)
+
+	server := NewServer()
+	server.Start()
+
+	serverURL := fmt.Sprintf("http://%v:%v", testHostname, testPort)
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	checkHTTPReady(httpClient, serverURL+"/healthz")
+
+	stopped := make(chan struct{})
+	go func() {
+		server.Stop()
+		close(stopped)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	// The requests are still served during the drain delay, the probes fail
+	resp, err := httpClient.Get(serverURL + "/healthz")
+	if err != nil {
+		t.Fatalf("Failed: the server should serve the requests during the drain delay: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Failed: the readiness should fail during the drain delay, got status [%v]", resp.StatusCode)
+	}
+	<-stopped
+}
+
+func TestNewServerHardening(t *testing.T) {
+	conf := config.NewConfig()
+	conf.Server.ReadTimeout = 5
+	conf.Server.WriteTimeout = 10
+	conf.Server.IdleTimeout = 60
+	conf.Server.MaxHeaderBytes = 8192
+	conf.Server.HTTP2Enabled = false
+	conf.Server.TLS.MinVersion = "1.3"
+	config.Set(conf)
+
+	server := NewServer()
+	httpServer := server.httpServer
+	if httpServer.ReadTimeout != 5*time.Second || httpServer.WriteTimeout != 10*time.Second || httpServer.IdleTimeout != time.Minute {
+		t.Fatalf("Failed: the timeouts of the server should be configured: %v %v %v", httpServer.ReadTimeout, httpServer.WriteTimeout, httpServer.IdleTimeout)
+	}
+	if httpServer.MaxHeaderBytes != 8192 {
+		t.Fatalf("Failed: the max header size should be configured: %v", httpServer.MaxHeaderBytes)
+	}
+	if httpServer.TLSNextProto == nil {
+		t.Fatalf("Failed: HTTP/2 should be disabled")
+	}
+	if httpServer.TLSConfig.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("Failed: the minimum TLS version should be 1.3: %v", httpServer.TLSConfig.MinVersion)
+	}
+}
@@ -0,0 +1,100 @@
+package status
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// Time given to an external service to answer when the configuration is validated
+const externalServiceCheckTimeout = 5 * time.Second
+
+type externalServiceCheck struct {
+	setting string
+	url     string
+	auth    config.Auth
+}
+
+// ValidateConfig lints a configuration and checks that the external services it configures are reachable with
+// their credentials
+func ValidateConfig(conf *config.Config) []config.LintFinding {
+	findings := conf.Lint()
+
+	es := conf.ExternalServices
+	checks := []externalServiceCheck{}
+	if es.Prometheus.URL != "" {
+		checks = append(checks, externalServiceCheck{"external_services.prometheus.url", es.Prometheus.URL, es.Prometheus.Auth})
+	}
+	for _, cluster := range sortedKeys(es.Prometheus.Clusters) {
+		if clusterConfig := es.Prometheus.Clusters[cluster]; clusterConfig.URL != "" {
+			checks = append(checks, externalServiceCheck{fmt.Sprintf("external_services.prometheus.clusters.%s.url", cluster), clusterConfig.URL, clusterConfig.Auth})
+		}
+	}
+	if es.CustomDashboards.Enabled && es.CustomDashboards.Prometheus.URL != "" {
+		checks = append(checks, externalServiceCheck{"external_services.custom_dashboards.prometheus.url", es.CustomDashboards.Prometheus.URL, es.CustomDashboards.Prometheus.Auth})
+	}
+	if es.Grafana.Enabled && es.Grafana.InClusterURL != "" {
+		checks = append(checks, externalServiceCheck{"external_services.grafana.in_cluster_url", es.Grafana.InClusterURL, es.Grafana.Auth})
+	}
+	if es.Tracing.Enabled && es.Tracing.InClusterURL != "" {
+		checks = append(checks, externalServiceCheck{"external_services.tracing.in_cluster_url", es.Tracing.InClusterURL, es.Tracing.Auth})
+	}
+	if es.Loki.Enabled && es.Loki.InClusterURL != "" {
+		checks = append(checks, externalServiceCheck{"external_services.loki.in_cluster_url", es.Loki.InClusterURL, es.Loki.Auth})
+	}
+
+	results := make([]*config.LintFinding, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check externalServiceCheck) {
+			defer wg.Done()
+			results[i] = checkExternalService(check)
+		}(i, check)
+	}
+	wg.Wait()
+	for _, finding := range results {
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+	return findings
+}
+
+// checkExternalService returns a finding when an external service cannot be reached, or rejects the credentials
+func checkExternalService(check externalServiceCheck) *config.LintFinding {
+	// Be sure to copy config.Auth and not modify the existing
+	auth := check.auth
+	if auth.UseKialiToken {
+		token, err := kubernetes.GetKialiToken()
+		if err != nil {
+			return &config.LintFinding{Severity: config.LintWarning, Setting: check.setting, Message: fmt.Sprintf("not checked, the token of Kiali cannot be read: %v", err)}
+		}
+		auth.Token = token
+	}
+	_, code, err := httputil.HttpGet(check.url, &auth, externalServiceCheckTimeout)
+	switch {
+	case err != nil:
+		return &config.LintFinding{Severity: config.LintError, Setting: check.setting, Message: fmt.Sprintf("unreachable: %v", err)}
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return &config.LintFinding{Severity: config.LintError, Setting: check.setting, Message: fmt.Sprintf("the credentials are rejected with status %d", code)}
+	case code >= http.StatusInternalServerError:
+		return &config.LintFinding{Severity: config.LintWarning, Setting: check.setting, Message: fmt.Sprintf("answered with status %d", code)}
+	}
+	return nil
+}
+
+func sortedKeys(clusters map[string]config.PrometheusClusterConfig) []string {
+	keys := make([]string, 0, len(clusters))
+	for k := range clusters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
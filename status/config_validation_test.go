@@ -0,0 +1,42 @@
+package status
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestValidateConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer prometheus.Close()
+	tracing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer tracing.Close()
+	loki := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	lokiURL := loki.URL
+	loki.Close()
+
+	conf := config.NewConfig()
+	conf.LoginToken.SigningKey = "a-secret-signing-key"
+	conf.ExternalServices.Prometheus.URL = prometheus.URL
+	conf.ExternalServices.Tracing.InClusterURL = tracing.URL
+	conf.ExternalServices.Loki.Enabled = true
+	conf.ExternalServices.Loki.InClusterURL = lokiURL
+
+	findings := ValidateConfig(conf)
+	assert.Len(findings, 2)
+	assert.Equal("external_services.tracing.in_cluster_url", findings[0].Setting)
+	assert.Equal("the credentials are rejected with status 401", findings[0].Message)
+	assert.Equal("external_services.loki.in_cluster_url", findings[1].Setting)
+	assert.Equal(config.LintError, findings[1].Severity)
+	assert.Contains(findings[1].Message, "unreachable")
+}
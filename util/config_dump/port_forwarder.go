@@ -1,6 +1,7 @@
 package config_dump
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -15,6 +16,7 @@ import (
 type PortForwarder interface {
 	Start() error
 	Stop()
+	LocalPort() (int, error)
 }
 
 type forwarder struct {
@@ -46,6 +48,19 @@ func (f forwarder) Stop() {
 	close(f.StopCh)
 }
 
+// LocalPort returns the local port of the forwarding once started, i.e. the port chosen by the system when the
+// local port of the mapping is 0
+func (f forwarder) LocalPort() (int, error) {
+	ports, err := f.forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	if len(ports) == 0 {
+		return 0, fmt.Errorf("no port is forwarded")
+	}
+	return int(ports[0].Local), nil
+}
+
 func NewPortForwarder(client rest.Interface, clientConfig *rest.Config, namespace, pod, address, portMap string, writer io.Writer) (forwarder, error) {
 	stopCh := make(chan struct{})
 	readyCh := make(chan struct{})
@@ -0,0 +1,85 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxCookieChunkSize is the size of the value of each cookie holding a chunk of a large value: browsers reject the
+// cookies larger than 4KB, including their name and their attributes.
+const maxCookieChunkSize = 3500
+
+// cookieChunkName returns the name of the cookie holding the chunk of the given index. The first chunk is held by the
+// cookie itself.
+func cookieChunkName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s-%d", name, index)
+}
+
+// SetChunkedCookie sets a cookie whose value can be larger than what the browsers accept in one cookie, i.e. a session
+// holding the credentials of the user for several clusters. The value is split in chunks, held by the cookie and by
+// cookies named after it with the index of the chunk: "kiali-token", "kiali-token-1"... The chunks of a previous
+// larger value, sent in the request, are dropped.
+func SetChunkedCookie(w http.ResponseWriter, r *http.Request, cookie http.Cookie) {
+	value := cookie.Value
+	index := 0
+	for ; index == 0 || value != ""; index++ {
+		size := maxCookieChunkSize
+		if len(value) < size {
+			size = len(value)
+		}
+		chunk := cookie
+		chunk.Name = cookieChunkName(cookie.Name, index)
+		chunk.Value = value[:size]
+		http.SetCookie(w, &chunk)
+		value = value[size:]
+	}
+	dropCookieChunks(w, r, cookie, index)
+}
+
+// GetChunkedCookie returns the value of a cookie set by SetChunkedCookie, joining its chunks. The error is
+// http.ErrNoCookie when the request doesn't hold the cookie.
+func GetChunkedCookie(r *http.Request, name string) (string, error) {
+	first, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	var value strings.Builder
+	value.WriteString(first.Value)
+	for index := 1; ; index++ {
+		chunk, err := r.Cookie(cookieChunkName(name, index))
+		if err != nil {
+			return value.String(), nil
+		}
+		value.WriteString(chunk.Value)
+	}
+}
+
+// DropChunkedCookie expires a cookie set by SetChunkedCookie, and its chunks, when they are sent in the request. The
+// attributes of the given cookie, i.e. its path, must be the ones of the dropped cookie.
+func DropChunkedCookie(w http.ResponseWriter, r *http.Request, cookie http.Cookie) {
+	dropCookieChunks(w, r, cookie, 0)
+}
+
+// dropCookieChunks expires the chunks of a cookie sent in the request, from the given index
+func dropCookieChunks(w http.ResponseWriter, r *http.Request, cookie http.Cookie, from int) {
+	if r == nil {
+		return
+	}
+	for index := from; ; index++ {
+		name := cookieChunkName(cookie.Name, index)
+		if _, err := r.Cookie(name); err != nil {
+			return
+		}
+		expired := cookie
+		expired.Name = name
+		expired.Value = ""
+		expired.Expires = time.Unix(0, 0)
+		expired.MaxAge = -1
+		http.SetCookie(w, &expired)
+	}
+}
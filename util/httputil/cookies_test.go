@@ -0,0 +1,55 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// requestWithCookies returns a request sending back the cookies set in the response
+func requestWithCookies(rr *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest("GET", "/api", nil)
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			r.AddCookie(cookie)
+		}
+	}
+	return r
+}
+
+func TestChunkedCookie(t *testing.T) {
+	assert := assert.New(t)
+
+	large := strings.Repeat("a", maxCookieChunkSize) + strings.Repeat("b", maxCookieChunkSize) + "c"
+	rr := httptest.NewRecorder()
+	SetChunkedCookie(rr, httptest.NewRequest("GET", "/api", nil), http.Cookie{Name: "kiali-token", Value: large, Path: "/kiali"})
+	cookies := rr.Result().Cookies()
+	assert.Len(cookies, 3)
+	assert.Equal("kiali-token-2", cookies[2].Name)
+	assert.Equal("/kiali", cookies[2].Path)
+
+	value, err := GetChunkedCookie(requestWithCookies(rr), "kiali-token")
+	assert.NoError(err)
+	assert.Equal(large, value)
+
+	// The chunks of the previous value are dropped
+	rr2 := httptest.NewRecorder()
+	SetChunkedCookie(rr2, requestWithCookies(rr), http.Cookie{Name: "kiali-token", Value: "small", Path: "/kiali"})
+	cookies = rr2.Result().Cookies()
+	assert.Len(cookies, 3)
+	assert.Equal("small", cookies[0].Value)
+	assert.Equal(-1, cookies[1].MaxAge)
+	assert.Equal(-1, cookies[2].MaxAge)
+	value, err = GetChunkedCookie(requestWithCookies(rr2), "kiali-token")
+	assert.NoError(err)
+	assert.Equal("small", value)
+
+	rr3 := httptest.NewRecorder()
+	DropChunkedCookie(rr3, requestWithCookies(rr), http.Cookie{Name: "kiali-token", Path: "/kiali"})
+	assert.Len(rr3.Result().Cookies(), 3)
+	_, err = GetChunkedCookie(requestWithCookies(rr3), "kiali-token")
+	assert.Equal(http.ErrNoCookie, err)
+}